@@ -0,0 +1,143 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gerunddev/jjazy/jj"
+)
+
+// SuggestDescription runs the configured describe hook for a change. The hook
+// is a shell command that gets the change's patch on stdin (and its ID in
+// JJAZY_CHANGE_ID) and prints a suggested description.
+func SuggestDescription(ctx context.Context, repoPath, hook, changeID string) (string, error) {
+	patch, err := jj.Patch(ctx, repoPath, changeID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get diff: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", hook)
+	cmd.Dir = repoPath
+	cmd.Env = append(os.Environ(), "JJAZY_CHANGE_ID="+changeID)
+	cmd.Stdin = strings.NewReader(patch)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("describe hook failed: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	suggestion := strings.TrimSpace(string(output))
+	if suggestion == "" {
+		return "", fmt.Errorf("describe hook returned nothing")
+	}
+	return suggestion, nil
+}
+
+// DescribeTrailers returns the trailers to add to descriptions: the
+// configured ones, then Signed-off-by from jj's user settings if signOff
+func DescribeTrailers(ctx context.Context, repoPath string, configured []string, signOff bool) ([]string, error) {
+	trailers := append([]string(nil), configured...)
+	if !signOff {
+		return trailers, nil
+	}
+	name, err := jj.ConfigGet(ctx, repoPath, "user.name")
+	if err != nil {
+		return trailers, fmt.Errorf("sign-off needs user.name: %w", err)
+	}
+	email, err := jj.ConfigGet(ctx, repoPath, "user.email")
+	if err != nil {
+		return trailers, fmt.Errorf("sign-off needs user.email: %w", err)
+	}
+	return append(trailers, fmt.Sprintf("Signed-off-by: %s <%s>", name, email)), nil
+}
+
+// AddTrailers appends trailers to a description as its last paragraph,
+// skipping lines it already has. An empty description gets none.
+func AddTrailers(description string, trailers []string) string {
+	description = strings.TrimRight(description, "\n ")
+	if description == "" {
+		return description
+	}
+	existing := make(map[string]bool)
+	for _, line := range strings.Split(description, "\n") {
+		existing[strings.TrimSpace(line)] = true
+	}
+	var missing []string
+	for _, trailer := range trailers {
+		if !existing[trailer] {
+			missing = append(missing, trailer)
+		}
+	}
+	if len(missing) == 0 {
+		return description
+	}
+	return description + "\n\n" + strings.Join(missing, "\n")
+}
+
+// StripTrailers removes the given trailer lines from a description, so
+// editing it shows just the message; AddTrailers puts them back on save
+func StripTrailers(description string, trailers []string) string {
+	strip := make(map[string]bool, len(trailers))
+	for _, trailer := range trailers {
+		strip[trailer] = true
+	}
+	var kept []string
+	for _, line := range strings.Split(description, "\n") {
+		if !strip[strings.TrimSpace(line)] {
+			kept = append(kept, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
+
+// NextTemplate swaps the template prefix of value for the next one in
+// templates. After the last template the prefix is removed again.
+func NextTemplate(value string, templates []string) string {
+	next := 0
+	for i, template := range templates {
+		if template != "" && strings.HasPrefix(value, template) {
+			value = strings.TrimPrefix(value, template)
+			next = i + 1
+			break
+		}
+	}
+	if next >= len(templates) {
+		return value
+	}
+	return templates[next] + value
+}
+
+// DefaultSubjectLimit is the subject length LintDescription warns past
+// when no limit is configured
+const DefaultSubjectLimit = 72
+
+// LintDescription returns a warning about a description, and the offset
+// in it to point at (-1 for none): trailing whitespace on a line, or a
+// subject line longer than limit characters. It returns "" when there is
+// nothing to warn about.
+func LintDescription(description string, limit int) (string, int) {
+	if limit <= 0 {
+		limit = DefaultSubjectLimit
+	}
+	start := 0
+	for i, line := range strings.Split(description, "\n") {
+		if trimmed := strings.TrimRightFunc(line, unicode.IsSpace); trimmed != line {
+			if i == 0 {
+				return "trailing whitespace", len(trimmed)
+			}
+			return fmt.Sprintf("trailing whitespace on line %d", i+1), start + len(trimmed)
+		}
+		start += len(line) + 1
+	}
+	subject, _, _ := strings.Cut(description, "\n")
+	if n := utf8.RuneCountInString(subject); n > limit {
+		return fmt.Sprintf("subject is %d characters, over %d", n, limit), len(string([]rune(subject)[:limit]))
+	}
+	return "", -1
+}
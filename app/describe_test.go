@@ -0,0 +1,83 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestAddTrailers(t *testing.T) {
+	trailers := []string{"Signed-off-by: A <a@example.com>", "Reviewed-by: B"}
+	tests := []struct {
+		name        string
+		description string
+		want        string
+	}{
+		{"appends a paragraph", "fix: typo", "fix: typo\n\nSigned-off-by: A <a@example.com>\nReviewed-by: B"},
+		{"skips present trailers", "fix: typo\n\nReviewed-by: B", "fix: typo\n\nReviewed-by: B\n\nSigned-off-by: A <a@example.com>"},
+		{"leaves empty alone", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AddTrailers(tt.description, trailers); got != tt.want {
+				t.Errorf("AddTrailers() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripTrailers(t *testing.T) {
+	trailers := []string{"Signed-off-by: A <a@example.com>"}
+	got := StripTrailers("fix: typo\n\nSigned-off-by: A <a@example.com>", trailers)
+	if got != "fix: typo" {
+		t.Errorf("StripTrailers() = %q, want %q", got, "fix: typo")
+	}
+	if got := AddTrailers(got, trailers); got != "fix: typo\n\nSigned-off-by: A <a@example.com>" {
+		t.Errorf("round trip = %q", got)
+	}
+}
+
+func TestNextTemplate(t *testing.T) {
+	templates := []string{"feat: ", "fix: "}
+	value := "add thing"
+	for _, want := range []string{"feat: add thing", "fix: add thing", "add thing"} {
+		value = NextTemplate(value, templates)
+		if value != want {
+			t.Errorf("NextTemplate() = %q, want %q", value, want)
+		}
+	}
+}
+
+func TestDescribeTrailersWithoutSignOff(t *testing.T) {
+	trailers, err := DescribeTrailers(context.Background(), "/nonexistent/path", []string{"Reviewed-by: B"}, false)
+	if err != nil || len(trailers) != 1 || trailers[0] != "Reviewed-by: B" {
+		t.Errorf("DescribeTrailers() = %v, %v", trailers, err)
+	}
+	if _, err := DescribeTrailers(context.Background(), "/nonexistent/path", nil, true); err == nil {
+		t.Error("DescribeTrailers() expected an error when user settings can't be read")
+	}
+}
+
+func TestLintDescription(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		limit       int
+		want        string
+		offset      int
+	}{
+		{"clean", "fix: typo", 0, "", -1},
+		{"trailing space", "fix: typo ", 0, "trailing whitespace", 9},
+		{"trailing space on a later line", "fix\n\nbody\t", 0, "trailing whitespace on line 3", 9},
+		{"long subject", "fix: a typo", 5, "subject is 11 characters, over 5", 5},
+		{"default limit", strings.Repeat("x", 73), 0, "subject is 73 characters, over 72", 72},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, offset := LintDescription(tt.description, tt.limit)
+			if got != tt.want || offset != tt.offset {
+				t.Errorf("LintDescription() = %q, %d, want %q, %d", got, offset, tt.want, tt.offset)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"strings"
+
+	"github.com/gerunddev/jjazy/jj"
+)
+
+// BranchURLs are the web pages for a pushed branch on its forge
+type BranchURLs struct {
+	Branch      string // Page showing the branch
+	PullRequest string // Page to open a PR/MR from the branch ("" if unknown forge)
+}
+
+// ForgeURLs derives web URLs for a bookmark from a git remote URL.
+// Handles https, ssh:// and scp-style (git@host:owner/repo) remotes.
+// GitHub and GitLab get a pull request link; other hosts only the branch
+// page, in GitHub's layout which most forges share.
+func ForgeURLs(remoteURL, bookmark string) (BranchURLs, bool) {
+	host, path, ok := parseRemote(remoteURL)
+	if !ok {
+		return BranchURLs{}, false
+	}
+
+	base := "https://" + host + "/" + path
+	branch := url.PathEscape(bookmark)
+
+	switch forgeOf(host) {
+	case forgeGitLab:
+		return BranchURLs{
+			Branch:      base + "/-/tree/" + branch,
+			PullRequest: base + "/-/merge_requests/new?merge_request%5Bsource_branch%5D=" + url.QueryEscape(bookmark),
+		}, true
+	case forgeGitHub:
+		return BranchURLs{
+			Branch:      base + "/tree/" + branch,
+			PullRequest: base + "/compare/" + branch + "?expand=1",
+		}, true
+	default:
+		return BranchURLs{Branch: base + "/tree/" + branch}, true
+	}
+}
+
+// Forges with a pull request page and CLI
+const (
+	forgeGitHub = "github"
+	forgeGitLab = "gitlab"
+)
+
+// forgeOf names the forge hosting host, or "" for one jjazy doesn't know
+func forgeOf(host string) string {
+	switch {
+	case strings.Contains(host, "gitlab"):
+		return forgeGitLab
+	case host == "github.com":
+		return forgeGitHub
+	}
+	return ""
+}
+
+// PullRequest is a pull request opened for a bookmark, or the page to
+// open one at
+type PullRequest struct {
+	Bookmark string
+	URL      string
+	Opened   bool // URL is the pull request itself, opened with gh or glab
+}
+
+// OpenPullRequest opens a pull request from bookmark into base ("" for
+// the default branch), titled and described from the bookmark's change.
+// It uses the gh or glab CLI when installed and returns the new pull
+// request, or one already open for the bookmark. Without the CLI it
+// returns the forge's page for opening one by hand. ok is false for
+// remotes on other forges.
+func OpenPullRequest(ctx context.Context, repoPath, remoteURL, bookmark, base string) (pr PullRequest, ok bool, err error) {
+	host, path, ok := parseRemote(remoteURL)
+	if !ok || forgeOf(host) == "" {
+		return PullRequest{}, false, nil
+	}
+	pr = PullRequest{Bookmark: bookmark}
+
+	name, args := forgeCLI(forgeOf(host), host+"/"+path, bookmark, base)
+	if _, err := exec.LookPath(name); err != nil {
+		urls, _ := ForgeURLs(remoteURL, bookmark)
+		pr.URL = urls.PullRequest
+		return pr, true, nil
+	}
+
+	description, err := jj.GetDescription(ctx, repoPath, jj.RevsetSymbol(bookmark))
+	if err != nil {
+		return pr, true, err
+	}
+	title, body, _ := strings.Cut(description, "\n")
+	if title == "" {
+		title = bookmark
+	}
+	args = append(args, "--title", title, "--"+forgeBodyFlag(forgeOf(host)), strings.TrimSpace(body))
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = repoPath
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		// Asking again for a bookmark with an open pull request points at it
+		if url := lastURL(stderr.String()); url != "" && strings.Contains(stderr.String(), "already exists") {
+			pr.URL, pr.Opened = url, true
+			return pr, true, nil
+		}
+		return pr, true, fmt.Errorf("%s failed: %s", name, strings.TrimSpace(stderr.String()))
+	}
+	pr.URL, pr.Opened = lastURL(string(output)), true
+	return pr, true, nil
+}
+
+// forgeCLI returns the command opening a pull request on forge for repo
+// (host/owner/name), before the title and body
+func forgeCLI(forge, repo, bookmark, base string) (name string, args []string) {
+	if forge == forgeGitLab {
+		args = []string{"mr", "create", "--repo", repo, "--source-branch", bookmark, "--yes"}
+		if base != "" {
+			args = append(args, "--target-branch", base)
+		}
+		return "glab", args
+	}
+	args = []string{"pr", "create", "--repo", repo, "--head", bookmark}
+	if base != "" {
+		args = append(args, "--base", base)
+	}
+	return "gh", args
+}
+
+// forgeBodyFlag names the CLI flag for a pull request's body
+func forgeBodyFlag(forge string) string {
+	if forge == forgeGitLab {
+		return "description"
+	}
+	return "body"
+}
+
+// lastURL returns the last web URL in a CLI's output
+func lastURL(output string) string {
+	fields := strings.Fields(output)
+	for i := len(fields) - 1; i >= 0; i-- {
+		if strings.HasPrefix(fields[i], "https://") {
+			return fields[i]
+		}
+	}
+	return ""
+}
+
+// parseRemote splits a remote URL into host and owner/repo path
+func parseRemote(remoteURL string) (host, path string, ok bool) {
+	remoteURL = strings.TrimSpace(remoteURL)
+
+	if strings.Contains(remoteURL, "://") {
+		u, err := url.Parse(remoteURL)
+		if err != nil || u.Host == "" {
+			return "", "", false
+		}
+		host, path = u.Hostname(), u.Path
+	} else if at := strings.Index(remoteURL, "@"); at >= 0 {
+		// scp-style: git@host:owner/repo.git
+		var found bool
+		host, path, found = strings.Cut(remoteURL[at+1:], ":")
+		if !found {
+			return "", "", false
+		}
+	} else {
+		return "", "", false // Local path
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if host == "" || path == "" {
+		return "", "", false
+	}
+	return host, path, true
+}
@@ -0,0 +1,92 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestForgeURLs(t *testing.T) {
+	tests := []struct {
+		remote     string
+		wantBranch string
+		wantPR     string
+	}{
+		{
+			remote:     "https://github.com/gerunddev/jjazy.git",
+			wantBranch: "https://github.com/gerunddev/jjazy/tree/feature",
+			wantPR:     "https://github.com/gerunddev/jjazy/compare/feature?expand=1",
+		},
+		{
+			remote:     "git@github.com:gerunddev/jjazy.git",
+			wantBranch: "https://github.com/gerunddev/jjazy/tree/feature",
+			wantPR:     "https://github.com/gerunddev/jjazy/compare/feature?expand=1",
+		},
+		{
+			remote:     "ssh://git@gitlab.com/group/sub/proj.git",
+			wantBranch: "https://gitlab.com/group/sub/proj/-/tree/feature",
+			wantPR:     "https://gitlab.com/group/sub/proj/-/merge_requests/new?merge_request%5Bsource_branch%5D=feature",
+		},
+		{
+			remote:     "https://codeberg.org/someone/thing",
+			wantBranch: "https://codeberg.org/someone/thing/tree/feature",
+		},
+	}
+
+	for _, tt := range tests {
+		urls, ok := ForgeURLs(tt.remote, "feature")
+		if !ok {
+			t.Errorf("ForgeURLs(%q) not ok", tt.remote)
+			continue
+		}
+		if urls.Branch != tt.wantBranch {
+			t.Errorf("ForgeURLs(%q).Branch = %q, want %q", tt.remote, urls.Branch, tt.wantBranch)
+		}
+		if urls.PullRequest != tt.wantPR {
+			t.Errorf("ForgeURLs(%q).PullRequest = %q, want %q", tt.remote, urls.PullRequest, tt.wantPR)
+		}
+	}
+}
+
+func TestForgeURLsLocalRemote(t *testing.T) {
+	if _, ok := ForgeURLs("/srv/git/repo.git", "feature"); ok {
+		t.Error("ForgeURLs should not handle a local path remote")
+	}
+}
+
+func TestForgeCLI(t *testing.T) {
+	name, args := forgeCLI(forgeGitHub, "github.com/o/r", "auth-2", "auth-1")
+	if want := "pr create --repo github.com/o/r --head auth-2 --base auth-1"; name != "gh" || strings.Join(args, " ") != want {
+		t.Errorf("forgeCLI(github) = %s %v, want gh %s", name, args, want)
+	}
+	name, args = forgeCLI(forgeGitLab, "gitlab.com/g/p", "auth-1", "")
+	if want := "mr create --repo gitlab.com/g/p --source-branch auth-1 --yes"; name != "glab" || strings.Join(args, " ") != want {
+		t.Errorf("forgeCLI(gitlab) = %s %v, want glab %s", name, args, want)
+	}
+}
+
+func TestLastURL(t *testing.T) {
+	output := "Creating pull request for auth-1 into main\n\nhttps://github.com/o/r/pull/12\n"
+	if got := lastURL(output); got != "https://github.com/o/r/pull/12" {
+		t.Errorf("lastURL() = %q, want the pull request", got)
+	}
+	if got := lastURL("no links here"); got != "" {
+		t.Errorf("lastURL() = %q, want none", got)
+	}
+}
+
+func TestOpenPullRequestWithoutCLI(t *testing.T) {
+	t.Setenv("PATH", "")
+
+	pr, ok, err := OpenPullRequest(context.Background(), "/nonexistent/path", "git@github.com:o/r.git", "feature", "")
+	if err != nil || !ok {
+		t.Fatalf("OpenPullRequest() = %v, %v, want the compare page", ok, err)
+	}
+	if pr.Opened || pr.URL != "https://github.com/o/r/compare/feature?expand=1" {
+		t.Errorf("OpenPullRequest() = %+v, want the compare page unopened", pr)
+	}
+
+	if _, ok, _ := OpenPullRequest(context.Background(), "/nonexistent/path", "https://codeberg.org/o/r", "feature", ""); ok {
+		t.Error("OpenPullRequest() should not handle a forge without pull request support")
+	}
+}
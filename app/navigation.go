@@ -1,6 +1,8 @@
 package app
 
 import (
+	"context"
+
 	"github.com/gerunddev/jjazy/jj"
 )
 
@@ -150,6 +152,6 @@ func (n *Navigation) FindBookmarkEditTarget(bookmarkName string) *jj.Revision {
 }
 
 // EditRevision executes jj edit for a revision.
-func (n *Navigation) EditRevision(changeID string) error {
-	return jj.Edit(n.repoPath, changeID)
+func (n *Navigation) EditRevision(ctx context.Context, changeID string) error {
+	return jj.Edit(ctx, n.repoPath, changeID)
 }
@@ -0,0 +1,35 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gerunddev/jjazy/jj"
+)
+
+// ExportPatches writes one patch file per change into dir and returns the
+// written paths. changeIDs are given in log order (newest first); files are
+// numbered oldest first so they apply in sequence.
+func ExportPatches(ctx context.Context, repoPath string, changeIDs []string, dir string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for i := len(changeIDs) - 1; i >= 0; i-- {
+		patch, err := jj.Patch(ctx, repoPath, changeIDs[i])
+		if err != nil {
+			return paths, fmt.Errorf("export %s: %w", changeIDs[i], err)
+		}
+
+		name := fmt.Sprintf("%04d-%s.patch", len(paths)+1, changeIDs[i])
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(patch), 0644); err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
@@ -0,0 +1,42 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gerunddev/jjazy/jj"
+)
+
+// Target is the revision an action was aimed at, captured when the key was
+// pressed so a refresh in between can't redirect the action.
+type Target struct {
+	ChangeID      string
+	CommitID      string
+	IsWorkingCopy bool
+}
+
+// TargetOf captures the target for a log selection.
+func TargetOf(change jj.ChangeInfo) Target {
+	return Target{
+		ChangeID:      change.ChangeID,
+		CommitID:      change.CommitID,
+		IsWorkingCopy: change.IsWorkingCopy,
+	}
+}
+
+// Validate checks the target still exists and hasn't been rewritten since it
+// was captured. The working copy is only checked for existence, since every
+// snapshot gives it a new commit ID.
+func (t Target) Validate(ctx context.Context, repoPath string) error {
+	commitID, err := jj.CommitIDOf(ctx, repoPath, t.ChangeID)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil {
+		return fmt.Errorf("change %s no longer exists", t.ChangeID)
+	}
+	if !t.IsWorkingCopy && commitID != t.CommitID {
+		return fmt.Errorf("change %s was rewritten since it was selected (now %s)", t.ChangeID, commitID)
+	}
+	return nil
+}
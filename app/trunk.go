@@ -0,0 +1,23 @@
+package app
+
+import (
+	"context"
+
+	"github.com/gerunddev/jjazy/jj"
+)
+
+// DefaultTrunkBookmark is used when no trunk is configured or detected.
+const DefaultTrunkBookmark = "main"
+
+// ResolveTrunkBookmark returns the bookmark name to treat as trunk.
+// A configured name always wins; otherwise the first local bookmark on
+// trunk() is used, falling back to DefaultTrunkBookmark.
+func ResolveTrunkBookmark(ctx context.Context, repoPath, configured string) string {
+	if configured != "" {
+		return configured
+	}
+	if bookmarks, err := jj.TrunkBookmarks(ctx, repoPath); err == nil && len(bookmarks) > 0 {
+		return bookmarks[0]
+	}
+	return DefaultTrunkBookmark
+}
@@ -0,0 +1,117 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/gerunddev/jjazy/jj"
+)
+
+// ErrNoOpener is returned when there is no way to open a workspace: no
+// workspace_opener configured and neither $VISUAL nor $EDITOR set
+var ErrNoOpener = errors.New("set workspace_opener or $EDITOR to open workspaces")
+
+// WorkspaceOpener returns the command that opens a workspace's root.
+//
+// A configured opener is a shell command (e.g. "code ." or a command that
+// opens a terminal tab) run in the root with JJAZY_WORKSPACE_PATH set; it
+// is expected to return quickly, so it runs in the background. Otherwise
+// $VISUAL or $EDITOR is opened on the root in the foreground, taking over
+// the terminal until it exits.
+func WorkspaceOpener(opener, rootPath string) (cmd *exec.Cmd, foreground bool, err error) {
+	if opener == "" {
+		editor := os.Getenv("VISUAL")
+		if editor == "" {
+			editor = os.Getenv("EDITOR")
+		}
+		if editor == "" {
+			return nil, false, ErrNoOpener
+		}
+		opener, foreground = editor+` "$JJAZY_WORKSPACE_PATH"`, true
+	}
+	cmd = exec.Command("sh", "-c", opener)
+	cmd.Dir = rootPath
+	cmd.Env = append(os.Environ(), "JJAZY_WORKSPACE_PATH="+rootPath)
+	return cmd, foreground, nil
+}
+
+// WorkspaceRoot identifies a repository across runs: the current
+// workspace's root, so starting from a subdirectory finds the same one.
+// Without it, repoPath made absolute stands in.
+func WorkspaceRoot(repo *jj.Repo, repoPath string) string {
+	if workspaces, err := repo.Workspaces(); err == nil {
+		for _, ws := range workspaces {
+			if ws.IsCurrent && ws.RootPath != "" {
+				return ws.RootPath
+			}
+		}
+	}
+	if abs, err := filepath.Abs(repoPath); err == nil {
+		return abs
+	}
+	return repoPath
+}
+
+// WorkspaceUsage is how much disk a workspace's files take up
+type WorkspaceUsage struct {
+	Bytes    int64
+	Files    int
+	Modified time.Time // Latest modification time of any file
+}
+
+// MeasureWorkspace adds up the files under a workspace root, leaving out
+// the .jj and .git directories. Unreadable entries are skipped.
+func MeasureWorkspace(ctx context.Context, rootPath string) (*WorkspaceUsage, error) {
+	usage := &WorkspaceUsage{}
+	err := filepath.WalkDir(rootPath, func(path string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			if path == rootPath {
+				return err
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if path != rootPath && (d.Name() == ".jj" || d.Name() == ".git") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		usage.Bytes += info.Size()
+		usage.Files++
+		if info.ModTime().After(usage.Modified) {
+			usage.Modified = info.ModTime()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+// FormatBytes renders a size with a binary unit, e.g. "1.5 MiB"
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
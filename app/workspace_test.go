@@ -0,0 +1,88 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWorkspaceOpener(t *testing.T) {
+	root := t.TempDir()
+
+	cmd, foreground, err := WorkspaceOpener(`printf %s "$JJAZY_WORKSPACE_PATH"`, root)
+	if err != nil {
+		t.Fatalf("WorkspaceOpener() error = %v", err)
+	}
+	if foreground {
+		t.Error("configured opener should run in the background")
+	}
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("opener failed: %v", err)
+	}
+	if string(output) != root {
+		t.Errorf("JJAZY_WORKSPACE_PATH = %q, want %q", output, root)
+	}
+
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "vi")
+	cmd, foreground, err = WorkspaceOpener("", root)
+	if err != nil {
+		t.Fatalf("WorkspaceOpener() error = %v", err)
+	}
+	if !foreground || !strings.HasPrefix(cmd.Args[len(cmd.Args)-1], "vi ") {
+		t.Errorf("expected $EDITOR in the foreground, got %v (foreground %v)", cmd.Args, foreground)
+	}
+
+	t.Setenv("EDITOR", "")
+	if _, _, err := WorkspaceOpener("", root); !errors.Is(err, ErrNoOpener) {
+		t.Errorf("expected ErrNoOpener, got %v", err)
+	}
+}
+
+func TestMeasureWorkspace(t *testing.T) {
+	root := t.TempDir()
+	for path, content := range map[string]string{
+		"a.txt":       "hello",
+		"dir/b.txt":   "world!",
+		".jj/repo/x":  "ignored",
+		".git/HEAD":   "ignored",
+		"dir/.jj/nop": "also ignored",
+	} {
+		full := filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	usage, err := MeasureWorkspace(context.Background(), root)
+	if err != nil {
+		t.Fatalf("MeasureWorkspace() error = %v", err)
+	}
+	if usage.Files != 2 || usage.Bytes != 11 {
+		t.Errorf("got %d files, %d bytes; want 2 files, 11 bytes", usage.Files, usage.Bytes)
+	}
+	if usage.Modified.IsZero() {
+		t.Error("expected a modification time")
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{
+		0:               "0 B",
+		1023:            "1023 B",
+		1536:            "1.5 KiB",
+		5 * 1024 * 1024: "5.0 MiB",
+	}
+	for n, want := range tests {
+		if got := FormatBytes(n); got != want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+// Package config loads user preferences for jjazy.
+// Settings are read from a JSON file; every field is optional and
+// falls back to a sensible default when missing.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+)
+
+// Config holds user-configurable settings.
+type Config struct {
+	// TrunkBookmark is the bookmark treated as trunk for decorations and
+	// trunk-relative actions. When empty it is detected from trunk().
+	TrunkBookmark string `json:"trunk_bookmark"`
+
+	// DescribeHook is a shell command that suggests a description. It gets
+	// the change's diff on stdin and prints the suggestion on stdout.
+	DescribeHook string `json:"describe_hook"`
+
+	// EnterOpensChange swaps Enter and → in the log: Enter opens the
+	// Change view and → runs jj edit.
+	EnterOpensChange bool `json:"enter_opens_change"`
+
+	// CheckUpdates looks for a newer jjazy release on startup and shows
+	// a notice in the status bar. Off by default.
+	CheckUpdates bool `json:"check_updates"`
+
+	// DefaultExperience is the view jjazy opens in: "log", "change" (the
+	// working copy) or "operations". When empty, the last session's view
+	// is restored.
+	DefaultExperience string `json:"default_experience"`
+
+	// HideWorkspacePanel drops the workspace panel from the Log sidebar,
+	// giving bookmarks the room. Useful with a single workspace.
+	HideWorkspacePanel bool `json:"hide_workspace_panel"`
+
+	// LogRevset is the revset the log shows. When empty, jj's default
+	// (revsets.log) is used.
+	LogRevset string `json:"log_revset"`
+
+	// DescribeTemplates are description prefixes (e.g. "feat: ") that
+	// ctrl+t cycles through in the describe overlay.
+	DescribeTemplates []string `json:"describe_templates"`
+
+	// DescribeTrailers are lines (e.g. "Reviewed-by: ...") appended to
+	// every description saved from the describe overlay.
+	DescribeTrailers []string `json:"describe_trailers"`
+
+	// SignOff adds a Signed-off-by trailer built from jj's user.name and
+	// user.email to every description saved from the describe overlay.
+	SignOff bool `json:"sign_off"`
+
+	// SubjectLimit is the description subject length past which the
+	// describe overlay warns. When zero, 72 is used.
+	SubjectLimit int `json:"subject_limit"`
+
+	// ConfirmEmptyDescription asks before saving an empty description
+	// from the describe overlay.
+	ConfirmEmptyDescription bool `json:"confirm_empty_description"`
+
+	// DiffIgnoreWhitespace hides whitespace-only changes in diffs. b
+	// toggles it in the Change view.
+	DiffIgnoreWhitespace bool `json:"diff_ignore_whitespace"`
+
+	// DiffContext is how many lines of context diffs show around each
+	// change. When zero, jj's default (3) is used.
+	DiffContext int `json:"diff_context"`
+
+	// WorkspaceOpener is a shell command that opens a workspace, run in
+	// its root with JJAZY_WORKSPACE_PATH set (e.g. "code ."). When empty,
+	// $VISUAL or $EDITOR is opened on the workspace instead.
+	WorkspaceOpener string `json:"workspace_opener"`
+
+	// SlowCallWarningMs is how long, in milliseconds, a bridge call can
+	// take before the status bar warns about it. When zero, 500 is used;
+	// a negative value turns the warning off.
+	SlowCallWarningMs int `json:"slow_call_warning_ms"`
+
+	// DimBackground fades the screen behind floating windows such as
+	// dialogs and the help screen.
+	DimBackground bool `json:"dim_background"`
+
+	// LogColumns chooses the fields drawn for each change in the native
+	// log, in order, e.g. [{"name": "change_id"}, {"name": "author",
+	// "width": 24}]. A trailing description goes on its own line, as in
+	// jj log. When empty, DefaultLogColumns is used.
+	LogColumns []LogColumn `json:"log_columns"`
+
+	// AdvanceBookmarks moves the bookmarks on @- forward when a new
+	// change or squash moves @ past them, like jj's advance-branches.
+	// M toggles it in the log.
+	AdvanceBookmarks bool `json:"advance_bookmarks"`
+
+	// Confirm sets when destructive actions ask first, by action
+	// ("abandon", "squash"): one of the Confirm* policies. Actions left
+	// out keep their usual behavior.
+	Confirm map[string]string `json:"confirm"`
+}
+
+// Confirmation policies for Config.Confirm
+const (
+	ConfirmAlways        = "always"
+	ConfirmNever         = "never"
+	ConfirmIfDescendants = "only-if-descendants" // Ask when the action rebases descendants
+)
+
+// confirmPolicies lists every valid Config.Confirm value
+var confirmPolicies = []string{ConfirmAlways, ConfirmNever, ConfirmIfDescendants}
+
+// Log column names
+const (
+	ColumnChangeID    = "change_id"
+	ColumnCommitID    = "commit_id"
+	ColumnAuthor      = "author"
+	ColumnTimestamp   = "timestamp"
+	ColumnBookmarks   = "bookmarks"
+	ColumnDescription = "description"
+)
+
+// LogColumn is a field of a change's row in the log. Width pads or
+// truncates it to a fixed number of cells; zero fits the value.
+type LogColumn struct {
+	Name  string `json:"name"`
+	Width int    `json:"width"`
+}
+
+// DefaultLogColumns returns jj log's layout: the IDs, author, time and
+// bookmarks, then the description below
+func DefaultLogColumns() []LogColumn {
+	return []LogColumn{
+		{Name: ColumnChangeID},
+		{Name: ColumnAuthor},
+		{Name: ColumnTimestamp},
+		{Name: ColumnBookmarks},
+		{Name: ColumnCommitID},
+		{Name: ColumnDescription},
+	}
+}
+
+// Default returns a Config with default values.
+func Default() *Config {
+	return &Config{}
+}
+
+// Path returns the location of the config file.
+// JJAZY_CONFIG overrides the default of <user config dir>/jjazy/config.json.
+func Path() string {
+	if p := os.Getenv("JJAZY_CONFIG"); p != "" {
+		return p
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "jjazy", "config.json")
+}
+
+// Load reads the config file. A missing file is not an error and
+// yields the defaults.
+func Load() (*Config, error) {
+	cfg := Default()
+
+	path := Path()
+	if path == "" {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return Default(), err
+	}
+	return cfg, cfg.dropInvalidConfirm()
+}
+
+// dropInvalidConfirm removes confirm policies that aren't one of the
+// Confirm* values, so those actions keep their usual behavior, and
+// reports them
+func (c *Config) dropInvalidConfirm() error {
+	actions := make([]string, 0, len(c.Confirm))
+	for action := range c.Confirm {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+
+	var errs []error
+	for _, action := range actions {
+		if policy := c.Confirm[action]; !slices.Contains(confirmPolicies, policy) {
+			errs = append(errs, fmt.Errorf("confirm.%s: unknown policy %q (want %q, %q or %q)",
+				action, policy, ConfirmAlways, ConfirmNever, ConfirmIfDescendants))
+			delete(c.Confirm, action)
+		}
+	}
+	return errors.Join(errs...)
+}
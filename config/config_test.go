@@ -0,0 +1,147 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLoadMissingFileUsesDefaults(t *testing.T) {
+	t.Setenv("JJAZY_CONFIG", filepath.Join(t.TempDir(), "missing.json"))
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.TrunkBookmark != "" {
+		t.Errorf("TrunkBookmark = %q, want empty", cfg.TrunkBookmark)
+	}
+}
+
+func TestLoadReadsTrunkBookmark(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"trunk_bookmark": "trunk"}`), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	t.Setenv("JJAZY_CONFIG", path)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.TrunkBookmark != "trunk" {
+		t.Errorf("TrunkBookmark = %q, want %q", cfg.TrunkBookmark, "trunk")
+	}
+}
+
+func TestLoadInvalidFileReturnsDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{not json`), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	t.Setenv("JJAZY_CONFIG", path)
+
+	cfg, err := Load()
+	if err == nil {
+		t.Error("Load() expected error for invalid JSON")
+	}
+	if cfg == nil {
+		t.Fatal("Load() should return defaults on error")
+	}
+}
+
+func TestLoadReadsEnterOpensChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"enter_opens_change": true}`), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	t.Setenv("JJAZY_CONFIG", path)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !cfg.EnterOpensChange {
+		t.Error("EnterOpensChange = false, want true")
+	}
+}
+
+func TestLoadReadsStartupOptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	data := `{"default_experience": "operations", "hide_workspace_panel": true, "log_revset": "mine()"}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	t.Setenv("JJAZY_CONFIG", path)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.DefaultExperience != "operations" {
+		t.Errorf("DefaultExperience = %q, want %q", cfg.DefaultExperience, "operations")
+	}
+	if !cfg.HideWorkspacePanel {
+		t.Error("HideWorkspacePanel = false, want true")
+	}
+	if cfg.LogRevset != "mine()" {
+		t.Errorf("LogRevset = %q, want %q", cfg.LogRevset, "mine()")
+	}
+}
+
+func TestLoadReadsLogColumns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	data := `{"log_columns": [{"name": "change_id"}, {"name": "author", "width": 20}]}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	t.Setenv("JJAZY_CONFIG", path)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := []LogColumn{{Name: ColumnChangeID}, {Name: ColumnAuthor, Width: 20}}
+	if !slices.Equal(cfg.LogColumns, want) {
+		t.Errorf("LogColumns = %v, want %v", cfg.LogColumns, want)
+	}
+}
+
+func TestLoadReadsConfirm(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	data := `{"confirm": {"abandon": "never", "squash": "only-if-descendants"}}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	t.Setenv("JJAZY_CONFIG", path)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Confirm["abandon"] != ConfirmNever || cfg.Confirm["squash"] != ConfirmIfDescendants {
+		t.Errorf("Confirm = %v, want abandon never and squash only-if-descendants", cfg.Confirm)
+	}
+}
+
+func TestLoadReportsInvalidConfirm(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	data := `{"confirm": {"abandon": "never", "squash": "sometimes"}, "subject_limit": 50}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	t.Setenv("JJAZY_CONFIG", path)
+
+	cfg, err := Load()
+	if err == nil || !strings.Contains(err.Error(), `confirm.squash: unknown policy "sometimes"`) {
+		t.Errorf("Load() error = %v, want the squash policy reported", err)
+	}
+	if _, ok := cfg.Confirm["squash"]; ok {
+		t.Errorf("Confirm = %v, want the invalid squash policy dropped", cfg.Confirm)
+	}
+	if cfg.Confirm["abandon"] != ConfirmNever || cfg.SubjectLimit != 50 {
+		t.Errorf("Load() = %+v, want the rest of the config kept", cfg)
+	}
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// State holds UI state that jjazy remembers between sessions. Unlike Config
+// it is written by jjazy itself, so it lives in a separate file.
+type State struct {
+	// SidebarRatio is the sidebar's share of the screen width.
+	// Zero means the size-based default.
+	SidebarRatio float64 `json:"sidebar_ratio,omitempty"`
+
+	// WorkspaceRatio is the workspace panel's share of the Log experience
+	// sidebar height. Zero means the default.
+	WorkspaceRatio float64 `json:"workspace_ratio,omitempty"`
+
+	// FileOrder is how the Change experience sorts files: "path",
+	// "status" or "size". Empty means path.
+	FileOrder string `json:"file_order,omitempty"`
+
+	// LogReversed lists the log's oldest changes first.
+	LogReversed bool `json:"log_reversed,omitempty"`
+
+	// Sessions remembers where jjazy was left in each repository, keyed
+	// by workspace root.
+	Sessions map[string]*Session `json:"sessions,omitempty"`
+
+	// PullRequests are the URLs of pull requests opened from jjazy, keyed
+	// by workspace root, then bookmark.
+	PullRequests map[string]map[string]string `json:"pull_requests,omitempty"`
+}
+
+// SetPullRequest records the pull request opened for a bookmark in the
+// repository at root
+func (s *State) SetPullRequest(root, bookmark, url string) {
+	if s.PullRequests == nil {
+		s.PullRequests = make(map[string]map[string]string)
+	}
+	if s.PullRequests[root] == nil {
+		s.PullRequests[root] = make(map[string]string)
+	}
+	s.PullRequests[root][bookmark] = url
+}
+
+// Session is where jjazy was left in one repository, restored on reopening.
+type Session struct {
+	// Experience is "change" or "operations". Empty means the log.
+	Experience string `json:"experience,omitempty"`
+
+	// ChangeID is the change selected in the log, and open in the Change
+	// experience.
+	ChangeID string `json:"change_id,omitempty"`
+
+	// FocusedPanel is the experience-relative panel with focus.
+	FocusedPanel int `json:"focused_panel,omitempty"`
+
+	// LogOffset and DiffOffset are the log and Change diff scroll positions.
+	LogOffset  int `json:"log_offset,omitempty"`
+	DiffOffset int `json:"diff_offset,omitempty"`
+}
+
+// StatePath returns the location of the state file, next to the config file.
+func StatePath() string {
+	path := Path()
+	if path == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(path), "state.json")
+}
+
+// LoadState reads the state file. A missing or unreadable file yields an
+// empty State, as does test mode.
+func LoadState() (*State, error) {
+	state := &State{}
+
+	path := StatePath()
+	if path == "" || TestMode() {
+		return state, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return &State{}, err
+	}
+	return state, nil
+}
+
+// Save writes the state file, creating its directory if needed. In test
+// mode it does nothing.
+func (s *State) Save() error {
+	if TestMode() {
+		return nil
+	}
+	path := StatePath()
+	if path == "" {
+		return errors.New("no config directory")
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
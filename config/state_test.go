@@ -0,0 +1,70 @@
+package config
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestStateRoundTrip(t *testing.T) {
+	t.Setenv("JJAZY_CONFIG", filepath.Join(t.TempDir(), "jjazy", "config.json"))
+
+	state, err := LoadState()
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	if state.SidebarRatio != 0 || state.WorkspaceRatio != 0 {
+		t.Errorf("LoadState() = %+v, want zero state", state)
+	}
+
+	state.SidebarRatio = 0.25
+	state.WorkspaceRatio = 0.4
+	state.FileOrder = "size"
+	state.Sessions = map[string]*Session{
+		"/src/project": {Experience: "change", ChangeID: "abcdefgh", FocusedPanel: 1, LogOffset: 12, DiffOffset: 40},
+	}
+	state.SetPullRequest("/src/project", "auth-1", "https://github.com/o/r/pull/12")
+	if err := state.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := LoadState()
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	if !reflect.DeepEqual(loaded, state) {
+		t.Errorf("LoadState() = %+v, want %+v", loaded, state)
+	}
+}
+
+func TestStateIgnoredInTestMode(t *testing.T) {
+	t.Setenv("JJAZY_CONFIG", filepath.Join(t.TempDir(), "jjazy", "config.json"))
+
+	state := &State{SidebarRatio: 0.25}
+	if err := state.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	t.Setenv(TestModeEnv, "1")
+	if !TestMode() {
+		t.Fatalf("TestMode() = false with %s=1", TestModeEnv)
+	}
+	loaded, err := LoadState()
+	if err != nil || loaded.SidebarRatio != 0 {
+		t.Errorf("LoadState() in test mode = %+v (%v), want zero state", loaded, err)
+	}
+
+	state.SidebarRatio = 0.5
+	if err := state.Save(); err != nil {
+		t.Fatalf("Save() in test mode error = %v", err)
+	}
+
+	t.Setenv(TestModeEnv, "0")
+	if TestMode() {
+		t.Errorf("TestMode() = true with %s=0", TestModeEnv)
+	}
+	loaded, _ = LoadState()
+	if loaded.SidebarRatio != 0.25 {
+		t.Errorf("Expected test mode not to overwrite the state file, got %+v", loaded)
+	}
+}
@@ -0,0 +1,15 @@
+package config
+
+import "os"
+
+// TestModeEnv is the environment variable that turns on test mode.
+const TestModeEnv = "JJAZY_TEST_MODE"
+
+// TestMode reports whether jjazy should render deterministically, for
+// golden-file tests and screenshots: timestamps are frozen to UTC, the
+// spinner doesn't animate, colors use a fixed profile, and the remembered
+// layout is neither read nor written so panel widths are the defaults.
+func TestMode() bool {
+	v := os.Getenv(TestModeEnv)
+	return v != "" && v != "0"
+}
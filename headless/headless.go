@@ -0,0 +1,283 @@
+// Package headless runs jjazy actions from the command line without the TUI,
+// so scripts and CI can reuse the same flows (e.g. bookmark edit targets).
+package headless
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/gerunddev/jjazy/app"
+	"github.com/gerunddev/jjazy/jj"
+)
+
+// Usage describes the headless commands
+const Usage = `Usage: jjazy <command> [args]
+
+Commands:
+  describe <rev> -m <message>    Set the description of a revision
+  new [<rev>]                    Create a new change on top of rev (default @)
+  edit <rev>                     Make rev the working copy
+  abandon <rev>                  Abandon a revision
+  squash <rev>                   Squash a revision into its parent
+  bookmark set <name> <rev>      Point a bookmark at rev
+      [--allow-backwards] [--ignore-immutable]
+  bookmark edit <name>           Edit the tip of a bookmark's branch
+  dump <view> [--json]           Print log, bookmarks, workspaces,
+                                 operations or status
+  changelog                      Print the bundled release notes
+`
+
+// ErrUsage is returned for unknown commands or bad arguments
+var ErrUsage = errors.New("invalid usage")
+
+// Run executes a headless command. Output goes to stdout.
+func Run(ctx context.Context, repo *jj.Repo, repoPath string, args []string) error {
+	return run(ctx, repo, repoPath, args, os.Stdout)
+}
+
+func run(ctx context.Context, repo *jj.Repo, repoPath string, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return usageError("missing command")
+	}
+
+	cmd, rest := args[0], args[1:]
+	switch cmd {
+	case "describe":
+		fs := newFlagSet(cmd)
+		message := fs.String("m", "", "description")
+		pos, err := parseArgs(fs, rest)
+		if err != nil {
+			return err
+		}
+		if len(pos) != 1 {
+			return usageError("describe takes one revision")
+		}
+		if err := repo.Describe(ctx, pos[0], *message); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Described %s\n", pos[0])
+
+	case "new":
+		rev := "@"
+		if len(rest) > 1 {
+			return usageError("new takes at most one revision")
+		} else if len(rest) == 1 {
+			rev = rest[0]
+		}
+		if err := repo.NewChange(ctx, rev); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Created new change on %s\n", rev)
+
+	case "edit", "abandon", "squash":
+		if len(rest) != 1 {
+			return usageError(cmd + " takes one revision")
+		}
+		actions := map[string]func(context.Context, string) error{
+			"edit": func(ctx context.Context, rev string) error {
+				return jj.Edit(ctx, repoPath, rev)
+			},
+			"abandon": func(ctx context.Context, rev string) error {
+				return repo.Abandon(ctx, rev)
+			},
+			"squash": repo.Squash,
+		}
+		if err := actions[cmd](ctx, rest[0]); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "%s %s\n", pastTense[cmd], rest[0])
+
+	case "bookmark":
+		return runBookmark(ctx, repo, repoPath, rest, out)
+
+	case "dump":
+		return runDump(repo, rest, out)
+
+	default:
+		return usageError("unknown command " + cmd)
+	}
+	return nil
+}
+
+var pastTense = map[string]string{
+	"edit":    "Now editing",
+	"abandon": "Abandoned",
+	"squash":  "Squashed",
+}
+
+// runBookmark handles the bookmark subcommands
+func runBookmark(ctx context.Context, repo *jj.Repo, repoPath string, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return usageError("missing bookmark subcommand")
+	}
+
+	sub, rest := args[0], args[1:]
+	switch sub {
+	case "set":
+		fs := newFlagSet("bookmark set")
+		allowBackwards := fs.Bool("allow-backwards", false, "allow moving the bookmark backwards")
+		ignoreImmutable := fs.Bool("ignore-immutable", false, "allow setting on an immutable revision")
+		pos, err := parseArgs(fs, rest)
+		if err != nil {
+			return err
+		}
+		if len(pos) != 2 {
+			return usageError("bookmark set takes a name and a revision")
+		}
+		name, rev := pos[0], pos[1]
+
+		// SetBookmark goes through jj-lib, which wants a commit ID
+		commitID, err := jj.CommitIDOf(ctx, repoPath, rev)
+		if err != nil {
+			return err
+		}
+		if err := repo.SetBookmark(name, commitID, *allowBackwards, *ignoreImmutable); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Set %s to %s\n", name, commitID)
+
+	case "edit":
+		if len(rest) != 1 {
+			return usageError("bookmark edit takes a bookmark name")
+		}
+		revisions, err := repo.Log()
+		if err != nil {
+			return err
+		}
+		nav := app.NewNavigation(repoPath, revisions)
+		target := nav.FindBookmarkEditTarget(rest[0])
+		if target == nil {
+			return fmt.Errorf("bookmark %s not found", rest[0])
+		}
+		if err := nav.EditRevision(ctx, target.ChangeID); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Now editing %s\n", target.ChangeID)
+
+	default:
+		return usageError("unknown bookmark subcommand " + sub)
+	}
+	return nil
+}
+
+// runDump prints one of the repo views read through jj-lib
+func runDump(repo *jj.Repo, args []string, out io.Writer) error {
+	fs := newFlagSet("dump")
+	asJSON := fs.Bool("json", false, "print JSON")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 {
+		return usageError("dump takes one view")
+	}
+
+	var data any
+	var lines []string
+	switch pos[0] {
+	case "log":
+		revisions, err := repo.Log()
+		if err != nil {
+			return err
+		}
+		data = revisions
+		for _, rev := range revisions {
+			line := rev.ChangeID + " " + rev.ID
+			if len(rev.Bookmarks) > 0 {
+				line += " " + strings.Join(rev.Bookmarks, ",")
+			}
+			lines = append(lines, line+" "+firstLine(rev.Description))
+		}
+	case "bookmarks":
+		branches, err := repo.Branches()
+		if err != nil {
+			return err
+		}
+		data = branches
+		for _, b := range branches {
+			lines = append(lines, b.Name)
+		}
+	case "workspaces":
+		workspaces, err := repo.Workspaces()
+		if err != nil {
+			return err
+		}
+		data = workspaces
+		for _, ws := range workspaces {
+			lines = append(lines, ws.Name+" "+ws.CommitID+" "+ws.RootPath)
+		}
+	case "operations":
+		ops, err := repo.Operations()
+		if err != nil {
+			return err
+		}
+		data = ops
+		for _, op := range ops {
+			lines = append(lines, op.ID+" "+op.Timestamp+" "+op.Description)
+		}
+	case "status":
+		changes, err := repo.WorkingCopyChanges()
+		if err != nil {
+			return err
+		}
+		data = changes
+		for _, c := range changes {
+			lines = append(lines, c.Status+" "+c.Path)
+		}
+	default:
+		return usageError("unknown view " + pos[0])
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(data)
+	}
+	for _, line := range lines {
+		fmt.Fprintln(out, line)
+	}
+	return nil
+}
+
+// firstLine returns the first line of a description
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+// newFlagSet creates a flag set that reports errors instead of exiting
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+// parseArgs parses flags mixed with positional arguments, so both
+// "describe -m msg @" and "describe @ -m msg" work
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, usageError(err.Error())
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		if args[0] == "--" {
+			return append(positional, args[1:]...), nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func usageError(msg string) error {
+	return fmt.Errorf("%w: %s\n\n%s", ErrUsage, msg, strings.TrimRight(Usage, "\n"))
+}
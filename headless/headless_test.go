@@ -0,0 +1,47 @@
+package headless
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestParseArgsInterleaved(t *testing.T) {
+	fs := newFlagSet("describe")
+	message := fs.String("m", "", "description")
+
+	pos, err := parseArgs(fs, []string{"abc", "-m", "hello world"})
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	if len(pos) != 1 || pos[0] != "abc" {
+		t.Errorf("positional = %v, want [abc]", pos)
+	}
+	if *message != "hello world" {
+		t.Errorf("message = %q, want %q", *message, "hello world")
+	}
+}
+
+func TestRunUsageErrors(t *testing.T) {
+	tests := [][]string{
+		{},
+		{"frobnicate"},
+		{"describe"},
+		{"describe", "a", "b", "-m", "x"},
+		{"new", "a", "b"},
+		{"edit"},
+		{"bookmark"},
+		{"bookmark", "set", "main"},
+		{"bookmark", "rename", "a", "b"},
+		{"describe", "@", "--bogus"},
+		{"dump"},
+		{"dump", "tags", "--json"},
+	}
+	for _, args := range tests {
+		err := run(context.Background(), nil, "/nonexistent/path", args, io.Discard)
+		if !errors.Is(err, ErrUsage) {
+			t.Errorf("run(context.Background(), %v) error = %v, want ErrUsage", args, err)
+		}
+	}
+}
@@ -1,16 +1,20 @@
 package interactive
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/huh"
+	"github.com/gerunddev/jjazy/app"
+	"github.com/gerunddev/jjazy/config"
 	"github.com/gerunddev/jjazy/jj"
 )
 
-func runEdit(repoPath string) error {
+func runEdit(ctx context.Context, repoPath string) error {
 	// Get log for revision selection
-	log, err := jj.LogCLI(repoPath)
+	log, err := jj.LogCLI(ctx, repoPath)
 	if err != nil {
 		return fmt.Errorf("failed to get log: %w", err)
 	}
@@ -23,9 +27,7 @@ func runEdit(repoPath string) error {
 	}
 
 	var revision string
-	err = huh.NewSelect[string]().
-		Title("Select revision to edit").
-		Options(options...).
+	err = revisionSelect("Select revision to edit", options).
 		Value(&revision).
 		Run()
 
@@ -34,7 +36,7 @@ func runEdit(repoPath string) error {
 	}
 
 	// Execute edit
-	if err := jj.Edit(repoPath, revision); err != nil {
+	if err := jj.Edit(ctx, repoPath, revision); err != nil {
 		return fmt.Errorf("edit failed: %w", err)
 	}
 
@@ -42,8 +44,8 @@ func runEdit(repoPath string) error {
 	return nil
 }
 
-func runRebase(repoPath string) error {
-	log, err := jj.LogCLI(repoPath)
+func runRebase(ctx context.Context, repoPath string) error {
+	log, err := jj.LogCLI(ctx, repoPath)
 	if err != nil {
 		return fmt.Errorf("failed to get log: %w", err)
 	}
@@ -56,9 +58,7 @@ func runRebase(repoPath string) error {
 
 	// Select source revision
 	var source string
-	err = huh.NewSelect[string]().
-		Title("Select revision to rebase (source)").
-		Options(options...).
+	err = revisionSelect("Select revision to rebase (source)", options).
 		Value(&source).
 		Run()
 
@@ -68,10 +68,8 @@ func runRebase(repoPath string) error {
 
 	// Select destination revision
 	var dest string
-	err = huh.NewSelect[string]().
-		Title("Select destination (new parent)").
+	err = revisionSelect("Select destination (new parent)", options).
 		Description(fmt.Sprintf("Rebasing %s onto...", source)).
-		Options(options...).
 		Value(&dest).
 		Run()
 
@@ -85,7 +83,7 @@ func runRebase(repoPath string) error {
 	}
 
 	// Execute rebase
-	if err := jj.Rebase(repoPath, source, dest); err != nil {
+	if err := jj.Rebase(ctx, repoPath, source, dest); err != nil {
 		return fmt.Errorf("rebase failed: %w", err)
 	}
 
@@ -93,6 +91,291 @@ func runRebase(repoPath string) error {
 	return nil
 }
 
+func runNew(ctx context.Context, repo *jj.Repo, repoPath string) error {
+	revision, ok, err := selectRevision(ctx, repoPath, "Select parent for the new change")
+	if err != nil || !ok {
+		return err
+	}
+
+	if err := repo.NewChange(ctx, revision); err != nil {
+		return fmt.Errorf("new failed: %w", err)
+	}
+
+	fmt.Printf("Created new change on %s\n", revision)
+	return nil
+}
+
+func runDescribe(ctx context.Context, repo *jj.Repo, repoPath string) error {
+	revision, ok, err := selectRevision(ctx, repoPath, "Select revision to describe")
+	if err != nil || !ok {
+		return err
+	}
+
+	description, _ := jj.GetDescription(ctx, repoPath, revision)
+	err = huh.NewText().
+		Title(fmt.Sprintf("Description for %s", revision)).
+		Value(&description).
+		Run()
+
+	if err != nil {
+		return nil // Cancelled
+	}
+
+	if err := repo.Describe(ctx, revision, description); err != nil {
+		return fmt.Errorf("describe failed: %w", err)
+	}
+
+	fmt.Printf("Described %s\n", revision)
+	return nil
+}
+
+func runSquash(ctx context.Context, repo *jj.Repo, repoPath string) error {
+	revision, ok, err := selectRevision(ctx, repoPath, "Select revision to squash into its parent")
+	if err != nil || !ok {
+		return err
+	}
+
+	if err := repo.Squash(ctx, revision); err != nil {
+		return fmt.Errorf("squash failed: %w", err)
+	}
+
+	fmt.Printf("Squashed %s\n", revision)
+	return nil
+}
+
+func runAbandon(ctx context.Context, repo *jj.Repo, repoPath string) error {
+	revision, ok, err := selectRevision(ctx, repoPath, "Select revision to abandon")
+	if err != nil || !ok {
+		return err
+	}
+
+	if !confirm(fmt.Sprintf("Abandon %s?", revision)) {
+		return nil
+	}
+
+	if err := repo.Abandon(ctx, revision); err != nil {
+		return fmt.Errorf("abandon failed: %w", err)
+	}
+
+	fmt.Printf("Abandoned %s\n", revision)
+	return nil
+}
+
+func runBookmarkSet(ctx context.Context, repo *jj.Repo, repoPath string) error {
+	log, err := jj.LogCLI(ctx, repoPath)
+	if err != nil {
+		return fmt.Errorf("failed to get log: %w", err)
+	}
+
+	// Offer existing bookmarks as suggestions, but allow new names
+	var existing []string
+	if branches, err := repo.Branches(); err == nil {
+		for _, b := range branches {
+			existing = append(existing, b.Name)
+		}
+	}
+
+	var name string
+	err = huh.NewInput().
+		Title("Bookmark name").
+		Suggestions(existing).
+		Value(&name).
+		Run()
+
+	if err != nil || name == "" {
+		return nil // Cancelled
+	}
+
+	options := buildRevisionOptions(log.Changes)
+	if len(options) == 0 {
+		fmt.Println("No revisions available")
+		return nil
+	}
+
+	var revision string
+	err = revisionSelect(fmt.Sprintf("Select revision for %s", name), options).
+		Value(&revision).
+		Run()
+
+	if err != nil {
+		return nil // Cancelled
+	}
+
+	// jj-lib takes a commit ID
+	var commitID string
+	for _, c := range log.Changes {
+		if c.ChangeID == revision {
+			commitID = c.CommitID
+		}
+	}
+
+	err = repo.SetBookmark(name, commitID, false, false)
+	if err != nil {
+		// Same escalation as the TUI: confirm, then retry with the flag
+		switch {
+		case errors.Is(err, jj.ErrBackwards):
+			if !confirm("Move bookmark backwards in history?") {
+				return nil
+			}
+			err = repo.SetBookmark(name, commitID, true, false)
+		case errors.Is(err, jj.ErrImmutable):
+			if !confirm("Set bookmark on immutable revision?") {
+				return nil
+			}
+			err = repo.SetBookmark(name, commitID, false, true)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("bookmark set failed: %w", err)
+	}
+
+	fmt.Printf("Set %s to %s\n", name, revision)
+	return nil
+}
+
+// newBookmarkOption is the push option for creating a bookmark at @- first
+const newBookmarkOption = "\x00new"
+
+// pushRemote is the remote whose URL is used for forge links
+const pushRemote = "origin"
+
+func runPush(ctx context.Context, repo *jj.Repo, repoPath string) error {
+	branches, err := repo.Branches()
+	if err != nil {
+		return fmt.Errorf("failed to list bookmarks: %w", err)
+	}
+
+	options := []huh.Option[string]{huh.NewOption("+ Create new bookmark at @-", newBookmarkOption)}
+	for _, b := range branches {
+		if b.IsLocal {
+			options = append(options, huh.NewOption(b.Name, b.Name))
+		}
+	}
+
+	var bookmark string
+	err = huh.NewSelect[string]().
+		Title("Select bookmark to push").
+		Options(options...).
+		Filtering(true).
+		Value(&bookmark).
+		Run()
+
+	if err != nil {
+		return nil // Cancelled
+	}
+
+	created := bookmark == newBookmarkOption
+	if created {
+		err = huh.NewInput().
+			Title("New bookmark name").
+			Description("Created at @- (the last finished change)").
+			Value(&bookmark).
+			Run()
+		if err != nil || bookmark == "" {
+			return nil // Cancelled
+		}
+
+		commitID, err := jj.CommitIDOf(ctx, repoPath, "@-")
+		if err != nil {
+			return err
+		}
+		if err := repo.SetBookmark(bookmark, commitID, false, false); err != nil {
+			return fmt.Errorf("bookmark set failed: %w", err)
+		}
+		fmt.Printf("Created %s at %s\n", bookmark, commitID)
+	}
+
+	if err := jj.GitPush(ctx, repoPath, bookmark, created); err != nil {
+		return err
+	}
+	fmt.Printf("Pushed %s\n", bookmark)
+
+	// Forge links are best effort: no origin or an unknown URL just skips them
+	remoteURL, err := jj.GitRemoteURL(ctx, repoPath, pushRemote)
+	if err != nil {
+		return nil
+	}
+	urls, ok := app.ForgeURLs(remoteURL, bookmark)
+	if !ok {
+		return nil
+	}
+	fmt.Println(urls.Branch)
+	if urls.PullRequest == "" || !confirm("Open a pull request?") {
+		return nil
+	}
+	return openPullRequest(ctx, repo, repoPath, remoteURL, bookmark)
+}
+
+// openPullRequest opens a pull request for a pushed bookmark with gh or
+// glab, remembering it for the bookmarks panel, or prints the page to open
+// one at when neither is installed
+func openPullRequest(ctx context.Context, repo *jj.Repo, repoPath, remoteURL, bookmark string) error {
+	pr, _, err := app.OpenPullRequest(ctx, repoPath, remoteURL, bookmark, "")
+	if err != nil {
+		return err
+	}
+	if !pr.Opened {
+		fmt.Printf("Open a pull request: %s\n", pr.URL)
+		return nil
+	}
+	fmt.Printf("Pull request: %s\n", pr.URL)
+
+	state, err := config.LoadState()
+	if err != nil {
+		return nil // Recording it is a nicety; the pull request is open
+	}
+	state.SetPullRequest(app.WorkspaceRoot(repo, repoPath), bookmark, pr.URL)
+	_ = state.Save()
+	return nil
+}
+
+// selectRevision asks for a revision from the log. ok is false when there is
+// nothing to choose or the user cancelled.
+func selectRevision(ctx context.Context, repoPath, title string) (revision string, ok bool, err error) {
+	log, err := jj.LogCLI(ctx, repoPath)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get log: %w", err)
+	}
+
+	options := buildRevisionOptions(log.Changes)
+	if len(options) == 0 {
+		fmt.Println("No revisions available")
+		return "", false, nil
+	}
+
+	err = revisionSelect(title, options).
+		Value(&revision).
+		Run()
+
+	if err != nil {
+		return "", false, nil // Cancelled
+	}
+	return revision, true, nil
+}
+
+// confirm asks a yes/no question, treating cancel as no
+func confirm(question string) bool {
+	var yes bool
+	err := huh.NewConfirm().
+		Title(question).
+		Value(&yes).
+		Run()
+	return err == nil && yes
+}
+
+// revisionSelectHeight keeps long logs scrollable instead of filling the terminal
+const revisionSelectHeight = 15
+
+// revisionSelect builds a revision picker that filters as you type. Labels
+// carry the change ID, bookmarks and description, so any of them matches.
+func revisionSelect(title string, options []huh.Option[string]) *huh.Select[string] {
+	return huh.NewSelect[string]().
+		Title(title).
+		Options(options...).
+		Filtering(true).
+		Height(revisionSelectHeight)
+}
+
 func buildRevisionOptions(changes []jj.ChangeInfo) []huh.Option[string] {
 	var options []huh.Option[string]
 	for _, c := range changes {
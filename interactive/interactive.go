@@ -1,11 +1,15 @@
 package interactive
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/charmbracelet/huh"
+	"github.com/gerunddev/jjazy/jj"
 )
 
 // Run starts the interactive mode
-func Run(repoPath string) error {
+func Run(ctx context.Context, repo *jj.Repo, repoPath string) error {
 	var action string
 
 	err := huh.NewSelect[string]().
@@ -13,6 +17,13 @@ func Run(repoPath string) error {
 		Options(
 			huh.NewOption("Edit - Switch working copy to revision", "edit"),
 			huh.NewOption("Rebase - Move revision to new parent", "rebase"),
+			huh.NewOption("New - Create a change on top of a revision", "new"),
+			huh.NewOption("Describe - Edit a revision's description", "describe"),
+			huh.NewOption("Squash - Fold a revision into its parent", "squash"),
+			huh.NewOption("Abandon - Drop a revision", "abandon"),
+			huh.NewOption("Bookmark - Point a bookmark at a revision", "bookmark"),
+			huh.NewOption("Push - Push a bookmark and open a pull request", "push"),
+			huh.NewOption("Fetch - Fetch from the git remote", "fetch"),
 		).
 		Value(&action).
 		Run()
@@ -23,9 +34,26 @@ func Run(repoPath string) error {
 
 	switch action {
 	case "edit":
-		return runEdit(repoPath)
+		return runEdit(ctx, repoPath)
 	case "rebase":
-		return runRebase(repoPath)
+		return runRebase(ctx, repoPath)
+	case "new":
+		return runNew(ctx, repo, repoPath)
+	case "describe":
+		return runDescribe(ctx, repo, repoPath)
+	case "squash":
+		return runSquash(ctx, repo, repoPath)
+	case "abandon":
+		return runAbandon(ctx, repo, repoPath)
+	case "bookmark":
+		return runBookmarkSet(ctx, repo, repoPath)
+	case "push":
+		return runPush(ctx, repo, repoPath)
+	case "fetch":
+		if err := jj.GitFetch(ctx, repoPath); err != nil {
+			return err
+		}
+		fmt.Println("Fetched")
 	}
 
 	return nil
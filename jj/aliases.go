@@ -0,0 +1,64 @@
+package jj
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Alias is a jj config alias (revset-aliases or aliases)
+type Alias struct {
+	Name       string
+	Definition string // Unquoted string, or the raw TOML value for arrays
+}
+
+// RevsetAliases returns the revset aliases from jj config
+func RevsetAliases(ctx context.Context, repoPath string) ([]Alias, error) {
+	return configAliases(ctx, repoPath, "revset-aliases")
+}
+
+// CommandAliases returns the command aliases from jj config
+func CommandAliases(ctx context.Context, repoPath string) ([]Alias, error) {
+	return configAliases(ctx, repoPath, "aliases")
+}
+
+// configAliases lists a config table of aliases, sorted by name
+func configAliases(ctx context.Context, repoPath, table string) ([]Alias, error) {
+	output, err := runJJ(ctx, repoPath, "config", "list", table)
+	if err != nil {
+		return nil, err
+	}
+	return parseConfigAliases(table, string(output)), nil
+}
+
+// parseConfigAliases parses `jj config list` lines of the form
+// table.name = value, where name may be quoted ('trunk()' or "x")
+func parseConfigAliases(table, output string) []Alias {
+	var aliases []Alias
+	prefix := table + "."
+
+	for _, line := range strings.Split(output, "\n") {
+		key, value, found := strings.Cut(line, " = ")
+		if !found || !strings.HasPrefix(key, prefix) {
+			continue
+		}
+
+		name := strings.TrimPrefix(key, prefix)
+		if len(name) >= 2 && (name[0] == '\'' || name[0] == '"') && name[len(name)-1] == name[0] {
+			name = name[1 : len(name)-1]
+		}
+
+		value = strings.TrimSpace(value)
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		} else if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+			value = value[1 : len(value)-1] // TOML literal string
+		}
+
+		aliases = append(aliases, Alias{Name: name, Definition: value})
+	}
+
+	sort.Slice(aliases, func(i, j int) bool { return aliases[i].Name < aliases[j].Name })
+	return aliases
+}
@@ -0,0 +1,30 @@
+package jj
+
+import "testing"
+
+func TestParseConfigAliases(t *testing.T) {
+	output := `revset-aliases.'trunk()' = "latest(remote_bookmarks(exact:\"main\"))"
+revset-aliases.mine = 'author("me")'
+revset-aliases."wip(x)" = "x & description(glob:\"wip*\")"
+aliases.l = ["log", "-r", "::@"]
+`
+	aliases := parseConfigAliases("revset-aliases", output)
+	want := []Alias{
+		{Name: "mine", Definition: `author("me")`},
+		{Name: "trunk()", Definition: `latest(remote_bookmarks(exact:"main"))`},
+		{Name: "wip(x)", Definition: `x & description(glob:"wip*")`},
+	}
+	if len(aliases) != len(want) {
+		t.Fatalf("got %d aliases, want %d: %+v", len(aliases), len(want), aliases)
+	}
+	for i := range want {
+		if aliases[i] != want[i] {
+			t.Errorf("alias %d = %+v, want %+v", i, aliases[i], want[i])
+		}
+	}
+
+	commands := parseConfigAliases("aliases", output)
+	if len(commands) != 1 || commands[0].Name != "l" || commands[0].Definition != `["log", "-r", "::@"]` {
+		t.Errorf("command aliases = %+v", commands)
+	}
+}
@@ -0,0 +1,33 @@
+package jj
+
+import "context"
+
+// backend is what a Repo runs its reads and rewrites against. Builds with
+// cgo use the jj-lib bridge (backend_bridge.go); builds without cgo, or
+// with -tags nobridge, run the jj binary instead (backend_cli.go), so
+// jjazy can be installed where the Rust library isn't built. Repo holds
+// its lock around every call, so close never overlaps another. The bridge
+// is only built for macOS, Linux and Windows; other platforms always get
+// the CLI backend.
+type backend interface {
+	branches() ([]Branch, error)
+	tags() ([]Tag, error)
+	workspaces() ([]Workspace, error)
+	workingCopyChanges() ([]FileChange, error)
+	operations() ([]Operation, error)
+	log() ([]Revision, error)
+	signatures(commitIDs []string) ([]Signature, error)
+	diff() (string, error)
+	fileDiff(path string) (string, error)
+	fileContents(path string) (FileContents, error)
+	revisionDiff(revisionID string) (string, error)
+	setBookmark(name, revisionID string, allowBackwards, ignoreImmutable bool) error
+	workspaceAdd(destinationPath, workspaceName string, revisionIDs []string) error
+	workspaceForget(workspaceName string) error
+	describe(ctx context.Context, changeID, message string) error
+	newChange(ctx context.Context, changeID string) error
+	abandon(ctx context.Context, changeIDs []string) error
+	squash(ctx context.Context, changeID string) error
+	reload() error
+	close()
+}
@@ -0,0 +1,147 @@
+//go:build cgo && !nobridge && (darwin || linux || windows)
+
+package jj
+
+import (
+	"context"
+
+	"github.com/gerunddev/jjazy/jj/internal/ffi"
+)
+
+// backendGoesStale is set because the bridge keeps the repository loaded:
+// an operation abandoned or collected elsewhere leaves the handle behind
+// until it's reopened
+const backendGoesStale = true
+
+// bridgeBackend calls jj-lib through the Rust bridge
+type bridgeBackend struct {
+	ptr ffi.RepoPtr
+}
+
+func openBackend(path string) (backend, error) {
+	ptr, err := ffi.OpenRepo(path)
+	if err != nil {
+		return nil, err
+	}
+	return &bridgeBackend{ptr: ptr}, nil
+}
+
+// logWarn writes to the bridge's log file, if logging is enabled
+func logWarn(msg string, keyvals ...any) {
+	ffi.LogWarn(msg, keyvals...)
+}
+
+// call runs a bridge operation and decodes its result into a T
+func call[T any](b *bridgeBackend, op string, params map[string]any) (T, error) {
+	var result T
+	err := ffi.Call(b.ptr, op, params, &result)
+	return result, err
+}
+
+// exec runs a bridge operation that has no result
+func (b *bridgeBackend) exec(op string, params map[string]any) error {
+	return ffi.Call(b.ptr, op, params, nil)
+}
+
+func (b *bridgeBackend) branches() ([]Branch, error) {
+	return call[[]Branch](b, "list_branches", nil)
+}
+
+func (b *bridgeBackend) tags() ([]Tag, error) {
+	return call[[]Tag](b, "list_tags", nil)
+}
+
+func (b *bridgeBackend) workspaces() ([]Workspace, error) {
+	return call[[]Workspace](b, "list_workspaces", nil)
+}
+
+func (b *bridgeBackend) workingCopyChanges() ([]FileChange, error) {
+	return call[[]FileChange](b, "get_working_copy_changes", nil)
+}
+
+func (b *bridgeBackend) operations() ([]Operation, error) {
+	return call[[]Operation](b, "list_operations", nil)
+}
+
+func (b *bridgeBackend) log() ([]Revision, error) {
+	return call[[]Revision](b, "get_log", nil)
+}
+
+func (b *bridgeBackend) signatures(commitIDs []string) ([]Signature, error) {
+	return call[[]Signature](b, "get_signatures", map[string]any{"revision_ids": commitIDs})
+}
+
+func (b *bridgeBackend) diff() (string, error) {
+	return call[string](b, "get_diff", nil)
+}
+
+func (b *bridgeBackend) fileDiff(path string) (string, error) {
+	return call[string](b, "get_file_diff", map[string]any{"path": path})
+}
+
+func (b *bridgeBackend) fileContents(path string) (FileContents, error) {
+	return call[FileContents](b, "get_file_contents", map[string]any{"path": path})
+}
+
+func (b *bridgeBackend) revisionDiff(revisionID string) (string, error) {
+	return call[string](b, "get_revision_diff", map[string]any{"revision_id": revisionID})
+}
+
+func (b *bridgeBackend) setBookmark(name, revisionID string, allowBackwards, ignoreImmutable bool) error {
+	return b.exec("set_bookmark", map[string]any{
+		"name":             name,
+		"revision_id":      revisionID,
+		"allow_backwards":  allowBackwards,
+		"ignore_immutable": ignoreImmutable,
+	})
+}
+
+func (b *bridgeBackend) workspaceAdd(destinationPath, workspaceName string, revisionIDs []string) error {
+	return b.exec("workspace_add", map[string]any{
+		"destination_path": destinationPath,
+		"workspace_name":   workspaceName,
+		"revision_ids":     revisionIDs,
+	})
+}
+
+func (b *bridgeBackend) workspaceForget(workspaceName string) error {
+	return b.exec("workspace_forget", map[string]any{"workspace_name": workspaceName})
+}
+
+// A bridge call can't be interrupted once it starts, so the mutations only
+// honour a cancellation that arrives before they do.
+func (b *bridgeBackend) describe(ctx context.Context, changeID, message string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return b.exec("describe", map[string]any{"revision_id": changeID, "message": message})
+}
+
+func (b *bridgeBackend) newChange(ctx context.Context, changeID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return b.exec("new", map[string]any{"revision_id": changeID})
+}
+
+func (b *bridgeBackend) abandon(ctx context.Context, changeIDs []string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return b.exec("abandon", map[string]any{"revision_ids": changeIDs})
+}
+
+func (b *bridgeBackend) squash(ctx context.Context, changeID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return b.exec("squash", map[string]any{"revision_id": changeID})
+}
+
+func (b *bridgeBackend) reload() error {
+	return b.exec("reload", nil)
+}
+
+func (b *bridgeBackend) close() {
+	ffi.CloseRepo(b.ptr)
+}
@@ -0,0 +1,365 @@
+//go:build !cgo || nobridge || !(darwin || linux || windows)
+
+package jj
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// backendGoesStale is unset because every call runs jj afresh
+const backendGoesStale = false
+
+// cliBackend runs the jj binary for everything the bridge would do.
+// Output mirrors the bridge's: 12-character IDs, the log limited to the
+// ancestors of working copies, and the same status words.
+type cliBackend struct {
+	path string
+}
+
+// cliLogLimit matches the bridge's cap on revisions walked for the log
+const cliLogLimit = 100
+
+// cliOpLimit matches the bridge's cap on operations listed
+const cliOpLimit = 50
+
+// cliSep separates template fields; cliEnd ends records that may span lines
+const (
+	cliSep = "<<SEP>>"
+	cliEnd = "<<END>>\n"
+)
+
+func openBackend(path string) (backend, error) {
+	if _, err := runJJ(context.Background(), path, "workspace", "root"); err != nil {
+		return nil, err
+	}
+	return &cliBackend{path: path}, nil
+}
+
+// logWarn has nowhere to go without the bridge's log file
+func logWarn(string, ...any) {}
+
+// run runs jj in the repository and returns its output as a string
+func (b *cliBackend) run(args ...string) (string, error) {
+	output, err := runJJ(context.Background(), b.path, args...)
+	return string(output), err
+}
+
+// records splits template output on sep, dropping empty records
+func records(output, sep string) []string {
+	var result []string
+	for _, record := range strings.Split(output, sep) {
+		if strings.TrimSpace(record) != "" {
+			result = append(result, strings.TrimPrefix(record, "\n"))
+		}
+	}
+	return result
+}
+
+// cliBranchTemplate lists each local bookmark by name, then the remote
+// bookmarks it tracks with how far it is ahead of and behind them
+const cliBranchTemplate = `if(!remote, name ++ "\n", if(tracked && remote != "git", name ++ "` + cliSep + `" ++ remote ++ "` + cliSep +
+	`" ++ tracking_ahead_count().lower() ++ "` + cliSep + `" ++ tracking_behind_count().lower() ++ "\n"))`
+
+func (b *cliBackend) branches() ([]Branch, error) {
+	output, err := b.run("bookmark", "list", "--all-remotes", "-T", cliBranchTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return parseCLIBranches(output), nil
+}
+
+// parseCLIBranches parses cliBranchTemplate output. A bookmark tracking
+// several remotes is compared with the first.
+func parseCLIBranches(output string) []Branch {
+	var branches []Branch
+	index := make(map[string]int)
+	for _, line := range records(output, "\n") {
+		fields := strings.Split(line, cliSep)
+		i, seen := index[fields[0]]
+		switch {
+		case len(fields) == 1 && !seen:
+			index[fields[0]] = len(branches)
+			branches = append(branches, Branch{Name: fields[0], IsLocal: true})
+		case len(fields) == 4 && seen && branches[i].Remote == "":
+			branches[i].Remote = fields[1]
+			branches[i].Ahead, _ = strconv.Atoi(fields[2])
+			branches[i].Behind, _ = strconv.Atoi(fields[3])
+		}
+	}
+	return branches
+}
+
+func (b *cliBackend) tags() ([]Tag, error) {
+	output, err := b.run("tag", "list", "-T",
+		`if(normal_target, name ++ "`+cliSep+`" ++ normal_target.commit_id().short(12) ++ "\n")`)
+	if err != nil {
+		return nil, err
+	}
+	var tags []Tag
+	for _, line := range records(output, "\n") {
+		if name, commitID, ok := strings.Cut(line, cliSep); ok {
+			tags = append(tags, Tag{Name: name, CommitID: commitID})
+		}
+	}
+	return tags, nil
+}
+
+func (b *cliBackend) workspaces() ([]Workspace, error) {
+	output, err := b.run("workspace", "list", "-T", `name ++ "`+cliSep+`" ++ target.commit_id() ++ "\n"`)
+	if err != nil {
+		return nil, err
+	}
+	current, err := b.run("workspace", "root")
+	if err != nil {
+		return nil, err
+	}
+	current = strings.TrimSpace(current)
+
+	var workspaces []Workspace
+	for _, line := range records(output, "\n") {
+		name, commitID, ok := strings.Cut(line, cliSep)
+		if !ok {
+			continue
+		}
+		root, err := b.run("workspace", "root", "--name", name)
+		if err != nil {
+			return nil, err
+		}
+		root = strings.TrimSpace(root)
+		workspaces = append(workspaces, Workspace{
+			Name:      name,
+			IsCurrent: root == current,
+			CommitID:  commitID,
+			RootPath:  root,
+		})
+	}
+	sort.Slice(workspaces, func(i, j int) bool { return workspaces[i].Name < workspaces[j].Name })
+	return workspaces, nil
+}
+
+// cliFileStatuses maps jj diff --summary letters to the bridge's status words
+var cliFileStatuses = map[string]string{"A": "added", "D": "deleted"}
+
+func (b *cliBackend) workingCopyChanges() ([]FileChange, error) {
+	files, err := FilesForChange(context.Background(), b.path, "@")
+	if err != nil {
+		return nil, err
+	}
+	changes := make([]FileChange, len(files))
+	for i, file := range files {
+		status, ok := cliFileStatuses[file.Status]
+		if !ok {
+			status = "modified"
+		}
+		changes[i] = FileChange{Path: file.Path, Status: status}
+	}
+	return changes, nil
+}
+
+// cliOpTemplate prints an operation's ID, whether it's current, its start
+// time in milliseconds since the epoch and its description
+const cliOpTemplate = `id.short(12) ++ "` + cliSep + `" ++ if(current_operation, "current") ++ "` + cliSep +
+	`" ++ time.start().format("%s%3f") ++ "` + cliSep + `" ++ description ++ "` + cliEnd + `"`
+
+func (b *cliBackend) operations() ([]Operation, error) {
+	output, err := b.run("op", "log", "--no-graph", "-n", fmt.Sprint(cliOpLimit), "-T", cliOpTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return parseCLIOperations(output), nil
+}
+
+// parseCLIOperations parses cliOpTemplate output
+func parseCLIOperations(output string) []Operation {
+	var ops []Operation
+	for _, record := range records(output, cliEnd) {
+		fields := strings.SplitN(record, cliSep, 4)
+		if len(fields) < 4 {
+			continue
+		}
+		ops = append(ops, Operation{
+			ID:          fields[0],
+			IsCurrent:   fields[1] == "current",
+			Timestamp:   fields[2],
+			Description: fields[3],
+		})
+	}
+	return ops
+}
+
+// cliLogTemplate prints the fields of a Revision, flags as letters and the
+// full description last since it spans lines
+const cliLogTemplate = `commit_id.short(12) ++ "` + cliSep + `" ++ change_id.short(12) ++ "` + cliSep +
+	`" ++ author.email() ++ "` + cliSep + `" ++ author.timestamp().utc().format("%Y-%m-%d %H:%M") ++ "` + cliSep +
+	`" ++ local_bookmarks.map(|b| b.name()).join(",") ++ "` + cliSep + `" ++ tags.map(|t| t.name()).join(",") ++ "` + cliSep +
+	`" ++ working_copies ++ "` + cliSep + `" ++ parents.map(|c| c.commit_id().short(12)).join(",") ++ "` + cliSep +
+	`" ++ if(git_head, "g") ++ if(root, "r") ++ if(divergent, "d") ++ if(hidden, "h") ++ if(immutable, "i") ++ if(signature, "s") ++ "` + cliSep +
+	`" ++ description ++ "` + cliEnd + `"`
+
+func (b *cliBackend) log() ([]Revision, error) {
+	output, err := b.run("log", "--no-graph", "-r", "::working_copies()", "-n", fmt.Sprint(cliLogLimit), "-T", cliLogTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return parseCLIRevisions(output), nil
+}
+
+// parseCLIRevisions parses cliLogTemplate output
+func parseCLIRevisions(output string) []Revision {
+	split := func(list string) []string {
+		if list == "" {
+			return []string{}
+		}
+		return strings.Split(list, ",")
+	}
+
+	var revisions []Revision
+	for _, record := range records(output, cliEnd) {
+		fields := strings.SplitN(record, cliSep, 10)
+		if len(fields) < 10 {
+			continue
+		}
+		flags := fields[8]
+		rev := Revision{
+			ID:          fields[0],
+			ChangeID:    fields[1],
+			Author:      fields[2],
+			Timestamp:   fields[3],
+			Bookmarks:   split(fields[4]),
+			Tags:        split(fields[5]),
+			Parents:     split(fields[7]),
+			GitHead:     strings.Contains(flags, "g"),
+			IsRoot:      strings.Contains(flags, "r"),
+			Divergent:   strings.Contains(flags, "d"),
+			Hidden:      strings.Contains(flags, "h"),
+			Immutable:   strings.Contains(flags, "i"),
+			Signed:      strings.Contains(flags, "s"),
+			Description: fields[9],
+		}
+		// working_copies lists "name@" for each workspace editing the commit
+		if workspaces := strings.Fields(fields[6]); len(workspaces) > 0 {
+			name := strings.TrimSuffix(workspaces[0], "@")
+			rev.WorkspaceName = &name
+			rev.IsWorkingCopy = true
+		}
+		revisions = append(revisions, rev)
+	}
+	return revisions
+}
+
+func (b *cliBackend) signatures(commitIDs []string) ([]Signature, error) {
+	if len(commitIDs) == 0 {
+		return nil, nil
+	}
+	present := make([]string, len(commitIDs))
+	for i, id := range commitIDs {
+		present[i] = fmt.Sprintf("present(%s)", id)
+	}
+	output, err := b.run("log", "--no-graph", "-r", strings.Join(present, " | "), "-T",
+		`commit_id ++ "`+cliSep+`" ++ if(signature, "s") ++ "\n"`)
+	if err != nil {
+		return nil, err
+	}
+
+	signed := make(map[string]bool)
+	for _, line := range records(output, "\n") {
+		if commitID, flag, ok := strings.Cut(line, cliSep); ok {
+			signed[commitID] = flag == "s"
+		}
+	}
+	var signatures []Signature
+	for _, id := range commitIDs {
+		for commitID, isSigned := range signed {
+			if strings.HasPrefix(commitID, id) {
+				signatures = append(signatures, Signature{ID: id, Signed: isSigned})
+				break
+			}
+		}
+	}
+	return signatures, nil
+}
+
+func (b *cliBackend) diff() (string, error) {
+	return b.run("diff", "--git", "-r", "@")
+}
+
+func (b *cliBackend) fileDiff(path string) (string, error) {
+	return b.run("diff", "--git", "-r", "@", RootFileset(path))
+}
+
+func (b *cliBackend) fileContents(path string) (FileContents, error) {
+	ctx := context.Background()
+	before, err := fileContent(ctx, b.path, "@-", path)
+	if err != nil {
+		return FileContents{}, err
+	}
+	after, err := fileContent(ctx, b.path, "@", path)
+	if err != nil {
+		return FileContents{}, err
+	}
+	return FileContents{Before: string(before), After: string(after), Path: path}, nil
+}
+
+func (b *cliBackend) revisionDiff(revisionID string) (string, error) {
+	return b.run("diff", "--git", "-r", revisionID)
+}
+
+func (b *cliBackend) setBookmark(name, revisionID string, allowBackwards, ignoreImmutable bool) error {
+	args := []string{"bookmark", "set", name, "-r", revisionID}
+	if allowBackwards {
+		args = append(args, "--allow-backwards")
+	}
+	if ignoreImmutable {
+		args = append(args, "--ignore-immutable")
+	}
+	_, err := b.run(args...)
+	return err
+}
+
+func (b *cliBackend) workspaceAdd(destinationPath, workspaceName string, revisionIDs []string) error {
+	args := []string{"workspace", "add", destinationPath}
+	if workspaceName != "" {
+		args = append(args, "--name", workspaceName)
+	}
+	for _, id := range revisionIDs {
+		args = append(args, "-r", id)
+	}
+	_, err := b.run(args...)
+	return err
+}
+
+func (b *cliBackend) workspaceForget(workspaceName string) error {
+	_, err := b.run("workspace", "forget", workspaceName)
+	return err
+}
+
+func (b *cliBackend) describe(ctx context.Context, changeID, message string) error {
+	_, err := runJJ(ctx, b.path, "describe", "-r", changeID, "-m", message)
+	return err
+}
+
+func (b *cliBackend) newChange(ctx context.Context, changeID string) error {
+	_, err := runJJ(ctx, b.path, "new", "--after", changeID)
+	return err
+}
+
+func (b *cliBackend) abandon(ctx context.Context, changeIDs []string) error {
+	_, err := runJJ(ctx, b.path, append([]string{"abandon"}, changeIDs...)...)
+	return err
+}
+
+func (b *cliBackend) squash(ctx context.Context, changeID string) error {
+	_, err := runJJ(ctx, b.path, "squash", "-r", changeID)
+	return err
+}
+
+// reload has nothing to do: every call runs jj afresh
+func (b *cliBackend) reload() error {
+	return nil
+}
+
+func (b *cliBackend) close() {}
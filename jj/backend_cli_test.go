@@ -0,0 +1,112 @@
+//go:build !cgo || nobridge || !(darwin || linux || windows)
+
+package jj
+
+import (
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseCLIRevisions(t *testing.T) {
+	output := "aaaaaaaaaaaa<<SEP>>kkkkkkkkkkkk<<SEP>>a@example.com<<SEP>>2026-01-02 03:04<<SEP>>main,dev<<SEP>>v1<<SEP>>default@ second@<<SEP>>bbbbbbbbbbbb<<SEP>>gis<<SEP>>feat: one\n\nbody\n<<END>>\n" +
+		"zzzzzzzzzzzz<<SEP>>zzzzzzzzzzzz<<SEP>><<SEP>>1970-01-01 00:00<<SEP>><<SEP>><<SEP>><<SEP>><<SEP>>ri<<SEP>><<END>>\n"
+
+	revisions := parseCLIRevisions(output)
+	if len(revisions) != 2 {
+		t.Fatalf("got %d revisions, want 2", len(revisions))
+	}
+
+	first := revisions[0]
+	if first.ID != "aaaaaaaaaaaa" || first.ChangeID != "kkkkkkkkkkkk" || first.Author != "a@example.com" {
+		t.Errorf("IDs and author parsed wrong: %+v", first)
+	}
+	if first.Description != "feat: one\n\nbody\n" {
+		t.Errorf("Description = %q", first.Description)
+	}
+	if !reflect.DeepEqual(first.Bookmarks, []string{"main", "dev"}) || !reflect.DeepEqual(first.Tags, []string{"v1"}) {
+		t.Errorf("Bookmarks = %v, Tags = %v", first.Bookmarks, first.Tags)
+	}
+	if !first.IsWorkingCopy || first.WorkspaceName == nil || *first.WorkspaceName != "default" {
+		t.Errorf("expected the default workspace's working copy, got %+v", first)
+	}
+	if !first.GitHead || !first.Immutable || !first.Signed || first.IsRoot {
+		t.Errorf("flags parsed wrong: %+v", first)
+	}
+
+	root := revisions[1]
+	if !root.IsRoot || root.IsWorkingCopy || len(root.Parents) != 0 {
+		t.Errorf("expected a root revision with no parents, got %+v", root)
+	}
+}
+
+func TestParseCLIOperations(t *testing.T) {
+	output := "111111111111<<SEP>>current<<SEP>>1767225600000<<SEP>>describe commit abc<<END>>\n" +
+		"222222222222<<SEP>><<SEP>>1767225500000<<SEP>>snapshot working copy<<END>>\n"
+
+	want := []Operation{
+		{ID: "111111111111", Description: "describe commit abc", Timestamp: "1767225600000", IsCurrent: true},
+		{ID: "222222222222", Description: "snapshot working copy", Timestamp: "1767225500000"},
+	}
+	if got := parseCLIOperations(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseCLIOperations() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseCLIBranches(t *testing.T) {
+	output := "feature\n" +
+		"feature<<SEP>>origin<<SEP>>2<<SEP>>1\n" +
+		"feature<<SEP>>upstream<<SEP>>5<<SEP>>5\n" +
+		"main\n" +
+		"main<<SEP>>origin<<SEP>>0<<SEP>>0\n" +
+		"local-only\n"
+
+	want := []Branch{
+		{Name: "feature", IsLocal: true, Remote: "origin", Ahead: 2, Behind: 1},
+		{Name: "main", IsLocal: true, Remote: "origin"},
+		{Name: "local-only", IsLocal: true},
+	}
+	if got := parseCLIBranches(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseCLIBranches() = %+v, want %+v", got, want)
+	}
+}
+
+// TestCLISignatures verifies signature status on a real repo: an unsigned
+// commit reports unsigned and, where ssh-keygen can make a key, a commit
+// signed with it reports signed
+func TestCLISignatures(t *testing.T) {
+	tmpDir := initTestRepo(t,
+		[]string{"describe", "-m", "unsigned"},
+		[]string{"new", "-m", "signed"},
+	)
+	unsigned := jjOutput(t, tmpDir, "log", "--no-graph", "-r", "@-", "-T", "commit_id")
+	backend := &cliBackend{path: tmpDir}
+
+	got, err := backend.signatures([]string{unsigned})
+	if err != nil {
+		t.Fatalf("signatures() error = %v", err)
+	}
+	if want := []Signature{{ID: unsigned, Signed: false}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("signatures() = %+v, want %+v", got, want)
+	}
+
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen not available to sign with")
+	}
+	keyPath := filepath.Join(t.TempDir(), "key")
+	if err := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-f", keyPath).Run(); err != nil {
+		t.Skipf("unable to generate a signing key: %v", err)
+	}
+	jjOutput(t, tmpDir, "sign", "-r", "@",
+		"--config", "signing.backend=ssh", "--config", "signing.key="+keyPath)
+	signed := jjOutput(t, tmpDir, "log", "--no-graph", "-r", "@", "-T", "commit_id")
+
+	got, err = backend.signatures([]string{signed})
+	if err != nil {
+		t.Fatalf("signatures() error = %v", err)
+	}
+	if want := []Signature{{ID: signed, Signed: true}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("signatures() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,87 @@
+package jj
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/gif" // Registered for image.DecodeConfig
+	_ "image/jpeg"
+	_ "image/png"
+	"strings"
+)
+
+// isBinaryDiff reports whether jj's diff output for a file says it's
+// binary. jj never prints binary content: its own diff format shows
+// "(binary)" under the file header, and --git shows
+// "Binary files a/… and b/… differ".
+func isBinaryDiff(diff string) bool {
+	for _, line := range strings.Split(diff, "\n") {
+		if strings.TrimSpace(line) == "(binary)" ||
+			strings.HasPrefix(line, "Binary files ") && strings.HasSuffix(line, " differ") {
+			return true
+		}
+	}
+	return false
+}
+
+// BinaryFileDiff is a binary file's size before and after a change
+type BinaryFileDiff struct {
+	Path    string
+	OldSize int    // Zero when the file was added
+	NewSize int    // Zero when the file was deleted
+	Image   string // Format and dimensions of the new side, e.g. "png 64×64"; empty if not an image
+}
+
+func (b BinaryFileDiff) String() string {
+	summary := fmt.Sprintf("binary file changed (%d bytes → %d bytes)", b.OldSize, b.NewSize)
+	if b.Image != "" {
+		summary += "\n" + b.Image + " image"
+	}
+	return summary
+}
+
+// GetBinaryFileDiff compares a binary file's content in a change and its
+// parent. Files missing on one side count as empty. A merge is compared
+// with its first parent.
+func GetBinaryFileDiff(ctx context.Context, repoPath, changeID, filePath string) (*BinaryFileDiff, error) {
+	newContent, err := fileContent(ctx, repoPath, changeID, filePath)
+	if err != nil {
+		return nil, err
+	}
+	parent, err := firstParent(ctx, repoPath, changeID)
+	if err != nil {
+		return nil, err
+	}
+	oldContent, err := fileContent(ctx, repoPath, parent, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &BinaryFileDiff{Path: filePath, OldSize: len(oldContent), NewSize: len(newContent)}
+	if cfg, format, err := image.DecodeConfig(bytes.NewReader(newContent)); err == nil {
+		diff.Image = fmt.Sprintf("%s %d×%d", format, cfg.Width, cfg.Height)
+	}
+	return diff, nil
+}
+
+// firstParent returns the commit ID of a change's first parent. changeID-
+// names every parent of a merge, which jj file show refuses.
+func firstParent(ctx context.Context, repoPath, changeID string) (string, error) {
+	output, err := runJJ(ctx, repoPath, "log", "-r", changeID, "--no-graph",
+		"-T", `parents.map(|c| c.commit_id()).join("\n")`)
+	if err != nil {
+		return "", err
+	}
+	parent, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+	if parent == "" {
+		return "", fmt.Errorf("%s has no parent", changeID)
+	}
+	return parent, nil
+}
+
+// fileContent returns a file's content at revision, empty if it's missing there
+// jj file show -r <revision> root:<path>
+func fileContent(ctx context.Context, repoPath, revision, filePath string) ([]byte, error) {
+	return runJJ(ctx, repoPath, "file", "show", "-r", revision, RootFileset(filePath))
+}
@@ -0,0 +1,62 @@
+package jj
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsBinaryDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		diff string
+		want bool
+	}{
+		{"color-words", "Modified regular file logo.png:\n    (binary)\n", true},
+		{"color-words added", "Added regular file logo.png:\n    (binary)\n", true},
+		{"git", "diff --git a/logo.png b/logo.png\nindex 1b2c3d4..5e6f7a8 100644\nBinary files a/logo.png and b/logo.png differ\n", true},
+		{"text", "Modified regular file main.go:\n   1    1: package main\n", false},
+		{"text mentioning the marker", "Modified regular file notes.txt:\n        1: (binary)\n", false},
+		{"empty", "", false},
+	}
+	for _, tt := range tests {
+		if got := isBinaryDiff(tt.diff); got != tt.want {
+			t.Errorf("%s: isBinaryDiff(%q) = %v, want %v", tt.name, tt.diff, got, tt.want)
+		}
+	}
+}
+
+func TestBinaryFileDiffString(t *testing.T) {
+	diff := BinaryFileDiff{Path: "logo.png", OldSize: 120, NewSize: 3400, Image: "png 64×64"}
+	want := "binary file changed (120 bytes → 3400 bytes)\npng 64×64 image"
+	if got := diff.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestGetBinaryFileDiffErrors(t *testing.T) {
+	if _, err := GetBinaryFileDiff(context.Background(), "/nonexistent/path", "abc", "logo.png"); err == nil {
+		t.Error("GetBinaryFileDiff() expected error for invalid path")
+	}
+}
+
+func TestDiffForChangeFileSummarizesBinary(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := exec.Command("jj", "git", "init", tmpDir).Run(); err != nil {
+		t.Skipf("jj not available or unable to initialize repo: %v", err)
+	}
+	path := filepath.Join(tmpDir, "blob file.bin")
+	if err := os.WriteFile(path, []byte("\x00\x01\x02binary"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := DiffForChangeFile(context.Background(), tmpDir, "@", "blob file.bin", DiffOptions{})
+	if err != nil {
+		t.Fatalf("DiffForChangeFile() error = %v", err)
+	}
+	if want := "binary file changed (0 bytes → 9 bytes)"; diff != want {
+		t.Errorf("DiffForChangeFile() = %q, want %q", diff, want)
+	}
+}
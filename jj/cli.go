@@ -1,9 +1,14 @@
 package jj
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -23,27 +28,54 @@ type ChangeInfo struct {
 	StartLine     int      // First line in RawANSI (0-indexed)
 	EndLine       int      // Last line (exclusive)
 	IsWorkingCopy bool     // True if this is the current working copy (@)
+	Divergent     bool     // True if the change has more than one visible commit
+	Hidden        bool     // True if the commit was rewritten or abandoned
+	Immutable     bool     // True if the commit can't be rewritten
+	GitHead       bool     // True if git's HEAD points at the commit (colocated repos)
+	CoAuthors     []string // Values of the description's Co-authored-by trailers
 }
 
 // LogCLI fetches the log using the jj CLI and returns structured output.
 // This uses a two-pass approach:
 // 1. Get pretty ANSI output for display
 // 2. Get structured data to map lines to changes
-func LogCLI(repoPath string) (*LogOutput, error) {
+func LogCLI(ctx context.Context, repoPath string) (*LogOutput, error) {
+	return logCLI(ctx, repoPath)
+}
+
+// LogRevset fetches the log like LogCLI, limited to revset (jj log -r).
+// An empty revset uses jj's default log revset. reversed lists the oldest
+// changes first (jj log --reversed).
+func LogRevset(ctx context.Context, repoPath, revset string, reversed bool) (*LogOutput, error) {
+	var filter []string
+	if revset != "" {
+		filter = append(filter, "-r", revset)
+	}
+	if reversed {
+		filter = append(filter, "--reversed")
+	}
+	return logCLI(ctx, repoPath, filter...)
+}
+
+// FileHistory fetches the log of changes touching a file (jj log <path>).
+// It covers all ancestors of @, not just the default log revset, since
+// the point is to find where a file's history starts.
+func FileHistory(ctx context.Context, repoPath, path string) (*LogOutput, error) {
+	return logCLI(ctx, repoPath, "-r", "::@", fmt.Sprintf("root-file:%q", path))
+}
+
+// logCLI runs both log passes with the same revision and path filter,
+// and any other log flags
+func logCLI(ctx context.Context, repoPath string, filter ...string) (*LogOutput, error) {
 	// Pass 1: Get pretty output with colors
-	prettyCmd := exec.Command("jj", "log", "--color=always")
-	prettyCmd.Dir = repoPath
-	prettyOutput, err := prettyCmd.Output()
+	prettyOutput, err := runJJ(ctx, repoPath, append([]string{"log", "--color=always"}, filter...)...)
 	if err != nil {
 		return nil, err
 	}
 	rawANSI := string(prettyOutput)
 
 	// Pass 2: Get structured metadata (including working copy detection, description, bookmarks)
-	structuredCmd := exec.Command("jj", "log", "--no-graph", "-T",
-		`change_id.short(8) ++ "<<SEP>>" ++ commit_id.short(8) ++ "<<SEP>>" ++ if(self.current_working_copy(), "wc", "no") ++ "<<SEP>>" ++ if(description, description.first_line(), "") ++ "<<SEP>>" ++ bookmarks.join(",") ++ "\n"`)
-	structuredCmd.Dir = repoPath
-	structuredOutput, err := structuredCmd.Output()
+	structuredOutput, err := runJJ(ctx, repoPath, append([]string{"log", "--no-graph", "-T", structuredLogTemplate}, filter...)...)
 	if err != nil {
 		return nil, err
 	}
@@ -93,8 +125,29 @@ func LogCLI(repoPath string) (*LogOutput, error) {
 	}, nil
 }
 
+// LogCLIAtOp fetches the log as it was at a past operation (jj log --at-op).
+// Nothing is snapshotted or written, so it is safe to call while scrubbing.
+func LogCLIAtOp(ctx context.Context, repoPath, opID string) (*LogOutput, error) {
+	return LogCLI(AtOperation(ctx, opID), repoPath)
+}
+
+// OpDiff returns what an operation changed (jj op diff --op), with colors:
+// the changes, bookmarks and working copies it added, rewrote or removed
+func OpDiff(ctx context.Context, repoPath, opID string) (string, error) {
+	output, err := runJJ(ctx, repoPath, "op", "diff", "--op", opID, "--color=always")
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
+
+// structuredLogTemplate renders one line per change for parseStructuredLog
+const structuredLogTemplate = `change_id.short(8) ++ "<<SEP>>" ++ commit_id.short(8) ++ "<<SEP>>" ++ if(self.current_working_copy(), "wc", "no") ++ "<<SEP>>" ++ if(description, description.first_line(), "") ++ "<<SEP>>" ++ bookmarks.join(",") ++ "<<SEP>>" ++ if(divergent, "d") ++ if(hidden, "h") ++ if(immutable, "i") ++ if(git_head, "g") ++ "<<SEP>>" ++ trailers().map(|t| if(t.key() == "Co-authored-by", t.value() ++ ";")).join("") ++ "\n"`
+
 // parseStructuredLog parses the structured template output into ChangeInfo slices.
-// Format: changeID<<SEP>>commitID<<SEP>>wc/no<<SEP>>description<<SEP>>bookmarks
+// Format: changeID<<SEP>>commitID<<SEP>>wc/no<<SEP>>description<<SEP>>bookmarks<<SEP>>flags<<SEP>>coauthors
+// where flags holds d (divergent), h (hidden), i (immutable) and g (git HEAD),
+// and coauthors the Co-authored-by trailer values, each ending in ";".
 func parseStructuredLog(output string) []ChangeInfo {
 	var changes []ChangeInfo
 	const sep = "<<SEP>>"
@@ -113,12 +166,31 @@ func parseStructuredLog(output string) []ChangeInfo {
 			bookmarks = strings.Split(parts[4], ",")
 		}
 
+		var flags string
+		if len(parts) > 5 {
+			flags = parts[5]
+		}
+
+		var coAuthors []string
+		if len(parts) > 6 {
+			for _, coAuthor := range strings.Split(parts[6], ";") {
+				if coAuthor = strings.TrimSpace(coAuthor); coAuthor != "" {
+					coAuthors = append(coAuthors, coAuthor)
+				}
+			}
+		}
+
 		changes = append(changes, ChangeInfo{
 			ChangeID:      parts[0],
 			CommitID:      parts[1],
 			IsWorkingCopy: parts[2] == "wc",
 			Description:   parts[3],
 			Bookmarks:     bookmarks,
+			Divergent:     strings.Contains(flags, "d"),
+			Hidden:        strings.Contains(flags, "h"),
+			Immutable:     strings.Contains(flags, "i"),
+			GitHead:       strings.Contains(flags, "g"),
+			CoAuthors:     coAuthors,
 		})
 	}
 
@@ -138,11 +210,9 @@ type CLIFileChange struct {
 }
 
 // FilesForChange returns the files changed in a specific change using CLI.
-func FilesForChange(repoPath, changeID string) ([]CLIFileChange, error) {
+func FilesForChange(ctx context.Context, repoPath, changeID string) ([]CLIFileChange, error) {
 	// Use jj diff --summary to get file list
-	cmd := exec.Command("jj", "diff", "-r", changeID, "--summary")
-	cmd.Dir = repoPath
-	output, err := cmd.Output()
+	output, err := runJJ(ctx, repoPath, "diff", "-r", changeID, "--summary")
 	if err != nil {
 		return nil, err
 	}
@@ -167,11 +237,31 @@ func FilesForChange(repoPath, changeID string) ([]CLIFileChange, error) {
 	return files, nil
 }
 
+// DefaultDiffContext is how many lines of context jj shows around changes
+const DefaultDiffContext = 3
+
+// DiffOptions changes how a diff is computed. The zero value is jj's default.
+type DiffOptions struct {
+	IgnoreWhitespace bool // Ignore whitespace when comparing lines (jj diff -w)
+	Context          int  // Lines of context around changes; 0 uses jj's default
+}
+
+// args returns the jj diff flags for the options
+func (o DiffOptions) args() []string {
+	var args []string
+	if o.IgnoreWhitespace {
+		args = append(args, "--ignore-all-space")
+	}
+	if o.Context > 0 {
+		args = append(args, "--context", strconv.Itoa(o.Context))
+	}
+	return args
+}
+
 // DiffForChange returns the diff content for a specific change using CLI.
-func DiffForChange(repoPath, changeID string) (string, error) {
-	cmd := exec.Command("jj", "diff", "-r", changeID, "--color=never")
-	cmd.Dir = repoPath
-	output, err := cmd.Output()
+func DiffForChange(ctx context.Context, repoPath, changeID string, opts DiffOptions) (string, error) {
+	args := append([]string{"diff", "-r", changeID, "--color=never"}, opts.args()...)
+	output, err := runJJ(ctx, repoPath, args...)
 	if err != nil {
 		return "", err
 	}
@@ -179,124 +269,923 @@ func DiffForChange(repoPath, changeID string) (string, error) {
 }
 
 // DiffForChangeFile returns the diff for a specific file within a change.
-func DiffForChangeFile(repoPath, changeID, filePath string) (string, error) {
-	cmd := exec.Command("jj", "diff", "-r", changeID, "--color=never", filePath)
-	cmd.Dir = repoPath
-	output, err := cmd.Output()
+// Binary files are summarized by their sizes instead.
+func DiffForChangeFile(ctx context.Context, repoPath, changeID, filePath string, opts DiffOptions) (string, error) {
+	args := append([]string{"diff", "-r", changeID, "--color=never"}, opts.args()...)
+	output, err := runJJ(ctx, repoPath, append(args, RootFileset(filePath))...)
 	if err != nil {
 		return "", err
 	}
+	if isBinaryDiff(string(output)) {
+		// Summarize binary files instead of printing their bytes
+		binary, err := GetBinaryFileDiff(ctx, repoPath, changeID, filePath)
+		if err != nil {
+			return "", err
+		}
+		return binary.String(), nil
+	}
 	return string(output), nil
 }
 
+// DiffSizes returns how many lines each file in a change adds and removes,
+// keyed by path. Binary files are left out.
+func DiffSizes(ctx context.Context, repoPath, changeID string) (map[string]int, error) {
+	output, err := runJJ(ctx, repoPath, "diff", "-r", changeID, "--git", "--color=never")
+	if err != nil {
+		return nil, err
+	}
+	return parseDiffSizes(string(output)), nil
+}
+
+// parseDiffSizes totals the +/- lines per text file in a git-format diff
+func parseDiffSizes(diff string) map[string]int {
+	sizes := make(map[string]int)
+	for _, stat := range parseDiffStats(diff) {
+		if !stat.Binary {
+			sizes[stat.Path] = stat.Added + stat.Removed
+		}
+	}
+	return sizes
+}
+
+// FileStat is how many lines a change adds to and removes from a file
+type FileStat struct {
+	Path    string
+	Added   int
+	Removed int
+	Binary  bool // Binary files have no line counts
+}
+
+// DiffStats returns the lines added and removed per file in a change, in
+// diff order. A filePath limits it to that file.
+// jj diff -r <changeID> --git [<filePath>]
+func DiffStats(ctx context.Context, repoPath, changeID, filePath string, opts DiffOptions) ([]FileStat, error) {
+	args := append([]string{"diff", "-r", changeID, "--git", "--color=never"}, opts.args()...)
+	if filePath != "" {
+		args = append(args, filePath)
+	}
+	output, err := runJJ(ctx, repoPath, args...)
+	if err != nil {
+		return nil, err
+	}
+	return parseDiffStats(string(output)), nil
+}
+
+// parseDiffStats counts the +/- lines per file in a git-format diff. Lines
+// only count inside hunks, since a removed "-- text" line looks the same
+// as a --- header.
+func parseDiffStats(diff string) []FileStat {
+	var stats []FileStat
+	inHunk := false
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			// "diff --git a/<old> b/<new>"
+			path := line[strings.LastIndex(line, " b/")+len(" b/"):]
+			stats = append(stats, FileStat{Path: path})
+			inHunk = false
+		case len(stats) == 0:
+			continue
+		case !inHunk && strings.HasPrefix(line, "Binary files "):
+			stats[len(stats)-1].Binary = true
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+		case inHunk && strings.HasPrefix(line, "+"):
+			stats[len(stats)-1].Added++
+		case inHunk && strings.HasPrefix(line, "-"):
+			stats[len(stats)-1].Removed++
+		}
+	}
+	return stats
+}
+
 // Edit runs jj edit to edit a specific revision.
-func Edit(repoPath, revisionSpec string) error {
-	cmd := exec.Command("jj", "edit", revisionSpec)
-	cmd.Dir = repoPath
-	output, err := cmd.CombinedOutput()
+func Edit(ctx context.Context, repoPath, revisionSpec string) error {
+	_, err := runJJ(ctx, repoPath, "edit", revisionSpec)
+	return err
+}
+
+// EditIgnoringImmutable runs jj edit on a revision jj considers immutable.
+func EditIgnoringImmutable(ctx context.Context, repoPath, revisionSpec string) error {
+	_, err := runJJ(ctx, repoPath, "edit", "--ignore-immutable", revisionSpec)
+	return err
+}
+
+// EditRisk is why editing a revision could surprise: rewriting it changes
+// history other people may already have
+type EditRisk struct {
+	Immutable       bool     // jj refuses to edit it without --ignore-immutable
+	RemoteBookmarks []string // Remote bookmarks at the revision, as name@remote
+}
+
+// Risky reports whether editing the revision should be confirmed first
+func (r *EditRisk) Risky() bool {
+	return r.Immutable || len(r.RemoteBookmarks) > 0
+}
+
+// CheckEdit looks up whether a revision is immutable or pushed before it
+// is edited
+func CheckEdit(ctx context.Context, repoPath, revisionSpec string) (*EditRisk, error) {
+	output, err := runJJ(ctx, repoPath, "log", "-r", revisionSpec, "--no-graph", "-T",
+		`if(immutable, "immutable") ++ "\n" ++ remote_bookmarks.map(|b| b.name() ++ "@" ++ b.remote() ++ "\n").join("")`)
 	if err != nil {
-		return fmt.Errorf("edit failed: %s", string(output))
+		return nil, err
 	}
-	return nil
+	return parseEditRisk(string(output)), nil
+}
+
+// parseEditRisk parses an "immutable" (or empty) line followed by one
+// name@remote line per remote bookmark. jj's own git tracking refs
+// (@git) are not pushed anywhere, so they are skipped.
+func parseEditRisk(output string) *EditRisk {
+	first, rest, _ := strings.Cut(output, "\n")
+	risk := &EditRisk{Immutable: first == "immutable"}
+	for _, ref := range strings.Fields(rest) {
+		if !strings.HasSuffix(ref, "@git") {
+			risk.RemoteBookmarks = append(risk.RemoteBookmarks, ref)
+		}
+	}
+	return risk
 }
 
 // RestoreFile discards changes to a file in the working copy
-func RestoreFile(repoPath, filePath string) error {
-	cmd := exec.Command("jj", "restore", filePath)
-	cmd.Dir = repoPath
-	output, err := cmd.CombinedOutput()
+func RestoreFile(ctx context.Context, repoPath, filePath string) error {
+	_, err := runJJ(ctx, repoPath, "restore", filePath)
+	return err
+}
+
+// SquashFile moves file changes from working copy to parent
+func SquashFile(ctx context.Context, repoPath, filePath string) error {
+	_, err := runJJ(ctx, repoPath, "squash", "--from", "@", "--into", "@-", filePath)
+	return err
+}
+
+// UntrackedFiles returns the working copy paths jj is not tracking, as
+// listed under "Untracked paths:" by jj status. Ignored files are never
+// listed. New files are only left untracked when snapshot.auto-track
+// excludes them or they exceed snapshot.max-new-file-size.
+func UntrackedFiles(ctx context.Context, repoPath string) ([]string, error) {
+	output, err := runJJ(ctx, repoPath, "status", "--color=never")
 	if err != nil {
-		return fmt.Errorf("restore failed: %s", string(output))
+		return nil, err
 	}
-	return nil
+	return parseUntracked(string(output)), nil
 }
 
-// SquashFile moves file changes from working copy to parent
-func SquashFile(repoPath, filePath string) error {
-	cmd := exec.Command("jj", "squash", "--from", "@", "--into", "@-", filePath)
-	cmd.Dir = repoPath
-	output, err := cmd.CombinedOutput()
+// parseUntracked extracts the "? path" lines following "Untracked paths:"
+func parseUntracked(status string) []string {
+	var paths []string
+	inSection := false
+	for _, line := range strings.Split(status, "\n") {
+		if line == "Untracked paths:" {
+			inSection = true
+			continue
+		}
+		if !inSection {
+			continue
+		}
+		path, ok := strings.CutPrefix(line, "? ")
+		if !ok {
+			break
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// TrackFile starts tracking an untracked file or directory
+// jj file track root:<path>
+func TrackFile(ctx context.Context, repoPath, filePath string) error {
+	_, err := runJJ(ctx, repoPath, "file", "track", RootFileset(filePath))
+	return err
+}
+
+// IgnoreFile appends filePath, anchored to the workspace root, to the
+// top-level .gitignore, creating it if needed. jj picks it up on the next
+// snapshot, so the path stops being reported as untracked.
+func IgnoreFile(repoPath, filePath string) error {
+	gitignore := filepath.Join(repoPath, ".gitignore")
+	existing, err := os.ReadFile(gitignore)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	entry := "/" + filePath + "\n"
+	if len(existing) > 0 && !strings.HasSuffix(string(existing), "\n") {
+		entry = "\n" + entry
+	}
+
+	f, err := os.OpenFile(gitignore, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
 	if err != nil {
-		return fmt.Errorf("squash failed: %s", string(output))
+		return err
 	}
-	return nil
+	if _, err := f.WriteString(entry); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
 }
 
-// NewChange creates a new change after the specified change
-func NewChange(repoPath, changeID string) error {
-	cmd := exec.Command("jj", "new", "--after", changeID)
-	cmd.Dir = repoPath
-	output, err := cmd.CombinedOutput()
+// BookmarkedParent returns the commit ID of @'s only parent when it is
+// mutable and has local bookmarks, the ones AdvanceBookmarks would move,
+// or "" otherwise
+func BookmarkedParent(ctx context.Context, repoPath string) (string, error) {
+	output, err := runJJ(ctx, repoPath, "log", "-r", "@-", "--no-graph", "-T",
+		`if(self.contained_in("mutable() & bookmarks()"), commit_id.short(12)) ++ "\n"`)
 	if err != nil {
-		return fmt.Errorf("new failed: %s", string(output))
+		return "", err
 	}
-	return nil
+	lines := strings.Split(strings.TrimSuffix(string(output), "\n"), "\n")
+	if len(lines) != 1 {
+		return "", nil // Merges have no single bookmark to advance
+	}
+	return lines[0], nil
+}
+
+// AdvanceBookmarks moves the local bookmarks on commitID forward to @-,
+// like jj's advance-branches setting. Nothing moves unless @- descends
+// from commitID.
+// jj bookmark move --from <commitID> --to @-
+func AdvanceBookmarks(ctx context.Context, repoPath, commitID string) error {
+	count, err := CheckRevset(ctx, repoPath, "@- & "+commitID+"+::")
+	if err != nil || count == 0 {
+		return err
+	}
+	_, err = runJJ(ctx, repoPath, "bookmark", "move", "--from", commitID, "--to", "@-")
+	return err
 }
 
 // GetDescription returns the description of a change
-func GetDescription(repoPath, changeID string) (string, error) {
-	cmd := exec.Command("jj", "log", "-r", changeID, "--no-graph", "-T", "if(description, description, \"\")")
-	cmd.Dir = repoPath
-	output, err := cmd.Output()
+func GetDescription(ctx context.Context, repoPath, changeID string) (string, error) {
+	output, err := runJJ(ctx, repoPath, "log", "-r", changeID, "--no-graph", "-T", "if(description, description, \"\")")
 	if err != nil {
-		return "", fmt.Errorf("get description failed: %s", string(output))
+		return "", err
 	}
 	return strings.TrimSpace(string(output)), nil
 }
 
-// Describe sets the description of a change
-func Describe(repoPath, changeID, message string) error {
-	cmd := exec.Command("jj", "describe", "-r", changeID, "-m", message)
-	cmd.Dir = repoPath
-	output, err := cmd.CombinedOutput()
+// Descriptions returns the descriptions of a revset's changes, oldest
+// first, leaving out empty ones
+func Descriptions(ctx context.Context, repoPath, revset string) ([]string, error) {
+	output, err := runJJ(ctx, repoPath, "log", "-r", revset, "--reversed", "--no-graph", "-T", `description ++ "\0"`)
 	if err != nil {
-		return fmt.Errorf("describe failed: %s", string(output))
+		return nil, err
 	}
-	return nil
+	var descriptions []string
+	for _, description := range strings.Split(string(output), "\x00") {
+		if description = strings.TrimSpace(description); description != "" {
+			descriptions = append(descriptions, description)
+		}
+	}
+	return descriptions, nil
+}
+
+// Author is the author of a change
+type Author struct {
+	Name      string
+	Email     string
+	Timestamp string // RFC 3339, as jj metaedit --author-timestamp takes it
+}
+
+// String returns the author as jj metaedit --author takes it
+func (a Author) String() string {
+	return fmt.Sprintf("%s <%s>", a.Name, a.Email)
 }
 
-// Abandon removes a change and rebases its descendants
-func Abandon(repoPath, changeID string) error {
-	cmd := exec.Command("jj", "abandon", changeID)
-	cmd.Dir = repoPath
-	output, err := cmd.CombinedOutput()
+// authorTemplate prints a change's author as name<<SEP>>email<<SEP>>timestamp
+const authorTemplate = `author.name() ++ "<<SEP>>" ++ author.email() ++ "<<SEP>>" ++ author.timestamp().format("%Y-%m-%dT%H:%M:%S%:z")`
+
+// GetAuthor returns the author of a change
+func GetAuthor(ctx context.Context, repoPath, changeID string) (*Author, error) {
+	output, err := runJJ(ctx, repoPath, "log", "-r", changeID, "--no-graph", "-T", authorTemplate)
 	if err != nil {
-		return fmt.Errorf("abandon failed: %s", string(output))
+		return nil, err
 	}
-	return nil
+	return parseAuthor(string(output))
 }
 
-// Squash squashes a change into its parent
-func Squash(repoPath, changeID string) error {
-	cmd := exec.Command("jj", "squash", "-r", changeID)
-	cmd.Dir = repoPath
-	output, err := cmd.CombinedOutput()
+// parseAuthor parses the output of authorTemplate
+func parseAuthor(output string) (*Author, error) {
+	parts := strings.Split(strings.TrimSpace(output), "<<SEP>>")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("unexpected author output: %q", output)
+	}
+	return &Author{Name: parts[0], Email: parts[1], Timestamp: parts[2]}, nil
+}
+
+// SetAuthor rewrites the author of a change (jj metaedit --author). An
+// empty Timestamp keeps the current author timestamp.
+func SetAuthor(ctx context.Context, repoPath, changeID string, author Author) error {
+	args := []string{"metaedit", "--author", author.String()}
+	if author.Timestamp != "" {
+		args = append(args, "--author-timestamp", author.Timestamp)
+	}
+	_, err := runJJ(ctx, repoPath, append(args, changeID)...)
+	return err
+}
+
+// ResetAuthor sets the author of a change to the configured user, with the
+// current time (jj metaedit --update-author --update-author-timestamp)
+func ResetAuthor(ctx context.Context, repoPath, changeID string) error {
+	_, err := runJJ(ctx, repoPath, "metaedit", "--update-author", "--update-author-timestamp", changeID)
+	return err
+}
+
+// ConfigGet returns a jj config value, e.g. user.email
+func ConfigGet(ctx context.Context, repoPath, name string) (string, error) {
+	output, err := runJJ(ctx, repoPath, "config", "get", name)
 	if err != nil {
-		return fmt.Errorf("squash failed: %s", string(output))
+		return "", err
 	}
-	return nil
+	return strings.TrimSpace(string(output)), nil
 }
 
-// Rebase moves a revision to a new parent
-// jj rebase -r <source> -d <destination>
-func Rebase(repoPath, sourceRev, destRev string) error {
-	cmd := exec.Command("jj", "rebase", "-r", sourceRev, "-d", destRev)
-	cmd.Dir = repoPath
-	output, err := cmd.CombinedOutput()
+// LogAuthors returns the distinct authors of the changes in revset (jj's
+// default log revset when empty), most recent first. Only Name and Email
+// are set.
+// jj log --no-graph -r <revset> -T <name and email>
+func LogAuthors(ctx context.Context, repoPath, revset string) ([]Author, error) {
+	args := []string{"log", "--no-graph", "-T", `author.name() ++ "<<SEP>>" ++ author.email() ++ "\n"`}
+	if revset != "" {
+		args = append(args, "-r", revset)
+	}
+	output, err := runJJ(ctx, repoPath, args...)
 	if err != nil {
-		return fmt.Errorf("rebase failed: %s", string(output))
+		return nil, err
 	}
-	return nil
+	return parseLogAuthors(string(output)), nil
+}
+
+// parseLogAuthors parses name<<SEP>>email lines, keeping the first of
+// each email
+func parseLogAuthors(output string) []Author {
+	var authors []Author
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(output, "\n") {
+		name, email, ok := strings.Cut(line, "<<SEP>>")
+		if !ok || email == "" || seen[email] {
+			continue
+		}
+		seen[email] = true
+		authors = append(authors, Author{Name: name, Email: email})
+	}
+	return authors
+}
+
+// AuthorRevset matches the changes email authored or co-authored, going by
+// Co-authored-by trailers that mention it
+func AuthorRevset(email string) string {
+	return fmt.Sprintf(`author_email(exact:%q) | (description(substring:"Co-authored-by:") & description(substring:%q))`, email, "<"+email+">")
+}
+
+// SigningBackend returns the configured signing.backend (e.g. "gpg" or
+// "ssh"), or "" when signing isn't set up
+func SigningBackend(ctx context.Context, repoPath string) string {
+	backend, err := ConfigGet(ctx, repoPath, "signing.backend")
+	if err != nil || backend == "none" {
+		return ""
+	}
+	return backend
+}
+
+// Sign signs revisions with the configured key (jj sign), replacing any
+// existing signature
+func Sign(ctx context.Context, repoPath, revset string) error {
+	_, err := runJJ(ctx, repoPath, "sign", "-r", revset)
+	return err
+}
+
+// CountDescendants returns how many changes descend from revisionSpec,
+// which abandoning it would rebase
+func CountDescendants(ctx context.Context, repoPath, revisionSpec string) (int, error) {
+	output, err := runJJ(ctx, repoPath, "log", "-r", "descendants("+revisionSpec+") ~ "+revisionSpec, "--no-graph", "-T", `"\n"`)
+	if err != nil {
+		return 0, err
+	}
+	return strings.Count(string(output), "\n"), nil
+}
+
+// SquashInto moves all changes from one revision into another
+// jj squash --from <source> --into <destination>
+func SquashInto(ctx context.Context, repoPath, sourceRev, destRev string) error {
+	_, err := runJJ(ctx, repoPath, "squash", "--from", sourceRev, "--into", destRev)
+	return err
+}
+
+// SquashWithMessage squashes like SquashInto but sets the combined
+// description, so jj doesn't open an editor when both sides are described
+// jj squash --from <source> --into <destination> -m <message>
+func SquashWithMessage(ctx context.Context, repoPath, sourceRev, destRev, message string) error {
+	_, err := runJJ(ctx, repoPath, "squash", "--from", sourceRev, "--into", destRev, "-m", message)
+	return err
+}
+
+// CombinedDescription returns the message jj would offer for squashing a
+// change described as source into one described as dest, or "" when at
+// most one of them is described and jj keeps that one without asking
+func CombinedDescription(dest, source string) string {
+	dest, source = strings.TrimSpace(dest), strings.TrimSpace(source)
+	if dest == "" || source == "" {
+		return ""
+	}
+	return dest + "\n\n" + source + "\n"
+}
+
+// Rebase moves a revision to a new parent
+// jj rebase -r <source> -d <destination>
+func Rebase(ctx context.Context, repoPath, sourceRev, destRev string) error {
+	_, err := runJJ(ctx, repoPath, "rebase", "-r", sourceRev, "-d", destRev)
+	return err
 }
 
 // RebaseBranch rebases a revision and its descendants
 // jj rebase -b <branch> -d <destination>
-func RebaseBranch(repoPath, branchRev, destRev string) error {
-	cmd := exec.Command("jj", "rebase", "-b", branchRev, "-d", destRev)
-	cmd.Dir = repoPath
-	output, err := cmd.CombinedOutput()
+func RebaseBranch(ctx context.Context, repoPath, branchRev, destRev string) error {
+	_, err := runJJ(ctx, repoPath, "rebase", "-b", branchRev, "-d", destRev)
+	return err
+}
+
+// TrunkBookmarks returns the local bookmarks pointing at the trunk() revision.
+func TrunkBookmarks(ctx context.Context, repoPath string) ([]string, error) {
+	return bookmarksAt(ctx, repoPath, "trunk()")
+}
+
+// bookmarksAt returns the local bookmarks pointing at the revisions in revset
+func bookmarksAt(ctx context.Context, repoPath, revset string) ([]string, error) {
+	output, err := runJJ(ctx, repoPath, "log", "-r", revset, "--no-graph", "-T",
+		`local_bookmarks.map(|b| b.name() ++ ",").join("")`)
 	if err != nil {
-		return fmt.Errorf("rebase failed: %s", string(output))
+		return nil, err
+	}
+
+	var bookmarks []string
+	for _, name := range strings.Split(strings.TrimSpace(string(output)), ",") {
+		if name != "" {
+			bookmarks = append(bookmarks, name)
+		}
+	}
+	return bookmarks, nil
+}
+
+// RepoSummary is the at-a-glance repo state shown in the status bar
+type RepoSummary struct {
+	Bookmarks []string // Local bookmarks on the closest bookmarked ancestor of @, or @ itself
+	Conflicts int      // Visible changes with unresolved conflicts
+	Divergent []string // Mutable change IDs with more than one visible commit
+	Ahead     int      // Changes in trunk..@, not counting an empty, undescribed @
+	Behind    int      // Changes in @..trunk, which @ isn't based on yet
+}
+
+// Summary collects the current bookmark, conflict count, divergent
+// changes and how far @ has moved away from trunk, a revset such as
+// TrunkRevset returns. Divergence is only checked for mutable changes,
+// since immutable ones can't be resolved from here anyway.
+func Summary(ctx context.Context, repoPath, trunk string) (*RepoSummary, error) {
+	bookmarks, err := runJJ(ctx, repoPath, "log", "-r", "heads(::@ & bookmarks())", "--no-graph", "-T",
+		`local_bookmarks.map(|b| b.name() ++ "\n").join("")`)
+	if err != nil {
+		return nil, err
+	}
+	conflicts, err := runJJ(ctx, repoPath, "log", "-r", "conflicts()", "--no-graph", "-T", `change_id.short(8) ++ "\n"`)
+	if err != nil {
+		return nil, err
+	}
+	divergent, err := runJJ(ctx, repoPath, "log", "-r", "mutable()", "--no-graph", "-T",
+		`if(divergent, change_id.short(8) ++ "\n")`)
+	if err != nil {
+		return nil, err
+	}
+	summary := parseSummary(string(bookmarks), string(conflicts), string(divergent))
+	summary.Ahead, err = CheckRevset(ctx, repoPath, fmt.Sprintf(`(%s)..@ ~ (@ & empty() & description(exact:""))`, trunk))
+	if err != nil {
+		return nil, err
+	}
+	summary.Behind, err = CheckRevset(ctx, repoPath, fmt.Sprintf("@..(%s)", trunk))
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// parseSummary builds a RepoSummary from one-per-line bookmark names,
+// conflicted change IDs and divergent change IDs. Each divergent commit is
+// listed, so change IDs repeat.
+func parseSummary(bookmarks, conflicts, divergent string) *RepoSummary {
+	summary := &RepoSummary{
+		Bookmarks: strings.Fields(bookmarks),
+		Conflicts: len(strings.Fields(conflicts)),
+	}
+	seen := make(map[string]bool)
+	for _, changeID := range strings.Fields(divergent) {
+		if !seen[changeID] {
+			seen[changeID] = true
+			summary.Divergent = append(summary.Divergent, changeID)
+		}
+	}
+	return summary
+}
+
+// DivergentCommit is one of the visible commits of a divergent change
+type DivergentCommit struct {
+	ChangeID    string
+	CommitID    string
+	Description string // First line of description (empty if none)
+	Timestamp   string // Committer timestamp, to tell the copies apart
+}
+
+// DivergentCommits lists the commits of every mutable divergent change,
+// grouped by change in log order
+func DivergentCommits(ctx context.Context, repoPath string) ([]DivergentCommit, error) {
+	output, err := runJJ(ctx, repoPath, "log", "-r", "mutable()", "--no-graph", "-T",
+		`if(divergent, change_id.short(8) ++ "\t" ++ commit_id.short(8) ++ "\t" ++ committer.timestamp().format("%Y-%m-%d %H:%M") ++ "\t" ++ description.first_line() ++ "\n")`)
+	if err != nil {
+		return nil, err
+	}
+	return parseDivergentCommits(string(output)), nil
+}
+
+// parseDivergentCommits parses "changeID<TAB>commitID<TAB>timestamp<TAB>description"
+// lines, moving each commit next to the others of its change
+func parseDivergentCommits(output string) []DivergentCommit {
+	var order []string
+	byChange := make(map[string][]DivergentCommit)
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.SplitN(line, "\t", 4)
+		if len(parts) < 4 {
+			continue
+		}
+		if _, ok := byChange[parts[0]]; !ok {
+			order = append(order, parts[0])
+		}
+		byChange[parts[0]] = append(byChange[parts[0]], DivergentCommit{
+			ChangeID:    parts[0],
+			CommitID:    parts[1],
+			Timestamp:   parts[2],
+			Description: parts[3],
+		})
+	}
+
+	var commits []DivergentCommit
+	for _, changeID := range order {
+		commits = append(commits, byChange[changeID]...)
+	}
+	return commits
+}
+
+// AbandonCommits abandons commits by commit ID. Divergent changes need
+// this, since their change ID matches more than one commit.
+func AbandonCommits(ctx context.Context, repoPath string, commitIDs []string) error {
+	_, err := runJJ(ctx, repoPath, append([]string{"abandon"}, commitIDs...)...)
+	return err
+}
+
+// StaleBookmark is a local bookmark that is probably safe to delete
+type StaleBookmark struct {
+	Name   string
+	Reason string // StaleMerged or StaleRemoteGone
+}
+
+// Reasons a bookmark is reported by StaleBookmarks
+const (
+	StaleMerged     = "merged"
+	StaleRemoteGone = "remote gone"
+)
+
+// bookmarkListTemplate renders one "name<TAB>remote<TAB>tracked<TAB>present" line per ref
+const bookmarkListTemplate = `name ++ "\t" ++ if(remote, remote, "") ++ "\t" ++ if(tracked, "tracked", "") ++ "\t" ++ if(present, "present", "") ++ "\n"`
+
+// StaleBookmarks returns the local bookmarks already merged into trunk, a
+// revset such as TrunkRevset returns, and those whose tracked remote
+// bookmark was deleted. Bookmarks on trunk itself are never reported.
+func StaleBookmarks(ctx context.Context, repoPath, trunk string) ([]StaleBookmark, error) {
+	merged, err := runJJ(ctx, repoPath, "log", "-r", "bookmarks() & ::("+trunk+")", "--no-graph", "-T",
+		`local_bookmarks.map(|b| b.name() ++ "\n").join("")`)
+	if err != nil {
+		return nil, err
+	}
+	listing, err := runJJ(ctx, repoPath, "bookmark", "list", "--all-remotes", "-T", bookmarkListTemplate)
+	if err != nil {
+		return nil, err
+	}
+	onTrunk, err := bookmarksAt(ctx, repoPath, trunk)
+	if err != nil {
+		return nil, err
+	}
+	return parseStaleBookmarks(string(merged), string(listing), onTrunk), nil
+}
+
+// parseStaleBookmarks combines the merged bookmark names and the bookmark
+// listing into the cleanup candidates, in listing order
+func parseStaleBookmarks(merged, listing string, trunk []string) []StaleBookmark {
+	isMerged := make(map[string]bool)
+	for _, name := range strings.Fields(merged) {
+		isMerged[name] = true
+	}
+	for _, name := range trunk {
+		delete(isMerged, name)
+	}
+
+	var locals []string
+	remoteGone := make(map[string]bool)
+	for _, line := range strings.Split(listing, "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 4 {
+			continue
+		}
+		name, remote, tracked, present := fields[0], fields[1], fields[2] != "", fields[3] != ""
+		switch {
+		case remote == "" && present:
+			locals = append(locals, name)
+		case remote != "" && remote != "git" && tracked && !present:
+			remoteGone[name] = true
+		}
+	}
+
+	var stale []StaleBookmark
+	for _, name := range locals {
+		switch {
+		case isMerged[name]:
+			stale = append(stale, StaleBookmark{Name: name, Reason: StaleMerged})
+		case remoteGone[name]:
+			stale = append(stale, StaleBookmark{Name: name, Reason: StaleRemoteGone})
+		}
+	}
+	return stale
+}
+
+// DeleteBookmarks deletes local bookmarks in a single operation
+// jj bookmark delete exact:<name>...
+func DeleteBookmarks(ctx context.Context, repoPath string, names []string) error {
+	args := []string{"bookmark", "delete"}
+	for _, name := range names {
+		args = append(args, "exact:"+name)
+	}
+	_, err := runJJ(ctx, repoPath, args...)
+	return err
+}
+
+// CreateTag points a new tag at a revision (jj tag set). Without
+// --allow-move, jj refuses to move an existing tag of the same name.
+func CreateTag(ctx context.Context, repoPath, name, revisionSpec string) error {
+	_, err := runJJ(ctx, repoPath, "tag", "set", name, "-r", revisionSpec)
+	return err
+}
+
+// RangeRevset builds a revset of the changes from oldest up to newest,
+// both included.
+func RangeRevset(oldest, newest string) string {
+	if oldest == newest {
+		return oldest
+	}
+	return oldest + "::" + newest
+}
+
+// Backout adds a change on top of @ that reverses rev, described as
+// message, or with jj's own description when message is empty.
+// jj revert -r <rev> --onto @ (formerly jj backout)
+func Backout(ctx context.Context, repoPath, rev, message string) error {
+	if _, err := runJJ(ctx, repoPath, "revert", "-r", rev, "--onto", "@"); err != nil {
+		return err
+	}
+	if message == "" {
+		return nil
+	}
+	// The reverting change is the newest child of @
+	_, err := runJJ(ctx, repoPath, "describe", "-r", "latest(@+)", "-m", message)
+	return err
+}
+
+// Parallelize makes the revisions in a revset siblings of each other
+// jj parallelize <revset>
+func Parallelize(ctx context.Context, repoPath, revset string) error {
+	_, err := runJJ(ctx, repoPath, "parallelize", revset)
+	return err
+}
+
+// CheckLinear returns an error unless revset is a linear run of at least
+// two changes, each one's parent the next one down: the shape Parallelize
+// turns into siblings
+func CheckLinear(ctx context.Context, repoPath, revset string) error {
+	output, err := runJJ(ctx, repoPath, "log", "-r", revset, "--no-graph", "-T",
+		`change_id.short(8) ++ "\t" ++ parents.map(|p| p.change_id().short(8)).join(",") ++ "\n"`)
+	if err != nil {
+		return err
+	}
+	return checkLinear(string(output))
+}
+
+// checkLinear checks one "changeID<TAB>parentIDs" line per change, as
+// printed by CheckLinear
+func checkLinear(output string) error {
+	parents := make(map[string][]string)
+	var ids []string
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		id, parentIDs, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		ids = append(ids, id)
+		parents[id] = strings.Split(parentIDs, ",")
+	}
+	if len(ids) < 2 {
+		return fmt.Errorf("select at least two changes to parallelize")
+	}
+
+	roots := 0
+	children := make(map[string]int)
+	for _, id := range ids {
+		inRange := 0
+		for _, parent := range parents[id] {
+			if _, ok := parents[parent]; ok {
+				inRange++
+				children[parent]++
+			}
+		}
+		switch {
+		case inRange == 0:
+			roots++
+		case inRange > 1:
+			return fmt.Errorf("%s merges changes in the range; select a linear run", id)
+		}
+	}
+	if roots > 1 {
+		return fmt.Errorf("the changes aren't connected; select a linear run without gaps")
+	}
+	for _, id := range ids {
+		if children[id] > 1 {
+			return fmt.Errorf("the range branches at %s; select a linear run", id)
+		}
 	}
 	return nil
 }
+
+// Patch returns a change formatted as a git-style patch (header + diff).
+func Patch(ctx context.Context, repoPath, changeID string) (string, error) {
+	output, err := runJJ(ctx, repoPath, "show", "-r", changeID, "--git", "--color=never")
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
+
+// CommitIDOf returns the short commit ID a change currently points at.
+// Fails if the change no longer exists or has become divergent.
+func CommitIDOf(ctx context.Context, repoPath, changeID string) (string, error) {
+	output, err := runJJ(ctx, repoPath, "log", "-r", changeID, "--no-graph", "-T", `commit_id.short(8) ++ "\n"`)
+	if err != nil {
+		return "", fmt.Errorf("change %s not found: %w", changeID, err)
+	}
+
+	ids := strings.Fields(string(output))
+	if len(ids) != 1 {
+		return "", fmt.Errorf("change %s resolves to %d commits", changeID, len(ids))
+	}
+	return ids[0], nil
+}
+
+// ChangeIDOf returns the short change ID a revset resolves to.
+// Fails unless it resolves to exactly one revision.
+func ChangeIDOf(ctx context.Context, repoPath, revset string) (string, error) {
+	output, err := runJJ(ctx, repoPath, "log", "-r", revset, "--no-graph", "-T", `change_id.short(8) ++ "\n"`)
+	if err != nil {
+		return "", err
+	}
+
+	ids := strings.Fields(string(output))
+	if len(ids) != 1 {
+		return "", fmt.Errorf("%s resolves to %d revisions", revset, len(ids))
+	}
+	return ids[0], nil
+}
+
+// ChangeIDs returns the change IDs of the revisions in a revset
+func ChangeIDs(ctx context.Context, repoPath, revset string) ([]string, error) {
+	output, err := runJJ(ctx, repoPath, "log", "-r", revset, "--no-graph", "-T", `change_id ++ "\n"`)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// GitPush pushes a bookmark to its remote
+// jj git push --bookmark <name> [--allow-new]
+func GitPush(ctx context.Context, repoPath, bookmark string, allowNew bool) error {
+	args := []string{"git", "push", "--bookmark", bookmark}
+	if allowNew {
+		args = append(args, "--allow-new")
+	}
+	_, err := runJJ(ctx, repoPath, args...)
+	return err
+}
+
+// GitRemoteURL returns the URL of a git remote
+func GitRemoteURL(ctx context.Context, repoPath, remote string) (string, error) {
+	output, err := runJJ(ctx, repoPath, "git", "remote", "list")
+	if err != nil {
+		return "", err
+	}
+
+	// Each line is "<name> <url>"
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == remote {
+			return fields[1], nil
+		}
+	}
+	return "", fmt.Errorf("remote %s not found", remote)
+}
+
+// GitFetch fetches from the default remote(s)
+// jj git fetch
+func GitFetch(ctx context.Context, repoPath string) error {
+	_, err := runJJ(ctx, repoPath, "git", "fetch")
+	return err
+}
+
+// GitColocation describes the git repo sharing the workspace directory
+type GitColocation struct {
+	Colocated bool   // The workspace root holds a .git directory used by jj
+	Head      string // Commit git's HEAD points at; empty when unborn
+	Parent    string // First parent of @, which jj exports as git's HEAD
+	Staged    bool   // git's index has changes staged against HEAD
+}
+
+// HeadMoved reports whether git's HEAD is not the working copy's parent,
+// e.g. after git commands jj hasn't imported yet
+func (c *GitColocation) HeadMoved() bool {
+	return c.Colocated && c.Head != c.Parent
+}
+
+// Diverged reports whether git and the jj working copy disagree
+func (c *GitColocation) Diverged() bool {
+	return c.HeadMoved() || (c.Colocated && c.Staged)
+}
+
+// Colocation detects a colocated git repo and compares git's HEAD and
+// index with the working copy. Non-colocated repos return Colocated false.
+func Colocation(ctx context.Context, repoPath string) (*GitColocation, error) {
+	rootOutput, err := runJJ(ctx, repoPath, "root")
+	if err != nil {
+		return nil, err
+	}
+	root := strings.TrimSpace(string(rootOutput))
+	if info, err := os.Stat(filepath.Join(root, ".git")); err != nil || !info.IsDir() {
+		return &GitColocation{}, nil
+	}
+
+	head, err := runJJ(ctx, repoPath, "log", "-r", "git_head()", "--no-graph", "-T", `commit_id.short(8) ++ "\n"`)
+	if err != nil {
+		return nil, err
+	}
+	parents, err := runJJ(ctx, repoPath, "log", "-r", "@", "--no-graph", "-T",
+		`parents.filter(|c| !c.root()).map(|c| c.commit_id().short(8)).join(" ")`)
+	if err != nil {
+		return nil, err
+	}
+	return &GitColocation{
+		Colocated: true,
+		Head:      firstField(string(head)),
+		Parent:    firstField(string(parents)),
+		Staged:    gitIndexStaged(ctx, root),
+	}, nil
+}
+
+// firstField returns the first whitespace-separated field, or ""
+func firstField(s string) string {
+	if fields := strings.Fields(s); len(fields) > 0 {
+		return fields[0]
+	}
+	return ""
+}
+
+// gitIndexStaged reports whether git's index differs from HEAD. jj never
+// reads the index, so anything staged there is invisible to it. A missing
+// git binary counts as nothing staged.
+func gitIndexStaged(ctx context.Context, root string) bool {
+	cmd := exec.CommandContext(ctx, "git", "diff", "--cached", "--quiet")
+	cmd.Dir = root
+	var exitErr *exec.ExitError
+	return errors.As(cmd.Run(), &exitErr) && exitErr.ExitCode() == 1
+}
+
+// Snapshot records edits made outside jj into the working-copy commit.
+// Every jj command snapshots first; this runs one that does nothing else.
+// jj log -r @ --no-graph -T ""
+func Snapshot(ctx context.Context, repoPath string) error {
+	_, err := runJJ(ctx, repoPath, "log", "-r", "@", "--no-graph", "-T", `""`)
+	return err
+}
+
+// GitImport updates jj from git's refs and HEAD
+// jj git import
+func GitImport(ctx context.Context, repoPath string) error {
+	_, err := runJJ(ctx, repoPath, "git", "import")
+	return err
+}
+
+// GitExport updates git's refs and HEAD from jj
+// jj git export
+func GitExport(ctx context.Context, repoPath string) error {
+	_, err := runJJ(ctx, repoPath, "git", "export")
+	return err
+}
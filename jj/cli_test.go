@@ -1,9 +1,12 @@
 package jj
 
 import (
+	"context"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -44,7 +47,7 @@ func TestRestoreFile(t *testing.T) {
 	}
 
 	// Test RestoreFile
-	err := RestoreFile(tmpDir, "test.txt")
+	err := RestoreFile(context.Background(), tmpDir, "test.txt")
 	if err != nil {
 		t.Errorf("RestoreFile failed: %v", err)
 	}
@@ -100,7 +103,7 @@ func TestSquashFile(t *testing.T) {
 
 	// Test SquashFile - this will fail if @ and @- don't have the right relationship
 	// But we just want to ensure the command is called correctly
-	err := SquashFile(tmpDir, "test.txt")
+	err := SquashFile(context.Background(), tmpDir, "test.txt")
 	if err != nil {
 		// SquashFile may fail due to jj state, but we should have called the command
 		t.Logf("SquashFile returned error (may be expected): %v", err)
@@ -110,7 +113,7 @@ func TestSquashFile(t *testing.T) {
 // TestRestoreFileErrors tests error handling in RestoreFile
 func TestRestoreFileErrors(t *testing.T) {
 	// Test with non-existent repo
-	err := RestoreFile("/nonexistent/path", "test.txt")
+	err := RestoreFile(context.Background(), "/nonexistent/path", "test.txt")
 	if err == nil {
 		t.Errorf("RestoreFile should fail with non-existent repo path")
 	}
@@ -119,7 +122,7 @@ func TestRestoreFileErrors(t *testing.T) {
 // TestSquashFileErrors tests error handling in SquashFile
 func TestSquashFileErrors(t *testing.T) {
 	// Test with non-existent repo
-	err := SquashFile("/nonexistent/path", "test.txt")
+	err := SquashFile(context.Background(), "/nonexistent/path", "test.txt")
 	if err == nil {
 		t.Errorf("SquashFile should fail with non-existent repo path")
 	}
@@ -162,7 +165,7 @@ func TestRebase(t *testing.T) {
 
 	// Test Rebase with valid revisions (@ onto root())
 	// This may fail due to repo state, but we verify the command executes
-	err := Rebase(tmpDir, "@-", "root()")
+	err := Rebase(context.Background(), tmpDir, "@-", "root()")
 	if err != nil {
 		t.Logf("Rebase returned error (may be expected depending on repo state): %v", err)
 	}
@@ -171,7 +174,7 @@ func TestRebase(t *testing.T) {
 // TestRebaseErrors tests error handling in Rebase
 func TestRebaseErrors(t *testing.T) {
 	// Test with non-existent repo
-	err := Rebase("/nonexistent/path", "@", "@-")
+	err := Rebase(context.Background(), "/nonexistent/path", "@", "@-")
 	if err == nil {
 		t.Errorf("Rebase should fail with non-existent repo path")
 	}
@@ -214,7 +217,7 @@ func TestRebaseBranch(t *testing.T) {
 
 	// Test RebaseBranch - rebase branch onto root()
 	// This may fail due to repo state, but we verify the command executes
-	err := RebaseBranch(tmpDir, "@-", "root()")
+	err := RebaseBranch(context.Background(), tmpDir, "@-", "root()")
 	if err != nil {
 		t.Logf("RebaseBranch returned error (may be expected depending on repo state): %v", err)
 	}
@@ -223,12 +226,22 @@ func TestRebaseBranch(t *testing.T) {
 // TestRebaseBranchErrors tests error handling in RebaseBranch
 func TestRebaseBranchErrors(t *testing.T) {
 	// Test with non-existent repo
-	err := RebaseBranch("/nonexistent/path", "@", "@-")
+	err := RebaseBranch(context.Background(), "/nonexistent/path", "@", "@-")
 	if err == nil {
 		t.Errorf("RebaseBranch should fail with non-existent repo path")
 	}
 }
 
+// TestAdvanceBookmarksErrors tests error handling when advancing bookmarks
+func TestAdvanceBookmarksErrors(t *testing.T) {
+	if _, err := BookmarkedParent(context.Background(), "/nonexistent/path"); err == nil {
+		t.Errorf("BookmarkedParent should fail with non-existent repo path")
+	}
+	if err := AdvanceBookmarks(context.Background(), "/nonexistent/path", "abc123"); err == nil {
+		t.Errorf("AdvanceBookmarks should fail with non-existent repo path")
+	}
+}
+
 // TestGetDescription tests that GetDescription returns empty string for changes without descriptions
 func TestGetDescription(t *testing.T) {
 	// Create a temporary directory as a mock repo
@@ -252,7 +265,7 @@ func TestGetDescription(t *testing.T) {
 	// Test GetDescription on a change with no description
 	// The old template "description" would return "@ | ~" for empty descriptions
 	// The new template "if(description, description, \"\")" should return empty string
-	desc, err := GetDescription(tmpDir, changeID)
+	desc, err := GetDescription(context.Background(), tmpDir, changeID)
 	if err != nil {
 		t.Errorf("GetDescription failed: %v", err)
 	}
@@ -265,3 +278,730 @@ func TestGetDescription(t *testing.T) {
 	// The description should be empty or contain actual description text
 	t.Logf("Description for change with no description: %q", desc)
 }
+
+// TestRangeRevset tests building the revset between two changes
+func TestRangeRevset(t *testing.T) {
+	if got := RangeRevset("abc", "def"); got != "abc::def" {
+		t.Errorf("RangeRevset() = %q, want %q", got, "abc::def")
+	}
+	if got := RangeRevset("abc", "abc"); got != "abc" {
+		t.Errorf("RangeRevset() of one change = %q, want %q", got, "abc")
+	}
+}
+
+// TestDescriptionsErrors tests error handling in Descriptions
+func TestDescriptionsErrors(t *testing.T) {
+	if _, err := Descriptions(context.Background(), "/nonexistent/path", "@"); err == nil {
+		t.Errorf("Descriptions should fail with non-existent repo path")
+	}
+}
+
+// TestBackoutErrors tests error handling in Backout
+func TestBackoutErrors(t *testing.T) {
+	if err := Backout(context.Background(), "/nonexistent/path", "@-", "Back out"); err == nil {
+		t.Errorf("Backout should fail with non-existent repo path")
+	}
+}
+
+// TestParallelizeErrors tests error handling in Parallelize
+func TestParallelizeErrors(t *testing.T) {
+	err := Parallelize(context.Background(), "/nonexistent/path", "@ | @-")
+	if err == nil {
+		t.Errorf("Parallelize should fail with non-existent repo path")
+	}
+}
+
+// TestCheckLinear tests telling a linear run from other shapes of range
+func TestCheckLinear(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		wantErr bool
+	}{
+		{"linear", "cccccccc\tbbbbbbbb\nbbbbbbbb\taaaaaaaa\naaaaaaaa\tzzzzzzzz\n", false},
+		{"merge below the run", "bbbbbbbb\taaaaaaaa\naaaaaaaa\tyyyyyyyy,zzzzzzzz\n", false},
+		{"single change", "aaaaaaaa\tzzzzzzzz\n", true},
+		{"gap", "cccccccc\tbbbbbbbb\naaaaaaaa\tzzzzzzzz\n", true},
+		{"branch", "cccccccc\taaaaaaaa\nbbbbbbbb\taaaaaaaa\naaaaaaaa\tzzzzzzzz\n", true},
+		{"merge in the run", "cccccccc\taaaaaaaa,bbbbbbbb\nbbbbbbbb\tzzzzzzzz\naaaaaaaa\tzzzzzzzz\n", true},
+	}
+	for _, tt := range tests {
+		if err := checkLinear(tt.output); (err != nil) != tt.wantErr {
+			t.Errorf("%s: checkLinear() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+	if err := CheckLinear(context.Background(), "/nonexistent/path", "@ | @-"); err == nil {
+		t.Errorf("CheckLinear should fail with non-existent repo path")
+	}
+}
+
+// TestCommitIDOfErrors tests error handling in CommitIDOf
+func TestCommitIDOfErrors(t *testing.T) {
+	if _, err := CommitIDOf(context.Background(), "/nonexistent/path", "@"); err == nil {
+		t.Errorf("CommitIDOf should fail with non-existent repo path")
+	}
+}
+
+// TestChangeIDOfErrors tests error handling in ChangeIDOf
+func TestChangeIDOfErrors(t *testing.T) {
+	if _, err := ChangeIDOf(context.Background(), "/nonexistent/path", "trunk()"); err == nil {
+		t.Errorf("ChangeIDOf should fail with non-existent repo path")
+	}
+}
+
+// TestLogCLIAtOpErrors tests error handling in LogCLIAtOp
+func TestLogCLIAtOpErrors(t *testing.T) {
+	if _, err := LogCLIAtOp(context.Background(), "/nonexistent/path", "000000000000"); err == nil {
+		t.Errorf("LogCLIAtOp should fail with non-existent repo path")
+	}
+}
+
+// TestFileHistoryErrors tests error handling in FileHistory
+func TestFileHistoryErrors(t *testing.T) {
+	if _, err := FileHistory(context.Background(), "/nonexistent/path", "main.go"); err == nil {
+		t.Errorf("FileHistory should fail with non-existent repo path")
+	}
+}
+
+// TestParseStaleBookmarks tests picking merged and orphaned bookmarks, sparing trunk
+func TestParseStaleBookmarks(t *testing.T) {
+	merged := "main\nold-feature\n"
+	listing := "gone\t\t\tpresent\n" +
+		"gone\torigin\ttracked\t\n" +
+		"main\t\t\tpresent\n" +
+		"main\torigin\ttracked\tpresent\n" +
+		"old-feature\t\t\tpresent\n" +
+		"wip\t\t\tpresent\n" +
+		"wip\tgit\ttracked\t\n"
+
+	got := parseStaleBookmarks(merged, listing, []string{"main"})
+	want := []StaleBookmark{
+		{Name: "gone", Reason: StaleRemoteGone},
+		{Name: "old-feature", Reason: StaleMerged},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("parseStaleBookmarks() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("parseStaleBookmarks()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+// TestParseSummary tests combining the status bar queries
+func TestParseSummary(t *testing.T) {
+	got := parseSummary("main\nrelease\n", "abc12345\ndef67890\n", "xyz11111\nxyz11111\nqrs22222\n")
+	if strings.Join(got.Bookmarks, ",") != "main,release" {
+		t.Errorf("Bookmarks = %v, want [main release]", got.Bookmarks)
+	}
+	if got.Conflicts != 2 {
+		t.Errorf("Conflicts = %d, want 2", got.Conflicts)
+	}
+	if strings.Join(got.Divergent, ",") != "xyz11111,qrs22222" {
+		t.Errorf("Divergent = %v, want [xyz11111 qrs22222]", got.Divergent)
+	}
+
+	if empty := parseSummary("", "", ""); len(empty.Bookmarks) != 0 || empty.Conflicts != 0 || len(empty.Divergent) != 0 {
+		t.Errorf("parseSummary of nothing = %+v, want empty", empty)
+	}
+}
+
+// TestSummaryErrors tests error handling in Summary
+func TestSummaryErrors(t *testing.T) {
+	if _, err := Summary(context.Background(), "/nonexistent/path", "trunk()"); err == nil {
+		t.Errorf("Summary should fail with non-existent repo path")
+	}
+}
+
+// TestDeleteBookmarksErrors tests error handling in DeleteBookmarks
+func TestDeleteBookmarksErrors(t *testing.T) {
+	if err := DeleteBookmarks(context.Background(), "/nonexistent/path", []string{"old"}); err == nil {
+		t.Errorf("DeleteBookmarks should fail with non-existent repo path")
+	}
+}
+
+// initTestRepo creates a jj repo in a temp dir and runs each step, a list
+// of jj arguments, in it. The test is skipped when jj isn't available.
+func initTestRepo(t *testing.T, steps ...[]string) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	initCmd := exec.Command("jj", "git", "init", tmpDir)
+	if err := initCmd.Run(); err != nil {
+		t.Skipf("jj not available or unable to initialize repo: %v", err)
+	}
+	for _, args := range steps {
+		jjOutput(t, tmpDir, args...)
+	}
+	return tmpDir
+}
+
+// jjOutput runs jj in repoPath and returns its trimmed stdout
+func jjOutput(t *testing.T, repoPath string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("jj", args...)
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("jj %s failed: %v", strings.Join(args, " "), err)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// TestStaleBookmarks verifies bookmarks merged into the trunk bookmark
+// are reported, and those on trunk or not yet merged aren't
+func TestStaleBookmarks(t *testing.T) {
+	tmpDir := initTestRepo(t,
+		[]string{"describe", "-m", "landed"},
+		[]string{"bookmark", "create", "landed", "-r", "@"},
+		[]string{"new", "-m", "develop tip"},
+		[]string{"bookmark", "create", "develop", "-r", "@"},
+		[]string{"new", "-m", "wip"},
+		[]string{"bookmark", "create", "wip", "-r", "@"},
+	)
+
+	stale, err := StaleBookmarks(context.Background(), tmpDir, TrunkRevset("develop"))
+	if err != nil {
+		t.Fatalf("StaleBookmarks() error = %v", err)
+	}
+	want := []StaleBookmark{{Name: "landed", Reason: StaleMerged}}
+	if !slices.Equal(stale, want) {
+		t.Errorf("StaleBookmarks() = %v, want %v", stale, want)
+	}
+}
+
+// TestDeleteBookmarks verifies only the named bookmarks are deleted,
+// matched exactly rather than as patterns
+func TestDeleteBookmarks(t *testing.T) {
+	tmpDir := initTestRepo(t,
+		[]string{"bookmark", "create", "old", "-r", "@"},
+		[]string{"bookmark", "create", "old-keep", "-r", "@"},
+		[]string{"bookmark", "create", "keep", "-r", "@"},
+	)
+
+	if err := DeleteBookmarks(context.Background(), tmpDir, []string{"old"}); err != nil {
+		t.Fatalf("DeleteBookmarks() error = %v", err)
+	}
+	got := strings.Fields(jjOutput(t, tmpDir, "bookmark", "list", "-T", `name ++ "\n"`))
+	if !slices.Equal(got, []string{"keep", "old-keep"}) {
+		t.Errorf("bookmarks after DeleteBookmarks() = %v, want [keep old-keep]", got)
+	}
+}
+
+// TestParseDiffSizes tests counting changed lines per file in a git diff
+func TestParseDiffSizes(t *testing.T) {
+	diff := "diff --git a/added.go b/added.go\n" +
+		"new file mode 100644\n" +
+		"--- /dev/null\n" +
+		"+++ b/added.go\n" +
+		"@@ -0,0 +1,2 @@\n" +
+		"+package main\n" +
+		"+\n" +
+		"diff --git a/gone.sql b/gone.sql\n" +
+		"deleted file mode 100644\n" +
+		"--- a/gone.sql\n" +
+		"+++ /dev/null\n" +
+		"@@ -1,3 +0,0 @@\n" +
+		"--- a comment\n" +
+		"-select 1;\n" +
+		"-\n" +
+		"diff --git a/image.png b/image.png\n" +
+		"Binary files a/image.png and b/image.png differ\n"
+
+	got := parseDiffSizes(diff)
+	want := map[string]int{"added.go": 2, "gone.sql": 3}
+	if len(got) != len(want) {
+		t.Fatalf("parseDiffSizes() = %v, want %v", got, want)
+	}
+	for path, size := range want {
+		if got[path] != size {
+			t.Errorf("parseDiffSizes()[%q] = %d, want %d", path, got[path], size)
+		}
+	}
+}
+
+// TestParseDiffStats tests the per-file line counts, in diff order
+func TestParseDiffStats(t *testing.T) {
+	diff := "diff --git a/old.go b/new.go\n" +
+		"rename from old.go\n" +
+		"rename to new.go\n" +
+		"--- a/old.go\n" +
+		"+++ b/new.go\n" +
+		"@@ -1,2 +1,2 @@\n" +
+		"-package old\n" +
+		"+package new\n" +
+		" \n" +
+		"diff --git a/image.png b/image.png\n" +
+		"Binary files a/image.png and b/image.png differ\n"
+
+	got := parseDiffStats(diff)
+	want := []FileStat{
+		{Path: "new.go", Added: 1, Removed: 1},
+		{Path: "image.png", Binary: true},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("parseDiffStats() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("parseDiffStats()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+// TestCombinedDescription tests when squashing needs a combined message
+func TestCombinedDescription(t *testing.T) {
+	tests := []struct {
+		dest, source, want string
+	}{
+		{"", "", ""},
+		{"parent\n", "", ""},
+		{"", "child\n", ""},
+		{"parent\n\nbody\n", "child\n", "parent\n\nbody\n\nchild\n"},
+	}
+	for _, tt := range tests {
+		if got := CombinedDescription(tt.dest, tt.source); got != tt.want {
+			t.Errorf("CombinedDescription(%q, %q) = %q, want %q", tt.dest, tt.source, got, tt.want)
+		}
+	}
+}
+
+// TestSquashWithMessageErrors tests error handling in SquashWithMessage
+func TestSquashWithMessageErrors(t *testing.T) {
+	if err := SquashWithMessage(context.Background(), "/nonexistent/path", "abc", "def", "msg"); err == nil {
+		t.Errorf("SquashWithMessage should fail with non-existent repo path")
+	}
+}
+
+// TestParseUntracked tests extracting the untracked section of jj status
+func TestParseUntracked(t *testing.T) {
+	status := "Working copy changes:\n" +
+		"M tracked.go\n" +
+		"Untracked paths:\n" +
+		"? big.bin\n" +
+		"? scratch/\n" +
+		"Working copy  (@) : abc 123 (no description set)\n"
+
+	got := parseUntracked(status)
+	want := []string{"big.bin", "scratch/"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("parseUntracked() = %v, want %v", got, want)
+	}
+
+	if got := parseUntracked("The working copy has no changes.\n"); len(got) != 0 {
+		t.Errorf("parseUntracked() with no section = %v, want none", got)
+	}
+}
+
+// TestTrackFileErrors tests error handling in TrackFile
+func TestTrackFileErrors(t *testing.T) {
+	if err := TrackFile(context.Background(), "/nonexistent/path", "new.txt"); err == nil {
+		t.Errorf("TrackFile should fail with non-existent repo path")
+	}
+}
+
+// TestIgnoreFile tests appending anchored entries to .gitignore
+func TestIgnoreFile(t *testing.T) {
+	dir := t.TempDir()
+	gitignore := filepath.Join(dir, ".gitignore")
+	if err := os.WriteFile(gitignore, []byte("target"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := IgnoreFile(dir, "big.bin"); err != nil {
+		t.Fatalf("IgnoreFile failed: %v", err)
+	}
+	if err := IgnoreFile(dir, "scratch/"); err != nil {
+		t.Fatalf("IgnoreFile failed: %v", err)
+	}
+
+	content, err := os.ReadFile(gitignore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "target\n/big.bin\n/scratch/\n"; string(content) != want {
+		t.Errorf(".gitignore = %q, want %q", content, want)
+	}
+}
+
+// TestParseStructuredLogFlags tests the divergent, hidden and immutable flags
+func TestParseStructuredLogFlags(t *testing.T) {
+	output := "abcdefgh<<SEP>>11111111<<SEP>>wc<<SEP>>work<<SEP>>feat<<SEP>>d\n" +
+		"ijklmnop<<SEP>>22222222<<SEP>>no<<SEP>><<SEP>><<SEP>>hig\n" +
+		"qrstuvwx<<SEP>>33333333<<SEP>>no<<SEP>>old<<SEP>>\n"
+
+	changes := parseStructuredLog(output)
+	if len(changes) != 3 {
+		t.Fatalf("parseStructuredLog() returned %d changes, want 3", len(changes))
+	}
+	if c := changes[0]; !c.Divergent || c.Hidden || c.Immutable || !c.IsWorkingCopy {
+		t.Errorf("changes[0] = %+v, want divergent working copy", c)
+	}
+	if c := changes[1]; c.Divergent || !c.Hidden || !c.Immutable || !c.GitHead {
+		t.Errorf("changes[1] = %+v, want hidden, immutable and git HEAD", c)
+	}
+	if changes[0].GitHead {
+		t.Error("changes[0] should not be git HEAD")
+	}
+	if c := changes[2]; c.Divergent || c.Hidden || c.Immutable {
+		t.Errorf("changes[2] = %+v, want no flags without the flags field", c)
+	}
+}
+
+// TestParseStructuredLogCoAuthors tests reading Co-authored-by trailers
+func TestParseStructuredLogCoAuthors(t *testing.T) {
+	output := "abcdefgh<<SEP>>11111111<<SEP>>no<<SEP>>pair<<SEP>><<SEP>><<SEP>>Ada <ada@example.com>;Bob <bob@example.com>;\n" +
+		"ijklmnop<<SEP>>22222222<<SEP>>no<<SEP>>solo<<SEP>><<SEP>><<SEP>>\n"
+
+	changes := parseStructuredLog(output)
+	if len(changes) != 2 {
+		t.Fatalf("parseStructuredLog() returned %d changes, want 2", len(changes))
+	}
+	want := []string{"Ada <ada@example.com>", "Bob <bob@example.com>"}
+	if got := changes[0].CoAuthors; strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("changes[0].CoAuthors = %v, want %v", got, want)
+	}
+	if got := changes[1].CoAuthors; got != nil {
+		t.Errorf("changes[1].CoAuthors = %v, want none", got)
+	}
+}
+
+// TestParseLogAuthors tests that each author is listed once
+func TestParseLogAuthors(t *testing.T) {
+	output := "Ada<<SEP>>ada@example.com\nBob<<SEP>>bob@example.com\nAda L<<SEP>>ada@example.com\n<<SEP>>\n"
+	got := parseLogAuthors(output)
+	want := []Author{{Name: "Ada", Email: "ada@example.com"}, {Name: "Bob", Email: "bob@example.com"}}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("parseLogAuthors() = %v, want %v", got, want)
+	}
+}
+
+func TestAuthorRevset(t *testing.T) {
+	want := `author_email(exact:"ada@example.com") | (description(substring:"Co-authored-by:") & description(substring:"<ada@example.com>"))`
+	if got := AuthorRevset("ada@example.com"); got != want {
+		t.Errorf("AuthorRevset() = %s, want %s", got, want)
+	}
+}
+
+// TestParseDivergentCommits tests grouping divergent commits by change
+func TestParseDivergentCommits(t *testing.T) {
+	output := "aaaaaaaa\t11111111\t2026-01-02 10:00\tfirst copy\n" +
+		"bbbbbbbb\t22222222\t2026-01-02 09:00\t\n" +
+		"aaaaaaaa\t33333333\t2026-01-01 08:00\tsecond copy\n"
+
+	got := parseDivergentCommits(output)
+	var ids []string
+	for _, c := range got {
+		ids = append(ids, c.ChangeID+":"+c.CommitID)
+	}
+	want := "aaaaaaaa:11111111,aaaaaaaa:33333333,bbbbbbbb:22222222"
+	if strings.Join(ids, ",") != want {
+		t.Errorf("parseDivergentCommits() = %v, want %s", ids, want)
+	}
+	if got[1].Description != "second copy" || got[1].Timestamp != "2026-01-01 08:00" {
+		t.Errorf("parseDivergentCommits()[1] = %+v", got[1])
+	}
+}
+
+// TestAbandonCommitsErrors tests error handling in AbandonCommits
+func TestAbandonCommitsErrors(t *testing.T) {
+	if err := AbandonCommits(context.Background(), "/nonexistent/path", []string{"11111111"}); err == nil {
+		t.Errorf("AbandonCommits should fail with non-existent repo path")
+	}
+}
+
+// TestParseEditRisk tests reading immutability and remote bookmarks
+func TestParseEditRisk(t *testing.T) {
+	risk := parseEditRisk("immutable\nmain@origin\nmain@git\nrelease@upstream\n")
+	if !risk.Immutable || !risk.Risky() {
+		t.Errorf("parseEditRisk() = %+v, want immutable", risk)
+	}
+	if got := strings.Join(risk.RemoteBookmarks, ","); got != "main@origin,release@upstream" {
+		t.Errorf("parseEditRisk().RemoteBookmarks = %q, want main@origin,release@upstream", got)
+	}
+
+	if risk := parseEditRisk("\n"); risk.Risky() {
+		t.Errorf("parseEditRisk() for a local mutable revision = %+v, want no risk", risk)
+	}
+}
+
+// TestCheckEditErrors tests error handling in CheckEdit and EditIgnoringImmutable
+func TestCheckEditErrors(t *testing.T) {
+	if _, err := CheckEdit(context.Background(), "/nonexistent/path", "@"); err == nil {
+		t.Errorf("CheckEdit should fail with non-existent repo path")
+	}
+	if err := EditIgnoringImmutable(context.Background(), "/nonexistent/path", "@"); err == nil {
+		t.Errorf("EditIgnoringImmutable should fail with non-existent repo path")
+	}
+}
+
+// TestCreateTagErrors tests error handling in CreateTag
+func TestCreateTagErrors(t *testing.T) {
+	if err := CreateTag(context.Background(), "/nonexistent/path", "v1.0.0", "@"); err == nil {
+		t.Errorf("CreateTag should fail with non-existent repo path")
+	}
+}
+
+// TestGitColocationDiverged tests when git and the working copy disagree
+func TestGitColocationDiverged(t *testing.T) {
+	tests := []struct {
+		name  string
+		c     GitColocation
+		moved bool
+		want  bool
+	}{
+		{"not colocated", GitColocation{Head: "11111111"}, false, false},
+		{"in sync", GitColocation{Colocated: true, Head: "11111111", Parent: "11111111"}, false, false},
+		{"unborn", GitColocation{Colocated: true}, false, false},
+		{"head moved", GitColocation{Colocated: true, Head: "22222222", Parent: "11111111"}, true, true},
+		{"staged", GitColocation{Colocated: true, Head: "11111111", Parent: "11111111", Staged: true}, false, true},
+	}
+	for _, tt := range tests {
+		if got := tt.c.HeadMoved(); got != tt.moved {
+			t.Errorf("%s: HeadMoved() = %v, want %v", tt.name, got, tt.moved)
+		}
+		if got := tt.c.Diverged(); got != tt.want {
+			t.Errorf("%s: Diverged() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestGitSyncErrors tests colocation checks and import/export on a bad path
+func TestGitSyncErrors(t *testing.T) {
+	if _, err := Colocation(context.Background(), "/nonexistent/path"); err == nil {
+		t.Error("Colocation() expected error for invalid path")
+	}
+	if err := GitImport(context.Background(), "/nonexistent/path"); err == nil {
+		t.Error("GitImport() expected error for invalid path")
+	}
+	if err := GitExport(context.Background(), "/nonexistent/path"); err == nil {
+		t.Error("GitExport() expected error for invalid path")
+	}
+}
+
+func TestSnapshotErrors(t *testing.T) {
+	if err := Snapshot(context.Background(), "/nonexistent/path"); err == nil {
+		t.Error("Snapshot() expected error for invalid path")
+	}
+}
+
+func TestParseAuthor(t *testing.T) {
+	author, err := parseAuthor("Ada Lovelace<<SEP>>ada@example.com<<SEP>>2024-01-02T03:04:05+00:00\n")
+	if err != nil {
+		t.Fatalf("parseAuthor() error = %v", err)
+	}
+	want := Author{Name: "Ada Lovelace", Email: "ada@example.com", Timestamp: "2024-01-02T03:04:05+00:00"}
+	if *author != want {
+		t.Errorf("parseAuthor() = %+v, want %+v", *author, want)
+	}
+	if got := author.String(); got != "Ada Lovelace <ada@example.com>" {
+		t.Errorf("String() = %q", got)
+	}
+	if _, err := parseAuthor("garbage"); err == nil {
+		t.Error("parseAuthor() expected an error for malformed output")
+	}
+}
+
+func TestAuthorErrors(t *testing.T) {
+	ctx := context.Background()
+	if _, err := GetAuthor(ctx, "/nonexistent/path", "abc"); err == nil {
+		t.Error("GetAuthor() expected error for nonexistent path")
+	}
+	if err := SetAuthor(ctx, "/nonexistent/path", "abc", Author{Name: "A", Email: "a@example.com"}); err == nil {
+		t.Error("SetAuthor() expected error for nonexistent path")
+	}
+	if err := ResetAuthor(ctx, "/nonexistent/path", "abc"); err == nil {
+		t.Error("ResetAuthor() expected error for nonexistent path")
+	}
+}
+
+func TestSigningErrors(t *testing.T) {
+	ctx := context.Background()
+	if got := SigningBackend(ctx, "/nonexistent/path"); got != "" {
+		t.Errorf("SigningBackend() = %q, want empty when config can't be read", got)
+	}
+	if err := Sign(ctx, "/nonexistent/path", "abc"); err == nil {
+		t.Error("Sign() expected error for nonexistent path")
+	}
+}
+
+func TestOpDiffErrors(t *testing.T) {
+	if _, err := OpDiff(context.Background(), "/nonexistent/path", "abc123"); err == nil {
+		t.Error("OpDiff() expected error for nonexistent path")
+	}
+}
+
+func TestDiffOptionsArgs(t *testing.T) {
+	tests := []struct {
+		opts DiffOptions
+		want string
+	}{
+		{DiffOptions{}, ""},
+		{DiffOptions{IgnoreWhitespace: true}, "--ignore-all-space"},
+		{DiffOptions{Context: 10}, "--context 10"},
+		{DiffOptions{IgnoreWhitespace: true, Context: 1}, "--ignore-all-space --context 1"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(tt.opts.args(), " "); got != tt.want {
+			t.Errorf("%+v.args() = %q, want %q", tt.opts, got, tt.want)
+		}
+	}
+}
+
+// TestParallelize verifies a linear pair of changes become siblings on
+// their common parent
+func TestParallelize(t *testing.T) {
+	tmpDir := initTestRepo(t,
+		[]string{"describe", "-m", "first"},
+		[]string{"new", "-m", "second"},
+	)
+
+	if err := Parallelize(context.Background(), tmpDir, "@- | @"); err != nil {
+		t.Fatalf("Parallelize() error = %v", err)
+	}
+	children := strings.Fields(jjOutput(t, tmpDir, "log", "--no-graph", "-r", "root()+", "-T", `description.first_line() ++ "\n"`))
+	slices.Sort(children)
+	if want := []string{"first", "second"}; !slices.Equal(children, want) {
+		t.Errorf("children of root = %v, want %v", children, want)
+	}
+}
+
+// TestBackout verifies the reverting change lands on top of @, undoes the
+// target's diff and takes the given description
+func TestBackout(t *testing.T) {
+	tmpDir := initTestRepo(t)
+	if err := os.WriteFile(filepath.Join(tmpDir, "added.txt"), []byte("added\n"), 0o644); err != nil {
+		t.Fatalf("failed to write added.txt: %v", err)
+	}
+	jjOutput(t, tmpDir, "commit", "-m", "add file")
+
+	if err := Backout(context.Background(), tmpDir, "@-", "back out add file"); err != nil {
+		t.Fatalf("Backout() error = %v", err)
+	}
+	if got := jjOutput(t, tmpDir, "diff", "--summary", "-r", "latest(@+)"); got != "D added.txt" {
+		t.Errorf("reverting change diff = %q, want %q", got, "D added.txt")
+	}
+	if got := jjOutput(t, tmpDir, "log", "--no-graph", "-r", "latest(@+)", "-T", "description.first_line()"); got != "back out add file" {
+		t.Errorf("reverting change description = %q, want %q", got, "back out add file")
+	}
+}
+
+// TestCreateTag verifies the tag points at the given revision
+func TestCreateTag(t *testing.T) {
+	tmpDir := initTestRepo(t,
+		[]string{"describe", "-m", "release"},
+		[]string{"new"},
+	)
+
+	if err := CreateTag(context.Background(), tmpDir, "v1.0.0", "@-"); err != nil {
+		t.Fatalf("CreateTag() error = %v", err)
+	}
+	if got := jjOutput(t, tmpDir, "log", "--no-graph", "-r", "@-", "-T", `tags.join(",")`); got != "v1.0.0" {
+		t.Errorf("tags on @- = %q, want %q", got, "v1.0.0")
+	}
+}
+
+// TestGitImportExport verifies a colocated repo's bookmarks reach git on
+// export and git's branches reach jj on import
+func TestGitImportExport(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	tmpDir := t.TempDir()
+	initCmd := exec.Command("jj", "git", "init", "--colocate", tmpDir)
+	if err := initCmd.Run(); err != nil {
+		t.Skipf("jj not available or unable to initialize repo: %v", err)
+	}
+	jjOutput(t, tmpDir, "describe", "-m", "exported")
+	jjOutput(t, tmpDir, "bookmark", "create", "from-jj", "-r", "@")
+	commitID := jjOutput(t, tmpDir, "log", "--no-graph", "-r", "@", "-T", "commit_id")
+	ctx := context.Background()
+
+	if err := GitExport(ctx, tmpDir); err != nil {
+		t.Fatalf("GitExport() error = %v", err)
+	}
+	out, err := exec.Command("git", "-C", tmpDir, "rev-parse", "refs/heads/from-jj").Output()
+	if err != nil {
+		t.Fatalf("git rev-parse failed: %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); got != commitID {
+		t.Errorf("git from-jj = %q, want %q", got, commitID)
+	}
+
+	if err := exec.Command("git", "-C", tmpDir, "branch", "from-git", commitID).Run(); err != nil {
+		t.Fatalf("git branch failed: %v", err)
+	}
+	if err := GitImport(ctx, tmpDir); err != nil {
+		t.Fatalf("GitImport() error = %v", err)
+	}
+	if got := jjOutput(t, tmpDir, "log", "--no-graph", "-r", `bookmarks(exact:"from-git")`, "-T", "commit_id"); got != commitID {
+		t.Errorf("jj from-git = %q, want %q", got, commitID)
+	}
+}
+
+// TestAdvanceBookmarks verifies a bookmark on the old @- follows a commit
+// forward to the new @-
+func TestAdvanceBookmarks(t *testing.T) {
+	tmpDir := initTestRepo(t,
+		[]string{"commit", "-m", "base"},
+		[]string{"bookmark", "create", "feature", "-r", "@-"},
+	)
+	commitID := jjOutput(t, tmpDir, "log", "--no-graph", "-r", "@-", "-T", "commit_id")
+	jjOutput(t, tmpDir, "commit", "-m", "next")
+
+	if err := AdvanceBookmarks(context.Background(), tmpDir, commitID); err != nil {
+		t.Fatalf("AdvanceBookmarks() error = %v", err)
+	}
+	if got := jjOutput(t, tmpDir, "log", "--no-graph", "-r", "@-", "-T", `bookmarks.join(",")`); got != "feature" {
+		t.Errorf("bookmarks on @- = %q, want %q", got, "feature")
+	}
+}
+
+// TestSnapshot verifies a file written outside jj is recorded in @
+func TestSnapshot(t *testing.T) {
+	tmpDir := initTestRepo(t)
+	if err := os.WriteFile(filepath.Join(tmpDir, "edited.txt"), []byte("edited\n"), 0o644); err != nil {
+		t.Fatalf("failed to write edited.txt: %v", err)
+	}
+
+	if err := Snapshot(context.Background(), tmpDir); err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	// Read what Snapshot recorded without letting jj snapshot again
+	if got := jjOutput(t, tmpDir, "file", "list", "-r", "@", "--ignore-working-copy"); got != "edited.txt" {
+		t.Errorf("files in @ = %q, want %q", got, "edited.txt")
+	}
+}
+
+// TestSetAuthor verifies the change's author and timestamp are rewritten
+func TestSetAuthor(t *testing.T) {
+	tmpDir := initTestRepo(t)
+	ctx := context.Background()
+	author := Author{Name: "Ada Lovelace", Email: "ada@example.com", Timestamp: "2024-01-02T03:04:05+00:00"}
+
+	if err := SetAuthor(ctx, tmpDir, "@", author); err != nil {
+		t.Fatalf("SetAuthor() error = %v", err)
+	}
+	got, err := GetAuthor(ctx, tmpDir, "@")
+	if err != nil {
+		t.Fatalf("GetAuthor() error = %v", err)
+	}
+	if *got != author {
+		t.Errorf("GetAuthor() = %+v, want %+v", *got, author)
+	}
+}
+
+// TestOpDiff verifies the diff of a real operation names the change it
+// rewrote
+func TestOpDiff(t *testing.T) {
+	tmpDir := initTestRepo(t, []string{"describe", "-m", "described"})
+	opID := jjOutput(t, tmpDir, "op", "log", "-n1", "--no-graph", "-T", "id.short()")
+
+	diff, err := OpDiff(context.Background(), tmpDir, opID)
+	if err != nil {
+		t.Fatalf("OpDiff() error = %v", err)
+	}
+	if !strings.Contains(diff, "described") {
+		t.Errorf("OpDiff() = %q, want it to mention the described change", diff)
+	}
+}
@@ -0,0 +1,13 @@
+package jj
+
+import "github.com/gerunddev/jjazy/jj/internal/ffi"
+
+// debugLogLines is how many lines DebugLog returns at most
+const debugLogLines = 500
+
+// DebugLog returns the newest lines of the bridge's operation log, oldest
+// first, and where they came from: the JJAZY_LOG_FILE path when logging to
+// a file, otherwise "memory" for the entries kept this session
+func DebugLog() (lines []string, source string, err error) {
+	return ffi.RecentLog(debugLogLines)
+}
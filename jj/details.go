@@ -0,0 +1,65 @@
+package jj
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// RevisionDetails is everything about a revision that the log row cuts
+// short
+type RevisionDetails struct {
+	ChangeID    string   // Full change ID
+	CommitID    string   // Full commit ID
+	Parents     []Parent // In order, the first parent first
+	Author      Author
+	Committer   Author
+	Bookmarks   []string // Local bookmarks, then remote ones as name@remote
+	Description string   // Full description (empty if none)
+}
+
+// Parent is a parent revision of a change
+type Parent struct {
+	CommitID string // Full commit ID
+	ChangeID string // Shortest unique change ID prefix
+}
+
+// detailsTemplate renders a revision's details separated by <<SEP>>, ending
+// with the description since it may span lines
+const detailsTemplate = `change_id ++ "<<SEP>>" ++ commit_id ++ "<<SEP>>" ++
+	parents.map(|p| p.commit_id() ++ " " ++ p.change_id().shortest()).join(",") ++ "<<SEP>>" ++
+	author.name() ++ "<<SEP>>" ++ author.email() ++ "<<SEP>>" ++ author.timestamp().format("%Y-%m-%d %H:%M:%S %:z") ++ "<<SEP>>" ++
+	committer.name() ++ "<<SEP>>" ++ committer.email() ++ "<<SEP>>" ++ committer.timestamp().format("%Y-%m-%d %H:%M:%S %:z") ++ "<<SEP>>" ++
+	bookmarks.map(|b| if(b.remote(), b.name() ++ "@" ++ b.remote(), b.name())).join(" ") ++ "<<SEP>>" ++
+	description`
+
+// GetRevisionDetails returns the full details of a revision
+func GetRevisionDetails(ctx context.Context, repoPath, revisionSpec string) (*RevisionDetails, error) {
+	output, err := runJJ(ctx, repoPath, "log", "-r", revisionSpec, "--no-graph", "-T", detailsTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return parseRevisionDetails(string(output))
+}
+
+// parseRevisionDetails parses the output of detailsTemplate
+func parseRevisionDetails(output string) (*RevisionDetails, error) {
+	parts := strings.SplitN(output, "<<SEP>>", 11)
+	if len(parts) != 11 {
+		return nil, fmt.Errorf("unexpected revision details output: %q", output)
+	}
+	details := &RevisionDetails{
+		ChangeID:    parts[0],
+		CommitID:    parts[1],
+		Author:      Author{Name: parts[3], Email: parts[4], Timestamp: parts[5]},
+		Committer:   Author{Name: parts[6], Email: parts[7], Timestamp: parts[8]},
+		Bookmarks:   strings.Fields(parts[9]),
+		Description: strings.TrimRight(parts[10], "\n"),
+	}
+	for _, parent := range strings.Split(parts[2], ",") {
+		if commitID, changeID, ok := strings.Cut(parent, " "); ok {
+			details.Parents = append(details.Parents, Parent{CommitID: commitID, ChangeID: changeID})
+		}
+	}
+	return details, nil
+}
@@ -0,0 +1,48 @@
+package jj
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+// TestParseRevisionDetails tests parsing every field, including a
+// multi-line description and a merge's parents
+func TestParseRevisionDetails(t *testing.T) {
+	output := "kmnopqrstuvwxyzkmnopqrstuvwxyzkm<<SEP>>0123456789abcdef0123456789abcdef01234567<<SEP>>" +
+		"1111111111111111111111111111111111111111 zz,2222222222222222222222222222222222222222 yx<<SEP>>" +
+		"Ada Lovelace<<SEP>>ada@example.com<<SEP>>2024-01-02 03:04:05 +00:00<<SEP>>" +
+		"Charles Babbage<<SEP>>charles@example.com<<SEP>>2024-01-03 04:05:06 +01:00<<SEP>>" +
+		"main main@origin<<SEP>>Add the engine\n\nWith notes.\n"
+
+	details, err := parseRevisionDetails(output)
+	if err != nil {
+		t.Fatalf("parseRevisionDetails() error = %v", err)
+	}
+	if details.ChangeID != "kmnopqrstuvwxyzkmnopqrstuvwxyzkm" || details.CommitID != "0123456789abcdef0123456789abcdef01234567" {
+		t.Errorf("IDs parsed wrong: %+v", details)
+	}
+	wantParents := []Parent{
+		{CommitID: "1111111111111111111111111111111111111111", ChangeID: "zz"},
+		{CommitID: "2222222222222222222222222222222222222222", ChangeID: "yx"},
+	}
+	if !slices.Equal(details.Parents, wantParents) {
+		t.Errorf("Parents = %+v, want %+v", details.Parents, wantParents)
+	}
+	if details.Author.String() != "Ada Lovelace <ada@example.com>" || details.Committer.Timestamp != "2024-01-03 04:05:06 +01:00" {
+		t.Errorf("Author = %+v, Committer = %+v", details.Author, details.Committer)
+	}
+	if !slices.Equal(details.Bookmarks, []string{"main", "main@origin"}) {
+		t.Errorf("Bookmarks = %v", details.Bookmarks)
+	}
+	if details.Description != "Add the engine\n\nWith notes." {
+		t.Errorf("Description = %q", details.Description)
+	}
+
+	if _, err := parseRevisionDetails("garbage"); err == nil {
+		t.Error("parseRevisionDetails() expected an error for malformed output")
+	}
+	if _, err := GetRevisionDetails(context.Background(), "/nonexistent/path", "@"); err == nil {
+		t.Error("GetRevisionDetails() expected error for invalid path")
+	}
+}
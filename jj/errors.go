@@ -0,0 +1,198 @@
+package jj
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync/atomic"
+)
+
+// Sentinel errors for failures the UI makes decisions on. Errors from both
+// the bridge and the CLI wrappers match these with errors.Is.
+var (
+	ErrImmutable        = errors.New("revision is immutable")
+	ErrBackwards        = errors.New("bookmark would move backwards")
+	ErrNotFound         = errors.New("not found")
+	ErrStaleWorkingCopy = errors.New("working copy is stale")
+)
+
+// errorKinds maps message fragments from jj and the bridge to sentinels.
+// This is the only place that depends on error wording.
+var errorKinds = []struct {
+	fragment string
+	kind     error
+}{
+	{"immutable", ErrImmutable},
+	{"backwards", ErrBackwards},
+	{"working copy is stale", ErrStaleWorkingCopy},
+	{"doesn't exist", ErrNotFound},
+	{"not found", ErrNotFound},
+}
+
+// classifyMessage returns the sentinel matching an error message, or nil
+func classifyMessage(msg string) error {
+	msg = strings.ToLower(msg)
+	for _, k := range errorKinds {
+		if strings.Contains(msg, k.fragment) {
+			return k.kind
+		}
+	}
+	return nil
+}
+
+// kindError attaches a sentinel to an error without changing its message
+type kindError struct {
+	err  error
+	kind error
+}
+
+func (e *kindError) Error() string {
+	return e.err.Error()
+}
+
+func (e *kindError) Unwrap() []error {
+	return []error{e.err, e.kind}
+}
+
+// classify tags a bridge error with its sentinel so callers can use errors.Is
+func classify(err error) error {
+	if err == nil {
+		return nil
+	}
+	if kind := classifyMessage(err.Error()); kind != nil {
+		return &kindError{err: err, kind: kind}
+	}
+	return err
+}
+
+// JJError is a jj command that failed, with what it wrote to stderr.
+type JJError struct {
+	Args     []string // Arguments passed to jj
+	ExitCode int      // -1 if jj could not be started
+	Stderr   string   // Trimmed stderr output
+	Err      error    // Underlying exec error
+}
+
+func (e *JJError) Error() string {
+	if e.Stderr == "" {
+		return fmt.Sprintf("jj %s failed: %v", e.Subcommand(), e.Err)
+	}
+	return fmt.Sprintf("jj %s failed: %s", e.Subcommand(), e.Stderr)
+}
+
+func (e *JJError) Unwrap() error {
+	return e.Err
+}
+
+// Is matches the sentinel errors by classifying jj's stderr
+func (e *JJError) Is(target error) bool {
+	kind := classifyMessage(e.Stderr)
+	return kind != nil && kind == target
+}
+
+// Subcommand returns the jj subcommand that failed, e.g. "rebase" or "git push"
+func (e *JJError) Subcommand() string {
+	if len(e.Args) == 0 {
+		return ""
+	}
+	if e.Args[0] == "git" && len(e.Args) > 1 {
+		return "git " + e.Args[1]
+	}
+	return e.Args[0]
+}
+
+// Command returns the full command line that was run
+func (e *JJError) Command() string {
+	return "jj " + strings.Join(e.Args, " ")
+}
+
+// Message returns jj's error line without the "Error: " prefix,
+// falling back to the exec error if jj printed nothing
+func (e *JJError) Message() string {
+	for _, line := range strings.Split(e.Stderr, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "Hint: ") {
+			continue
+		}
+		return strings.TrimPrefix(line, "Error: ")
+	}
+	return e.Err.Error()
+}
+
+// Hints returns jj's "Hint: " lines without the prefix
+func (e *JJError) Hints() []string {
+	var hints []string
+	for _, line := range strings.Split(e.Stderr, "\n") {
+		if hint, ok := strings.CutPrefix(strings.TrimSpace(line), "Hint: "); ok {
+			hints = append(hints, hint)
+		}
+	}
+	return hints
+}
+
+// atOpKey is the context key for AtOperation
+type atOpKey struct{}
+
+// AtOperation returns a context whose jj commands see the repo as it was
+// at opID (jj --at-op). The working copy is neither snapshotted nor
+// updated, so reads are safe; mutations would fork the operation log.
+func AtOperation(ctx context.Context, opID string) context.Context {
+	return context.WithValue(ctx, atOpKey{}, opID)
+}
+
+// operationOf returns the operation set with AtOperation, or ""
+func operationOf(ctx context.Context) string {
+	opID, _ := ctx.Value(atOpKey{}).(string)
+	return opID
+}
+
+// frozenTimestamps is set by FreezeTimestamps
+var frozenTimestamps atomic.Bool
+
+// frozenTimestampConfig makes jj's templates print timestamps in UTC
+const frozenTimestampConfig = `--config=template-aliases."format_timestamp(timestamp)"='timestamp.utc().format("%Y-%m-%d %H:%M:%S")'`
+
+// FreezeTimestamps makes every jj command print timestamps as UTC instead
+// of local (or relative) time, so output doesn't depend on the machine.
+func FreezeTimestamps() {
+	frozenTimestamps.Store(true)
+}
+
+// runJJ runs jj in repoPath and returns its stdout. Failures are returned
+// as a *JJError carrying stderr and the exit code. Cancelling ctx kills jj;
+// the resulting error matches context.Canceled. A ctx from AtOperation
+// adds --at-op.
+func runJJ(ctx context.Context, repoPath string, args ...string) ([]byte, error) {
+	if opID := operationOf(ctx); opID != "" {
+		args = append(args[:len(args):len(args)], "--at-op", opID)
+	}
+	if frozenTimestamps.Load() {
+		args = append(args[:len(args):len(args)], frozenTimestampConfig)
+	}
+	cmd := exec.CommandContext(ctx, "jj", args...)
+	cmd.Dir = repoPath
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		jjErr := &JJError{
+			Args:     args,
+			ExitCode: -1,
+			Stderr:   strings.TrimSpace(stderr.String()),
+			Err:      err,
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			jjErr.ExitCode = exitErr.ExitCode()
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			jjErr.Err = ctxErr // Killed by us, not a jj failure
+		}
+		return nil, jjErr
+	}
+	return output, nil
+}
@@ -0,0 +1,160 @@
+package jj
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestJJErrorMessage verifies the message and hints are split out of stderr
+func TestJJErrorMessage(t *testing.T) {
+	err := &JJError{
+		Args:     []string{"git", "push", "--bookmark", "feature"},
+		ExitCode: 1,
+		Stderr: "Error: Refusing to create new remote bookmark feature@origin\n" +
+			"Hint: Use --allow-new to push new bookmark.\n" +
+			"Hint: Or configure git.push-new-bookmarks.",
+		Err: errors.New("exit status 1"),
+	}
+
+	if got := err.Message(); got != "Refusing to create new remote bookmark feature@origin" {
+		t.Errorf("Message() = %q", got)
+	}
+	wantHints := []string{"Use --allow-new to push new bookmark.", "Or configure git.push-new-bookmarks."}
+	if got := err.Hints(); !reflect.DeepEqual(got, wantHints) {
+		t.Errorf("Hints() = %q, want %q", got, wantHints)
+	}
+	if got := err.Subcommand(); got != "git push" {
+		t.Errorf("Subcommand() = %q, want %q", got, "git push")
+	}
+	if got := err.Command(); got != "jj git push --bookmark feature" {
+		t.Errorf("Command() = %q", got)
+	}
+}
+
+// TestJJErrorEmptyStderr verifies the exec error is used when jj printed nothing
+func TestJJErrorEmptyStderr(t *testing.T) {
+	err := &JJError{Args: []string{"log"}, ExitCode: -1, Err: errors.New("boom")}
+	if got := err.Message(); got != "boom" {
+		t.Errorf("Message() = %q, want %q", got, "boom")
+	}
+	if got := err.Error(); got != "jj log failed: boom" {
+		t.Errorf("Error() = %q", got)
+	}
+}
+
+// TestRunJJErrors verifies wrapper failures surface as *JJError
+func TestRunJJErrors(t *testing.T) {
+	err := Edit(context.Background(), "/nonexistent/path", "@")
+	var jjErr *JJError
+	if !errors.As(err, &jjErr) {
+		t.Fatalf("Expected *JJError, got %T: %v", err, err)
+	}
+	if jjErr.ExitCode != -1 {
+		t.Errorf("ExitCode = %d, want -1 for a command that could not start", jjErr.ExitCode)
+	}
+
+	// Errors wrapped with extra context still unwrap to the JJError
+	_, err = CommitIDOf(context.Background(), "/nonexistent/path", "@")
+	if !errors.As(err, &jjErr) {
+		t.Errorf("Expected CommitIDOf error to wrap *JJError, got %T", err)
+	}
+	if !errors.Is(err, jjErr.Err) {
+		t.Error("Expected JJError to unwrap to the exec error")
+	}
+}
+
+// TestErrorClassification verifies CLI and bridge errors match the sentinels
+func TestErrorClassification(t *testing.T) {
+	cliErr := &JJError{
+		Args:     []string{"edit", "zzz"},
+		ExitCode: 1,
+		Stderr:   "Error: Revision `zzz` doesn't exist",
+		Err:      errors.New("exit status 1"),
+	}
+	if !errors.Is(cliErr, ErrNotFound) {
+		t.Error("Expected missing revision to match ErrNotFound")
+	}
+	if errors.Is(cliErr, ErrImmutable) {
+		t.Error("Expected missing revision not to match ErrImmutable")
+	}
+
+	tests := []struct {
+		message string
+		want    error
+	}{
+		{"Cannot set bookmark on immutable revision (already pushed)", ErrImmutable},
+		{"Commit 1a2b3c4d5e6f is immutable", ErrImmutable},
+		{"Refusing to move bookmark backwards or sideways: main", ErrBackwards},
+		{"Revision not found: abc123", ErrNotFound},
+		{"The working copy is stale (not updated since operation 1234)", ErrStaleWorkingCopy},
+	}
+	for _, tt := range tests {
+		err := classify(errors.New(tt.message))
+		if !errors.Is(err, tt.want) {
+			t.Errorf("classify(%q) does not match %v", tt.message, tt.want)
+		}
+		if err.Error() != tt.message {
+			t.Errorf("classify(%q) changed the message to %q", tt.message, err.Error())
+		}
+	}
+
+	if classify(nil) != nil {
+		t.Error("Expected classify(nil) to be nil")
+	}
+	plain := errors.New("something else")
+	if classify(plain) != plain {
+		t.Error("Expected unclassified errors to pass through unchanged")
+	}
+}
+
+// TestRunJJCancelled verifies a cancelled context surfaces as context.Canceled
+func TestRunJJCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := runJJ(ctx, t.TempDir(), "log")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+// TestRunJJAtOperation verifies AtOperation adds --at-op without touching the caller's args
+func TestRunJJAtOperation(t *testing.T) {
+	ctx, cancel := context.WithCancel(AtOperation(context.Background(), "abc123"))
+	cancel()
+
+	args := make([]string, 1, 4)
+	args[0] = "log"
+	_, err := runJJ(ctx, t.TempDir(), args...)
+
+	var jjErr *JJError
+	if !errors.As(err, &jjErr) {
+		t.Fatalf("Expected *JJError, got %v", err)
+	}
+	if got := jjErr.Command(); got != "jj log --at-op abc123" {
+		t.Errorf("Command() = %q, want %q", got, "jj log --at-op abc123")
+	}
+	if got := args[:cap(args)][1]; got != "" {
+		t.Errorf("Expected caller's args to be left alone, got %q appended", got)
+	}
+}
+
+// TestRunJJFrozenTimestamps verifies FreezeTimestamps adds the UTC format override
+func TestRunJJFrozenTimestamps(t *testing.T) {
+	FreezeTimestamps()
+	defer frozenTimestamps.Store(false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := runJJ(ctx, t.TempDir(), "log")
+	var jjErr *JJError
+	if !errors.As(err, &jjErr) {
+		t.Fatalf("Expected *JJError, got %v", err)
+	}
+	if got := jjErr.Args; len(got) != 2 || got[1] != frozenTimestampConfig {
+		t.Errorf("Args = %q, want log followed by the timestamp override", got)
+	}
+}
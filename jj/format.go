@@ -0,0 +1,111 @@
+package jj
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SupportedJJLib is the jj-lib version the bridge is built against.
+// Keep in sync with rust/Cargo.toml.
+const SupportedJJLib = "0.36"
+
+// supportedStoreTypes lists, for each .jj store directory, the backend type
+// names the linked jj-lib can read. A repo written by a newer jj that
+// switched to a new format records a type name missing from this list.
+var supportedStoreTypes = []struct {
+	dir   string // Relative to .jj/repo, or .jj for the working copy
+	types []string
+}{
+	{"store", []string{"git", "local"}},
+	{"op_store", []string{"simple_op_store"}},
+	{"op_heads", []string{"simple_op_heads_store"}},
+	{"index", []string{"default"}},
+	{"submodule_store", []string{"default"}},
+}
+
+// supportedWorkingCopyTypes are the working copy formats the bridge can read
+var supportedWorkingCopyTypes = []string{"local"}
+
+// UnsupportedRepoError is a repo whose on-disk format is newer than the
+// linked jj-lib understands. Opening it through the bridge could corrupt it.
+type UnsupportedRepoError struct {
+	Path      string // Type file that was rejected
+	Component string // Store directory, e.g. "op_store"
+	Type      string // Unrecognised type name
+}
+
+func (e *UnsupportedRepoError) Error() string {
+	return fmt.Sprintf("repository %s format %q is not supported by jj-lib %s",
+		e.Component, e.Type, SupportedJJLib)
+}
+
+// CheckFormat verifies that the workspace at path uses only store formats
+// the linked jj-lib supports. Missing type files are accepted, since older
+// repos predate them, and a missing .jj is left for Open to report.
+func CheckFormat(path string) error {
+	dotJJ := filepath.Join(path, ".jj")
+	if _, err := os.Stat(dotJJ); err != nil {
+		return nil
+	}
+
+	if err := checkStoreType(filepath.Join(dotJJ, "working_copy"), "working_copy", supportedWorkingCopyTypes); err != nil {
+		return err
+	}
+
+	repoDir, err := resolveRepoDir(dotJJ)
+	if err != nil {
+		return err
+	}
+	for _, store := range supportedStoreTypes {
+		if err := checkStoreType(filepath.Join(repoDir, store.dir), store.dir, store.types); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveRepoDir returns the repo directory for a workspace. Secondary
+// workspaces store the path to the main repo in a .jj/repo file.
+func resolveRepoDir(dotJJ string) (string, error) {
+	repoPath := filepath.Join(dotJJ, "repo")
+	info, err := os.Stat(repoPath)
+	if err != nil {
+		return "", err
+	}
+	if info.IsDir() {
+		return repoPath, nil
+	}
+
+	target, err := os.ReadFile(repoPath)
+	if err != nil {
+		return "", err
+	}
+	resolved := strings.TrimSpace(string(target))
+	if !filepath.IsAbs(resolved) {
+		resolved = filepath.Join(dotJJ, resolved)
+	}
+	return resolved, nil
+}
+
+// checkStoreType reads dir/type and rejects names not in supported
+func checkStoreType(dir, component string, supported []string) error {
+	typePath := filepath.Join(dir, "type")
+	data, err := os.ReadFile(typePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	storeType := strings.TrimSpace(string(data))
+	for _, name := range supported {
+		if storeType == name {
+			return nil
+		}
+	}
+	return &UnsupportedRepoError{Path: typePath, Component: component, Type: storeType}
+}
@@ -0,0 +1,78 @@
+package jj
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTypeFile writes a store type file under root, creating directories
+func writeTypeFile(t *testing.T, root, dir, storeType string) {
+	t.Helper()
+	full := filepath.Join(root, dir)
+	if err := os.MkdirAll(full, 0755); err != nil {
+		t.Fatalf("failed to create %s: %v", dir, err)
+	}
+	if err := os.WriteFile(filepath.Join(full, "type"), []byte(storeType), 0644); err != nil {
+		t.Fatalf("failed to write %s/type: %v", dir, err)
+	}
+}
+
+// newFakeRepo lays out a .jj directory with the current store formats
+func newFakeRepo(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	writeTypeFile(t, root, ".jj/working_copy", "local")
+	writeTypeFile(t, root, ".jj/repo/store", "git")
+	writeTypeFile(t, root, ".jj/repo/op_store", "simple_op_store")
+	writeTypeFile(t, root, ".jj/repo/op_heads", "simple_op_heads_store")
+	writeTypeFile(t, root, ".jj/repo/index", "default")
+	return root
+}
+
+// TestCheckFormatSupported verifies current formats and missing type files pass
+func TestCheckFormatSupported(t *testing.T) {
+	root := newFakeRepo(t)
+	if err := CheckFormat(root); err != nil {
+		t.Errorf("Expected supported repo to pass, got %v", err)
+	}
+
+	// No .jj at all is left for the bridge to report
+	if err := CheckFormat(t.TempDir()); err != nil {
+		t.Errorf("Expected missing .jj to pass, got %v", err)
+	}
+}
+
+// TestCheckFormatNewer verifies an unknown store type is rejected
+func TestCheckFormatNewer(t *testing.T) {
+	root := newFakeRepo(t)
+	writeTypeFile(t, root, ".jj/repo/op_store", "simple_op_store_v2")
+
+	err := CheckFormat(root)
+	var unsupported *UnsupportedRepoError
+	if !errors.As(err, &unsupported) {
+		t.Fatalf("Expected *UnsupportedRepoError, got %v", err)
+	}
+	if unsupported.Component != "op_store" || unsupported.Type != "simple_op_store_v2" {
+		t.Errorf("Unexpected error details: %+v", unsupported)
+	}
+}
+
+// TestCheckFormatSecondaryWorkspace verifies .jj/repo pointer files are followed
+func TestCheckFormatSecondaryWorkspace(t *testing.T) {
+	main := newFakeRepo(t)
+	writeTypeFile(t, main, ".jj/repo/index", "segmented_v9")
+
+	workspace := t.TempDir()
+	writeTypeFile(t, workspace, ".jj/working_copy", "local")
+	repoDir := filepath.Join(main, ".jj", "repo")
+	if err := os.WriteFile(filepath.Join(workspace, ".jj", "repo"), []byte(repoDir), 0644); err != nil {
+		t.Fatalf("failed to write repo pointer: %v", err)
+	}
+
+	var unsupported *UnsupportedRepoError
+	if err := CheckFormat(workspace); !errors.As(err, &unsupported) || unsupported.Component != "index" {
+		t.Errorf("Expected index format error via main repo, got %v", err)
+	}
+}
@@ -6,86 +6,52 @@
 // Opaque handle to a jj repository
 typedef struct RepoHandle RepoHandle;
 
-// Result structure for FFI calls
-typedef struct {
-    char* data;   // JSON data on success, NULL on error
-    char* error;  // Error message on failure, NULL on success
-} JjResult;
+// C API version of this header and the jj_call protocol; the Go bindings
+// refuse a library reporting a different version
+uint32_t jj_bridge_api_version(void);
 
 // Open a jj repository at the given path
 // Returns NULL on error
 RepoHandle* jj_open_repo(const char* path);
 
-// List branches in the repository
-// Returns JjResult with JSON array of branch info
-JjResult jj_list_branches(RepoHandle* handle);
-
-// List workspaces in the repository
-// Returns JjResult with JSON array of workspace info
-JjResult jj_list_workspaces(RepoHandle* handle);
-
-// Get working copy file changes
-// Returns JjResult with JSON array of file change info
-JjResult jj_get_working_copy_changes(RepoHandle* handle);
-
-// List operations in the repository
-// Returns JjResult with JSON array of operation info
-JjResult jj_list_operations(RepoHandle* handle);
-
-// Get revision log
-// Returns JjResult with JSON array of revision info
-JjResult jj_get_log(RepoHandle* handle);
-
-// Get diff for working copy
-// Returns JjResult with unified diff string
-JjResult jj_get_diff(RepoHandle* handle);
-
-// Get diff for a specific file in working copy
-// Returns JjResult with unified diff string
-JjResult jj_get_file_diff(RepoHandle* handle, const char* path);
-
-// Get before/after file contents
-// Returns JjResult with JSON containing before and after content
-JjResult jj_get_file_contents(RepoHandle* handle, const char* path);
-
-// Get diff for a revision compared to its parent
-// Returns JjResult with unified diff string
-JjResult jj_get_revision_diff(RepoHandle* handle, const char* revision_id);
+/**
+ * Run an operation on a repository.
+ *
+ * The request is JSON: {"op": "<name>", "params": {...}}. The response is
+ * JSON too: {"result": ...} on success or {"error": "<message>"} on failure.
+ * Mutations have no result. Free the response with jj_free_string.
+ *
+ * Operations and their params (revision IDs are commit or change ID prefixes):
+ *   reload                                         -> no result
+ *   list_branches, list_tags, list_workspaces, list_operations, get_log,
+ *   get_working_copy_changes                       -> JSON array
+ *   get_signatures {revision_ids: [string]}        -> JSON array of {id, signed}
+ *   get_diff                                       -> unified diff string
+ *   get_file_diff {path}                           -> unified diff string
+ *   get_file_contents {path}                       -> {before, after, path}
+ *   get_revision_diff {revision_id}                -> unified diff string
+ *   set_bookmark {name, revision_id, allow_backwards, ignore_immutable}
+ *   workspace_add {destination_path, workspace_name?, revision_ids?}
+ *   workspace_forget {workspace_name}
+ *   describe {revision_id, message}
+ *   new {revision_id}
+ *   abandon {revision_ids: [string]}
+ *   squash {revision_id}
+ *
+ * Reads see the operation the handle last loaded. reload moves the handle
+ * to the repository's current operation, merging concurrent operation
+ * heads; every mutation does the same before it starts.
+ *
+ * describe, new, abandon and squash snapshot the working copy first and
+ * check out the new working-copy commit if they move it, as the jj CLI does. Their revision IDs
+ * are commit or change ID prefixes, or "@" for the working-copy commit.
+ */
+char* jj_call(RepoHandle* handle, const char* request);
 
 // Close a repository handle and free its memory
 void jj_close_repo(RepoHandle* handle);
 
-// Free a JjResult's memory
-void jj_free_result(JjResult result);
-
 // Free a string allocated by Rust
 void jj_free_string(char* s);
 
-// Set a bookmark to point to a specific revision
-// Parameters:
-// - handle: repository handle
-// - name: bookmark name
-// - revision_id: target revision ID prefix
-// - allow_backwards: if true, allow moving bookmark backwards in history
-// - ignore_immutable: if true, allow setting bookmark on immutable revisions
-// Returns JjResult with empty success or error message
-JjResult jj_set_bookmark(RepoHandle* handle, const char* name, const char* revision_id,
-                         int allow_backwards, int ignore_immutable);
-
-/**
- * Add a new workspace at the given path.
- *
- * @param handle Repository handle
- * @param destination_path Path where workspace should be created
- * @param workspace_name Name for the workspace (NULL to derive from path)
- * @param revision_ids Comma-separated revision ID prefixes for parent commits,
- *                     or NULL to use parent(s) of current workspace's working copy
- * @return JjResult with empty data on success, or error message on failure
- */
-JjResult jj_workspace_add(RepoHandle* handle, const char* destination_path, const char* workspace_name, const char* revision_ids);
-
-// Forget a workspace by name (removes tracking, keeps files on disk)
-// Returns JjResult with empty success or error message
-JjResult jj_workspace_forget(RepoHandle* handle, const char* workspace_name);
-
 #endif // JJ_BRIDGE_H
@@ -0,0 +1,114 @@
+package ffi
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/log"
+)
+
+// memoryLogLines is how many entries the in-memory log keeps
+const memoryLogLines = 500
+
+// tailBytes is how much of the end of a log file RecentLog reads
+const tailBytes = 256 * 1024
+
+// lineRing keeps the last lines written to it
+type lineRing struct {
+	mu    sync.Mutex
+	lines []string
+	size  int
+}
+
+func newLineRing(size int) *lineRing {
+	return &lineRing{size: size}
+}
+
+// Write adds each line of p, dropping the oldest beyond the ring's size
+func (r *lineRing) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		r.lines = append(r.lines, line)
+	}
+	if extra := len(r.lines) - r.size; extra > 0 {
+		r.lines = append(r.lines[:0:0], r.lines[extra:]...)
+	}
+	return len(p), nil
+}
+
+// Lines returns a copy of the kept lines, oldest first
+func (r *lineRing) Lines() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.lines...)
+}
+
+var (
+	// memoryLog holds recent entries whether or not a log file is set
+	memoryLog    = newLineRing(memoryLogLines)
+	memoryLogger = log.NewWithOptions(memoryLog, log.Options{
+		Level:           log.DebugLevel,
+		Prefix:          "FFI",
+		ReportTimestamp: true,
+	})
+	logFilePath string // Set by InitLogger
+)
+
+// emit writes an entry to the in-memory log and, if enabled, the log file
+func emit(level log.Level, msg string, keyvals ...any) {
+	memoryLogger.Log(level, msg, keyvals...)
+	if logEnabled && logger != nil {
+		logger.Log(level, msg, keyvals...)
+	}
+}
+
+// RecentLog returns up to n of the newest log lines, oldest first, and
+// where they came from: the end of the JJAZY_LOG_FILE when one is set,
+// otherwise the entries kept in memory this session ("memory").
+func RecentLog(n int) (lines []string, source string, err error) {
+	if logFilePath == "" {
+		lines = memoryLog.Lines()
+		return lines[max(len(lines)-n, 0):], "memory", nil
+	}
+	lines, err = tailFile(logFilePath, n)
+	return lines, logFilePath, err
+}
+
+// tailFile returns the last n lines of a file, reading at most tailBytes
+func tailFile(path string, n int) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	offset := max(info.Size()-tailBytes, 0)
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 {
+		// The read most likely started mid-line
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			data = data[i+1:]
+		}
+	}
+
+	text := strings.TrimRight(string(data), "\n")
+	if text == "" {
+		return nil, nil
+	}
+	lines := strings.Split(text, "\n")
+	return lines[max(len(lines)-n, 0):], nil
+}
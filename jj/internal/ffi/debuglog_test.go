@@ -0,0 +1,74 @@
+package ffi
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLineRing_KeepsNewest(t *testing.T) {
+	r := newLineRing(3)
+	for i := 1; i <= 5; i++ {
+		fmt.Fprintf(r, "line %d\n", i)
+	}
+	got := r.Lines()
+	if want := []string{"line 3", "line 4", "line 5"}; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestRecentLog_Memory(t *testing.T) {
+	resetLogger()
+
+	LogWarn("handle leaked", "path", "/repo")
+	lines, source, err := RecentLog(10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if source != "memory" {
+		t.Errorf("expected the in-memory log without a file, got %q", source)
+	}
+	if len(lines) == 0 || !strings.Contains(lines[len(lines)-1], "handle leaked") {
+		t.Errorf("expected the warning as the newest line, got %v", lines)
+	}
+}
+
+func TestRecentLog_File(t *testing.T) {
+	resetLogger()
+	defer resetLogger()
+
+	path := filepath.Join(t.TempDir(), "jjazy.log")
+	if err := os.WriteFile(path, []byte("old entry\nfirst\nsecond\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	logFilePath = path
+
+	lines, source, err := RecentLog(2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if source != path {
+		t.Errorf("expected the log file as the source, got %q", source)
+	}
+	if strings.Join(lines, ",") != "first,second" {
+		t.Errorf("expected the last two lines, got %v", lines)
+	}
+}
+
+func TestTailFile_DropsPartialLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jjazy.log")
+	long := strings.Repeat("x", tailBytes) + "\nlast\n"
+	if err := os.WriteFile(path, []byte(long), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	lines, err := tailFile(path, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(lines) != 1 || lines[0] != "last" {
+		t.Errorf("expected only the whole last line, got %d lines", len(lines))
+	}
+}
@@ -1,377 +1,132 @@
+//go:build cgo && !nobridge && (darwin || linux || windows)
+
 package ffi
 
+// The bridge is a Rust staticlib; each OS needs the system libraries Rust's
+// std and jj-lib link against (cargo rustc -- --print native-static-libs).
+// On Windows, build it for the x86_64-pc-windows-gnu target so cgo's MinGW
+// toolchain can link it.
+
 /*
 #cgo LDFLAGS: ${SRCDIR}/../../../rust/target/release/libjjbridge.a
-#cgo LDFLAGS: -framework CoreFoundation -framework Security -framework SystemConfiguration -liconv -lresolv
+#cgo darwin LDFLAGS: -framework CoreFoundation -framework Security -framework SystemConfiguration -liconv -lresolv
+#cgo linux LDFLAGS: -lpthread -ldl -lm -lrt
+#cgo windows LDFLAGS: -lws2_32 -luserenv -lbcrypt -lntdll -ladvapi32 -lkernel32 -lcrypt32 -lsecur32 -lole32
 #include "bridge.h"
 #include <stdlib.h>
 */
 import "C"
 import (
+	"encoding/json"
 	"errors"
-	"strings"
+	"fmt"
 	"unsafe"
 )
 
 // RepoPtr is an opaque pointer to a Rust repository handle
 type RepoPtr unsafe.Pointer
 
-// OpenRepo opens a jj repository at the given path
-// Returns nil and an error if the repo cannot be opened
-func OpenRepo(path string) (RepoPtr, error) {
-	done := logOp("OpenRepo", "path", truncate(path, 100))
-
-	cpath := C.CString(path)
-	defer C.free(unsafe.Pointer(cpath))
+// APIVersion is the bridge.h API these bindings were written against.
+// Bump together with BRIDGE_API_VERSION in rust/src/lib.rs.
+const APIVersion = 7
 
-	handle := C.jj_open_repo(cpath)
-	if handle == nil {
-		err := errors.New("failed to open repository")
-		done(err)
-		return nil, err
-	}
-	done(nil)
-	return RepoPtr(handle), nil
+// CheckAPIVersion verifies the linked libjjbridge matches these bindings.
+// A mismatch means the Rust library is stale and calls could misbehave.
+func CheckAPIVersion() error {
+	return checkAPIVersion(uint32(C.jj_bridge_api_version()))
 }
 
-// ListBranches returns JSON-encoded branch data from the repository
-func ListBranches(repo RepoPtr) ([]byte, error) {
-	done := logOpWithResult("ListBranches")
-
-	result := C.jj_list_branches((*C.RepoHandle)(repo))
-	defer C.jj_free_result(result)
-
-	if result.error != nil {
-		errMsg := C.GoString(result.error)
-		err := errors.New(errMsg)
-		done(err)
-		return nil, err
-	}
-
-	if result.data == nil {
-		err := errors.New("no data returned")
-		done(err)
-		return nil, err
+// checkAPIVersion compares a bridge-reported version against APIVersion
+func checkAPIVersion(got uint32) error {
+	if got != APIVersion {
+		return fmt.Errorf("libjjbridge API version %d does not match jjazy's bindings (version %d); rebuild with `make rust`", got, APIVersion)
 	}
-
-	data := []byte(C.GoString(result.data))
-	done(nil, "bytes", len(data))
-	return data, nil
+	return nil
 }
 
-// ListWorkspaces returns JSON-encoded workspace data from the repository
-func ListWorkspaces(repo RepoPtr) ([]byte, error) {
-	done := logOpWithResult("ListWorkspaces")
-
-	result := C.jj_list_workspaces((*C.RepoHandle)(repo))
-	defer C.jj_free_result(result)
-
-	if result.error != nil {
-		errMsg := C.GoString(result.error)
-		err := errors.New(errMsg)
-		done(err)
-		return nil, err
-	}
+// OpenRepo opens a jj repository at the given path
+// Returns nil and an error if the repo cannot be opened
+func OpenRepo(path string) (RepoPtr, error) {
+	done := logOp("open_repo", "path", truncate(path, 100))
 
-	if result.data == nil {
-		err := errors.New("no data returned")
+	if err := CheckAPIVersion(); err != nil {
 		done(err)
 		return nil, err
 	}
 
-	data := []byte(C.GoString(result.data))
-	done(nil, "bytes", len(data))
-	return data, nil
-}
-
-// GetWorkingCopyChanges returns JSON-encoded file change data from the repository
-func GetWorkingCopyChanges(repo RepoPtr) ([]byte, error) {
-	done := logOpWithResult("GetWorkingCopyChanges")
-
-	result := C.jj_get_working_copy_changes((*C.RepoHandle)(repo))
-	defer C.jj_free_result(result)
-
-	if result.error != nil {
-		errMsg := C.GoString(result.error)
-		err := errors.New(errMsg)
-		done(err)
-		return nil, err
-	}
+	cpath := C.CString(path)
+	defer C.free(unsafe.Pointer(cpath))
 
-	if result.data == nil {
-		err := errors.New("no data returned")
+	handle := C.jj_open_repo(cpath)
+	if handle == nil {
+		err := errors.New("failed to open repository")
 		done(err)
 		return nil, err
 	}
-
-	data := []byte(C.GoString(result.data))
-	done(nil, "bytes", len(data))
-	return data, nil
+	done(nil)
+	return RepoPtr(handle), nil
 }
 
-// ListOperations returns JSON-encoded operation data from the repository
-func ListOperations(repo RepoPtr) ([]byte, error) {
-	done := logOpWithResult("ListOperations")
-
-	result := C.jj_list_operations((*C.RepoHandle)(repo))
-	defer C.jj_free_result(result)
-
-	if result.error != nil {
-		errMsg := C.GoString(result.error)
-		err := errors.New(errMsg)
-		done(err)
-		return nil, err
-	}
-
-	if result.data == nil {
-		err := errors.New("no data returned")
-		done(err)
-		return nil, err
-	}
-
-	data := []byte(C.GoString(result.data))
-	done(nil, "bytes", len(data))
-	return data, nil
+// request is what jj_call takes: an operation name and its parameters
+type request struct {
+	Op     string `json:"op"`
+	Params any    `json:"params,omitempty"`
 }
 
-// GetLog returns JSON-encoded revision log data from the repository
-func GetLog(repo RepoPtr) ([]byte, error) {
-	done := logOpWithResult("GetLog")
-
-	result := C.jj_get_log((*C.RepoHandle)(repo))
-	defer C.jj_free_result(result)
-
-	if result.error != nil {
-		errMsg := C.GoString(result.error)
-		err := errors.New(errMsg)
-		done(err)
-		return nil, err
-	}
-
-	if result.data == nil {
-		err := errors.New("no data returned")
-		done(err)
-		return nil, err
-	}
-
-	data := []byte(C.GoString(result.data))
-	done(nil, "bytes", len(data))
-	return data, nil
+// response is what jj_call returns: a result on success, an error on failure
+type response struct {
+	Result json.RawMessage `json:"result"`
+	Error  *string         `json:"error"`
 }
 
-// GetDiff returns the unified diff for the working copy
-func GetDiff(repo RepoPtr) (string, error) {
-	done := logOpWithResult("GetDiff")
-
-	result := C.jj_get_diff((*C.RepoHandle)(repo))
-	defer C.jj_free_result(result)
-
-	if result.error != nil {
-		errMsg := C.GoString(result.error)
-		err := errors.New(errMsg)
-		done(err)
-		return "", err
-	}
-
-	if result.data == nil {
-		done(nil, "bytes", 0)
-		return "", nil
+// Call runs a bridge operation (see bridge.h for the ops and their
+// params). params is encoded as the request's JSON params; on success the
+// result is decoded into result, unless result is nil.
+func Call(repo RepoPtr, op string, params, result any) error {
+	req, err := json.Marshal(request{Op: op, Params: params})
+	if err != nil {
+		return err
 	}
+	done := logOpWithResult(op, "params", truncate(string(req), 200))
 
-	data := C.GoString(result.data)
-	done(nil, "bytes", len(data))
-	return data, nil
-}
+	creq := C.CString(string(req))
+	defer C.free(unsafe.Pointer(creq))
 
-// GetFileDiff returns the unified diff for a specific file in the working copy
-func GetFileDiff(repo RepoPtr, path string) (string, error) {
-	done := logOpWithResult("GetFileDiff", "path", truncate(path, 100))
-
-	cpath := C.CString(path)
-	defer C.free(unsafe.Pointer(cpath))
-
-	result := C.jj_get_file_diff((*C.RepoHandle)(repo), cpath)
-	defer C.jj_free_result(result)
-
-	if result.error != nil {
-		errMsg := C.GoString(result.error)
-		err := errors.New(errMsg)
+	cresp := C.jj_call((*C.RepoHandle)(repo), creq)
+	if cresp == nil {
+		err := errors.New("no response returned")
 		done(err)
-		return "", err
-	}
-
-	if result.data == nil {
-		done(nil, "bytes", 0)
-		return "", nil
+		return err
 	}
+	defer C.jj_free_string(cresp)
 
-	data := C.GoString(result.data)
-	done(nil, "bytes", len(data))
-	return data, nil
+	resp := C.GoString(cresp)
+	err = decodeResponse([]byte(resp), result)
+	done(err, "bytes", len(resp))
+	return err
 }
 
-// GetFileContents returns JSON-encoded before/after file contents
-func GetFileContents(repo RepoPtr, path string) ([]byte, error) {
-	done := logOpWithResult("GetFileContents", "path", truncate(path, 100))
-
-	cpath := C.CString(path)
-	defer C.free(unsafe.Pointer(cpath))
-
-	result := C.jj_get_file_contents((*C.RepoHandle)(repo), cpath)
-	defer C.jj_free_result(result)
-
-	if result.error != nil {
-		errMsg := C.GoString(result.error)
-		err := errors.New(errMsg)
-		done(err)
-		return nil, err
-	}
-
-	if result.data == nil {
-		err := errors.New("no data returned")
-		done(err)
-		return nil, err
+// decodeResponse decodes a jj_call response into result
+func decodeResponse(data []byte, result any) error {
+	var resp response
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return fmt.Errorf("invalid bridge response: %w", err)
 	}
-
-	data := []byte(C.GoString(result.data))
-	done(nil, "bytes", len(data))
-	return data, nil
-}
-
-// GetRevisionDiff returns the unified diff for a revision compared to its parent
-func GetRevisionDiff(repo RepoPtr, revisionID string) (string, error) {
-	done := logOpWithResult("GetRevisionDiff", "revision", truncate(revisionID, 12))
-
-	crevID := C.CString(revisionID)
-	defer C.free(unsafe.Pointer(crevID))
-
-	result := C.jj_get_revision_diff((*C.RepoHandle)(repo), crevID)
-	defer C.jj_free_result(result)
-
-	if result.error != nil {
-		errMsg := C.GoString(result.error)
-		err := errors.New(errMsg)
-		done(err)
-		return "", err
+	if resp.Error != nil {
+		return errors.New(*resp.Error)
 	}
-
-	if result.data == nil {
-		done(nil, "bytes", 0)
-		return "", nil
+	if result == nil || len(resp.Result) == 0 {
+		return nil
 	}
-
-	data := C.GoString(result.data)
-	done(nil, "bytes", len(data))
-	return data, nil
+	return json.Unmarshal(resp.Result, result)
 }
 
 // CloseRepo closes a repository handle
 func CloseRepo(repo RepoPtr) {
-	done := logOp("CloseRepo")
+	done := logOp("close_repo")
 
 	if repo != nil {
 		C.jj_close_repo((*C.RepoHandle)(repo))
 	}
 	done(nil)
 }
-
-// SetBookmark sets a bookmark to point to a specific revision
-func SetBookmark(repo RepoPtr, name, revisionID string, allowBackwards, ignoreImmutable bool) error {
-	done := logOp("SetBookmark",
-		"name", truncate(name, 50),
-		"revision", truncate(revisionID, 12),
-		"allowBackwards", allowBackwards,
-		"ignoreImmutable", ignoreImmutable,
-	)
-
-	cname := C.CString(name)
-	defer C.free(unsafe.Pointer(cname))
-
-	crevID := C.CString(revisionID)
-	defer C.free(unsafe.Pointer(crevID))
-
-	var allowBackwardsInt, ignoreImmutableInt C.int
-	if allowBackwards {
-		allowBackwardsInt = 1
-	}
-	if ignoreImmutable {
-		ignoreImmutableInt = 1
-	}
-
-	result := C.jj_set_bookmark((*C.RepoHandle)(repo), cname, crevID, allowBackwardsInt, ignoreImmutableInt)
-	defer C.jj_free_result(result)
-
-	if result.error != nil {
-		errMsg := C.GoString(result.error)
-		err := errors.New(errMsg)
-		done(err)
-		return err
-	}
-
-	done(nil)
-	return nil
-}
-
-// WorkspaceAdd creates a new workspace at the given path.
-// revisionIDs specifies parent commits for the new workspace's working copy.
-// If empty, defaults to parent(s) of current workspace's working copy.
-func WorkspaceAdd(repo RepoPtr, destinationPath, workspaceName string, revisionIDs []string) error {
-	done := logOp("WorkspaceAdd",
-		"destinationPath", truncate(destinationPath, 100),
-		"workspaceName", truncate(workspaceName, 50),
-		"revisionIDs", revisionIDs,
-	)
-
-	cDestPath := C.CString(destinationPath)
-	defer C.free(unsafe.Pointer(cDestPath))
-
-	var cWsName *C.char
-	if workspaceName != "" {
-		cWsName = C.CString(workspaceName)
-		defer C.free(unsafe.Pointer(cWsName))
-	}
-
-	// Convert revision IDs to comma-separated string
-	var cRevIDs *C.char
-	if len(revisionIDs) > 0 {
-		revIDStr := strings.Join(revisionIDs, ",")
-		cRevIDs = C.CString(revIDStr)
-		defer C.free(unsafe.Pointer(cRevIDs))
-	}
-
-	result := C.jj_workspace_add((*C.RepoHandle)(repo), cDestPath, cWsName, cRevIDs)
-	defer C.jj_free_result(result)
-
-	if result.error != nil {
-		errMsg := C.GoString(result.error)
-		err := errors.New(errMsg)
-		done(err)
-		return err
-	}
-
-	done(nil)
-	return nil
-}
-
-// WorkspaceForget removes workspace tracking (keeps files on disk)
-func WorkspaceForget(repo RepoPtr, workspaceName string) error {
-	done := logOp("WorkspaceForget",
-		"workspaceName", truncate(workspaceName, 50),
-	)
-
-	cWsName := C.CString(workspaceName)
-	defer C.free(unsafe.Pointer(cWsName))
-
-	result := C.jj_workspace_forget((*C.RepoHandle)(repo), cWsName)
-	defer C.jj_free_result(result)
-
-	if result.error != nil {
-		errMsg := C.GoString(result.error)
-		err := errors.New(errMsg)
-		done(err)
-		return err
-	}
-
-	done(nil)
-	return nil
-}
@@ -0,0 +1,54 @@
+//go:build cgo && !nobridge && (darwin || linux || windows)
+
+package ffi
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckAPIVersion_Match(t *testing.T) {
+	if err := checkAPIVersion(APIVersion); err != nil {
+		t.Errorf("expected matching version to pass, got %v", err)
+	}
+}
+
+func TestCheckAPIVersion_Mismatch(t *testing.T) {
+	err := checkAPIVersion(APIVersion + 1)
+	if err == nil {
+		t.Fatal("expected mismatched version to fail")
+	}
+	if !strings.Contains(err.Error(), "rebuild") {
+		t.Errorf("expected error to explain how to fix it, got %q", err)
+	}
+}
+
+func TestDecodeResponse_Result(t *testing.T) {
+	var names []string
+	if err := decodeResponse([]byte(`{"result":["main","dev"]}`), &names); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(names) != 2 || names[0] != "main" || names[1] != "dev" {
+		t.Errorf("expected [main dev], got %v", names)
+	}
+}
+
+func TestDecodeResponse_Error(t *testing.T) {
+	var names []string
+	err := decodeResponse([]byte(`{"error":"revision is immutable"}`), &names)
+	if err == nil || err.Error() != "revision is immutable" {
+		t.Errorf("expected the bridge's error, got %v", err)
+	}
+}
+
+func TestDecodeResponse_NoResult(t *testing.T) {
+	if err := decodeResponse([]byte(`{}`), nil); err != nil {
+		t.Errorf("expected a mutation's empty response to succeed, got %v", err)
+	}
+}
+
+func TestDecodeResponse_Invalid(t *testing.T) {
+	if err := decodeResponse([]byte(`not json`), nil); err == nil {
+		t.Error("expected an invalid response to fail")
+	}
+}
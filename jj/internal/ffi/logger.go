@@ -65,6 +65,7 @@ func InitLogger(logPath string, level log.Level) error {
 			ReportCaller:    false,
 		})
 		logEnabled = true
+		logFilePath = logPath
 	})
 	return initErr
 }
@@ -75,34 +76,18 @@ func SetLogger(l *log.Logger) {
 	logEnabled = l != nil
 }
 
-// logOp creates a logging context for an operation.
+// logOp times an operation, recording its latency in the metrics and
+// logging it to the in-memory log and, if enabled, the log file.
 // Returns a function that should be called when the operation completes.
 //
 // Usage:
 //
-//	done := logOp("OpenRepo", "path", path)
+//	done := logOp("open_repo", "path", path)
 //	defer done(nil) // or done(err) on error
 func logOp(op string, keyvals ...any) func(error) {
-	if !logEnabled || logger == nil {
-		return func(error) {}
-	}
-
-	start := time.Now()
+	done := logOpWithResult(op, keyvals...)
 	return func(err error) {
-		duration := time.Since(start)
-
-		// Build args: operation name, duration, then provided keyvals
-		args := make([]any, 0, len(keyvals)+4)
-		args = append(args, "op", op)
-		args = append(args, "duration", duration.String())
-		args = append(args, keyvals...)
-
-		if err != nil {
-			args = append(args, "error", err.Error())
-			logger.Error("operation failed", args...)
-		} else {
-			logger.Info("operation complete", args...)
-		}
+		done(err)
 	}
 }
 
@@ -110,17 +95,14 @@ func logOp(op string, keyvals ...any) func(error) {
 //
 // Usage:
 //
-//	done := logOpWithResult("ListBranches")
+//	done := logOpWithResult("list_branches")
 //	// ... operation ...
 //	done(nil, "count", len(branches))
 func logOpWithResult(op string, keyvals ...any) func(error, ...any) {
-	if !logEnabled || logger == nil {
-		return func(error, ...any) {}
-	}
-
 	start := time.Now()
 	return func(err error, resultKeyvals ...any) {
 		duration := time.Since(start)
+		record(op, duration)
 
 		args := make([]any, 0, len(keyvals)+len(resultKeyvals)+4)
 		args = append(args, "op", op)
@@ -130,9 +112,9 @@ func logOpWithResult(op string, keyvals ...any) func(error, ...any) {
 
 		if err != nil {
 			args = append(args, "error", err.Error())
-			logger.Error("operation failed", args...)
+			emit(log.ErrorLevel, "operation failed", args...)
 		} else {
-			logger.Info("operation complete", args...)
+			emit(log.InfoLevel, "operation complete", args...)
 		}
 	}
 }
@@ -144,3 +126,8 @@ func truncate(s string, maxLen int) string {
 	}
 	return s[:maxLen] + "..."
 }
+
+// LogWarn logs a warning outside of an FFI operation, e.g. a leaked handle.
+func LogWarn(msg string, keyvals ...any) {
+	emit(log.WarnLevel, msg, keyvals...)
+}
@@ -229,6 +229,7 @@ func resetLogger() {
 	loggerOnce = sync.Once{}
 	logger = nil
 	logEnabled = false
+	logFilePath = ""
 }
 
 func setupTestLogger(t *testing.T) *bytes.Buffer {
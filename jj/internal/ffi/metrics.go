@@ -0,0 +1,107 @@
+package ffi
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// LatencyBounds are the upper bounds of the latency histogram's buckets.
+// A last bucket counts everything slower.
+var LatencyBounds = []time.Duration{
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	time.Second,
+}
+
+// OpStats is the latency of one bridge operation over the session
+type OpStats struct {
+	Op      string
+	Count   int
+	Total   time.Duration
+	Max     time.Duration
+	Buckets []int // Counts per LatencyBounds bucket, plus one for slower
+}
+
+// Mean returns the average latency, or zero before the first call
+func (s OpStats) Mean() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Total / time.Duration(s.Count)
+}
+
+// SlowOp is a bridge call that took at least the slow threshold
+type SlowOp struct {
+	Op       string
+	Duration time.Duration
+}
+
+var (
+	metricsMu     sync.Mutex
+	opStats       = make(map[string]*OpStats)
+	slowThreshold time.Duration
+	// slowOps is buffered so recording never blocks; warnings nobody is
+	// reading yet are dropped once it fills
+	slowOps = make(chan SlowOp, 16)
+)
+
+// record adds a call's latency to its operation's histogram
+func record(op string, d time.Duration) {
+	metricsMu.Lock()
+	s, ok := opStats[op]
+	if !ok {
+		s = &OpStats{Op: op, Buckets: make([]int, len(LatencyBounds)+1)}
+		opStats[op] = s
+	}
+	s.Count++
+	s.Total += d
+	s.Max = max(s.Max, d)
+	bucket := sort.Search(len(LatencyBounds), func(i int) bool { return d < LatencyBounds[i] })
+	s.Buckets[bucket]++
+	threshold := slowThreshold
+	metricsMu.Unlock()
+
+	if threshold > 0 && d >= threshold {
+		select {
+		case slowOps <- SlowOp{Op: op, Duration: d}:
+		default:
+		}
+	}
+}
+
+// Metrics returns every operation's latency so far, sorted by name
+func Metrics() []OpStats {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+
+	stats := make([]OpStats, 0, len(opStats))
+	for _, s := range opStats {
+		copied := *s
+		copied.Buckets = append([]int(nil), s.Buckets...)
+		stats = append(stats, copied)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Op < stats[j].Op })
+	return stats
+}
+
+// SetSlowThreshold sets how long a call can take before it's reported on
+// SlowOps. Zero turns the reports off.
+func SetSlowThreshold(d time.Duration) {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	slowThreshold = d
+}
+
+// SlowOps delivers calls that took at least the slow threshold
+func SlowOps() <-chan SlowOp {
+	return slowOps
+}
+
+// resetMetrics clears the recorded latencies, for tests
+func resetMetrics() {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	opStats = make(map[string]*OpStats)
+}
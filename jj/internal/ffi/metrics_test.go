@@ -0,0 +1,79 @@
+package ffi
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecord_Histogram(t *testing.T) {
+	resetMetrics()
+	defer resetMetrics()
+
+	record("get_log", 500*time.Microsecond)
+	record("get_log", 50*time.Millisecond)
+	record("get_log", 2*time.Second)
+	record("list_tags", time.Millisecond)
+
+	stats := Metrics()
+	if len(stats) != 2 || stats[0].Op != "get_log" || stats[1].Op != "list_tags" {
+		t.Fatalf("expected get_log and list_tags sorted by name, got %+v", stats)
+	}
+	log := stats[0]
+	if log.Count != 3 || log.Max != 2*time.Second {
+		t.Errorf("expected 3 calls with a 2s max, got %d and %v", log.Count, log.Max)
+	}
+	if want := []int{1, 0, 1, 0, 1}; !equalInts(log.Buckets, want) {
+		t.Errorf("expected buckets %v, got %v", want, log.Buckets)
+	}
+	// A bucket's bound is exclusive, so exactly 1ms is in the second bucket
+	if want := []int{0, 1, 0, 0, 0}; !equalInts(stats[1].Buckets, want) {
+		t.Errorf("expected buckets %v, got %v", want, stats[1].Buckets)
+	}
+}
+
+func TestMetrics_IsACopy(t *testing.T) {
+	resetMetrics()
+	defer resetMetrics()
+
+	record("get_diff", time.Millisecond)
+	Metrics()[0].Buckets[1] = 99
+	if got := Metrics()[0].Buckets[1]; got != 1 {
+		t.Errorf("expected the snapshot not to share buckets, got %d", got)
+	}
+}
+
+func TestRecord_SlowOps(t *testing.T) {
+	resetMetrics()
+	defer resetMetrics()
+	SetSlowThreshold(100 * time.Millisecond)
+	defer SetSlowThreshold(0)
+
+	record("get_diff", 10*time.Millisecond)
+	record("get_log", 300*time.Millisecond)
+
+	select {
+	case op := <-SlowOps():
+		if op.Op != "get_log" || op.Duration != 300*time.Millisecond {
+			t.Errorf("expected get_log at 300ms, got %+v", op)
+		}
+	default:
+		t.Fatal("expected the slow call to be reported")
+	}
+	select {
+	case op := <-SlowOps():
+		t.Errorf("expected only the slow call to be reported, got %+v", op)
+	default:
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -1,5 +1,6 @@
 // Package jj provides a Go interface to jj (Jujutsu) repositories.
-// This package wraps the jj-lib Rust library via CGO/FFI.
+// This package wraps the jj-lib Rust library via CGO/FFI, or the jj binary
+// in builds without the bridge (see backend.go).
 // All FFI details are hidden - consumers of this package interact
 // with pure Go types.
 package jj
@@ -8,6 +9,19 @@ package jj
 type Branch struct {
 	Name    string `json:"name"`
 	IsLocal bool   `json:"is_local"`
+
+	// Remote is the remote whose bookmark this one tracks, empty when it
+	// tracks none. Ahead counts the commits not pushed there yet, Behind
+	// those on the remote not here.
+	Remote string `json:"remote"`
+	Ahead  int    `json:"ahead"`
+	Behind int    `json:"behind"`
+}
+
+// Tag represents a local tag in a jj repository.
+type Tag struct {
+	Name     string `json:"name"`
+	CommitID string `json:"commit_id"`
 }
 
 // Workspace represents a jj workspace.
@@ -47,9 +61,21 @@ type Revision struct {
 	Author        string   `json:"author"`
 	Timestamp     string   `json:"timestamp"`
 	Bookmarks     []string `json:"bookmarks"`
+	Tags          []string `json:"tags"`
 	GitHead       bool     `json:"git_head"`
 	IsWorkingCopy bool     `json:"is_working_copy"`
 	WorkspaceName *string  `json:"workspace_name"`
 	IsRoot        bool     `json:"is_root"`
 	Parents       []string `json:"parents"`
+	Divergent     bool     `json:"divergent"` // The change has more than one visible commit
+	Hidden        bool     `json:"hidden"`    // The commit is no longer visible (rewritten or abandoned)
+	Immutable     bool     `json:"immutable"` // The commit is pushed or the root, so can't be rewritten
+	Signed        bool     `json:"signed"`    // The commit carries a cryptographic signature
+}
+
+// Signature reports whether a commit is signed. ID is the commit ID
+// prefix it was asked for.
+type Signature struct {
+	ID     string `json:"id"`
+	Signed bool   `json:"signed"`
 }
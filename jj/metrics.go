@@ -0,0 +1,64 @@
+package jj
+
+import (
+	"time"
+
+	"github.com/gerunddev/jjazy/jj/internal/ffi"
+)
+
+// DefaultSlowThreshold is how long a bridge call can take before it counts
+// as slow, unless SetSlowThreshold says otherwise
+const DefaultSlowThreshold = 500 * time.Millisecond
+
+func init() {
+	ffi.SetSlowThreshold(DefaultSlowThreshold)
+}
+
+// OpLatency is how long one bridge operation has taken this session
+type OpLatency struct {
+	Op      string
+	Count   int
+	Mean    time.Duration
+	Max     time.Duration
+	Buckets []int // Calls per LatencyBuckets range
+}
+
+// LatencyBuckets labels the ranges OpLatency.Buckets counts calls in,
+// e.g. "<10ms"
+func LatencyBuckets() []string {
+	labels := make([]string, 0, len(ffi.LatencyBounds)+1)
+	for _, bound := range ffi.LatencyBounds {
+		labels = append(labels, "<"+bound.String())
+	}
+	return append(labels, "≥"+ffi.LatencyBounds[len(ffi.LatencyBounds)-1].String())
+}
+
+// BridgeLatency returns the latency of every bridge operation called so
+// far, sorted by name. Builds without the bridge have none.
+func BridgeLatency() []OpLatency {
+	stats := ffi.Metrics()
+	latencies := make([]OpLatency, len(stats))
+	for i, s := range stats {
+		latencies[i] = OpLatency{Op: s.Op, Count: s.Count, Mean: s.Mean(), Max: s.Max, Buckets: s.Buckets}
+	}
+	return latencies
+}
+
+// SlowCall is a bridge call that took at least the slow threshold
+type SlowCall struct {
+	Op       string
+	Duration time.Duration
+}
+
+// SetSlowThreshold sets how long a bridge call can take before
+// WaitForSlowCall reports it. Zero or less turns the reports off.
+func SetSlowThreshold(d time.Duration) {
+	ffi.SetSlowThreshold(max(d, 0))
+}
+
+// WaitForSlowCall blocks until a bridge call takes at least the slow
+// threshold, then returns it
+func WaitForSlowCall() SlowCall {
+	op := <-ffi.SlowOps()
+	return SlowCall{Op: op.Op, Duration: op.Duration}
+}
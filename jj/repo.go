@@ -1,129 +1,221 @@
 package jj
 
 import (
-	"encoding/json"
-
-	"github.com/gerunddev/jjazy/jj/internal/ffi"
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
 )
 
-// Repo represents an open jj repository.
+// ErrClosed is returned by Repo methods called after Close.
+var ErrClosed = errors.New("repository is closed")
+
+// Repo represents an open jj repository. It is safe for concurrent use:
+// reads run in parallel, while Reload, mutations and Close each have the
+// repository to themselves. Callers must Close it; a Repo collected while
+// still open is logged as a leak.
 type Repo struct {
-	ptr ffi.RepoPtr
+	mu      sync.RWMutex // Held for reading during reads, for writing by Reload, mutations, reopen and Close
+	backend backend
+	path    string
+}
+
+// openRepos counts Repo handles that have been opened but not closed
+var openRepos atomic.Int64
+
+// OpenRepos returns the number of Repo handles currently open.
+// Tests use it to check that code paths close every repo they open.
+func OpenRepos() int {
+	return int(openRepos.Load())
+}
+
+// finalizeRepo frees a Repo that was garbage-collected without Close
+func finalizeRepo(r *Repo) {
+	if r.backend == nil {
+		return
+	}
+	logWarn("jj.Repo garbage-collected without Close", "path", r.path)
+	r.Close()
 }
 
 // Open opens a jj repository at the given path.
+// Repos written in a newer format return an *UnsupportedRepoError.
 func Open(path string) (*Repo, error) {
-	ptr, err := ffi.OpenRepo(path)
-	if err != nil {
+	if err := CheckFormat(path); err != nil {
 		return nil, err
 	}
-	return &Repo{ptr: ptr}, nil
-}
-
-// Branches returns a list of branches (bookmarks) in the repository.
-func (r *Repo) Branches() ([]Branch, error) {
-	data, err := ffi.ListBranches(r.ptr)
+	b, err := openBackend(path)
 	if err != nil {
 		return nil, err
 	}
+	r := &Repo{backend: b, path: path}
+	openRepos.Add(1)
+	runtime.SetFinalizer(r, finalizeRepo)
+	return r, nil
+}
 
-	var branches []Branch
-	if err := json.Unmarshal(data, &branches); err != nil {
-		return nil, err
+// useExclusive runs fn with the backend under the write lock. The bridge
+// moves its handle to a new operation on Reload and on every mutation, so
+// these never overlap a read or each other. Returns ErrClosed once the
+// repo (or a nil *Repo) is closed.
+func (r *Repo) useExclusive(fn func(b backend) error) error {
+	if r == nil {
+		return ErrClosed
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.backend == nil {
+		return ErrClosed
 	}
-	return branches, nil
+	return fn(r.backend)
 }
 
-// Workspaces returns a list of workspaces in the repository.
-func (r *Repo) Workspaces() ([]Workspace, error) {
-	data, err := ffi.ListWorkspaces(r.ptr)
-	if err != nil {
-		return nil, err
+// read runs fn with the backend, holding the read lock so Close can't free
+// it mid-call; reads run in parallel with each other. A bridge handle
+// whose operation was abandoned or garbage-collected elsewhere fails to
+// find what it's asked for, so when fn fails the repository is reopened
+// and fn retried once. Mutations are never retried, and the CLI backend
+// has nothing to go stale.
+func (r *Repo) read(fn func(b backend) error) error {
+	b, err := r.call(fn)
+	if err == nil || !backendGoesStale || errors.Is(err, ErrClosed) || !r.reopen(b) {
+		return err
 	}
+	_, err = r.call(fn)
+	return err
+}
 
-	var workspaces []Workspace
-	if err := json.Unmarshal(data, &workspaces); err != nil {
-		return nil, err
+// call runs fn under the read lock and returns the backend it was given
+func (r *Repo) call(fn func(b backend) error) (backend, error) {
+	if r == nil {
+		return nil, ErrClosed
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.backend == nil {
+		return nil, ErrClosed
 	}
-	return workspaces, nil
+	return r.backend, fn(r.backend)
 }
 
-// WorkingCopyChanges returns a list of changed files in the working copy.
-func (r *Repo) WorkingCopyChanges() ([]FileChange, error) {
-	data, err := ffi.GetWorkingCopyChanges(r.ptr)
+// reopen replaces the backend stale with a freshly opened one, unless a
+// concurrent caller already has. Reports whether there is a new backend
+// to retry with: false if the repo was closed or couldn't be reopened.
+func (r *Repo) reopen(stale backend) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.backend == nil {
+		return false
+	}
+	if r.backend != stale {
+		return true
+	}
+	b, err := openBackend(r.path)
 	if err != nil {
-		return nil, err
+		logWarn("reopening jj.Repo failed", "path", r.path, "error", err)
+		return false
 	}
+	r.backend.close()
+	r.backend = b
+	return true
+}
 
-	var changes []FileChange
-	if err := json.Unmarshal(data, &changes); err != nil {
-		return nil, err
-	}
-	return changes, nil
+// Reload brings the repository up to jj's current operation. The bridge
+// reads from the operation it last loaded, so after jj runs elsewhere (a
+// CLI wrapper, the user's shell) reads are stale until Reload. Mutations
+// reload on their own before they start.
+func (r *Repo) Reload() error {
+	return r.useExclusive(backend.reload)
+}
+
+// query runs a read on the backend
+func query[T any](r *Repo, fetch func(b backend) (T, error)) (T, error) {
+	var result T
+	err := r.read(func(b backend) (err error) {
+		result, err = fetch(b)
+		return err
+	})
+	return result, err
+}
+
+// Branches returns a list of branches (bookmarks) in the repository.
+func (r *Repo) Branches() ([]Branch, error) {
+	return query(r, backend.branches)
+}
+
+// Tags returns the local tags in the repository.
+func (r *Repo) Tags() ([]Tag, error) {
+	return query(r, backend.tags)
+}
+
+// Workspaces returns a list of workspaces in the repository.
+func (r *Repo) Workspaces() ([]Workspace, error) {
+	return query(r, backend.workspaces)
+}
+
+// WorkingCopyChanges returns a list of changed files in the working copy.
+func (r *Repo) WorkingCopyChanges() ([]FileChange, error) {
+	return query(r, backend.workingCopyChanges)
 }
 
 // Operations returns a list of operations in the undo history.
 func (r *Repo) Operations() ([]Operation, error) {
-	data, err := ffi.ListOperations(r.ptr)
-	if err != nil {
-		return nil, err
-	}
-
-	var operations []Operation
-	if err := json.Unmarshal(data, &operations); err != nil {
-		return nil, err
-	}
-	return operations, nil
+	return query(r, backend.operations)
 }
 
 // Log returns the revision log.
 func (r *Repo) Log() ([]Revision, error) {
-	data, err := ffi.GetLog(r.ptr)
-	if err != nil {
-		return nil, err
-	}
+	return query(r, backend.log)
+}
 
-	var revisions []Revision
-	if err := json.Unmarshal(data, &revisions); err != nil {
-		return nil, err
-	}
-	return revisions, nil
+// Signatures reports which of the given commits are signed. Commits the
+// backend can't find are left out.
+func (r *Repo) Signatures(commitIDs []string) ([]Signature, error) {
+	return query(r, func(b backend) ([]Signature, error) {
+		return b.signatures(commitIDs)
+	})
 }
 
 // Diff returns the unified diff for the working copy.
 func (r *Repo) Diff() (string, error) {
-	return ffi.GetDiff(r.ptr)
+	return query(r, backend.diff)
 }
 
 // FileDiff returns the unified diff for a specific file in the working copy.
 func (r *Repo) FileDiff(path string) (string, error) {
-	return ffi.GetFileDiff(r.ptr, path)
+	return query(r, func(b backend) (string, error) {
+		return b.fileDiff(path)
+	})
 }
 
 // FileContents returns the before/after contents of a specific file.
 func (r *Repo) FileContents(path string) (*FileContents, error) {
-	data, err := ffi.GetFileContents(r.ptr, path)
+	contents, err := query(r, func(b backend) (FileContents, error) {
+		return b.fileContents(path)
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	var contents FileContents
-	if err := json.Unmarshal(data, &contents); err != nil {
-		return nil, err
-	}
 	return &contents, nil
 }
 
 // RevisionDiff returns the unified diff for a revision compared to its parent.
 func (r *Repo) RevisionDiff(revisionID string) (string, error) {
-	return ffi.GetRevisionDiff(r.ptr, revisionID)
+	diff, err := query(r, func(b backend) (string, error) {
+		return b.revisionDiff(revisionID)
+	})
+	return diff, classify(err)
 }
 
 // SetBookmark sets a bookmark to point to a specific revision.
 // If allowBackwards is true, the bookmark can be moved to an ancestor.
 // If ignoreImmutable is true, the bookmark can be set on immutable revisions.
+// Refusals match ErrBackwards or ErrImmutable.
 func (r *Repo) SetBookmark(name, revisionID string, allowBackwards, ignoreImmutable bool) error {
-	return ffi.SetBookmark(r.ptr, name, revisionID, allowBackwards, ignoreImmutable)
+	return classify(r.useExclusive(func(b backend) error {
+		return b.setBookmark(name, revisionID, allowBackwards, ignoreImmutable)
+	}))
 }
 
 // WorkspaceAdd creates a new workspace at the given path.
@@ -132,18 +224,62 @@ func (r *Repo) SetBookmark(name, revisionID string, allowBackwards, ignoreImmuta
 // as the current workspace's working copy (siblings).
 // If revisionIDs is provided, the new workspace starts on top of those revisions.
 func (r *Repo) WorkspaceAdd(destinationPath, workspaceName string, revisionIDs ...string) error {
-	return ffi.WorkspaceAdd(r.ptr, destinationPath, workspaceName, revisionIDs)
+	return classify(r.useExclusive(func(b backend) error {
+		return b.workspaceAdd(destinationPath, workspaceName, revisionIDs)
+	}))
 }
 
 // WorkspaceForget removes workspace tracking (keeps files on disk).
 func (r *Repo) WorkspaceForget(workspaceName string) error {
-	return ffi.WorkspaceForget(r.ptr, workspaceName)
+	return classify(r.useExclusive(func(b backend) error {
+		return b.workspaceForget(workspaceName)
+	}))
+}
+
+// Describe sets the description of a change. The working copy is
+// snapshotted first, as the jj CLI does.
+func (r *Repo) Describe(ctx context.Context, changeID, message string) error {
+	return classify(r.useExclusive(func(b backend) error {
+		return b.describe(ctx, changeID, message)
+	}))
+}
+
+// NewChange creates an empty change after the specified change, or "@",
+// and edits it; the change's children are rebased onto the new one.
+func (r *Repo) NewChange(ctx context.Context, changeID string) error {
+	return classify(r.useExclusive(func(b backend) error {
+		return b.newChange(ctx, changeID)
+	}))
+}
+
+// Abandon removes changes and rebases their descendants.
+func (r *Repo) Abandon(ctx context.Context, changeIDs ...string) error {
+	return classify(r.useExclusive(func(b backend) error {
+		return b.abandon(ctx, changeIDs)
+	}))
+}
+
+// Squash squashes a change into its parent. When both are described, the
+// descriptions are joined as CombinedDescription does.
+func (r *Repo) Squash(ctx context.Context, changeID string) error {
+	return classify(r.useExclusive(func(b backend) error {
+		return b.squash(ctx, changeID)
+	}))
 }
 
 // Close closes the repository and frees associated resources.
+// It waits for in-flight calls, and extra or concurrent calls are no-ops.
 func (r *Repo) Close() {
-	if r.ptr != nil {
-		ffi.CloseRepo(r.ptr)
-		r.ptr = nil
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.backend == nil {
+		return
 	}
+	r.backend.close()
+	r.backend = nil
+	openRepos.Add(-1)
+	runtime.SetFinalizer(r, nil)
 }
@@ -1,10 +1,13 @@
 package jj
 
 import (
+	"context"
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -355,3 +358,588 @@ func TestWorkspaceAddExistingNonEmptyPath(t *testing.T) {
 		t.Errorf("WorkspaceAdd should fail for non-empty directory")
 	}
 }
+
+// TestOpenReposCountsHandles verifies the open-handle counter used for leak checks
+func TestOpenReposCountsHandles(t *testing.T) {
+	tmpDir := t.TempDir()
+	initCmd := exec.Command("jj", "git", "init", tmpDir)
+	if err := initCmd.Run(); err != nil {
+		t.Skipf("jj not available or unable to initialize repo: %v", err)
+	}
+
+	before := OpenRepos()
+	repo, err := Open(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to open repository: %v", err)
+	}
+	if got := OpenRepos(); got != before+1 {
+		t.Errorf("OpenRepos() = %d after Open, want %d", got, before+1)
+	}
+
+	repo.Close()
+	repo.Close() // Closing twice must not double count
+	if got := OpenRepos(); got != before {
+		t.Errorf("OpenRepos() = %d after Close, want %d", got, before)
+	}
+}
+
+// TestClosedRepoReturnsErrClosed verifies methods refuse a closed or nil repo
+// instead of passing a dangling handle to the bridge
+func TestClosedRepoReturnsErrClosed(t *testing.T) {
+	closed := &Repo{}
+	closed.Close()
+
+	var nilRepo *Repo
+	nilRepo.Close() // Must not panic
+
+	for name, repo := range map[string]*Repo{"closed": closed, "nil": nilRepo} {
+		if _, err := repo.Log(); !errors.Is(err, ErrClosed) {
+			t.Errorf("%s repo: Log() error = %v, want ErrClosed", name, err)
+		}
+		if _, err := repo.Tags(); !errors.Is(err, ErrClosed) {
+			t.Errorf("%s repo: Tags() error = %v, want ErrClosed", name, err)
+		}
+		if _, err := repo.FileContents("a.txt"); !errors.Is(err, ErrClosed) {
+			t.Errorf("%s repo: FileContents() error = %v, want ErrClosed", name, err)
+		}
+		if err := repo.SetBookmark("main", "abc", false, false); !errors.Is(err, ErrClosed) {
+			t.Errorf("%s repo: SetBookmark() error = %v, want ErrClosed", name, err)
+		}
+	}
+}
+
+// TestConcurrentClose verifies Close is idempotent when called concurrently
+func TestConcurrentClose(t *testing.T) {
+	tmpDir := t.TempDir()
+	initCmd := exec.Command("jj", "git", "init", tmpDir)
+	if err := initCmd.Run(); err != nil {
+		t.Skipf("jj not available or unable to initialize repo: %v", err)
+	}
+
+	before := OpenRepos()
+	repo, err := Open(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to open repository: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			repo.Close()
+		}()
+		go func() {
+			defer wg.Done()
+			_, _ = repo.Log() // Either succeeds or sees ErrClosed
+		}()
+	}
+	wg.Wait()
+
+	if got := OpenRepos(); got != before {
+		t.Errorf("OpenRepos() = %d after concurrent Close, want %d", got, before)
+	}
+}
+
+// TestReloadSeesChangesMadeElsewhere verifies reads after Reload see
+// bookmarks and commits made by jj outside the open handle
+func TestReloadSeesChangesMadeElsewhere(t *testing.T) {
+	tmpDir := t.TempDir()
+	initCmd := exec.Command("jj", "git", "init", tmpDir)
+	if err := initCmd.Run(); err != nil {
+		t.Skipf("jj not available or unable to initialize repo: %v", err)
+	}
+
+	repo, err := Open(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to open repository: %v", err)
+	}
+	defer repo.Close()
+	if _, err := repo.Branches(); err != nil {
+		t.Fatalf("Branches() error = %v", err)
+	}
+
+	// A commit and bookmark the open handle has never seen
+	for _, args := range [][]string{
+		{"new", "-m", "made elsewhere"},
+		{"bookmark", "create", "elsewhere", "-r", "@"},
+	} {
+		cmd := exec.Command("jj", args...)
+		cmd.Dir = tmpDir
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("jj %s failed: %v", args[0], err)
+		}
+	}
+	logCmd := exec.Command("jj", "log", "-r", "@", "-T", "commit_id", "--no-graph")
+	logCmd.Dir = tmpDir
+	commitID, err := logCmd.Output()
+	if err != nil {
+		t.Fatalf("failed to get commit ID: %v", err)
+	}
+
+	if err := repo.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	branches, err := repo.Branches()
+	if err != nil {
+		t.Fatalf("Branches() error = %v", err)
+	}
+	if len(branches) != 1 || branches[0].Name != "elsewhere" {
+		t.Errorf("Branches() = %+v, want the bookmark made elsewhere", branches)
+	}
+	if _, err := repo.RevisionDiff(strings.TrimSpace(string(commitID))); err != nil {
+		t.Errorf("RevisionDiff() error = %v, want the commit made elsewhere found", err)
+	}
+}
+
+// TestRewriteRefusesImmutable verifies the rewrites check immutable_heads()
+// for every commit they would change: a tagged commit can't be described,
+// nor have its child squashed into it
+func TestRewriteRefusesImmutable(t *testing.T) {
+	tmpDir := t.TempDir()
+	initCmd := exec.Command("jj", "git", "init", tmpDir)
+	if err := initCmd.Run(); err != nil {
+		t.Skipf("jj not available or unable to initialize repo: %v", err)
+	}
+	for _, args := range [][]string{
+		{"describe", "-m", "released"},
+		{"tag", "set", "v1", "-r", "@"},
+		{"new", "-m", "after release"},
+	} {
+		cmd := exec.Command("jj", args...)
+		cmd.Dir = tmpDir
+		if err := cmd.Run(); err != nil {
+			t.Skipf("jj %s failed: %v", args[0], err)
+		}
+	}
+
+	repo, err := Open(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to open repository: %v", err)
+	}
+	defer repo.Close()
+
+	changeID := func(revset string) string {
+		cmd := exec.Command("jj", "log", "-r", revset, "-T", "change_id", "--no-graph")
+		cmd.Dir = tmpDir
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("failed to get change ID of %s: %v", revset, err)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	tagged, current := changeID("v1"), changeID("@")
+
+	ctx := context.Background()
+	if err := repo.Describe(ctx, tagged, "rewritten"); !errors.Is(err, ErrImmutable) {
+		t.Errorf("Describe() of a tagged commit error = %v, want ErrImmutable", err)
+	}
+	if err := repo.Squash(ctx, current); !errors.Is(err, ErrImmutable) {
+		t.Errorf("Squash() into a tagged commit error = %v, want ErrImmutable", err)
+	}
+	if err := repo.Describe(ctx, current, "still mutable"); err != nil {
+		t.Errorf("Describe() of a mutable commit error = %v", err)
+	}
+}
+
+// TestRewriteHonoursCancelledContext verifies a rewrite whose context is
+// already cancelled leaves the repo alone
+func TestRewriteHonoursCancelledContext(t *testing.T) {
+	tmpDir := t.TempDir()
+	initCmd := exec.Command("jj", "git", "init", tmpDir)
+	if err := initCmd.Run(); err != nil {
+		t.Skipf("jj not available or unable to initialize repo: %v", err)
+	}
+
+	repo, err := Open(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to open repository: %v", err)
+	}
+	defer repo.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	logCmd := exec.Command("jj", "log", "-r", "@", "-T", "change_id", "--no-graph")
+	logCmd.Dir = tmpDir
+	changeID, err := logCmd.Output()
+	if err != nil {
+		t.Fatalf("failed to get change ID: %v", err)
+	}
+	if err := repo.Describe(ctx, strings.TrimSpace(string(changeID)), "never written"); err == nil {
+		t.Fatal("Describe() with a cancelled context succeeded")
+	}
+	descCmd := exec.Command("jj", "log", "-r", "@", "-T", "description", "--no-graph")
+	descCmd.Dir = tmpDir
+	out, err := descCmd.Output()
+	if err != nil {
+		t.Fatalf("jj log failed: %v", err)
+	}
+	if strings.TrimSpace(string(out)) != "" {
+		t.Errorf("description = %q after a cancelled Describe, want it unchanged", out)
+	}
+}
+
+// TestTagsListsTagCreatedByCLI verifies a tag made with CreateTag, which
+// runs jj rather than the open handle, is listed once the repo reloads
+func TestTagsListsTagCreatedByCLI(t *testing.T) {
+	tmpDir := t.TempDir()
+	initCmd := exec.Command("jj", "git", "init", tmpDir)
+	if err := initCmd.Run(); err != nil {
+		t.Skipf("jj not available or unable to initialize repo: %v", err)
+	}
+
+	repo, err := Open(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to open repository: %v", err)
+	}
+	defer repo.Close()
+	if _, err := repo.Tags(); err != nil {
+		t.Fatalf("Tags() error = %v", err)
+	}
+
+	if err := CreateTag(context.Background(), tmpDir, "v1.0.0", "@"); err != nil {
+		t.Fatalf("CreateTag() error = %v", err)
+	}
+	if err := repo.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	tags, err := repo.Tags()
+	if err != nil {
+		t.Fatalf("Tags() error = %v", err)
+	}
+	if len(tags) != 1 || tags[0].Name != "v1.0.0" {
+		t.Errorf("Tags() = %+v, want the tag just created", tags)
+	}
+}
+
+// TestBranchesAheadAfterPush verifies ahead counts follow pushes made with
+// the jj CLI once the repo reloads, rather than staying where Open left them
+func TestBranchesAheadAfterPush(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	t.Setenv("JJ_USER", "Test User")
+	t.Setenv("JJ_EMAIL", "test@example.com")
+	tmpDir := t.TempDir()
+	remoteDir := filepath.Join(tmpDir, "remote.git")
+	repoDir := filepath.Join(tmpDir, "repo")
+	if err := exec.Command("git", "init", "--bare", remoteDir).Run(); err != nil {
+		t.Skipf("unable to create git remote: %v", err)
+	}
+	if err := exec.Command("jj", "git", "init", repoDir).Run(); err != nil {
+		t.Skipf("jj not available or unable to initialize repo: %v", err)
+	}
+	jjRun := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("jj", args...)
+		cmd.Dir = repoDir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("jj %s failed: %v\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+	jjRun("git", "remote", "add", "origin", remoteDir)
+	jjRun("describe", "-m", "one")
+	jjRun("bookmark", "create", "main", "-r", "@")
+
+	repo, err := Open(repoDir)
+	if err != nil {
+		t.Fatalf("failed to open repository: %v", err)
+	}
+	defer repo.Close()
+
+	ctx := context.Background()
+	if err := GitPushBookmarks(ctx, repoDir, []string{"main"}, true); err != nil {
+		t.Fatalf("GitPushBookmarks() error = %v", err)
+	}
+	jjRun("new", "-m", "two")
+	jjRun("bookmark", "set", "main", "-r", "@")
+
+	ahead := func() int {
+		t.Helper()
+		if err := repo.Reload(); err != nil {
+			t.Fatalf("Reload() error = %v", err)
+		}
+		branches, err := repo.Branches()
+		if err != nil {
+			t.Fatalf("Branches() error = %v", err)
+		}
+		for _, b := range branches {
+			if b.Name == "main" {
+				return b.Ahead
+			}
+		}
+		t.Fatalf("Branches() = %+v, want main", branches)
+		return 0
+	}
+	if got := ahead(); got != 1 {
+		t.Errorf("Ahead = %d before pushing the new commit, want 1", got)
+	}
+	if err := GitPushBookmarks(ctx, repoDir, []string{"main"}, false); err != nil {
+		t.Fatalf("GitPushBookmarks() error = %v", err)
+	}
+	if got := ahead(); got != 0 {
+		t.Errorf("Ahead = %d after pushing, want 0", got)
+	}
+}
+
+// TestOperationsListsOperationsMadeElsewhere verifies the operation log
+// includes operations run with the jj CLI once the repo reloads
+func TestOperationsListsOperationsMadeElsewhere(t *testing.T) {
+	tmpDir := t.TempDir()
+	initCmd := exec.Command("jj", "git", "init", tmpDir)
+	if err := initCmd.Run(); err != nil {
+		t.Skipf("jj not available or unable to initialize repo: %v", err)
+	}
+
+	repo, err := Open(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to open repository: %v", err)
+	}
+	defer repo.Close()
+	before, err := repo.Operations()
+	if err != nil {
+		t.Fatalf("Operations() error = %v", err)
+	}
+
+	newCmd := exec.Command("jj", "new", "-m", "made elsewhere")
+	newCmd.Dir = tmpDir
+	if err := newCmd.Run(); err != nil {
+		t.Fatalf("jj new failed: %v", err)
+	}
+	if err := repo.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	after, err := repo.Operations()
+	if err != nil {
+		t.Fatalf("Operations() error = %v", err)
+	}
+	if len(after) <= len(before) {
+		t.Fatalf("Operations() = %d operations after jj new, want more than %d", len(after), len(before))
+	}
+	for _, op := range after {
+		if op.IsCurrent && !strings.HasPrefix(op.Description, "new empty commit") {
+			t.Errorf("current operation = %q, want the jj new run elsewhere", op.Description)
+		}
+	}
+}
+
+// TestReadReopensStaleHandle verifies a read that fails on a handle opened
+// before jj changed the repo elsewhere is retried on a fresh handle
+func TestReadReopensStaleHandle(t *testing.T) {
+	if !backendGoesStale {
+		t.Skip("the CLI backend has no handle to go stale")
+	}
+	tmpDir := t.TempDir()
+	initCmd := exec.Command("jj", "git", "init", tmpDir)
+	if err := initCmd.Run(); err != nil {
+		t.Skipf("jj not available or unable to initialize repo: %v", err)
+	}
+
+	before := OpenRepos()
+	repo, err := Open(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to open repository: %v", err)
+	}
+	defer repo.Close()
+	stale := repo.backend
+
+	// A commit the open handle has never seen
+	newCmd := exec.Command("jj", "new", "-m", "made elsewhere")
+	newCmd.Dir = tmpDir
+	if err := newCmd.Run(); err != nil {
+		t.Fatalf("jj new failed: %v", err)
+	}
+	logCmd := exec.Command("jj", "log", "-r", "@", "-T", "commit_id", "--no-graph")
+	logCmd.Dir = tmpDir
+	commitID, err := logCmd.Output()
+	if err != nil {
+		t.Fatalf("failed to get commit ID: %v", err)
+	}
+
+	if _, err := repo.RevisionDiff(strings.TrimSpace(string(commitID))); err != nil {
+		t.Errorf("RevisionDiff() error = %v, want the commit found after reopening", err)
+	}
+	if repo.backend == stale {
+		t.Error("expected the stale handle to be replaced")
+	}
+	if got := OpenRepos(); got != before+1 {
+		t.Errorf("OpenRepos() = %d after reopening, want %d", got, before+1)
+	}
+
+	// A caller holding the old handle doesn't reopen again
+	current := repo.backend
+	if !repo.reopen(stale) || repo.backend != current {
+		t.Error("reopen of an already replaced handle should keep the current one")
+	}
+}
+
+// TestConcurrentReloadAndMutation verifies Reload and mutations racing
+// from different goroutines each run alone and all succeed
+func TestConcurrentReloadAndMutation(t *testing.T) {
+	tmpDir := t.TempDir()
+	initCmd := exec.Command("jj", "git", "init", tmpDir)
+	if err := initCmd.Run(); err != nil {
+		t.Skipf("jj not available or unable to initialize repo: %v", err)
+	}
+
+	repo, err := Open(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to open repository: %v", err)
+	}
+	defer repo.Close()
+
+	ctx := context.Background()
+	errs := make(chan error, 12)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			errs <- repo.Reload()
+		}()
+		go func() {
+			defer wg.Done()
+			errs <- repo.NewChange(ctx, "@")
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := repo.Log()
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("concurrent call failed: %v", err)
+		}
+	}
+
+	logCmd := exec.Command("jj", "log", "-r", "::@ ~ root()", "-T", "change_id ++ \"\\n\"", "--no-graph")
+	logCmd.Dir = tmpDir
+	out, err := logCmd.Output()
+	if err != nil {
+		t.Fatalf("jj log failed: %v", err)
+	}
+	if got := len(strings.Fields(string(out))); got != 5 {
+		t.Errorf("got %d changes after 4 concurrent NewChange calls, want 5", got)
+	}
+}
+
+// TestAbandonSeveral verifies Abandon removes every change it's given in
+// one operation and keeps their descendants
+func TestAbandonSeveral(t *testing.T) {
+	tmpDir := t.TempDir()
+	initCmd := exec.Command("jj", "git", "init", tmpDir)
+	if err := initCmd.Run(); err != nil {
+		t.Skipf("jj not available or unable to initialize repo: %v", err)
+	}
+	for _, msg := range []string{"first", "second", "third"} {
+		cmd := exec.Command("jj", "new", "-m", msg)
+		cmd.Dir = tmpDir
+		if err := cmd.Run(); err != nil {
+			t.Skipf("jj new failed: %v", err)
+		}
+	}
+
+	ctx := context.Background()
+	changeIDs, err := ChangeIDs(ctx, tmpDir, `description("first") | description("second")`)
+	if err != nil {
+		t.Fatalf("ChangeIDs() error = %v", err)
+	}
+	if len(changeIDs) != 2 {
+		t.Fatalf("ChangeIDs() = %v, want 2 IDs", changeIDs)
+	}
+
+	repo, err := Open(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to open repository: %v", err)
+	}
+	defer repo.Close()
+	if err := repo.Abandon(ctx, changeIDs...); err != nil {
+		t.Fatalf("Abandon() error = %v", err)
+	}
+
+	descriptions, err := Descriptions(ctx, tmpDir, "::@ ~ root()")
+	if err != nil {
+		t.Fatalf("Descriptions() error = %v", err)
+	}
+	if len(descriptions) != 1 || descriptions[0] != "third" {
+		t.Errorf("descriptions after Abandon() = %q, want only third", descriptions)
+	}
+}
+
+// TestSnapshotHonoursGitExcludes verifies the snapshot a rewrite takes
+// leaves out files listed in .git/info/exclude, as jj does
+func TestSnapshotHonoursGitExcludes(t *testing.T) {
+	tmpDir := t.TempDir()
+	initCmd := exec.Command("jj", "git", "init", "--colocate", tmpDir)
+	if err := initCmd.Run(); err != nil {
+		t.Skipf("jj not available or unable to initialize repo: %v", err)
+	}
+	excludePath := filepath.Join(tmpDir, ".git", "info", "exclude")
+	if err := os.MkdirAll(filepath.Dir(excludePath), 0o755); err != nil {
+		t.Fatalf("failed to create .git/info: %v", err)
+	}
+	if err := os.WriteFile(excludePath, []byte("secret.txt\n"), 0o644); err != nil {
+		t.Fatalf("failed to write exclude file: %v", err)
+	}
+	for _, name := range []string{"secret.txt", "tracked.txt"} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(name+"\n"), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	repo, err := Open(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to open repository: %v", err)
+	}
+	defer repo.Close()
+	if err := repo.Describe(context.Background(), "@", "snapshotted"); err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+
+	// Read what the rewrite recorded without letting jj snapshot again
+	listCmd := exec.Command("jj", "file", "list", "-r", "@", "--ignore-working-copy")
+	listCmd.Dir = tmpDir
+	out, err := listCmd.Output()
+	if err != nil {
+		t.Fatalf("jj file list failed: %v", err)
+	}
+	if files := strings.Fields(string(out)); len(files) != 1 || files[0] != "tracked.txt" {
+		t.Errorf("files in @ = %v, want only tracked.txt", files)
+	}
+}
+
+// TestRewriteRefusesDivergentChange verifies a change ID naming two
+// visible commits is refused instead of picking one of them
+func TestRewriteRefusesDivergentChange(t *testing.T) {
+	tmpDir := t.TempDir()
+	initCmd := exec.Command("jj", "git", "init", tmpDir)
+	if err := initCmd.Run(); err != nil {
+		t.Skipf("jj not available or unable to initialize repo: %v", err)
+	}
+	run := func(args ...string) string {
+		cmd := exec.Command("jj", args...)
+		cmd.Dir = tmpDir
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("jj %s failed: %v", args[0], err)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	changeID := run("log", "-r", "@", "-T", "change_id", "--no-graph")
+	opID := run("op", "log", "-n", "1", "-T", "id", "--no-graph")
+	// Two describes from the same operation leave two commits for the change
+	run("describe", "-m", "one")
+	run("describe", "--at-op", opID, "-m", "other")
+
+	repo, err := Open(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to open repository: %v", err)
+	}
+	defer repo.Close()
+	if err := repo.Describe(context.Background(), changeID, "which one?"); err == nil {
+		t.Error("Describe() of a divergent change succeeded")
+	}
+}
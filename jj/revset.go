@@ -0,0 +1,122 @@
+package jj
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// RevsetError is a revset that jj failed to parse or resolve.
+type RevsetError struct {
+	Revset  string
+	Message string // First line of jj's error, without the "Error: " prefix
+	Offset  int    // 0-indexed position of the error in Revset, -1 if unknown
+}
+
+func (e *RevsetError) Error() string {
+	return e.Message
+}
+
+// revsetErrorPos matches the "--> line:column" marker in jj's parse errors
+var revsetErrorPos = regexp.MustCompile(`-->\s*1:(\d+)`)
+
+// CheckRevset dry-runs a revset, returning how many revisions it matches
+// without loading them, for validating a revset as it's typed. Invalid
+// revsets return a *RevsetError.
+func CheckRevset(ctx context.Context, repoPath, revset string) (int, error) {
+	output, err := runJJ(ctx, repoPath, "log", "-r", revset, "--no-graph", "--color=never", "-T", `"\n"`)
+	if err != nil {
+		return 0, revsetError(revset, err)
+	}
+	return strings.Count(string(output), "\n"), nil
+}
+
+// revsetError turns jj's failure to resolve revset into a *RevsetError,
+// or returns err as is if jj didn't run at all
+func revsetError(revset string, err error) error {
+	var jjErr *JJError
+	if !errors.As(err, &jjErr) || jjErr.Stderr == "" {
+		return err
+	}
+	return parseRevsetError(revset, jjErr.Stderr)
+}
+
+// RevsetSymbol returns name as a revset symbol, quoted when it has
+// characters jj would read as operators
+func RevsetSymbol(name string) string {
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("_-./@", r) {
+			return quoteString(name)
+		}
+	}
+	return name
+}
+
+// RootFileset returns a fileset matching exactly the file at path, relative
+// to the workspace root, however the path is spelled
+func RootFileset(path string) string {
+	return "root:" + quoteString(path)
+}
+
+// TrunkRevset returns the revset for the trunk bookmark: the bookmark's
+// target, or trunk() when no bookmark is given or it doesn't exist
+func TrunkRevset(bookmark string) string {
+	if bookmark == "" {
+		return "trunk()"
+	}
+	return fmt.Sprintf("coalesce(bookmarks(exact:%s), trunk())", quoteString(bookmark))
+}
+
+// quoteString returns s as a jj string literal. Unlike strconv.Quote it
+// never writes \u escapes, which jj doesn't read: UTF-8 passes through and
+// other control characters are written as \xNN.
+func quoteString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\n':
+			b.WriteString(`\n`)
+		default:
+			if c < 0x20 || c == 0x7f {
+				fmt.Fprintf(&b, `\x%02x`, c)
+			} else {
+				b.WriteByte(c)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// parseRevsetError extracts the message and error position from jj's stderr
+func parseRevsetError(revset, stderr string) *RevsetError {
+	revErr := &RevsetError{Revset: revset, Message: "invalid revset", Offset: -1}
+
+	for _, line := range strings.Split(stderr, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			revErr.Message = strings.TrimPrefix(line, "Error: ")
+			break
+		}
+	}
+
+	if m := revsetErrorPos.FindStringSubmatch(stderr); m != nil {
+		if col, err := strconv.Atoi(m[1]); err == nil && col >= 1 {
+			revErr.Offset = col - 1
+		}
+	}
+
+	return revErr
+}
@@ -0,0 +1,86 @@
+package jj
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseRevsetError(t *testing.T) {
+	stderr := `Error: Failed to parse revset: Syntax error
+Caused by:  --> 1:7
+  |
+1 | main..|
+  |       ^---
+  |
+  = expected <EOI> or <expression>
+`
+	err := parseRevsetError("main..|", stderr)
+	if err.Message != "Failed to parse revset: Syntax error" {
+		t.Errorf("Message = %q", err.Message)
+	}
+	if err.Offset != 6 {
+		t.Errorf("Offset = %d, want 6", err.Offset)
+	}
+}
+
+func TestParseRevsetErrorWithoutPosition(t *testing.T) {
+	err := parseRevsetError("nope", "Error: Revision `nope` doesn't exist\n")
+	if err.Message != "Revision `nope` doesn't exist" {
+		t.Errorf("Message = %q", err.Message)
+	}
+	if err.Offset != -1 {
+		t.Errorf("Offset = %d, want -1", err.Offset)
+	}
+}
+
+// TestCheckRevsetErrors tests error handling in CheckRevset
+func TestCheckRevsetErrors(t *testing.T) {
+	if _, err := CheckRevset(context.Background(), "/nonexistent/path", "@"); err == nil {
+		t.Errorf("CheckRevset should fail with non-existent repo path")
+	}
+}
+
+func TestRevsetSymbol(t *testing.T) {
+	tests := map[string]string{
+		"main":          "main",
+		"feature/login": "feature/login",
+		"fix-1.2":       "fix-1.2",
+		"a&b":           `"a&b"`,
+		"with space":    `"with space"`,
+	}
+	for name, want := range tests {
+		if got := RevsetSymbol(name); got != want {
+			t.Errorf("RevsetSymbol(%q) = %s, want %s", name, got, want)
+		}
+	}
+}
+
+func TestTrunkRevset(t *testing.T) {
+	tests := map[string]string{
+		"":         "trunk()",
+		"main":     `coalesce(bookmarks(exact:"main"), trunk())`,
+		"rel/v1.0": `coalesce(bookmarks(exact:"rel/v1.0"), trunk())`,
+	}
+	for bookmark, want := range tests {
+		if got := TrunkRevset(bookmark); got != want {
+			t.Errorf("TrunkRevset(%q) = %s, want %s", bookmark, got, want)
+		}
+	}
+}
+
+func TestRootFileset(t *testing.T) {
+	tests := map[string]string{
+		"main.go":           `root:"main.go"`,
+		"dir/with space.md": `root:"dir/with space.md"`,
+		`say "hi".txt`:      `root:"say \"hi\".txt"`,
+		`back\slash`:        `root:"back\\slash"`,
+		"tab\there":         `root:"tab\there"`,
+		"bell\a":            `root:"bell\x07"`,
+		"café.txt":          `root:"café.txt"`,
+	}
+	for path, want := range tests {
+		if got := RootFileset(path); got != want {
+			t.Errorf("RootFileset(%q) = %s, want %s", path, got, want)
+		}
+	}
+}
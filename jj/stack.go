@@ -0,0 +1,237 @@
+package jj
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// StackRevset returns the stack: the changes on @'s side of trunk, a
+// revset such as TrunkRevset returns
+func StackRevset(trunk string) string {
+	return "(" + trunk + ")..@"
+}
+
+// Push states of a stack entry
+const (
+	StackNeedsPush = "needs push" // A bookmark is new or moved since it was pushed
+	StackMerged    = "merged"     // A pushed bookmark is gone from its remote, as after merging
+	StackBehind    = "behind"     // Based on an older trunk, so the stack needs a rebase
+	StackPushed    = "pushed"     // Every bookmark matches its remote
+)
+
+// StackEntry is a change in the stack
+type StackEntry struct {
+	ChangeID    string
+	CommitID    string
+	Description string   // First line of description (empty if none)
+	Bookmarks   []string // Local bookmarks on the change
+	Status      string   // A Stack* state; empty for an unbookmarked change that isn't behind
+}
+
+// stackTemplate renders one "changeID<TAB>commitID<TAB>bookmarks<TAB>behind<TAB>description"
+// line per change, behind unless the change descends from trunk
+func stackTemplate(trunk string) string {
+	return `change_id.short(8) ++ "\t" ++ commit_id.short(8) ++ "\t" ++ local_bookmarks.map(|b| b.name()).join(",") ++ "\t" ++ ` +
+		`if(self.contained_in(` + quoteString("("+trunk+")::") + `), "", "behind") ++ "\t" ++ description.first_line() ++ "\n"`
+}
+
+// bookmarkTargetTemplate renders one "name<TAB>remote<TAB>tracked<TAB>present<TAB>commitID" line per ref
+const bookmarkTargetTemplate = `name ++ "\t" ++ if(remote, remote, "") ++ "\t" ++ if(tracked, "tracked", "") ++ "\t" ++ if(present, "present", "") ++ "\t" ++ if(normal_target, normal_target.commit_id().short(8), "") ++ "\n"`
+
+// Stack lists the changes from @ down to trunk, newest first, with the
+// push state of their bookmarks
+func Stack(ctx context.Context, repoPath, trunk string) ([]StackEntry, error) {
+	log, err := runJJ(ctx, repoPath, "log", "-r", StackRevset(trunk), "--no-graph", "-T", stackTemplate(trunk))
+	if err != nil {
+		return nil, err
+	}
+	listing, err := runJJ(ctx, repoPath, "bookmark", "list", "--all-remotes", "-T", bookmarkTargetTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return parseStack(string(log), string(listing)), nil
+}
+
+// remoteRef is a tracked remote bookmark, where it points if still present
+type remoteRef struct {
+	present bool
+	target  string
+}
+
+// parseStack combines the stack log and the bookmark listing into entries
+func parseStack(log, listing string) []StackEntry {
+	remotes := make(map[string][]remoteRef)
+	for _, line := range strings.Split(listing, "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 5 {
+			continue
+		}
+		name, remote, tracked := fields[0], fields[1], fields[2] != ""
+		if remote != "" && remote != "git" && tracked {
+			remotes[name] = append(remotes[name], remoteRef{present: fields[3] != "", target: fields[4]})
+		}
+	}
+
+	var entries []StackEntry
+	for _, line := range strings.Split(log, "\n") {
+		parts := strings.SplitN(line, "\t", 5)
+		if len(parts) < 5 {
+			continue
+		}
+		entry := StackEntry{ChangeID: parts[0], CommitID: parts[1], Description: parts[4]}
+		if parts[2] != "" {
+			entry.Bookmarks = strings.Split(parts[2], ",")
+		}
+		entry.Status = stackStatus(entry, parts[3] != "", remotes)
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// stackStatus decides an entry's push state: merged wins over needing a
+// push, which wins over being behind
+func stackStatus(entry StackEntry, behind bool, remotes map[string][]remoteRef) string {
+	needsPush, merged := false, false
+	for _, name := range entry.Bookmarks {
+		refs := remotes[name]
+		if len(refs) == 0 {
+			needsPush = true
+		}
+		for _, ref := range refs {
+			switch {
+			case !ref.present:
+				merged = true
+			case ref.target != entry.CommitID:
+				needsPush = true
+			}
+		}
+	}
+
+	switch {
+	case merged:
+		return StackMerged
+	case needsPush:
+		return StackNeedsPush
+	case behind:
+		return StackBehind
+	case len(entry.Bookmarks) > 0:
+		return StackPushed
+	}
+	return ""
+}
+
+// StackRenames returns the renames that number the first bookmark of each
+// bookmarked entry base1, base2, … from the bottom of the stack, as
+// [old, new] pairs. Names already right are left alone.
+func StackRenames(entries []StackEntry, base string) [][2]string {
+	var renames [][2]string
+	n := 0
+	for _, entry := range slices.Backward(entries) {
+		if len(entry.Bookmarks) == 0 {
+			continue
+		}
+		n++
+		if name := fmt.Sprintf("%s%d", base, n); entry.Bookmarks[0] != name {
+			renames = append(renames, [2]string{entry.Bookmarks[0], name})
+		}
+	}
+	return renames
+}
+
+// RenameBookmarks applies renames in order. Each bookmark first moves to a
+// temporary name, so renames may swap names within the set.
+// jj bookmark rename <old> <new>
+func RenameBookmarks(ctx context.Context, repoPath string, renames [][2]string) error {
+	for _, rename := range renames {
+		if _, err := runJJ(ctx, repoPath, "bookmark", "rename", rename[0], rename[0]+".renaming"); err != nil {
+			return err
+		}
+	}
+	for _, rename := range renames {
+		if _, err := runJJ(ctx, repoPath, "bookmark", "rename", rename[0]+".renaming", rename[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GitPushBookmarks pushes several bookmarks to their remote at once
+// jj git push --bookmark <name>... [--allow-new]
+func GitPushBookmarks(ctx context.Context, repoPath string, bookmarks []string, allowNew bool) error {
+	args := []string{"git", "push"}
+	for _, name := range bookmarks {
+		args = append(args, "--bookmark", name)
+	}
+	if allowNew {
+		args = append(args, "--allow-new")
+	}
+	_, err := runJJ(ctx, repoPath, args...)
+	return err
+}
+
+// UpdateResult is the stack after UpdateFromTrunk
+type UpdateResult struct {
+	Changes   int                // Changes in the stack after the rebase
+	Conflicts []ConflictedChange // Changes left conflicted, newest first
+}
+
+// ConflictedChange is a stack change with unresolved conflicts
+type ConflictedChange struct {
+	ChangeID    string
+	Description string // First line of description (empty if none)
+	New         bool   // Conflicted by the rebase rather than before it
+}
+
+// conflictTemplate renders one "changeID<TAB>conflict<TAB>description" line per change
+const conflictTemplate = `change_id.short(8) ++ "\t" ++ if(conflict, "conflict", "") ++ "\t" ++ description.first_line() ++ "\n"`
+
+// UpdateFromTrunk fetches, then rebases the stack onto trunk, a revset
+// such as TrunkRevset returns, and reports the changes the rebase left
+// conflicted
+// jj git fetch && jj rebase -b @ -d <trunk>
+func UpdateFromTrunk(ctx context.Context, repoPath, trunk string) (*UpdateResult, error) {
+	if err := GitFetch(ctx, repoPath); err != nil {
+		return nil, err
+	}
+	before, err := runJJ(ctx, repoPath, "log", "-r", StackRevset(trunk), "--no-graph", "-T", conflictTemplate)
+	if err != nil {
+		return nil, err
+	}
+	if err := RebaseBranch(ctx, repoPath, "@", trunk); err != nil {
+		return nil, err
+	}
+	after, err := runJJ(ctx, repoPath, "log", "-r", StackRevset(trunk), "--no-graph", "-T", conflictTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return parseUpdate(string(before), string(after)), nil
+}
+
+// parseUpdate compares the stack's conflicts before and after the rebase
+func parseUpdate(before, after string) *UpdateResult {
+	conflicted := make(map[string]bool)
+	for _, line := range strings.Split(before, "\n") {
+		if parts := strings.SplitN(line, "\t", 3); len(parts) == 3 && parts[1] != "" {
+			conflicted[parts[0]] = true
+		}
+	}
+
+	result := &UpdateResult{}
+	for _, line := range strings.Split(after, "\n") {
+		parts := strings.SplitN(line, "\t", 3)
+		if len(parts) < 3 {
+			continue
+		}
+		result.Changes++
+		if parts[1] != "" {
+			result.Conflicts = append(result.Conflicts, ConflictedChange{
+				ChangeID:    parts[0],
+				Description: parts[2],
+				New:         !conflicted[parts[0]],
+			})
+		}
+	}
+	return result
+}
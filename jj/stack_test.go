@@ -0,0 +1,162 @@
+package jj
+
+import (
+	"context"
+	"os/exec"
+	"slices"
+	"testing"
+)
+
+// TestParseStack tests the push state of each stack entry
+func TestParseStack(t *testing.T) {
+	log := "aaaaaaaa\t11111111\t\t\twip\n" +
+		"bbbbbbbb\t22222222\tnew-part\t\tNew part\n" +
+		"cccccccc\t33333333\tmoved\t\tMoved\n" +
+		"dddddddd\t44444444\tlanded\t\tLanded\n" +
+		"eeeeeeee\t55555555\tsynced\tbehind\tSynced\n" +
+		"ffffffff\t66666666\t\tbehind\tBase\n"
+	listing := "landed\t\t\tpresent\t44444444\n" +
+		"landed\torigin\ttracked\t\t\n" +
+		"moved\t\t\tpresent\t33333333\n" +
+		"moved\torigin\ttracked\tpresent\t30303030\n" +
+		"new-part\t\t\tpresent\t22222222\n" +
+		"new-part\tgit\ttracked\tpresent\t22222222\n" +
+		"synced\t\t\tpresent\t55555555\n" +
+		"synced\torigin\ttracked\tpresent\t55555555\n"
+
+	got := parseStack(log, listing)
+	want := []string{"", StackNeedsPush, StackNeedsPush, StackMerged, StackBehind, StackBehind}
+	if len(got) != len(want) {
+		t.Fatalf("parseStack() returned %d entries, want %d", len(got), len(want))
+	}
+	for i, status := range want {
+		if got[i].Status != status {
+			t.Errorf("entry %s status = %q, want %q", got[i].ChangeID, got[i].Status, status)
+		}
+	}
+	if got[1].Description != "New part" || !slices.Equal(got[1].Bookmarks, []string{"new-part"}) {
+		t.Errorf("entry = %+v, want new-part described as New part", got[1])
+	}
+
+	// A bookmark whose remote matches is pushed once the stack is on trunk
+	synced := parseStack("eeeeeeee\t55555555\tsynced\t\tSynced\n", listing)
+	if synced[0].Status != StackPushed {
+		t.Errorf("synced status = %q, want %q", synced[0].Status, StackPushed)
+	}
+}
+
+// TestStackRenames tests numbering bookmarks from the bottom of the stack
+func TestStackRenames(t *testing.T) {
+	entries := []StackEntry{
+		{ChangeID: "top", Bookmarks: []string{"auth-1"}},
+		{ChangeID: "wip"},
+		{ChangeID: "mid", Bookmarks: []string{"auth-2", "extra"}},
+		{ChangeID: "low", Bookmarks: []string{"login"}},
+	}
+	got := StackRenames(entries, "auth-")
+	want := [][2]string{{"login", "auth-1"}, {"auth-1", "auth-3"}}
+	if !slices.Equal(got, want) {
+		t.Errorf("StackRenames() = %v, want %v", got, want)
+	}
+}
+
+// TestParseUpdate tests telling conflicts from the rebase apart from older ones
+func TestParseUpdate(t *testing.T) {
+	before := "aaaaaaaa\t\tTop\n" +
+		"bbbbbbbb\tconflict\tStuck\n" +
+		"cccccccc\t\tBase\n"
+	after := "aaaaaaaa\tconflict\tTop\n" +
+		"bbbbbbbb\tconflict\tStuck\n" +
+		"cccccccc\t\tBase\n"
+
+	got := parseUpdate(before, after)
+	want := []ConflictedChange{
+		{ChangeID: "aaaaaaaa", Description: "Top", New: true},
+		{ChangeID: "bbbbbbbb", Description: "Stuck"},
+	}
+	if got.Changes != 3 || !slices.Equal(got.Conflicts, want) {
+		t.Errorf("parseUpdate() = %+v, want 3 changes and conflicts %+v", got, want)
+	}
+}
+
+// TestStackErrors tests error handling of the stack commands
+func TestStackErrors(t *testing.T) {
+	ctx := context.Background()
+	if _, err := Stack(ctx, "/nonexistent/path", "trunk()"); err == nil {
+		t.Error("Stack() expected error for invalid path")
+	}
+	if err := RenameBookmarks(ctx, "/nonexistent/path", [][2]string{{"a", "b"}}); err == nil {
+		t.Error("RenameBookmarks() expected error for invalid path")
+	}
+	if _, err := UpdateFromTrunk(ctx, "/nonexistent/path", "trunk()"); err == nil {
+		t.Error("UpdateFromTrunk() expected error for invalid path")
+	}
+	if err := GitPushBookmarks(ctx, "/nonexistent/path", []string{"a"}, true); err == nil {
+		t.Error("GitPushBookmarks() expected error for invalid path")
+	}
+}
+
+// TestStackAgainstTrunkBookmark verifies the stack stops at the trunk
+// bookmark it is given, and changes not based on it are behind
+func TestStackAgainstTrunkBookmark(t *testing.T) {
+	tmpDir := t.TempDir()
+	initCmd := exec.Command("jj", "git", "init", tmpDir)
+	if err := initCmd.Run(); err != nil {
+		t.Skipf("jj not available or unable to initialize repo: %v", err)
+	}
+	for _, args := range [][]string{
+		{"describe", "-m", "base"},
+		{"new", "-m", "develop tip"},
+		{"bookmark", "create", "develop", "-r", "@"},
+		{"new", "-m", "first"},
+		{"new", "-m", "second"},
+	} {
+		cmd := exec.Command("jj", args...)
+		cmd.Dir = tmpDir
+		if err := cmd.Run(); err != nil {
+			t.Skipf("jj %s failed: %v", args[0], err)
+		}
+	}
+
+	ctx := context.Background()
+	entries, err := Stack(ctx, tmpDir, TrunkRevset("develop"))
+	if err != nil {
+		t.Fatalf("Stack() error = %v", err)
+	}
+	var descriptions []string
+	for _, entry := range entries {
+		descriptions = append(descriptions, entry.Description)
+		if entry.Status == StackBehind {
+			t.Errorf("entry %q is behind, but it is based on develop", entry.Description)
+		}
+	}
+	if !slices.Equal(descriptions, []string{"second", "first"}) {
+		t.Errorf("Stack() descriptions = %v, want [second first]", descriptions)
+	}
+
+	// Moving develop past the stack leaves it behind
+	moveCmd := exec.Command("jj", "new", "-m", "landed elsewhere", "-r", "develop")
+	moveCmd.Dir = tmpDir
+	if err := moveCmd.Run(); err != nil {
+		t.Fatalf("jj new failed: %v", err)
+	}
+	for _, args := range [][]string{
+		{"bookmark", "set", "develop", "-r", "@"},
+		{"edit", "description(second)"},
+	} {
+		cmd := exec.Command("jj", args...)
+		cmd.Dir = tmpDir
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("jj %s failed: %v", args[0], err)
+		}
+	}
+	entries, err = Stack(ctx, tmpDir, TrunkRevset("develop"))
+	if err != nil {
+		t.Fatalf("Stack() error = %v", err)
+	}
+	for _, entry := range entries {
+		if entry.Status != StackBehind {
+			t.Errorf("entry %q status = %q, want %q", entry.Description, entry.Status, StackBehind)
+		}
+	}
+}
@@ -1,44 +1,163 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gerunddev/jjazy/config"
+	"github.com/gerunddev/jjazy/headless"
 	"github.com/gerunddev/jjazy/interactive"
 	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/release"
 	"github.com/gerunddev/jjazy/ui"
+	"github.com/muesli/termenv"
 )
 
+// Version is set at build time with -ldflags "-X main.Version=..."
+var Version = "dev"
+
 func main() {
+	os.Exit(run())
+}
+
+// run runs jjazy and returns its exit code. Exiting from main rather than
+// here lets the deferred Close release the repository first.
+func run() int {
 	// Parse flags
 	interactiveMode := flag.Bool("i", false, "Run in interactive mode (quick actions)")
+	pickMode := flag.Bool("pick", false, "Pick a revision in the log and print its change ID")
+	pickFileMode := flag.Bool("pick-file", false, "Pick files changed in a revision (default @) and print their paths")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: jjazy [-i | --pick | --pick-file [rev]]\n\n")
+		flag.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\n%s", headless.Usage)
+	}
 	flag.Parse()
 
+	// Release notes don't need a repository
+	if flag.Arg(0) == "changelog" {
+		fmt.Print(release.Changelog)
+		return 0
+	}
+
+	// Load user config (defaults are used if the file is missing or invalid)
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load config %s: %v\n", config.Path(), err)
+	}
+
+	// Test mode renders the same on every machine
+	if config.TestMode() {
+		time.Local = time.UTC
+		jj.FreezeTimestamps()
+		lipgloss.SetColorProfile(termenv.TrueColor)
+		lipgloss.SetHasDarkBackground(true)
+	}
+
+	if cfg.SlowCallWarningMs != 0 {
+		jj.SetSlowThreshold(time.Duration(cfg.SlowCallWarningMs) * time.Millisecond)
+	}
+
+	// Load remembered panel layout
+	layout, err := config.LoadState()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load state %s: %v\n", config.StatePath(), err)
+	}
+
 	// Open the repository in the current directory
 	repo, err := jj.Open(".")
 	if err != nil {
+		var unsupported *jj.UnsupportedRepoError
+		if errors.As(err, &unsupported) && flag.NArg() == 0 && !*interactiveMode {
+			// Explain the refusal on a full screen (on stderr, so --pick output stays clean)
+			tea.NewProgram(ui.NewUpgradeScreen(unsupported), tea.WithAltScreen(), tea.WithOutput(os.Stderr)).Run()
+		}
 		fmt.Fprintf(os.Stderr, "Error opening repository: %v\n", err)
-		os.Exit(1)
+		if unsupported != nil {
+			fmt.Fprintf(os.Stderr, "Please upgrade jjazy to a release that supports this repository.\n")
+		}
+		return 1
 	}
 	defer repo.Close()
 
 	// Dispatch based on mode
+	if flag.NArg() > 0 && !*pickFileMode {
+		if err := headless.Run(context.Background(), repo, ".", flag.Args()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
 	if *interactiveMode {
-		if err := interactive.Run("."); err != nil {
+		if err := interactive.Run(context.Background(), repo, "."); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			return 1
 		}
-		return
+		return 0
 	}
 
 	// Full TUI mode (default)
-	app := ui.NewApp(repo, ".")
+	app := ui.NewApp(repo, ".", cfg, layout)
+	app.SetVersion(Version)
+
+	opts := []tea.ProgramOption{tea.WithAltScreen(), tea.WithMouseCellMotion()}
+	picking := *pickMode || *pickFileMode
+	if *pickFileMode {
+		revision := "@"
+		if flag.NArg() > 0 {
+			revision = flag.Arg(0)
+		}
+		app.SetPickFileMode(revision)
+	} else if *pickMode {
+		app.SetPickMode()
+	}
+	if picking {
+		// Draw on stderr so stdout carries only the picked values
+		opts = append(opts, tea.WithOutput(os.Stderr))
+	}
 
-	p := tea.NewProgram(app, tea.WithAltScreen(), tea.WithMouseCellMotion())
-	if _, err := p.Run(); err != nil {
+	recoverer := ui.Recover(app)
+	p := tea.NewProgram(recoverer, opts...)
+	_, err = p.Run()
+	if crash := recoverer.Crash(); crash != nil || errors.Is(err, tea.ErrProgramPanic) {
+		reportCrash(crash)
+		return 2
+	}
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		return 1
+	}
+
+	if picking {
+		if len(app.Picked()) == 0 {
+			return 1 // Nothing picked
+		}
+		for _, picked := range app.Picked() {
+			fmt.Println(picked)
+		}
+	}
+	return 0
+}
+
+// reportCrash writes a crash report once the terminal is restored and
+// says where it is. crash is nil when Bubble Tea caught the panic itself,
+// having printed the stack already.
+func reportCrash(crash *ui.Crash) {
+	if crash == nil {
+		crash = &ui.Crash{Value: "panic in a background command"}
+	}
+	fmt.Fprintf(os.Stderr, "jjazy crashed: %v\n", crash.Value)
+	path, err := crash.WriteReport(ui.CrashReportDir(), Version)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not write a crash report: %v\n", err)
+		return
 	}
+	fmt.Fprintf(os.Stderr, "A crash report was written to %s\nPlease attach it when reporting the bug.\n", path)
 }
@@ -0,0 +1,104 @@
+// Package release describes jjazy's own releases: the release notes bundled
+// into the binary and the opt-in check for a newer version.
+package release
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Changelog is the release notes bundled at build time
+//
+//go:embed CHANGELOG.md
+var Changelog string
+
+// DefaultFeed is the release feed checked for updates, in the format of
+// GitHub's latest release API
+const DefaultFeed = "https://api.github.com/repos/gerunddev/jjazy/releases/latest"
+
+// Update is a release newer than the running version
+type Update struct {
+	Version string // Tag of the release, e.g. "v0.4.0"
+	Notes   string // Release notes as markdown
+	URL     string // Release page
+}
+
+// feedRelease is the part of a feed entry Check reads
+type feedRelease struct {
+	TagName string `json:"tag_name"`
+	Body    string `json:"body"`
+	HTMLURL string `json:"html_url"`
+}
+
+// Check fetches the latest release from feedURL and returns it if it is
+// newer than current. It returns nil without fetching when current is not
+// a release version (e.g. "dev"), so development builds never nag.
+func Check(ctx context.Context, feedURL, current string) (*Update, error) {
+	if _, ok := parseVersion(current); !ok {
+		return nil, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("release feed returned %s", resp.Status)
+	}
+
+	var latest feedRelease
+	if err := json.NewDecoder(resp.Body).Decode(&latest); err != nil {
+		return nil, fmt.Errorf("reading release feed: %w", err)
+	}
+	if !Newer(latest.TagName, current) {
+		return nil, nil
+	}
+	return &Update{Version: latest.TagName, Notes: latest.Body, URL: latest.HTMLURL}, nil
+}
+
+// Newer reports whether version a is newer than version b. Versions look
+// like v1.2.3; anything after a "-" (a pre-release or git describe suffix)
+// is ignored. It returns false if either doesn't parse.
+func Newer(a, b string) bool {
+	va, okA := parseVersion(a)
+	vb, okB := parseVersion(b)
+	if !okA || !okB {
+		return false
+	}
+	for i := range va {
+		if va[i] != vb[i] {
+			return va[i] > vb[i]
+		}
+	}
+	return false
+}
+
+// parseVersion splits v1.2.3 into its numbers. Missing minor and patch
+// numbers are zero.
+func parseVersion(v string) ([3]int, bool) {
+	var parts [3]int
+	v, _, _ = strings.Cut(strings.TrimPrefix(v, "v"), "-")
+	fields := strings.Split(v, ".")
+	if len(fields) > len(parts) {
+		return parts, false
+	}
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return parts, false
+		}
+		parts[i] = n
+	}
+	return parts, true
+}
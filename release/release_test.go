@@ -0,0 +1,80 @@
+package release
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestNewer verifies version ordering and that non-release versions never compare newer
+func TestNewer(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"v0.4.0", "v0.3.9", true},
+		{"v1.0.0", "v0.10.0", true},
+		{"v0.10.0", "v0.9.0", true},
+		{"v0.3.0", "v0.3.0", false},
+		{"v0.3.0", "v0.4.0", false},
+		{"0.4", "v0.3.1", true},
+		{"v0.4.0", "v0.4.0-3-gabc1234-dirty", false},
+		{"v0.5.0", "v0.4.0-3-gabc1234", true},
+		{"v0.4.0", "dev", false},
+		{"nightly", "v0.1.0", false},
+	}
+	for _, tt := range tests {
+		if got := Newer(tt.a, tt.b); got != tt.want {
+			t.Errorf("Newer(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// TestCheck verifies an update is returned only when the feed has a newer release
+func TestCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"tag_name":"v0.4.0","body":"- New things","html_url":"https://example.com/v0.4.0"}`))
+	}))
+	defer server.Close()
+
+	update, err := Check(context.Background(), server.URL, "v0.3.0")
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if update == nil || update.Version != "v0.4.0" || update.Notes != "- New things" || update.URL != "https://example.com/v0.4.0" {
+		t.Errorf("Expected the v0.4.0 update, got %+v", update)
+	}
+
+	if update, err := Check(context.Background(), server.URL, "v0.4.0"); err != nil || update != nil {
+		t.Errorf("Expected no update when current, got %+v, %v", update, err)
+	}
+}
+
+// TestCheckSkipsDevBuilds verifies development builds don't contact the feed
+func TestCheckSkipsDevBuilds(t *testing.T) {
+	update, err := Check(context.Background(), "http://127.0.0.1:0/unreachable", "dev")
+	if err != nil || update != nil {
+		t.Errorf("Expected dev builds to skip the check, got %+v, %v", update, err)
+	}
+}
+
+// TestCheckFeedError verifies a failing feed is reported
+func TestCheckFeedError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "rate limited", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	if _, err := Check(context.Background(), server.URL, "v0.3.0"); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Expected the feed status in the error, got %v", err)
+	}
+}
+
+// TestChangelogBundled verifies the release notes are embedded
+func TestChangelogBundled(t *testing.T) {
+	if !strings.HasPrefix(Changelog, "# Changelog") {
+		t.Errorf("Expected the bundled changelog, got %q", Changelog[:min(len(Changelog), 40)])
+	}
+}
@@ -1,5 +1,5 @@
 use libc::c_char;
-use serde::Serialize;
+use serde::{Deserialize, Serialize};
 use std::ffi::{CStr, CString};
 use std::path::Path;
 use std::ptr;
@@ -14,6 +14,11 @@ use jj_lib::repo::{ReadonlyRepo, Repo};
 use jj_lib::settings::UserSettings;
 use jj_lib::workspace::{default_working_copy_factories, Workspace};
 
+/// Version of the C API in bridge.h and the jj_call protocol. Bump together
+/// with ffi.APIVersion in jj/internal/ffi/ffi.go whenever a signature, op
+/// name or parameter changes.
+const BRIDGE_API_VERSION: u32 = 7;
+
 /// Opaque handle to a jj repository
 pub struct RepoHandle {
     repo: Arc<ReadonlyRepo>,
@@ -21,20 +26,23 @@ pub struct RepoHandle {
     repo_root: String, // Directory where repo was opened
 }
 
-/// Result structure for FFI calls
-#[repr(C)]
-pub struct JjResult {
-    /// JSON data on success, NULL on error
-    data: *mut c_char,
-    /// Error message on failure, NULL on success
-    error: *mut c_char,
-}
-
 /// Branch information for serialization
 #[derive(Serialize)]
 struct BranchInfo {
     name: String,
     is_local: bool,
+    /// Remote whose bookmark this one tracks, empty when none
+    remote: String,
+    /// Commits not pushed to the remote yet, and those only on the remote
+    ahead: usize,
+    behind: usize,
+}
+
+/// Tag information for serialization
+#[derive(Serialize)]
+struct TagInfo {
+    name: String,
+    commit_id: String,
 }
 
 /// Workspace information for serialization
@@ -79,27 +87,23 @@ struct RevisionInfo {
     author: String,
     timestamp: String,
     bookmarks: Vec<String>,
+    tags: Vec<String>,
     git_head: bool,
     is_working_copy: bool,
     workspace_name: Option<String>,
     is_root: bool,
     parents: Vec<String>,
+    divergent: bool,
+    hidden: bool,
+    immutable: bool,
+    signed: bool,
 }
 
-impl JjResult {
-    fn success(data: String) -> Self {
-        JjResult {
-            data: CString::new(data).unwrap().into_raw(),
-            error: ptr::null_mut(),
-        }
-    }
-
-    fn error(msg: String) -> Self {
-        JjResult {
-            data: ptr::null_mut(),
-            error: CString::new(msg).unwrap().into_raw(),
-        }
-    }
+/// Signature state of a commit for serialization
+#[derive(Serialize)]
+struct SignatureInfo {
+    id: String,
+    signed: bool,
 }
 
 fn create_user_settings() -> Result<UserSettings, String> {
@@ -186,6 +190,12 @@ fn create_user_settings() -> Result<UserSettings, String> {
     UserSettings::from_config(config).map_err(|e| e.to_string())
 }
 
+/// Return the bridge's C API version so the Go side can detect a stale library
+#[no_mangle]
+pub extern "C" fn jj_bridge_api_version() -> u32 {
+    BRIDGE_API_VERSION
+}
+
 /// Open a jj repository at the given path
 /// Returns NULL on error (check stderr)
 #[no_mangle]
@@ -242,43 +252,76 @@ pub extern "C" fn jj_open_repo(path: *const c_char) -> *mut RepoHandle {
 }
 
 /// List branches in the repository
-/// Returns JjResult with JSON array of branch names on success
-#[no_mangle]
-pub extern "C" fn jj_list_branches(handle: *mut RepoHandle) -> JjResult {
-    let handle = unsafe {
-        if handle.is_null() {
-            return JjResult::error("null repo handle".to_string());
-        }
-        &*handle
-    };
-
+fn list_branches(handle: &RepoHandle) -> Result<Vec<BranchInfo>, String> {
     let mut branches = Vec::new();
 
-    // Get local branches (bookmarks in jj terminology) from the view
-    for (name, _target) in handle.repo.view().local_bookmarks() {
+    // Get local branches (bookmarks in jj terminology) from the view,
+    // compared with the first remote bookmark each one tracks
+    for (name, target) in handle.repo.view().local_bookmarks() {
+        let tracked = handle.repo.view().all_remote_bookmarks().find(|(symbol, remote_ref)| {
+            symbol.name == name && remote_ref.is_tracked() && symbol.remote.as_str() != "git"
+        });
+        let (remote, ahead, behind) = match tracked {
+            Some((symbol, remote_ref)) => {
+                let local: Vec<jj_lib::backend::CommitId> = target.added_ids().cloned().collect();
+                let pushed: Vec<jj_lib::backend::CommitId> = remote_ref.target.added_ids().cloned().collect();
+                (
+                    symbol.remote.as_str().to_string(),
+                    count_range(handle, &pushed, &local),
+                    count_range(handle, &local, &pushed),
+                )
+            }
+            None => (String::new(), 0, 0),
+        };
         branches.push(BranchInfo {
             name: name.as_str().to_string(),
             is_local: true,
+            remote,
+            ahead,
+            behind,
         });
     }
 
-    match serde_json::to_string(&branches) {
-        Ok(json) => JjResult::success(json),
-        Err(e) => JjResult::error(format!("JSON serialization failed: {}", e)),
+    Ok(branches)
+}
+
+/// Counts the commits reachable from heads but not from roots (roots..heads),
+/// zero if the revset can't be evaluated
+fn count_range(
+    handle: &RepoHandle,
+    roots: &[jj_lib::backend::CommitId],
+    heads: &[jj_lib::backend::CommitId],
+) -> usize {
+    use jj_lib::revset::RevsetExpression;
+
+    RevsetExpression::commits(roots.to_vec())
+        .range(&RevsetExpression::commits(heads.to_vec()))
+        .evaluate(handle.repo.as_ref())
+        .map(|revset| revset.iter().filter(|id| id.is_ok()).count())
+        .unwrap_or(0)
+}
+
+/// List tags in the repository
+fn list_tags(handle: &RepoHandle) -> Result<Vec<TagInfo>, String> {
+    let mut tags = Vec::new();
+
+    for (name, target) in handle.repo.view().local_tags() {
+        // A conflicted tag points at several commits; list the first
+        let commit_id = match target.added_ids().next() {
+            Some(id) => id.hex()[..12].to_string(),
+            None => continue,
+        };
+        tags.push(TagInfo {
+            name: name.as_str().to_string(),
+            commit_id,
+        });
     }
+
+    Ok(tags)
 }
 
 /// List workspaces in the repository
-/// Returns JjResult with JSON array of workspace info on success
-#[no_mangle]
-pub extern "C" fn jj_list_workspaces(handle: *mut RepoHandle) -> JjResult {
-    let handle = unsafe {
-        if handle.is_null() {
-            return JjResult::error("null repo handle".to_string());
-        }
-        &*handle
-    };
-
+fn list_workspaces(handle: &RepoHandle) -> Result<Vec<WorkspaceInfo>, String> {
     let mut workspaces = Vec::new();
 
     // Get the parent directory of current workspace for computing sibling paths
@@ -313,10 +356,7 @@ pub extern "C" fn jj_list_workspaces(handle: *mut RepoHandle) -> JjResult {
     // Sort workspaces by name for consistent ordering
     workspaces.sort_by(|a, b| a.name.cmp(&b.name));
 
-    match serde_json::to_string(&workspaces) {
-        Ok(json) => JjResult::success(json),
-        Err(e) => JjResult::error(format!("JSON serialization failed: {}", e)),
-    }
+    Ok(workspaces)
 }
 
 /// Get the parent commit ID(s) of the current workspace's working copy
@@ -393,70 +433,31 @@ fn resolve_revision_specs(handle: &RepoHandle, specs: &[String]) -> Result<Vec<j
 
 /// Add a new workspace at the given path
 /// Creates directory if needed, initializes workspace with existing repo
-/// If revision_ids is NULL or empty, the new workspace will be created as a sibling
+/// If revision_specs is empty, the new workspace will be created as a sibling
 /// of the current workspace's working copy (sharing the same parent commits).
-/// If revision_ids is provided (comma-separated), those commits become the parents.
-/// Returns JjResult with empty success or error message
-#[no_mangle]
-pub extern "C" fn jj_workspace_add(
-    handle: *mut RepoHandle,
-    destination_path: *const c_char,
-    workspace_name: *const c_char,
-    revision_ids: *const c_char,
-) -> JjResult {
+/// Otherwise those commits become the parents.
+fn workspace_add(
+    handle: &mut RepoHandle,
+    destination_path: &str,
+    workspace_name: Option<&str>,
+    revision_specs: &[String],
+) -> Result<(), String> {
     use jj_lib::ref_name::WorkspaceNameBuf;
     use jj_lib::local_working_copy::LocalWorkingCopyFactory;
     use std::fs;
 
-    let handle = unsafe {
-        if handle.is_null() {
-            return JjResult::error("null repo handle".to_string());
-        }
-        &mut *handle
-    };
-
-    let dest_path_str = unsafe {
-        if destination_path.is_null() {
-            return JjResult::error("null destination_path".to_string());
-        }
-        match CStr::from_ptr(destination_path).to_str() {
-            Ok(s) => s,
-            Err(e) => return JjResult::error(format!("invalid destination_path UTF-8: {}", e)),
-        }
-    };
-
-    // Parse revision IDs (comma-separated or NULL for default)
-    let revision_specs: Vec<String> = unsafe {
-        if revision_ids.is_null() {
-            Vec::new()  // Empty means "use default" (parent of current @)
-        } else {
-            match CStr::from_ptr(revision_ids).to_str() {
-                Ok(s) if s.is_empty() => Vec::new(),
-                Ok(s) => s.split(',').map(|r| r.trim().to_string()).filter(|s| !s.is_empty()).collect(),
-                Err(e) => return JjResult::error(format!("invalid revision_ids UTF-8: {}", e)),
-            }
-        }
-    };
-
     // Get workspace name - use provided name or derive from path basename
-    let ws_name = unsafe {
-        if workspace_name.is_null() {
-            // Derive from destination path basename
-            Path::new(dest_path_str)
-                .file_name()
-                .and_then(|n| n.to_str())
-                .unwrap_or("default")
-                .to_string()
-        } else {
-            match CStr::from_ptr(workspace_name).to_str() {
-                Ok(s) => s.to_string(),
-                Err(e) => return JjResult::error(format!("invalid workspace_name UTF-8: {}", e)),
-            }
-        }
+    let ws_name = match workspace_name {
+        Some(name) => name.to_string(),
+        None => Path::new(destination_path)
+            .file_name()
+            .and_then(|n| n.to_str())
+            .unwrap_or("default")
+            .to_string(),
     };
 
     // Convert to absolute path
-    let dest_path = Path::new(dest_path_str);
+    let dest_path = Path::new(destination_path);
     let abs_dest_path = if dest_path.is_absolute() {
         dest_path.to_path_buf()
     } else {
@@ -470,7 +471,7 @@ pub extern "C" fn jj_workspace_add(
     // Validate path: must not exist OR be an empty directory
     if abs_dest_path.exists() {
         if abs_dest_path.is_file() {
-            return JjResult::error(format!("Path exists and is a file: {}", abs_dest_path.display()));
+            return Err(format!("Path exists and is a file: {}", abs_dest_path.display()));
         }
         // Check if directory is empty or only contains .jj
         match fs::read_dir(&abs_dest_path) {
@@ -480,14 +481,14 @@ pub extern "C" fn jj_workspace_add(
                     .filter(|e| e.file_name() != ".jj")
                     .collect();
                 if !non_jj_entries.is_empty() {
-                    return JjResult::error(format!(
+                    return Err(format!(
                         "Directory is not empty: {}",
                         abs_dest_path.display()
                     ));
                 }
             }
             Err(e) => {
-                return JjResult::error(format!(
+                return Err(format!(
                     "Cannot read directory {}: {}",
                     abs_dest_path.display(),
                     e
@@ -497,7 +498,7 @@ pub extern "C" fn jj_workspace_add(
     } else {
         // Create the directory
         if let Err(e) = fs::create_dir_all(&abs_dest_path) {
-            return JjResult::error(format!(
+            return Err(format!(
                 "Failed to create directory {}: {}",
                 abs_dest_path.display(),
                 e
@@ -508,16 +509,10 @@ pub extern "C" fn jj_workspace_add(
     // Determine parent commit(s) for new workspace's working copy
     let parent_ids: Vec<jj_lib::backend::CommitId> = if revision_specs.is_empty() {
         // Default: use parent(s) of current workspace's working copy
-        match get_current_wc_parent_ids(handle) {
-            Ok(ids) => ids,
-            Err(e) => return JjResult::error(e),
-        }
+        get_current_wc_parent_ids(handle)?
     } else {
         // Explicit: resolve the specified revision(s)
-        match resolve_revision_specs(handle, &revision_specs) {
-            Ok(ids) => ids,
-            Err(e) => return JjResult::error(e),
-        }
+        resolve_revision_specs(handle, revision_specs)?
     };
 
     // Handle edge case: if no parents (root commit scenario), use root
@@ -552,7 +547,7 @@ pub extern "C" fn jj_workspace_add(
                 .collect();
 
             if parents.is_empty() {
-                return JjResult::error("Failed to resolve parent commits".to_string());
+                return Err("Failed to resolve parent commits".to_string());
             }
 
             // Get the tree from first parent
@@ -566,12 +561,12 @@ pub extern "C" fn jj_workspace_add(
 
             let new_commit = match new_commit {
                 Ok(c) => c,
-                Err(e) => return JjResult::error(format!("Failed to write commit: {}", e)),
+                Err(e) => return Err(format!("Failed to write commit: {}", e)),
             };
 
             // Set this as the workspace's working copy
             if let Err(e) = tx.repo_mut().set_wc_commit(workspace_name_buf.clone(), new_commit.id().clone()) {
-                return JjResult::error(format!("Failed to set working copy: {:?}", e));
+                return Err(format!("Failed to set working copy: {:?}", e));
             }
 
             // Commit the transaction
@@ -586,45 +581,23 @@ pub extern "C" fn jj_workspace_add(
                 Ok(final_repo) => {
                     handle.repo = final_repo;
                     let _ = workspace; // Workspace is consumed
-                    JjResult::success("".to_string())
+                    Ok(())
                 }
-                Err(e) => JjResult::error(format!("Failed to commit transaction: {}", e)),
+                Err(e) => Err(format!("Failed to commit transaction: {}", e)),
             }
         }
-        Err(e) => JjResult::error(format!("Failed to create workspace: {:?}", e)),
+        Err(e) => Err(format!("Failed to create workspace: {:?}", e)),
     }
 }
 
 /// Forget a workspace by name
 /// Removes workspace tracking from repo (does not delete files on disk)
-/// Returns JjResult with empty success or error message
-#[no_mangle]
-pub extern "C" fn jj_workspace_forget(
-    handle: *mut RepoHandle,
-    workspace_name: *const c_char,
-) -> JjResult {
+fn workspace_forget(handle: &mut RepoHandle, ws_name: &str) -> Result<(), String> {
     use jj_lib::ref_name::WorkspaceNameBuf;
 
-    let handle = unsafe {
-        if handle.is_null() {
-            return JjResult::error("null repo handle".to_string());
-        }
-        &mut *handle
-    };
-
-    let ws_name = unsafe {
-        if workspace_name.is_null() {
-            return JjResult::error("null workspace_name".to_string());
-        }
-        match CStr::from_ptr(workspace_name).to_str() {
-            Ok(s) => s,
-            Err(e) => return JjResult::error(format!("invalid workspace_name UTF-8: {}", e)),
-        }
-    };
-
     // Cannot forget current workspace
     if ws_name == handle.current_workspace {
-        return JjResult::error("Cannot forget current workspace".to_string());
+        return Err("Cannot forget current workspace".to_string());
     }
 
     // Check if workspace exists by iterating through wc_commit_ids
@@ -633,7 +606,7 @@ pub extern "C" fn jj_workspace_forget(
         .any(|(ws_id, _)| ws_id.as_str() == ws_name);
 
     if !workspace_exists {
-        return JjResult::error(format!("Workspace not found: {}", ws_name));
+        return Err(format!("Workspace not found: {}", ws_name));
     }
 
     // Create workspace ID using WorkspaceNameBuf
@@ -644,7 +617,7 @@ pub extern "C" fn jj_workspace_forget(
 
     // Remove the working copy commit for this workspace
     if let Err(e) = tx.repo_mut().remove_wc_commit(&workspace_name_buf) {
-        return JjResult::error(format!("Failed to remove workspace: {:?}", e));
+        return Err(format!("Failed to remove workspace: {:?}", e));
     }
 
     // Commit the transaction
@@ -652,23 +625,14 @@ pub extern "C" fn jj_workspace_forget(
     match tx.commit(&description) {
         Ok(new_repo) => {
             handle.repo = new_repo;
-            JjResult::success("".to_string())
+            Ok(())
         }
-        Err(e) => JjResult::error(format!("Failed to commit transaction: {}", e)),
+        Err(e) => Err(format!("Failed to commit transaction: {}", e)),
     }
 }
 
 /// Get file changes in the current working copy
-/// Returns JjResult with JSON array of file change info on success
-#[no_mangle]
-pub extern "C" fn jj_get_working_copy_changes(handle: *mut RepoHandle) -> JjResult {
-    let handle = unsafe {
-        if handle.is_null() {
-            return JjResult::error("null repo handle".to_string());
-        }
-        &*handle
-    };
-
+fn get_working_copy_changes(handle: &RepoHandle) -> Result<Vec<FileChangeInfo>, String> {
     // Find the current workspace's working copy commit
     let wc_commit_id = match handle
         .repo
@@ -678,25 +642,25 @@ pub extern "C" fn jj_get_working_copy_changes(handle: *mut RepoHandle) -> JjResu
         .find(|(ws_id, _)| ws_id.as_str() == handle.current_workspace)
     {
         Some((_, commit_id)) => commit_id.clone(),
-        None => return JjResult::error("No working copy found for current workspace".to_string()),
+        None => return Err("No working copy found for current workspace".to_string()),
     };
 
     // Get the working copy commit
     let wc_commit: Commit = match handle.repo.store().get_commit(&wc_commit_id) {
         Ok(commit) => commit,
-        Err(e) => return JjResult::error(format!("Failed to get working copy commit: {}", e)),
+        Err(e) => return Err(format!("Failed to get working copy commit: {}", e)),
     };
 
     // Get the parent commit(s) - use first parent for diff
     let parent_ids = wc_commit.parent_ids();
     if parent_ids.is_empty() {
         // Root commit - compare against empty tree
-        return JjResult::success("[]".to_string());
+        return Ok(Vec::new());
     }
 
     let parent_commit: Commit = match handle.repo.store().get_commit(&parent_ids[0]) {
         Ok(commit) => commit,
-        Err(e) => return JjResult::error(format!("Failed to get parent commit: {}", e)),
+        Err(e) => return Err(format!("Failed to get parent commit: {}", e)),
     };
 
     // Get trees for comparison
@@ -736,25 +700,13 @@ pub extern "C" fn jj_get_working_copy_changes(handle: *mut RepoHandle) -> JjResu
         }
     });
 
-    match serde_json::to_string(&changes) {
-        Ok(json) => JjResult::success(json),
-        Err(e) => JjResult::error(format!("JSON serialization failed: {}", e)),
-    }
+    Ok(changes)
 }
 
 /// List recent operations in the repository
-/// Returns JjResult with JSON array of operation info on success
-#[no_mangle]
-pub extern "C" fn jj_list_operations(handle: *mut RepoHandle) -> JjResult {
+fn list_operations(handle: &RepoHandle) -> Result<Vec<OperationInfo>, String> {
     use jj_lib::operation::Operation;
 
-    let handle = unsafe {
-        if handle.is_null() {
-            return JjResult::error("null repo handle".to_string());
-        }
-        &*handle
-    };
-
     let mut operations = Vec::new();
     let op_store = handle.repo.op_store();
     let current_op = handle.repo.operation();
@@ -797,24 +749,71 @@ pub extern "C" fn jj_list_operations(handle: *mut RepoHandle) -> JjResult {
         }
     }
 
-    match serde_json::to_string(&operations) {
-        Ok(json) => JjResult::success(json),
-        Err(e) => JjResult::error(format!("JSON serialization failed: {}", e)),
-    }
+    Ok(operations)
 }
 
-/// Get revision log for the repository
-/// Returns JjResult with JSON array of revision info on success
-#[no_mangle]
-pub extern "C" fn jj_get_log(handle: *mut RepoHandle) -> JjResult {
+/// Report which of the given commits carry a cryptographic signature.
+/// revision_ids are commit ID prefixes; each is echoed back as the id of
+/// its entry. The signature isn't verified, which
+/// would run the signing backend per commit. Commits not reachable from a
+/// working copy or local bookmark within MAX_REVISION_SEARCH_DEPTH are
+/// left out.
+fn get_signatures(handle: &RepoHandle, revision_ids: &[String]) -> Result<Vec<SignatureInfo>, String> {
     use std::collections::HashSet;
 
-    let handle = unsafe {
-        if handle.is_null() {
-            return JjResult::error("null repo handle".to_string());
+    let mut wanted: Vec<&str> = revision_ids
+        .iter()
+        .map(|s| s.trim())
+        .filter(|s| !s.is_empty())
+        .collect();
+
+    // Walk once from every working copy and local bookmark, matching
+    // prefixes as commits are reached
+    let mut to_visit: Vec<jj_lib::backend::CommitId> = Vec::new();
+    for (_ws_id, commit_id) in handle.repo.view().wc_commit_ids() {
+        to_visit.push(commit_id.clone());
+    }
+    for (_name, target) in handle.repo.view().local_bookmarks() {
+        to_visit.extend(target.added_ids().cloned());
+    }
+
+    let mut signatures = Vec::new();
+    let mut visited: HashSet<String> = HashSet::new();
+    while let Some(commit_id) = to_visit.pop() {
+        if wanted.is_empty() || visited.len() >= MAX_REVISION_SEARCH_DEPTH {
+            break;
         }
-        &*handle
-    };
+
+        let hex = commit_id.hex();
+        if !visited.insert(hex.clone()) {
+            continue;
+        }
+        let commit = match handle.repo.store().get_commit(&commit_id) {
+            Ok(c) => c,
+            Err(_) => continue,
+        };
+
+        if let Some(pos) = wanted.iter().position(|spec| hex.starts_with(spec)) {
+            let spec = wanted.swap_remove(pos);
+            signatures.push(SignatureInfo {
+                id: spec.to_string(),
+                signed: commit.is_signed(),
+            });
+        }
+
+        for parent_id in commit.parent_ids() {
+            if !visited.contains(&parent_id.hex()) {
+                to_visit.push(parent_id.clone());
+            }
+        }
+    }
+
+    Ok(signatures)
+}
+
+/// Get revision log for the repository
+fn get_log(handle: &RepoHandle) -> Result<Vec<RevisionInfo>, String> {
+    use std::collections::HashSet;
 
     // Build a map of workspace commit IDs to workspace names
     let mut workspace_commits: std::collections::HashMap<String, String> =
@@ -826,6 +825,26 @@ pub extern "C" fn jj_get_log(handle: *mut RepoHandle) -> JjResult {
     // Get the root commit ID
     let root_commit_id = handle.repo.store().root_commit_id().hex();
 
+    // Commits reachable from remote bookmarks have been pushed and are
+    // treated as immutable, the same heuristic set_bookmark uses
+    let mut pushed: HashSet<String> = HashSet::new();
+    let mut to_check: Vec<jj_lib::backend::CommitId> = Vec::new();
+    for (_, remote_ref) in handle.repo.view().all_remote_bookmarks() {
+        to_check.extend(remote_ref.target.added_ids().cloned());
+    }
+    let max_pushed = 1000;
+    while let Some(commit_id) = to_check.pop() {
+        if pushed.len() >= max_pushed {
+            break;
+        }
+        if !pushed.insert(commit_id.hex()) {
+            continue;
+        }
+        if let Ok(c) = handle.repo.store().get_commit(&commit_id) {
+            to_check.extend(c.parent_ids().iter().cloned());
+        }
+    }
+
     let mut revisions = Vec::new();
     let mut visited: HashSet<String> = HashSet::new();
     let max_revisions = 100;
@@ -882,6 +901,14 @@ pub extern "C" fn jj_get_log(handle: *mut RepoHandle) -> JjResult {
             }
         }
 
+        // Get tags for this commit
+        let mut tags: Vec<String> = Vec::new();
+        for (name, target) in handle.repo.view().local_tags() {
+            if target.added_ids().any(|id| id == commit.id()) {
+                tags.push(name.as_str().to_string());
+            }
+        }
+
         // Check if this commit is at git HEAD
         let git_head_ref = handle.repo.view().git_head();
         if git_head_ref.added_ids().any(|id| id == commit.id()) {
@@ -895,11 +922,18 @@ pub extern "C" fn jj_get_log(handle: *mut RepoHandle) -> JjResult {
             author,
             timestamp,
             bookmarks,
+            tags,
             git_head,
             is_working_copy,
             workspace_name,
             is_root,
             parents: parents.iter().map(|p| p[..12].to_string()).collect(),
+            divergent: false, // Set below, once every revision is known
+            // The walk only follows parents from working copies, so it
+            // never reaches a hidden commit
+            hidden: false,
+            immutable: is_root || pushed.contains(&commit_id_hex),
+            signed: commit.is_signed(),
         });
 
         // Add parent commits to visit
@@ -914,23 +948,21 @@ pub extern "C" fn jj_get_log(handle: *mut RepoHandle) -> JjResult {
         }
     }
 
-    match serde_json::to_string(&revisions) {
-        Ok(json) => JjResult::success(json),
-        Err(e) => JjResult::error(format!("JSON serialization failed: {}", e)),
+    // A change with more than one visible commit is divergent
+    let mut change_counts: std::collections::HashMap<String, usize> =
+        std::collections::HashMap::new();
+    for rev in &revisions {
+        *change_counts.entry(rev.change_id.clone()).or_insert(0) += 1;
+    }
+    for rev in &mut revisions {
+        rev.divergent = change_counts[&rev.change_id] > 1;
     }
-}
 
-/// Get diff for the working copy (changes from parent)
-/// Returns JjResult with unified diff string on success
-#[no_mangle]
-pub extern "C" fn jj_get_diff(handle: *mut RepoHandle) -> JjResult {
-    let handle = unsafe {
-        if handle.is_null() {
-            return JjResult::error("null repo handle".to_string());
-        }
-        &*handle
-    };
+    Ok(revisions)
+}
 
+/// Get the working copy's unified diff (changes from parent)
+fn get_diff(handle: &RepoHandle) -> Result<String, String> {
     // Find the current workspace's working copy commit
     let wc_commit_id = match handle
         .repo
@@ -940,24 +972,24 @@ pub extern "C" fn jj_get_diff(handle: *mut RepoHandle) -> JjResult {
         .find(|(ws_id, _)| ws_id.as_str() == handle.current_workspace)
     {
         Some((_, commit_id)) => commit_id.clone(),
-        None => return JjResult::error("No working copy found for current workspace".to_string()),
+        None => return Err("No working copy found for current workspace".to_string()),
     };
 
     // Get the working copy commit
     let wc_commit: Commit = match handle.repo.store().get_commit(&wc_commit_id) {
         Ok(commit) => commit,
-        Err(e) => return JjResult::error(format!("Failed to get working copy commit: {}", e)),
+        Err(e) => return Err(format!("Failed to get working copy commit: {}", e)),
     };
 
     // Get the parent commit(s)
     let parent_ids = wc_commit.parent_ids();
     if parent_ids.is_empty() {
-        return JjResult::success("".to_string());
+        return Ok(String::new());
     }
 
     let parent_commit: Commit = match handle.repo.store().get_commit(&parent_ids[0]) {
         Ok(commit) => commit,
-        Err(e) => return JjResult::error(format!("Failed to get parent commit: {}", e)),
+        Err(e) => return Err(format!("Failed to get parent commit: {}", e)),
     };
 
     // Get trees for comparison
@@ -1026,7 +1058,7 @@ pub extern "C" fn jj_get_diff(handle: *mut RepoHandle) -> JjResult {
         }
     });
 
-    JjResult::success(diff_output)
+    Ok(diff_output)
 }
 
 fn get_file_content(
@@ -1097,27 +1129,8 @@ fn generate_unified_diff(before: &str, after: &str) -> String {
     result
 }
 
-/// Get diff for a specific file in the working copy
-/// Returns JjResult with unified diff string on success
-#[no_mangle]
-pub extern "C" fn jj_get_file_diff(handle: *mut RepoHandle, path: *const c_char) -> JjResult {
-    let handle = unsafe {
-        if handle.is_null() {
-            return JjResult::error("null repo handle".to_string());
-        }
-        &*handle
-    };
-
-    let path_str = unsafe {
-        if path.is_null() {
-            return JjResult::error("null path".to_string());
-        }
-        match CStr::from_ptr(path).to_str() {
-            Ok(s) => s,
-            Err(e) => return JjResult::error(format!("invalid UTF-8: {}", e)),
-        }
-    };
-
+/// Get the unified diff for a specific file in the working copy
+fn get_file_diff(handle: &RepoHandle, path_str: &str) -> Result<String, String> {
     // Find the current workspace's working copy commit
     let wc_commit_id = match handle
         .repo
@@ -1127,24 +1140,24 @@ pub extern "C" fn jj_get_file_diff(handle: *mut RepoHandle, path: *const c_char)
         .find(|(ws_id, _)| ws_id.as_str() == handle.current_workspace)
     {
         Some((_, commit_id)) => commit_id.clone(),
-        None => return JjResult::error("No working copy found for current workspace".to_string()),
+        None => return Err("No working copy found for current workspace".to_string()),
     };
 
     // Get the working copy commit
     let wc_commit: Commit = match handle.repo.store().get_commit(&wc_commit_id) {
         Ok(commit) => commit,
-        Err(e) => return JjResult::error(format!("Failed to get working copy commit: {}", e)),
+        Err(e) => return Err(format!("Failed to get working copy commit: {}", e)),
     };
 
     // Get the parent commit(s)
     let parent_ids = wc_commit.parent_ids();
     if parent_ids.is_empty() {
-        return JjResult::success("".to_string());
+        return Ok(String::new());
     }
 
     let parent_commit: Commit = match handle.repo.store().get_commit(&parent_ids[0]) {
         Ok(commit) => commit,
-        Err(e) => return JjResult::error(format!("Failed to get parent commit: {}", e)),
+        Err(e) => return Err(format!("Failed to get parent commit: {}", e)),
     };
 
     // Get trees for comparison
@@ -1154,7 +1167,7 @@ pub extern "C" fn jj_get_file_diff(handle: *mut RepoHandle, path: *const c_char)
     // Build a matcher for just this file
     let repo_path = match jj_lib::repo_path::RepoPathBuf::from_internal_string(path_str) {
         Ok(p) => p,
-        Err(e) => return JjResult::error(format!("Invalid path: {:?}", e)),
+        Err(e) => return Err(format!("Invalid path: {:?}", e)),
     };
     let matcher = jj_lib::matchers::FilesMatcher::new(vec![repo_path]);
 
@@ -1213,33 +1226,11 @@ pub extern "C" fn jj_get_file_diff(handle: *mut RepoHandle, path: *const c_char)
         }
     });
 
-    JjResult::success(diff_output)
+    Ok(diff_output)
 }
 
 /// Get the before/after content for a file in the working copy
-/// Returns JjResult with JSON containing before and after content
-#[no_mangle]
-pub extern "C" fn jj_get_file_contents(
-    handle: *mut RepoHandle,
-    path: *const c_char,
-) -> JjResult {
-    let handle = unsafe {
-        if handle.is_null() {
-            return JjResult::error("null repo handle".to_string());
-        }
-        &*handle
-    };
-
-    let path_str = unsafe {
-        if path.is_null() {
-            return JjResult::error("null path".to_string());
-        }
-        match CStr::from_ptr(path).to_str() {
-            Ok(s) => s,
-            Err(e) => return JjResult::error(format!("invalid UTF-8: {}", e)),
-        }
-    };
-
+fn get_file_contents(handle: &RepoHandle, path_str: &str) -> Result<FileContents, String> {
     // Find the current workspace's working copy commit
     let wc_commit_id = match handle
         .repo
@@ -1249,13 +1240,13 @@ pub extern "C" fn jj_get_file_contents(
         .find(|(ws_id, _)| ws_id.as_str() == handle.current_workspace)
     {
         Some((_, commit_id)) => commit_id.clone(),
-        None => return JjResult::error("No working copy found for current workspace".to_string()),
+        None => return Err("No working copy found for current workspace".to_string()),
     };
 
     // Get the working copy commit
     let wc_commit: Commit = match handle.repo.store().get_commit(&wc_commit_id) {
         Ok(commit) => commit,
-        Err(e) => return JjResult::error(format!("Failed to get working copy commit: {}", e)),
+        Err(e) => return Err(format!("Failed to get working copy commit: {}", e)),
     };
 
     // Get the parent commit(s)
@@ -1267,21 +1258,18 @@ pub extern "C" fn jj_get_file_contents(
             after: String::new(),
             path: path_str.to_string(),
         };
-        return match serde_json::to_string(&contents) {
-            Ok(json) => JjResult::success(json),
-            Err(e) => JjResult::error(format!("JSON serialization failed: {}", e)),
-        };
+        return Ok(contents);
     }
 
     let parent_commit: Commit = match handle.repo.store().get_commit(&parent_ids[0]) {
         Ok(commit) => commit,
-        Err(e) => return JjResult::error(format!("Failed to get parent commit: {}", e)),
+        Err(e) => return Err(format!("Failed to get parent commit: {}", e)),
     };
 
     // Build a repo path
     let repo_path = match jj_lib::repo_path::RepoPathBuf::from_internal_string(path_str) {
         Ok(p) => p,
-        Err(e) => return JjResult::error(format!("Invalid path: {:?}", e)),
+        Err(e) => return Err(format!("Invalid path: {:?}", e)),
     };
 
     // Get trees for comparison
@@ -1291,11 +1279,11 @@ pub extern "C" fn jj_get_file_contents(
     // Get the file content at this path from both trees
     let before_value = match parent_tree.path_value(&repo_path) {
         Ok(v) => v,
-        Err(e) => return JjResult::error(format!("Failed to get before value: {}", e)),
+        Err(e) => return Err(format!("Failed to get before value: {}", e)),
     };
     let after_value = match wc_tree.path_value(&repo_path) {
         Ok(v) => v,
-        Err(e) => return JjResult::error(format!("Failed to get after value: {}", e)),
+        Err(e) => return Err(format!("Failed to get after value: {}", e)),
     };
 
     let before_content = get_file_content(&handle.repo, &before_value);
@@ -1307,33 +1295,11 @@ pub extern "C" fn jj_get_file_contents(
         path: path_str.to_string(),
     };
 
-    match serde_json::to_string(&contents) {
-        Ok(json) => JjResult::success(json),
-        Err(e) => JjResult::error(format!("JSON serialization failed: {}", e)),
-    }
+    Ok(contents)
 }
 
-/// Get diff for a revision compared to its parent
-/// Returns JjResult with unified diff string on success
-#[no_mangle]
-pub extern "C" fn jj_get_revision_diff(handle: *mut RepoHandle, revision_id: *const c_char) -> JjResult {
-    let handle = unsafe {
-        if handle.is_null() {
-            return JjResult::error("null repo handle".to_string());
-        }
-        &*handle
-    };
-
-    let revision_str = unsafe {
-        if revision_id.is_null() {
-            return JjResult::error("null revision_id".to_string());
-        }
-        match CStr::from_ptr(revision_id).to_str() {
-            Ok(s) => s,
-            Err(e) => return JjResult::error(format!("invalid UTF-8: {}", e)),
-        }
-    };
-
+/// Get the unified diff of a revision compared to its parent
+fn get_revision_diff(handle: &RepoHandle, revision_str: &str) -> Result<String, String> {
     // Find the commit by ID prefix - walk from working copy commits
     let commit = {
         use std::collections::HashSet;
@@ -1375,19 +1341,19 @@ pub extern "C" fn jj_get_revision_diff(handle: *mut RepoHandle, revision_id: *co
 
         match found {
             Some(c) => c,
-            None => return JjResult::error(format!("Revision not found: {}", revision_str)),
+            None => return Err(format!("Revision not found: {}", revision_str)),
         }
     };
 
     // Get the parent commit(s)
     let parent_ids = commit.parent_ids();
     if parent_ids.is_empty() {
-        return JjResult::success("".to_string());
+        return Ok(String::new());
     }
 
     let parent_commit: Commit = match handle.repo.store().get_commit(&parent_ids[0]) {
         Ok(c) => c,
-        Err(e) => return JjResult::error(format!("Failed to get parent commit: {}", e)),
+        Err(e) => return Err(format!("Failed to get parent commit: {}", e)),
     };
 
     // Get trees for comparison
@@ -1450,7 +1416,7 @@ pub extern "C" fn jj_get_revision_diff(handle: *mut RepoHandle, revision_id: *co
         }
     });
 
-    JjResult::success(diff_output)
+    Ok(diff_output)
 }
 
 /// Close a repository handle and free its memory
@@ -1463,19 +1429,219 @@ pub extern "C" fn jj_close_repo(handle: *mut RepoHandle) {
     }
 }
 
-/// Free a JjResult's memory
-#[no_mangle]
-pub extern "C" fn jj_free_result(result: JjResult) {
-    if !result.data.is_null() {
-        unsafe {
-            drop(CString::from_raw(result.data));
+/// A request to jj_call: an operation name and its parameters
+#[derive(Deserialize)]
+struct Request {
+    op: String,
+    #[serde(default)]
+    params: serde_json::Value,
+}
+
+/// jj_call's reply: result on success, error on failure
+#[derive(Serialize)]
+struct Response {
+    #[serde(skip_serializing_if = "Option::is_none")]
+    result: Option<serde_json::Value>,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    error: Option<String>,
+}
+
+impl Response {
+    fn error(msg: String) -> Self {
+        Response {
+            result: None,
+            error: Some(msg),
         }
     }
-    if !result.error.is_null() {
-        unsafe {
-            drop(CString::from_raw(result.error));
-        }
+}
+
+/// Params of ops that take one revision
+#[derive(Deserialize)]
+struct RevisionParams {
+    revision_id: String,
+}
+
+/// Params of ops that take several revisions
+#[derive(Deserialize)]
+struct RevisionsParams {
+    #[serde(default, deserialize_with = "list_or_null")]
+    revision_ids: Vec<String>,
+}
+
+/// Params of ops that take a path in the working copy
+#[derive(Deserialize)]
+struct PathParams {
+    path: String,
+}
+
+#[derive(Deserialize)]
+struct SetBookmarkParams {
+    name: String,
+    revision_id: String,
+    #[serde(default)]
+    allow_backwards: bool,
+    #[serde(default)]
+    ignore_immutable: bool,
+}
+
+#[derive(Deserialize)]
+struct WorkspaceAddParams {
+    destination_path: String,
+    /// Derived from the destination when absent or empty
+    #[serde(default)]
+    workspace_name: Option<String>,
+    #[serde(default, deserialize_with = "list_or_null")]
+    revision_ids: Vec<String>,
+}
+
+#[derive(Deserialize)]
+struct WorkspaceForgetParams {
+    workspace_name: String,
+}
+
+#[derive(Deserialize)]
+struct DescribeParams {
+    revision_id: String,
+    message: String,
+}
+
+/// Read a list that may be sent as null, as Go encodes an empty slice
+fn list_or_null<'de, D>(deserializer: D) -> Result<Vec<String>, D::Error>
+where
+    D: serde::Deserializer<'de>,
+{
+    Ok(Option::<Vec<String>>::deserialize(deserializer)?.unwrap_or_default())
+}
+
+/// Decode an op's params
+fn parse<T: serde::de::DeserializeOwned>(params: serde_json::Value) -> Result<T, String> {
+    serde_json::from_value(params).map_err(|e| format!("invalid params: {}", e))
+}
+
+/// Encode an op's result; mutations return (), which becomes no result
+fn reply<T: Serialize>(result: T) -> Result<serde_json::Value, String> {
+    serde_json::to_value(result).map_err(|e| format!("JSON serialization failed: {}", e))
+}
+
+/// Ops that write a new operation. They always start from the repository's
+/// current operation, so a handle left behind by jj running elsewhere never
+/// forks the operation log.
+const MUTATING_OPS: &[&str] = &[
+    "set_bookmark",
+    "workspace_add",
+    "workspace_forget",
+    "describe",
+    "new",
+    "abandon",
+    "squash",
+];
+
+/// Move the handle to the repository's current operation, merging
+/// concurrent operation heads as jj does when it loads a repository. The
+/// loaded repo is only swapped in when the operation has moved on.
+fn reload_at_head(handle: &mut RepoHandle) -> Result<(), String> {
+    let repo = handle.repo.reload_at_head()
+        .map_err(|e| format!("Failed to load repo at head: {}", e))?;
+    if repo.op_id() != handle.repo.op_id() {
+        handle.repo = repo;
     }
+    Ok(())
+}
+
+/// Run one operation by name
+fn dispatch(
+    handle: &mut RepoHandle,
+    op: &str,
+    params: serde_json::Value,
+) -> Result<serde_json::Value, String> {
+    if op == "reload" || MUTATING_OPS.contains(&op) {
+        reload_at_head(handle)?;
+    }
+
+    match op {
+        "reload" => reply(()),
+        "list_branches" => reply(list_branches(handle)?),
+        "list_tags" => reply(list_tags(handle)?),
+        "list_workspaces" => reply(list_workspaces(handle)?),
+        "get_working_copy_changes" => reply(get_working_copy_changes(handle)?),
+        "list_operations" => reply(list_operations(handle)?),
+        "get_log" => reply(get_log(handle)?),
+        "get_signatures" => {
+            let p: RevisionsParams = parse(params)?;
+            reply(get_signatures(handle, &p.revision_ids)?)
+        }
+        "get_diff" => reply(get_diff(handle)?),
+        "get_file_diff" => {
+            let p: PathParams = parse(params)?;
+            reply(get_file_diff(handle, &p.path)?)
+        }
+        "get_file_contents" => {
+            let p: PathParams = parse(params)?;
+            reply(get_file_contents(handle, &p.path)?)
+        }
+        "get_revision_diff" => {
+            let p: RevisionParams = parse(params)?;
+            reply(get_revision_diff(handle, &p.revision_id)?)
+        }
+        "set_bookmark" => {
+            let p: SetBookmarkParams = parse(params)?;
+            reply(set_bookmark(handle, &p.name, &p.revision_id, p.allow_backwards, p.ignore_immutable)?)
+        }
+        "workspace_add" => {
+            let p: WorkspaceAddParams = parse(params)?;
+            let name = p.workspace_name.as_deref().filter(|n| !n.is_empty());
+            reply(workspace_add(handle, &p.destination_path, name, &p.revision_ids)?)
+        }
+        "workspace_forget" => {
+            let p: WorkspaceForgetParams = parse(params)?;
+            reply(workspace_forget(handle, &p.workspace_name)?)
+        }
+        "describe" => {
+            let p: DescribeParams = parse(params)?;
+            reply(describe(handle, &p.revision_id, &p.message)?)
+        }
+        "new" => {
+            let p: RevisionParams = parse(params)?;
+            reply(new_change(handle, &p.revision_id)?)
+        }
+        "abandon" => {
+            let p: RevisionsParams = parse(params)?;
+            reply(abandon(handle, &p.revision_ids)?)
+        }
+        "squash" => {
+            let p: RevisionParams = parse(params)?;
+            reply(squash(handle, &p.revision_id)?)
+        }
+        _ => Err(format!("unknown op {}", op)),
+    }
+}
+
+/// Run an operation described by a JSON request, {"op": ..., "params": {...}},
+/// and return a JSON response, {"result": ...} or {"error": "..."}.
+/// The response must be freed with jj_free_string.
+#[no_mangle]
+pub extern "C" fn jj_call(handle: *mut RepoHandle, request: *const c_char) -> *mut c_char {
+    let request: Result<Request, String> = if request.is_null() {
+        Err("null request".to_string())
+    } else {
+        match unsafe { CStr::from_ptr(request) }.to_str() {
+            Ok(s) => serde_json::from_str(s).map_err(|e| format!("invalid request: {}", e)),
+            Err(e) => Err(format!("invalid request UTF-8: {}", e)),
+        }
+    };
+
+    let handle = unsafe { handle.as_mut() }.ok_or_else(|| "null repo handle".to_string());
+    let response = match handle.and_then(|h| request.and_then(|r| dispatch(h, &r.op, r.params))) {
+        Ok(result) => Response {
+            result: Some(result).filter(|r| !r.is_null()),
+            error: None,
+        },
+        Err(e) => Response::error(e),
+    };
+    let json = serde_json::to_string(&response)
+        .unwrap_or_else(|_| r#"{"error":"response serialization failed"}"#.to_string());
+    // serde_json escapes NUL, so the response never contains one
+    CString::new(json).unwrap().into_raw()
 }
 
 /// Free a string allocated by Rust
@@ -1491,55 +1657,24 @@ pub extern "C" fn jj_free_string(s: *mut c_char) {
 /// Set a bookmark to point to a specific revision.
 ///
 /// Parameters:
-/// - handle: repository handle
-/// - name: bookmark name (C string)
-/// - revision_id: target revision ID prefix (C string)
+/// - name: bookmark name
+/// - revision_str: target revision ID prefix
 /// - allow_backwards: if true, allow moving bookmark backwards in history
 /// - ignore_immutable: if true, allow setting bookmark on immutable revisions
-///
-/// Returns JjResult with empty success or error message.
-#[no_mangle]
-pub extern "C" fn jj_set_bookmark(
-    handle: *mut RepoHandle,
-    name: *const c_char,
-    revision_id: *const c_char,
+fn set_bookmark(
+    handle: &mut RepoHandle,
+    bookmark_name: &str,
+    revision_str: &str,
     allow_backwards: bool,
     ignore_immutable: bool,
-) -> JjResult {
+) -> Result<(), String> {
     use std::collections::HashSet;
     use jj_lib::op_store::RefTarget;
     use jj_lib::ref_name::RefNameBuf;
 
-    let handle = unsafe {
-        if handle.is_null() {
-            return JjResult::error("null repo handle".to_string());
-        }
-        &mut *handle
-    };
-
-    let bookmark_name = unsafe {
-        if name.is_null() {
-            return JjResult::error("null bookmark name".to_string());
-        }
-        match CStr::from_ptr(name).to_str() {
-            Ok(s) => s,
-            Err(e) => return JjResult::error(format!("invalid bookmark name UTF-8: {}", e)),
-        }
-    };
-
     // Convert bookmark name to RefNameBuf
     let ref_name = RefNameBuf::from(bookmark_name.to_string());
 
-    let revision_str = unsafe {
-        if revision_id.is_null() {
-            return JjResult::error("null revision_id".to_string());
-        }
-        match CStr::from_ptr(revision_id).to_str() {
-            Ok(s) => s,
-            Err(e) => return JjResult::error(format!("invalid revision_id UTF-8: {}", e)),
-        }
-    };
-
     // Find the target commit by ID prefix
     let target_commit = {
         let mut found: Option<Commit> = None;
@@ -1580,7 +1715,7 @@ pub extern "C" fn jj_set_bookmark(
 
         match found {
             Some(c) => c,
-            None => return JjResult::error(format!("Revision not found: {}", revision_str)),
+            None => return Err(format!("Revision not found: {}", revision_str)),
         }
     };
 
@@ -1591,7 +1726,7 @@ pub extern "C" fn jj_set_bookmark(
     if !ignore_immutable {
         let root_commit_id = handle.repo.store().root_commit_id();
         if target_commit.id() == root_commit_id {
-            return JjResult::error("Cannot set bookmark on immutable revision (root commit)".to_string());
+            return Err("Cannot set bookmark on immutable revision (root commit)".to_string());
         }
 
         // Check if commit is an ancestor of any remote-tracked bookmark
@@ -1640,7 +1775,7 @@ pub extern "C" fn jj_set_bookmark(
             }
 
             if is_immutable {
-                return JjResult::error("Cannot set bookmark on immutable revision (already pushed)".to_string());
+                return Err("Cannot set bookmark on immutable revision (already pushed)".to_string());
             }
         }
     }
@@ -1684,7 +1819,7 @@ pub extern "C" fn jj_set_bookmark(
                 }
 
                 if is_backwards {
-                    return JjResult::error("Cannot move bookmark backwards (use allow_backwards flag)".to_string());
+                    return Err("Cannot move bookmark backwards (use allow_backwards flag)".to_string());
                 }
             }
         }
@@ -1705,8 +1840,414 @@ pub extern "C" fn jj_set_bookmark(
         Ok(new_repo) => {
             // Update handle to point to new repo
             handle.repo = new_repo;
-            JjResult::success("".to_string())
+            Ok(())
+        }
+        Err(e) => Err(format!("Failed to commit transaction: {}", e)),
+    }
+}
+
+/// Find a commit by commit ID or change ID prefix through the repo's ID
+/// index, as jj resolves a bare ID. A prefix matching more than one ID, or
+/// a change ID with more than one visible commit (divergent), is an error
+/// rather than a guess. "@" is the current workspace's working-copy commit.
+fn resolve_commit(handle: &RepoHandle, spec: &str) -> Result<Commit, String> {
+    use jj_lib::object_id::{HexPrefix, PrefixResolution};
+
+    if spec == "@" {
+        return current_wc_commit(handle);
+    }
+
+    let repo = handle.repo.as_ref();
+    // Commit IDs are hex and change IDs use the letters z-k, so a prefix
+    // can only ever name one kind
+    let commit_id = if let Some(prefix) = HexPrefix::try_from_hex(spec) {
+        let resolution = repo.index().resolve_commit_id_prefix(&prefix)
+            .map_err(|e| format!("Failed to resolve {}: {}", spec, e))?;
+        match resolution {
+            PrefixResolution::NoMatch => return Err(format!("Revision not found: {}", spec)),
+            PrefixResolution::AmbiguousMatch => {
+                return Err(format!("Commit ID prefix {} is ambiguous", spec));
+            }
+            PrefixResolution::SingleMatch(id) => id,
+        }
+    } else if let Some(prefix) = HexPrefix::try_from_reverse_hex(spec) {
+        let resolution = repo.resolve_change_id_prefix(&prefix)
+            .map_err(|e| format!("Failed to resolve {}: {}", spec, e))?;
+        match resolution {
+            PrefixResolution::NoMatch => return Err(format!("Revision not found: {}", spec)),
+            PrefixResolution::AmbiguousMatch => {
+                return Err(format!("Change ID prefix {} is ambiguous", spec));
+            }
+            PrefixResolution::SingleMatch(ids) => match ids.as_slice() {
+                [id] => id.clone(),
+                [] => return Err(format!("Revision not found: {}", spec)),
+                _ => return Err(format!("Change ID {} is divergent", spec)),
+            },
+        }
+    } else {
+        return Err(format!("Revision not found: {}", spec));
+    };
+
+    repo.store().get_commit(&commit_id)
+        .map_err(|e| format!("Failed to get commit {}: {}", spec, e))
+}
+
+/// Load the workspace the handle was opened in
+fn load_workspace(handle: &RepoHandle) -> Result<Workspace, String> {
+    let settings = create_user_settings()?;
+    let working_copy_factories = default_working_copy_factories();
+    Workspace::load(
+        &settings,
+        Path::new(&handle.repo_root),
+        &Default::default(),
+        &working_copy_factories,
+    )
+    .map_err(|e| format!("Failed to load workspace: {:?}", e))
+}
+
+/// The current workspace's working-copy commit
+fn current_wc_commit(handle: &RepoHandle) -> Result<Commit, String> {
+    let wc_commit_id = handle.repo.view().wc_commit_ids()
+        .iter()
+        .find(|(ws_id, _)| ws_id.as_str() == handle.current_workspace)
+        .map(|(_, commit_id)| commit_id.clone())
+        .ok_or_else(|| "No working copy found for current workspace".to_string())?;
+    handle.repo.store().get_commit(&wc_commit_id)
+        .map_err(|e| format!("Failed to get working copy commit: {}", e))
+}
+
+/// The ignore rules that apply above the workspace's own .gitignore files,
+/// built as the jj CLI does: git's global excludes file (core.excludesFile,
+/// or $XDG_CONFIG_HOME/git/ignore) and the backing repo's info/exclude
+fn base_ignores(
+    handle: &RepoHandle,
+    workspace_root: &Path,
+) -> Result<Arc<jj_lib::gitignore::GitIgnoreFile>, String> {
+    use jj_lib::gitignore::GitIgnoreFile;
+    use std::path::PathBuf;
+
+    let xdg_config_home = || -> Option<PathBuf> {
+        match std::env::var("XDG_CONFIG_HOME") {
+            Ok(dir) if !dir.is_empty() => Some(PathBuf::from(dir)),
+            _ => std::env::var("HOME").ok().map(|home| Path::new(&home).join(".config")),
+        }
+    };
+    let default_excludes_file = || xdg_config_home().map(|dir| dir.join("git").join("ignore"));
+
+    let mut ignores = GitIgnoreFile::empty();
+    match jj_lib::git::get_git_backend(handle.repo.store()) {
+        Ok(git_backend) => {
+            let config = git_backend.git_repo().config_snapshot();
+            let excludes_file = match config.string("core.excludesFile") {
+                Some(value) => std::str::from_utf8(&value).ok()
+                    .map(jj_lib::file_util::expand_home_path)
+                    .map(|path| workspace_root.join(path)),
+                None => default_excludes_file(),
+            };
+            if let Some(path) = excludes_file {
+                ignores = ignores.chain_with_file("", path)
+                    .map_err(|e| format!("Failed to read global gitignore: {}", e))?;
+            }
+            let exclude = git_backend.git_repo_path().join("info").join("exclude");
+            ignores = ignores.chain_with_file("", exclude)
+                .map_err(|e| format!("Failed to read info/exclude: {}", e))?;
+        }
+        Err(_) => {
+            if let Some(path) = default_excludes_file() {
+                ignores = ignores.chain_with_file("", path)
+                    .map_err(|e| format!("Failed to read global gitignore: {}", e))?;
+            }
         }
-        Err(e) => JjResult::error(format!("Failed to commit transaction: {}", e)),
     }
+    Ok(ignores)
+}
+
+/// Record files edited on disk into the working-copy commit, as jj does
+/// before every command, so a mutation that moves the working copy doesn't
+/// overwrite them
+fn snapshot_working_copy(handle: &mut RepoHandle) -> Result<(), String> {
+    use jj_lib::matchers::NothingMatcher;
+    use jj_lib::working_copy::SnapshotOptions;
+
+    let mut workspace = load_workspace(handle)?;
+    let wc_commit = current_wc_commit(handle)?;
+    let settings = create_user_settings()?;
+    let base_ignores = base_ignores(handle, workspace.workspace_root())?;
+
+    let mut locked_ws = workspace.start_working_copy_mutation()
+        .map_err(|e| format!("Failed to lock working copy: {}", e))?;
+    let options = SnapshotOptions {
+        base_ignores,
+        progress: None,
+        start_tracking_matcher: &EverythingMatcher,
+        force_tracking_matcher: &NothingMatcher,
+        max_new_file_size: settings.get_u64("snapshot.max-new-file-size").unwrap_or(1024 * 1024),
+    };
+    let (new_tree, _stats) = pollster::block_on(locked_ws.locked_wc().snapshot(&options))
+        .map_err(|e| format!("Failed to snapshot working copy: {}", e))?;
+
+    if new_tree.tree_ids() == wc_commit.tree().tree_ids() {
+        return Ok(());
+    }
+
+    let mut tx = handle.repo.start_transaction();
+    tx.repo_mut().rewrite_commit(&wc_commit).set_tree(new_tree).write()
+        .map_err(|e| format!("Failed to write commit: {}", e))?;
+    tx.repo_mut().rebase_descendants()
+        .map_err(|e| format!("Failed to rebase descendants: {}", e))?;
+    let new_repo = tx.commit("snapshot working copy")
+        .map_err(|e| format!("Failed to commit transaction: {}", e))?;
+    locked_ws.finish(new_repo.op_id().clone())
+        .map_err(|e| format!("Failed to record working copy: {}", e))?;
+    handle.repo = new_repo;
+    Ok(())
+}
+
+/// Commit a transaction that rewrites commits, then check out the current
+/// workspace's new working-copy commit if the transaction moved it
+fn finish_rewrite(
+    handle: &mut RepoHandle,
+    mut tx: jj_lib::transaction::Transaction,
+    description: &str,
+) -> Result<(), String> {
+    let old_wc_commit = current_wc_commit(handle)?;
+
+    tx.repo_mut().rebase_descendants()
+        .map_err(|e| format!("Failed to rebase descendants: {}", e))?;
+    handle.repo = tx.commit(description)
+        .map_err(|e| format!("Failed to commit transaction: {}", e))?;
+
+    let new_wc_commit = current_wc_commit(handle)?;
+    if new_wc_commit.id() == old_wc_commit.id() {
+        return Ok(());
+    }
+
+    let mut workspace = load_workspace(handle)?;
+    let old_tree = old_wc_commit.tree();
+    workspace.check_out(handle.repo.op_id().clone(), Some(&old_tree), &new_wc_commit)
+        .map_err(|e| format!("Failed to update working copy: {}", e))?;
+    Ok(())
+}
+
+/// jj's built-in revset aliases that immutable_heads() is defined in terms of
+const DEFAULT_REVSET_ALIASES: &[(&str, &str)] = &[
+    (
+        "trunk()",
+        r#"latest(
+            remote_bookmarks(exact:"main", exact:"origin") |
+            remote_bookmarks(exact:"master", exact:"origin") |
+            remote_bookmarks(exact:"trunk", exact:"origin") |
+            remote_bookmarks(exact:"main", exact:"upstream") |
+            remote_bookmarks(exact:"master", exact:"upstream") |
+            remote_bookmarks(exact:"trunk", exact:"upstream") |
+            root()
+        )"#,
+    ),
+    ("builtin_immutable_heads()", "present(trunk()) | tags() | untracked_remote_bookmarks()"),
+    ("immutable_heads()", "builtin_immutable_heads()"),
+];
+
+/// Revset aliases as jj sees them: its built-in ones, overridden by the
+/// user's config and then the repo's
+fn revset_aliases(handle: &RepoHandle) -> Result<jj_lib::revset::RevsetAliasesMap, String> {
+    use jj_lib::config::{ConfigLayer, ConfigSource};
+
+    let mut aliases_map = jj_lib::revset::RevsetAliasesMap::new();
+    for (decl, defn) in DEFAULT_REVSET_ALIASES {
+        aliases_map.insert(*decl, *defn).map_err(|e| e.to_string())?;
+    }
+
+    let mut config = create_user_settings()?.config().clone();
+    let repo_config = handle.repo.repo_path().join("config.toml");
+    if repo_config.exists() {
+        let layer = ConfigLayer::load_from_file(ConfigSource::Repo, repo_config)
+            .map_err(|e| format!("Failed to load repo config: {}", e))?;
+        config.add_layer(layer);
+    }
+    let settings = UserSettings::from_config(config).map_err(|e| e.to_string())?;
+    for decl in settings.table_keys("revset-aliases") {
+        let defn = settings.get_string(["revset-aliases", decl])
+            .map_err(|e| format!("Invalid revset alias {}: {}", decl, e))?;
+        aliases_map.insert(decl, defn).map_err(|e| e.to_string())?;
+    }
+    Ok(aliases_map)
+}
+
+/// Refuse to rewrite any of the commits that jj considers immutable: the
+/// ancestors of immutable_heads(), as the user's config defines it
+fn check_mutable(handle: &RepoHandle, ids: &[jj_lib::backend::CommitId]) -> Result<(), String> {
+    use jj_lib::revset::{
+        self, RevsetDiagnostics, RevsetExpression, RevsetExtensions, RevsetParseContext,
+        SymbolResolver, SymbolResolverExtension,
+    };
+    use std::collections::HashMap;
+
+    if ids.is_empty() {
+        return Ok(());
+    }
+    let root_id = handle.repo.store().root_commit_id();
+    if ids.contains(root_id) {
+        return Err("The root commit is immutable".to_string());
+    }
+
+    let settings = create_user_settings()?;
+    let aliases_map = revset_aliases(handle)?;
+    let extensions = RevsetExtensions::default();
+    let context = RevsetParseContext {
+        aliases_map: &aliases_map,
+        local_variables: HashMap::new(),
+        user_email: settings.user_email(),
+        date_pattern_context: chrono::Local::now().into(),
+        default_ignored_remote: Some(jj_lib::git::REMOTE_NAME_FOR_LOCAL_GIT_REPO),
+        use_glob_by_default: false,
+        extensions: &extensions,
+        workspace: None,
+    };
+    let mut diagnostics = RevsetDiagnostics::new();
+    let heads = revset::parse(&mut diagnostics, "immutable_heads()", &context)
+        .map_err(|e| format!("Invalid immutable_heads(): {}", e))?;
+
+    let repo = handle.repo.as_ref();
+    let no_extensions: &[Box<dyn SymbolResolverExtension>] = &[];
+    let symbol_resolver = SymbolResolver::new(repo, no_extensions);
+    let immutable = RevsetExpression::commits(ids.to_vec())
+        .intersection(&heads.ancestors())
+        .resolve_user_expression(repo, &symbol_resolver)
+        .map_err(|e| format!("Failed to resolve immutable_heads(): {}", e))?
+        .evaluate(repo)
+        .map_err(|e| format!("Failed to evaluate immutable_heads(): {}", e))?;
+    match immutable.iter().next() {
+        Some(Ok(id)) => Err(format!("Commit {} is immutable", &id.hex()[..12])),
+        Some(Err(e)) => Err(format!("Failed to evaluate immutable_heads(): {}", e)),
+        None => Ok(()),
+    }
+}
+
+/// Resolve a mutable revision for a rewrite, snapshotting the working copy
+/// first so the revision reflects files edited on disk
+fn resolve_for_rewrite(handle: &mut RepoHandle, revision: &str) -> Result<Commit, String> {
+    snapshot_working_copy(handle)?;
+    let commit = resolve_commit(handle, revision)?;
+    check_mutable(handle, &[commit.id().clone()])?;
+    Ok(commit)
+}
+
+/// Set the description of a revision (jj describe -r <revision> -m <message>)
+fn describe(handle: &mut RepoHandle, revision_str: &str, message: &str) -> Result<(), String> {
+    let commit = resolve_for_rewrite(handle, revision_str)?;
+
+    // jj keeps descriptions newline-terminated
+    let mut description = message.trim_end().to_string();
+    if !description.is_empty() {
+        description.push('\n');
+    }
+
+    let mut tx = handle.repo.start_transaction();
+    tx.repo_mut().rewrite_commit(&commit).set_description(description).write()
+        .map_err(|e| format!("Failed to write commit: {}", e))?;
+    let description = format!("describe commit {}", commit.id().hex());
+    finish_rewrite(handle, tx, &description)
+}
+
+/// Create a new empty change after a revision and edit it
+/// (jj new --after <revision>): the revision's children are rebased onto it
+fn new_change(handle: &mut RepoHandle, revision_str: &str) -> Result<(), String> {
+    use jj_lib::ref_name::WorkspaceNameBuf;
+    use jj_lib::revset::RevsetExpression;
+
+    snapshot_working_copy(handle)?;
+    let parent = resolve_commit(handle, revision_str)?;
+
+    // Children of the parent, to move on top of the new change
+    let children: Vec<Commit> = RevsetExpression::commit(parent.id().clone())
+        .children()
+        .evaluate(handle.repo.as_ref())
+        .map_err(|e| format!("Failed to find children: {}", e))?
+        .iter()
+        .filter_map(|id| id.ok())
+        .filter_map(|id| handle.repo.store().get_commit(&id).ok())
+        .collect();
+    let child_ids: Vec<jj_lib::backend::CommitId> = children.iter().map(|c| c.id().clone()).collect();
+    check_mutable(handle, &child_ids)?;
+
+    let mut tx = handle.repo.start_transaction();
+    let new_commit = tx.repo_mut()
+        .new_commit(vec![parent.id().clone()], parent.tree())
+        .write()
+        .map_err(|e| format!("Failed to write commit: {}", e))?;
+
+    for child in &children {
+        let parents: Vec<jj_lib::backend::CommitId> = child.parent_ids()
+            .iter()
+            .map(|id| if id == parent.id() { new_commit.id().clone() } else { id.clone() })
+            .collect();
+        tx.repo_mut().rewrite_commit(child).set_parents(parents).write()
+            .map_err(|e| format!("Failed to rebase {}: {}", child.id().hex(), e))?;
+    }
+
+    let workspace_name = WorkspaceNameBuf::from(handle.current_workspace.clone());
+    tx.repo_mut().edit(workspace_name, &new_commit)
+        .map_err(|e| format!("Failed to edit new commit: {:?}", e))?;
+
+    let description = format!("new empty commit after {}", parent.id().hex());
+    finish_rewrite(handle, tx, &description)
+}
+
+/// Abandon revisions, rebasing their descendants onto their parents
+/// (jj abandon <revisions>...)
+fn abandon(handle: &mut RepoHandle, revision_specs: &[String]) -> Result<(), String> {
+    if revision_specs.is_empty() {
+        return Err("No revisions to abandon".to_string());
+    }
+
+    snapshot_working_copy(handle)?;
+    let mut commits = Vec::with_capacity(revision_specs.len());
+    for spec in revision_specs {
+        commits.push(resolve_commit(handle, spec)?);
+    }
+    let ids: Vec<jj_lib::backend::CommitId> = commits.iter().map(|c| c.id().clone()).collect();
+    check_mutable(handle, &ids)?;
+
+    // Abandoning the working-copy commit leaves a new empty one on its
+    // parents, which rebase_descendants creates
+    let mut tx = handle.repo.start_transaction();
+    for commit in &commits {
+        tx.repo_mut().record_abandoned_commit(commit);
+    }
+    let description = match commits.as_slice() {
+        [commit] => format!("abandon commit {}", commit.id().hex()),
+        _ => format!("abandon {} commits", commits.len()),
+    };
+    finish_rewrite(handle, tx, &description)
+}
+
+/// Move a revision's changes into its parent and abandon it (jj squash -r <revision>)
+/// When both are described, the descriptions are joined as jj does
+fn squash(handle: &mut RepoHandle, revision_str: &str) -> Result<(), String> {
+    let commit = resolve_for_rewrite(handle, revision_str)?;
+    let parent_ids = commit.parent_ids();
+    if parent_ids.len() != 1 {
+        return Err("Cannot squash a merge commit into its parents".to_string());
+    }
+    let parent = handle.repo.store().get_commit(&parent_ids[0])
+        .map_err(|e| format!("Failed to get parent commit: {}", e))?;
+    check_mutable(handle, &[parent.id().clone()])?;
+
+    let description = match (parent.description().trim(), commit.description().trim()) {
+        (_, "") => parent.description().to_string(),
+        ("", _) => commit.description().to_string(),
+        (dest, source) => format!("{}\n\n{}\n", dest, source),
+    };
+
+    // With a single parent, the commit's tree is the parent plus its changes
+    let mut tx = handle.repo.start_transaction();
+    tx.repo_mut().record_abandoned_commit(&commit);
+    tx.repo_mut()
+        .rewrite_commit(&parent)
+        .set_tree(commit.tree())
+        .set_description(description)
+        .write()
+        .map_err(|e| format!("Failed to write commit: {}", e))?;
+    let description = format!("squash commit {} into {}", commit.id().hex(), parent.id().hex());
+    finish_rewrite(handle, tx, &description)
 }
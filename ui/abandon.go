@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/app"
+	"github.com/gerunddev/jjazy/jj"
+)
+
+// abandonTypeToConfirm is how many descendants a change can have before
+// abandoning it asks for its change ID to be typed
+const abandonTypeToConfirm = 10
+
+// confirmAbandon abandons a change, asking first when the confirm setting
+// says to; by default, only when it has many descendants, since they are
+// all rebased. Many descendants also need the change ID typed. By
+// default, descendants that couldn't be counted don't ask.
+func (a *App) confirmAbandon(target app.Target) tea.Cmd {
+	fallback := func(count int) bool {
+		return count >= abandonTypeToConfirm
+	}
+	return a.askFirst(abandonPolicy, target.CommitID, fallback, func(ask bool, count int) tea.Cmd {
+		if !ask {
+			return a.abandon(target)
+		}
+		a.abandonTarget = target
+		message := fmt.Sprintf("Abandon %s?", target.ChangeID)
+		if count > 0 {
+			message = fmt.Sprintf("Abandon %s? Its %d descendants are rebased onto its parent.", target.ChangeID, count)
+		}
+		a.showConfirmDialog("Abandon Change", message, "abandon_descendants")
+		a.confirmOverlay.SetDanger("Abandon")
+		if count >= abandonTypeToConfirm {
+			a.confirmOverlay.RequireTyping(target.ChangeID)
+		}
+		return nil
+	})
+}
+
+// confirmAbandonRange abandons the selected range, asking first unless
+// the confirm setting says not to
+func (a *App) confirmAbandonRange(revset string, count int) tea.Cmd {
+	fallback := func(int) bool { return true }
+	return a.askFirst(abandonPolicy, "("+revset+")", fallback, func(ask bool, _ int) tea.Cmd {
+		if !ask {
+			return a.abandonRange()
+		}
+		a.showConfirmDialog("Abandon Range", fmt.Sprintf("Abandon %d changes?", count), "abandon_range")
+		a.confirmOverlay.SetDanger("Abandon")
+		return nil
+	})
+}
+
+// abandonRange removes the selected range, rebasing its descendants
+func (a *App) abandonRange() tea.Cmd {
+	revset, _, err := a.rangeRevset()
+	if err != nil {
+		a.showErrorDialog(err)
+		return nil
+	}
+	return a.runMutation(func(ctx context.Context) error {
+		changeIDs, err := jj.ChangeIDs(ctx, a.repoPath, revset)
+		if err != nil {
+			return err
+		}
+		return a.repo.Abandon(ctx, changeIDs...)
+	}, func() {
+		a.logPanel.SetVisualMode(false)
+		a.refreshLogPanels()
+	})
+}
+
+// abandon removes the target change, rebasing its descendants
+func (a *App) abandon(target app.Target) tea.Cmd {
+	return a.runOnTarget(target, func(ctx context.Context, changeID string) error {
+		return a.repo.Abandon(ctx, changeID)
+	}, a.refreshLogPanels)
+}
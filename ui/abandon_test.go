@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/app"
+	"github.com/gerunddev/jjazy/config"
+)
+
+// TestAbandonTypeToConfirm verifies Enter does nothing until the change ID
+// is typed, then abandons it and records the action that asked
+func TestAbandonTypeToConfirm(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	key := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}}
+	a.recordAction("abandon", "kmno", key)
+	a.abandonTarget = app.Target{ChangeID: "kmno", CommitID: "abc123"}
+	a.showConfirmDialog("Abandon Change", "Abandon kmno?", "abandon_descendants")
+	a.confirmOverlay.SetDanger("Abandon")
+	a.confirmOverlay.RequireTyping("kmno")
+
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("km")})
+	a.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !a.showConfirm || a.busy {
+		t.Fatal("Expected Enter to wait for the whole change ID")
+	}
+
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("no")})
+	a.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if a.showConfirm || !a.busy {
+		t.Fatal("Expected the typed change ID to start the abandon")
+	}
+	if last := a.history.Last(); last == nil || last.Name != "abandon" || last.Subject != "kmno" {
+		t.Errorf("Expected the abandon recorded once confirmed, got %+v", last)
+	}
+}
+
+// TestAbandonTypeToConfirmCancel verifies esc closes the dialog and
+// nothing runs or is recorded
+func TestAbandonTypeToConfirmCancel(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	a.recordAction("abandon", "kmno", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	a.showConfirmDialog("Abandon Change", "Abandon kmno?", "abandon_descendants")
+	a.confirmOverlay.RequireTyping("kmno")
+	a.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	if a.showConfirm || a.busy || a.history.Last() != nil || a.pendingAction != nil {
+		t.Error("Expected esc to drop the abandon")
+	}
+}
@@ -0,0 +1,585 @@
+package ui
+
+import (
+	"context"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/app"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/floating"
+	"github.com/gerunddev/jjazy/ui/messages"
+)
+
+// isEscape reports whether msg backs out of a mode, panel or experience
+func (a *App) isEscape(msg tea.KeyMsg) bool {
+	return key.Matches(msg, a.keys.Escape) ||
+		msg.Type == tea.KeyEscape ||
+		msg.Type == tea.KeyEsc ||
+		msg.String() == "esc" ||
+		msg.String() == "escape" ||
+		msg.String() == "ctrl+g" ||
+		msg.Type == tea.KeyCtrlG
+}
+
+// logActions handles the log panel's keys: change actions on the selected
+// revision, or range actions in visual mode
+func (a *App) logActions(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch {
+	case msg.Type == tea.KeyLeft:
+		// Leave a picking mode, or move to the sidebar
+		switch {
+		case a.bookmarkSetMode:
+			a.exitBookmarkSetMode()
+		case a.rebaseMode:
+			a.exitRebaseMode()
+		case a.squashIntoMode:
+			a.exitSquashIntoMode()
+		default:
+			a.setFocus(1)
+		}
+		return nil, true
+
+	case msg.Type == tea.KeyRight:
+		// Drill into the change, or edit it when Enter and → are swapped
+		if change := a.logPanel.SelectedChange(); change != nil {
+			if a.swappedEnter() {
+				return a.edit(app.TargetOf(*change)), true
+			}
+			a.enterChangeExperience(change.ChangeID, change.IsWorkingCopy)
+			return nil, true
+		}
+
+	case key.Matches(msg, a.keys.Enter):
+		change := a.logPanel.SelectedChange()
+		if change == nil {
+			return nil, true
+		}
+		switch {
+		case a.bookmarkSetMode:
+			// Confirm bookmark set
+			a.executeBookmarkSet(change.CommitID)
+			return nil, true
+		case a.rebaseMode:
+			// Confirm rebase destination
+			return a.executeRebase(app.TargetOf(*change)), true
+		case a.squashIntoMode:
+			// Confirm squash destination
+			return a.executeSquashInto(app.TargetOf(*change)), true
+		case a.swappedEnter():
+			a.enterChangeExperience(change.ChangeID, change.IsWorkingCopy)
+			return nil, true
+		}
+		// Normal: jj edit
+		return a.edit(app.TargetOf(*change)), true
+	}
+
+	// Type a change ID prefix to jump to
+	if key.Matches(msg, a.keys.GoToChange) {
+		a.logPanel.StartPrefixJump()
+		return nil, true
+	}
+
+	// Show everything the row cuts short
+	if key.Matches(msg, a.keys.Details) && !a.rebaseMode && !a.squashIntoMode && !a.logPanel.InVisualMode() {
+		a.openDetails()
+		return nil, true
+	}
+
+	// Type the rebase destination instead of picking it
+	if a.rebaseMode && key.Matches(msg, a.keys.Rebase) {
+		return a.openRevsetInput("Rebase Destination", "Enter destination revset...", "", "rebase_destination"), true
+	}
+
+	// Rewriting immutable revisions is refused before anything runs
+	if !a.rebaseMode && !a.squashIntoMode && a.blockImmutable(msg) {
+		return nil, true
+	}
+
+	// Visual (range) mode actions
+	if a.logPanel.InVisualMode() {
+		switch {
+		case key.Matches(msg, a.keys.VisualMode):
+			a.logPanel.SetVisualMode(false)
+			return nil, true
+
+		case key.Matches(msg, a.keys.Abandon):
+			revset, count, err := a.rangeRevset()
+			if err != nil {
+				a.showErrorDialog(err)
+				return nil, true
+			}
+			return a.confirmAbandonRange(revset, count), true
+
+		case key.Matches(msg, a.keys.Rebase):
+			revset, _, err := a.rangeRevset()
+			if err != nil {
+				a.showErrorDialog(err)
+				return nil, true
+			}
+			a.enterRebaseMode(revset)
+			return nil, true
+
+		case key.Matches(msg, a.keys.SquashChange):
+			a.cooldown.Arm(msg.String(), time.Now())
+			return a.squashRange(), true
+
+		case key.Matches(msg, a.keys.Parallelize):
+			revset, _, err := a.rangeRevset()
+			if err != nil {
+				a.showErrorDialog(err)
+				return nil, true
+			}
+			a.cooldown.Arm(msg.String(), time.Now())
+			a.recordAction("parallelize", revset, msg)
+			return a.runMutation(func(ctx context.Context) error {
+				if err := jj.CheckLinear(ctx, a.repoPath, revset); err != nil {
+					return err
+				}
+				return jj.Parallelize(ctx, a.repoPath, revset)
+			}, func() {
+				a.logPanel.SetVisualMode(false)
+				a.refreshLogPanels()
+			}), true
+
+		case key.Matches(msg, a.keys.Sign):
+			revset, _, err := a.rangeRevset()
+			if err != nil {
+				a.showErrorDialog(err)
+				return nil, true
+			}
+			return a.signRange(revset), true
+
+		case key.Matches(msg, a.keys.ExportPatches):
+			a.textInputOverlay = floating.NewTextInputOverlay(
+				"Export Patches",
+				"Enter destination directory...",
+				"patches",
+			)
+			a.textInputOverlay.SetSize(a.width, a.height-1)
+			a.showTextInput = true
+			a.textInputAction = "export_patches"
+			return nil, true
+
+		case key.Matches(msg, a.keys.NewChange),
+			key.Matches(msg, a.keys.Describe),
+			key.Matches(msg, a.keys.SquashInto),
+			key.Matches(msg, a.keys.CreateTag),
+			key.Matches(msg, a.keys.EditAuthor):
+			// Single-change actions are disabled while a range is selected
+			return nil, true
+		}
+	}
+
+	// Change actions
+	if !a.rebaseMode && !a.squashIntoMode {
+		switch {
+		case key.Matches(msg, a.keys.VisualMode):
+			a.logPanel.SetVisualMode(true)
+			return nil, true
+
+		case key.Matches(msg, a.keys.Rebase):
+			// Rebase a revset, starting from the selected change
+			if change := a.logPanel.SelectedChange(); change != nil {
+				return a.openRevsetInput("Rebase Revset", "Enter revset to rebase...", change.ChangeID, "rebase_revset"), true
+			}
+			return nil, true
+
+		case key.Matches(msg, a.keys.NewChange):
+			// Create new change after selected
+			if change := a.logPanel.SelectedChange(); change != nil {
+				a.recordAction("new after", change.ChangeID, msg)
+				return a.runOnTarget(app.TargetOf(*change), func(ctx context.Context, changeID string) error {
+					return a.advancing(ctx, func() error {
+						return a.repo.NewChange(ctx, changeID)
+					})
+				}, a.refreshLogPanels), true
+			}
+			return nil, true
+
+		case key.Matches(msg, a.keys.Describe):
+			// Edit change description
+			if change := a.logPanel.SelectedChange(); change != nil {
+				a.openDescribe(*change)
+			}
+			return nil, true
+
+		case key.Matches(msg, a.keys.Commit):
+			// Describe @ and start the next change on top of it
+			return a.openCommit(), true
+
+		case key.Matches(msg, a.keys.Backout):
+			// Reverse the change on top of @, describing it first
+			if change := a.logPanel.SelectedChange(); change != nil {
+				a.openBackout(*change)
+			}
+			return nil, true
+
+		case key.Matches(msg, a.keys.Abandon):
+			// Abandon change
+			if change := a.logPanel.SelectedChange(); change != nil {
+				a.cooldown.Arm(msg.String(), time.Now())
+				a.recordAction("abandon", change.ChangeID, msg)
+				return a.confirmAbandon(app.TargetOf(*change)), true
+			}
+			return nil, true
+
+		case key.Matches(msg, a.keys.SquashChange):
+			// Squash change into parent
+			if change := a.logPanel.SelectedChange(); change != nil {
+				a.cooldown.Arm(msg.String(), time.Now())
+				a.recordAction("squash", change.ChangeID, msg)
+				return a.confirmSquash(app.TargetOf(*change), nil), true
+			}
+			return nil, true
+
+		case key.Matches(msg, a.keys.SquashInto):
+			// Squash change into a destination picked in the log
+			if change := a.logPanel.SelectedChange(); change != nil {
+				a.enterSquashIntoMode(app.TargetOf(*change))
+			}
+			return nil, true
+
+		case key.Matches(msg, a.keys.Sign):
+			// Sign (or re-sign) the selected change
+			if change := a.logPanel.SelectedChange(); change != nil {
+				return a.signChange(app.TargetOf(*change)), true
+			}
+			return nil, true
+
+		case key.Matches(msg, a.keys.EditAuthor):
+			// Fix the selected change's author
+			if change := a.logPanel.SelectedChange(); change != nil {
+				a.openAuthorEdit(*change)
+			}
+			return nil, true
+
+		case key.Matches(msg, a.keys.CreateTag):
+			// Tag the selected revision
+			if change := a.logPanel.SelectedChange(); change != nil {
+				a.textInputOverlay = floating.NewTextInputOverlay(
+					"Create Tag",
+					"Enter tag name...",
+					"",
+				)
+				a.textInputOverlay.SetSize(a.width, a.height-1)
+				a.showTextInput = true
+				a.textInputAction = "create_tag"
+				a.textInputTarget = app.TargetOf(*change)
+			}
+			return nil, true
+		}
+	}
+
+	return nil, false
+}
+
+// bookmarkActions handles the bookmarks panel's keys
+func (a *App) bookmarkActions(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch {
+	case a.bookmarksPanel.IsEntered() && (a.isEscape(msg) || msg.Type == tea.KeyLeft ||
+		key.Matches(msg, a.keys.NextPanel, a.keys.PrevPanel)):
+		// Leave cursor mode, keeping focus
+		a.bookmarksPanel.SetEntered(false)
+		return nil, true
+
+	case msg.Type == tea.KeyRight:
+		// Back to the log panel
+		a.bookmarksPanel.SetEntered(false)
+		a.setFocus(0)
+		return nil, true
+
+	case msg.Type == tea.KeyUp && !a.bookmarksPanel.IsEntered() && a.workspaceShown():
+		// Up to the workspace panel
+		a.setFocus(1)
+		return nil, true
+
+	case key.Matches(msg, a.keys.Enter):
+		if !a.bookmarksPanel.IsEntered() {
+			a.bookmarksPanel.SetEntered(true)
+		} else if bm := a.bookmarksPanel.SelectedBookmark(); bm != nil {
+			// Enter starts bookmark set mode
+			a.enterBookmarkSetMode(bm.Name)
+		} else if tag := a.bookmarksPanel.SelectedTag(); tag != nil {
+			// Enter on a tag shows its revision in the log
+			if a.logPanel.SelectByCommitID(tag.CommitID) {
+				a.bookmarksPanel.SetEntered(false)
+				a.setFocus(0)
+			}
+		}
+		return nil, true
+	}
+
+	// Bookmark cleanup ('c' key)
+	if key.Matches(msg, a.keys.CleanupBookmarks) {
+		a.openBookmarkCleanup()
+		return nil, true
+	}
+
+	// Bookmark edit action ('e' key when entered)
+	if a.bookmarksPanel.IsEntered() {
+		if msg.String() == "e" {
+			if bm := a.bookmarksPanel.SelectedBookmark(); bm != nil {
+				// Use Navigation to find edit target (tip of branch or boundary)
+				a.bookmarksPanel.SetEntered(false)
+				a.setFocus(0) // Return to log view
+				if revisions, err := a.repo.Log(); err == nil {
+					nav := app.NewNavigation(a.repoPath, revisions)
+					if target := nav.FindBookmarkEditTarget(bm.Name); target != nil {
+						return a.runMutation(func(ctx context.Context) error {
+							return nav.EditRevision(ctx, target.ChangeID)
+						}, a.refreshLogPanels), true
+					}
+				}
+			}
+			return nil, true
+		}
+	}
+
+	return nil, false
+}
+
+// workspaceActions handles the workspace panel's keys
+func (a *App) workspaceActions(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch {
+	case a.workspacePanel.IsEntered() && (a.isEscape(msg) || msg.Type == tea.KeyLeft ||
+		key.Matches(msg, a.keys.NextPanel, a.keys.PrevPanel)):
+		// Leave cursor mode, keeping focus
+		a.workspacePanel.SetEntered(false)
+		return nil, true
+
+	case msg.Type == tea.KeyRight:
+		// Back to the log panel
+		a.workspacePanel.SetEntered(false)
+		a.setFocus(0)
+		return nil, true
+
+	case msg.Type == tea.KeyDown && !a.workspacePanel.IsEntered():
+		// Down to the bookmarks panel
+		a.setFocus(2)
+		return nil, true
+
+	case key.Matches(msg, a.keys.Enter):
+		if a.workspaceCollapsed() {
+			return nil, true // Nothing to browse with a single workspace
+		}
+		if !a.workspacePanel.IsEntered() {
+			a.workspacePanel.SetEntered(true)
+		} else if ws := a.workspacePanel.SelectedWorkspace(); ws != nil {
+			// Switch workspace using jj-lib (close and reopen repo)
+			if err := a.switchWorkspace(ws.RootPath); err != nil {
+				a.showErrorDialog(err)
+			} else {
+				a.workspacePanel.SetEntered(false)
+				a.setFocus(0) // Return to log view
+				return a.refreshSummary(), true
+			}
+		}
+		return nil, true
+	}
+
+	// 'a' key in focus mode (not entered) - add workspace
+	if msg.String() == "a" && !a.workspacePanel.IsEntered() {
+		a.textInputOverlay = floating.NewTextInputOverlay(
+			"Add Workspace",
+			"Enter destination path...",
+			"",
+		)
+		a.textInputOverlay.SetSize(a.width, a.height-1)
+		a.showTextInput = true
+		a.textInputAction = "workspace_add"
+		return nil, true
+	}
+
+	// Open the selected workspace in an editor or terminal
+	if key.Matches(msg, a.keys.OpenWorkspace) && a.workspacePanel.IsEntered() {
+		if ws := a.workspacePanel.SelectedWorkspace(); ws != nil {
+			return a.openWorkspace(ws), true
+		}
+		return nil, true
+	}
+
+	// Show the selected workspace's path and disk usage
+	if key.Matches(msg, a.keys.WorkspaceInfo) && a.workspacePanel.IsEntered() {
+		if ws := a.workspacePanel.SelectedWorkspace(); ws != nil {
+			return a.showWorkspaceDetails(*ws), true
+		}
+		return nil, true
+	}
+
+	// 'd' key in cursor mode (entered) - forget workspace
+	if msg.String() == "d" && a.workspacePanel.IsEntered() {
+		if ws := a.workspacePanel.SelectedWorkspace(); ws != nil {
+			if ws.IsCurrent {
+				a.showInfoDialog("Error", "Cannot forget current workspace")
+			} else {
+				if err := a.repo.WorkspaceForget(ws.Name); err != nil {
+					a.showErrorDialog(err)
+				} else {
+					// Adjust cursor if needed and refresh
+					if a.workspacePanel.Cursor() >= a.workspacePanel.Count()-1 {
+						a.workspacePanel.SetCursor(a.workspacePanel.Count() - 2)
+					}
+					a.requestRefresh(messages.RefreshWorkspaces)
+				}
+			}
+		}
+		return nil, true
+	}
+
+	return nil, false
+}
+
+// filesActions handles the files panel's keys, and the file history's
+// when it takes the panel's place
+func (a *App) filesActions(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch {
+	case msg.Type == tea.KeyLeft:
+		if a.fileHistoryPath != "" {
+			// From History → back to Files
+			a.exitFileHistory()
+			return nil, true
+		}
+		// From Files → Exit to Log experience
+		a.exitChangeExperience()
+		return nil, true
+
+	case msg.Type == tea.KeyRight:
+		// From Files → Diff panel
+		a.setFocus(0)
+		return nil, true
+
+	case a.fileHistoryPath != "" && key.Matches(msg, a.keys.Enter):
+		// Show the file's diff in the selected revision
+		if change := a.historyPanel.SelectedChange(); change != nil {
+			a.showFileHistoryRevision(*change)
+		}
+		return nil, true
+	}
+
+	// File history of the selected file
+	if a.fileHistoryPath == "" &&
+		key.Matches(msg, a.keys.FileHistory) {
+		if file := a.filesPanel.SelectedFile(); file != nil {
+			a.enterFileHistory(file.Path)
+		}
+		return nil, true
+	}
+
+	// Cycle the files' sort order
+	if a.fileHistoryPath == "" &&
+		key.Matches(msg, a.keys.SortFiles) {
+		return a.cycleFileOrder(), true
+	}
+
+	// Untracked file operations (working copy files, untracked section)
+	if a.untrackedFileSelected() &&
+		key.Matches(msg, a.keys.TrackFile, a.keys.IgnoreFile) {
+		return a.handleUntrackedFileKey(msg), true
+	}
+
+	// File operations (working copy only)
+	if a.selectedChangeIsWorking && a.fileHistoryPath == "" &&
+		!a.untrackedFileSelected() {
+		switch {
+		case msg.Type == tea.KeyDelete || msg.Type == tea.KeyBackspace ||
+			msg.String() == "delete" || msg.String() == "backspace":
+			// Restore (discard) file changes - using Delete/Backspace keys
+			if file := a.filesPanel.SelectedFile(); file != nil {
+				a.cooldown.Arm(msg.String(), time.Now())
+				a.recordAction("restore file", file.Path, msg)
+				return a.runMutation(func(ctx context.Context) error {
+					return jj.RestoreFile(ctx, a.repoPath, file.Path)
+				}, func() {
+					a.filesPanel.LoadForChange(a.selectedChangeID)
+
+					// If no files remain, exit to log (consistent with squash)
+					if a.filesPanel.Count() == 0 {
+						a.exitChangeExperience()
+						a.refreshLogPanels()
+					} else {
+						// Update diff view for remaining files
+						a.showSelectedFile()
+					}
+				}), true
+			}
+			return nil, true
+
+		case msg.String() == "s":
+			// Squash file to parent
+			if file := a.filesPanel.SelectedFile(); file != nil {
+				a.cooldown.Arm(msg.String(), time.Now())
+				a.recordAction("squash file", file.Path, msg)
+				return a.runMutation(func(ctx context.Context) error {
+					return jj.SquashFile(ctx, a.repoPath, file.Path)
+				}, func() {
+					a.filesPanel.LoadForChange(a.selectedChangeID)
+
+					// If no files remain, exit to log
+					if a.filesPanel.Count() == 0 {
+						a.exitChangeExperience()
+						a.refreshLogPanels()
+					} else {
+						// Update diff view for remaining files
+						a.showSelectedFile()
+					}
+				}), true
+			}
+			return nil, true
+		}
+	}
+
+	return nil, false
+}
+
+// diffActions handles the diff panel's keys
+func (a *App) diffActions(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch msg.Type {
+	case tea.KeyLeft:
+		// Scroll long lines back first, then Diff → Files panel
+		if !a.diffPanel.ScrollLeft() {
+			a.setFocus(1)
+		}
+		return nil, true
+
+	case tea.KeyRight:
+		// Already the rightmost panel, so scroll long lines
+		a.diffPanel.ScrollRight()
+		return nil, true
+	}
+	return nil, false
+}
+
+// timelineActions handles the operation timeline's keys. ← and → scrub it,
+// which the panel does itself.
+func (a *App) timelineActions(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if key.Matches(msg, a.keys.Enter) {
+		// Browse the log and diffs at the selected operation
+		if op := a.timelinePanel.SelectedOperation(); op != nil {
+			a.browseAtOperation(op)
+		}
+		return nil, true
+	}
+	return nil, false
+}
+
+// operationsActions handles the operations list's keys
+func (a *App) operationsActions(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch {
+	case msg.Type == tea.KeyLeft:
+		a.exitOperationsExperience()
+		return nil, true
+
+	case msg.Type == tea.KeyRight:
+		// Hand over to the timeline
+		a.setFocus(0)
+		return nil, true
+
+	case key.Matches(msg, a.keys.Enter):
+		// Show what the selected operation changed
+		a.openOpDiff()
+		return nil, true
+	}
+	return nil, false
+}
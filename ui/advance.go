@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/jj"
+)
+
+// advancing runs fn, a mutation that may move @ past its parent, and then
+// moves the bookmarks on the old @- forward to the new one when
+// auto-advance is on. Bookmarks on immutable changes, such as a pushed
+// trunk, stay put.
+func (a *App) advancing(ctx context.Context, fn func() error) error {
+	if !a.advanceBookmarks {
+		return fn()
+	}
+	parent, err := jj.BookmarkedParent(ctx, a.repoPath)
+	if err != nil {
+		return err
+	}
+	if err := fn(); err != nil || parent == "" {
+		return err
+	}
+	return jj.AdvanceBookmarks(ctx, a.repoPath, parent)
+}
+
+// toggleAdvanceBookmarks turns auto-advancing bookmarks on or off for the
+// session
+func (a *App) toggleAdvanceBookmarks() tea.Cmd {
+	a.advanceBookmarks = !a.advanceBookmarks
+	if a.advanceBookmarks {
+		return a.showToast("bookmarks on @- advance with new changes")
+	}
+	return a.showToast("bookmarks stay put")
+}
@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"context"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/config"
+)
+
+// TestToggleAdvanceBookmarks verifies M flips auto-advance, starting from
+// the config
+func TestToggleAdvanceBookmarks(t *testing.T) {
+	cfg := config.Default()
+	cfg.AdvanceBookmarks = true
+	a := NewApp(nil, "/nonexistent/path", cfg, &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	if !a.advanceBookmarks {
+		t.Fatal("Expected advance_bookmarks to turn auto-advance on")
+	}
+
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'M'}})
+	if a.advanceBookmarks {
+		t.Error("Expected M to turn auto-advance off")
+	}
+}
+
+// TestAdvancing verifies the mutation runs alone when auto-advance is off,
+// and not at all when the bookmarked parent can't be found
+func TestAdvancing(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	ran := false
+	if err := a.advancing(context.Background(), func() error { ran = true; return nil }); err != nil || !ran {
+		t.Errorf("Expected the mutation to run alone, got ran %v err %v", ran, err)
+	}
+
+	a.advanceBookmarks = true
+	ran = false
+	if err := a.advancing(context.Background(), func() error { ran = true; return nil }); err == nil || ran {
+		t.Errorf("Expected the failed lookup to stop the mutation, got ran %v err %v", ran, err)
+	}
+}
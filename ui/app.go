@@ -1,28 +1,49 @@
 package ui
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/gerunddev/jjazy/app"
+	"github.com/gerunddev/jjazy/config"
 	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/release"
 	"github.com/gerunddev/jjazy/ui/floating"
 	"github.com/gerunddev/jjazy/ui/messages"
 	"github.com/gerunddev/jjazy/ui/panels"
+	"github.com/gerunddev/jjazy/ui/theme"
 )
 
 // Experience represents the current view mode of the application
 type Experience int
 
 const (
-	ExperienceLog    Experience = iota // Main log view
-	ExperienceChange                   // Change detail view (files + diff)
+	ExperienceLog        Experience = iota // Main log view
+	ExperienceChange                       // Change detail view (files + diff)
+	ExperienceOperations                   // Operation timeline with a log preview
 )
 
+// doubleClickInterval is the maximum delay between two clicks on the same row
+// for them to count as a double-click
+const doubleClickInterval = 400 * time.Millisecond
+
+// revsetEvalDelay is how long typing in a revset input pauses before the
+// revset is evaluated, so fast typing doesn't run jj per keystroke
+const revsetEvalDelay = 150 * time.Millisecond
+
+// previewDelay is how long the log selection rests before the preview pane
+// loads its diff, so scrolling through the log doesn't run jj per row
+const previewDelay = 100 * time.Millisecond
+
 // PanelBound defines the screen coordinates of a panel for mouse detection
 type PanelBound struct {
 	X1, Y1, X2, Y2 int
@@ -34,6 +55,18 @@ type App struct {
 	// Repository
 	repo     *jj.Repo
 	repoPath string
+	cfg      *config.Config
+	layout   *config.State // Panel size ratios, persisted across sessions
+
+	// Trunk bookmark name (configured, or detected from trunk() after
+	// startup; empty until then)
+	trunkBookmark string
+
+	// jj's signing.backend, or "" when commits can't be signed
+	signingBackend string
+
+	// Bookmarks on @- move forward with new changes and squashes (M)
+	advanceBookmarks bool
 
 	// Experience state
 	currentExperience       Experience
@@ -45,21 +78,131 @@ type App struct {
 	bookmarksPanel *panels.BookmarksPanel
 	logPanel       *panels.LogPanel
 
+	// Diff preview pane - Log Experience split layout (toggle with z)
+	previewPanel    *panels.DiffViewer
+	showPreview     bool
+	previewCommitID string // Commit currently loaded in the preview
+	previewSeq      int    // Bumped per selection so only the last one loads
+
 	// Panels - Change Experience (Exp 2)
 	filesPanel *panels.FilesPanel
 	diffPanel  *panels.DiffViewer
 
+	// File history (H on a file): the sidebar lists changes touching the file
+	historyPanel    *panels.LogPanel
+	fileHistoryPath string // File whose history is shown; empty when not browsing
+
+	// Panels - Operations Experience (Exp 3)
+	timelinePanel   *panels.TimelinePanel
+	operationsPanel *panels.OperationsPanel
+	opLogPanel      *panels.LogPanel   // Log as of the scrubbed operation
+	cancelOpLog     context.CancelFunc // Aborts the in-flight at-op log load
+
+	// Read-only browsing of the log and diffs at a past operation
+	atOp string // Operation being browsed; empty for the present
+
 	// Floating windows
-	helpOverlay      *floating.HelpOverlay
-	showHelp         bool
-	textInputOverlay *floating.TextInputOverlay
-	showTextInput    bool
-	textInputAction  string // "describe" - indicates what action is being performed
+	helpOverlay       *floating.HelpOverlay
+	showHelp          bool
+	textInputOverlay  *floating.TextInputOverlay
+	showTextInput     bool
+	textInputAction   string             // "describe" - indicates what action is being performed
+	describeTrailers  []string           // Trailers added when the describe input is saved
+	describeThenNew   bool               // Start a new change on top once the describe input is saved (commit)
+	textInputTarget   app.Target         // Change the text input applies to, captured when it opened
+	revsetCompletions []revsetCompletion // Alias and bookmark names, for revset completion
 
 	// Confirm overlay
 	confirmOverlay *floating.ConfirmOverlay
 	showConfirm    bool
-	confirmAction  string // "immutable" or "backwards"
+	confirmAction  string // "immutable", "backwards", "abandon_range", "abandon_descendants", "squash", "squash_range", "resolve_divergent", "edit_risky", "describe_empty", "push_stack" or "open_pull_requests"
+
+	// Edit waiting for confirmation because the revision is immutable or pushed
+	editTarget          app.Target
+	editIgnoreImmutable bool
+
+	// Abandon waiting for its change ID to be typed, for having many descendants
+	abandonTarget app.Target
+
+	// Confirm policy waiting on a descendant count (see askFirst)
+	askSeq     int                           // Bumped per ask so only the latest count decides
+	pendingAsk func(descendants int) tea.Cmd // Decides once the count arrives
+
+	// Bookmark cleanup overlay (c in the bookmarks panel)
+	cleanupOverlay *floating.BookmarkCleanupOverlay
+	showCleanup    bool
+
+	// Divergent changes overlay (! in the log)
+	divergentOverlay *floating.DivergentOverlay
+	showDivergent    bool
+	divergentAbandon []string // Commit IDs to abandon once the resolution is confirmed
+
+	// Operation details (Enter in the operations list)
+	opDiffOverlay *floating.OpDiffOverlay
+	showOpDiff    bool
+
+	// Author form (A in the log)
+	authorOverlay *floating.AuthorOverlay
+	showAuthor    bool
+	authorTarget  app.Target
+
+	// Mutating actions run from the keyboard, for . and the action log (L)
+	history          actionHistory
+	pendingAction    *actionEntry // Recorded by a key, added to history once its mutation runs
+	mutationRecorded bool         // The running mutation is the newest history entry
+	actionLogOverlay *floating.ActionLogOverlay
+	showActionLog    bool
+
+	// Bridge latency table (ctrl+alt+d) and the status bar's slow call warning
+	metricsOverlay *floating.MetricsOverlay
+	showMetrics    bool
+	slowCall       string // Shown in the status bar until it expires
+	slowCallSeq    int    // Bumped per warning so only the latest expiry clears it
+
+	// Operation log tail (ctrl+alt+l)
+	debugLogOverlay *floating.DebugLogOverlay
+	showDebugLog    bool
+
+	// Notifications stacked in the corner, and the log of them all (N)
+	notifications        []shownNotification // Oldest first
+	notificationSeq      int                 // Bumped per notification, for its expiry
+	notificationLog      []floating.Notification
+	notificationsOverlay *floating.NotificationsOverlay
+	showNotifications    bool
+
+	// Jump list (' in the log)
+	jumpOverlay *floating.JumpOverlay
+	showJump    bool
+
+	// Log filter presets (F in the log)
+	logFilterOverlay *floating.JumpOverlay
+	showLogFilter    bool
+
+	// Context menu of the selected item's actions (m or right-click)
+	menuOverlay *floating.MenuOverlay
+	showMenu    bool
+
+	// Stack view (B in the log) and the stack its bulk actions apply to
+	stackOverlay *floating.StackOverlay
+	showStack    bool
+	stackEntries []jj.StackEntry
+
+	// Revision details overlay (i in the log)
+	detailsOverlay *floating.DetailsOverlay
+	showDetails    bool
+
+	// Report of updating the stack from trunk (U in the log)
+	updateOverlay *floating.UpdateOverlay
+	showUpdate    bool
+
+	// Bookmarks just pushed, offered pull requests
+	pullRequestBookmarks []string
+
+	// Release notes overlay (W) and the update found at startup
+	changelogOverlay *floating.ChangelogOverlay
+	showChangelog    bool
+	version          string          // Running jjazy version, set with SetVersion
+	update           *release.Update // Nil unless a newer release was found
 
 	// Info overlay (for errors)
 	infoOverlay *floating.InfoOverlay
@@ -70,6 +213,61 @@ type App struct {
 	bookmarkSetName   string // Name of bookmark being set
 	bookmarkSetCursor int    // Preserved cursor position in bookmarks panel
 
+	// Rebase destination pick state (after choosing a visual range or revset)
+	rebaseMode   bool   // True when picking a rebase destination in the log
+	rebaseSource string // Revset being rebased
+
+	// Squash destination pick state (after S on a change)
+	squashIntoMode   bool       // True when picking a squash destination in the log
+	squashIntoSource app.Target // Change whose contents are moved
+
+	// Squash waiting on confirmation or a combined message (both sides described)
+	squashMessageSource app.Target  // Change being squashed
+	squashMessageDest   *app.Target // Destination; nil squashes into the parent
+	squashRangeSource   string      // Revset of a range squashed into its oldest change
+	squashRangeDest     string      // That oldest change
+
+	// Mutation in flight (working indicator, conflicting keys suppressed)
+	busy           bool
+	afterMutation  func()                // Refresh to run once the mutation completes
+	cancelMutation context.CancelFunc    // Aborts the running mutation's jj command
+	pendingRefresh messages.RefreshScope // Sent as one RefreshRequestMsg after the current message
+	cancelEval     context.CancelFunc    // Aborts the in-flight revset evaluation
+	evalSeq        int                   // Bumped per keystroke so only the last one evaluates
+
+	// Cancelled on quit so in-flight jj commands don't outlive the UI
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// Cooldown on the last destructive key, against key-repeat
+	cooldown keyCooldown
+
+	// Status bar: repo summary and background activity spinner
+	summary           *jj.RepoSummary   // Nil until the first refresh finishes
+	git               *jj.GitColocation // Colocated git state, nil until the first refresh
+	workspaceName     string
+	refreshingSummary bool
+	spinner           spinner.Model
+	spinning          bool // True while spinner ticks are scheduled
+
+	// Clipboard copy (Y, then what to copy) and its confirmation
+	copyPending bool   // True after Y, waiting for c/i/d
+	toast       string // Shown in the top border until it expires
+	toastSeq    int    // Bumped per toast so only the latest expiry clears it
+
+	// Colocated git sync (G, then i to import or e to export)
+	gitSyncPending bool
+
+	// Workspace panel collapsed to its header in the current layout
+	workspaceCollapsedLayout bool
+
+	// Custom sidebar panels added with RegisterPanel
+	plugins []*registeredPanel
+
+	// Picker mode for scripts (--pick, --pick-file): Enter records and quits
+	pickMode PickKind
+	picked   []string
+
 	// State
 	focusedPanel int // Experience-relative: 0=main, 1=sidebar1, 2=sidebar2
 	keys         KeyMap
@@ -80,34 +278,71 @@ type App struct {
 
 	// Panel bounds for mouse coordinate mapping
 	panelBounds []PanelBound
+
+	// Last left click, for double-click detection
+	lastClickAt    time.Time
+	lastClickPanel int
+	lastClickY     int
 }
 
 // NewApp creates a new application
-func NewApp(repo *jj.Repo, repoPath string) *App {
+func NewApp(repo *jj.Repo, repoPath string, cfg *config.Config, layout *config.State) *App {
 	keys := DefaultKeyMap()
 
 	// Create panels
 	filesPanel := panels.NewFilesPanel(repo)
 	filesPanel.SetRepoPath(repoPath)
+	filesPanel.SetOrder(panels.FileOrder(layout.FileOrder))
 
+	diffOptions := jj.DiffOptions{IgnoreWhitespace: cfg.DiffIgnoreWhitespace, Context: cfg.DiffContext}
 	diffPanel := panels.NewDiffViewer(repo)
 	diffPanel.SetRepoPath(repoPath)
+	diffPanel.SetDiffOptions(diffOptions)
+
+	previewPanel := panels.NewDiffViewer(repo)
+	previewPanel.SetRepoPath(repoPath)
+	previewPanel.SetTitle("Preview")
+	previewPanel.SetDiffOptions(diffOptions)
+
+	ctx, cancel := context.WithCancel(context.Background())
 
 	app := &App{
+		ctx:               ctx,
+		cancel:            cancel,
 		repo:              repo,
 		repoPath:          repoPath,
+		cfg:               cfg,
+		layout:            layout,
+		trunkBookmark:     cfg.TrunkBookmark,
 		currentExperience: ExperienceLog,
 		// Log Experience panels
 		workspacePanel: panels.NewWorkspacePanel(repo),
 		bookmarksPanel: panels.NewBookmarksPanel(repo, repoPath),
-		logPanel:       panels.NewLogPanel(repoPath),
+		logPanel:       panels.NewLogPanel(repoPath, cfg.LogRevset, layout.LogReversed),
+		previewPanel:   previewPanel,
 		// Change Experience panels
-		filesPanel:   filesPanel,
-		diffPanel:    diffPanel,
-		helpOverlay:  floating.NewHelpOverlay(&keys),
-		focusedPanel: 0, // Main panel (log in Exp1, diff in Exp2)
-		keys:         keys,
-		help:         help.New(),
+		filesPanel: filesPanel,
+		diffPanel:  diffPanel,
+		// Operations Experience panels
+		timelinePanel:   panels.NewTimelinePanel(repo),
+		operationsPanel: panels.NewOperationsPanel(repo),
+		opLogPanel:      panels.NewLogPreviewPanel("Log at operation"),
+		historyPanel:    panels.NewLogPreviewPanel("1 History"),
+		helpOverlay:     floating.NewHelpOverlay(append(helpSections(keys), aliasHelpSections(ctx, repoPath)...)),
+		focusedPanel:    0, // Main panel (log in Exp1, diff in Exp2)
+		keys:            keys,
+		help:            help.New(),
+		spinner:         spinner.New(spinner.WithSpinner(spinner.MiniDot), spinner.WithStyle(theme.DimmedStyle)),
+	}
+
+	app.bookmarksPanel.SetTrunkBookmark(app.trunkBookmark)
+	app.bookmarksPanel.SetPullRequests(layout.PullRequests[app.sessionKey()])
+
+	// Badge log rows as signed or unsigned when there is a key to sign with
+	app.signingBackend = jj.SigningBackend(ctx, repoPath)
+	app.advanceBookmarks = cfg.AdvanceBookmarks
+	if app.signingBackend != "" {
+		app.logPanel.ShowSignatures(repo)
 	}
 
 	// Set initial focus to Log panel
@@ -117,10 +352,21 @@ func NewApp(repo *jj.Repo, repoPath string) *App {
 }
 
 func (a *App) Init() tea.Cmd {
-	return nil
+	return tea.Batch(a.initPlugins(), a.refreshSummary(), a.resolveTrunk(), a.checkForUpdate(), a.waitForSlowCall())
 }
 
 func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := a.handleMsg(msg)
+	// Keep the preview pane in step with the log selection on every path out
+	cmd = tea.Batch(cmd, a.syncPreview())
+	return model, a.flushRefresh(cmd)
+}
+
+// handleMsg updates the app for msg. Panel reloads it asks for with
+// requestRefresh go out afterwards, merged, as a RefreshRequestMsg.
+func (a *App) handleMsg(msg tea.Msg) (tea.Model, tea.Cmd) {
+	defer a.syncWorkspaceCollapse()
+
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
@@ -128,20 +374,241 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.width = msg.Width
 		a.height = msg.Height
 		a.updateLayout()
-		a.ready = true
+		if !a.ready {
+			// Panels have their sizes now, so scroll positions can be restored
+			a.ready = true
+			return a, a.restoreSession()
+		}
 		return a, nil
 
 	case tea.MouseMsg:
 		return a.handleMouse(msg)
 
+	case messages.MutationDoneMsg:
+		a.busy = false
+		a.cancelMutation = nil
+		if a.mutationRecorded {
+			a.history.Finish(msg.Err)
+		}
+		var notice tea.Cmd
+		if msg.Err != nil && !errors.Is(msg.Err, context.Canceled) {
+			a.showErrorDialog(msg.Err)
+		} else if last := a.history.Last(); msg.Err == nil && a.mutationRecorded && last != nil {
+			notice = a.notify(strings.TrimSpace(last.Name + " " + last.Subject))
+		}
+		if after := a.afterMutation; after != nil {
+			a.afterMutation = nil
+			after()
+		}
+		return a, tea.Batch(a.refreshSummary(), notice)
+
+	case messages.RepoSummaryMsg:
+		a.refreshingSummary = false
+		if msg.Err != nil {
+			return a, a.notifyError("refresh failed", msg.Err)
+		}
+		a.summary = msg.Summary
+		a.workspaceName = msg.Workspace
+		a.git = msg.Git
+		return a, nil
+
+	case messages.TrunkResolvedMsg:
+		return a, a.setTrunkBookmark(msg.Bookmark)
+
+	case messages.UpdateAvailableMsg:
+		a.update = msg.Update
+		return a, nil
+
+	case spinner.TickMsg:
+		return a, a.updateSpinner(msg)
+
+	case messages.CopiedMsg:
+		if msg.Err != nil {
+			a.showErrorDialog(msg.Err)
+			return a, nil
+		}
+		return a, a.showToast("copied " + msg.What)
+
+	case messages.WorkspaceOpenedMsg:
+		if msg.Err != nil {
+			a.showErrorDialog(msg.Err)
+			return a, nil
+		}
+		return a, a.showToast("opened workspace " + msg.Name)
+
+	case messages.WorkspaceDetailsMsg:
+		if msg.Err != nil {
+			a.showErrorDialog(msg.Err)
+			return a, nil
+		}
+		a.showInfoDialog("Workspace "+msg.Workspace.Name, workspaceDetails(msg.Workspace, msg.Usage))
+		return a, nil
+
+	case messages.NotificationExpiredMsg:
+		a.expireNotification(msg.Seq)
+		return a, nil
+
+	case messages.PullRequestsMsg:
+		a.showPullRequests(msg)
+		return a, nil
+
+	case messages.RefreshRequestMsg:
+		a.applyRefresh(msg.Scope)
+		return a, tea.Batch(a.updatePlugins(msg)...)
+
+	case messages.DescendantsCountedMsg:
+		if msg.Seq != a.askSeq || a.pendingAsk == nil {
+			return a, nil
+		}
+		decide := a.pendingAsk
+		a.pendingAsk = nil
+		if msg.Err != nil {
+			msg.Count = -1
+		}
+		return a, decide(msg.Count)
+
+	case messages.ToastExpiredMsg:
+		if msg.Seq == a.toastSeq {
+			a.toast = ""
+		}
+		return a, nil
+
+	case messages.SlowCallMsg:
+		return a, a.warnSlowCall(msg)
+
+	case messages.SlowCallExpiredMsg:
+		if msg.Seq == a.slowCallSeq {
+			a.slowCall = ""
+		}
+		return a, nil
+
+	case messages.RevsetEvalDueMsg:
+		if msg.Seq != a.evalSeq || !a.showTextInput || !isRevsetInput(a.textInputAction) {
+			return a, nil
+		}
+		return a, a.runRevsetEval(msg.Revset)
+
+	case messages.PreviewDueMsg:
+		if msg.Seq != a.previewSeq {
+			return a, nil
+		}
+		load := a.previewPanel.ChangeLoader(msg.ChangeID)
+		return a, func() tea.Msg {
+			diff, stats, err := load()
+			return messages.PreviewLoadedMsg{Seq: msg.Seq, ChangeID: msg.ChangeID, Diff: diff, Stats: stats, Err: err}
+		}
+
+	case messages.PreviewLoadedMsg:
+		// Drop diffs for a selection the user has already moved past
+		if msg.Seq == a.previewSeq {
+			a.previewPanel.ShowChange(msg.ChangeID, msg.Diff, msg.Stats, msg.Err)
+		}
+		return a, nil
+
+	case messages.RevsetEvalMsg:
+		// Drop results for text the user has already changed
+		if !a.showTextInput || !isRevsetInput(a.textInputAction) || msg.Revset != a.textInputOverlay.Value() {
+			return a, nil
+		}
+		if msg.Err != nil {
+			a.showRevsetError(msg.Err)
+		} else if msg.Count == 1 {
+			a.textInputOverlay.SetStatus("1 revision")
+		} else {
+			a.textInputOverlay.SetStatus(fmt.Sprintf("%d revisions", msg.Count))
+		}
+		return a, nil
+
+	case messages.DescriptionSuggestionMsg:
+		// Drop suggestions for an overlay that has since closed or moved on
+		if !a.showTextInput || a.textInputAction != "describe" || msg.ChangeID != a.textInputTarget.ChangeID {
+			return a, nil
+		}
+		if msg.Err != nil {
+			a.textInputOverlay.SetError(msg.Err.Error(), -1)
+		} else {
+			a.textInputOverlay.SetValue(msg.Text)
+			a.textInputOverlay.SetStatus("Suggested by describe hook")
+		}
+		return a, nil
+
+	case messages.OperationSelectedMsg:
+		// Timeline and operations list follow each other
+		if a.currentExperience != ExperienceOperations {
+			return a, nil
+		}
+		a.timelinePanel.SelectOperation(msg.OpID)
+		a.operationsPanel.SelectOperation(msg.OpID)
+		return a, a.loadOperationLog(msg.OpID)
+
+	case messages.OperationLogMsg:
+		// Drop logs for an operation the user has already scrubbed past
+		op := a.timelinePanel.SelectedOperation()
+		if a.currentExperience != ExperienceOperations || op == nil || op.ID != msg.OpID {
+			return a, nil
+		}
+		if !errors.Is(msg.Err, context.Canceled) {
+			a.opLogPanel.SetOutput(msg.Output, msg.Err)
+		}
+		return a, nil
+
 	case messages.FileSelectedMsg:
 		// When a file is selected in Change experience, update the diff view
 		if a.currentExperience == ExperienceChange && msg.Path != "" {
-			a.diffPanel.LoadFileInChange(a.selectedChangeID, msg.Path)
+			a.showSelectedFile()
 		}
 		return a, nil
 
 	case tea.KeyMsg:
+		// An action recorded by an earlier key only survives a prompt
+		if !a.showTextInput && !a.showConfirm {
+			a.pendingAction = nil
+		}
+
+		// While a mutation runs, only navigation keys get through so key
+		// repeats can't queue a second execution
+		if a.busy && !a.allowedWhileBusy(msg) {
+			if key.Matches(msg, a.keys.Escape) && a.cancelMutation != nil {
+				a.cancelMutation() // Abort; the refresh still runs on completion
+			}
+			return a, nil
+		}
+
+		// The key after Y chooses what to copy
+		if a.copyPending {
+			return a, a.handleCopyKey(msg)
+		}
+
+		// The key after G chooses the git sync direction
+		if a.gitSyncPending {
+			return a, a.handleGitSyncKey(msg)
+		}
+
+		// Keys after g type a change ID prefix
+		if a.logPanel.PrefixJumping() {
+			a.logPanel.TypePrefix(msg)
+			return a, nil
+		}
+
+		// Pick mode only navigates and picks
+		if a.pickMode != PickNone {
+			if cmd, handled := a.handlePickKey(msg); handled {
+				return a, cmd
+			}
+		}
+
+		// Browsing a past operation is read-only
+		if a.atOp != "" {
+			if cmd, handled := a.handleAtOpKey(msg); handled {
+				return a, cmd
+			}
+		}
+
+		// Ignore key repeats of a destructive action that just fired
+		if a.cooldown.Blocks(msg.String(), time.Now()) {
+			return a, nil
+		}
+
 		// Handle info overlay first if visible (any key dismisses)
 		if a.showInfo {
 			if msg.String() == "enter" || msg.String() == "esc" || msg.String() == "escape" {
@@ -162,20 +629,102 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				a.confirmAction = ""
 				return a, nil
 			case "enter":
+				if a.confirmOverlay.Pending() {
+					return a, nil // The confirmation phrase isn't typed yet
+				}
 				// Process confirmation
+				var cmd tea.Cmd
 				if a.confirmOverlay.Confirmed() {
-					a.handleConfirmAction()
+					if a.confirmAction == "abandon_range" || a.confirmAction == "abandon_descendants" || a.confirmAction == "resolve_divergent" || a.confirmAction == "push_stack" {
+						a.cooldown.Arm(msg.String(), time.Now())
+					}
+					cmd = a.handleConfirmAction()
 				}
 				a.showConfirm = false
 				a.confirmOverlay = nil
 				a.confirmAction = ""
-				return a, nil
+				return a, cmd
 			default:
 				_, cmd := a.confirmOverlay.Update(msg)
 				return a, cmd
 			}
 		}
 
+		// Handle bookmark cleanup overlay if visible
+		if a.showCleanup {
+			return a, a.handleCleanupKey(msg)
+		}
+
+		// Handle divergent changes overlay if visible
+		if a.showDivergent {
+			return a, a.handleDivergentKey(msg)
+		}
+
+		// Handle action log if visible
+		if a.showActionLog {
+			return a, a.handleActionLogKey(msg)
+		}
+
+		// Handle bridge metrics if visible
+		if a.showMetrics {
+			return a, a.handleMetricsKey(msg)
+		}
+
+		// Handle debug log if visible
+		if a.showDebugLog {
+			return a, a.handleDebugLogKey(msg)
+		}
+
+		// Handle notification log if visible
+		if a.showNotifications {
+			return a, a.handleNotificationsKey(msg)
+		}
+
+		// Handle jump list if visible
+		if a.showJump {
+			return a, a.handleJumpKey(msg)
+		}
+
+		// Handle log filter list if visible
+		if a.showLogFilter {
+			return a, a.handleLogFilterKey(msg)
+		}
+
+		// Handle context menu if visible
+		if a.showMenu {
+			return a, a.handleMenuKey(msg)
+		}
+
+		// Handle stack view if visible
+		if a.showStack {
+			return a, a.handleStackKey(msg)
+		}
+
+		// Handle revision details if visible
+		if a.showDetails {
+			return a, a.handleDetailsKey(msg)
+		}
+
+		// Handle update report if visible
+		if a.showUpdate {
+			return a, a.handleUpdateKey(msg)
+		}
+
+		// Handle operation details if visible
+		if a.showOpDiff {
+			return a, a.handleOpDiffKey(msg)
+		}
+
+		// Handle author form if visible
+		if a.showAuthor {
+			return a, a.handleAuthorKey(msg)
+		}
+
+		// Handle release notes overlay if visible
+		if a.showChangelog {
+			return a, a.handleChangelogKey(msg)
+		}
+
 		// Handle floating text input first if visible
 		if a.showTextInput {
 			switch msg.String() {
@@ -185,27 +734,113 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				a.textInputOverlay = nil
 				a.textInputAction = ""
 				return a, nil
+			case "tab":
+				// Complete alias and bookmark names, or list the operators
+				if isRevsetInput(a.textInputAction) {
+					value, matches := completeRevset(a.textInputOverlay.Value(), a.revsetCompletions)
+					a.textInputOverlay.SetValue(value)
+					if len(matches) > 1 {
+						a.textInputOverlay.SetStatus(strings.Join(matches, "  "))
+						return a, nil
+					}
+					return a, a.evalRevset(value)
+				}
+				_, cmd := a.textInputOverlay.Update(msg)
+				return a, cmd
+			case "ctrl+t":
+				// Cycle the description's template prefix
+				if a.textInputAction == "describe" && len(a.cfg.DescribeTemplates) > 0 {
+					a.textInputOverlay.SetValue(app.NextTemplate(a.textInputOverlay.Value(), a.cfg.DescribeTemplates))
+					return a, nil
+				}
+				_, cmd := a.textInputOverlay.Update(msg)
+				return a, cmd
+			case "ctrl+r":
+				// Ask the describe hook for a suggestion
+				if a.textInputAction == "describe" && a.cfg.DescribeHook != "" {
+					a.textInputOverlay.SetStatus("Generating suggestion…")
+					return a, a.suggestDescription(a.textInputTarget.ChangeID)
+				}
+				_, cmd := a.textInputOverlay.Update(msg)
+				return a, cmd
 			case "ctrl+s":
 				// Save text input
 				value := a.textInputOverlay.Value()
+
+				// A squash message can't be empty, or jj would ask for one itself
+				if (a.textInputAction == "squash_message" || a.textInputAction == "squash_range_message") && strings.TrimSpace(value) == "" {
+					a.textInputOverlay.SetError("message is empty", -1)
+					return a, nil
+				}
+
+				// Revset inputs stay open until the revset resolves
+				if isRevsetInput(a.textInputAction) {
+					count, err := jj.CheckRevset(a.ctx, a.repoPath, value)
+					if err != nil {
+						a.showRevsetError(err)
+						return a, nil
+					}
+					if count == 0 {
+						a.textInputOverlay.SetError("revset matches no revisions", -1)
+						return a, nil
+					}
+				}
+
 				a.showTextInput = false
 				a.textInputOverlay = nil
 
 				// Execute the action based on textInputAction
 				switch a.textInputAction {
 				case "describe":
-					if change := a.logPanel.SelectedChange(); change != nil {
-						_ = jj.Describe(a.repoPath, change.ChangeID, value)
-						a.logPanel.Refresh()
-						a.workspacePanel.Refresh()
-						a.bookmarksPanel.Refresh()
+					a.textInputAction = ""
+					if strings.TrimSpace(value) == "" && a.cfg.ConfirmEmptyDescription {
+						a.showConfirmDialog("Empty Description",
+							fmt.Sprintf("Save %s with an empty description?", a.textInputTarget.ChangeID),
+							"describe_empty")
+						return a, nil
+					}
+					return a, a.saveDescription(value)
+				case "create_tag":
+					if value != "" {
+						return a, a.runOnTarget(a.textInputTarget, func(ctx context.Context, changeID string) error {
+							return jj.CreateTag(ctx, a.repoPath, value, changeID)
+						}, a.refreshLogPanels)
 					}
+				case "export_patches":
+					if value != "" {
+						paths, err := app.ExportPatches(a.ctx, a.repoPath, a.selectedRangeIDs(), value)
+						if err != nil {
+							a.showErrorDialog(err)
+						} else {
+							a.logPanel.SetVisualMode(false)
+							a.showInfoDialog("Exported", fmt.Sprintf("Wrote %d patches to %s", len(paths), value))
+						}
+					}
+				case "rebase_revset":
+					a.enterRebaseMode(value)
+				case "rebase_destination":
+					a.textInputAction = ""
+					return a, a.rebaseOntoRevset(value)
+				case "filter_revset":
+					a.filterLog(floating.JumpTarget{Label: value, Revset: value})
+				case "renumber_stack":
+					a.textInputAction = ""
+					return a, a.renumberStack(strings.TrimSpace(value))
+				case "squash_message":
+					a.textInputAction = ""
+					return a, a.runSquash(a.squashMessageSource, a.squashMessageDest, value)
+				case "squash_range_message":
+					a.textInputAction = ""
+					return a, a.runSquashRange(value)
+				case "backout":
+					a.textInputAction = ""
+					return a, a.backout(value)
 				case "workspace_add":
 					if value != "" {
 						if err := a.repo.WorkspaceAdd(value, ""); err != nil {
-							a.showInfoDialog("Error", err.Error())
+							a.showErrorDialog(err)
 						} else {
-							a.workspacePanel.Refresh()
+							a.requestRefresh(messages.RefreshWorkspaces)
 						}
 					}
 				}
@@ -213,6 +848,12 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return a, nil
 			default:
 				_, cmd := a.textInputOverlay.Update(msg)
+				switch {
+				case isRevsetInput(a.textInputAction):
+					cmd = tea.Batch(cmd, a.evalRevset(a.textInputOverlay.Value()))
+				case a.textInputAction == "describe":
+					a.lintDescription()
+				}
 				return a, cmd
 			}
 		}
@@ -220,11 +861,15 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Handle floating help if visible
 		if a.showHelp {
 			switch {
+			case a.helpOverlay.Filtering():
+				// Typing into the help filter; esc/?/q are text there
+				_, cmd := a.helpOverlay.Update(msg)
+				return a, cmd
 			case key.Matches(msg, a.keys.Escape), key.Matches(msg, a.keys.Help):
 				a.showHelp = false
 				return a, nil
 			case key.Matches(msg, a.keys.Quit):
-				return a, tea.Quit
+				return a, a.quit()
 			default:
 				_, cmd := a.helpOverlay.Update(msg)
 				return a, cmd
@@ -234,162 +879,160 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Global keys
 		switch {
 		case key.Matches(msg, a.keys.Quit):
-			return a, tea.Quit
+			return a, a.quit()
 
 		case key.Matches(msg, a.keys.Help):
 			a.showHelp = true
 			return a, nil
 
-		case key.Matches(msg, a.keys.Escape),
-			msg.Type == tea.KeyEscape,
-			msg.Type == tea.KeyEsc,
-			msg.String() == "esc",
-			msg.String() == "escape",
-			msg.String() == "ctrl+g",
-			msg.Type == tea.KeyCtrlG:
+		case key.Matches(msg, a.keys.TogglePreview) && a.currentExperience == ExperienceLog:
+			a.showPreview = !a.showPreview
+			a.previewCommitID = ""
+			a.updateLayout()
+			return a, nil
+
+		case key.Matches(msg, a.keys.ReverseLog) && a.currentExperience == ExperienceLog:
+			return a, a.reverseLog()
+
+		case key.Matches(msg, a.keys.Operations) && a.currentExperience == ExperienceLog:
+			return a, a.enterOperationsExperience()
+
+		case key.Matches(msg, a.keys.Jump) && a.currentExperience == ExperienceLog:
+			a.openJump()
+			return a, nil
+
+		case key.Matches(msg, a.keys.LogFilter) && a.currentExperience == ExperienceLog:
+			a.openLogFilter()
+			return a, nil
+
+		case key.Matches(msg, a.keys.ContextMenu):
+			return a, a.openContextMenu()
+
+		case key.Matches(msg, a.keys.Divergent) && a.currentExperience == ExperienceLog:
+			a.openDivergent()
+			return a, nil
+
+		case key.Matches(msg, a.keys.Stack) && a.currentExperience == ExperienceLog:
+			a.openStack()
+			return a, nil
+
+		case key.Matches(msg, a.keys.AdvanceBookmarks) && a.currentExperience == ExperienceLog:
+			return a, a.toggleAdvanceBookmarks()
+
+		case key.Matches(msg, a.keys.UpdateFromTrunk) && a.currentExperience == ExperienceLog:
+			return a, a.updateFromTrunk(msg)
+
+		case key.Matches(msg, a.keys.Repeat):
+			return a, a.repeatLastAction()
+
+		case key.Matches(msg, a.keys.LoadFullDiff) && a.truncatedDiff() != nil:
+			// L loads a cut-off diff on screen fully; otherwise it's the action log
+			a.truncatedDiff().LoadFully()
+			return a, nil
+
+		case key.Matches(msg, a.keys.ActionLog):
+			a.openActionLog()
+			return a, nil
+
+		case key.Matches(msg, a.keys.Notifications):
+			a.openNotifications()
+			return a, nil
+
+		case key.Matches(msg, a.keys.Metrics):
+			a.openMetrics()
+			return a, nil
+
+		case key.Matches(msg, a.keys.DebugLog):
+			a.openDebugLog()
+			return a, nil
+
+		case key.Matches(msg, a.keys.Changelog):
+			a.openChangelog()
+			return a, nil
+
+		case key.Matches(msg, a.keys.GitSync):
+			return a, a.startGitSync()
+
+		case key.Matches(msg, a.keys.Snapshot):
+			return a, a.snapshotWorkingCopy()
+
+		case key.Matches(msg, a.keys.NewOnWorkingCopy):
+			return a, a.newOnWorkingCopy(msg)
+
+		case key.Matches(msg, a.keys.Copy) && a.currentExperience != ExperienceOperations:
+			a.copyPending = true
+			return a, nil
+
+		case key.Matches(msg, a.keys.WrapDiff) && a.currentExperience == ExperienceChange:
+			a.diffPanel.ToggleWrap()
+			return a, nil
+
+		case key.Matches(msg, a.keys.IgnoreSpace) && a.currentExperience == ExperienceChange:
+			a.diffPanel.ToggleIgnoreWhitespace()
+			return a, nil
+
+		case key.Matches(msg, a.keys.LessContext) && a.currentExperience == ExperienceChange:
+			a.diffPanel.AdjustContext(-1)
+			return a, nil
+
+		case key.Matches(msg, a.keys.MoreContext) && a.currentExperience == ExperienceChange:
+			a.diffPanel.AdjustContext(1)
+			return a, nil
+
+		case key.Matches(msg, a.keys.ToggleMessage) && a.currentExperience == ExperienceChange:
+			a.diffPanel.ToggleMessage()
+			return a, nil
+
+		case key.Matches(msg, a.keys.GrowSidebar):
+			a.resizeSidebar(sidebarResizeStep)
+			return a, nil
+
+		case key.Matches(msg, a.keys.ShrinkSidebar):
+			a.resizeSidebar(-sidebarResizeStep)
+			return a, nil
+
+		case key.Matches(msg, a.keys.GrowWorkspace) && a.currentExperience == ExperienceLog:
+			a.resizeWorkspace(1)
+			return a, nil
+
+		case key.Matches(msg, a.keys.ShrinkWorkspace) && a.currentExperience == ExperienceLog:
+			a.resizeWorkspace(-1)
+			return a, nil
+
+		case a.isEscape(msg):
 			// First check if we're in bookmark set mode
 			if a.bookmarkSetMode {
 				a.exitBookmarkSetMode()
 				return a, nil
 			}
-			// Check if we need to exit cursor mode in browsable panels
-			if a.currentExperience == ExperienceLog && a.focusedPanel == 1 && a.workspacePanel.IsEntered() {
-				a.workspacePanel.SetEntered(false)
+			if a.rebaseMode {
+				a.exitRebaseMode()
 				return a, nil
 			}
-			if a.currentExperience == ExperienceLog && a.focusedPanel == 2 && a.bookmarksPanel.IsEntered() {
-				a.bookmarksPanel.SetEntered(false)
+			if a.squashIntoMode {
+				a.exitSquashIntoMode()
 				return a, nil
 			}
-			// Go back to previous experience
-			if a.currentExperience == ExperienceChange {
-				a.exitChangeExperience()
-			}
-			return a, nil
-
-		case msg.Type == tea.KeyLeft, msg.String() == "left":
-			// Left arrow navigation
-			// First check if we're in bookmark set mode and on log panel
-			if a.bookmarkSetMode && a.currentExperience == ExperienceLog && a.focusedPanel == 0 {
-				a.exitBookmarkSetMode()
+			if a.currentExperience == ExperienceLog && a.logPanel.InVisualMode() {
+				a.logPanel.SetVisualMode(false)
 				return a, nil
 			}
-			// Check if we need to exit entered mode
-			if a.currentExperience == ExperienceLog && a.focusedPanel == 1 && a.workspacePanel.IsEntered() {
-				a.workspacePanel.SetEntered(false)
-				return a, nil
+			// Browsable panels leave cursor mode first
+			if cmd, handled := a.panelActions(msg); handled {
+				return a, cmd
 			}
-			if a.currentExperience == ExperienceLog && a.focusedPanel == 2 && a.bookmarksPanel.IsEntered() {
-				a.bookmarksPanel.SetEntered(false)
+			if a.fileHistoryPath != "" {
+				a.exitFileHistory()
 				return a, nil
 			}
+			// Go back to previous experience
 			if a.currentExperience == ExperienceChange {
-				if a.focusedPanel == 0 {
-					// From Diff → Files panel
-					a.setFocus(1)
-					return a, nil
-				}
-				// From Files → Exit to Log experience
 				a.exitChangeExperience()
-				return a, nil
-			} else if a.currentExperience == ExperienceLog && a.focusedPanel == 0 {
-				// From Log panel → Workspace
-				a.setFocus(1)
-				return a, nil
-			}
-
-		case msg.Type == tea.KeyRight, msg.String() == "right":
-			// Right arrow navigation
-			// First check if we need to exit entered mode and move to log
-			if a.currentExperience == ExperienceLog && a.focusedPanel == 1 && a.workspacePanel.IsEntered() {
-				a.workspacePanel.SetEntered(false)
-				a.setFocus(0) // Move to log
-				return a, nil
-			}
-			if a.currentExperience == ExperienceLog && a.focusedPanel == 2 && a.bookmarksPanel.IsEntered() {
-				a.bookmarksPanel.SetEntered(false)
-				a.setFocus(0) // Move to log
-				return a, nil
 			}
-			if a.currentExperience == ExperienceLog {
-				if a.focusedPanel == 0 {
-					// From Log panel → drill into change
-					if change := a.logPanel.SelectedChange(); change != nil {
-						a.enterChangeExperience(change.ChangeID, change.IsWorkingCopy)
-						return a, nil
-					}
-				} else if a.focusedPanel == 1 || a.focusedPanel == 2 {
-					// From Workspace or Bookmarks → Log panel
-					a.setFocus(0)
-					return a, nil
-				}
-			} else if a.currentExperience == ExperienceChange {
-				if a.focusedPanel == 1 {
-					// From Files → Diff panel
-					a.setFocus(0)
-					return a, nil
-				}
-				// From Diff → no action (already at rightmost)
-			}
-
-		case key.Matches(msg, a.keys.Enter):
-			if a.currentExperience == ExperienceLog {
-				switch a.focusedPanel {
-				case 0: // Log panel
-					if a.bookmarkSetMode {
-						// Confirm bookmark set
-						if change := a.logPanel.SelectedChange(); change != nil {
-							a.executeBookmarkSet(change.CommitID)
-						}
-						return a, nil
-					}
-					// Normal: jj edit
-					if change := a.logPanel.SelectedChange(); change != nil {
-						_ = jj.Edit(a.repoPath, change.ChangeID)
-						a.logPanel.Refresh()
-						a.workspacePanel.Refresh()
-						a.bookmarksPanel.Refresh()
-					}
-					return a, nil
-				case 1: // Workspace panel
-					if !a.workspacePanel.IsEntered() {
-						a.workspacePanel.SetEntered(true)
-					} else if ws := a.workspacePanel.SelectedWorkspace(); ws != nil {
-						// Switch workspace using jj-lib (close and reopen repo)
-						if err := a.switchWorkspace(ws.RootPath); err != nil {
-							a.showInfoDialog("Error", err.Error())
-						} else {
-							a.workspacePanel.SetEntered(false)
-							a.setFocus(0) // Return to log view
-						}
-					}
-					return a, nil
-				case 2: // Bookmarks panel
-					if !a.bookmarksPanel.IsEntered() {
-						a.bookmarksPanel.SetEntered(true)
-					} else if bm := a.bookmarksPanel.SelectedBookmark(); bm != nil {
-						// Enter starts bookmark set mode
-						a.enterBookmarkSetMode(bm.Name)
-					}
-					return a, nil
-				}
-			}
-
-		case msg.Type == tea.KeyDown, msg.String() == "down":
-			// Down from Workspace → Bookmarks (in Log experience)
-			// Only move between panels if not in entered mode
-			if a.currentExperience == ExperienceLog && a.focusedPanel == 1 && !a.workspacePanel.IsEntered() {
-				a.setFocus(2)
-				return a, nil
-			}
-
-		case msg.Type == tea.KeyUp, msg.String() == "up":
-			// Up from Bookmarks → Workspace (in Log experience)
-			// Only move between panels if not in entered mode
-			if a.currentExperience == ExperienceLog && a.focusedPanel == 2 && !a.bookmarksPanel.IsEntered() {
-				a.setFocus(1)
-				return a, nil
+			if a.currentExperience == ExperienceOperations {
+				a.exitOperationsExperience()
 			}
+			return a, nil
 
 		case key.Matches(msg, a.keys.Panel0):
 			a.setFocus(0) // Main panel (log or diff)
@@ -407,219 +1050,33 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return a, nil
 
 		case key.Matches(msg, a.keys.NextPanel):
-			// If in cursor mode in a browsable panel, exit to focus mode
-			if a.currentExperience == ExperienceLog && a.focusedPanel == 1 && a.workspacePanel.IsEntered() {
-				a.workspacePanel.SetEntered(false)
-				return a, nil
-			}
-			if a.currentExperience == ExperienceLog && a.focusedPanel == 2 && a.bookmarksPanel.IsEntered() {
-				a.bookmarksPanel.SetEntered(false)
-				return a, nil
+			// Browsable panels leave cursor mode before focus moves on
+			if cmd, handled := a.panelActions(msg); handled {
+				return a, cmd
 			}
-			maxPanels := a.maxPanelsForExperience()
-			a.setFocus((a.focusedPanel + 1) % maxPanels)
+			a.cyclePanel(1)
 			return a, nil
 
 		case key.Matches(msg, a.keys.PrevPanel):
-			// If in cursor mode in a browsable panel, exit to focus mode
-			if a.currentExperience == ExperienceLog && a.focusedPanel == 1 && a.workspacePanel.IsEntered() {
-				a.workspacePanel.SetEntered(false)
-				return a, nil
-			}
-			if a.currentExperience == ExperienceLog && a.focusedPanel == 2 && a.bookmarksPanel.IsEntered() {
-				a.bookmarksPanel.SetEntered(false)
-				return a, nil
+			if cmd, handled := a.panelActions(msg); handled {
+				return a, cmd
 			}
-			maxPanels := a.maxPanelsForExperience()
-			a.setFocus((a.focusedPanel + maxPanels - 1) % maxPanels)
+			a.cyclePanel(-1)
 			return a, nil
 		}
 
-		// Log view change actions (only in Log experience, Log panel focused)
-		if a.currentExperience == ExperienceLog && a.focusedPanel == 0 {
-			switch {
-			case key.Matches(msg, a.keys.NewChange):
-				// Create new change after selected
-				if change := a.logPanel.SelectedChange(); change != nil {
-					_ = jj.NewChange(a.repoPath, change.ChangeID)
-					a.logPanel.Refresh()
-					a.workspacePanel.Refresh()
-					a.bookmarksPanel.Refresh()
-				}
-				return a, nil
-
-			case key.Matches(msg, a.keys.Describe):
-				// Edit change description
-				if change := a.logPanel.SelectedChange(); change != nil {
-					// Get current description
-					currentDesc, _ := jj.GetDescription(a.repoPath, change.ChangeID)
-
-					// Show text input overlay
-					a.textInputOverlay = floating.NewTextInputOverlay(
-						"Describe Change",
-						"Enter description...",
-						currentDesc,
-					)
-					a.textInputOverlay.SetSize(a.width, a.height-1)
-					a.showTextInput = true
-					a.textInputAction = "describe"
-				}
-				return a, nil
-
-			case key.Matches(msg, a.keys.Abandon):
-				// Abandon change
-				if change := a.logPanel.SelectedChange(); change != nil {
-					_ = jj.Abandon(a.repoPath, change.ChangeID)
-					a.logPanel.Refresh()
-					a.workspacePanel.Refresh()
-					a.bookmarksPanel.Refresh()
-				}
-				return a, nil
-
-			case key.Matches(msg, a.keys.SquashChange):
-				// Squash change into parent
-				if change := a.logPanel.SelectedChange(); change != nil {
-					_ = jj.Squash(a.repoPath, change.ChangeID)
-					a.logPanel.Refresh()
-					a.workspacePanel.Refresh()
-					a.bookmarksPanel.Refresh()
-				}
-				return a, nil
-			}
-		}
-
-		// Bookmark edit action ('e' key in bookmarks panel when entered)
-		if a.currentExperience == ExperienceLog && a.focusedPanel == 2 && a.bookmarksPanel.IsEntered() {
-			if msg.String() == "e" {
-				if bm := a.bookmarksPanel.SelectedBookmark(); bm != nil {
-					// Use Navigation to find edit target (tip of branch or boundary)
-					if revisions, err := a.repo.Log(); err == nil {
-						nav := app.NewNavigation(a.repoPath, revisions)
-						if target := nav.FindBookmarkEditTarget(bm.Name); target != nil {
-							_ = nav.EditRevision(target.ChangeID)
-						}
-					}
-					a.bookmarksPanel.SetEntered(false)
-					a.setFocus(0) // Return to log view
-					a.logPanel.Refresh()
-					a.workspacePanel.Refresh()
-					a.bookmarksPanel.Refresh()
-				}
-				return a, nil
-			}
-		}
-
-		// Workspace panel actions
-		if a.currentExperience == ExperienceLog && a.focusedPanel == 1 {
-			// 'a' key in focus mode (not entered) - add workspace
-			if msg.String() == "a" && !a.workspacePanel.IsEntered() {
-				a.textInputOverlay = floating.NewTextInputOverlay(
-					"Add Workspace",
-					"Enter destination path...",
-					"",
-				)
-				a.textInputOverlay.SetSize(a.width, a.height-1)
-				a.showTextInput = true
-				a.textInputAction = "workspace_add"
-				return a, nil
-			}
-
-			// 'd' key in cursor mode (entered) - forget workspace
-			if msg.String() == "d" && a.workspacePanel.IsEntered() {
-				if ws := a.workspacePanel.SelectedWorkspace(); ws != nil {
-					if ws.IsCurrent {
-						a.showInfoDialog("Error", "Cannot forget current workspace")
-					} else {
-						if err := a.repo.WorkspaceForget(ws.Name); err != nil {
-							a.showInfoDialog("Error", err.Error())
-						} else {
-							// Adjust cursor if needed and refresh
-							if a.workspacePanel.Cursor() >= a.workspacePanel.Count()-1 {
-								a.workspacePanel.SetCursor(a.workspacePanel.Count() - 2)
-							}
-							a.workspacePanel.Refresh()
-						}
-					}
-				}
-				return a, nil
-			}
+		// Offer the key to the focused panel's actions, then its navigation
+		if cmd, handled := a.panelActions(msg); handled {
+			return a, cmd
 		}
-
-		// File operations (only in Change experience, Files panel focused, working copy)
-		if a.currentExperience == ExperienceChange && a.focusedPanel == 1 && a.selectedChangeIsWorking {
-			switch {
-			case msg.Type == tea.KeyDelete || msg.Type == tea.KeyBackspace ||
-				msg.String() == "delete" || msg.String() == "backspace":
-				// Restore (discard) file changes - using Delete/Backspace keys
-				if file := a.filesPanel.SelectedFile(); file != nil {
-					_ = jj.RestoreFile(a.repoPath, file.Path) // TODO: handle error
-					a.filesPanel.LoadForChange(a.selectedChangeID)
-
-					// If no files remain, exit to log (consistent with squash)
-					if a.filesPanel.Count() == 0 {
-						a.exitChangeExperience()
-						a.logPanel.Refresh()
-						a.workspacePanel.Refresh()
-						a.bookmarksPanel.Refresh()
-					} else {
-						// Update diff view for remaining files
-						if newFile := a.filesPanel.SelectedFile(); newFile != nil {
-							a.diffPanel.LoadFileInChange(a.selectedChangeID, newFile.Path)
-						} else {
-							a.diffPanel.LoadChange(a.selectedChangeID)
-						}
-					}
-				}
-				return a, nil
-
-			case msg.String() == "s":
-				// Squash file to parent
-				if file := a.filesPanel.SelectedFile(); file != nil {
-					_ = jj.SquashFile(a.repoPath, file.Path) // TODO: handle error
-					a.filesPanel.LoadForChange(a.selectedChangeID)
-
-					// If no files remain, exit to log
-					if a.filesPanel.Count() == 0 {
-						a.exitChangeExperience()
-						a.logPanel.Refresh()
-						a.workspacePanel.Refresh()
-						a.bookmarksPanel.Refresh()
-					} else {
-						// Update diff view for remaining files
-						if newFile := a.filesPanel.SelectedFile(); newFile != nil {
-							a.diffPanel.LoadFileInChange(a.selectedChangeID, newFile.Path)
-						} else {
-							a.diffPanel.LoadChange(a.selectedChangeID)
-						}
-					}
-				}
-				return a, nil
+		if entry := a.panelAt(a.focusedPanel); entry != nil {
+			if _, cmd := entry.panel.Update(msg); cmd != nil {
+				cmds = append(cmds, cmd)
 			}
 		}
 
-		// Route to focused panel based on current experience
-		var cmd tea.Cmd
-		switch a.currentExperience {
-		case ExperienceLog:
-			switch a.focusedPanel {
-			case 0:
-				_, cmd = a.logPanel.Update(msg)
-			case 1:
-				_, cmd = a.workspacePanel.Update(msg)
-			case 2:
-				_, cmd = a.bookmarksPanel.Update(msg)
-			}
-		case ExperienceChange:
-			switch a.focusedPanel {
-			case 0:
-				_, cmd = a.diffPanel.Update(msg)
-			case 1:
-				_, cmd = a.filesPanel.Update(msg)
-			}
-		}
-		if cmd != nil {
-			cmds = append(cmds, cmd)
-		}
+	default:
+		cmds = append(cmds, a.updatePlugins(msg)...)
 	}
 
 	return a, tea.Batch(cmds...)
@@ -636,16 +1093,26 @@ func (a *App) View() string {
 	switch a.currentExperience {
 	case ExperienceLog:
 		// Log experience: Workspace + Bookmarks sidebar, Log main
-		sidebar = lipgloss.JoinVertical(lipgloss.Left,
-			a.workspacePanel.View(),
-			a.bookmarksPanel.View(),
-		)
+		var views []string
+		if a.workspaceShown() {
+			views = append(views, a.workspacePanel.View())
+		}
+		views = append(views, a.bookmarksPanel.View())
+		sidebar = lipgloss.JoinVertical(lipgloss.Left, append(views, a.pluginViews()...)...)
 		mainPanel = a.logPanel.View()
+		if a.showPreview {
+			mainPanel = lipgloss.JoinHorizontal(lipgloss.Top, mainPanel, a.previewPanel.View())
+		}
 
 	case ExperienceChange:
 		// Change experience: Files sidebar, Diff main
-		sidebar = a.filesPanel.View()
+		sidebar = lipgloss.JoinVertical(lipgloss.Left, append([]string{a.filesSidebar().View()}, a.pluginViews()...)...)
 		mainPanel = a.diffPanel.View()
+
+	case ExperienceOperations:
+		// Operations experience: Operations sidebar, Timeline above the log preview
+		sidebar = lipgloss.JoinVertical(lipgloss.Left, append([]string{a.operationsPanel.View()}, a.pluginViews()...)...)
+		mainPanel = lipgloss.JoinVertical(lipgloss.Left, a.timelinePanel.View(), a.opLogPanel.View())
 	}
 
 	// Build main layout
@@ -654,26 +1121,104 @@ func (a *App) View() string {
 		mainPanel,
 	)
 
-	// Add space at top of main content
-	mainWithSpacing := "\n" + main
+	// Add space at top of main content
+	mainWithSpacing := "\n" + main
+
+	// Wrap main in border with breadcrumb tabs
+	borderedMain := a.renderMainFrame(mainWithSpacing)
+
+	// Build help bar
+	helpBar := a.renderHelpBar()
+
+	// Combine bordered main + status + help
+	fullView := lipgloss.JoinVertical(lipgloss.Left, borderedMain, a.renderStatusBar(), helpBar)
+
+	// Stack notifications inside the main frame, above its bottom border
+	fullView = a.overlayNotifications(fullView, lipgloss.Height(borderedMain)-1)
+
+	// Overlay floating help if visible
+	if a.showHelp {
+		fullView = a.overlayHelp(fullView)
+	}
+
+	// Overlay text input if visible
+	if a.showTextInput {
+		fullView = a.overlayTextInput(fullView)
+	}
+
+	// Overlay bookmark cleanup if visible
+	if a.showCleanup {
+		fullView = a.overlayCleanup(fullView)
+	}
+
+	// Overlay divergent changes if visible
+	if a.showDivergent {
+		fullView = a.overlayDivergent(fullView)
+	}
+
+	// Overlay operation details if visible
+	if a.showOpDiff {
+		fullView = a.overlayOpDiff(fullView)
+	}
+
+	// Overlay author form if visible
+	if a.showAuthor {
+		fullView = a.overlayAuthor(fullView)
+	}
+
+	// Overlay action log if visible
+	if a.showActionLog {
+		fullView = a.overlayActionLog(fullView)
+	}
+
+	// Overlay bridge metrics if visible
+	if a.showMetrics {
+		fullView = a.overlayMetrics(fullView)
+	}
+
+	// Overlay debug log if visible
+	if a.showDebugLog {
+		fullView = a.overlayDebugLog(fullView)
+	}
+
+	// Overlay notification log if visible
+	if a.showNotifications {
+		fullView = a.overlayNotificationLog(fullView)
+	}
+
+	// Overlay jump list if visible
+	if a.showJump {
+		fullView = a.overlayJump(fullView)
+	}
+
+	// Overlay log filter list if visible
+	if a.showLogFilter {
+		fullView = a.overlayLogFilter(fullView)
+	}
 
-	// Wrap main in border with breadcrumb tabs
-	borderedMain := a.renderMainFrame(mainWithSpacing)
+	// Overlay context menu if visible
+	if a.showMenu {
+		fullView = a.overlayMenu(fullView)
+	}
 
-	// Build help bar
-	helpBar := a.renderHelpBar()
+	// Overlay stack view if visible
+	if a.showStack {
+		fullView = a.overlayStack(fullView)
+	}
 
-	// Combine bordered main + help
-	fullView := lipgloss.JoinVertical(lipgloss.Left, borderedMain, helpBar)
+	// Overlay revision details if visible
+	if a.showDetails {
+		fullView = a.overlayDetails(fullView)
+	}
 
-	// Overlay floating help if visible
-	if a.showHelp {
-		fullView = a.overlayHelp(fullView)
+	// Overlay update report if visible
+	if a.showUpdate {
+		fullView = a.overlayUpdate(fullView)
 	}
 
-	// Overlay text input if visible
-	if a.showTextInput {
-		fullView = a.overlayTextInput(fullView)
+	// Overlay release notes if visible
+	if a.showChangelog {
+		fullView = a.overlayChangelog(fullView)
 	}
 
 	// Overlay confirm dialog if visible
@@ -691,36 +1236,41 @@ func (a *App) View() string {
 
 // clearAllFocus clears focus from all panels
 func (a *App) clearAllFocus() {
-	a.logPanel.SetFocused(false)
-	a.workspacePanel.SetFocused(false)
-	a.bookmarksPanel.SetFocused(false)
+	for _, exp := range []Experience{ExperienceLog, ExperienceChange, ExperienceOperations} {
+		for _, entry := range a.panelsFor(exp) {
+			entry.panel.SetFocused(false)
+		}
+	}
+	// Only one of these is the Change sidebar at a time
 	a.filesPanel.SetFocused(false)
-	a.diffPanel.SetFocused(false)
+	a.historyPanel.SetFocused(false)
 }
 
 // setFocusForExperience sets default focus for the current experience
 func (a *App) setFocusForExperience() {
 	a.clearAllFocus()
 
-	switch a.currentExperience {
-	case ExperienceLog:
-		a.focusedPanel = 0
-		a.logPanel.SetFocused(true)
-	case ExperienceChange:
+	a.focusedPanel = 0
+	if a.currentExperience == ExperienceChange {
 		a.focusedPanel = 1 // Files panel is default focus
-		a.filesPanel.SetFocused(true)
 	}
+	a.panelAt(a.focusedPanel).panel.SetFocused(true)
 }
 
 // maxPanelsForExperience returns the number of panels in the current experience
 func (a *App) maxPanelsForExperience() int {
-	switch a.currentExperience {
-	case ExperienceLog:
-		return 3 // log, workspace, bookmarks
-	case ExperienceChange:
-		return 2 // diff, files
+	return builtinPanelCount(a.currentExperience) + len(a.pluginsFor(a.currentExperience))
+}
+
+// cyclePanel moves focus step panels along (tab/shift+tab), wrapping
+// around and skipping a hidden workspace panel
+func (a *App) cyclePanel(step int) {
+	maxPanels := a.maxPanelsForExperience()
+	panel := (a.focusedPanel + step + maxPanels) % maxPanels
+	if a.currentExperience == ExperienceLog && panel == 1 && !a.workspaceShown() {
+		panel = (panel + step + maxPanels) % maxPanels
 	}
-	return 3
+	a.setFocus(panel)
 }
 
 func (a *App) setFocus(panel int) {
@@ -738,28 +1288,14 @@ func (a *App) setFocus(panel int) {
 	if panel < 0 {
 		panel = 0
 	}
+	// A hidden workspace panel hands focus to bookmarks below it
+	if a.currentExperience == ExperienceLog && panel == 1 && !a.workspaceShown() {
+		panel = 2
+	}
 
 	a.focusedPanel = panel
-
-	switch a.currentExperience {
-	case ExperienceLog:
-		// 0=log, 1=workspace, 2=bookmarks
-		switch panel {
-		case 0:
-			a.logPanel.SetFocused(true)
-		case 1:
-			a.workspacePanel.SetFocused(true)
-		case 2:
-			a.bookmarksPanel.SetFocused(true)
-		}
-	case ExperienceChange:
-		// 0=diff, 1=files
-		switch panel {
-		case 0:
-			a.diffPanel.SetFocused(true)
-		case 1:
-			a.filesPanel.SetFocused(true)
-		}
+	if entry := a.panelAt(panel); entry != nil {
+		entry.panel.SetFocused(true)
 	}
 }
 
@@ -768,6 +1304,7 @@ func (a *App) enterChangeExperience(changeID string, isWorkingCopy bool) {
 	a.currentExperience = ExperienceChange
 	a.selectedChangeID = changeID
 	a.selectedChangeIsWorking = isWorkingCopy
+	a.filesPanel.SetWorkingCopy(isWorkingCopy)
 
 	// Set focus first so files panel renders with correct highlight
 	a.setFocusForExperience()
@@ -775,19 +1312,42 @@ func (a *App) enterChangeExperience(changeID string, isWorkingCopy bool) {
 	// Load files for this change (will render with first file highlighted)
 	a.filesPanel.LoadForChange(changeID)
 
-	// Load diff for the first file (if any), otherwise load full change diff
-	if file := a.filesPanel.SelectedFile(); file != nil {
-		a.diffPanel.LoadFileInChange(changeID, file.Path)
+	// Commit message header above the diff
+	if desc, err := jj.GetDescription(a.readCtx(), a.repoPath, changeID); err == nil {
+		a.diffPanel.SetDescription(desc)
 	} else {
-		a.diffPanel.LoadChange(changeID)
+		a.diffPanel.ClearDescription()
 	}
 
+	// Load diff for the first file (if any), otherwise load full change diff
+	a.showSelectedFile()
+
 	// Recalculate layout for new experience
 	a.updateLayout()
 }
 
+// syncPreview schedules reloading the preview pane when the selected log
+// revision changes, once the selection rests for previewDelay. Keyed on
+// commit ID so rewrites of the same change are picked up too.
+func (a *App) syncPreview() tea.Cmd {
+	if !a.showPreview || a.currentExperience != ExperienceLog {
+		return nil
+	}
+	change := a.logPanel.SelectedChange()
+	if change == nil || change.CommitID == a.previewCommitID {
+		return nil
+	}
+	a.previewCommitID = change.CommitID
+	a.previewSeq++
+	seq, changeID := a.previewSeq, change.ChangeID
+	return tea.Tick(previewDelay, func(time.Time) tea.Msg {
+		return messages.PreviewDueMsg{Seq: seq, ChangeID: changeID}
+	})
+}
+
 // exitChangeExperience returns to the Log experience
 func (a *App) exitChangeExperience() {
+	a.fileHistoryPath = ""
 	a.currentExperience = ExperienceLog
 	a.selectedChangeID = ""
 	a.selectedChangeIsWorking = false
@@ -799,52 +1359,139 @@ func (a *App) exitChangeExperience() {
 	a.setFocusForExperience()
 }
 
+// enterOperationsExperience opens the operation timeline at the present,
+// previewing the log there
+func (a *App) enterOperationsExperience() tea.Cmd {
+	a.currentExperience = ExperienceOperations
+	// jj commands run since the last refresh (undo, the user's shell) have
+	// added operations the repo hasn't loaded yet
+	_ = a.repo.Reload()
+	a.timelinePanel.Refresh()
+	a.operationsPanel.Refresh()
+	a.opLogPanel.SetOutput(&jj.LogOutput{}, nil)
+
+	a.setFocusForExperience()
+	a.updateLayout()
+
+	op := a.timelinePanel.SelectedOperation()
+	if op == nil {
+		return nil
+	}
+	a.operationsPanel.SelectOperation(op.ID)
+	return a.loadOperationLog(op.ID)
+}
+
+// exitOperationsExperience returns to the Log experience
+func (a *App) exitOperationsExperience() {
+	if a.cancelOpLog != nil {
+		a.cancelOpLog()
+		a.cancelOpLog = nil
+	}
+	a.currentExperience = ExperienceLog
+	a.updateLayout()
+	a.setFocusForExperience()
+}
+
+// loadOperationLog loads the log as of an operation for the preview. Only
+// the latest scrub position matters, so the previous load is cancelled.
+func (a *App) loadOperationLog(opID string) tea.Cmd {
+	if a.cancelOpLog != nil {
+		a.cancelOpLog()
+	}
+	ctx, cancel := context.WithCancel(a.ctx)
+	a.cancelOpLog = cancel
+	a.opLogPanel.SetTitle("Log at " + opID)
+
+	repoPath := a.repoPath
+	return func() tea.Msg {
+		defer cancel()
+		output, err := jj.LogCLIAtOp(ctx, repoPath, opID)
+		return messages.OperationLogMsg{OpID: opID, Output: output, Err: err}
+	}
+}
+
 func (a *App) updateLayout() {
 	// Calculate dimensions
-	// Account for outer border (2 chars width, 2 chars height), help bar (1 line), and top spacing (1 line)
-	availableWidth := a.width - 2  // Border takes 2 chars
-	availableHeight := a.height - 4 // Border (2) + help bar (1) + top spacing (1)
+	// Account for outer border (2 chars width, 2 chars height), status and help bars, and top spacing (1 line)
+	availableWidth := a.width - 2                     // Border takes 2 chars
+	availableHeight := a.height - 4 - statusBarHeight // Border (2) + help bar (1) + top spacing (1)
 
-	sidebarWidth := SidebarWidth
-	if a.width < 100 {
-		sidebarWidth = SidebarMinWidth
-	} else if a.width > 200 {
-		sidebarWidth = SidebarMaxWidth
-	}
+	sidebarWidth := sidebarWidthFor(a.width, availableWidth, a.layout.SidebarRatio)
 
 	mainWidth := availableWidth - sidebarWidth
 	contentHeight := availableHeight
 
+	// Registered panels take rows off the bottom of the sidebar
+	sidebarHeight := max(contentHeight-a.pluginRows(a.currentExperience), minWorkspaceHeight+minBookmarksHeight)
+
 	switch a.currentExperience {
 	case ExperienceLog:
 		// Log Experience: Workspace + Bookmarks sidebar, Log main
-		workspaceHeight := 3
-		bookmarksHeight := contentHeight - workspaceHeight
+		workspaceHeight := 0
+		a.workspaceCollapsedLayout = a.workspaceCollapsed()
+		if a.workspaceCollapsedLayout {
+			workspaceHeight = collapsedWorkspaceRows
+		} else if a.workspaceShown() {
+			workspaceHeight = workspaceHeightFor(sidebarHeight, a.layout.WorkspaceRatio)
+		}
+		bookmarksHeight := sidebarHeight - workspaceHeight
 		if bookmarksHeight < 3 {
 			bookmarksHeight = 3
 		}
 
+		// Split the main area between log and preview when the preview is shown
+		logWidth := mainWidth
+		if a.showPreview {
+			logWidth = mainWidth / 2
+			a.previewPanel.SetSize(mainWidth-logWidth, contentHeight)
+		}
+
 		a.workspacePanel.SetSize(sidebarWidth, workspaceHeight)
 		a.bookmarksPanel.SetSize(sidebarWidth, bookmarksHeight)
-		a.logPanel.SetSize(mainWidth, contentHeight)
+		a.logPanel.SetSize(logWidth, contentHeight)
+
+		logX2 := a.width - 1
+		if a.showPreview {
+			logX2 = sidebarWidth + logWidth - 1
+		}
 
 		// Panel bounds: 0=log, 1=workspace, 2=bookmarks
 		a.panelBounds = []PanelBound{
-			{X1: sidebarWidth, Y1: 0, X2: a.width - 1, Y2: contentHeight - 1, PanelIndex: 0},                    // Log
-			{X1: 0, Y1: 0, X2: sidebarWidth - 1, Y2: workspaceHeight - 1, PanelIndex: 1},                        // Workspace
-			{X1: 0, Y1: workspaceHeight, X2: sidebarWidth - 1, Y2: contentHeight - 1, PanelIndex: 2},            // Bookmarks
+			{X1: sidebarWidth, Y1: 0, X2: logX2, Y2: contentHeight - 1, PanelIndex: 0},               // Log
+			{X1: 0, Y1: workspaceHeight, X2: sidebarWidth - 1, Y2: sidebarHeight - 1, PanelIndex: 2}, // Bookmarks
 		}
+		if a.workspaceShown() {
+			a.panelBounds = append(a.panelBounds, PanelBound{
+				X1: 0, Y1: 0, X2: sidebarWidth - 1, Y2: workspaceHeight - 1, PanelIndex: 1, // Workspace
+			})
+		}
+		a.layoutPlugins(sidebarWidth, workspaceHeight+bookmarksHeight)
 
 	case ExperienceChange:
 		// Change Experience: Files sidebar, Diff main
-		a.filesPanel.SetSize(sidebarWidth, contentHeight)
+		a.filesPanel.SetSize(sidebarWidth, sidebarHeight)
+		a.historyPanel.SetSize(sidebarWidth, sidebarHeight)
 		a.diffPanel.SetSize(mainWidth, contentHeight)
 
 		// Panel bounds: 0=diff, 1=files
 		a.panelBounds = []PanelBound{
 			{X1: sidebarWidth, Y1: 0, X2: a.width - 1, Y2: contentHeight - 1, PanelIndex: 0}, // Diff
-			{X1: 0, Y1: 0, X2: sidebarWidth - 1, Y2: contentHeight - 1, PanelIndex: 1},       // Files
+			{X1: 0, Y1: 0, X2: sidebarWidth - 1, Y2: sidebarHeight - 1, PanelIndex: 1},       // Files
+		}
+		a.layoutPlugins(sidebarWidth, sidebarHeight)
+
+	case ExperienceOperations:
+		// Operations Experience: Operations sidebar, Timeline + log preview main
+		a.operationsPanel.SetSize(sidebarWidth, sidebarHeight)
+		a.timelinePanel.SetSize(mainWidth, panels.TimelineHeight)
+		a.opLogPanel.SetSize(mainWidth, contentHeight-panels.TimelineHeight)
+
+		// Panel bounds: 0=timeline, 1=operations
+		a.panelBounds = []PanelBound{
+			{X1: sidebarWidth, Y1: 0, X2: a.width - 1, Y2: panels.TimelineHeight - 1, PanelIndex: 0}, // Timeline
+			{X1: 0, Y1: 0, X2: sidebarWidth - 1, Y2: sidebarHeight - 1, PanelIndex: 1},               // Operations
 		}
+		a.layoutPlugins(sidebarWidth, sidebarHeight)
 	}
 
 	// Set overlay sizes to full screen
@@ -872,10 +1519,36 @@ func (a *App) renderBreadcrumbs() string {
 
 	folderTab := orangeTextStyle.Render(folderName)
 
+	if a.busy {
+		folderTab += theme.DimmedStyle.Render(" working… (esc to cancel)")
+	}
+
+	if a.toast != "" {
+		folderTab += " " + theme.AddedStyle.Render("✓ "+a.toast)
+	}
+
+	if a.atOp != "" {
+		// Red text style so browsing the past is never mistaken for the present
+		redTextStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FF6188")). // Monokai red
+			Bold(true)
+
+		folderTab += " " + redTextStyle.Render("@ "+a.atOp+" read-only")
+	}
+
 	if a.currentExperience == ExperienceLog {
 		return folderTab
 	}
 
+	if a.currentExperience == ExperienceOperations {
+		// Green text style for the operation timeline
+		greenTextStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#A9DC76")). // Monokai green
+			Bold(true)
+
+		return folderTab + " " + greenTextStyle.Render("operations")
+	}
+
 	// Blue text style for change ID (Change experience)
 	blueTextStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#78DCE8")). // Monokai blue/cyan
@@ -892,7 +1565,7 @@ func (a *App) renderMainFrame(content string) string {
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(lipgloss.Color("#939293")). // Dimmed color for border
 		Width(a.width - 2).                          // Account for border width
-		Height(a.height - 3)                         // Account for border height and help bar
+		Height(a.height - 3 - statusBarHeight)       // Account for border height, status and help bars
 
 	// Render content with border
 	bordered := borderStyle.Render(content)
@@ -927,6 +1600,22 @@ func (a *App) renderHelpBar() string {
 		Entered:         false,
 		IsWorkingCopy:   a.selectedChangeIsWorking,
 		BookmarkSetMode: a.bookmarkSetMode,
+		VisualMode:      a.logPanel.InVisualMode(),
+		RebaseMode:      a.rebaseMode,
+		SquashIntoMode:  a.squashIntoMode,
+		PickMode:        a.pickMode != PickNone,
+		AtOperation:     a.atOp != "",
+		FileHistory:     a.fileHistoryPath != "",
+		CopyPending:     a.copyPending,
+		GitSyncPending:  a.gitSyncPending,
+		EnterOpens:      a.cfg.EnterOpensChange,
+	}
+	if a.currentExperience == ExperienceChange {
+		ctx.UntrackedFile = a.untrackedFileSelected()
+	}
+
+	if plugin := a.focusedPlugin(); plugin != nil {
+		ctx.PanelHints = append([]HelpHint{}, plugin.hints...) // Non-nil: no built-in hints
 	}
 
 	// Determine entered state based on focused panel
@@ -942,40 +1631,18 @@ func (a *App) renderHelpBar() string {
 	return RenderContextualHelpBar(ctx, a.width)
 }
 
-func (a *App) overlayHelp(background string) string {
-	// Render help overlay at full screen
-	helpView := a.helpOverlay.View()
-
-	// Replace background with help view (full overlay)
-	bgLines := strings.Split(background, "\n")
-	helpLines := strings.Split(helpView, "\n")
-
-	// Replace background lines with help lines starting from the top
-	for i, helpLine := range helpLines {
-		if i >= 0 && i < len(bgLines) {
-			bgLines[i] = helpLine
-		}
-	}
+// composite draws a floating window's view over the screen, leaving the
+// screen around it in place, faded if the config says so
+func (a *App) composite(background, view string) string {
+	return floating.Composite(background, view, a.cfg.DimBackground)
+}
 
-	return strings.Join(bgLines, "\n")
+func (a *App) overlayHelp(background string) string {
+	return a.composite(background, a.helpOverlay.View())
 }
 
 func (a *App) overlayTextInput(background string) string {
-	// Render text input overlay
-	textInputView := a.textInputOverlay.View()
-
-	// Overlay on top of background
-	bgLines := strings.Split(background, "\n")
-	textInputLines := strings.Split(textInputView, "\n")
-
-	// Replace background lines with text input lines
-	for i, textInputLine := range textInputLines {
-		if i >= 0 && i < len(bgLines) {
-			bgLines[i] = textInputLine
-		}
-	}
-
-	return strings.Join(bgLines, "\n")
+	return a.composite(background, a.textInputOverlay.View())
 }
 
 // handleMouse processes mouse events for panel focus and interaction
@@ -1003,12 +1670,33 @@ func (a *App) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 	switch msg.Button {
 	case tea.MouseButtonLeft:
 		if msg.Action == tea.MouseActionPress {
+			doubleClick := a.registerClick(panelIndex, msg.Y)
+
 			// Focus the clicked panel
 			if panelIndex >= 0 && panelIndex != a.focusedPanel {
 				a.setFocus(panelIndex)
 			}
 			// Forward click to panel for item selection
-			return a.forwardMouseToPanel(panelIndex, msg)
+			model, cmd := a.forwardMouseToPanel(panelIndex, msg)
+			if doubleClick && a.pickMode != PickNone {
+				if a.pickPanelFocused() {
+					return a, a.pickSelected()
+				}
+			} else if doubleClick {
+				a.handleDoubleClick(panelIndex)
+			}
+			return model, cmd
+		}
+
+	case tea.MouseButtonRight:
+		// Select the item under the pointer and show its actions
+		if msg.Action == tea.MouseActionPress && panelIndex >= 0 && a.canOpenMenu() {
+			if panelIndex != a.focusedPanel {
+				a.setFocus(panelIndex)
+			}
+			msg.Button = tea.MouseButtonLeft
+			_, cmd := a.forwardMouseToPanel(panelIndex, msg)
+			return a, tea.Batch(cmd, a.openContextMenu())
 		}
 
 	case tea.MouseButtonWheelUp, tea.MouseButtonWheelDown:
@@ -1019,6 +1707,46 @@ func (a *App) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 	return a, nil
 }
 
+// registerClick records a left click and reports whether it completes a
+// double-click on the same panel row
+func (a *App) registerClick(panelIndex, y int) bool {
+	now := time.Now()
+	doubleClick := panelIndex >= 0 &&
+		panelIndex == a.lastClickPanel &&
+		y == a.lastClickY &&
+		now.Sub(a.lastClickAt) <= doubleClickInterval
+
+	if doubleClick {
+		// Reset so a triple-click doesn't register as a second double-click
+		a.lastClickAt = time.Time{}
+	} else {
+		a.lastClickAt = now
+	}
+	a.lastClickPanel = panelIndex
+	a.lastClickY = y
+	return doubleClick
+}
+
+// handleDoubleClick drills into the item under a double-click:
+// a log row opens the Change experience, a file row focuses its diff
+func (a *App) handleDoubleClick(panelIndex int) {
+	switch a.currentExperience {
+	case ExperienceLog:
+		if panelIndex == 0 && !a.bookmarkSetMode {
+			if change := a.logPanel.SelectedChange(); change != nil {
+				a.enterChangeExperience(change.ChangeID, change.IsWorkingCopy)
+			}
+		}
+	case ExperienceChange:
+		if panelIndex == 1 {
+			if a.filesPanel.SelectedFile() != nil {
+				a.showSelectedFile()
+				a.setFocus(0)
+			}
+		}
+	}
+}
+
 // panelAtPoint returns the panel index at the given screen coordinates
 func (a *App) panelAtPoint(x, y int) int {
 	for _, bound := range a.panelBounds {
@@ -1040,26 +1768,11 @@ func (a *App) forwardMouseToPanel(panelIndex int, msg tea.MouseMsg) (tea.Model,
 		}
 	}
 
-	var cmd tea.Cmd
-	switch a.currentExperience {
-	case ExperienceLog:
-		switch panelIndex {
-		case 0:
-			_, cmd = a.logPanel.Update(msg)
-		case 1:
-			_, cmd = a.workspacePanel.Update(msg)
-		case 2:
-			_, cmd = a.bookmarksPanel.Update(msg)
-		}
-	case ExperienceChange:
-		switch panelIndex {
-		case 0:
-			_, cmd = a.diffPanel.Update(msg)
-		case 1:
-			_, cmd = a.filesPanel.Update(msg)
-		}
+	if entry := a.panelAt(panelIndex); entry != nil {
+		_, cmd := entry.panel.Update(msg)
+		return a, cmd
 	}
-	return a, cmd
+	return a, nil
 }
 
 // enterBookmarkSetMode starts the bookmark set flow
@@ -1093,6 +1806,299 @@ func (a *App) exitBookmarkSetMode() {
 	a.bookmarksPanel.SetCursor(a.bookmarkSetCursor)
 }
 
+// selectedRangeIDs returns the change IDs of the log selection or visual range
+func (a *App) selectedRangeIDs() []string {
+	var ids []string
+	for _, change := range a.logPanel.SelectedRange() {
+		ids = append(ids, change.ChangeID)
+	}
+	return ids
+}
+
+// enterRebaseMode starts picking a destination for rebasing a revset
+func (a *App) enterRebaseMode(source string) {
+	a.rebaseMode = true
+	a.rebaseSource = source
+	a.logPanel.SetVisualMode(false)
+	a.logPanel.SetTitle("0 Rebase")
+}
+
+// exitRebaseMode cancels or finishes the rebase destination pick
+func (a *App) exitRebaseMode() {
+	a.rebaseMode = false
+	a.rebaseSource = ""
+	a.logPanel.SetTitle("0 Log")
+}
+
+// executeRebase rebases the picked revset onto the destination change
+func (a *App) executeRebase(dest app.Target) tea.Cmd {
+	source := a.rebaseSource
+	a.exitRebaseMode()
+
+	return a.runOnTarget(dest, func(ctx context.Context, changeID string) error {
+		return jj.Rebase(ctx, a.repoPath, source, changeID)
+	}, a.refreshLogPanels)
+}
+
+// enterSquashIntoMode starts picking a destination to squash a change into
+func (a *App) enterSquashIntoMode(source app.Target) {
+	a.squashIntoMode = true
+	a.squashIntoSource = source
+	a.logPanel.SetTitle("0 Squash into")
+}
+
+// exitSquashIntoMode cancels or finishes the squash destination pick
+func (a *App) exitSquashIntoMode() {
+	a.squashIntoMode = false
+	a.squashIntoSource = app.Target{}
+	a.logPanel.SetTitle("0 Log")
+}
+
+// executeSquashInto squashes the picked source change into the destination.
+// Both ends were captured at keypress time and are validated before running.
+func (a *App) executeSquashInto(dest app.Target) tea.Cmd {
+	source := a.squashIntoSource
+	a.exitSquashIntoMode()
+	return a.confirmSquash(source, &dest)
+}
+
+// confirmSquash asks before squashing source into dest when the confirm
+// setting says to, then squashes
+func (a *App) confirmSquash(source app.Target, dest *app.Target) tea.Cmd {
+	return a.askFirst(squashPolicy, source.CommitID, nil, func(ask bool, count int) tea.Cmd {
+		if !ask {
+			return a.squash(source, dest)
+		}
+		a.squashMessageSource = source
+		a.squashMessageDest = dest
+		message := fmt.Sprintf("Squash %s into its parent?", source.ChangeID)
+		if dest != nil {
+			message = fmt.Sprintf("Squash %s into %s?", source.ChangeID, dest.ChangeID)
+		}
+		if count > 0 {
+			message += fmt.Sprintf(" Its %d descendants are rebased.", count)
+		}
+		a.showConfirmDialog("Squash Change", message, "squash")
+		return nil
+	})
+}
+
+// squash squashes source into dest, or into its parent when dest is nil.
+// When both are described, jj would open an editor to combine the
+// messages; instead the combined message is opened in the multi-line
+// editor and the squash runs once it is saved.
+func (a *App) squash(source app.Target, dest *app.Target) tea.Cmd {
+	destRev := source.ChangeID + "-"
+	if dest != nil {
+		destRev = dest.ChangeID
+	}
+	destDesc, _ := jj.GetDescription(a.ctx, a.repoPath, destRev)
+	sourceDesc, _ := jj.GetDescription(a.ctx, a.repoPath, source.ChangeID)
+
+	combined := jj.CombinedDescription(destDesc, sourceDesc)
+	if combined == "" {
+		return a.runSquash(source, dest, "")
+	}
+
+	a.squashMessageSource = source
+	a.squashMessageDest = dest
+	a.textInputOverlay = floating.NewTextAreaOverlay("Squash Message", "Enter description...", combined)
+	a.textInputOverlay.SetSize(a.width, a.height-1)
+	a.showTextInput = true
+	a.textInputAction = "squash_message"
+	return nil
+}
+
+// runSquash runs a squash, setting the combined description when message
+// is not empty
+func (a *App) runSquash(source app.Target, dest *app.Target, message string) tea.Cmd {
+	if dest == nil {
+		return a.runOnTarget(source, func(ctx context.Context, changeID string) error {
+			return a.advancing(ctx, func() error {
+				if message == "" {
+					return a.repo.Squash(ctx, changeID)
+				}
+				return jj.SquashWithMessage(ctx, a.repoPath, changeID, changeID+"-", message)
+			})
+		}, a.refreshLogPanels)
+	}
+
+	return a.runOnTarget(*dest, func(ctx context.Context, changeID string) error {
+		if err := source.Validate(ctx, a.repoPath); err != nil {
+			return err
+		}
+		if message == "" {
+			return jj.SquashInto(ctx, a.repoPath, source.ChangeID, changeID)
+		}
+		return jj.SquashWithMessage(ctx, a.repoPath, source.ChangeID, changeID, message)
+	}, a.refreshLogPanels)
+}
+
+// cycleFileOrder sorts the files by the next order, remembering it for
+// later sessions
+func (a *App) cycleFileOrder() tea.Cmd {
+	order := a.filesPanel.Order().Next()
+	a.filesPanel.SetOrder(order)
+	a.showSelectedFile()
+	a.layout.FileOrder = string(order)
+	_ = a.layout.Save() // The order still applies for this session if saving fails
+	return a.showToast("files sorted by " + string(order))
+}
+
+// reverseLog flips the log's order, remembering it for later sessions
+func (a *App) reverseLog() tea.Cmd {
+	a.logPanel.ToggleReversed()
+	a.layout.LogReversed = a.logPanel.Reversed()
+	_ = a.layout.Save() // The order still applies for this session if saving fails
+	if a.layout.LogReversed {
+		return a.showToast("log oldest first")
+	}
+	return a.showToast("log newest first")
+}
+
+// filesSidebar returns the Change experience sidebar: the file's history
+// while browsing it, otherwise the changed files
+func (a *App) filesSidebar() panels.Panel {
+	if a.fileHistoryPath != "" {
+		return a.historyPanel
+	}
+	return a.filesPanel
+}
+
+// enterFileHistory lists the changes touching path in place of the files
+func (a *App) enterFileHistory(path string) {
+	a.fileHistoryPath = path
+	a.historyPanel.SetTitle("1 History: " + path)
+	a.historyPanel.SetOutput(jj.FileHistory(a.readCtx(), a.repoPath, path))
+	a.historyPanel.SelectByChangeID(a.selectedChangeID)
+	a.setFocus(1)
+}
+
+// exitFileHistory returns to the files, showing the file's diff in the
+// change being viewed again
+func (a *App) exitFileHistory() {
+	path := a.fileHistoryPath
+	a.fileHistoryPath = ""
+	a.setFocus(1)
+	a.diffPanel.LoadFileInChange(a.selectedChangeID, path)
+	if desc, err := jj.GetDescription(a.readCtx(), a.repoPath, a.selectedChangeID); err == nil {
+		a.diffPanel.SetDescription(desc)
+	} else {
+		a.diffPanel.ClearDescription()
+	}
+}
+
+// showFileHistoryRevision shows the history file's diff in change
+func (a *App) showFileHistoryRevision(change jj.ChangeInfo) {
+	a.diffPanel.LoadFileInChange(change.ChangeID, a.fileHistoryPath)
+	if desc, err := jj.GetDescription(a.readCtx(), a.repoPath, change.ChangeID); err == nil {
+		a.diffPanel.SetDescription(desc)
+	} else {
+		a.diffPanel.ClearDescription()
+	}
+}
+
+// runMutation runs a mutating jj command off the UI loop. The working
+// indicator is shown and conflicting keys are ignored until it finishes;
+// after runs on completion whether or not the command failed.
+func (a *App) runMutation(fn func(ctx context.Context) error, after func()) tea.Cmd {
+	ctx, cancel := context.WithCancel(a.ctx)
+	a.busy = true
+	a.cancelMutation = cancel
+	a.mutationRecorded = a.pendingAction != nil
+	if a.pendingAction != nil {
+		a.history.Record(*a.pendingAction)
+		a.pendingAction = nil
+	}
+	a.afterMutation = after
+	return tea.Batch(func() tea.Msg {
+		defer cancel()
+		return messages.MutationDoneMsg{Err: fn(ctx)}
+	}, a.startSpinner())
+}
+
+// quit cancels any in-flight jj commands and exits
+func (a *App) quit() tea.Cmd {
+	a.saveSession()
+	a.cancel()
+	return tea.Quit
+}
+
+// allowedWhileBusy reports whether a key may be handled while a mutation runs
+func (a *App) allowedWhileBusy(msg tea.KeyMsg) bool {
+	return key.Matches(msg, a.keys.Up, a.keys.Down, a.keys.PageUp, a.keys.PageDown,
+		a.keys.Home, a.keys.End, a.keys.Help, a.keys.Quit, a.keys.NextPanel, a.keys.PrevPanel)
+}
+
+// evalRevset schedules counting a revset's matches for live input
+// feedback, once typing pauses for revsetEvalDelay
+func (a *App) evalRevset(revset string) tea.Cmd {
+	a.evalSeq++
+	seq := a.evalSeq
+	return tea.Tick(revsetEvalDelay, func(time.Time) tea.Msg {
+		return messages.RevsetEvalDueMsg{Seq: seq, Revset: revset}
+	})
+}
+
+// runRevsetEval counts a revset's matches in the background
+func (a *App) runRevsetEval(revset string) tea.Cmd {
+	// Only the latest text matters; stop evaluating the previous one
+	if a.cancelEval != nil {
+		a.cancelEval()
+	}
+	ctx, cancel := context.WithCancel(a.ctx)
+	a.cancelEval = cancel
+
+	repoPath := a.repoPath
+	return func() tea.Msg {
+		defer cancel()
+		count, err := jj.CheckRevset(ctx, repoPath, revset)
+		return messages.RevsetEvalMsg{Revset: revset, Count: count, Err: err}
+	}
+}
+
+// suggestDescription runs the describe hook in the background
+func (a *App) suggestDescription(changeID string) tea.Cmd {
+	ctx, repoPath, hook := a.ctx, a.repoPath, a.cfg.DescribeHook
+	return func() tea.Msg {
+		text, err := app.SuggestDescription(ctx, repoPath, hook, changeID)
+		return messages.DescriptionSuggestionMsg{ChangeID: changeID, Text: text, Err: err}
+	}
+}
+
+// showRevsetError shows a revset error in the text input, marking its position
+func (a *App) showRevsetError(err error) {
+	var revErr *jj.RevsetError
+	if errors.As(err, &revErr) {
+		a.textInputOverlay.SetError(revErr.Message, revErr.Offset)
+		return
+	}
+	a.textInputOverlay.SetError(err.Error(), -1)
+}
+
+// runOnTarget runs a mutation against a target captured at keypress time,
+// refusing if the target has since vanished or been rewritten
+func (a *App) runOnTarget(target app.Target, fn func(ctx context.Context, changeID string) error, after func()) tea.Cmd {
+	return a.runMutation(func(ctx context.Context) error {
+		if err := target.Validate(ctx, a.repoPath); err != nil {
+			return err
+		}
+		return fn(ctx, target.ChangeID)
+	}, after)
+}
+
+// truncatedDiff returns the diff on screen if it was cut off for being too
+// long, or nil
+func (a *App) truncatedDiff() *panels.DiffViewer {
+	switch {
+	case a.currentExperience == ExperienceChange && a.diffPanel.Truncated():
+		return a.diffPanel
+	case a.currentExperience == ExperienceLog && a.showPreview && a.previewPanel.Truncated():
+		return a.previewPanel
+	}
+	return nil
+}
+
 // selectBookmarkInLog tries to select the revision where the bookmark currently points
 func (a *App) selectBookmarkInLog(bookmarkName string) {
 	// Get revisions from jj-lib which include bookmarks
@@ -1119,28 +2125,79 @@ func (a *App) selectBookmarkInLog(bookmarkName string) {
 func (a *App) executeBookmarkSet(commitID string) {
 	err := a.repo.SetBookmark(a.bookmarkSetName, commitID, false, false)
 	if err != nil {
-		errMsg := err.Error()
-		// Check for immutable error
-		if strings.Contains(errMsg, "immutable") {
+		if errors.Is(err, jj.ErrImmutable) {
 			a.showConfirmDialog("Set on Immutable", "Set bookmark on immutable revision?", "immutable")
 			return
 		}
-		// Check for backwards error
-		if strings.Contains(errMsg, "backwards") {
+		if errors.Is(err, jj.ErrBackwards) {
 			a.showConfirmDialog("Move Backwards", "Move bookmark backwards in history?", "backwards")
 			return
 		}
 		// Other error - show error to user
-		a.showInfoDialog("Error", errMsg)
+		a.showErrorDialog(err)
 		a.exitBookmarkSetMode()
 		return
 	}
 
 	// Success - exit set mode and refresh
 	a.exitBookmarkSetMode()
-	a.logPanel.Refresh()
-	a.workspacePanel.Refresh()
-	a.bookmarksPanel.Refresh()
+	a.refreshLogPanels()
+}
+
+// openBookmarkCleanup lists the stale bookmarks for deletion
+func (a *App) openBookmarkCleanup() {
+	stale, err := jj.StaleBookmarks(a.ctx, a.repoPath, a.trunkRevset())
+	if err != nil {
+		a.showErrorDialog(err)
+		return
+	}
+	if len(stale) == 0 {
+		a.showInfoDialog("Clean Up Bookmarks", "No bookmarks are merged into trunk or missing on their remote.")
+		return
+	}
+	a.cleanupOverlay = floating.NewBookmarkCleanupOverlay(stale)
+	a.cleanupOverlay.SetSize(a.width, a.height-1)
+	a.showCleanup = true
+}
+
+// handleCleanupKey handles a key in the bookmark cleanup overlay. Enter on
+// the list shows the dry run; Enter on the dry run deletes.
+func (a *App) handleCleanupKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "escape", "ctrl+g", "ctrl+c":
+		if a.cleanupOverlay.Previewing() {
+			a.cleanupOverlay.SetPreviewing(false)
+			return nil
+		}
+		a.showCleanup = false
+		a.cleanupOverlay = nil
+		return nil
+	case "enter":
+		names := a.cleanupOverlay.Marked()
+		if len(names) == 0 {
+			return nil
+		}
+		if !a.cleanupOverlay.Previewing() {
+			a.cleanupOverlay.SetPreviewing(true)
+			return nil
+		}
+		a.showCleanup = false
+		a.cleanupOverlay = nil
+		a.cooldown.Arm(msg.String(), time.Now())
+		return a.runMutation(func(ctx context.Context) error {
+			return jj.DeleteBookmarks(ctx, a.repoPath, names)
+		}, func() {
+			a.bookmarksPanel.SetCursor(0)
+			a.refreshLogPanels()
+		})
+	default:
+		_, cmd := a.cleanupOverlay.Update(msg)
+		return cmd
+	}
+}
+
+func (a *App) overlayCleanup(background string) string {
+	return a.composite(background, a.cleanupOverlay.View())
 }
 
 // showConfirmDialog displays a confirmation dialog
@@ -1152,14 +2209,42 @@ func (a *App) showConfirmDialog(title, message, action string) {
 }
 
 // handleConfirmAction processes confirmed action
-func (a *App) handleConfirmAction() {
+func (a *App) handleConfirmAction() tea.Cmd {
+	if a.confirmAction == "abandon_range" {
+		return a.abandonRange()
+	}
+	if a.confirmAction == "squash" {
+		return a.squash(a.squashMessageSource, a.squashMessageDest)
+	}
+	if a.confirmAction == "squash_range" {
+		return a.describeSquashRange()
+	}
+	if a.confirmAction == "abandon_descendants" {
+		return a.abandon(a.abandonTarget)
+	}
+	if a.confirmAction == "resolve_divergent" {
+		return a.resolveDivergent()
+	}
+	if a.confirmAction == "describe_empty" {
+		return a.saveDescription("")
+	}
+	if a.confirmAction == "edit_risky" {
+		return a.runEdit(a.editTarget, a.editIgnoreImmutable)
+	}
+	if a.confirmAction == "push_stack" {
+		return a.pushStack()
+	}
+	if a.confirmAction == "open_pull_requests" {
+		return a.openPullRequests()
+	}
+
 	if a.bookmarkSetName == "" {
-		return
+		return nil
 	}
 
 	change := a.logPanel.SelectedChange()
 	if change == nil {
-		return
+		return nil
 	}
 
 	allowBackwards := a.confirmAction == "backwards"
@@ -1169,30 +2254,18 @@ func (a *App) handleConfirmAction() {
 	if err != nil {
 		// Failed even with flag - exit set mode
 		a.exitBookmarkSetMode()
-		return
+		return nil
 	}
 
 	// Success
 	a.exitBookmarkSetMode()
-	a.logPanel.Refresh()
-	a.workspacePanel.Refresh()
-	a.bookmarksPanel.Refresh()
+	a.refreshLogPanels()
+	return nil
 }
 
 // overlayConfirm renders the confirm dialog overlay
 func (a *App) overlayConfirm(background string) string {
-	confirmView := a.confirmOverlay.View()
-
-	bgLines := strings.Split(background, "\n")
-	confirmLines := strings.Split(confirmView, "\n")
-
-	for i, confirmLine := range confirmLines {
-		if i >= 0 && i < len(bgLines) {
-			bgLines[i] = confirmLine
-		}
-	}
-
-	return strings.Join(bgLines, "\n")
+	return a.composite(background, a.confirmOverlay.View())
 }
 
 // showInfoDialog displays an informational/error message
@@ -1204,47 +2277,47 @@ func (a *App) showInfoDialog(title, message string) {
 
 // overlayInfo renders the info dialog overlay
 func (a *App) overlayInfo(background string) string {
-	infoView := a.infoOverlay.View()
-
-	bgLines := strings.Split(background, "\n")
-	infoLines := strings.Split(infoView, "\n")
-
-	for i, infoLine := range infoLines {
-		if i >= 0 && i < len(bgLines) {
-			bgLines[i] = infoLine
-		}
-	}
-
-	return strings.Join(bgLines, "\n")
+	return a.composite(background, a.infoOverlay.View())
 }
 
 // switchWorkspace switches to a different workspace by closing and reopening the repo
 func (a *App) switchWorkspace(workspacePath string) error {
-	// Close old repo
-	a.repo.Close()
-
-	// Open new repo at workspace path
+	// Open the new repo first so a failure leaves the current one in use
 	newRepo, err := jj.Open(workspacePath)
 	if err != nil {
-		// Try to reopen old repo on failure
-		a.repo, _ = jj.Open(a.repoPath)
 		return err
 	}
 
-	// Replace repo and path
+	// Replace repo and path; the panels below are rebuilt on the new repo
+	a.repo.Close()
 	a.repo = newRepo
 	a.repoPath = workspacePath
 
 	// Recreate all panels with new repo
 	a.workspacePanel = panels.NewWorkspacePanel(a.repo)
 	a.bookmarksPanel = panels.NewBookmarksPanel(a.repo, a.repoPath)
-	a.logPanel = panels.NewLogPanel(a.repoPath)
+	a.bookmarksPanel.SetTrunkBookmark(a.trunkBookmark)
+	a.logPanel = panels.NewLogPanel(a.repoPath, a.cfg.LogRevset, a.layout.LogReversed)
+	if a.signingBackend != "" {
+		a.logPanel.ShowSignatures(a.repo)
+	}
+
+	previewOptions := a.previewPanel.DiffOptions()
+	a.previewPanel = panels.NewDiffViewer(a.repo)
+	a.previewPanel.SetRepoPath(a.repoPath)
+	a.previewPanel.SetTitle("Preview")
+	a.previewPanel.SetDiffOptions(previewOptions)
+	a.previewCommitID = ""
 
 	a.filesPanel = panels.NewFilesPanel(a.repo)
 	a.filesPanel.SetRepoPath(a.repoPath)
+	a.filesPanel.SetOrder(panels.FileOrder(a.layout.FileOrder))
 
+	// Keep the diff options toggled in this session
+	diffOptions := a.diffPanel.DiffOptions()
 	a.diffPanel = panels.NewDiffViewer(a.repo)
 	a.diffPanel.SetRepoPath(a.repoPath)
+	a.diffPanel.SetDiffOptions(diffOptions)
 
 	// Update layout to resize panels
 	a.updateLayout()
@@ -0,0 +1,370 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/gerunddev/jjazy/config"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/floating"
+	"github.com/gerunddev/jjazy/ui/golden"
+	"github.com/gerunddev/jjazy/ui/messages"
+	"github.com/gerunddev/jjazy/ui/panels"
+)
+
+// TestCycleFileOrder verifies the files' sort order cycles and is remembered
+func TestCycleFileOrder(t *testing.T) {
+	t.Setenv("JJAZY_CONFIG", filepath.Join(t.TempDir(), "jjazy", "config.json"))
+
+	state := &config.State{FileOrder: "status"}
+	a := NewApp(nil, "/nonexistent/path", config.Default(), state)
+	if a.filesPanel.Order() != panels.FileOrderStatus {
+		t.Fatalf("Expected the saved order to apply, got %q", a.filesPanel.Order())
+	}
+
+	for _, want := range []panels.FileOrder{panels.FileOrderSize, panels.FileOrderPath, panels.FileOrderStatus} {
+		a.cycleFileOrder()
+		if a.filesPanel.Order() != want || state.FileOrder != string(want) {
+			t.Errorf("Expected order %q, got panel %q and saved %q", want, a.filesPanel.Order(), state.FileOrder)
+		}
+	}
+
+	loaded, err := config.LoadState()
+	if err != nil || loaded.FileOrder != "status" {
+		t.Errorf("Expected the order to be saved, got %+v (%v)", loaded, err)
+	}
+}
+
+// TestBlockImmutable verifies rewriting keys are refused on immutable changes
+func TestBlockImmutable(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.logPanel.SetOutput(&jj.LogOutput{
+		LineToChange: []string{"trunk111", "work2222"},
+		Changes: []jj.ChangeInfo{
+			{ChangeID: "trunk111", Immutable: true, StartLine: 0, EndLine: 1},
+			{ChangeID: "work2222", StartLine: 1, EndLine: 2},
+		},
+	}, nil)
+
+	describe := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}}
+	if !a.blockImmutable(describe) || !a.showInfo {
+		t.Fatalf("Expected describe on an immutable change to be refused with a message")
+	}
+
+	a.showInfo = false
+	newChange := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}}
+	if a.blockImmutable(newChange) {
+		t.Errorf("Expected a new change on top of an immutable change to be allowed")
+	}
+
+	a.logPanel.SelectByChangeID("work2222")
+	if a.blockImmutable(describe) {
+		t.Errorf("Expected describe on a mutable change to be allowed")
+	}
+}
+
+// TestHideWorkspacePanel verifies a hidden workspace panel is left out of
+// the sidebar and skipped by focus changes
+func TestHideWorkspacePanel(t *testing.T) {
+	cfg := config.Default()
+	cfg.HideWorkspacePanel = true
+	a := NewApp(nil, "/nonexistent/path", cfg, &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	if strings.Contains(a.View(), "1 Workspace") {
+		t.Errorf("Expected the workspace panel to be hidden")
+	}
+	a.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if a.focusedPanel != 2 {
+		t.Errorf("Expected tab to skip to bookmarks, got panel %d", a.focusedPanel)
+	}
+	a.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	if a.focusedPanel != 0 {
+		t.Errorf("Expected shift+tab to skip back to the log, got panel %d", a.focusedPanel)
+	}
+	a.setFocus(1)
+	if a.focusedPanel != 2 {
+		t.Errorf("Expected focusing the workspace to land on bookmarks, got panel %d", a.focusedPanel)
+	}
+}
+
+// TestCollapsedWorkspacePanel verifies a single workspace shrinks the
+// workspace panel to a header line and gives bookmarks the rows
+func TestCollapsedWorkspacePanel(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	if !a.workspaceCollapsedLayout {
+		t.Fatalf("Expected the workspace panel to collapse without a second workspace")
+	}
+	if got := a.workspacePanel.Height(); got != collapsedWorkspaceRows {
+		t.Errorf("Expected the workspace panel to be %d row, got %d", collapsedWorkspaceRows, got)
+	}
+	if !strings.Contains(a.View(), "── 1 Workspace") {
+		t.Errorf("Expected the collapsed workspace header in the sidebar")
+	}
+
+	a.setFocus(1)
+	a.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if a.workspacePanel.IsEntered() {
+		t.Errorf("Expected Enter not to browse a single workspace")
+	}
+}
+
+// TestDescribeTemplates verifies ctrl+t cycles the configured prefixes
+func TestDescribeTemplates(t *testing.T) {
+	cfg := config.Default()
+	cfg.DescribeTemplates = []string{"feat: ", "fix: "}
+	a := NewApp(nil, "/nonexistent/path", cfg, &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	a.openDescribe(jj.ChangeInfo{ChangeID: "work2222"})
+
+	ctrlT := tea.KeyMsg{Type: tea.KeyCtrlT}
+	for _, want := range []string{"feat: ", "fix: ", ""} {
+		a.Update(ctrlT)
+		if got := a.textInputOverlay.Value(); got != want {
+			t.Errorf("Expected %q after ctrl+t, got %q", want, got)
+		}
+	}
+}
+
+// TestDescribeLintAndEmptyConfirm verifies typing warns about trailing
+// whitespace and saving an empty description asks first when configured
+func TestDescribeLintAndEmptyConfirm(t *testing.T) {
+	cfg := config.Default()
+	cfg.ConfirmEmptyDescription = true
+	a := NewApp(nil, "/nonexistent/path", cfg, &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	a.openDescribe(jj.ChangeInfo{ChangeID: "work2222"})
+
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("fix ")})
+	if !a.textInputOverlay.HasWarning() {
+		t.Errorf("Expected a trailing whitespace warning")
+	}
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("it")})
+	if a.textInputOverlay.HasWarning() {
+		t.Errorf("Expected the warning to clear")
+	}
+
+	a.textInputOverlay.SetValue("")
+	a.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if !a.showConfirm || a.confirmAction != "describe_empty" {
+		t.Errorf("Expected saving an empty description to ask first")
+	}
+}
+
+// TestLoadFullDiff verifies long diffs are cut off until L loads them fully
+func TestLoadFullDiff(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	a.currentExperience = ExperienceChange
+	a.updateLayout()
+	a.diffPanel.SetContent(strings.Repeat("+line\n", 50000))
+
+	if !a.diffPanel.Truncated() {
+		t.Fatal("Expected a 50000 line diff to be cut off")
+	}
+	if !strings.Contains(a.diffPanel.View(), "+line") {
+		t.Error("Expected the first lines to be shown")
+	}
+
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'L'}})
+	if a.diffPanel.Truncated() {
+		t.Error("Expected L to load the diff fully")
+	}
+	if a.showActionLog {
+		t.Error("Expected L not to open the action log while a diff is cut off")
+	}
+}
+
+// TestDiffWrapAndHorizontalScroll verifies → scrolls long diff lines, ←
+// scrolls back before leaving the diff, and w wraps them instead
+func TestDiffWrapAndHorizontalScroll(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	a.currentExperience = ExperienceChange
+	a.updateLayout()
+	a.setFocus(0)
+	a.diffPanel.SetContent("+" + strings.Repeat("a", 300) + "END")
+
+	if strings.Contains(a.diffPanel.View(), "END") {
+		t.Fatal("Expected the long line to be cut off")
+	}
+	for range 50 {
+		a.Update(tea.KeyMsg{Type: tea.KeyRight})
+	}
+	if !strings.Contains(a.diffPanel.View(), "END") {
+		t.Error("Expected → to scroll to the end of the line")
+	}
+	for range 50 {
+		a.Update(tea.KeyMsg{Type: tea.KeyLeft})
+		if a.focusedPanel != 0 {
+			break
+		}
+	}
+	if a.focusedPanel != 1 {
+		t.Error("Expected ← to move to the files once scrolled back")
+	}
+
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+	if !a.diffPanel.Wrapped() || !strings.Contains(a.diffPanel.View(), "END") {
+		t.Error("Expected w to wrap the long line")
+	}
+}
+
+// TestDiffOptionKeys verifies b and [ ] change how the diff is computed,
+// starting from the configured options
+func TestDiffOptionKeys(t *testing.T) {
+	cfg := config.Default()
+	cfg.DiffContext = 2
+	a := NewApp(nil, "/nonexistent/path", cfg, &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	a.currentExperience = ExperienceChange
+	a.updateLayout()
+
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'b'}})
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{']'}})
+	if got, want := a.diffPanel.DiffOptions(), (jj.DiffOptions{IgnoreWhitespace: true, Context: 3}); got != want {
+		t.Errorf("Expected options %+v, got %+v", want, got)
+	}
+	if !strings.Contains(a.diffPanel.View(), "[-w -U3]") {
+		t.Error("Expected the diff title to show the options")
+	}
+
+	for range 5 {
+		a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'['}})
+	}
+	if got := a.diffPanel.DiffOptions().Context; got != 1 {
+		t.Errorf("Expected context to stop at 1 line, got %d", got)
+	}
+}
+
+// TestLogMinimap verifies the minimap marks @ and that clicking it jumps
+// the log to that point
+func TestLogMinimap(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	output := &jj.LogOutput{}
+	var raw []string
+	for i := range 200 {
+		id := fmt.Sprintf("change%03d", i)
+		raw = append(raw, "○ "+id)
+		output.LineToChange = append(output.LineToChange, id)
+		output.Changes = append(output.Changes, jj.ChangeInfo{ChangeID: id, StartLine: i, EndLine: i + 1, IsWorkingCopy: i == 0})
+	}
+	output.RawANSI = strings.Join(raw, "\n")
+	a.logPanel.SetOutput(output, nil)
+
+	lines := strings.Split(a.logPanel.View(), "\n")
+	if !strings.HasSuffix(ansi.Strip(lines[1]), "@│") {
+		t.Errorf("Expected @ at the top of the minimap, got %q", ansi.Strip(lines[1]))
+	}
+
+	height := a.logPanel.Height()
+	a.logPanel.Update(tea.MouseMsg{X: a.logPanel.Width() - 2, Y: height - 2, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	if a.logPanel.SelectedChange().ChangeID < "change150" {
+		t.Errorf("Expected clicking the bottom of the minimap to select a late change, got %s", a.logPanel.SelectedChange().ChangeID)
+	}
+	if a.logPanel.ScrollOffset() == 0 {
+		t.Error("Expected clicking the minimap to scroll the log")
+	}
+}
+
+// TestReverseLog verifies R flips the log order and remembers it
+func TestReverseLog(t *testing.T) {
+	t.Setenv("JJAZY_CONFIG", filepath.Join(t.TempDir(), "jjazy", "config.json"))
+
+	state := &config.State{}
+	a := NewApp(nil, "/nonexistent/path", config.Default(), state)
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'R'}})
+	if !a.logPanel.Reversed() || !state.LogReversed || a.toast != "log oldest first" {
+		t.Fatalf("Expected R to list the oldest changes first, got reversed=%v saved=%v toast=%q",
+			a.logPanel.Reversed(), state.LogReversed, a.toast)
+	}
+	loaded, err := config.LoadState()
+	if err != nil || !loaded.LogReversed {
+		t.Errorf("Expected the order to be saved, got %+v (%v)", loaded, err)
+	}
+
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'R'}})
+	if a.logPanel.Reversed() || state.LogReversed {
+		t.Error("Expected a second R to list the newest changes first again")
+	}
+}
+
+// TestOverlayKeepsBackground verifies a dialog is drawn over the screen
+// without blanking the rest of its rows, faded when dim_background is set
+func TestOverlayKeepsBackground(t *testing.T) {
+	golden.Freeze(t) // Colors on, so fading shows
+	for _, dim := range []bool{false, true} {
+		cfg := config.Default()
+		cfg.DimBackground = dim
+		a := NewApp(nil, "/nonexistent/path", cfg, &config.State{})
+		a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+		background := a.View()
+
+		a.showConfirmDialog("Abandon", "Abandon this change?", "abandon")
+		view := a.View()
+		var row string
+		for _, line := range strings.Split(view, "\n") {
+			if strings.Contains(line, "[ Yes ]") {
+				row = ansi.Strip(line)
+			}
+		}
+		if row == "" {
+			t.Fatalf("dim=%v: expected the dialog's buttons on screen", dim)
+		}
+		if strings.TrimSpace(row[:strings.Index(row, "│")]) != "" || !strings.HasPrefix(row, "│") {
+			t.Errorf("dim=%v: expected the main frame left of the dialog, got %q", dim, row)
+		}
+		if w := ansi.StringWidth(row); w != 120 {
+			t.Errorf("dim=%v: expected the row to stay 120 cells wide, got %d", dim, w)
+		}
+		if faded := floating.Dim(background); dim != strings.Contains(view, strings.Split(faded, "\n")[1]) {
+			t.Errorf("dim=%v: expected the background faded only when dimming", dim)
+		}
+	}
+}
+
+// TestPreviewLoadsLatestSelection verifies the preview pane loads in the
+// background and drops diffs for a selection the user moved past
+func TestPreviewLoadsLatestSelection(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	a.showPreview = true
+	a.logPanel.SetOutput(&jj.LogOutput{
+		LineToChange: []string{"aaaa1111", "bbbb2222"},
+		Changes: []jj.ChangeInfo{
+			{ChangeID: "aaaa1111", CommitID: "c1", StartLine: 0, EndLine: 1},
+			{ChangeID: "bbbb2222", CommitID: "c2", StartLine: 1, EndLine: 2},
+		},
+	}, nil)
+
+	if _, cmd := a.Update(tea.KeyMsg{Type: tea.KeyDown}); cmd == nil {
+		t.Fatal("Expected the new selection to schedule a preview load")
+	}
+	stale := a.previewSeq
+	a.Update(tea.KeyMsg{Type: tea.KeyUp})
+	if a.previewSeq == stale {
+		t.Fatal("Expected moving again to reschedule the preview")
+	}
+
+	if _, cmd := a.Update(messages.PreviewDueMsg{Seq: stale, ChangeID: "bbbb2222"}); cmd != nil {
+		t.Error("Expected a superseded load not to run")
+	}
+	a.Update(messages.PreviewLoadedMsg{Seq: stale, ChangeID: "bbbb2222", Diff: "stale diff"})
+	if strings.Contains(a.previewPanel.Content(), "stale diff") {
+		t.Error("Expected a superseded diff to be dropped")
+	}
+	a.Update(messages.PreviewLoadedMsg{Seq: a.previewSeq, ChangeID: "aaaa1111", Diff: "latest diff"})
+	if !strings.Contains(a.previewPanel.Content(), "latest diff") {
+		t.Errorf("Expected the latest diff in the preview, got %q", a.previewPanel.Content())
+	}
+}
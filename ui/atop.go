@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"context"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/jj"
+)
+
+// browseAtOperation shows the log and diffs as they were at op, read-only.
+// Picking the current operation returns to the present.
+func (a *App) browseAtOperation(op *jj.Operation) {
+	a.exitOperationsExperience()
+	if op.IsCurrent {
+		a.returnToPresent()
+		return
+	}
+	a.setAtOperation(op.ID)
+	a.logPanel.SetTitle("0 Log @ " + op.ID + " (read-only)")
+}
+
+// returnToPresent leaves at-operation browsing
+func (a *App) returnToPresent() {
+	if a.atOp == "" {
+		return
+	}
+	if a.currentExperience == ExperienceChange {
+		a.exitChangeExperience()
+	}
+	a.setAtOperation("")
+	a.logPanel.SetTitle("0 Log")
+}
+
+// setAtOperation points every log and diff reader at opID ("" for the present)
+func (a *App) setAtOperation(opID string) {
+	a.atOp = opID
+	a.logPanel.SetAtOperation(opID)
+	a.previewPanel.SetAtOperation(opID)
+	a.diffPanel.SetAtOperation(opID)
+	a.filesPanel.SetAtOperation(opID)
+	a.previewCommitID = ""
+}
+
+// readCtx returns the context for jj reads, at the browsed operation if any
+func (a *App) readCtx() context.Context {
+	return jj.AtOperation(a.ctx, a.atOp)
+}
+
+// handleAtOpKey handles a key while browsing a past operation. Navigation
+// and viewing fall through to the normal handlers; anything that could
+// change the repo is swallowed, since it would fork the operation log.
+func (a *App) handleAtOpKey(msg tea.KeyMsg) (cmd tea.Cmd, handled bool) {
+	if a.showHelp || a.showInfo || a.showJump || a.showLogFilter || a.showChangelog || a.showActionLog || a.showMetrics || a.showDebugLog || a.showNotifications || a.showOpDiff {
+		return nil, false
+	}
+
+	switch {
+	case key.Matches(msg, a.keys.Present):
+		a.returnToPresent()
+		return nil, true
+
+	case a.currentExperience == ExperienceOperations, a.fileHistoryPath != "":
+		// The timeline and file history only read; Enter there browses
+		return nil, false
+
+	case a.swappedEnter() && key.Matches(msg, a.keys.Enter):
+		// Enter opens the change instead of editing it
+		return nil, false
+
+	case a.swappedEnter() && msg.Type == tea.KeyRight:
+		// → edits instead of opening the change
+		return nil, true
+
+	case a.allowedWhileBusy(msg),
+		key.Matches(msg, a.keys.Escape),
+		key.Matches(msg, a.keys.TogglePreview, a.keys.ReverseLog, a.keys.ToggleMessage, a.keys.WrapDiff, a.keys.IgnoreSpace, a.keys.LessContext, a.keys.MoreContext, a.keys.Operations, a.keys.FileHistory, a.keys.SortFiles, a.keys.Copy, a.keys.Changelog, a.keys.Jump, a.keys.GoToChange, a.keys.LogFilter, a.keys.Details, a.keys.AdvanceBookmarks, a.keys.ActionLog, a.keys.Notifications, a.keys.Metrics, a.keys.DebugLog),
+		key.Matches(msg, a.keys.Panel0, a.keys.Panel1, a.keys.Panel2),
+		key.Matches(msg, a.keys.GrowSidebar, a.keys.ShrinkSidebar, a.keys.GrowWorkspace, a.keys.ShrinkWorkspace),
+		msg.Type == tea.KeyLeft, msg.Type == tea.KeyRight:
+		return nil, false
+	}
+
+	return nil, true
+}
@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/config"
+	"github.com/gerunddev/jjazy/jj"
+)
+
+// TestBrowseAtOperationIsReadOnly verifies mutating keys are swallowed while
+// browsing a past operation and @ returns to the present
+func TestBrowseAtOperationIsReadOnly(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	a.browseAtOperation(&jj.Operation{ID: "abc123def456"})
+	if a.atOp != "abc123def456" || a.currentExperience != ExperienceLog {
+		t.Fatalf("Expected to browse the log at the operation, got atOp %q", a.atOp)
+	}
+
+	for _, r := range []rune{'n', 'a', 's', 'V'} {
+		a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	a.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if a.busy || a.logPanel.InVisualMode() {
+		t.Error("Expected mutating keys to be ignored at a past operation")
+	}
+
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'@'}})
+	if a.atOp != "" {
+		t.Errorf("Expected @ to return to the present, still at %q", a.atOp)
+	}
+}
+
+// TestJumpAtOperation verifies the jump list takes typed keys while
+// browsing a past operation instead of them being read as commands
+func TestJumpAtOperation(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	a.browseAtOperation(&jj.Operation{ID: "abc123def456"})
+
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'\''}})
+	if !a.showJump {
+		t.Fatal("Expected ' to open the jump list at a past operation")
+	}
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'@'}})
+	if a.atOp == "" {
+		t.Error("Expected @ to filter the jump list, not return to the present")
+	}
+	a.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if a.showJump {
+		t.Error("Expected esc to close the jump list")
+	}
+}
+
+// TestBrowseCurrentOperation verifies picking the current operation stays in the present
+func TestBrowseCurrentOperation(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.browseAtOperation(&jj.Operation{ID: "abc123def456", IsCurrent: true})
+	if a.atOp != "" {
+		t.Errorf("Expected the current operation to be the present, got atOp %q", a.atOp)
+	}
+}
@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/app"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/floating"
+)
+
+// openAuthorEdit shows the author form for a change
+func (a *App) openAuthorEdit(change jj.ChangeInfo) {
+	author, err := jj.GetAuthor(a.ctx, a.repoPath, change.ChangeID)
+	if err != nil {
+		a.showErrorDialog(err)
+		return
+	}
+	a.authorOverlay = floating.NewAuthorOverlay(*author)
+	a.authorOverlay.SetSize(a.width, a.height-1)
+	a.authorTarget = app.TargetOf(change)
+	a.showAuthor = true
+}
+
+// handleAuthorKey handles a key in the author form. ctrl+s saves the
+// entered author; ctrl+r resets it to the configured user and now.
+func (a *App) handleAuthorKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "ctrl+x", "esc", "escape", "ctrl+c", "ctrl+g":
+		a.closeAuthor()
+		return nil
+	case "ctrl+s":
+		if problem := a.authorOverlay.Validate(); problem != "" {
+			a.authorOverlay.SetError(problem)
+			return nil
+		}
+		author := a.authorOverlay.Author()
+		a.closeAuthor()
+		return a.runOnTarget(a.authorTarget, func(ctx context.Context, changeID string) error {
+			return jj.SetAuthor(ctx, a.repoPath, changeID, author)
+		}, a.refreshLogPanels)
+	case "ctrl+r":
+		a.closeAuthor()
+		return a.runOnTarget(a.authorTarget, func(ctx context.Context, changeID string) error {
+			return jj.ResetAuthor(ctx, a.repoPath, changeID)
+		}, a.refreshLogPanels)
+	default:
+		_, cmd := a.authorOverlay.Update(msg)
+		return cmd
+	}
+}
+
+// closeAuthor hides the author form
+func (a *App) closeAuthor() {
+	a.showAuthor = false
+	a.authorOverlay = nil
+}
+
+func (a *App) overlayAuthor(background string) string {
+	return a.composite(background, a.authorOverlay.View())
+}
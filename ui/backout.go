@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"context"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/app"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/floating"
+)
+
+// openBackout asks for the description of the change that backs out
+// change, prefilled from its subject
+func (a *App) openBackout(change jj.ChangeInfo) {
+	subject := change.Description
+	if subject == "" {
+		subject = change.ChangeID
+	}
+	a.textInputOverlay = floating.NewTextInputOverlay(
+		"Back Out Change",
+		"Enter description...",
+		"Back out "+subject,
+	)
+	a.textInputOverlay.SetSize(a.width, a.height-1)
+	a.showTextInput = true
+	a.textInputAction = "backout"
+	a.textInputTarget = app.TargetOf(change)
+}
+
+// backout adds the change reversing the one the input was opened on, on
+// top of @. An empty description keeps jj's own.
+func (a *App) backout(message string) tea.Cmd {
+	message = strings.TrimSpace(message)
+	return a.runOnTarget(a.textInputTarget, func(ctx context.Context, changeID string) error {
+		return jj.Backout(ctx, a.repoPath, changeID, message)
+	}, a.refreshLogPanels)
+}
@@ -0,0 +1,29 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/config"
+	"github.com/gerunddev/jjazy/jj"
+)
+
+// TestBackoutAsksForDescription verifies B offers a description naming the
+// change before backing it out
+func TestBackoutAsksForDescription(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	a.logPanel.SetOutput(&jj.LogOutput{
+		LineToChange: []string{"work2222"},
+		Changes:      []jj.ChangeInfo{{ChangeID: "work2222", CommitID: "abcd1234", Description: "Add login", StartLine: 0, EndLine: 1}},
+	}, nil)
+
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'B'}})
+	if !a.showTextInput || a.textInputAction != "backout" {
+		t.Fatalf("Expected B to ask for a description, got input %v action %q", a.showTextInput, a.textInputAction)
+	}
+	if got := a.textInputOverlay.Value(); got != "Back out Add login" {
+		t.Errorf("Expected the description prefilled from the subject, got %q", got)
+	}
+
+}
@@ -70,6 +70,23 @@ func RenderTitledBorder(content, title string, width, height int, focused bool)
 	return strings.Join(lines, "\n")
 }
 
+// RenderTitleLine renders a panel collapsed to its title: ── Title ───────
+func RenderTitleLine(title string, width int, focused bool) string {
+	borderStyle := lipgloss.NewStyle().Foreground(theme.ColorDimWhite)
+	titleStyle := lipgloss.NewStyle().Foreground(theme.ColorWhite)
+	if focused {
+		borderStyle = lipgloss.NewStyle().Foreground(theme.ColorYellow)
+		titleStyle = borderStyle
+	}
+
+	// Format: ── Title ─...─, the same width as a box's top border
+	title = truncateString(title, max(width-6, 0))
+	remainingWidth := max(width-4-lipgloss.Width(title), 0)
+	return borderStyle.Render(Horizontal+Horizontal+" ") +
+		titleStyle.Render(title) +
+		borderStyle.Render(" "+strings.Repeat(Horizontal, remainingWidth))
+}
+
 // buildTopBorder creates: ╭─ Title ─────────╮
 func buildTopBorder(title string, width int, borderStyle, titleStyle lipgloss.Style) string {
 	if width < 4 {
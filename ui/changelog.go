@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/config"
+	"github.com/gerunddev/jjazy/release"
+	"github.com/gerunddev/jjazy/ui/floating"
+	"github.com/gerunddev/jjazy/ui/messages"
+)
+
+// updateCheckTimeout bounds the startup release check
+const updateCheckTimeout = 10 * time.Second
+
+// SetVersion records the running jjazy version, used by the update check.
+// Call it before the program starts.
+func (a *App) SetVersion(version string) {
+	a.version = version
+}
+
+// checkForUpdate looks for a newer release in the background when the
+// user opted in with check_updates. Failures are silent: the notice is
+// a courtesy, not worth an error dialog.
+func (a *App) checkForUpdate() tea.Cmd {
+	if !a.cfg.CheckUpdates || config.TestMode() {
+		return nil
+	}
+	ctx, version := a.ctx, a.version
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, updateCheckTimeout)
+		defer cancel()
+		update, err := release.Check(ctx, release.DefaultFeed, version)
+		if err != nil || update == nil {
+			return nil
+		}
+		return messages.UpdateAvailableMsg{Update: update}
+	}
+}
+
+// updateVersion returns the version of the newer release, or ""
+func (a *App) updateVersion() string {
+	if a.update == nil {
+		return ""
+	}
+	return a.update.Version
+}
+
+// openChangelog shows the notes of the newer release if one was found,
+// otherwise the release notes bundled with this build
+func (a *App) openChangelog() {
+	if a.update != nil {
+		notes := a.update.Notes
+		if a.update.URL != "" {
+			notes += "\n\n" + a.update.URL
+		}
+		a.changelogOverlay = floating.NewChangelogOverlay("What's new in "+a.update.Version, notes)
+	} else {
+		a.changelogOverlay = floating.NewChangelogOverlay("Changelog", release.Changelog)
+	}
+	a.changelogOverlay.SetSize(a.width, a.height-1)
+	a.showChangelog = true
+}
+
+// handleChangelogKey scrolls the release notes or closes them
+func (a *App) handleChangelogKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "escape", "ctrl+g", "ctrl+c", "q", "W":
+		a.closeChangelog()
+		return nil
+	default:
+		_, cmd := a.changelogOverlay.Update(msg)
+		return cmd
+	}
+}
+
+// closeChangelog hides the release notes overlay
+func (a *App) closeChangelog() {
+	a.showChangelog = false
+	a.changelogOverlay = nil
+}
+
+func (a *App) overlayChangelog(background string) string {
+	return a.composite(background, a.changelogOverlay.View())
+}
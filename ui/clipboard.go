@@ -0,0 +1,121 @@
+package ui
+
+import (
+	"os"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/messages"
+)
+
+// toastDuration is how long a toast stays in the top border
+const toastDuration = 2 * time.Second
+
+// writeClipboard copies text to the system clipboard. Over SSH, or when no
+// clipboard tool is available, it falls back to an OSC52 escape sequence
+// so the local terminal sets its clipboard instead. The sequence goes to
+// stderr, which is the terminal even when stdout is piped for --pick.
+func writeClipboard(text string) error {
+	remote := os.Getenv("SSH_TTY") != "" || os.Getenv("SSH_CONNECTION") != ""
+	if !remote && clipboard.WriteAll(text) == nil {
+		return nil
+	}
+
+	seq := osc52.New(text)
+	switch {
+	case os.Getenv("TMUX") != "":
+		seq = seq.Tmux()
+	case os.Getenv("STY") != "":
+		seq = seq.Screen()
+	}
+	_, err := seq.WriteTo(os.Stderr)
+	return err
+}
+
+// handleCopyKey picks what to copy after Y: c the change ID, i the commit
+// ID, d the visible diff. Any other key cancels.
+func (a *App) handleCopyKey(msg tea.KeyMsg) tea.Cmd {
+	a.copyPending = false
+
+	switch msg.String() {
+	case "c":
+		if changeID := a.copyChangeID(); changeID != "" {
+			return copyText("change ID "+changeID, changeID)
+		}
+		return a.showToast("no change selected")
+	case "i":
+		if commitID := a.copyCommitID(); commitID != "" {
+			return copyText("commit ID "+commitID, commitID)
+		}
+		return a.showToast("no commit selected")
+	case "d":
+		if diff := a.visibleDiff(); diff != "" {
+			return copyText("diff", diff)
+		}
+		return a.showToast("no diff visible")
+	}
+	return nil
+}
+
+// copyChangeID returns the change being looked at: the log selection, or
+// the change open in the Change experience
+func (a *App) copyChangeID() string {
+	switch a.currentExperience {
+	case ExperienceLog:
+		if change := a.logPanel.SelectedChange(); change != nil {
+			return change.ChangeID
+		}
+	case ExperienceChange:
+		return a.selectedChangeID
+	}
+	return ""
+}
+
+// copyCommitID returns the commit ID of the change copyChangeID returns
+func (a *App) copyCommitID() string {
+	switch a.currentExperience {
+	case ExperienceLog:
+		if change := a.logPanel.SelectedChange(); change != nil {
+			return change.CommitID
+		}
+	case ExperienceChange:
+		commitID, err := jj.CommitIDOf(a.readCtx(), a.repoPath, a.selectedChangeID)
+		if err == nil {
+			return commitID
+		}
+	}
+	return ""
+}
+
+// visibleDiff returns the diff on screen: the Change experience's diff, or
+// the log preview when it is shown
+func (a *App) visibleDiff() string {
+	switch {
+	case a.currentExperience == ExperienceChange:
+		return a.diffPanel.Content()
+	case a.currentExperience == ExperienceLog && a.showPreview:
+		return a.previewPanel.Content()
+	}
+	return ""
+}
+
+// copyText writes text to the clipboard off the UI loop
+func copyText(what, text string) tea.Cmd {
+	return func() tea.Msg {
+		return messages.CopiedMsg{What: what, Err: writeClipboard(text)}
+	}
+}
+
+// showToast shows a short confirmation in the top border, cleared after
+// toastDuration
+func (a *App) showToast(text string) tea.Cmd {
+	a.toast = text
+	a.toastSeq++
+	seq := a.toastSeq
+	return tea.Tick(toastDuration, func(time.Time) tea.Msg {
+		return messages.ToastExpiredMsg{Seq: seq}
+	})
+}
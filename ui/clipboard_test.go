@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/config"
+	"github.com/gerunddev/jjazy/ui/messages"
+)
+
+// TestCopyPending verifies Y waits for the next key and any key ends the wait
+func TestCopyPending(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'Y'}})
+	if !a.copyPending {
+		t.Fatal("Expected Y to wait for what to copy")
+	}
+
+	// Nothing is selected in an empty log, so nothing is copied
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	if a.copyPending {
+		t.Error("Expected the key after Y to end the wait")
+	}
+	if a.toast != "no change selected" {
+		t.Errorf("Expected a toast saying nothing was copied, got %q", a.toast)
+	}
+
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'Y'}})
+	a.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if a.copyPending || a.currentExperience != ExperienceLog {
+		t.Error("Expected esc to cancel the copy and nothing else")
+	}
+}
+
+// TestToastExpiry verifies only the latest toast's expiry clears it
+func TestToastExpiry(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+
+	a.Update(messages.CopiedMsg{What: "change ID abc"})
+	first := a.toastSeq
+	a.Update(messages.CopiedMsg{What: "diff"})
+	if a.toast != "copied diff" {
+		t.Fatalf("Expected the latest toast, got %q", a.toast)
+	}
+
+	a.Update(messages.ToastExpiredMsg{Seq: first})
+	if a.toast == "" {
+		t.Error("Expected an older toast's expiry to leave the newer toast")
+	}
+	a.Update(messages.ToastExpiredMsg{Seq: a.toastSeq})
+	if a.toast != "" {
+		t.Errorf("Expected the toast to clear, got %q", a.toast)
+	}
+}
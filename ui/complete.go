@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gerunddev/jjazy/jj"
+)
+
+// revsetWordBreaks separate the word being completed from the rest of a revset
+const revsetWordBreaks = " |&~(),:"
+
+// revsetOperators are listed when tab is pressed after a complete term,
+// where an operator comes next rather than a name
+var revsetOperators = []string{
+	"| union", "& intersection", "~ difference", ":: ancestry", ".. range", "- parents", "+ children",
+}
+
+// revsetCompletion is a name a revset input can complete to
+type revsetCompletion struct {
+	Label  string // Shown when several names match
+	Insert string // Replaces the word being completed
+}
+
+// aliasInsertText is what completing an alias inserts: functions with
+// parameters stop after "(" so the arguments can be typed
+func aliasInsertText(name string) string {
+	if open := strings.Index(name, "("); open >= 0 && !strings.HasSuffix(name, "()") {
+		return name[:open+1]
+	}
+	return name
+}
+
+// revsetCompletions returns the names a revset can be completed to: the
+// revset aliases, then the bookmarks
+func revsetCompletions(aliases []jj.Alias, bookmarks []string) []revsetCompletion {
+	completions := make([]revsetCompletion, 0, len(aliases)+len(bookmarks))
+	for _, alias := range aliases {
+		completions = append(completions, revsetCompletion{Label: alias.Name, Insert: aliasInsertText(alias.Name)})
+	}
+	for _, name := range bookmarks {
+		completions = append(completions, revsetCompletion{Label: name, Insert: jj.RevsetSymbol(name)})
+	}
+	return completions
+}
+
+// completeRevset completes the last word of a revset against completions.
+// It returns the new value and the names that matched; with several
+// matches the word is only extended to their common prefix. After a
+// complete term it lists the operators instead.
+func completeRevset(value string, completions []revsetCompletion) (string, []string) {
+	start := strings.LastIndexAny(value, revsetWordBreaks) + 1
+	word := value[start:]
+	if word == "" && endsTerm(value) {
+		return value, revsetOperators
+	}
+
+	var matches, inserts []string
+	for _, completion := range completions {
+		// Quoted names match on the name too, before the quote is typed
+		if strings.HasPrefix(completion.Insert, word) || strings.HasPrefix(completion.Label, word) {
+			matches = append(matches, completion.Label)
+			inserts = append(inserts, completion.Insert)
+		}
+	}
+	if len(inserts) == 0 {
+		return value, nil
+	}
+
+	prefix := inserts[0]
+	for _, insert := range inserts[1:] {
+		for !strings.HasPrefix(insert, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	if len(prefix) < len(word) {
+		return value, matches // Quoted and bare names share nothing to add
+	}
+	return value[:start] + prefix, matches
+}
+
+// endsTerm reports whether value, ignoring trailing spaces, ends with a
+// complete term, so what follows is an operator
+func endsTerm(value string) bool {
+	last, _ := utf8.DecodeLastRuneInString(strings.TrimRight(value, " "))
+	return last == ')' || last == '"' || last == '@' || unicode.IsLetter(last) || unicode.IsDigit(last)
+}
@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/gerunddev/jjazy/jj"
+)
+
+func TestCompleteRevset(t *testing.T) {
+	aliases := []jj.Alias{
+		{Name: "mine"},
+		{Name: "trunk()"},
+		{Name: "wip(x)"},
+		{Name: "wipe"},
+	}
+
+	tests := []struct {
+		value       string
+		want        string
+		wantMatches int
+	}{
+		{"tr", "trunk()", 1},
+		{"@ | mi", "@ | mine", 1},
+		{"wip", "wip", 2}, // wip( and wipe share only "wip"
+		{"x & ~mi", "x & ~mine", 1},
+		{"zzz", "zzz", 0},
+		{"", "", 4},
+	}
+	for _, tt := range tests {
+		got, matches := completeRevset(tt.value, revsetCompletions(aliases, nil))
+		if got != tt.want || len(matches) != tt.wantMatches {
+			t.Errorf("completeRevset(%q) = %q, %d matches; want %q, %d", tt.value, got, len(matches), tt.want, tt.wantMatches)
+		}
+	}
+}
+
+// TestCompleteRevsetBookmarks verifies bookmarks complete, quoted when
+// their names have operator characters
+func TestCompleteRevsetBookmarks(t *testing.T) {
+	completions := revsetCompletions([]jj.Alias{{Name: "mine"}}, []string{"main", "feature&fix"})
+
+	if got, matches := completeRevset("::ma", completions); got != "::main" || len(matches) != 1 {
+		t.Errorf("completeRevset(::ma) = %q, %v; want ::main", got, matches)
+	}
+	if got, _ := completeRevset("fea", completions); got != `"feature&fix"` {
+		t.Errorf("completeRevset(fea) = %q, want the quoted name", got)
+	}
+	if got, matches := completeRevset("m", completions); got != "m" || len(matches) != 2 {
+		t.Errorf("completeRevset(m) = %q, %v; want mine and main listed", got, matches)
+	}
+}
+
+// TestCompleteRevsetOperators verifies tab after a complete term lists
+// the operators instead of names
+func TestCompleteRevsetOperators(t *testing.T) {
+	completions := revsetCompletions([]jj.Alias{{Name: "mine"}}, nil)
+
+	for _, value := range []string{"trunk()", "main ", "@ "} {
+		got, matches := completeRevset(value, completions)
+		if got != value || len(matches) != len(revsetOperators) {
+			t.Errorf("completeRevset(%q) = %q, %v; want the operators", value, got, matches)
+		}
+	}
+	if _, matches := completeRevset("main | ", completions); len(matches) != 1 {
+		t.Errorf("Expected names after an operator, got %v", matches)
+	}
+}
@@ -0,0 +1,50 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/config"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/messages"
+)
+
+// Actions with a policy in the confirm setting
+const (
+	abandonPolicy = "abandon"
+	squashPolicy  = "squash"
+)
+
+// askFirst decides whether action should ask before running on revset,
+// under its configured policy, and hands then the answer along with how
+// many changes outside revset descend from it (-1 when not counted or the
+// count failed). A failed count asks under only-if-descendants. Without a
+// policy, fallback decides from the count; a nil fallback never asks.
+// Policies that need the count take it in the background, answering with
+// a DescendantsCountedMsg; a later ask supersedes one still counting.
+func (a *App) askFirst(action, revset string, fallback func(descendants int) bool, then func(ask bool, descendants int) tea.Cmd) tea.Cmd {
+	policy := a.cfg.Confirm[action]
+	switch policy {
+	case config.ConfirmNever:
+		return then(false, -1)
+	case config.ConfirmAlways, config.ConfirmIfDescendants:
+	default:
+		if fallback == nil {
+			return then(false, -1)
+		}
+	}
+
+	a.askSeq++
+	a.pendingAsk = func(descendants int) tea.Cmd {
+		switch policy {
+		case config.ConfirmAlways:
+			return then(true, descendants)
+		case config.ConfirmIfDescendants:
+			return then(descendants != 0, descendants)
+		}
+		return then(fallback(descendants), descendants)
+	}
+	ctx, repoPath, seq := a.ctx, a.repoPath, a.askSeq
+	return func() tea.Msg {
+		count, err := jj.CountDescendants(ctx, repoPath, revset)
+		return messages.DescendantsCountedMsg{Seq: seq, Count: count, Err: err}
+	}
+}
@@ -0,0 +1,117 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/config"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/messages"
+)
+
+// newConfirmApp returns an app with one change in the log and confirm set
+// to policies
+func newConfirmApp(policies map[string]string) *App {
+	cfg := config.Default()
+	cfg.Confirm = policies
+	a := NewApp(nil, "/nonexistent/path", cfg, &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	a.logPanel.SetOutput(&jj.LogOutput{
+		LineToChange: []string{"work2222"},
+		Changes:      []jj.ChangeInfo{{ChangeID: "work2222", CommitID: "abcd1234", StartLine: 0, EndLine: 1}},
+	}, nil)
+	return a
+}
+
+// TestConfirmPolicies verifies each policy decides whether abandon and
+// squash ask first, given how many descendants the count found (-1 for a
+// count that failed)
+func TestConfirmPolicies(t *testing.T) {
+	tests := []struct {
+		name        string
+		policies    map[string]string
+		key         rune
+		descendants int    // Count delivered, when the policy takes one
+		action      string // Confirm action expected, or "" to run at once
+	}{
+		{"abandon by default, no descendants", nil, 'a', 0, ""},
+		{"abandon by default, few descendants", nil, 'a', 3, ""},
+		{"abandon by default, count failed", nil, 'a', -1, ""},
+		{"abandon always, no descendants", map[string]string{"abandon": config.ConfirmAlways}, 'a', 0, "abandon_descendants"},
+		{"abandon if descendants, none", map[string]string{"abandon": config.ConfirmIfDescendants}, 'a', 0, ""},
+		{"abandon if descendants, some", map[string]string{"abandon": config.ConfirmIfDescendants}, 'a', 2, "abandon_descendants"},
+		{"abandon if descendants, count failed", map[string]string{"abandon": config.ConfirmIfDescendants}, 'a', -1, "abandon_descendants"},
+		{"abandon never", map[string]string{"abandon": config.ConfirmNever}, 'a', 0, ""},
+		{"squash by default", nil, 's', 0, ""},
+		{"squash always, no descendants", map[string]string{"squash": config.ConfirmAlways}, 's', 0, "squash"},
+		{"squash if descendants, none", map[string]string{"squash": config.ConfirmIfDescendants}, 's', 0, ""},
+		{"squash if descendants, some", map[string]string{"squash": config.ConfirmIfDescendants}, 's', 4, "squash"},
+		{"unknown policy", map[string]string{"squash": "sometimes"}, 's', 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newConfirmApp(tt.policies)
+			a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{tt.key}})
+			if a.pendingAsk != nil {
+				if a.busy || a.showConfirm {
+					t.Fatal("Expected nothing to happen until the descendants are counted")
+				}
+				counted := messages.DescendantsCountedMsg{Seq: a.askSeq, Count: tt.descendants}
+				if tt.descendants < 0 {
+					counted.Err = errors.New("count failed")
+				}
+				a.Update(counted)
+			}
+			if tt.action == "" {
+				if a.showConfirm || !a.busy {
+					t.Errorf("Expected the action to run without asking, got confirm %q", a.confirmAction)
+				}
+				return
+			}
+			if !a.showConfirm || a.confirmAction != tt.action {
+				t.Fatalf("Expected confirm %q, got shown %v action %q", tt.action, a.showConfirm, a.confirmAction)
+			}
+			a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+			a.Update(tea.KeyMsg{Type: tea.KeyEnter})
+			if a.showConfirm || !a.busy {
+				t.Error("Expected confirming to run the action")
+			}
+		})
+	}
+}
+
+// TestAbandonManyDescendantsRequiresTyping verifies the default abandon
+// policy asks, with the change ID to type, once the count reaches the
+// threshold
+func TestAbandonManyDescendantsRequiresTyping(t *testing.T) {
+	a := newConfirmApp(nil)
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	a.Update(messages.DescendantsCountedMsg{Seq: a.askSeq, Count: abandonTypeToConfirm})
+
+	if !a.showConfirm || a.confirmAction != "abandon_descendants" {
+		t.Fatalf("Expected the abandon to ask, got shown %v action %q", a.showConfirm, a.confirmAction)
+	}
+	if !a.confirmOverlay.Pending() {
+		t.Error("Expected the change ID to be typed before confirming")
+	}
+}
+
+// TestStaleDescendantCountIgnored verifies a count for an ask that a later
+// one superseded decides nothing
+func TestStaleDescendantCountIgnored(t *testing.T) {
+	a := newConfirmApp(map[string]string{"abandon": config.ConfirmAlways, "squash": config.ConfirmAlways})
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	stale := a.askSeq
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+
+	a.Update(messages.DescendantsCountedMsg{Seq: stale, Count: 1})
+	if a.showConfirm {
+		t.Fatal("Expected a superseded count to be ignored")
+	}
+	a.Update(messages.DescendantsCountedMsg{Seq: a.askSeq, Count: 1})
+	if !a.showConfirm || a.confirmAction != "squash" {
+		t.Errorf("Expected the latest count to ask about the squash, got shown %v action %q", a.showConfirm, a.confirmAction)
+	}
+}
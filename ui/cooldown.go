@@ -0,0 +1,34 @@
+package ui
+
+import "time"
+
+// destructiveKeyCooldown is how long repeats of a destructive key are ignored
+// after the action fires
+const destructiveKeyCooldown = 500 * time.Millisecond
+
+// keyCooldown swallows key-repeat presses of the last destructive key so that
+// holding it down can't hit a new target each time the log refreshes
+type keyCooldown struct {
+	key   string
+	until time.Time
+}
+
+// Arm starts the cooldown for a key that just triggered a destructive action
+func (c *keyCooldown) Arm(key string, now time.Time) {
+	c.key = key
+	c.until = now.Add(destructiveKeyCooldown)
+}
+
+// Blocks reports whether the key should be ignored. A blocked repeat extends
+// the cooldown, so a held key stays suppressed until it is released.
+func (c *keyCooldown) Blocks(key string, now time.Time) bool {
+	if c.key == "" || key != c.key {
+		return false
+	}
+	if now.After(c.until) {
+		c.key = ""
+		return false
+	}
+	c.until = now.Add(destructiveKeyCooldown)
+	return true
+}
@@ -0,0 +1,44 @@
+package ui
+
+import (
+	"testing"
+	"time"
+)
+
+// TestKeyCooldownBlocksRepeats verifies repeats of the armed key are ignored
+func TestKeyCooldownBlocksRepeats(t *testing.T) {
+	var c keyCooldown
+	now := time.Now()
+
+	if c.Blocks("a", now) {
+		t.Error("Expected unarmed cooldown not to block")
+	}
+
+	c.Arm("a", now)
+	if !c.Blocks("a", now.Add(100*time.Millisecond)) {
+		t.Error("Expected repeat within cooldown to be blocked")
+	}
+	if c.Blocks("j", now.Add(100*time.Millisecond)) {
+		t.Error("Expected a different key not to be blocked")
+	}
+}
+
+// TestKeyCooldownHeldKey verifies a held key keeps extending the cooldown
+func TestKeyCooldownHeldKey(t *testing.T) {
+	var c keyCooldown
+	now := time.Now()
+	c.Arm("a", now)
+
+	// Key repeat every 50ms for well past the base cooldown
+	for i := 1; i <= 30; i++ {
+		if !c.Blocks("a", now.Add(time.Duration(i)*50*time.Millisecond)) {
+			t.Fatalf("Expected held key to stay blocked at repeat %d", i)
+		}
+	}
+
+	// Released and pressed again after the cooldown lapses
+	later := now.Add(30*50*time.Millisecond + destructiveKeyCooldown + time.Millisecond)
+	if c.Blocks("a", later) {
+		t.Error("Expected key to be accepted after the cooldown lapses")
+	}
+}
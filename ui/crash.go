@@ -0,0 +1,239 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/jj"
+)
+
+// What a crash report includes: the newest actions and operation log lines
+const (
+	crashActions  = 10
+	crashLogLines = 50
+)
+
+// Crash is a panic caught while the TUI was running
+type Crash struct {
+	Value any
+	Stack []byte // Stack of the panicking goroutine; nil when Bubble Tea caught the panic
+	State string // What the app was showing, if it could still say
+}
+
+// crashMsg carries a panic out of a command's goroutine
+type crashMsg struct {
+	crash *Crash
+}
+
+// crashStater is implemented by models that can describe their state for
+// a crash report
+type crashStater interface {
+	CrashState() string
+}
+
+// Recoverer wraps a model so a panic in Init, Update or a command quits the
+// program cleanly, which restores the terminal, and is kept for a crash
+// report. A panic in View is recorded and passed on for Bubble Tea to
+// recover, since View can't quit. Commands inside tea.Sequence are out of
+// reach; Bubble Tea recovers those itself.
+type Recoverer struct {
+	model tea.Model
+	crash *Crash
+}
+
+// Recover wraps model in a Recoverer
+func Recover(model tea.Model) *Recoverer {
+	return &Recoverer{model: model}
+}
+
+// Crash returns the panic that ended the program, or nil
+func (r *Recoverer) Crash() *Crash {
+	return r.crash
+}
+
+func (r *Recoverer) Init() (cmd tea.Cmd) {
+	defer func() {
+		if v := recover(); v != nil {
+			r.record(&Crash{Value: v, Stack: debug.Stack()})
+			cmd = tea.Quit
+		}
+	}()
+	return r.wrap(r.model.Init())
+}
+
+func (r *Recoverer) Update(msg tea.Msg) (model tea.Model, cmd tea.Cmd) {
+	if crashed, ok := msg.(crashMsg); ok {
+		r.record(crashed.crash)
+		return r, tea.Quit
+	}
+	if r.crash != nil {
+		return r, nil // Quitting; the model may be broken
+	}
+
+	defer func() {
+		if v := recover(); v != nil {
+			r.record(&Crash{Value: v, Stack: debug.Stack()})
+			model, cmd = r, tea.Quit
+		}
+	}()
+	r.model, cmd = r.model.Update(msg)
+	return r, r.wrap(cmd)
+}
+
+func (r *Recoverer) View() string {
+	if r.crash != nil {
+		return ""
+	}
+	defer func() {
+		if v := recover(); v != nil {
+			r.record(&Crash{Value: v, Stack: debug.Stack()})
+			panic(v)
+		}
+	}()
+	return r.model.View()
+}
+
+// record keeps the first crash, with the model's state if it can give it
+func (r *Recoverer) record(crash *Crash) {
+	if r.crash != nil {
+		return
+	}
+	r.crash = crash
+	if stater, ok := r.model.(crashStater); ok {
+		func() {
+			defer func() {
+				if recover() != nil {
+					crash.State = "(unavailable: describing the state panicked too)"
+				}
+			}()
+			crash.State = stater.CrashState()
+		}()
+	}
+}
+
+// wrap makes a command turn a panic into a crashMsg, and wraps the
+// commands of a batch it returns the same way
+func (r *Recoverer) wrap(cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() (msg tea.Msg) {
+		defer func() {
+			if v := recover(); v != nil {
+				msg = crashMsg{crash: &Crash{Value: v, Stack: debug.Stack()}}
+			}
+		}()
+		msg = cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			wrapped := make(tea.BatchMsg, len(batch))
+			for i, c := range batch {
+				wrapped[i] = r.wrap(c)
+			}
+			return wrapped
+		}
+		return msg
+	}
+}
+
+// CrashReportDir is where crash reports are written: jjazy's directory in
+// the user cache, or the temp directory if there is none
+func CrashReportDir() string {
+	if dir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(dir, "jjazy")
+	}
+	return os.TempDir()
+}
+
+// Report formats the crash for a bug report: the panic and its stack, the
+// app's state, and the newest lines of the operation log
+func (c *Crash) Report(version string, at time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "jjazy %s crashed at %s\n", version, at.Format(time.RFC3339))
+	fmt.Fprintf(&b, "%s %s/%s\n\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "panic: %v\n\n", c.Value)
+
+	b.WriteString("## Stack\n\n")
+	if len(c.Stack) > 0 {
+		b.Write(c.Stack)
+	} else {
+		b.WriteString("(not captured; Bubble Tea printed it to the terminal)\n")
+	}
+
+	b.WriteString("\n## App state\n\n")
+	if c.State != "" {
+		b.WriteString(c.State + "\n")
+	} else {
+		b.WriteString("(not captured)\n")
+	}
+
+	b.WriteString("\n## Recent operations\n\n")
+	lines, source, err := jj.DebugLog()
+	switch {
+	case err != nil:
+		fmt.Fprintf(&b, "(could not read the log: %v)\n", err)
+	case len(lines) == 0:
+		b.WriteString("(none)\n")
+	default:
+		fmt.Fprintf(&b, "From %s:\n", source)
+		b.WriteString(strings.Join(lines[max(len(lines)-crashLogLines, 0):], "\n") + "\n")
+	}
+	return b.String()
+}
+
+// WriteReport writes the crash report to a new file in dir and returns its path
+func (c *Crash) WriteReport(dir, version string) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	at := time.Now()
+	path := filepath.Join(dir, "crash-"+at.Format("20060102-150405")+".txt")
+	if err := os.WriteFile(path, []byte(c.Report(version, at)), 0o600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// CrashState describes what the app was showing, for a crash report
+func (a *App) CrashState() string {
+	experience := map[Experience]string{
+		ExperienceLog:        "log",
+		ExperienceChange:     "change",
+		ExperienceOperations: "operations",
+	}[a.currentExperience]
+
+	lines := []string{
+		fmt.Sprintf("repo: %s", a.repoPath),
+		fmt.Sprintf("size: %dx%d", a.width, a.height),
+		fmt.Sprintf("experience: %s, focused panel %d", experience, a.focusedPanel),
+	}
+	if change := a.logPanel.SelectedChange(); change != nil {
+		lines = append(lines, "log selection: "+change.ChangeID)
+	}
+	if a.selectedChangeID != "" {
+		lines = append(lines, "open change: "+a.selectedChangeID)
+	}
+	if a.atOp != "" {
+		lines = append(lines, "browsing operation: "+a.atOp)
+	}
+	if a.fileHistoryPath != "" {
+		lines = append(lines, "file history: "+a.fileHistoryPath)
+	}
+	if a.busy {
+		lines = append(lines, "busy: a mutation was running")
+	}
+
+	entries := a.history.entries
+	if len(entries) > 0 {
+		lines = append(lines, "", "Newest actions:")
+		for i := len(entries) - 1; i >= max(len(entries)-crashActions, 0); i-- {
+			lines = append(lines, "  "+entries[i].String())
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/config"
+)
+
+// panicky is a model that panics on the message "boom", or returns a
+// command that panics on "later"
+type panicky struct{}
+
+func (panicky) Init() tea.Cmd { return nil }
+
+func (p panicky) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg {
+	case "boom":
+		panic("boom")
+	case "later":
+		return p, tea.Batch(func() tea.Msg { return nil }, func() tea.Msg { panic("in a command") })
+	}
+	return p, nil
+}
+
+func (panicky) View() string { return "" }
+
+func (panicky) CrashState() string { return "state of panicky" }
+
+// TestRecovererUpdatePanic verifies a panic in Update is kept with its
+// stack and the model's state, and quits the program
+func TestRecovererUpdatePanic(t *testing.T) {
+	r := Recover(panicky{})
+	_, cmd := r.Update("boom")
+
+	crash := r.Crash()
+	if crash == nil || crash.Value != "boom" {
+		t.Fatalf("Expected the panic to be recorded, got %+v", crash)
+	}
+	if !strings.Contains(string(crash.Stack), "panicky.Update") {
+		t.Errorf("Expected the stack of the panic, got %s", crash.Stack)
+	}
+	if crash.State != "state of panicky" {
+		t.Errorf("Expected the model's state, got %q", crash.State)
+	}
+	if cmd == nil {
+		t.Fatal("Expected a command to quit")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("Expected the program to quit")
+	}
+}
+
+// TestRecovererCommandPanic verifies a panic in a batched command comes
+// back as a message that records the crash and quits
+func TestRecovererCommandPanic(t *testing.T) {
+	r := Recover(panicky{})
+	_, cmd := r.Update("later")
+	if r.Crash() != nil {
+		t.Fatal("Expected no crash before the command runs")
+	}
+
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok {
+		t.Fatalf("Expected the batch to pass through, got %T", cmd())
+	}
+	_, quit := r.Update(batch[1]())
+	if crash := r.Crash(); crash == nil || crash.Value != "in a command" {
+		t.Fatalf("Expected the command's panic to be recorded, got %+v", crash)
+	}
+	if _, ok := quit().(tea.QuitMsg); !ok {
+		t.Error("Expected the program to quit")
+	}
+}
+
+// TestCrashReport verifies the report carries the panic, stack and state
+// and is written to a new file
+func TestCrashReport(t *testing.T) {
+	crash := &Crash{Value: "boom", Stack: []byte("goroutine 1 [running]:"), State: "experience: log"}
+	report := crash.Report("v1.2.3", time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	for _, want := range []string{"jjazy v1.2.3 crashed at 2026-01-02T03:04:05Z", "panic: boom", "goroutine 1 [running]:", "experience: log", "## Recent operations"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("Expected the report to contain %q, got %q", want, report)
+		}
+	}
+
+	path, err := crash.WriteReport(t.TempDir(), "v1.2.3")
+	if err != nil {
+		t.Fatalf("WriteReport failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || !strings.Contains(string(data), "panic: boom") {
+		t.Errorf("Expected the report in %s, got %q (%v)", path, data, err)
+	}
+}
+
+// TestAppCrashState verifies the app describes its experience and recent actions
+func TestAppCrashState(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	a.history.Record(actionEntry{Name: "abandon", Subject: "xyz11111", At: time.Now()})
+
+	state := a.CrashState()
+	for _, want := range []string{"size: 120x40", "experience: log", "abandon xyz11111"} {
+		if !strings.Contains(state, want) {
+			t.Errorf("Expected the state to contain %q, got %q", want, state)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/floating"
+)
+
+// openDebugLog shows the newest lines of the operation log, from
+// JJAZY_LOG_FILE or, without one, this session's entries kept in memory
+func (a *App) openDebugLog() {
+	lines, source, err := jj.DebugLog()
+	if err != nil {
+		a.closeDebugLog()
+		a.showErrorDialog(err)
+		return
+	}
+	a.debugLogOverlay = floating.NewDebugLogOverlay(source, lines)
+	a.debugLogOverlay.SetSize(a.width, a.height-1)
+	a.showDebugLog = true
+}
+
+// handleDebugLogKey scrolls the debug log, copies it, reloads it or closes it
+func (a *App) handleDebugLogKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "escape", "ctrl+g", "ctrl+c", "q", "alt+ctrl+l":
+		a.closeDebugLog()
+		return nil
+	case "y":
+		return copyText("debug log", a.debugLogOverlay.Text())
+	case "r":
+		a.openDebugLog()
+		return nil
+	default:
+		_, cmd := a.debugLogOverlay.Update(msg)
+		return cmd
+	}
+}
+
+// closeDebugLog hides the debug log
+func (a *App) closeDebugLog() {
+	a.showDebugLog = false
+	a.debugLogOverlay = nil
+}
+
+func (a *App) overlayDebugLog(background string) string {
+	return a.composite(background, a.debugLogOverlay.View())
+}
@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/app"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/floating"
+)
+
+// openDescribe shows the describe input for a change. Configured trailers
+// are left out of the text and added back on save; if they can't all be
+// resolved (no user.email for sign-off), the input says so.
+func (a *App) openDescribe(change jj.ChangeInfo) {
+	a.openDescribeInput("Describe Change", change)
+	a.describeThenNew = false
+}
+
+// openCommit describes @ and then starts a new change on top of it, like
+// jj commit. @ must be in the log.
+func (a *App) openCommit() tea.Cmd {
+	for _, change := range a.logPanel.GetChanges() {
+		if change.IsWorkingCopy {
+			a.openDescribeInput("Commit Change", change)
+			a.describeThenNew = true
+			return nil
+		}
+	}
+	return a.showToast("@ is not in the log")
+}
+
+// openDescribeInput shows the describe input titled title
+func (a *App) openDescribeInput(title string, change jj.ChangeInfo) {
+	trailers, err := app.DescribeTrailers(a.ctx, a.repoPath, a.cfg.DescribeTrailers, a.cfg.SignOff)
+	a.describeTrailers = trailers
+
+	currentDesc, _ := jj.GetDescription(a.ctx, a.repoPath, change.ChangeID)
+	a.textInputOverlay = floating.NewTextInputOverlay(
+		title,
+		"Enter description...",
+		app.StripTrailers(currentDesc, trailers),
+	)
+	a.textInputOverlay.SetSize(a.width, a.height-1)
+	a.showTextInput = true
+	a.textInputAction = "describe"
+	a.textInputTarget = app.TargetOf(change)
+	if len(a.cfg.DescribeTemplates) > 0 {
+		a.textInputOverlay.AddHint("ctrl+t template")
+	}
+	if a.cfg.DescribeHook != "" {
+		a.textInputOverlay.AddHint("ctrl+r suggest")
+	}
+	if err != nil {
+		a.textInputOverlay.SetError(err.Error(), -1)
+	} else {
+		a.lintDescription()
+	}
+}
+
+// lintDescription warns about trailing whitespace or a long subject as the
+// description is typed. Warnings don't stop saving, and clearing one leaves
+// other status lines (like a pending suggestion) alone.
+func (a *App) lintDescription() {
+	warning, offset := app.LintDescription(a.textInputOverlay.Value(), a.cfg.SubjectLimit)
+	if warning != "" {
+		a.textInputOverlay.SetWarning(warning, offset)
+	} else if a.textInputOverlay.HasWarning() {
+		a.textInputOverlay.SetStatus("")
+	}
+}
+
+// saveDescription describes the change the describe input was opened on,
+// adding the configured trailers. When committing, a new change follows on
+// top of it.
+func (a *App) saveDescription(value string) tea.Cmd {
+	message := app.AddTrailers(value, a.describeTrailers)
+	commit := a.describeThenNew
+	a.describeThenNew = false
+	return a.runOnTarget(a.textInputTarget, func(ctx context.Context, changeID string) error {
+		if err := a.repo.Describe(ctx, changeID, message); err != nil || !commit {
+			return err
+		}
+		return a.advancing(ctx, func() error {
+			return a.repo.NewChange(ctx, changeID)
+		})
+	}, a.refreshLogPanels)
+}
@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/config"
+	"github.com/gerunddev/jjazy/jj"
+)
+
+// TestCommitDescribesWorkingCopy verifies c describes @ whatever is
+// selected, and marks the input to start a new change once saved
+func TestCommitDescribesWorkingCopy(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	a.logPanel.SetOutput(&jj.LogOutput{
+		LineToChange: []string{"side1111", "work2222"},
+		Changes: []jj.ChangeInfo{
+			{ChangeID: "side1111", CommitID: "1111abcd", StartLine: 0, EndLine: 1},
+			{ChangeID: "work2222", CommitID: "2222abcd", StartLine: 1, EndLine: 2, IsWorkingCopy: true},
+		},
+	}, nil)
+
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	if !a.showTextInput || a.textInputAction != "describe" || !a.describeThenNew {
+		t.Fatalf("Expected c to open the commit input, got input %v action %q", a.showTextInput, a.textInputAction)
+	}
+	if a.textInputTarget.ChangeID != "work2222" {
+		t.Errorf("Expected the commit to describe @, got %q", a.textInputTarget.ChangeID)
+	}
+
+	// A plain describe afterwards doesn't start a new change
+	a.showTextInput = false
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	if !a.showTextInput || a.describeThenNew {
+		t.Errorf("Expected d to describe without committing, got input %v commit %v", a.showTextInput, a.describeThenNew)
+	}
+}
+
+// TestCommitNeedsWorkingCopy verifies c leaves the input closed when the
+// log doesn't show @
+func TestCommitNeedsWorkingCopy(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	a.logPanel.SetOutput(&jj.LogOutput{
+		LineToChange: []string{"side1111"},
+		Changes:      []jj.ChangeInfo{{ChangeID: "side1111", CommitID: "1111abcd", StartLine: 0, EndLine: 1}},
+	}, nil)
+
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	if a.showTextInput || a.toast == "" {
+		t.Errorf("Expected a toast instead of the commit input, got input %v toast %q", a.showTextInput, a.toast)
+	}
+}
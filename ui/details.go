@@ -0,0 +1,46 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/floating"
+)
+
+// openDetails shows the full details of the selected revision. Its commit
+// ID finds it even when viewing an older operation.
+func (a *App) openDetails() {
+	change := a.logPanel.SelectedChange()
+	if change == nil {
+		return
+	}
+	details, err := jj.GetRevisionDetails(a.ctx, a.repoPath, change.CommitID)
+	if err != nil {
+		a.showErrorDialog(err)
+		return
+	}
+	a.detailsOverlay = floating.NewDetailsOverlay(details)
+	a.detailsOverlay.SetSize(a.width, a.height-1)
+	a.showDetails = true
+}
+
+// handleDetailsKey scrolls the revision details or closes them
+func (a *App) handleDetailsKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "escape", "ctrl+g", "ctrl+c", "q", "enter", "i":
+		a.closeDetails()
+		return nil
+	default:
+		_, cmd := a.detailsOverlay.Update(msg)
+		return cmd
+	}
+}
+
+// closeDetails hides the revision details
+func (a *App) closeDetails() {
+	a.showDetails = false
+	a.detailsOverlay = nil
+}
+
+func (a *App) overlayDetails(background string) string {
+	return a.composite(background, a.detailsOverlay.View())
+}
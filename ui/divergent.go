@@ -0,0 +1,72 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/floating"
+)
+
+// openDivergent lists the commits of every divergent change
+func (a *App) openDivergent() {
+	commits, err := jj.DivergentCommits(a.ctx, a.repoPath)
+	if err != nil {
+		a.showErrorDialog(err)
+		return
+	}
+	if len(commits) == 0 {
+		a.showInfoDialog("Divergent Changes", "No mutable change has more than one visible commit.")
+		return
+	}
+	a.divergentOverlay = floating.NewDivergentOverlay(commits)
+	a.divergentOverlay.SetSize(a.width, a.height-1)
+	a.showDivergent = true
+}
+
+// handleDivergentKey handles a key in the divergent changes overlay. Enter
+// asks to keep the selected commit and abandon the rest of its change.
+func (a *App) handleDivergentKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "escape", "ctrl+g", "ctrl+c":
+		a.closeDivergent()
+		return nil
+	case "enter":
+		keep := a.divergentOverlay.Selected()
+		abandon := a.divergentOverlay.Resolution()
+		if keep == nil || len(abandon) == 0 {
+			return nil
+		}
+		a.closeDivergent()
+		a.divergentAbandon = abandon
+		a.showConfirmDialog("Resolve Divergence",
+			fmt.Sprintf("Keep %s of %s and abandon %s?", keep.CommitID, keep.ChangeID, strings.Join(abandon, ", ")),
+			"resolve_divergent")
+		a.confirmOverlay.SetDanger("Abandon")
+		return nil
+	default:
+		_, cmd := a.divergentOverlay.Update(msg)
+		return cmd
+	}
+}
+
+// closeDivergent hides the divergent changes overlay
+func (a *App) closeDivergent() {
+	a.showDivergent = false
+	a.divergentOverlay = nil
+}
+
+// resolveDivergent abandons the commits picked in the divergent overlay
+func (a *App) resolveDivergent() tea.Cmd {
+	abandon := a.divergentAbandon
+	a.divergentAbandon = nil
+	return a.runMutation(func(ctx context.Context) error {
+		return jj.AbandonCommits(ctx, a.repoPath, abandon)
+	}, a.refreshLogPanels)
+}
+
+func (a *App) overlayDivergent(background string) string {
+	return a.composite(background, a.divergentOverlay.View())
+}
@@ -0,0 +1,177 @@
+package ui
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/config"
+	"github.com/gerunddev/jjazy/jj"
+)
+
+// waitTimeout bounds how long waitFor lets jj work before failing the test
+const waitTimeout = 10 * time.Second
+
+// newFixtureRepo creates a jj repository for scripted sessions: one commit,
+// "add a and b", with the bookmark feature, and a working copy that
+// modifies a.txt and adds c.txt. It skips the test when jj isn't installed.
+func newFixtureRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("jj"); err != nil {
+		t.Skipf("jj not available: %v", err)
+	}
+	t.Setenv("JJ_USER", "Test User")
+	t.Setenv("JJ_EMAIL", "test@example.com")
+
+	dir := t.TempDir()
+	jjRun(t, dir, "git", "init")
+	writeFile(t, dir, "a.txt", "a\n")
+	writeFile(t, dir, "b.txt", "b\n")
+	jjRun(t, dir, "commit", "-m", "add a and b")
+	jjRun(t, dir, "bookmark", "create", "feature", "-r", "@-")
+	writeFile(t, dir, "a.txt", "a changed\n")
+	writeFile(t, dir, "c.txt", "c\n")
+	return dir
+}
+
+// jjRun runs the jj CLI in dir and returns its trimmed output
+func jjRun(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("jj", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		stderr := ""
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			stderr = string(exitErr.Stderr)
+		}
+		t.Fatalf("jj %s: %v\n%s", strings.Join(args, " "), err, stderr)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+}
+
+// driver runs an App against a real repository the way tea.Program does:
+// keys go through Update, and the commands Update returns run in the
+// background, their messages fed back in by waitFor
+type driver struct {
+	t    *testing.T
+	app  *App
+	msgs chan tea.Msg
+}
+
+// newDriver opens the repository at repoPath and starts an App on a
+// 120x40 screen
+func newDriver(t *testing.T, repoPath string) *driver {
+	t.Helper()
+	t.Setenv(config.TestModeEnv, "1")
+
+	repo, err := jj.Open(repoPath)
+	if err != nil {
+		t.Fatalf("open %s: %v", repoPath, err)
+	}
+	t.Cleanup(func() { repo.Close() })
+
+	d := &driver{
+		t:    t,
+		app:  NewApp(repo, repoPath, config.Default(), &config.State{}),
+		msgs: make(chan tea.Msg, 64),
+	}
+	d.run(d.app.Init())
+	d.send(tea.WindowSizeMsg{Width: 120, Height: 40})
+	return d
+}
+
+// send passes msg to Update and runs the command it returns
+func (d *driver) send(msg tea.Msg) {
+	_, cmd := d.app.Update(msg)
+	d.run(cmd)
+}
+
+// run runs cmd in the background, as tea.Program does
+func (d *driver) run(cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+	go d.deliver(cmd())
+}
+
+// cmdsType is the type of tea.Sequence's unexported message
+var cmdsType = reflect.TypeOf([]tea.Cmd(nil))
+
+// deliver queues msg for waitFor, running the commands of batches and
+// sequences instead
+func (d *driver) deliver(msg tea.Msg) {
+	switch msg := msg.(type) {
+	case nil, tea.QuitMsg:
+	case tea.BatchMsg:
+		for _, cmd := range msg {
+			d.run(cmd)
+		}
+	default:
+		if v := reflect.ValueOf(msg); v.Type().ConvertibleTo(cmdsType) {
+			// A sequence: each command finishes before the next starts
+			for _, cmd := range v.Convert(cmdsType).Interface().([]tea.Cmd) {
+				if cmd != nil {
+					d.deliver(cmd())
+				}
+			}
+			return
+		}
+		d.msgs <- msg
+	}
+}
+
+// keyNames maps the key names press takes to special keys
+var keyNames = map[string]tea.KeyType{
+	"enter": tea.KeyEnter,
+	"esc":   tea.KeyEsc,
+	"tab":   tea.KeyTab,
+	"up":    tea.KeyUp,
+	"down":  tea.KeyDown,
+	"left":  tea.KeyLeft,
+	"right": tea.KeyRight,
+}
+
+// press types keys in order: names from keyNames, anything else as runes
+func (d *driver) press(keys ...string) {
+	for _, k := range keys {
+		if keyType, ok := keyNames[k]; ok {
+			d.send(tea.KeyMsg{Type: keyType})
+		} else {
+			d.send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+		}
+	}
+}
+
+// waitFor feeds command results back into the app until cond holds,
+// failing with the screen if it doesn't within waitTimeout
+func (d *driver) waitFor(what string, cond func() bool) {
+	d.t.Helper()
+	deadline := time.After(waitTimeout)
+	for !cond() {
+		select {
+		case msg := <-d.msgs:
+			d.send(msg)
+		case <-deadline:
+			d.t.Fatalf("timed out waiting for %s; screen:\n%s", what, d.app.View())
+		}
+	}
+}
+
+// settle waits for the running mutation, if any, to finish
+func (d *driver) settle() {
+	d.t.Helper()
+	d.waitFor("the mutation to finish", func() bool { return !d.app.busy })
+}
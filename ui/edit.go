@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/app"
+	"github.com/gerunddev/jjazy/jj"
+)
+
+// swappedEnter reports whether Enter opens the Change view and → edits,
+// which only applies to the log panel outside its picking and range modes
+func (a *App) swappedEnter() bool {
+	return a.cfg.EnterOpensChange && a.focused(a.logPanel) &&
+		a.pickMode == PickNone && !a.bookmarkSetMode && !a.rebaseMode && !a.squashIntoMode &&
+		!a.logPanel.InVisualMode()
+}
+
+// edit runs jj edit on the target, asking first when the revision is
+// immutable or a remote bookmark points at it
+func (a *App) edit(target app.Target) tea.Cmd {
+	if target.IsWorkingCopy {
+		return a.runEdit(target, false)
+	}
+	risk, err := jj.CheckEdit(a.ctx, a.repoPath, target.CommitID)
+	if err != nil {
+		a.showErrorDialog(err)
+		return nil
+	}
+	if !risk.Risky() {
+		return a.runEdit(target, false)
+	}
+	a.editTarget = target
+	a.editIgnoreImmutable = risk.Immutable
+	title := "Edit Pushed Revision"
+	if risk.Immutable {
+		title = "Edit Immutable Revision"
+	}
+	a.showConfirmDialog(title, editRiskMessage(target, risk), "edit_risky")
+	return nil
+}
+
+// editRiskMessage explains what editing a risky revision leads to
+func editRiskMessage(target app.Target, risk *jj.EditRisk) string {
+	var reasons []string
+	if risk.Immutable {
+		reasons = append(reasons, "is immutable")
+	}
+	if len(risk.RemoteBookmarks) > 0 {
+		reasons = append(reasons, "is pushed as "+strings.Join(risk.RemoteBookmarks, ", "))
+	}
+	return fmt.Sprintf("%s %s. Changes made while editing it rewrite a published commit: "+
+		"its descendants are rebased, and anyone who already has it will see it diverge. "+
+		"Edit anyway?", target.ChangeID, strings.Join(reasons, " and "))
+}
+
+// runEdit makes the target the working copy
+func (a *App) runEdit(target app.Target, ignoreImmutable bool) tea.Cmd {
+	return a.runOnTarget(target, func(ctx context.Context, changeID string) error {
+		if ignoreImmutable {
+			return jj.EditIgnoringImmutable(ctx, a.repoPath, changeID)
+		}
+		return jj.Edit(ctx, a.repoPath, changeID)
+	}, a.refreshLogPanels)
+}
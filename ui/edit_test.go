@@ -0,0 +1,26 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gerunddev/jjazy/app"
+	"github.com/gerunddev/jjazy/jj"
+)
+
+// TestEditRiskMessage verifies the confirmation names every reason
+func TestEditRiskMessage(t *testing.T) {
+	target := app.Target{ChangeID: "abcdefgh", CommitID: "12345678"}
+
+	msg := editRiskMessage(target, &jj.EditRisk{Immutable: true, RemoteBookmarks: []string{"main@origin"}})
+	for _, want := range []string{"abcdefgh", "is immutable", "is pushed as main@origin", "diverge"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("Expected message to contain %q, got %q", want, msg)
+		}
+	}
+
+	msg = editRiskMessage(target, &jj.EditRisk{RemoteBookmarks: []string{"feat@origin"}})
+	if strings.Contains(msg, "immutable") {
+		t.Errorf("Expected no immutability note for a mutable pushed revision, got %q", msg)
+	}
+}
@@ -0,0 +1,32 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gerunddev/jjazy/jj"
+)
+
+// errorText formats an error for the error dialog. Failed jj commands show
+// jj's own message and hints rather than the raw stderr dump.
+func errorText(err error) string {
+	var jjErr *jj.JJError
+	if !errors.As(err, &jjErr) {
+		return err.Error()
+	}
+
+	parts := []string{jjErr.Message()}
+	for _, hint := range jjErr.Hints() {
+		parts = append(parts, "Hint: "+hint)
+	}
+	if jjErr.ExitCode >= 0 {
+		parts = append(parts, fmt.Sprintf("jj %s exited with status %d", jjErr.Subcommand(), jjErr.ExitCode))
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// showErrorDialog displays an error in the info dialog
+func (a *App) showErrorDialog(err error) {
+	a.showInfoDialog("Error", errorText(err))
+}
@@ -0,0 +1,29 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gerunddev/jjazy/jj"
+)
+
+// TestErrorText verifies jj failures are shown as message, hints and status
+func TestErrorText(t *testing.T) {
+	err := &jj.JJError{
+		Args:     []string{"abandon", "abc"},
+		ExitCode: 1,
+		Stderr:   "Error: Commit abc is immutable\nHint: Pass `--ignore-immutable` to continue.",
+		Err:      errors.New("exit status 1"),
+	}
+
+	want := "Commit abc is immutable\n\n" +
+		"Hint: Pass `--ignore-immutable` to continue.\n\n" +
+		"jj abandon exited with status 1"
+	if got := errorText(err); got != want {
+		t.Errorf("errorText() = %q, want %q", got, want)
+	}
+
+	if got := errorText(errors.New("plain")); got != "plain" {
+		t.Errorf("errorText() = %q, want %q", got, "plain")
+	}
+}
@@ -9,6 +9,7 @@ const (
 	StatusDeleted
 	StatusRenamed
 	StatusConflict
+	StatusUntracked // Present in the working copy but not tracked by jj
 )
 
 func (s FileStatus) String() string {
@@ -23,6 +24,8 @@ func (s FileStatus) String() string {
 		return "R"
 	case StatusConflict:
 		return "C"
+	case StatusUntracked:
+		return "?"
 	default:
 		return "?"
 	}
@@ -49,6 +52,8 @@ type Bookmark struct {
 	IsLocal   bool
 	RevisionID string
 	IsCurrent bool
+	Ahead      int // Commits not yet on the tracked remote bookmark
+	Behind     int // Commits on the tracked remote bookmark not yet here
 }
 
 // Operation represents a jj operation in the undo history
@@ -67,11 +72,15 @@ type Revision struct {
 	Author        string
 	Timestamp     string
 	Bookmarks     []string
+	Tags          []string
 	GitHead       bool
 	IsWorkingCopy bool
 	WorkspaceName string
 	IsRoot        bool
 	Parents       []string
+	Divergent     bool
+	Hidden        bool
+	Immutable     bool
 }
 
 // Mock data for development
@@ -0,0 +1,132 @@
+package floating
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/gerunddev/jjazy/ui/borders"
+	"github.com/gerunddev/jjazy/ui/theme"
+)
+
+// ActionLogOverlay lists the actions run this session, one per row
+type ActionLogOverlay struct {
+	rows   []string // Newest first
+	offset int      // First row shown
+	width  int
+	height int
+	ready  bool
+}
+
+// NewActionLogOverlay creates the overlay for pre-formatted rows
+func NewActionLogOverlay(rows []string) *ActionLogOverlay {
+	return &ActionLogOverlay{rows: rows}
+}
+
+func (l *ActionLogOverlay) Init() tea.Cmd {
+	return nil
+}
+
+func (l *ActionLogOverlay) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return l, nil
+	}
+
+	switch keyMsg.String() {
+	case "up", "ctrl+p":
+		l.scroll(-1)
+	case "down", "ctrl+n":
+		l.scroll(1)
+	case "pgup", "alt+v":
+		l.scroll(-l.listRows())
+	case "pgdown", "ctrl+v":
+		l.scroll(l.listRows())
+	}
+	return l, nil
+}
+
+// scroll moves the view by delta rows, within the list
+func (l *ActionLogOverlay) scroll(delta int) {
+	l.offset = max(min(l.offset+delta, len(l.rows)-l.listRows()), 0)
+}
+
+// listRows returns how many rows fit in the window
+func (l *ActionLogOverlay) listRows() int {
+	return max(l.windowHeight()-6, 1) // Borders, blank lines and the hint line
+}
+
+func (l *ActionLogOverlay) View() string {
+	if !l.ready {
+		return l.renderFrame("Initializing...")
+	}
+
+	lines := []string{""}
+	if len(l.rows) == 0 {
+		lines = append(lines, "  "+theme.DimmedStyle.Render("No actions yet"))
+	}
+	end := min(l.offset+l.listRows(), len(l.rows))
+	for _, row := range l.rows[l.offset:end] {
+		// Long errors are cut rather than wrapped, to keep one row per action
+		row = ansi.Truncate(row, max(l.windowWidth()-6, 10), "…")
+		lines = append(lines, "  "+theme.NormalItemStyle.Render(row))
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, "  "+theme.HelpDescStyle.Render(". repeats the newest  esc close"))
+	return l.renderFrame(strings.Join(lines, "\n"))
+}
+
+func (l *ActionLogOverlay) SetSize(width, height int) {
+	l.width = width
+	l.height = height
+	l.ready = true
+	l.scroll(0)
+}
+
+func (l *ActionLogOverlay) windowWidth() int {
+	return min(80, l.width-4)
+}
+
+// windowHeight sizes the window to the list, within the screen
+func (l *ActionLogOverlay) windowHeight() int {
+	return max(min(len(l.rows)+6, l.height-4), 8)
+}
+
+func (l *ActionLogOverlay) renderFrame(content string) string {
+	// Calculate centered window dimensions
+	windowWidth := l.windowWidth()
+	windowHeight := l.windowHeight()
+
+	// Center the window
+	x := (l.width - windowWidth) / 2
+	y := (l.height - windowHeight) / 2
+
+	borderStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.ColorYellow).
+		Width(windowWidth - 2).
+		Height(windowHeight - 2)
+
+	bordered := borderStyle.Render(content)
+
+	// Add title to top border
+	lines := strings.Split(bordered, "\n")
+	if len(lines) > 0 {
+		borderColorStyle := lipgloss.NewStyle().Foreground(theme.ColorYellow)
+		styledTitle := theme.FloatingTitleStyle.Render(" Action Log ")
+
+		remainingWidth := max(windowWidth-3-lipgloss.Width(styledTitle), 0)
+		lines[0] = borderColorStyle.Render(borders.TopLeft+borders.Horizontal) +
+			styledTitle +
+			borderColorStyle.Render(strings.Repeat(borders.Horizontal, remainingWidth)+borders.TopRight)
+	}
+
+	// Pad to center the window
+	paddingLeft := strings.Repeat(" ", x)
+	for i := range lines {
+		lines[i] = paddingLeft + lines[i]
+	}
+	return strings.Repeat("\n", y) + strings.Join(lines, "\n")
+}
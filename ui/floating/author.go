@@ -0,0 +1,170 @@
+package floating
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/borders"
+	"github.com/gerunddev/jjazy/ui/theme"
+)
+
+// authorFields labels the form's inputs, in order
+var authorFields = []string{"Name", "Email", "Date"}
+
+// AuthorOverlay is a form for a change's author name, email and timestamp.
+// Saving or resetting to the configured user is left to the caller.
+type AuthorOverlay struct {
+	inputs []textinput.Model
+	active int // Index of the focused input
+	err    string
+	width  int
+	height int
+	ready  bool
+}
+
+// NewAuthorOverlay creates the form filled in with the change's author
+func NewAuthorOverlay(author jj.Author) *AuthorOverlay {
+	values := []string{author.Name, author.Email, author.Timestamp}
+	inputs := make([]textinput.Model, len(values))
+	for i, value := range values {
+		ti := textinput.New()
+		ti.Prompt = ""
+		ti.CharLimit = 200
+		ti.Width = 40
+		ti.SetValue(value)
+		inputs[i] = ti
+	}
+	inputs[0].Focus()
+	return &AuthorOverlay{inputs: inputs}
+}
+
+func (a *AuthorOverlay) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (a *AuthorOverlay) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "tab", "down", "ctrl+n":
+			a.focus((a.active + 1) % len(a.inputs))
+			return a, nil
+		case "shift+tab", "up", "ctrl+p":
+			a.focus((a.active + len(a.inputs) - 1) % len(a.inputs))
+			return a, nil
+		}
+	}
+	var cmd tea.Cmd
+	a.inputs[a.active], cmd = a.inputs[a.active].Update(msg)
+	return a, cmd
+}
+
+// focus moves the cursor to input i
+func (a *AuthorOverlay) focus(i int) {
+	a.inputs[a.active].Blur()
+	a.active = i
+	a.inputs[a.active].Focus()
+}
+
+// Author returns the author as entered. An empty date keeps the current
+// timestamp.
+func (a *AuthorOverlay) Author() jj.Author {
+	return jj.Author{
+		Name:      strings.TrimSpace(a.inputs[0].Value()),
+		Email:     strings.TrimSpace(a.inputs[1].Value()),
+		Timestamp: strings.TrimSpace(a.inputs[2].Value()),
+	}
+}
+
+// Validate returns why the entered author can't be saved, or ""
+func (a *AuthorOverlay) Validate() string {
+	author := a.Author()
+	switch {
+	case author.Name == "":
+		return "name is empty"
+	case !strings.Contains(author.Email, "@"):
+		return "email needs an @"
+	}
+	return ""
+}
+
+// SetError shows an error under the form
+func (a *AuthorOverlay) SetError(message string) {
+	a.err = message
+}
+
+func (a *AuthorOverlay) View() string {
+	if !a.ready {
+		return a.renderFrame("Initializing...")
+	}
+
+	lines := []string{""}
+	for i, input := range a.inputs {
+		label := lipgloss.NewStyle().Width(7).Render(authorFields[i])
+		if i == a.active {
+			label = theme.SelectedItemStyle.Render(label)
+		} else {
+			label = theme.NormalItemStyle.Render(label)
+		}
+		lines = append(lines, "  "+label+input.View())
+	}
+	lines = append(lines, "")
+	if a.err != "" {
+		lines = append(lines, "  "+theme.DeletedStyle.Render(a.err))
+	} else {
+		lines = append(lines, "  "+theme.DimmedStyle.Render("Clear the date to keep the current timestamp"))
+	}
+	lines = append(lines, "")
+	lines = append(lines, theme.HelpDescStyle.Render("  tab next • ctrl+s save • ctrl+r reset to me • ctrl+x cancel"))
+	return a.renderFrame(strings.Join(lines, "\n"))
+}
+
+func (a *AuthorOverlay) SetSize(width, height int) {
+	a.width = width
+	a.height = height
+	a.ready = true
+
+	inputWidth := max(min(50, width-16), 10)
+	for i := range a.inputs {
+		a.inputs[i].Width = inputWidth
+	}
+}
+
+func (a *AuthorOverlay) renderFrame(content string) string {
+	// Calculate centered window dimensions
+	windowWidth := min(70, a.width-4)
+	windowHeight := 11
+
+	// Center the window
+	x := (a.width - windowWidth) / 2
+	y := (a.height - windowHeight) / 2
+
+	borderStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.ColorYellow).
+		Width(windowWidth - 2).
+		Height(windowHeight - 2)
+
+	bordered := borderStyle.Render(content)
+
+	// Add title to top border
+	lines := strings.Split(bordered, "\n")
+	if len(lines) > 0 {
+		borderColorStyle := lipgloss.NewStyle().Foreground(theme.ColorYellow)
+		styledTitle := theme.FloatingTitleStyle.Render(" Edit Author ")
+
+		remainingWidth := max(windowWidth-3-lipgloss.Width(styledTitle), 0)
+		lines[0] = borderColorStyle.Render(borders.TopLeft+borders.Horizontal) +
+			styledTitle +
+			borderColorStyle.Render(strings.Repeat(borders.Horizontal, remainingWidth)+borders.TopRight)
+	}
+
+	// Pad to center the window
+	paddingLeft := strings.Repeat(" ", x)
+	for i := range lines {
+		lines[i] = paddingLeft + lines[i]
+	}
+	return strings.Repeat("\n", y) + strings.Join(lines, "\n")
+}
@@ -0,0 +1,37 @@
+package floating
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/jj"
+)
+
+// TestAuthorOverlayFields verifies tab moves between fields and the
+// entered author is validated
+func TestAuthorOverlayFields(t *testing.T) {
+	o := NewAuthorOverlay(jj.Author{Name: "Wrong Name", Email: "wrong", Timestamp: "2024-01-02T03:04:05+00:00"})
+	o.SetSize(100, 30)
+
+	if got := o.Validate(); got != "email needs an @" {
+		t.Errorf("Validate() = %q, want the email problem", got)
+	}
+
+	o.Update(tea.KeyMsg{Type: tea.KeyTab})
+	o.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("@example.com")})
+	o.Update(tea.KeyMsg{Type: tea.KeyTab})
+	o.inputs[2].SetValue("")
+
+	want := jj.Author{Name: "Wrong Name", Email: "wrong@example.com"}
+	if got := o.Author(); got != want {
+		t.Errorf("Author() = %+v, want %+v", got, want)
+	}
+	if got := o.Validate(); got != "" {
+		t.Errorf("Validate() = %q, want no problem", got)
+	}
+
+	o.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	if o.active != 1 {
+		t.Errorf("Expected shift+tab to go back to the email, got field %d", o.active)
+	}
+}
@@ -0,0 +1,150 @@
+package floating
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gerunddev/jjazy/ui/borders"
+	"github.com/gerunddev/jjazy/ui/theme"
+)
+
+// ChangelogOverlay shows release notes in a scrollable window
+type ChangelogOverlay struct {
+	title  string
+	notes  string
+	offset int // First line shown
+	width  int
+	height int
+	ready  bool
+}
+
+// NewChangelogOverlay creates the overlay for markdown release notes
+func NewChangelogOverlay(title, notes string) *ChangelogOverlay {
+	return &ChangelogOverlay{
+		title: title,
+		notes: strings.TrimSpace(notes),
+	}
+}
+
+func (c *ChangelogOverlay) Init() tea.Cmd {
+	return nil
+}
+
+func (c *ChangelogOverlay) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return c, nil
+	}
+
+	switch keyMsg.String() {
+	case "up", "ctrl+p":
+		c.scroll(-1)
+	case "down", "ctrl+n":
+		c.scroll(1)
+	case "pgup", "alt+v":
+		c.scroll(-c.textRows())
+	case "pgdown", "ctrl+v", " ":
+		c.scroll(c.textRows())
+	case "home", "alt+<":
+		c.offset = 0
+	case "end", "alt+>":
+		c.scroll(len(c.lines()))
+	}
+	return c, nil
+}
+
+// scroll moves the view by delta lines, within the notes
+func (c *ChangelogOverlay) scroll(delta int) {
+	c.offset = max(min(c.offset+delta, len(c.lines())-c.textRows()), 0)
+}
+
+// lines returns the notes wrapped to the window
+func (c *ChangelogOverlay) lines() []string {
+	if c.notes == "" {
+		return []string{theme.DimmedStyle.Render("No release notes.")}
+	}
+	wrapped := lipgloss.NewStyle().Width(max(c.windowWidth()-6, 10)).Render(c.notes)
+	lines := strings.Split(wrapped, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "#") {
+			lines[i] = theme.FloatingTitleStyle.Render(strings.TrimSpace(strings.TrimLeft(line, "#")))
+		}
+	}
+	return lines
+}
+
+// textRows returns how many lines of notes fit in the window
+func (c *ChangelogOverlay) textRows() int {
+	return max(c.windowHeight()-6, 1) // Borders, blank lines and the hint line
+}
+
+func (c *ChangelogOverlay) View() string {
+	if !c.ready {
+		return c.renderFrame("Initializing...")
+	}
+
+	lines := c.lines()
+	end := min(c.offset+c.textRows(), len(lines))
+
+	content := []string{""}
+	for _, line := range lines[c.offset:end] {
+		content = append(content, "  "+line)
+	}
+	content = append(content, "")
+	content = append(content, "  "+theme.HelpDescStyle.Render("↑/↓ scroll  esc close"))
+	return c.renderFrame(strings.Join(content, "\n"))
+}
+
+func (c *ChangelogOverlay) SetSize(width, height int) {
+	c.width = width
+	c.height = height
+	c.ready = true
+	c.scroll(0)
+}
+
+func (c *ChangelogOverlay) windowWidth() int {
+	return min(80, c.width-4)
+}
+
+// windowHeight sizes the window to the notes, within the screen
+func (c *ChangelogOverlay) windowHeight() int {
+	return max(min(len(c.lines())+6, c.height-4), 8)
+}
+
+func (c *ChangelogOverlay) renderFrame(content string) string {
+	// Calculate centered window dimensions
+	windowWidth := c.windowWidth()
+	windowHeight := c.windowHeight()
+
+	// Center the window
+	x := (c.width - windowWidth) / 2
+	y := (c.height - windowHeight) / 2
+
+	borderStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.ColorYellow).
+		Width(windowWidth - 2).
+		Height(windowHeight - 2)
+
+	bordered := borderStyle.Render(content)
+
+	// Add title to top border
+	lines := strings.Split(bordered, "\n")
+	if len(lines) > 0 {
+		borderColorStyle := lipgloss.NewStyle().Foreground(theme.ColorYellow)
+		styledTitle := theme.FloatingTitleStyle.Render(" " + c.title + " ")
+
+		remainingWidth := max(windowWidth-3-lipgloss.Width(styledTitle), 0)
+		lines[0] = borderColorStyle.Render(borders.TopLeft+borders.Horizontal) +
+			styledTitle +
+			borderColorStyle.Render(strings.Repeat(borders.Horizontal, remainingWidth)+borders.TopRight)
+	}
+
+	// Pad to center the window
+	paddingLeft := strings.Repeat(" ", x)
+	for i := range lines {
+		lines[i] = paddingLeft + lines[i]
+	}
+	return strings.Repeat("\n", y) + strings.Join(lines, "\n")
+}
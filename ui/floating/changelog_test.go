@@ -0,0 +1,37 @@
+package floating
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestChangelogScroll verifies long notes scroll and stop at the last line
+func TestChangelogScroll(t *testing.T) {
+	var notes []string
+	for i := 0; i < 50; i++ {
+		notes = append(notes, "- entry "+string(rune('A'+i%26)))
+	}
+	notes[0] = "## v0.4.0"
+	notes[49] = "- last entry"
+	c := NewChangelogOverlay("What's new in v0.4.0", strings.Join(notes, "\n"))
+	c.SetSize(100, 20)
+
+	view := c.View()
+	if !strings.Contains(view, "What's new in v0.4.0") || !strings.Contains(view, "v0.4.0") {
+		t.Errorf("Expected the title and first heading, got %q", view)
+	}
+	if strings.Contains(view, "last entry") {
+		t.Error("Expected the last entry to be scrolled out of view")
+	}
+
+	c.Update(tea.KeyMsg{Type: tea.KeyEnd})
+	if view := c.View(); !strings.Contains(view, "last entry") {
+		t.Error("Expected end to show the last entry")
+	}
+	c.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if view := c.View(); !strings.Contains(view, "last entry") {
+		t.Error("Expected scrolling past the end to stop at the last entry")
+	}
+}
@@ -0,0 +1,225 @@
+package floating
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/borders"
+	"github.com/gerunddev/jjazy/ui/theme"
+)
+
+// BookmarkCleanupOverlay lists stale bookmarks for multi-select deletion.
+// Confirming the selection first shows a dry-run preview of what would be
+// deleted; confirming that is left to the caller.
+type BookmarkCleanupOverlay struct {
+	bookmarks  []jj.StaleBookmark
+	marked     map[string]bool
+	cursor     int
+	offset     int  // First row shown in the list
+	previewing bool // True while showing the dry-run preview
+	width      int
+	height     int
+	ready      bool
+}
+
+// NewBookmarkCleanupOverlay creates the overlay with every bookmark marked
+func NewBookmarkCleanupOverlay(bookmarks []jj.StaleBookmark) *BookmarkCleanupOverlay {
+	marked := make(map[string]bool, len(bookmarks))
+	for _, b := range bookmarks {
+		marked[b.Name] = true
+	}
+	return &BookmarkCleanupOverlay{
+		bookmarks: bookmarks,
+		marked:    marked,
+	}
+}
+
+func (c *BookmarkCleanupOverlay) Init() tea.Cmd {
+	return nil
+}
+
+func (c *BookmarkCleanupOverlay) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok || c.previewing {
+		return c, nil
+	}
+
+	switch keyMsg.String() {
+	case "up", "ctrl+p":
+		if c.cursor > 0 {
+			c.cursor--
+		}
+	case "down", "ctrl+n":
+		if c.cursor < len(c.bookmarks)-1 {
+			c.cursor++
+		}
+	case " ":
+		if c.cursor < len(c.bookmarks) {
+			name := c.bookmarks[c.cursor].Name
+			c.marked[name] = !c.marked[name]
+		}
+	case "a":
+		// Mark all, or clear all when everything is already marked
+		all := len(c.Marked()) < len(c.bookmarks)
+		for _, b := range c.bookmarks {
+			c.marked[b.Name] = all
+		}
+	}
+	c.ensureCursorVisible()
+	return c, nil
+}
+
+// Marked returns the marked bookmark names, in list order
+func (c *BookmarkCleanupOverlay) Marked() []string {
+	var names []string
+	for _, b := range c.bookmarks {
+		if c.marked[b.Name] {
+			names = append(names, b.Name)
+		}
+	}
+	return names
+}
+
+// SetPreviewing switches between the list and the dry-run preview
+func (c *BookmarkCleanupOverlay) SetPreviewing(previewing bool) {
+	c.previewing = previewing
+}
+
+// Previewing returns true while the dry-run preview is shown
+func (c *BookmarkCleanupOverlay) Previewing() bool {
+	return c.previewing
+}
+
+// listRows returns how many bookmarks fit in the window
+func (c *BookmarkCleanupOverlay) listRows() int {
+	return max(c.windowHeight()-6, 1) // Borders, blank lines and the hint line
+}
+
+func (c *BookmarkCleanupOverlay) ensureCursorVisible() {
+	rows := c.listRows()
+	if c.cursor < c.offset {
+		c.offset = c.cursor
+	} else if c.cursor >= c.offset+rows {
+		c.offset = c.cursor - rows + 1
+	}
+}
+
+func (c *BookmarkCleanupOverlay) View() string {
+	if !c.ready {
+		return c.renderFrame("Initializing...")
+	}
+	if c.previewing {
+		return c.renderFrame(c.renderPreview())
+	}
+	return c.renderFrame(c.renderList())
+}
+
+func (c *BookmarkCleanupOverlay) renderList() string {
+	lines := []string{""}
+
+	end := min(c.offset+c.listRows(), len(c.bookmarks))
+	for i := c.offset; i < end; i++ {
+		b := c.bookmarks[i]
+		box := "[ ]"
+		if c.marked[b.Name] {
+			box = "[x]"
+		}
+		line := box + " " + b.Name
+		if i == c.cursor {
+			line = theme.SelectedItemStyle.Render(line)
+		} else {
+			line = theme.NormalItemStyle.Render(line)
+		}
+		lines = append(lines, "  "+line+" "+theme.DimmedStyle.Render(b.Reason))
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, "  "+theme.HelpDescStyle.Render("space mark  a all  ↵ preview  esc close"))
+	return strings.Join(lines, "\n")
+}
+
+func (c *BookmarkCleanupOverlay) renderPreview() string {
+	marked := c.Marked()
+	lines := []string{"", fmt.Sprintf("  Dry run: %d bookmarks would be deleted", len(marked)), ""}
+
+	maxNames := max(c.windowHeight()-7, 1) // Borders, header and hint lines
+	for i, name := range marked {
+		if i == maxNames && len(marked) > maxNames {
+			lines = append(lines, theme.DimmedStyle.Render(fmt.Sprintf("    … and %d more", len(marked)-i)))
+			break
+		}
+		lines = append(lines, "    "+theme.DeletedStyle.Render(name))
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, "  "+theme.HelpDescStyle.Render("↵ delete  esc back"))
+	return strings.Join(lines, "\n")
+}
+
+func (c *BookmarkCleanupOverlay) SetSize(width, height int) {
+	c.width = width
+	c.height = height
+	c.ready = true
+	c.ensureCursorVisible()
+}
+
+// windowHeight sizes the window to the list, within the screen
+func (c *BookmarkCleanupOverlay) windowHeight() int {
+	return max(min(len(c.bookmarks)+7, c.height-4), 8)
+}
+
+func (c *BookmarkCleanupOverlay) renderFrame(content string) string {
+	// Calculate centered window dimensions
+	windowWidth := min(60, c.width-4)
+	windowHeight := c.windowHeight()
+
+	// Center the window
+	x := (c.width - windowWidth) / 2
+	y := (c.height - windowHeight) / 2
+
+	// Use lipgloss native border rendering
+	borderStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.ColorYellow).
+		Width(windowWidth - 2).
+		Height(windowHeight - 2)
+
+	// Render content with border
+	bordered := borderStyle.Render(content)
+
+	// Add title to top border
+	lines := strings.Split(bordered, "\n")
+	if len(lines) > 0 {
+		borderColorStyle := lipgloss.NewStyle().Foreground(theme.ColorYellow)
+		styledTitle := theme.FloatingTitleStyle.Render(" Clean Up Bookmarks ")
+
+		titleWidth := lipgloss.Width(styledTitle)
+		remainingWidth := windowWidth - 3 - titleWidth
+		if remainingWidth < 0 {
+			remainingWidth = 0
+		}
+
+		topBorder := borderColorStyle.Render(borders.TopLeft+borders.Horizontal) +
+			styledTitle +
+			borderColorStyle.Render(strings.Repeat(borders.Horizontal, remainingWidth)+borders.TopRight)
+
+		lines[0] = topBorder
+	}
+
+	centeredWindow := strings.Join(lines, "\n")
+
+	// Add vertical padding to center the window
+	paddingTop := strings.Repeat("\n", y)
+	paddingLeft := strings.Repeat(" ", x)
+
+	// Apply horizontal padding to each line
+	windowLines := strings.Split(centeredWindow, "\n")
+	for i := range windowLines {
+		windowLines[i] = paddingLeft + windowLines[i]
+	}
+
+	return paddingTop + strings.Join(windowLines, "\n")
+}
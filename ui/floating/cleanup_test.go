@@ -0,0 +1,51 @@
+package floating
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/jj"
+)
+
+// TestBookmarkCleanupMarking verifies bookmarks start marked and space/a toggle them
+func TestBookmarkCleanupMarking(t *testing.T) {
+	c := NewBookmarkCleanupOverlay([]jj.StaleBookmark{
+		{Name: "old", Reason: jj.StaleMerged},
+		{Name: "gone", Reason: jj.StaleRemoteGone},
+	})
+	c.SetSize(100, 30)
+
+	if got := strings.Join(c.Marked(), ","); got != "old,gone" {
+		t.Errorf("Expected all bookmarks marked initially, got %q", got)
+	}
+
+	c.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	if got := strings.Join(c.Marked(), ","); got != "gone" {
+		t.Errorf("Expected space to unmark the first bookmark, got %q", got)
+	}
+
+	c.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	if len(c.Marked()) != 2 {
+		t.Errorf("Expected a to mark all, got %v", c.Marked())
+	}
+	c.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	if len(c.Marked()) != 0 {
+		t.Errorf("Expected a to clear all when all are marked, got %v", c.Marked())
+	}
+}
+
+// TestBookmarkCleanupPreview verifies the dry run lists the marked bookmarks and ignores keys
+func TestBookmarkCleanupPreview(t *testing.T) {
+	c := NewBookmarkCleanupOverlay([]jj.StaleBookmark{{Name: "old", Reason: jj.StaleMerged}})
+	c.SetSize(100, 30)
+	c.SetPreviewing(true)
+
+	c.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	if len(c.Marked()) != 1 {
+		t.Error("Expected marks to be frozen during the dry run")
+	}
+	if view := c.View(); !strings.Contains(view, "1 bookmarks would be deleted") || !strings.Contains(view, "old") {
+		t.Errorf("Expected dry run to list the bookmark, got:\n%s", view)
+	}
+}
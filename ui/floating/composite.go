@@ -0,0 +1,91 @@
+package floating
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/gerunddev/jjazy/ui/theme"
+)
+
+// resetStyle ends any style left open, so styles don't bleed between the
+// window and the background around it
+const resetStyle = "\x1b[0m"
+
+// Composite draws a floating window's view over background. Windows
+// center themselves with blank lines above and spaces to the left; those
+// give the window's position instead of being drawn, so the background
+// stays visible around it. dim fades the background first.
+func Composite(background, view string, dim bool) string {
+	if dim {
+		background = Dim(background)
+	}
+
+	lines := strings.Split(view, "\n")
+	y := 0
+	for y < len(lines)-1 && lines[y] == "" {
+		y++
+	}
+	lines = lines[y:]
+
+	x := -1
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if x < 0 || indent < x {
+			x = indent
+		}
+	}
+	x = max(x, 0)
+	for i, line := range lines {
+		if len(line) >= x {
+			lines[i] = line[x:]
+		}
+	}
+	return Place(background, strings.Join(lines, "\n"), x, y)
+}
+
+// Place draws window over background with its top-left cell at column x
+// of row y, keeping the background on either side. Rows past the bottom
+// of the background are dropped.
+func Place(background, window string, x, y int) string {
+	bgLines := strings.Split(background, "\n")
+	for i, line := range strings.Split(window, "\n") {
+		if row := y + i; row >= 0 && row < len(bgLines) {
+			bgLines[row] = splice(bgLines[row], line, x)
+		}
+	}
+	return strings.Join(bgLines, "\n")
+}
+
+// splice replaces the cells of line from column x with window, keeping
+// the styles of what's left of line to either side
+func splice(line, window string, x int) string {
+	lineWidth, windowWidth := ansi.StringWidth(line), ansi.StringWidth(window)
+
+	left := ansi.Truncate(line, x, "")
+	if pad := x - ansi.StringWidth(left); pad > 0 {
+		left += strings.Repeat(" ", pad) // Short line, or a wide character cut in two
+	}
+	if lineWidth <= x+windowWidth {
+		return left + resetStyle + window
+	}
+
+	right := ansi.TruncateLeft(line, x+windowWidth, "")
+	if pad := lineWidth - x - windowWidth - ansi.StringWidth(right); pad > 0 {
+		right = strings.Repeat(" ", pad) + right
+	}
+	return left + resetStyle + window + resetStyle + right
+}
+
+// Dim fades a rendered screen, as the background behind a floating window
+func Dim(view string) string {
+	lines := strings.Split(view, "\n")
+	for i, line := range lines {
+		if plain := ansi.Strip(line); plain != "" {
+			lines[i] = theme.DimmedStyle.Render(plain)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
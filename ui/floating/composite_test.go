@@ -0,0 +1,74 @@
+package floating
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// TestPlaceKeepsSurroundings verifies the window covers only its own cells
+func TestPlaceKeepsSurroundings(t *testing.T) {
+	background := "aaaaaaaaaa\nbbbbbbbbbb\ncccccccccc\ndddddddddd"
+	got := ansi.Strip(Place(background, "+--+\n|hi|\n+--+", 3, 1))
+	want := "aaaaaaaaaa\nbbb+--+bbb\nccc|hi|ccc\nddd+--+ddd"
+	if got != want {
+		t.Errorf("Place() = %q, want %q", got, want)
+	}
+}
+
+// TestPlaceKeepsBackgroundStyles verifies the background to the right of
+// the window keeps the style it had
+func TestPlaceKeepsBackgroundStyles(t *testing.T) {
+	background := "\x1b[31mred red red red\x1b[0m"
+	got := Place(background, "XX", 2, 0)
+	if ansi.Strip(got) != "reXXred red red" {
+		t.Fatalf("Expected the window spliced in, got %q", ansi.Strip(got))
+	}
+	right := got[strings.LastIndex(got, "XX")+len("XX"):]
+	if !strings.Contains(right, "\x1b[31m") {
+		t.Errorf("Expected the right side still red, got %q", right)
+	}
+}
+
+// TestPlaceEdges verifies short lines are padded out to the window and
+// rows below the background are dropped
+func TestPlaceEdges(t *testing.T) {
+	got := ansi.Strip(Place("ab\ncd", "XY\nZW\nQQ", 4, 1))
+	if want := "ab\ncd  XY"; got != want {
+		t.Errorf("Place() = %q, want %q", got, want)
+	}
+}
+
+// TestPlaceWideCharacters verifies a wide character cut by the window's
+// edge becomes spaces, keeping the line's width
+func TestPlaceWideCharacters(t *testing.T) {
+	got := Place("日本語日本語", "X", 3, 0)
+	if w := ansi.StringWidth(got); w != 12 {
+		t.Errorf("Expected the line to stay 12 cells wide, got %d: %q", w, got)
+	}
+}
+
+// TestCompositeUsesPadding verifies a centered view is placed by its
+// padding, which isn't drawn
+func TestCompositeUsesPadding(t *testing.T) {
+	background := strings.Repeat("..........\n", 4) + ".........."
+	view := "\n\n   ┌──┐\n   └──┘"
+	got := ansi.Strip(Composite(background, view, false))
+	want := "..........\n..........\n...┌──┐...\n...└──┘...\n.........."
+	if got != want {
+		t.Errorf("Composite() = %q, want %q", got, want)
+	}
+}
+
+// TestCompositeDim verifies dimming restyles the background but keeps its text
+func TestCompositeDim(t *testing.T) {
+	background := "\x1b[31mred\x1b[0m line\nsecond"
+	got := Composite(background, "\nX", true)
+	if strings.Contains(got, "\x1b[31m") {
+		t.Errorf("Expected the background's colors replaced, got %q", got)
+	}
+	if want := "red line\nXecond"; ansi.Strip(got) != want {
+		t.Errorf("Composite() = %q, want %q", ansi.Strip(got), want)
+	}
+}
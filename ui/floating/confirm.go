@@ -9,10 +9,17 @@ import (
 	"github.com/gerunddev/jjazy/ui/theme"
 )
 
-// ConfirmOverlay is a floating Yes/No confirmation dialog
+// ConfirmOverlay is a floating Yes/No confirmation dialog. A danger
+// dialog is drawn in red for destructive actions, and one that requires
+// typing only confirms once a phrase, like a change ID, is typed.
 type ConfirmOverlay struct {
 	title    string
 	message  string
+	yesLabel string
+	noLabel  string
+	danger   bool   // Red border and title, for actions that destroy work
+	phrase   string // Must be typed to confirm; empty for a plain Yes/No
+	typed    string
 	width    int
 	height   int
 	ready    bool
@@ -24,29 +31,67 @@ func NewConfirmOverlay(title, message string) *ConfirmOverlay {
 	return &ConfirmOverlay{
 		title:    title,
 		message:  message,
+		yesLabel: "Yes",
+		noLabel:  "No",
 		selected: 1, // Default to "No" for safety
 	}
 }
 
+// SetLabels replaces the Yes and No button labels
+func (c *ConfirmOverlay) SetLabels(yes, no string) {
+	c.yesLabel, c.noLabel = yes, no
+}
+
+// SetDanger draws the dialog in red and labels the buttons with the
+// destructive verb, e.g. "Abandon", and Cancel, so they say what they do
+func (c *ConfirmOverlay) SetDanger(verb string) {
+	c.danger = true
+	c.yesLabel, c.noLabel = verb, "Cancel"
+}
+
+// RequireTyping makes the dialog confirm only once phrase is typed, for
+// actions too costly for a single keypress. Typed keys go to the phrase,
+// so y and n no longer pick a button.
+func (c *ConfirmOverlay) RequireTyping(phrase string) {
+	c.phrase = phrase
+	c.selected = 0
+}
+
 func (c *ConfirmOverlay) Init() tea.Cmd {
 	return nil
 }
 
 func (c *ConfirmOverlay) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch msg.String() {
-		case "left", "h":
-			c.selected = 0 // Yes
-		case "right", "l":
-			c.selected = 1 // No
-		case "tab":
-			c.selected = (c.selected + 1) % 2
-		case "y", "Y":
-			c.selected = 0 // Yes
-		case "n", "N":
-			c.selected = 1 // No
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return c, nil
+	}
+
+	if c.phrase != "" {
+		switch keyMsg.Type {
+		case tea.KeyRunes, tea.KeySpace:
+			c.typed += string(keyMsg.Runes)
+		case tea.KeyBackspace:
+			if runes := []rune(c.typed); len(runes) > 0 {
+				c.typed = string(runes[:len(runes)-1])
+			}
+		case tea.KeyCtrlU:
+			c.typed = ""
 		}
+		return c, nil
+	}
+
+	switch keyMsg.String() {
+	case "left", "h", "ctrl+b":
+		c.selected = 0 // Yes
+	case "right", "l", "ctrl+f":
+		c.selected = 1 // No
+	case "tab", "shift+tab":
+		c.selected = (c.selected + 1) % 2
+	case "y", "Y":
+		c.selected = 0 // Yes
+	case "n", "N":
+		c.selected = 1 // No
 	}
 	return c, nil
 }
@@ -59,20 +104,36 @@ func (c *ConfirmOverlay) View() string {
 	// Build content
 	var lines []string
 	lines = append(lines, "")
-	lines = append(lines, "  "+c.message)
+	for _, line := range c.messageLines() {
+		lines = append(lines, "  "+line)
+	}
 	lines = append(lines, "")
 
+	if c.phrase != "" {
+		lines = append(lines, "  "+theme.HelpDescStyle.Render("Type ")+theme.SelectedItemStyle.Render(c.phrase)+theme.HelpDescStyle.Render(" to confirm"))
+		lines = append(lines, "  > "+c.typed+"█")
+		lines = append(lines, "")
+	}
+
 	// Build Yes/No buttons
+	yes, no := "[ "+c.yesLabel+" ]", "[ "+c.noLabel+" ]"
 	yesStyle := theme.HelpDescStyle
 	noStyle := theme.HelpDescStyle
-	if c.selected == 0 {
+	switch {
+	case c.Pending():
+		yesStyle = theme.DimmedStyle
+		no = "esc " + c.noLabel
+	case c.selected == 0 && c.danger:
+		yesStyle = theme.SelectedItemStyle.Foreground(theme.ColorRed)
+	case c.selected == 0:
 		yesStyle = theme.SelectedItemStyle
-	}
-	if c.selected == 1 {
+	case c.selected == 1:
 		noStyle = theme.SelectedItemStyle
 	}
 
-	buttons := "        " + yesStyle.Render("[ Yes ]") + "    " + noStyle.Render("[ No ]")
+	// Indent the buttons, less in a narrow dialog so they stay on one line
+	indent := max(min(8, c.windowWidth()-2-lipgloss.Width(yes+"    "+no)), 0)
+	buttons := strings.Repeat(" ", indent) + yesStyle.Render(yes) + "    " + noStyle.Render(no)
 	lines = append(lines, buttons)
 	lines = append(lines, "")
 
@@ -87,15 +148,43 @@ func (c *ConfirmOverlay) SetSize(width, height int) {
 	c.ready = true
 }
 
-// Confirmed returns true if Yes is selected
+// Confirmed returns true if Yes is selected, or the phrase is typed
 func (c *ConfirmOverlay) Confirmed() bool {
+	if c.phrase != "" {
+		return c.typed == c.phrase
+	}
 	return c.selected == 0
 }
 
+// Pending returns true while the phrase a dialog requires isn't typed yet,
+// when Enter should do nothing rather than cancel
+func (c *ConfirmOverlay) Pending() bool {
+	return c.phrase != "" && c.typed != c.phrase
+}
+
+// windowWidth returns the dialog width, within the screen
+func (c *ConfirmOverlay) windowWidth() int {
+	return min(60, c.width-4)
+}
+
+// messageLines wraps the message to the dialog, keeping its own line breaks
+func (c *ConfirmOverlay) messageLines() []string {
+	wrapped := lipgloss.NewStyle().Width(max(c.windowWidth()-6, 1)).Render(c.message)
+	return strings.Split(wrapped, "\n")
+}
+
 func (c *ConfirmOverlay) renderFrame(content string) string {
-	// Calculate centered window dimensions
-	windowWidth := min(60, c.width-4)
-	windowHeight := 8
+	// Calculate centered window dimensions; a one-line message gives the
+	// usual 8 rows and longer explanations grow the window
+	windowWidth := c.windowWidth()
+	windowHeight := max(len(c.messageLines())+7, 8)
+	if c.phrase != "" {
+		windowHeight += 3 // Prompt, input and a blank line
+	}
+	color := theme.ColorYellow
+	if c.danger {
+		color = theme.ColorRed
+	}
 
 	// Center the window
 	x := (c.width - windowWidth) / 2
@@ -104,7 +193,7 @@ func (c *ConfirmOverlay) renderFrame(content string) string {
 	// Use lipgloss native border rendering
 	borderStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(theme.ColorYellow).
+		BorderForeground(color).
 		Width(windowWidth - 2).
 		Height(windowHeight - 2)
 
@@ -114,7 +203,7 @@ func (c *ConfirmOverlay) renderFrame(content string) string {
 	// Add title to top border
 	lines := strings.Split(bordered, "\n")
 	if len(lines) > 0 {
-		borderColorStyle := lipgloss.NewStyle().Foreground(theme.ColorYellow)
+		borderColorStyle := lipgloss.NewStyle().Foreground(color)
 		styledTitle := theme.FloatingTitleStyle.Render(" " + c.title + " ")
 
 		titleWidth := lipgloss.Width(styledTitle)
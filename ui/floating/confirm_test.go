@@ -0,0 +1,80 @@
+package floating
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func typeKeys(c *ConfirmOverlay, text string) {
+	for _, r := range text {
+		c.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+// TestConfirmOverlayKeys verifies the arrow, vi, emacs and y/n keys all
+// move between the buttons
+func TestConfirmOverlayKeys(t *testing.T) {
+	c := NewConfirmOverlay("Abandon", "Abandon?")
+	for _, step := range []struct {
+		key  tea.KeyMsg
+		want bool
+	}{
+		{tea.KeyMsg{Type: tea.KeyLeft}, true},
+		{tea.KeyMsg{Type: tea.KeyRight}, false},
+		{tea.KeyMsg{Type: tea.KeyCtrlB}, true},
+		{tea.KeyMsg{Type: tea.KeyCtrlF}, false},
+		{tea.KeyMsg{Type: tea.KeyShiftTab}, true},
+		{tea.KeyMsg{Type: tea.KeyTab}, false},
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'h'}}, true},
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}}, false},
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}}, true},
+	} {
+		c.Update(step.key)
+		if c.Confirmed() != step.want {
+			t.Errorf("After %q expected confirmed=%v", step.key.String(), step.want)
+		}
+	}
+}
+
+// TestConfirmOverlayDanger verifies the confirm button is labeled with the verb
+func TestConfirmOverlayDanger(t *testing.T) {
+	c := NewConfirmOverlay("Abandon Range", "Abandon 3 changes?")
+	c.SetDanger("Abandon")
+	c.SetLabels("Abandon", "Keep")
+	c.SetSize(120, 30)
+	view := c.View()
+	if !strings.Contains(view, "[ Abandon ]") || !strings.Contains(view, "[ Keep ]") {
+		t.Errorf("Expected the custom labels, got %q", view)
+	}
+}
+
+// TestConfirmOverlayRequireTyping verifies the dialog confirms only once
+// the phrase is typed, with y and n typed rather than picking a button
+func TestConfirmOverlayRequireTyping(t *testing.T) {
+	c := NewConfirmOverlay("Abandon Change", "Abandon kmnopqrs?")
+	c.RequireTyping("kmny")
+	if c.Confirmed() || !c.Pending() {
+		t.Fatal("Expected nothing confirmed before typing")
+	}
+
+	typeKeys(c, "kmnx")
+	if c.Confirmed() {
+		t.Fatal("Expected a wrong phrase not to confirm")
+	}
+	c.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	typeKeys(c, "y")
+	if !c.Confirmed() || c.Pending() {
+		t.Errorf("Expected the typed phrase to confirm, typed %q", c.typed)
+	}
+
+	c.SetSize(120, 30)
+	if view := c.View(); !strings.Contains(view, "> kmny") {
+		t.Errorf("Expected the typed text shown, got %q", view)
+	}
+	c.Update(tea.KeyMsg{Type: tea.KeyCtrlU})
+	if c.Confirmed() {
+		t.Error("Expected ctrl+u to clear what was typed")
+	}
+}
@@ -0,0 +1,149 @@
+package floating
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/gerunddev/jjazy/ui/borders"
+	"github.com/gerunddev/jjazy/ui/theme"
+)
+
+// DebugLogOverlay shows the newest lines of the operation log, scrolled to
+// the end. Lines are cut to the window rather than wrapped.
+type DebugLogOverlay struct {
+	source string   // Log file path, or "memory"
+	lines  []string // Oldest first
+	offset int      // First line shown
+	width  int
+	height int
+	ready  bool
+}
+
+// NewDebugLogOverlay creates the overlay for lines read from source
+func NewDebugLogOverlay(source string, lines []string) *DebugLogOverlay {
+	return &DebugLogOverlay{source: source, lines: lines}
+}
+
+func (d *DebugLogOverlay) Init() tea.Cmd {
+	return nil
+}
+
+func (d *DebugLogOverlay) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return d, nil
+	}
+
+	switch keyMsg.String() {
+	case "up", "ctrl+p":
+		d.scroll(-1)
+	case "down", "ctrl+n":
+		d.scroll(1)
+	case "pgup", "alt+v":
+		d.scroll(-d.textRows())
+	case "pgdown", "ctrl+v", " ":
+		d.scroll(d.textRows())
+	case "home", "alt+<":
+		d.offset = 0
+	case "end", "alt+>":
+		d.scroll(len(d.lines))
+	}
+	return d, nil
+}
+
+// Text returns the lines as read, for copying into a bug report
+func (d *DebugLogOverlay) Text() string {
+	return strings.Join(d.lines, "\n")
+}
+
+// scroll moves the view by delta lines, within the log
+func (d *DebugLogOverlay) scroll(delta int) {
+	d.offset = max(min(d.offset+delta, len(d.lines)-d.textRows()), 0)
+}
+
+// textRows returns how many log lines fit in the window
+func (d *DebugLogOverlay) textRows() int {
+	return max(d.windowHeight()-7, 1) // Borders, source line, blank lines and the hint line
+}
+
+func (d *DebugLogOverlay) View() string {
+	if !d.ready {
+		return d.renderFrame("Initializing...")
+	}
+
+	lines := []string{"", "  " + theme.DimmedStyle.Render(d.sourceLabel()), ""}
+	if len(d.lines) == 0 {
+		lines = append(lines, "  "+theme.DimmedStyle.Render("Nothing logged yet"))
+	}
+	end := min(d.offset+d.textRows(), len(d.lines))
+	for _, line := range d.lines[d.offset:end] {
+		lines = append(lines, "  "+theme.NormalItemStyle.Render(ansi.Truncate(line, max(d.windowWidth()-6, 10), "…")))
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, "  "+theme.HelpDescStyle.Render("y copy  r reload  esc close"))
+	return d.renderFrame(strings.Join(lines, "\n"))
+}
+
+// sourceLabel says where the lines were read from
+func (d *DebugLogOverlay) sourceLabel() string {
+	if d.source == "memory" {
+		return "This session (set JJAZY_LOG_FILE to keep a log)"
+	}
+	return d.source
+}
+
+func (d *DebugLogOverlay) SetSize(width, height int) {
+	d.width = width
+	d.height = height
+	d.ready = true
+	// Open on the newest lines, as tail does
+	d.scroll(len(d.lines))
+}
+
+func (d *DebugLogOverlay) windowWidth() int {
+	return max(d.width-8, 20)
+}
+
+func (d *DebugLogOverlay) windowHeight() int {
+	return max(d.height-4, 8)
+}
+
+func (d *DebugLogOverlay) renderFrame(content string) string {
+	// Calculate centered window dimensions
+	windowWidth := d.windowWidth()
+	windowHeight := d.windowHeight()
+
+	// Center the window
+	x := (d.width - windowWidth) / 2
+	y := (d.height - windowHeight) / 2
+
+	borderStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.ColorYellow).
+		Width(windowWidth - 2).
+		Height(windowHeight - 2)
+
+	bordered := borderStyle.Render(content)
+
+	// Add title to top border
+	lines := strings.Split(bordered, "\n")
+	if len(lines) > 0 {
+		borderColorStyle := lipgloss.NewStyle().Foreground(theme.ColorYellow)
+		styledTitle := theme.FloatingTitleStyle.Render(" Debug Log ")
+
+		remainingWidth := max(windowWidth-3-lipgloss.Width(styledTitle), 0)
+		lines[0] = borderColorStyle.Render(borders.TopLeft+borders.Horizontal) +
+			styledTitle +
+			borderColorStyle.Render(strings.Repeat(borders.Horizontal, remainingWidth)+borders.TopRight)
+	}
+
+	// Pad to center the window
+	paddingLeft := strings.Repeat(" ", x)
+	for i := range lines {
+		lines[i] = paddingLeft + lines[i]
+	}
+	return strings.Repeat("\n", y) + strings.Join(lines, "\n")
+}
@@ -0,0 +1,42 @@
+package floating
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestDebugLogOpensAtEnd verifies the log opens on its newest lines, like tail
+func TestDebugLogOpensAtEnd(t *testing.T) {
+	var lines []string
+	for i := 1; i <= 100; i++ {
+		lines = append(lines, fmt.Sprintf("entry %03d", i))
+	}
+	d := NewDebugLogOverlay("/tmp/jjazy.log", lines)
+	d.SetSize(100, 30)
+
+	view := d.View()
+	if !strings.Contains(view, "entry 100") || strings.Contains(view, "entry 001") {
+		t.Errorf("Expected the newest entries, got %q", view)
+	}
+	if !strings.Contains(view, "/tmp/jjazy.log") {
+		t.Errorf("Expected the log file path, got %q", view)
+	}
+
+	d.Update(tea.KeyMsg{Type: tea.KeyHome})
+	if view := d.View(); !strings.Contains(view, "entry 001") {
+		t.Error("Expected home to show the oldest entry")
+	}
+}
+
+// TestDebugLogMemory verifies the in-memory log says how to keep a log file
+func TestDebugLogMemory(t *testing.T) {
+	d := NewDebugLogOverlay("memory", nil)
+	d.SetSize(100, 30)
+	view := d.View()
+	if !strings.Contains(view, "JJAZY_LOG_FILE") || !strings.Contains(view, "Nothing logged yet") {
+		t.Errorf("Expected the memory source hint and empty message, got %q", view)
+	}
+}
@@ -0,0 +1,180 @@
+package floating
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/borders"
+	"github.com/gerunddev/jjazy/ui/theme"
+)
+
+// detailsLabelWidth fits the longest field label
+const detailsLabelWidth = len("Committer") + 2
+
+// DetailsOverlay shows everything about a revision that its log row cuts
+// short, in a scrollable window. The description wraps to the window.
+type DetailsOverlay struct {
+	details *jj.RevisionDetails
+	offset  int // First line shown
+	width   int
+	height  int
+	ready   bool
+}
+
+// NewDetailsOverlay creates the overlay for details from jj.GetRevisionDetails
+func NewDetailsOverlay(details *jj.RevisionDetails) *DetailsOverlay {
+	return &DetailsOverlay{details: details}
+}
+
+func (d *DetailsOverlay) Init() tea.Cmd {
+	return nil
+}
+
+func (d *DetailsOverlay) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return d, nil
+	}
+
+	switch keyMsg.String() {
+	case "up", "ctrl+p":
+		d.scroll(-1)
+	case "down", "ctrl+n":
+		d.scroll(1)
+	case "pgup", "alt+v":
+		d.scroll(-d.textRows())
+	case "pgdown", "ctrl+v", " ":
+		d.scroll(d.textRows())
+	case "home", "alt+<":
+		d.offset = 0
+	case "end", "alt+>":
+		d.scroll(len(d.lines()))
+	}
+	return d, nil
+}
+
+// lines returns the details as lines: a labelled field per line, then the
+// description wrapped to the window
+func (d *DetailsOverlay) lines() []string {
+	field := func(label, value string) string {
+		return theme.HelpDescStyle.Render(label+strings.Repeat(" ", detailsLabelWidth-len(label))) + value
+	}
+	more := strings.Repeat(" ", detailsLabelWidth)
+	none := theme.DimmedStyle.Render("(none)")
+
+	lines := []string{
+		field("Change ID", theme.ChangeIDStyle.Render(d.details.ChangeID)),
+		field("Commit ID", d.details.CommitID),
+	}
+	if len(d.details.Parents) == 0 {
+		lines = append(lines, field("Parents", none))
+	}
+	for i, parent := range d.details.Parents {
+		line := parent.CommitID + " " + theme.ChangeIDStyle.Render(parent.ChangeID)
+		if i == 0 {
+			lines = append(lines, field("Parents", line))
+		} else {
+			lines = append(lines, more+line)
+		}
+	}
+	lines = append(lines,
+		field("Author", d.details.Author.String()),
+		more+theme.TimestampStyle.Render(d.details.Author.Timestamp),
+		field("Committer", d.details.Committer.String()),
+		more+theme.TimestampStyle.Render(d.details.Committer.Timestamp),
+	)
+	if len(d.details.Bookmarks) == 0 {
+		lines = append(lines, field("Bookmarks", none))
+	} else {
+		lines = append(lines, field("Bookmarks", theme.CurrentBookmarkStyle.Render(strings.Join(d.details.Bookmarks, " "))))
+	}
+
+	lines = append(lines, "")
+	if d.details.Description == "" {
+		return append(lines, theme.DimmedStyle.Render("(no description)"))
+	}
+	wrapped := ansi.Wrap(d.details.Description, max(d.windowWidth()-6, 10), "")
+	return append(lines, strings.Split(wrapped, "\n")...)
+}
+
+// scroll moves the view by delta lines, within the details
+func (d *DetailsOverlay) scroll(delta int) {
+	d.offset = max(min(d.offset+delta, len(d.lines())-d.textRows()), 0)
+}
+
+// textRows returns how many lines of details fit in the window
+func (d *DetailsOverlay) textRows() int {
+	return max(d.windowHeight()-6, 1) // Borders, blank lines and the hint line
+}
+
+func (d *DetailsOverlay) View() string {
+	if !d.ready {
+		return d.renderFrame("Initializing...")
+	}
+
+	lines := d.lines()
+	content := []string{""}
+	end := min(d.offset+d.textRows(), len(lines))
+	for _, line := range lines[d.offset:end] {
+		content = append(content, "  "+ansi.Truncate(line, max(d.windowWidth()-6, 10), "…"))
+	}
+	content = append(content, "")
+	content = append(content, "  "+theme.HelpDescStyle.Render("↑/↓ scroll  esc close"))
+	return d.renderFrame(strings.Join(content, "\n"))
+}
+
+func (d *DetailsOverlay) SetSize(width, height int) {
+	d.width = width
+	d.height = height
+	d.ready = true
+	d.scroll(0)
+}
+
+func (d *DetailsOverlay) windowWidth() int {
+	return min(100, d.width-4)
+}
+
+// windowHeight sizes the window to the details, within the screen
+func (d *DetailsOverlay) windowHeight() int {
+	return max(min(len(d.lines())+6, d.height-4), 8)
+}
+
+func (d *DetailsOverlay) renderFrame(content string) string {
+	// Calculate centered window dimensions
+	windowWidth := d.windowWidth()
+	windowHeight := d.windowHeight()
+
+	// Center the window
+	x := (d.width - windowWidth) / 2
+	y := (d.height - windowHeight) / 2
+
+	borderStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.ColorYellow).
+		Width(windowWidth - 2).
+		Height(windowHeight - 2)
+
+	bordered := borderStyle.Render(content)
+
+	// Add title to top border
+	lines := strings.Split(bordered, "\n")
+	if len(lines) > 0 {
+		borderColorStyle := lipgloss.NewStyle().Foreground(theme.ColorYellow)
+		styledTitle := theme.FloatingTitleStyle.Render(" Revision Details ")
+
+		remainingWidth := max(windowWidth-3-lipgloss.Width(styledTitle), 0)
+		lines[0] = borderColorStyle.Render(borders.TopLeft+borders.Horizontal) +
+			styledTitle +
+			borderColorStyle.Render(strings.Repeat(borders.Horizontal, remainingWidth)+borders.TopRight)
+	}
+
+	// Pad to center the window
+	paddingLeft := strings.Repeat(" ", x)
+	for i := range lines {
+		lines[i] = paddingLeft + lines[i]
+	}
+	return strings.Repeat("\n", y) + strings.Join(lines, "\n")
+}
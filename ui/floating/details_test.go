@@ -0,0 +1,65 @@
+package floating
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/gerunddev/jjazy/jj"
+)
+
+// TestDetailsOverlay verifies every field shows in full and a long
+// description scrolls
+func TestDetailsOverlay(t *testing.T) {
+	details := &jj.RevisionDetails{
+		ChangeID:    "kmnopqrstuvwxyzkmnopqrstuvwxyzkm",
+		CommitID:    "0123456789abcdef0123456789abcdef01234567",
+		Parents:     []jj.Parent{{CommitID: "1111", ChangeID: "zz"}, {CommitID: "2222", ChangeID: "yx"}},
+		Author:      jj.Author{Name: "Ada Lovelace", Email: "ada@example.com", Timestamp: "2024-01-02 03:04:05 +00:00"},
+		Committer:   jj.Author{Name: "Charles Babbage", Email: "charles@example.com", Timestamp: "2024-01-03 04:05:06 +01:00"},
+		Bookmarks:   []string{"main", "main@origin"},
+		Description: "Add the engine\n\n" + strings.Repeat("More notes.\n", 30) + "The end.",
+	}
+	d := NewDetailsOverlay(details)
+	d.SetSize(120, 40)
+
+	view := ansi.Strip(d.View())
+	for _, want := range []string{
+		"Change ID  kmnopqrstuvwxyzkmnopqrstuvwxyzkm",
+		"Commit ID  0123456789abcdef0123456789abcdef01234567",
+		"Parents    1111 zz",
+		"           2222 yx",
+		"Author     Ada Lovelace <ada@example.com>",
+		"Committer  Charles Babbage <charles@example.com>",
+		"           2024-01-03 04:05:06 +01:00",
+		"Bookmarks  main main@origin",
+		"Add the engine",
+	} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in the details, got:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "The end.") {
+		t.Error("Expected the end of the description below the window")
+	}
+
+	d.Update(tea.KeyMsg{Type: tea.KeyEnd})
+	if view := ansi.Strip(d.View()); !strings.Contains(view, "The end.") {
+		t.Errorf("Expected end to scroll to the last line, got:\n%s", view)
+	}
+}
+
+// TestDetailsOverlayRoot verifies a revision without parents, bookmarks or
+// description says so
+func TestDetailsOverlayRoot(t *testing.T) {
+	d := NewDetailsOverlay(&jj.RevisionDetails{ChangeID: "zzzzzzzz", CommitID: "00000000"})
+	d.SetSize(120, 40)
+
+	view := ansi.Strip(d.View())
+	for _, want := range []string{"Parents    (none)", "Bookmarks  (none)", "(no description)"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in the details, got:\n%s", want, view)
+		}
+	}
+}
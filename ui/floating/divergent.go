@@ -0,0 +1,188 @@
+package floating
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/borders"
+	"github.com/gerunddev/jjazy/ui/theme"
+)
+
+// DivergentOverlay warns about divergent changes, listing the visible
+// commits of each. Resolving keeps the selected commit and abandons the
+// other commits of its change; running that is left to the caller.
+type DivergentOverlay struct {
+	commits []jj.DivergentCommit // Grouped by change
+	cursor  int
+	offset  int // First row shown in the list
+	width   int
+	height  int
+	ready   bool
+}
+
+// NewDivergentOverlay creates the overlay for commits from jj.DivergentCommits
+func NewDivergentOverlay(commits []jj.DivergentCommit) *DivergentOverlay {
+	return &DivergentOverlay{commits: commits}
+}
+
+func (d *DivergentOverlay) Init() tea.Cmd {
+	return nil
+}
+
+func (d *DivergentOverlay) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return d, nil
+	}
+
+	switch keyMsg.String() {
+	case "up", "ctrl+p":
+		if d.cursor > 0 {
+			d.cursor--
+		}
+	case "down", "ctrl+n":
+		if d.cursor < len(d.commits)-1 {
+			d.cursor++
+		}
+	}
+	d.ensureCursorVisible()
+	return d, nil
+}
+
+// Selected returns the commit under the cursor, or nil if there are none
+func (d *DivergentOverlay) Selected() *jj.DivergentCommit {
+	if d.cursor < len(d.commits) {
+		return &d.commits[d.cursor]
+	}
+	return nil
+}
+
+// Resolution returns the commit IDs to abandon to keep the selected
+// commit: the other commits of the same change
+func (d *DivergentOverlay) Resolution() []string {
+	keep := d.Selected()
+	if keep == nil {
+		return nil
+	}
+	var abandon []string
+	for _, c := range d.commits {
+		if c.ChangeID == keep.ChangeID && c.CommitID != keep.CommitID {
+			abandon = append(abandon, c.CommitID)
+		}
+	}
+	return abandon
+}
+
+// rows returns the list as lines: a header per change, then its commits.
+// commitRow maps each commit index to its line.
+func (d *DivergentOverlay) rows() (lines []string, commitRow []int) {
+	for i, c := range d.commits {
+		if i == 0 || c.ChangeID != d.commits[i-1].ChangeID {
+			lines = append(lines, "  "+theme.DivergentStyle.Render(c.ChangeID))
+		}
+		desc := c.Description
+		if desc == "" {
+			desc = "(no description)"
+		}
+		line := c.CommitID + " " + desc
+		if i == d.cursor {
+			line = theme.SelectedItemStyle.Render(line)
+		} else {
+			line = theme.NormalItemStyle.Render(line)
+		}
+		commitRow = append(commitRow, len(lines))
+		lines = append(lines, "    "+line+" "+theme.TimestampStyle.Render(c.Timestamp))
+	}
+	return lines, commitRow
+}
+
+// listRows returns how many lines of the list fit in the window
+func (d *DivergentOverlay) listRows() int {
+	return max(d.windowHeight()-6, 1) // Borders, blank lines and the hint line
+}
+
+func (d *DivergentOverlay) ensureCursorVisible() {
+	lines, commitRow := d.rows()
+	if d.cursor >= len(commitRow) {
+		return
+	}
+	row := commitRow[d.cursor]
+	// Keep the change header in view with its first commit
+	if d.cursor == 0 || d.commits[d.cursor-1].ChangeID != d.commits[d.cursor].ChangeID {
+		row--
+	}
+	rows := d.listRows()
+	if row < d.offset {
+		d.offset = row
+	} else if commitRow[d.cursor] >= d.offset+rows {
+		d.offset = commitRow[d.cursor] - rows + 1
+	}
+	d.offset = max(min(d.offset, len(lines)-rows), 0)
+}
+
+func (d *DivergentOverlay) View() string {
+	if !d.ready {
+		return d.renderFrame("Initializing...")
+	}
+
+	lines, _ := d.rows()
+	end := min(d.offset+d.listRows(), len(lines))
+
+	content := []string{""}
+	content = append(content, lines[d.offset:end]...)
+	content = append(content, "")
+	content = append(content, "  "+theme.HelpDescStyle.Render("↵ keep this commit, abandon the others  esc close"))
+	return d.renderFrame(strings.Join(content, "\n"))
+}
+
+func (d *DivergentOverlay) SetSize(width, height int) {
+	d.width = width
+	d.height = height
+	d.ready = true
+	d.ensureCursorVisible()
+}
+
+// windowHeight sizes the window to the list, within the screen
+func (d *DivergentOverlay) windowHeight() int {
+	lines, _ := d.rows()
+	return max(min(len(lines)+6, d.height-4), 8)
+}
+
+func (d *DivergentOverlay) renderFrame(content string) string {
+	// Calculate centered window dimensions
+	windowWidth := min(70, d.width-4)
+	windowHeight := d.windowHeight()
+
+	// Center the window
+	x := (d.width - windowWidth) / 2
+	y := (d.height - windowHeight) / 2
+
+	borderStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.ColorYellow).
+		Width(windowWidth - 2).
+		Height(windowHeight - 2)
+
+	bordered := borderStyle.Render(content)
+
+	// Add title to top border
+	lines := strings.Split(bordered, "\n")
+	if len(lines) > 0 {
+		borderColorStyle := lipgloss.NewStyle().Foreground(theme.ColorYellow)
+		styledTitle := theme.FloatingTitleStyle.Render(" ⚠ Divergent Changes ")
+
+		remainingWidth := max(windowWidth-3-lipgloss.Width(styledTitle), 0)
+		lines[0] = borderColorStyle.Render(borders.TopLeft+borders.Horizontal) +
+			styledTitle +
+			borderColorStyle.Render(strings.Repeat(borders.Horizontal, remainingWidth)+borders.TopRight)
+	}
+
+	// Pad to center the window
+	paddingLeft := strings.Repeat(" ", x)
+	for i := range lines {
+		lines[i] = paddingLeft + lines[i]
+	}
+	return strings.Repeat("\n", y) + strings.Join(lines, "\n")
+}
@@ -0,0 +1,39 @@
+package floating
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/jj"
+)
+
+// TestDivergentResolution verifies resolving keeps the selected commit and
+// abandons only the other commits of the same change
+func TestDivergentResolution(t *testing.T) {
+	d := NewDivergentOverlay([]jj.DivergentCommit{
+		{ChangeID: "aaaaaaaa", CommitID: "11111111", Description: "first"},
+		{ChangeID: "aaaaaaaa", CommitID: "22222222", Description: "second"},
+		{ChangeID: "bbbbbbbb", CommitID: "33333333"},
+		{ChangeID: "bbbbbbbb", CommitID: "44444444"},
+	})
+	d.SetSize(100, 30)
+
+	if got := strings.Join(d.Resolution(), ","); got != "22222222" {
+		t.Errorf("Expected keeping the first commit to abandon 22222222, got %q", got)
+	}
+
+	d.Update(tea.KeyMsg{Type: tea.KeyDown})
+	d.Update(tea.KeyMsg{Type: tea.KeyDown})
+	d.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if got := strings.Join(d.Resolution(), ","); got != "33333333" {
+		t.Errorf("Expected keeping 44444444 to abandon 33333333, got %q", got)
+	}
+
+	view := d.View()
+	for _, want := range []string{"Divergent Changes", "aaaaaaaa", "bbbbbbbb", "(no description)"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected view to contain %q", want)
+		}
+	}
+}
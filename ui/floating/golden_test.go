@@ -0,0 +1,51 @@
+package floating
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/golden"
+)
+
+func TestGoldenConfirmOverlay(t *testing.T) {
+	golden.Freeze(t)
+	c := NewConfirmOverlay("Abandon", "Abandon change kmnopqrs? Its descendants are rebased onto its parent.")
+	c.SetSize(80, 20)
+	golden.Assert(t, "confirm", c.View(), 80, 20)
+}
+
+func TestGoldenConfirmOverlayNarrow(t *testing.T) {
+	golden.Freeze(t)
+	c := NewConfirmOverlay("Abandon", "Abandon change kmnopqrs? Its descendants are rebased onto its parent.")
+	c.SetSize(30, 12)
+	golden.Assert(t, "confirm_narrow", c.View(), 30, 12)
+}
+
+func TestGoldenMetricsOverlay(t *testing.T) {
+	golden.Freeze(t)
+	m := NewMetricsOverlay([]jj.OpLatency{
+		{Op: "get_log", Count: 3, Mean: 12500 * time.Microsecond, Max: 2 * time.Second, Buckets: []int{1, 0, 1, 0, 1}},
+		{Op: "list_branches", Count: 1, Mean: 800 * time.Microsecond, Max: 800 * time.Microsecond, Buckets: []int{1, 0, 0, 0, 0}},
+	}, []string{"<1ms", "<10ms", "<100ms", "<1s", "≥1s"})
+	m.SetSize(100, 20)
+	golden.Assert(t, "metrics", m.View(), 100, 20)
+}
+
+func TestGoldenTextInputOverlay(t *testing.T) {
+	golden.Freeze(t)
+	ti := NewTextInputOverlay("Describe", "Enter a description", "Fix the border overflow")
+	ti.SetSize(80, 20)
+	golden.Assert(t, "textinput", ti.View(), 80, 20)
+}
+
+func TestGoldenConfirmOverlayDanger(t *testing.T) {
+	golden.Freeze(t)
+	c := NewConfirmOverlay("Abandon Change", "Abandon kmnopqrs? Its 12 descendants are rebased onto its parent.")
+	c.SetDanger("Abandon")
+	c.RequireTyping("kmnopqrs")
+	c.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("kmn")})
+	c.SetSize(80, 20)
+	golden.Assert(t, "confirm_danger", c.View(), 80, 20)
+}
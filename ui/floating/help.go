@@ -1,9 +1,10 @@
 package floating
 
 import (
+	"strconv"
 	"strings"
 
-	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -11,21 +12,38 @@ import (
 	"github.com/gerunddev/jjazy/ui/theme"
 )
 
+// HelpEntry is a single key and what it does
+type HelpEntry struct {
+	Key  string
+	Desc string
+}
+
+// HelpSection is a titled group of entries on the help screen
+type HelpSection struct {
+	Title   string
+	Entries []HelpEntry
+}
+
 // HelpOverlay is a floating window showing help information
 type HelpOverlay struct {
-	viewport viewport.Model
-	help     help.Model
-	keymap   help.KeyMap
-	width    int
-	height   int
-	ready    bool
+	viewport  viewport.Model
+	sections  []HelpSection
+	filter    textinput.Model
+	filtering bool // True while typing into the filter
+	width     int
+	height    int
+	ready     bool
 }
 
-// NewHelpOverlay creates a new floating help window
-func NewHelpOverlay(keymap help.KeyMap) *HelpOverlay {
+// NewHelpOverlay creates a new floating help window showing the given sections
+func NewHelpOverlay(sections []HelpSection) *HelpOverlay {
+	filter := textinput.New()
+	filter.Prompt = "/"
+	filter.Placeholder = "filter by key or description"
+
 	return &HelpOverlay{
-		help:   help.New(),
-		keymap: keymap,
+		sections: sections,
+		filter:   filter,
 	}
 }
 
@@ -47,7 +65,27 @@ func (h *HelpOverlay) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case tea.KeyMsg:
+		if h.filtering {
+			switch msg.String() {
+			case "esc", "escape":
+				// Drop the filter entirely
+				h.filter.SetValue("")
+				h.stopFiltering()
+			case "enter":
+				// Keep the filter, go back to scrolling
+				h.stopFiltering()
+			default:
+				h.filter, cmd = h.filter.Update(msg)
+				h.refresh()
+			}
+			return h, cmd
+		}
+
 		switch msg.String() {
+		case "/":
+			h.filtering = true
+			h.filter.Focus()
+			return h, textinput.Blink
 		case "up", "k":
 			h.viewport.LineUp(1)
 		case "down", "j":
@@ -67,21 +105,46 @@ func (h *HelpOverlay) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return h, cmd
 }
 
+// Filtering reports whether keys are going to the filter input, so the
+// caller shouldn't treat them as close/quit
+func (h *HelpOverlay) Filtering() bool {
+	return h.filtering
+}
+
+func (h *HelpOverlay) stopFiltering() {
+	h.filtering = false
+	h.filter.Blur()
+	h.refresh()
+}
+
+// refresh re-renders the help content after the filter changes
+func (h *HelpOverlay) refresh() {
+	if h.ready {
+		h.viewport.SetContent(h.renderHelp())
+		h.viewport.GotoTop()
+	}
+}
+
 func (h *HelpOverlay) View() string {
 	if !h.ready {
 		return h.renderFrame("Initializing...")
 	}
 
-	return h.renderFrame(h.viewport.View())
+	filterLine := h.filter.View()
+	if !h.filtering && h.filter.Value() == "" {
+		filterLine = theme.HelpDescStyle.Render("/ filter")
+	}
+	return h.renderFrame(filterLine + "\n" + h.viewport.View())
 }
 
 func (h *HelpOverlay) SetSize(width, height int) {
 	h.width = width
 	h.height = height
 
-	// With titled borders: just top and bottom borders (title is in top border)
+	// With titled borders: just top and bottom borders (title is in top border),
+	// plus one line for the filter input
 	contentWidth := width - 2
-	contentHeight := height - 2
+	contentHeight := height - 3
 
 	if !h.ready {
 		h.viewport = viewport.New(contentWidth, contentHeight)
@@ -106,62 +169,83 @@ func (h *HelpOverlay) renderHelp() string {
 
 	// Description
 	descStyle := lipgloss.NewStyle().
-		Foreground(theme.ColorWhite).
-		MarginBottom(2)
+		Foreground(theme.ColorWhite)
 	sections = append(sections, descStyle.Render("A terminal user interface for Jujutsu version control"))
 
-	// Render help sections using bubbles help
-	h.help.Width = h.viewport.Width
-	helpView := h.help.View(h.keymap)
-	sections = append(sections, helpView)
-
-	// Additional help text
 	sectionTitleStyle := lipgloss.NewStyle().
 		Bold(true).
 		Foreground(theme.ColorYellow).
-		MarginTop(2).
-		MarginBottom(1)
+		MarginTop(1)
+
+	visible := filterSections(h.sections, h.filter.Value())
+	if len(visible) == 0 {
+		sections = append(sections, "", theme.HelpDescStyle.Render("No bindings match "+strconv.Quote(h.filter.Value())))
+	}
+
+	// Align descriptions on the widest key across all sections
+	keyWidth := 0
+	for _, section := range visible {
+		for _, entry := range section.Entries {
+			keyWidth = max(keyWidth, lipgloss.Width(entry.Key))
+		}
+	}
+	keyStyle := theme.HelpKeyStyle.Width(keyWidth + 2)
+
+	for _, section := range visible {
+		sections = append(sections, sectionTitleStyle.Render(section.Title))
+		for _, entry := range section.Entries {
+			sections = append(sections, "  "+keyStyle.Render(entry.Key)+descStyle.Render(entry.Desc))
+		}
+	}
 
-	sections = append(sections, sectionTitleStyle.Render("Navigation"))
-	navHelp := lipgloss.NewStyle().
-		Foreground(theme.ColorWhite).
-		Render("• Use tab/shift+tab to navigate between panels\n" +
-		"• Use number keys (0-4) to jump directly to a panel:\n" +
-		"  0: Diff viewer  1: Status  2: Files  3: Bookmarks  4: Operations\n" +
-		"• Use arrow keys or j/k to move within lists\n" +
-		"• Press enter to select an item")
-	sections = append(sections, navHelp)
-
-	sections = append(sections, sectionTitleStyle.Render("Panels"))
-	panelsHelp := lipgloss.NewStyle().
-		Foreground(theme.ColorWhite).
-		Render("• Status: Shows the current working copy revision\n" +
-		"• Files: Lists changed files in the working copy\n" +
-		"• Bookmarks: Shows local and remote bookmarks\n" +
-		"• Operations: Shows recent Jujutsu operations\n" +
-		"• Diff: Displays file diffs and revision changes")
-	sections = append(sections, panelsHelp)
-
-	sections = append(sections, sectionTitleStyle.Render("Special Views"))
-	specialHelp := lipgloss.NewStyle().
-		Foreground(theme.ColorWhite).
-		Render("• Log (L): View full revision history\n" +
-		"• Help (?): Show this help screen\n" +
-		"• Press esc or ? to close overlays")
-	sections = append(sections, specialHelp)
-
-	sections = append(sections, sectionTitleStyle.Render("File Operations"))
-	fileOpsHelp := lipgloss.NewStyle().
-		Foreground(theme.ColorWhite).
-		Render("When viewing files in working copy (@):\n" +
-		"• del/backspace: Discard file changes (uses jj restore)\n" +
-		"• s: Squash file changes to parent commit\n" +
-		"\nNote: All operations are undoable with 'jj undo'")
-	sections = append(sections, fileOpsHelp)
+	noteStyle := lipgloss.NewStyle().
+		Foreground(theme.ColorDimWhite).
+		MarginTop(1)
+	sections = append(sections, noteStyle.Render("All operations are undoable with 'jj undo'"))
 
 	return strings.Join(sections, "\n")
 }
 
+// filterSections keeps the entries whose key or description fuzzy-matches the
+// filter; a section whose title matches is kept whole
+func filterSections(sections []HelpSection, filter string) []HelpSection {
+	if filter == "" {
+		return sections
+	}
+
+	var result []HelpSection
+	for _, section := range sections {
+		if fuzzyMatch(filter, section.Title) {
+			result = append(result, section)
+			continue
+		}
+		var entries []HelpEntry
+		for _, entry := range section.Entries {
+			if fuzzyMatch(filter, entry.Key+" "+entry.Desc) {
+				entries = append(entries, entry)
+			}
+		}
+		if len(entries) > 0 {
+			result = append(result, HelpSection{Title: section.Title, Entries: entries})
+		}
+	}
+	return result
+}
+
+// fuzzyMatch reports whether the pattern's characters appear in order in
+// text, ignoring case
+func fuzzyMatch(pattern, text string) bool {
+	text = strings.ToLower(text)
+	for _, r := range strings.ToLower(pattern) {
+		i := strings.IndexRune(text, r)
+		if i < 0 {
+			return false
+		}
+		text = text[i+len(string(r)):]
+	}
+	return true
+}
+
 func (h *HelpOverlay) renderFrame(content string) string {
 	// Use lipgloss native border rendering
 	borderStyle := lipgloss.NewStyle().
@@ -0,0 +1,48 @@
+package floating
+
+import "testing"
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		pattern, text string
+		want          bool
+	}{
+		{"", "anything", true},
+		{"abn", "a abandon", true},
+		{"ABD", "a abandon", true},
+		{"prv", "z preview", true},
+		{"zz", "z preview", false},
+		{"quit", "q quit", true},
+		{"tiuq", "q quit", false},
+	}
+	for _, tt := range tests {
+		if got := fuzzyMatch(tt.pattern, tt.text); got != tt.want {
+			t.Errorf("fuzzyMatch(%q, %q) = %v, want %v", tt.pattern, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestFilterSections(t *testing.T) {
+	sections := []HelpSection{
+		{Title: "Log", Entries: []HelpEntry{{"n", "new"}, {"a", "abandon"}}},
+		{Title: "Layout", Entries: []HelpEntry{{"z", "preview"}}},
+	}
+
+	got := filterSections(sections, "abandon")
+	if len(got) != 1 || got[0].Title != "Log" || len(got[0].Entries) != 1 || got[0].Entries[0].Key != "a" {
+		t.Errorf("filterSections(abandon) = %+v", got)
+	}
+
+	// A matching title keeps the whole section
+	got = filterSections(sections, "layout")
+	if len(got) != 1 || len(got[0].Entries) != 1 {
+		t.Errorf("filterSections(layout) = %+v", got)
+	}
+
+	if got := filterSections(sections, ""); len(got) != len(sections) {
+		t.Errorf("empty filter should keep all sections, got %d", len(got))
+	}
+	if got := filterSections(sections, "xyzzy"); len(got) != 0 {
+		t.Errorf("filterSections(xyzzy) = %+v, want none", got)
+	}
+}
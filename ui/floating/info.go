@@ -44,11 +44,16 @@ func (i *InfoOverlay) View() string {
 	var lines []string
 	lines = append(lines, "")
 
-	// Word wrap message if too long
+	// Word wrap message if too long, keeping its line breaks
 	maxWidth := min(56, i.width-8)
-	wrappedLines := wrapText(i.message, maxWidth)
-	for _, line := range wrappedLines {
-		lines = append(lines, "  "+line)
+	for _, paragraph := range strings.Split(i.message, "\n") {
+		if strings.TrimSpace(paragraph) == "" {
+			lines = append(lines, "")
+			continue
+		}
+		for _, line := range wrapText(paragraph, maxWidth) {
+			lines = append(lines, "  "+line)
+		}
 	}
 
 	lines = append(lines, "")
@@ -0,0 +1,190 @@
+package floating
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gerunddev/jjazy/ui/borders"
+	"github.com/gerunddev/jjazy/ui/theme"
+)
+
+// JumpTarget is a place in the log the jump list can go to
+type JumpTarget struct {
+	Label  string // Shown in the list
+	Revset string // Resolved to the revision to select
+}
+
+// JumpOverlay lists jump targets. Typing filters the list; resolving and
+// selecting the picked target is left to the caller.
+type JumpOverlay struct {
+	title   string // Window title
+	action  string // What Enter does, for the hint line
+	targets []JumpTarget
+	filter  string
+	cursor  int // Index into the filtered list
+	offset  int // First row shown in the list
+	width   int
+	height  int
+	ready   bool
+}
+
+// NewJumpOverlay creates the jump list
+func NewJumpOverlay(targets []JumpTarget) *JumpOverlay {
+	return &JumpOverlay{title: "Jump To", action: "jump", targets: targets}
+}
+
+// SetTitle reuses the list for picking revsets for something else, e.g.
+// filtering the log. action says what Enter does.
+func (j *JumpOverlay) SetTitle(title, action string) {
+	j.title = title
+	j.action = action
+}
+
+func (j *JumpOverlay) Init() tea.Cmd {
+	return nil
+}
+
+func (j *JumpOverlay) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return j, nil
+	}
+
+	switch keyMsg.Type {
+	case tea.KeyUp, tea.KeyCtrlP:
+		if j.cursor > 0 {
+			j.cursor--
+		}
+	case tea.KeyDown, tea.KeyCtrlN:
+		if j.cursor < len(j.filtered())-1 {
+			j.cursor++
+		}
+	case tea.KeyBackspace:
+		if j.filter != "" {
+			runes := []rune(j.filter)
+			j.filter = string(runes[:len(runes)-1])
+			j.cursor = 0
+		}
+	case tea.KeyRunes:
+		j.filter += string(keyMsg.Runes)
+		j.cursor = 0
+	}
+	j.ensureCursorVisible()
+	return j, nil
+}
+
+// filtered returns the targets whose label contains the filter
+func (j *JumpOverlay) filtered() []JumpTarget {
+	if j.filter == "" {
+		return j.targets
+	}
+	var shown []JumpTarget
+	for _, t := range j.targets {
+		if strings.Contains(strings.ToLower(t.Label), strings.ToLower(j.filter)) {
+			shown = append(shown, t)
+		}
+	}
+	return shown
+}
+
+// Selected returns the target under the cursor, or nil if none match
+func (j *JumpOverlay) Selected() *JumpTarget {
+	shown := j.filtered()
+	if j.cursor < len(shown) {
+		return &shown[j.cursor]
+	}
+	return nil
+}
+
+// listRows returns how many targets fit in the window
+func (j *JumpOverlay) listRows() int {
+	return max(j.windowHeight()-7, 1) // Borders, filter, blank lines and the hint line
+}
+
+func (j *JumpOverlay) ensureCursorVisible() {
+	rows := j.listRows()
+	if j.cursor < j.offset {
+		j.offset = j.cursor
+	} else if j.cursor >= j.offset+rows {
+		j.offset = j.cursor - rows + 1
+	}
+	j.offset = max(min(j.offset, len(j.filtered())-rows), 0)
+}
+
+func (j *JumpOverlay) View() string {
+	if !j.ready {
+		return j.renderFrame("Initializing...")
+	}
+
+	lines := []string{"", "  " + theme.DimmedStyle.Render("/") + " " + j.filter + "█"}
+
+	shown := j.filtered()
+	if len(shown) == 0 {
+		lines = append(lines, "  "+theme.DimmedStyle.Render("No matches"))
+	}
+	end := min(j.offset+j.listRows(), len(shown))
+	for i := j.offset; i < end; i++ {
+		line := shown[i].Label
+		if i == j.cursor {
+			line = theme.SelectedItemStyle.Render(line)
+		} else {
+			line = theme.NormalItemStyle.Render(line)
+		}
+		lines = append(lines, "  "+line)
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, "  "+theme.HelpDescStyle.Render("type to filter  ↵ "+j.action+"  esc close"))
+	return j.renderFrame(strings.Join(lines, "\n"))
+}
+
+func (j *JumpOverlay) SetSize(width, height int) {
+	j.width = width
+	j.height = height
+	j.ready = true
+	j.ensureCursorVisible()
+}
+
+// windowHeight sizes the window to the full list, within the screen, so
+// it doesn't shrink while filtering
+func (j *JumpOverlay) windowHeight() int {
+	return max(min(len(j.targets)+7, j.height-4), 8)
+}
+
+func (j *JumpOverlay) renderFrame(content string) string {
+	// Calculate centered window dimensions
+	windowWidth := min(50, j.width-4)
+	windowHeight := j.windowHeight()
+
+	// Center the window
+	x := (j.width - windowWidth) / 2
+	y := (j.height - windowHeight) / 2
+
+	borderStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.ColorYellow).
+		Width(windowWidth - 2).
+		Height(windowHeight - 2)
+
+	bordered := borderStyle.Render(content)
+
+	// Add title to top border
+	lines := strings.Split(bordered, "\n")
+	if len(lines) > 0 {
+		borderColorStyle := lipgloss.NewStyle().Foreground(theme.ColorYellow)
+		styledTitle := theme.FloatingTitleStyle.Render(" " + j.title + " ")
+
+		remainingWidth := max(windowWidth-3-lipgloss.Width(styledTitle), 0)
+		lines[0] = borderColorStyle.Render(borders.TopLeft+borders.Horizontal) +
+			styledTitle +
+			borderColorStyle.Render(strings.Repeat(borders.Horizontal, remainingWidth)+borders.TopRight)
+	}
+
+	// Pad to center the window
+	paddingLeft := strings.Repeat(" ", x)
+	for i := range lines {
+		lines[i] = paddingLeft + lines[i]
+	}
+	return strings.Repeat("\n", y) + strings.Join(lines, "\n")
+}
@@ -0,0 +1,40 @@
+package floating
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestJumpFilter verifies typing narrows the list and the cursor stays on a match
+func TestJumpFilter(t *testing.T) {
+	j := NewJumpOverlay([]JumpTarget{
+		{Label: "@ working copy", Revset: "@"},
+		{Label: "trunk()", Revset: "trunk()"},
+		{Label: "feature-login", Revset: `"feature-login"`},
+		{Label: "feature-logout", Revset: `"feature-logout"`},
+	})
+	j.SetSize(100, 30)
+
+	if got := j.Selected(); got == nil || got.Revset != "@" {
+		t.Fatalf("Expected @ selected first, got %+v", got)
+	}
+
+	for _, r := range "feat" {
+		j.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	j.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if got := j.Selected(); got == nil || got.Label != "feature-logout" {
+		t.Errorf("Expected feature-logout after filtering and moving down, got %+v", got)
+	}
+
+	j.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if got := j.Selected(); got != nil {
+		t.Errorf("Expected no selection without matches, got %+v", got)
+	}
+
+	j.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	if got := j.Selected(); got == nil || got.Label != "feature-login" {
+		t.Errorf("Expected backspace to restore the matches, got %+v", got)
+	}
+}
@@ -1,12 +1,13 @@
 package floating
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/gerunddev/jjazy/config"
 	"github.com/gerunddev/jjazy/jj"
 	"github.com/gerunddev/jjazy/ui/borders"
 	"github.com/gerunddev/jjazy/ui/fixtures"
@@ -21,6 +22,8 @@ type LogOverlay struct {
 	repo      *jj.Repo
 	viewport  viewport.Model
 	revisions []fixtures.Revision
+	trunk     string // Bookmark decorated with ◆
+	columns   []config.LogColumn
 	cursor    int
 	width     int
 	height    int
@@ -31,9 +34,14 @@ type LogOverlay struct {
 	revisionIDPrefixes *prefix.IDSet
 }
 
-// NewLogOverlay creates a new floating log window
-func NewLogOverlay(repo *jj.Repo) *LogOverlay {
-	l := &LogOverlay{repo: repo}
+// NewLogOverlay creates a new floating log window.
+// trunkBookmark names the bookmark drawn with the trunk (◆) decoration,
+// and columns lay out each revision, config.DefaultLogColumns when empty.
+func NewLogOverlay(repo *jj.Repo, trunkBookmark string, columns []config.LogColumn) *LogOverlay {
+	if len(columns) == 0 {
+		columns = config.DefaultLogColumns()
+	}
+	l := &LogOverlay{repo: repo, trunk: trunkBookmark, columns: columns}
 	l.loadRevisions()
 	return l
 }
@@ -64,11 +72,15 @@ func (l *LogOverlay) loadRevisions() {
 			Author:        rev.Author,
 			Timestamp:     rev.Timestamp, // Unix timestamp (TODO: format as relative time)
 			Bookmarks:     rev.Bookmarks,
+			Tags:          rev.Tags,
 			GitHead:       rev.GitHead,
 			IsWorkingCopy: rev.IsWorkingCopy,
 			WorkspaceName: wsName,
 			IsRoot:        rev.IsRoot,
 			Parents:       rev.Parents,
+			Divergent:     rev.Divergent,
+			Hidden:        rev.Hidden,
+			Immutable:     rev.Immutable,
 		}
 		// Store truncated IDs (8 chars) for unique prefix calculation
 		changeID := rev.ChangeID
@@ -203,6 +215,9 @@ func (l *LogOverlay) renderLog() string {
 			Parents:       rev.Parents,
 			IsWorkingCopy: rev.IsWorkingCopy,
 			IsRoot:        rev.IsRoot,
+			Divergent:     rev.Divergent,
+			Hidden:        rev.Hidden,
+			Immutable:     rev.Immutable,
 		}
 		graphChar, connector := graph.Simple(revInfo, isLast,
 			theme.WorkingCopyStyle,
@@ -211,20 +226,20 @@ func (l *LogOverlay) renderLog() string {
 			theme.DimmedStyle, // Connector line style
 		)
 
-		// Override symbol to ◆ (blue) if this revision has the "main" bookmark
-		hasMain := false
+		// Override symbol to ◆ (blue) if this revision has the trunk bookmark
+		hasTrunk := false
 		for _, bookmark := range rev.Bookmarks {
-			if bookmark == "main" {
-				hasMain = true
+			if bookmark == l.trunk {
+				hasTrunk = true
 				break
 			}
 		}
-		if hasMain {
-			mainStyle := lipgloss.NewStyle().Foreground(theme.ColorBlue)
-			graphChar = mainStyle.Render("◆")
+		if hasTrunk {
+			trunkStyle := lipgloss.NewStyle().Foreground(theme.ColorBlue)
+			graphChar = trunkStyle.Render("◆")
 		}
 
-		// Line 1: graph + changeID(8 chars) + email + timestamp + bookmarks + revID(8 chars) [+ workspace marker]
+		// Line 1: graph + the configured columns [+ workspace marker]
 		// Use unique prefix highlighting for IDs
 		var changeID, revID string
 
@@ -278,7 +293,7 @@ func (l *LogOverlay) renderLog() string {
 
 		// Format bookmarks and git_head
 		var bookmarksStr string
-		if rev.GitHead || len(rev.Bookmarks) > 0 {
+		if rev.GitHead || len(rev.Bookmarks) > 0 || len(rev.Tags) > 0 {
 			var parts []string
 			if rev.GitHead {
 				gitHeadStyle := lipgloss.NewStyle().Foreground(theme.ColorGreen)
@@ -294,7 +309,14 @@ func (l *LogOverlay) renderLog() string {
 				}
 				parts = append(parts, bookmarkStyle.Render(strings.Join(rev.Bookmarks, " ")))
 			}
-			bookmarksStr = " " + strings.Join(parts, " ")
+			if len(rev.Tags) > 0 {
+				tagStyle := theme.TagStyle
+				if rev.IsWorkingCopy {
+					tagStyle = tagStyle.Bold(true)
+				}
+				parts = append(parts, tagStyle.Render(strings.Join(rev.Tags, " ")))
+			}
+			bookmarksStr = strings.Join(parts, " ")
 		}
 
 		var wsMarker string
@@ -302,41 +324,89 @@ func (l *LogOverlay) renderLog() string {
 			wsMarker = " " + theme.WorkingCopyStyle.Render(rev.WorkspaceName+"@")
 		}
 
-		line1 := fmt.Sprintf("%s  %s %s %s%s %s%s", graphChar, changeID, email, timestamp, bookmarksStr, revID, wsMarker)
+		var flagMarkers string
+		if m := graph.Markers(revInfo, theme.DivergentStyle, theme.HiddenStyle, theme.ImmutableStyle); m != "" {
+			flagMarkers = " " + m
+		}
+
+		styledDesc := renderDescription(rev)
+		fields := map[string]string{
+			config.ColumnChangeID:    changeID,
+			config.ColumnCommitID:    revID,
+			config.ColumnAuthor:      email,
+			config.ColumnTimestamp:   timestamp,
+			config.ColumnBookmarks:   bookmarksStr,
+			config.ColumnDescription: styledDesc,
+		}
+
+		// A trailing description goes on the second line, like jj log
+		columns := l.columns
+		var below string
+		if last := len(columns) - 1; columns[last].Name == config.ColumnDescription {
+			below = fitColumn(styledDesc, columns[last].Width)
+			columns = columns[:last]
+		}
+		var cells []string
+		for _, column := range columns {
+			value, ok := fields[column.Name]
+			if !ok || (value == "" && column.Width == 0) {
+				continue // Unknown, or empty with no width to keep
+			}
+			cells = append(cells, fitColumn(value, column.Width))
+		}
 
-		// Apply selection style if this is the cursor line
+		line1 := graphChar + "  " + strings.Join(cells, " ") + wsMarker + flagMarkers
+
+		// Apply selection style if this is the cursor line; immutable
+		// revisions are otherwise dimmed
 		if i == l.cursor {
 			line1 = theme.SelectedItemStyle.Render(line1)
+		} else if rev.Immutable {
+			line1 = theme.DimmedStyle.Render(ansi.Strip(line1))
 		}
 
 		lines = append(lines, line1)
 
-		// Line 2: connector + description (first line only, like jj log)
-		var styledDesc string
-		if rev.Description == "" {
-			descStyle := theme.DimmedStyle.Italic(true)
-			if rev.IsWorkingCopy {
-				descStyle = descStyle.Bold(true)
-			}
-			styledDesc = descStyle.Render("(no description)")
-		} else {
-			// Only show first line of description
-			desc := rev.Description
-			if idx := strings.Index(desc, "\n"); idx >= 0 {
-				desc = desc[:idx]
-			}
-			descStyle := theme.NormalItemStyle
-			if rev.IsWorkingCopy {
-				descStyle = descStyle.Bold(true)
-			}
-			styledDesc = descStyle.Render(desc)
+		// Line 2: connector + description, when it trails the columns
+		lines = append(lines, strings.TrimRight(connector+" "+below, " "))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// renderDescription styles the first line of a revision's description
+func renderDescription(rev fixtures.Revision) string {
+	if rev.Description == "" {
+		descStyle := theme.DimmedStyle.Italic(true)
+		if rev.IsWorkingCopy {
+			descStyle = descStyle.Bold(true)
 		}
+		return descStyle.Render("(no description)")
+	}
 
-		line2 := connector + " " + styledDesc
-		lines = append(lines, line2)
+	// Only show first line of description
+	desc := rev.Description
+	if idx := strings.Index(desc, "\n"); idx >= 0 {
+		desc = desc[:idx]
+	}
+	descStyle := theme.NormalItemStyle
+	if rev.Immutable {
+		descStyle = theme.DimmedStyle
+	}
+	if rev.IsWorkingCopy {
+		descStyle = descStyle.Bold(true)
 	}
+	return descStyle.Render(desc)
+}
 
-	return strings.Join(lines, "\n")
+// fitColumn pads or truncates a styled value to width cells, leaving it
+// as is when width is zero
+func fitColumn(value string, width int) string {
+	if width <= 0 {
+		return value
+	}
+	value = ansi.Truncate(value, width, "…")
+	return value + strings.Repeat(" ", width-lipgloss.Width(value))
 }
 
 func (l *LogOverlay) renderFrame(content string) string {
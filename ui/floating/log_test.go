@@ -0,0 +1,52 @@
+package floating
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/gerunddev/jjazy/config"
+	"github.com/gerunddev/jjazy/ui/fixtures"
+)
+
+// logLines renders revs with columns and returns the plain lines
+func logLines(columns []config.LogColumn, revs ...fixtures.Revision) []string {
+	l := NewLogOverlay(nil, "main", columns)
+	l.revisions = revs
+	l.cursor = -1
+	return strings.Split(ansi.Strip(l.renderLog()), "\n")
+}
+
+// TestLogDefaultColumns verifies the default layout matches jj log, with
+// the description on its own line
+func TestLogDefaultColumns(t *testing.T) {
+	lines := logLines(nil, fixtures.Revision{
+		ID: "abcdef0123", ChangeID: "kmnopqrs12", Author: "a@example.com",
+		Timestamp: "2026-01-02", Bookmarks: []string{"feature"}, Description: "Fix it",
+	})
+	if want := "○  kmnopqrs a@example.com 2026-01-02 feature abcdef01"; lines[0] != want {
+		t.Errorf("first line = %q, want %q", lines[0], want)
+	}
+	if !strings.HasSuffix(lines[1], " Fix it") {
+		t.Errorf("second line = %q, want the description", lines[1])
+	}
+}
+
+// TestLogColumnsOrderAndWidth verifies columns follow the configured
+// order and width, and a description that isn't last stays on the first
+// line
+func TestLogColumnsOrderAndWidth(t *testing.T) {
+	lines := logLines([]config.LogColumn{
+		{Name: config.ColumnAuthor, Width: 6},
+		{Name: config.ColumnDescription},
+		{Name: config.ColumnChangeID},
+	}, fixtures.Revision{
+		ID: "abcdef0123", ChangeID: "kmnopqrs12", Author: "a@example.com", Description: "Fix it",
+	})
+	if want := "○  a@exa… Fix it kmnopqrs"; lines[0] != want {
+		t.Errorf("first line = %q, want %q", lines[0], want)
+	}
+	if strings.Contains(lines[1], "Fix it") {
+		t.Errorf("second line = %q, want no description", lines[1])
+	}
+}
@@ -0,0 +1,159 @@
+package floating
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gerunddev/jjazy/ui/borders"
+	"github.com/gerunddev/jjazy/ui/theme"
+)
+
+// MenuItem is an action in the context menu, run by pressing Key
+type MenuItem struct {
+	Label string
+	Key   tea.KeyMsg
+}
+
+// MenuOverlay lists the actions for the selected item. Picking one, with
+// the cursor or by its key, is left to the caller.
+type MenuOverlay struct {
+	title  string // Names the item the actions apply to
+	items  []MenuItem
+	cursor int
+	width  int
+	height int
+	ready  bool
+}
+
+// NewMenuOverlay creates the menu of items for the item named by title
+func NewMenuOverlay(title string, items []MenuItem) *MenuOverlay {
+	return &MenuOverlay{title: title, items: items}
+}
+
+func (m *MenuOverlay) Init() tea.Cmd {
+	return nil
+}
+
+func (m *MenuOverlay) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "up", "ctrl+p":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "ctrl+n":
+		if m.cursor < len(m.items)-1 {
+			m.cursor++
+		}
+	}
+	return m, nil
+}
+
+// Selected returns the item under the cursor, or nil for an empty menu
+func (m *MenuOverlay) Selected() *MenuItem {
+	if m.cursor < len(m.items) {
+		return &m.items[m.cursor]
+	}
+	return nil
+}
+
+// ItemFor returns the item run by key, or nil if none is
+func (m *MenuOverlay) ItemFor(key string) *MenuItem {
+	for i := range m.items {
+		if m.items[i].Key.String() == key {
+			return &m.items[i]
+		}
+	}
+	return nil
+}
+
+func (m *MenuOverlay) View() string {
+	if !m.ready {
+		return m.renderFrame("Initializing...")
+	}
+
+	keyWidth := 0
+	for _, item := range m.items {
+		keyWidth = max(keyWidth, lipgloss.Width(keyLabel(item.Key)))
+	}
+
+	lines := []string{""}
+	for i, item := range m.items {
+		keyText := keyLabel(item.Key)
+		keyText += strings.Repeat(" ", keyWidth-lipgloss.Width(keyText))
+		label := theme.NormalItemStyle.Render(item.Label)
+		if i == m.cursor {
+			label = theme.SelectedItemStyle.Render(item.Label)
+		}
+		lines = append(lines, "  "+theme.HelpKeyStyle.Render(keyText)+"  "+label)
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, "  "+theme.HelpDescStyle.Render("↵ run  esc close"))
+	return m.renderFrame(strings.Join(lines, "\n"))
+}
+
+// keyLabel shows a key the way the help screen does
+func keyLabel(msg tea.KeyMsg) string {
+	if msg.Type == tea.KeySpace || msg.String() == " " {
+		return "space"
+	}
+	return msg.String()
+}
+
+func (m *MenuOverlay) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.ready = true
+}
+
+func (m *MenuOverlay) windowWidth() int {
+	return min(max(40, lipgloss.Width(m.title)+8), m.width-4)
+}
+
+// windowHeight sizes the window to the menu, within the screen
+func (m *MenuOverlay) windowHeight() int {
+	return max(min(len(m.items)+5, m.height-4), 6)
+}
+
+func (m *MenuOverlay) renderFrame(content string) string {
+	// Calculate centered window dimensions
+	windowWidth := m.windowWidth()
+	windowHeight := m.windowHeight()
+
+	// Center the window
+	x := (m.width - windowWidth) / 2
+	y := (m.height - windowHeight) / 2
+
+	borderStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.ColorYellow).
+		Width(windowWidth - 2).
+		Height(windowHeight - 2)
+
+	bordered := borderStyle.Render(content)
+
+	// Add title to top border
+	lines := strings.Split(bordered, "\n")
+	if len(lines) > 0 {
+		borderColorStyle := lipgloss.NewStyle().Foreground(theme.ColorYellow)
+		styledTitle := theme.FloatingTitleStyle.Render(" " + m.title + " ")
+
+		remainingWidth := max(windowWidth-3-lipgloss.Width(styledTitle), 0)
+		lines[0] = borderColorStyle.Render(borders.TopLeft+borders.Horizontal) +
+			styledTitle +
+			borderColorStyle.Render(strings.Repeat(borders.Horizontal, remainingWidth)+borders.TopRight)
+	}
+
+	// Pad to center the window
+	paddingLeft := strings.Repeat(" ", x)
+	for i := range lines {
+		lines[i] = paddingLeft + lines[i]
+	}
+	return strings.Repeat("\n", y) + strings.Join(lines, "\n")
+}
@@ -0,0 +1,31 @@
+package floating
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestMenuPicking verifies items are found by cursor or by their key
+func TestMenuPicking(t *testing.T) {
+	m := NewMenuOverlay("Change kmnopqrs", []MenuItem{
+		{Label: "open change", Key: tea.KeyMsg{Type: tea.KeyEnter}},
+		{Label: "describe", Key: tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}}},
+		{Label: "abandon", Key: tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}}},
+	})
+	m.SetSize(100, 30)
+
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if got := m.Selected(); got == nil || got.Label != "abandon" {
+		t.Errorf("Expected the cursor to stop on the last item, got %+v", got)
+	}
+
+	if got := m.ItemFor("d"); got == nil || got.Label != "describe" {
+		t.Errorf("ItemFor(d) = %+v, want describe", got)
+	}
+	if got := m.ItemFor("x"); got != nil {
+		t.Errorf("ItemFor(x) = %+v, want nil", got)
+	}
+}
@@ -0,0 +1,164 @@
+package floating
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/borders"
+	"github.com/gerunddev/jjazy/ui/theme"
+)
+
+// Column widths of the metrics table
+const (
+	metricsOpWidth     = 26
+	metricsNumberWidth = 8
+)
+
+// MetricsOverlay is a debug table of bridge call latencies: calls, mean
+// and max per operation, and how many calls fell in each latency bucket
+type MetricsOverlay struct {
+	latencies []jj.OpLatency
+	buckets   []string // Labels of the latency buckets
+	offset    int      // First row shown
+	width     int
+	height    int
+	ready     bool
+}
+
+// NewMetricsOverlay creates the overlay for a snapshot of the latencies
+func NewMetricsOverlay(latencies []jj.OpLatency, buckets []string) *MetricsOverlay {
+	return &MetricsOverlay{latencies: latencies, buckets: buckets}
+}
+
+func (m *MetricsOverlay) Init() tea.Cmd {
+	return nil
+}
+
+func (m *MetricsOverlay) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "up", "ctrl+p":
+		m.scroll(-1)
+	case "down", "ctrl+n":
+		m.scroll(1)
+	}
+	return m, nil
+}
+
+// scroll moves the view by delta rows, within the table
+func (m *MetricsOverlay) scroll(delta int) {
+	m.offset = max(min(m.offset+delta, len(m.latencies)-m.listRows()), 0)
+}
+
+// listRows returns how many operations fit in the window
+func (m *MetricsOverlay) listRows() int {
+	return max(m.windowHeight()-7, 1) // Borders, header, blank lines and the hint line
+}
+
+// formatLatency renders a duration to two or three significant figures
+func formatLatency(d time.Duration) string {
+	switch {
+	case d < time.Millisecond:
+		return fmt.Sprintf("%dµs", d.Microseconds())
+	case d < time.Second:
+		return fmt.Sprintf("%.1fms", float64(d)/float64(time.Millisecond))
+	default:
+		return fmt.Sprintf("%.2fs", d.Seconds())
+	}
+}
+
+// row lays out one table row: the op, then right-aligned numbers
+func (m *MetricsOverlay) row(op string, cells []string) string {
+	var b strings.Builder
+	b.WriteString(lipgloss.NewStyle().Width(metricsOpWidth).MaxWidth(metricsOpWidth).Render(op))
+	for _, cell := range cells {
+		b.WriteString(fmt.Sprintf("%*s", metricsNumberWidth, cell))
+	}
+	return b.String()
+}
+
+func (m *MetricsOverlay) View() string {
+	if !m.ready {
+		return m.renderFrame("Initializing...")
+	}
+
+	header := append([]string{"calls", "mean", "max"}, m.buckets...)
+	lines := []string{"", "  " + theme.DimmedStyle.Render(m.row("op", header))}
+	if len(m.latencies) == 0 {
+		lines = append(lines, "  "+theme.DimmedStyle.Render("No bridge calls yet"))
+	}
+	end := min(m.offset+m.listRows(), len(m.latencies))
+	for _, l := range m.latencies[m.offset:end] {
+		cells := []string{fmt.Sprint(l.Count), formatLatency(l.Mean), formatLatency(l.Max)}
+		for _, n := range l.Buckets {
+			cells = append(cells, fmt.Sprint(n))
+		}
+		lines = append(lines, "  "+theme.NormalItemStyle.Render(m.row(l.Op, cells)))
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, "  "+theme.HelpDescStyle.Render("esc close"))
+	return m.renderFrame(strings.Join(lines, "\n"))
+}
+
+func (m *MetricsOverlay) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.ready = true
+	m.scroll(0)
+}
+
+// windowWidth fits the table's columns, within the screen
+func (m *MetricsOverlay) windowWidth() int {
+	return min(metricsOpWidth+(3+len(m.buckets))*metricsNumberWidth+6, m.width-4)
+}
+
+// windowHeight sizes the window to the table, within the screen
+func (m *MetricsOverlay) windowHeight() int {
+	return max(min(len(m.latencies)+7, m.height-4), 8)
+}
+
+func (m *MetricsOverlay) renderFrame(content string) string {
+	// Calculate centered window dimensions
+	windowWidth := m.windowWidth()
+	windowHeight := m.windowHeight()
+
+	// Center the window
+	x := (m.width - windowWidth) / 2
+	y := (m.height - windowHeight) / 2
+
+	borderStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.ColorYellow).
+		Width(windowWidth - 2).
+		Height(windowHeight - 2)
+
+	bordered := borderStyle.Render(content)
+
+	// Add title to top border
+	lines := strings.Split(bordered, "\n")
+	if len(lines) > 0 {
+		borderColorStyle := lipgloss.NewStyle().Foreground(theme.ColorYellow)
+		styledTitle := theme.FloatingTitleStyle.Render(" Bridge Metrics ")
+
+		remainingWidth := max(windowWidth-3-lipgloss.Width(styledTitle), 0)
+		lines[0] = borderColorStyle.Render(borders.TopLeft+borders.Horizontal) +
+			styledTitle +
+			borderColorStyle.Render(strings.Repeat(borders.Horizontal, remainingWidth)+borders.TopRight)
+	}
+
+	// Pad to center the window
+	paddingLeft := strings.Repeat(" ", x)
+	for i := range lines {
+		lines[i] = paddingLeft + lines[i]
+	}
+	return strings.Repeat("\n", y) + strings.Join(lines, "\n")
+}
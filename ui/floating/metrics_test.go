@@ -0,0 +1,33 @@
+package floating
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gerunddev/jjazy/jj"
+)
+
+// TestMetricsOverlay verifies each operation's row shows its counts and latencies
+func TestMetricsOverlay(t *testing.T) {
+	m := NewMetricsOverlay([]jj.OpLatency{
+		{Op: "get_log", Count: 3, Mean: 12500 * time.Microsecond, Max: 2 * time.Second, Buckets: []int{1, 0, 1, 0, 1}},
+	}, []string{"<1ms", "<10ms", "<100ms", "<1s", "≥1s"})
+	m.SetSize(120, 30)
+
+	view := m.View()
+	for _, want := range []string{"Bridge Metrics", "get_log", "12.5ms", "2.00s", "≥1s"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected the table to contain %q, got %q", want, view)
+		}
+	}
+}
+
+// TestMetricsOverlayEmpty verifies the table says when nothing has been called
+func TestMetricsOverlayEmpty(t *testing.T) {
+	m := NewMetricsOverlay(nil, []string{"<1ms"})
+	m.SetSize(120, 30)
+	if view := m.View(); !strings.Contains(view, "No bridge calls yet") {
+		t.Errorf("Expected the empty message, got %q", view)
+	}
+}
@@ -0,0 +1,155 @@
+package floating
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/gerunddev/jjazy/ui/borders"
+	"github.com/gerunddev/jjazy/ui/theme"
+)
+
+// Notification is a transient message: a confirmation, or an error that
+// didn't warrant a dialog
+type Notification struct {
+	At   time.Time
+	Text string
+	Err  bool
+}
+
+// String formats the notification as a row: time, outcome and text
+func (n Notification) String() string {
+	mark := "✓"
+	if n.Err {
+		mark = "✗"
+	}
+	return n.At.Format("15:04:05") + "  " + mark + " " + n.Text
+}
+
+// NotificationsOverlay lists the session's notifications, newest first,
+// for reading those that expired
+type NotificationsOverlay struct {
+	notifications []Notification // Newest first
+	offset        int            // First row shown
+	width         int
+	height        int
+	ready         bool
+}
+
+// NewNotificationsOverlay creates the overlay for notifications, newest first
+func NewNotificationsOverlay(notifications []Notification) *NotificationsOverlay {
+	return &NotificationsOverlay{notifications: notifications}
+}
+
+func (n *NotificationsOverlay) Init() tea.Cmd {
+	return nil
+}
+
+func (n *NotificationsOverlay) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return n, nil
+	}
+
+	switch keyMsg.String() {
+	case "up", "ctrl+p":
+		n.scroll(-1)
+	case "down", "ctrl+n":
+		n.scroll(1)
+	case "pgup", "alt+v":
+		n.scroll(-n.listRows())
+	case "pgdown", "ctrl+v":
+		n.scroll(n.listRows())
+	}
+	return n, nil
+}
+
+// scroll moves the view by delta rows, within the list
+func (n *NotificationsOverlay) scroll(delta int) {
+	n.offset = max(min(n.offset+delta, len(n.notifications)-n.listRows()), 0)
+}
+
+// listRows returns how many rows fit in the window
+func (n *NotificationsOverlay) listRows() int {
+	return max(n.windowHeight()-6, 1) // Borders, blank lines and the hint line
+}
+
+func (n *NotificationsOverlay) View() string {
+	if !n.ready {
+		return n.renderFrame("Initializing...")
+	}
+
+	lines := []string{""}
+	if len(n.notifications) == 0 {
+		lines = append(lines, "  "+theme.DimmedStyle.Render("No notifications yet"))
+	}
+	end := min(n.offset+n.listRows(), len(n.notifications))
+	for _, notification := range n.notifications[n.offset:end] {
+		style := theme.NormalItemStyle
+		if notification.Err {
+			style = theme.DeletedStyle
+		}
+		// Long errors are cut rather than wrapped, to keep one row each
+		row := ansi.Truncate(notification.String(), max(n.windowWidth()-6, 10), "…")
+		lines = append(lines, "  "+style.Render(row))
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, "  "+theme.HelpDescStyle.Render("esc close"))
+	return n.renderFrame(strings.Join(lines, "\n"))
+}
+
+func (n *NotificationsOverlay) SetSize(width, height int) {
+	n.width = width
+	n.height = height
+	n.ready = true
+	n.scroll(0)
+}
+
+func (n *NotificationsOverlay) windowWidth() int {
+	return min(80, n.width-4)
+}
+
+// windowHeight sizes the window to the list, within the screen
+func (n *NotificationsOverlay) windowHeight() int {
+	return max(min(len(n.notifications)+6, n.height-4), 8)
+}
+
+func (n *NotificationsOverlay) renderFrame(content string) string {
+	// Calculate centered window dimensions
+	windowWidth := n.windowWidth()
+	windowHeight := n.windowHeight()
+
+	// Center the window
+	x := (n.width - windowWidth) / 2
+	y := (n.height - windowHeight) / 2
+
+	borderStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.ColorYellow).
+		Width(windowWidth - 2).
+		Height(windowHeight - 2)
+
+	bordered := borderStyle.Render(content)
+
+	// Add title to top border
+	lines := strings.Split(bordered, "\n")
+	if len(lines) > 0 {
+		borderColorStyle := lipgloss.NewStyle().Foreground(theme.ColorYellow)
+		styledTitle := theme.FloatingTitleStyle.Render(" Notifications ")
+
+		remainingWidth := max(windowWidth-3-lipgloss.Width(styledTitle), 0)
+		lines[0] = borderColorStyle.Render(borders.TopLeft+borders.Horizontal) +
+			styledTitle +
+			borderColorStyle.Render(strings.Repeat(borders.Horizontal, remainingWidth)+borders.TopRight)
+	}
+
+	// Pad to center the window
+	paddingLeft := strings.Repeat(" ", x)
+	for i := range lines {
+		lines[i] = paddingLeft + lines[i]
+	}
+	return strings.Repeat("\n", y) + strings.Join(lines, "\n")
+}
@@ -0,0 +1,33 @@
+package floating
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestNotificationsOverlay verifies rows show the time, outcome and text
+func TestNotificationsOverlay(t *testing.T) {
+	at := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	n := NewNotificationsOverlay([]Notification{
+		{At: at, Text: "refresh failed: boom", Err: true},
+		{At: at, Text: "abandon kmnopqrs"},
+	})
+	n.SetSize(120, 30)
+
+	view := n.View()
+	for _, want := range []string{"Notifications", "15:04:05  ✗ refresh failed: boom", "15:04:05  ✓ abandon kmnopqrs"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected the list to contain %q, got %q", want, view)
+		}
+	}
+}
+
+// TestNotificationsOverlayEmpty verifies the list says when there are none
+func TestNotificationsOverlayEmpty(t *testing.T) {
+	n := NewNotificationsOverlay(nil)
+	n.SetSize(120, 30)
+	if view := n.View(); !strings.Contains(view, "No notifications yet") {
+		t.Errorf("Expected the empty message, got %q", view)
+	}
+}
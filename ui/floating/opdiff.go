@@ -0,0 +1,143 @@
+package floating
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/gerunddev/jjazy/ui/borders"
+	"github.com/gerunddev/jjazy/ui/theme"
+)
+
+// OpDiffOverlay shows what an operation changed (jj op diff) in a
+// scrollable window. The output keeps jj's colors; long lines are cut.
+type OpDiffOverlay struct {
+	title  string
+	lines  []string
+	offset int // First line shown
+	width  int
+	height int
+	ready  bool
+}
+
+// NewOpDiffOverlay creates the overlay for an operation's jj op diff output
+func NewOpDiffOverlay(opID, output string) *OpDiffOverlay {
+	output = strings.TrimRight(output, "\n")
+	var lines []string
+	if strings.TrimSpace(ansi.Strip(output)) != "" {
+		lines = strings.Split(output, "\n")
+	}
+	return &OpDiffOverlay{
+		title: "Operation " + opID,
+		lines: lines,
+	}
+}
+
+func (o *OpDiffOverlay) Init() tea.Cmd {
+	return nil
+}
+
+func (o *OpDiffOverlay) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return o, nil
+	}
+
+	switch keyMsg.String() {
+	case "up", "ctrl+p":
+		o.scroll(-1)
+	case "down", "ctrl+n":
+		o.scroll(1)
+	case "pgup", "alt+v":
+		o.scroll(-o.textRows())
+	case "pgdown", "ctrl+v", " ":
+		o.scroll(o.textRows())
+	case "home", "alt+<":
+		o.offset = 0
+	case "end", "alt+>":
+		o.scroll(len(o.lines))
+	}
+	return o, nil
+}
+
+// scroll moves the view by delta lines, within the output
+func (o *OpDiffOverlay) scroll(delta int) {
+	o.offset = max(min(o.offset+delta, len(o.lines)-o.textRows()), 0)
+}
+
+// textRows returns how many lines of output fit in the window
+func (o *OpDiffOverlay) textRows() int {
+	return max(o.windowHeight()-6, 1) // Borders, blank lines and the hint line
+}
+
+func (o *OpDiffOverlay) View() string {
+	if !o.ready {
+		return o.renderFrame("Initializing...")
+	}
+
+	content := []string{""}
+	if len(o.lines) == 0 {
+		content = append(content, "  "+theme.DimmedStyle.Render("This operation changed nothing."))
+	}
+	end := min(o.offset+o.textRows(), len(o.lines))
+	for _, line := range o.lines[o.offset:end] {
+		content = append(content, "  "+ansi.Truncate(line, max(o.windowWidth()-6, 10), "…"))
+	}
+	content = append(content, "")
+	content = append(content, "  "+theme.HelpDescStyle.Render("↑/↓ scroll  esc close"))
+	return o.renderFrame(strings.Join(content, "\n"))
+}
+
+func (o *OpDiffOverlay) SetSize(width, height int) {
+	o.width = width
+	o.height = height
+	o.ready = true
+	o.scroll(0)
+}
+
+func (o *OpDiffOverlay) windowWidth() int {
+	return min(110, o.width-4)
+}
+
+// windowHeight sizes the window to the output, within the screen
+func (o *OpDiffOverlay) windowHeight() int {
+	return max(min(len(o.lines)+6, o.height-4), 8)
+}
+
+func (o *OpDiffOverlay) renderFrame(content string) string {
+	// Calculate centered window dimensions
+	windowWidth := o.windowWidth()
+	windowHeight := o.windowHeight()
+
+	// Center the window
+	x := (o.width - windowWidth) / 2
+	y := (o.height - windowHeight) / 2
+
+	borderStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.ColorYellow).
+		Width(windowWidth - 2).
+		Height(windowHeight - 2)
+
+	bordered := borderStyle.Render(content)
+
+	// Add title to top border
+	lines := strings.Split(bordered, "\n")
+	if len(lines) > 0 {
+		borderColorStyle := lipgloss.NewStyle().Foreground(theme.ColorYellow)
+		styledTitle := theme.FloatingTitleStyle.Render(" " + o.title + " ")
+
+		remainingWidth := max(windowWidth-3-lipgloss.Width(styledTitle), 0)
+		lines[0] = borderColorStyle.Render(borders.TopLeft+borders.Horizontal) +
+			styledTitle +
+			borderColorStyle.Render(strings.Repeat(borders.Horizontal, remainingWidth)+borders.TopRight)
+	}
+
+	// Pad to center the window
+	paddingLeft := strings.Repeat(" ", x)
+	for i := range lines {
+		lines[i] = paddingLeft + lines[i]
+	}
+	return strings.Repeat("\n", y) + strings.Join(lines, "\n")
+}
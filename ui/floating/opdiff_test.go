@@ -0,0 +1,41 @@
+package floating
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestOpDiffOverlayScroll verifies the output scrolls within its length
+func TestOpDiffOverlayScroll(t *testing.T) {
+	var lines []string
+	for i := range 50 {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	o := NewOpDiffOverlay("abc123", strings.Join(lines, "\n")+"\n")
+	o.SetSize(100, 20)
+
+	o.Update(tea.KeyMsg{Type: tea.KeyEnd})
+	if !strings.Contains(o.View(), "line 49") {
+		t.Errorf("Expected end to show the last line")
+	}
+	o.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if o.offset != len(lines)-o.textRows() {
+		t.Errorf("Expected scrolling to stop at the end, got offset %d", o.offset)
+	}
+	o.Update(tea.KeyMsg{Type: tea.KeyHome})
+	if !strings.Contains(o.View(), "line 0") {
+		t.Errorf("Expected home to show the first line")
+	}
+}
+
+// TestOpDiffOverlayEmpty verifies an operation with no changes says so
+func TestOpDiffOverlayEmpty(t *testing.T) {
+	o := NewOpDiffOverlay("abc123", "\n")
+	o.SetSize(100, 20)
+	if !strings.Contains(o.View(), "changed nothing") {
+		t.Errorf("Expected the empty message")
+	}
+}
@@ -0,0 +1,183 @@
+package floating
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/borders"
+	"github.com/gerunddev/jjazy/ui/theme"
+)
+
+// statusWidth fits the longest push state
+const statusWidth = len(jj.StackNeedsPush)
+
+// StackOverlay lists the changes between trunk and @ with their push
+// state. The bulk actions on the stack are left to the caller.
+type StackOverlay struct {
+	entries []jj.StackEntry
+	cursor  int
+	offset  int // First row shown in the list
+	width   int
+	height  int
+	ready   bool
+}
+
+// NewStackOverlay creates the stack view of entries, newest first
+func NewStackOverlay(entries []jj.StackEntry) *StackOverlay {
+	return &StackOverlay{entries: entries}
+}
+
+func (s *StackOverlay) Init() tea.Cmd {
+	return nil
+}
+
+func (s *StackOverlay) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return s, nil
+	}
+
+	switch keyMsg.String() {
+	case "up", "ctrl+p":
+		if s.cursor > 0 {
+			s.cursor--
+		}
+	case "down", "ctrl+n":
+		if s.cursor < len(s.entries)-1 {
+			s.cursor++
+		}
+	}
+	s.ensureCursorVisible()
+	return s, nil
+}
+
+// Entries returns the stack, newest first
+func (s *StackOverlay) Entries() []jj.StackEntry {
+	return s.entries
+}
+
+// Selected returns the entry under the cursor, or nil for an empty stack
+func (s *StackOverlay) Selected() *jj.StackEntry {
+	if s.cursor < len(s.entries) {
+		return &s.entries[s.cursor]
+	}
+	return nil
+}
+
+// listRows returns how many entries fit in the window
+func (s *StackOverlay) listRows() int {
+	return max(s.windowHeight()-6, 1) // Borders, blank lines and the hint line
+}
+
+func (s *StackOverlay) ensureCursorVisible() {
+	rows := s.listRows()
+	if s.cursor < s.offset {
+		s.offset = s.cursor
+	} else if s.cursor >= s.offset+rows {
+		s.offset = s.cursor - rows + 1
+	}
+}
+
+func (s *StackOverlay) View() string {
+	if !s.ready {
+		return s.renderFrame("Initializing...")
+	}
+
+	lines := []string{""}
+	end := min(s.offset+s.listRows(), len(s.entries))
+	for i := s.offset; i < end; i++ {
+		lines = append(lines, "  "+s.renderEntry(s.entries[i], i == s.cursor))
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, "  "+theme.HelpDescStyle.Render("↵ select  p push all  r rebase onto trunk  n renumber  esc close"))
+	return s.renderFrame(strings.Join(lines, "\n"))
+}
+
+// renderEntry draws an entry as its change ID, push state, bookmarks and
+// description, cut to the window
+func (s *StackOverlay) renderEntry(entry jj.StackEntry, selected bool) string {
+	status := entry.Status + strings.Repeat(" ", statusWidth-len(entry.Status))
+	text := entry.Description
+	if text == "" {
+		text = "(no description)"
+	}
+	if len(entry.Bookmarks) > 0 {
+		text = theme.CurrentBookmarkStyle.Render(strings.Join(entry.Bookmarks, " ")) + " " + text
+	}
+	line := theme.ChangeIDStyle.Render(entry.ChangeID) + "  " + stackStatusStyle(entry.Status).Render(status) + "  " + text
+	line = ansi.Truncate(line, max(s.windowWidth()-6, 1), "…")
+	if selected {
+		return theme.SelectedItemStyle.Render(ansi.Strip(line))
+	}
+	return line
+}
+
+// stackStatusStyle colors a push state by what it asks of the user
+func stackStatusStyle(status string) lipgloss.Style {
+	switch status {
+	case jj.StackNeedsPush:
+		return theme.ModifiedStyle
+	case jj.StackMerged:
+		return theme.AddedStyle
+	case jj.StackBehind:
+		return theme.DeletedStyle
+	}
+	return theme.DimmedStyle
+}
+
+func (s *StackOverlay) SetSize(width, height int) {
+	s.width = width
+	s.height = height
+	s.ready = true
+	s.ensureCursorVisible()
+}
+
+func (s *StackOverlay) windowWidth() int {
+	return min(90, s.width-4)
+}
+
+// windowHeight sizes the window to the stack, within the screen
+func (s *StackOverlay) windowHeight() int {
+	return max(min(len(s.entries)+6, s.height-4), 7)
+}
+
+func (s *StackOverlay) renderFrame(content string) string {
+	// Calculate centered window dimensions
+	windowWidth := s.windowWidth()
+	windowHeight := s.windowHeight()
+
+	// Center the window
+	x := (s.width - windowWidth) / 2
+	y := (s.height - windowHeight) / 2
+
+	borderStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.ColorYellow).
+		Width(windowWidth - 2).
+		Height(windowHeight - 2)
+
+	bordered := borderStyle.Render(content)
+
+	// Add title to top border
+	lines := strings.Split(bordered, "\n")
+	if len(lines) > 0 {
+		borderColorStyle := lipgloss.NewStyle().Foreground(theme.ColorYellow)
+		styledTitle := theme.FloatingTitleStyle.Render(" Stack ")
+
+		remainingWidth := max(windowWidth-3-lipgloss.Width(styledTitle), 0)
+		lines[0] = borderColorStyle.Render(borders.TopLeft+borders.Horizontal) +
+			styledTitle +
+			borderColorStyle.Render(strings.Repeat(borders.Horizontal, remainingWidth)+borders.TopRight)
+	}
+
+	// Pad to center the window
+	paddingLeft := strings.Repeat(" ", x)
+	for i := range lines {
+		lines[i] = paddingLeft + lines[i]
+	}
+	return strings.Repeat("\n", y) + strings.Join(lines, "\n")
+}
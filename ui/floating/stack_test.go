@@ -0,0 +1,31 @@
+package floating
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/gerunddev/jjazy/jj"
+)
+
+// TestStackOverlay verifies entries show their push state and the cursor
+// picks one
+func TestStackOverlay(t *testing.T) {
+	s := NewStackOverlay([]jj.StackEntry{
+		{ChangeID: "kmnopqrs", Bookmarks: []string{"auth-2"}, Description: "Add login", Status: jj.StackNeedsPush},
+		{ChangeID: "zyxwvuts", Bookmarks: []string{"auth-1"}, Description: "Add users", Status: jj.StackPushed},
+	})
+	s.SetSize(100, 30)
+
+	view := ansi.Strip(s.View())
+	if !strings.Contains(view, "kmnopqrs  needs push  auth-2 Add login") {
+		t.Errorf("Expected the entry with its state and bookmark, got:\n%s", view)
+	}
+
+	s.Update(tea.KeyMsg{Type: tea.KeyDown})
+	s.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if got := s.Selected(); got == nil || got.ChangeID != "zyxwvuts" {
+		t.Errorf("Expected the cursor to stop on the last entry, got %+v", got)
+	}
+}
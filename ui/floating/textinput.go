@@ -3,6 +3,7 @@ package floating
 import (
 	"strings"
 
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -10,13 +11,25 @@ import (
 	"github.com/gerunddev/jjazy/ui/theme"
 )
 
+// textAreaRows is the height of the multi-line editor
+const textAreaRows = 8
+
 // TextInputOverlay is a floating window for text input
 type TextInputOverlay struct {
 	textInput textinput.Model
+	area      *textarea.Model // Set for multi-line input, where enter adds a line
 	title     string
 	width     int
 	height    int
 	ready     bool
+
+	// Validation feedback shown under the input
+	status        string
+	statusError   bool
+	statusWarning bool
+	errorOffset   int // Position in the value to mark with a caret, -1 for none
+
+	hints []string // Extra key hints after save/cancel
 }
 
 // NewTextInputOverlay creates a new floating text input window
@@ -29,17 +42,44 @@ func NewTextInputOverlay(title, placeholder, initialValue string) *TextInputOver
 	ti.Width = 60
 
 	return &TextInputOverlay{
-		textInput: ti,
-		title:     title,
+		textInput:   ti,
+		title:       title,
+		errorOffset: -1,
+	}
+}
+
+// NewTextAreaOverlay creates a floating multi-line editor, for messages
+// that need more than a subject line
+func NewTextAreaOverlay(title, placeholder, initialValue string) *TextInputOverlay {
+	ta := textarea.New()
+	ta.Placeholder = placeholder
+	ta.ShowLineNumbers = false
+	ta.CharLimit = 0
+	ta.SetWidth(60)
+	ta.SetHeight(textAreaRows)
+	ta.SetValue(initialValue)
+	ta.Focus()
+
+	return &TextInputOverlay{
+		area:        &ta,
+		title:       title,
+		errorOffset: -1,
 	}
 }
 
 func (t *TextInputOverlay) Init() tea.Cmd {
+	if t.area != nil {
+		return textarea.Blink
+	}
 	return textinput.Blink
 }
 
 func (t *TextInputOverlay) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
+	if t.area != nil {
+		*t.area, cmd = t.area.Update(msg)
+		return t, cmd
+	}
 	t.textInput, cmd = t.textInput.Update(msg)
 	return t, cmd
 }
@@ -52,9 +92,24 @@ func (t *TextInputOverlay) View() string {
 	// Build content
 	var lines []string
 	lines = append(lines, "")
-	lines = append(lines, t.textInput.View())
+	if t.area != nil {
+		lines = append(lines, t.area.View())
+	} else {
+		lines = append(lines, t.textInput.View())
+	}
+	if t.status != "" {
+		lines = append(lines, t.renderCaret())
+		if t.statusError {
+			lines = append(lines, "  "+theme.DeletedStyle.Render(t.status))
+		} else if t.statusWarning {
+			lines = append(lines, "  "+theme.ModifiedStyle.Render(t.status))
+		} else {
+			lines = append(lines, "  "+theme.DimmedStyle.Render(t.status))
+		}
+	}
 	lines = append(lines, "")
-	lines = append(lines, theme.HelpDescStyle.Render("  ctrl+s save • ctrl+x cancel"))
+	hints := append([]string{"ctrl+s save", "ctrl+x cancel"}, t.hints...)
+	lines = append(lines, theme.HelpDescStyle.Render("  "+strings.Join(hints, " • ")))
 
 	content := strings.Join(lines, "\n")
 
@@ -69,16 +124,87 @@ func (t *TextInputOverlay) SetSize(width, height int) {
 	// Update text input width to fit within the window
 	inputWidth := min(60, width-8)
 	t.textInput.Width = inputWidth
+	if t.area != nil {
+		t.area.SetWidth(inputWidth)
+	}
 }
 
 func (t *TextInputOverlay) Value() string {
+	if t.area != nil {
+		return t.area.Value()
+	}
 	return t.textInput.Value()
 }
 
+// AddHint adds a key hint next to save/cancel
+func (t *TextInputOverlay) AddHint(hint string) {
+	t.hints = append(t.hints, hint)
+}
+
+// SetValue replaces the input text, leaving the cursor at the end
+func (t *TextInputOverlay) SetValue(value string) {
+	if t.area != nil {
+		t.area.SetValue(value) // Leaves the cursor at the end
+		return
+	}
+	t.textInput.SetValue(value)
+	t.textInput.CursorEnd()
+}
+
+// SetStatus shows a neutral status line under the input (e.g. a match count)
+func (t *TextInputOverlay) SetStatus(status string) {
+	t.status = status
+	t.statusError = false
+	t.statusWarning = false
+	t.errorOffset = -1
+}
+
+// SetError shows an error under the input, with a caret under the offending
+// position when offset is within the value (-1 for none)
+func (t *TextInputOverlay) SetError(message string, offset int) {
+	t.status = message
+	t.statusError = true
+	t.statusWarning = false
+	t.errorOffset = offset
+}
+
+// SetWarning shows a warning that doesn't stop saving, with a caret like
+// SetError
+func (t *TextInputOverlay) SetWarning(message string, offset int) {
+	t.status = message
+	t.statusError = false
+	t.statusWarning = true
+	t.errorOffset = offset
+}
+
+// HasWarning returns true while a warning from SetWarning is shown
+func (t *TextInputOverlay) HasWarning() bool {
+	return t.statusWarning
+}
+
+// renderCaret points at the error position, or is blank when there is none
+func (t *TextInputOverlay) renderCaret() string {
+	if !t.statusError && !t.statusWarning || t.errorOffset < 0 || t.errorOffset > len(t.textInput.Value()) {
+		return ""
+	}
+	// Revsets are short; skip the caret rather than track horizontal scroll
+	if t.errorOffset >= t.textInput.Width {
+		return ""
+	}
+	col := lipgloss.Width(t.textInput.Prompt) + t.errorOffset
+	if t.statusWarning {
+		return strings.Repeat(" ", col) + theme.ModifiedStyle.Render("^")
+	}
+	return strings.Repeat(" ", col) + theme.DeletedStyle.Render("^")
+}
+
 func (t *TextInputOverlay) renderFrame(content string) string {
 	// Calculate centered window dimensions
 	windowWidth := min(70, t.width-4)
 	windowHeight := 8
+	if t.area != nil {
+		windowHeight += textAreaRows - 1 // The single-line input takes one row
+	}
 
 	// Center the window
 	x := (t.width - windowWidth) / 2
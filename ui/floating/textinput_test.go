@@ -0,0 +1,36 @@
+package floating
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestTextAreaOverlayMultiline verifies the multi-line editor keeps enter as a newline
+func TestTextAreaOverlayMultiline(t *testing.T) {
+	o := NewTextAreaOverlay("Squash Message", "", "parent\n\nchild")
+	o.SetSize(100, 30)
+
+	o.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	o.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("more")})
+
+	if got, want := o.Value(), "parent\n\nchild\nmore"; got != want {
+		t.Errorf("Value() = %q, want %q", got, want)
+	}
+}
+
+// TestTextInputOverlayWarning verifies warnings show a caret and give way to statuses
+func TestTextInputOverlayWarning(t *testing.T) {
+	o := NewTextInputOverlay("Describe Change", "", "fix ")
+	o.SetSize(100, 30)
+
+	o.SetWarning("trailing whitespace", 3)
+	if !o.HasWarning() || !strings.Contains(o.View(), "^") {
+		t.Errorf("Expected the warning with a caret")
+	}
+	o.SetStatus("")
+	if o.HasWarning() {
+		t.Errorf("Expected SetStatus to clear the warning")
+	}
+}
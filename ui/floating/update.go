@@ -0,0 +1,186 @@
+package floating
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/borders"
+	"github.com/gerunddev/jjazy/ui/theme"
+)
+
+// UpdateOverlay reports the result of updating the stack from trunk,
+// listing the changes the rebase left conflicted
+type UpdateOverlay struct {
+	result *jj.UpdateResult
+	trunk  string // What the stack was rebased onto, e.g. main
+	cursor int
+	offset int // First conflicted change shown in the list
+	width  int
+	height int
+	ready  bool
+}
+
+// NewUpdateOverlay creates the report of result from jj.UpdateFromTrunk,
+// which rebased onto trunk
+func NewUpdateOverlay(result *jj.UpdateResult, trunk string) *UpdateOverlay {
+	return &UpdateOverlay{result: result, trunk: trunk}
+}
+
+func (u *UpdateOverlay) Init() tea.Cmd {
+	return nil
+}
+
+func (u *UpdateOverlay) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return u, nil
+	}
+
+	switch keyMsg.String() {
+	case "up", "ctrl+p":
+		if u.cursor > 0 {
+			u.cursor--
+		}
+	case "down", "ctrl+n":
+		if u.cursor < len(u.result.Conflicts)-1 {
+			u.cursor++
+		}
+	}
+	u.ensureCursorVisible()
+	return u, nil
+}
+
+// Selected returns the conflicted change under the cursor, or nil when the
+// update left no conflicts
+func (u *UpdateOverlay) Selected() *jj.ConflictedChange {
+	if u.cursor < len(u.result.Conflicts) {
+		return &u.result.Conflicts[u.cursor]
+	}
+	return nil
+}
+
+// listRows returns how many conflicted changes fit in the window
+func (u *UpdateOverlay) listRows() int {
+	return max(u.windowHeight()-7, 1) // Borders, summary, blank lines and the hint line
+}
+
+func (u *UpdateOverlay) ensureCursorVisible() {
+	rows := u.listRows()
+	if u.cursor < u.offset {
+		u.offset = u.cursor
+	} else if u.cursor >= u.offset+rows {
+		u.offset = u.cursor - rows + 1
+	}
+}
+
+// summary is the first line of the report
+func (u *UpdateOverlay) summary() string {
+	changes := "changes"
+	if u.result.Changes == 1 {
+		changes = "change"
+	}
+	text := fmt.Sprintf("Fetched and rebased %d %s onto %s.", u.result.Changes, changes, u.trunk)
+	switch len(u.result.Conflicts) {
+	case 0:
+		return text + " No conflicts."
+	case 1:
+		return text + " 1 change is conflicted:"
+	}
+	return text + fmt.Sprintf(" %d changes are conflicted:", len(u.result.Conflicts))
+}
+
+func (u *UpdateOverlay) View() string {
+	if !u.ready {
+		return u.renderFrame("Initializing...")
+	}
+
+	lines := []string{"", "  " + ansi.Truncate(u.summary(), max(u.windowWidth()-6, 1), "…"), ""}
+	end := min(u.offset+u.listRows(), len(u.result.Conflicts))
+	for i := u.offset; i < end; i++ {
+		lines = append(lines, "  "+u.renderConflict(u.result.Conflicts[i], i == u.cursor))
+	}
+
+	hint := "esc close"
+	if len(u.result.Conflicts) > 0 {
+		lines = append(lines, "")
+		hint = "↵ select  esc close"
+	}
+	lines = append(lines, "  "+theme.HelpDescStyle.Render(hint))
+	return u.renderFrame(strings.Join(lines, "\n"))
+}
+
+// renderConflict draws a conflicted change as its change ID, whether the
+// rebase conflicted it, and its description, cut to the window
+func (u *UpdateOverlay) renderConflict(change jj.ConflictedChange, selected bool) string {
+	origin := theme.DimmedStyle.Render("already")
+	if change.New {
+		origin = theme.DeletedStyle.Render("new    ")
+	}
+	text := change.Description
+	if text == "" {
+		text = "(no description)"
+	}
+	line := theme.ChangeIDStyle.Render(change.ChangeID) + "  " + origin + "  " + text
+	line = ansi.Truncate(line, max(u.windowWidth()-6, 1), "…")
+	if selected {
+		return theme.SelectedItemStyle.Render(ansi.Strip(line))
+	}
+	return line
+}
+
+func (u *UpdateOverlay) SetSize(width, height int) {
+	u.width = width
+	u.height = height
+	u.ready = true
+	u.ensureCursorVisible()
+}
+
+func (u *UpdateOverlay) windowWidth() int {
+	return min(90, u.width-4)
+}
+
+// windowHeight sizes the window to the report, within the screen
+func (u *UpdateOverlay) windowHeight() int {
+	return max(min(len(u.result.Conflicts)+7, u.height-4), 7)
+}
+
+func (u *UpdateOverlay) renderFrame(content string) string {
+	// Calculate centered window dimensions
+	windowWidth := u.windowWidth()
+	windowHeight := u.windowHeight()
+
+	// Center the window
+	x := (u.width - windowWidth) / 2
+	y := (u.height - windowHeight) / 2
+
+	borderStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.ColorYellow).
+		Width(windowWidth - 2).
+		Height(windowHeight - 2)
+
+	bordered := borderStyle.Render(content)
+
+	// Add title to top border
+	lines := strings.Split(bordered, "\n")
+	if len(lines) > 0 {
+		borderColorStyle := lipgloss.NewStyle().Foreground(theme.ColorYellow)
+		styledTitle := theme.FloatingTitleStyle.Render(" Update from " + u.trunk + " ")
+
+		remainingWidth := max(windowWidth-3-lipgloss.Width(styledTitle), 0)
+		lines[0] = borderColorStyle.Render(borders.TopLeft+borders.Horizontal) +
+			styledTitle +
+			borderColorStyle.Render(strings.Repeat(borders.Horizontal, remainingWidth)+borders.TopRight)
+	}
+
+	// Pad to center the window
+	paddingLeft := strings.Repeat(" ", x)
+	for i := range lines {
+		lines[i] = paddingLeft + lines[i]
+	}
+	return strings.Repeat("\n", y) + strings.Join(lines, "\n")
+}
@@ -0,0 +1,42 @@
+package floating
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/gerunddev/jjazy/jj"
+)
+
+// TestUpdateOverlay verifies the report lists conflicted changes, marking
+// those the rebase conflicted, and the cursor picks one
+func TestUpdateOverlay(t *testing.T) {
+	u := NewUpdateOverlay(&jj.UpdateResult{Changes: 3, Conflicts: []jj.ConflictedChange{
+		{ChangeID: "kmnopqrs", Description: "Add login", New: true},
+		{ChangeID: "zyxwvuts", Description: "Add users"},
+	}}, "main")
+	u.SetSize(100, 30)
+
+	view := ansi.Strip(u.View())
+	for _, want := range []string{"Update from main", "rebased 3 changes onto main. 2 changes are conflicted:", "kmnopqrs  new      Add login", "zyxwvuts  already  Add users"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in the report, got:\n%s", want, view)
+		}
+	}
+
+	u.Update(tea.KeyMsg{Type: tea.KeyDown})
+	u.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if got := u.Selected(); got == nil || got.ChangeID != "zyxwvuts" {
+		t.Errorf("Expected the cursor to stop on the last change, got %+v", got)
+	}
+
+	clean := NewUpdateOverlay(&jj.UpdateResult{Changes: 1}, "trunk()")
+	clean.SetSize(100, 30)
+	if view := ansi.Strip(clean.View()); !strings.Contains(view, "rebased 1 change onto trunk(). No conflicts.") {
+		t.Errorf("Expected a clean report, got:\n%s", view)
+	}
+	if clean.Selected() != nil {
+		t.Error("Expected nothing to select without conflicts")
+	}
+}
@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// These tests drive whole sessions against a temp repository built by
+// newFixtureRepo, and skip when jj isn't installed.
+
+// selectWorkingCopy checks the log opened on the working copy
+func selectWorkingCopy(t *testing.T, d *driver) {
+	t.Helper()
+	change := d.app.logPanel.SelectedChange()
+	if change == nil || !change.IsWorkingCopy {
+		t.Fatalf("expected the log to open on the working copy; screen:\n%s", d.app.View())
+	}
+}
+
+func TestFlowEnterChange(t *testing.T) {
+	d := newDriver(t, newFixtureRepo(t))
+	selectWorkingCopy(t, d)
+
+	d.press("right")
+	d.waitFor("the working copy's files", func() bool {
+		return d.app.currentExperience == ExperienceChange && d.app.filesPanel.Count() == 2
+	})
+	if d.app.focusedPanel != 1 {
+		t.Errorf("expected the files panel focused, got panel %d", d.app.focusedPanel)
+	}
+	view := d.app.View()
+	for _, name := range []string{"a.txt", "c.txt"} {
+		if !strings.Contains(view, name) {
+			t.Errorf("expected %s listed; screen:\n%s", name, view)
+		}
+	}
+
+	d.press("left")
+	d.waitFor("the log", func() bool { return d.app.currentExperience == ExperienceLog })
+}
+
+func TestFlowSquashFile(t *testing.T) {
+	dir := newFixtureRepo(t)
+	d := newDriver(t, dir)
+	selectWorkingCopy(t, d)
+
+	d.press("right")
+	d.waitFor("the working copy's files", func() bool { return d.app.filesPanel.Count() == 2 })
+	file := d.app.filesPanel.SelectedFile()
+	if file == nil {
+		t.Fatal("expected a selected file")
+	}
+	path := file.Path
+
+	d.press("s")
+	d.settle()
+	d.waitFor("the squashed file to leave the list", func() bool { return d.app.filesPanel.Count() == 1 })
+
+	if changed := strings.Fields(jjRun(t, dir, "diff", "-r", "@", "--name-only")); slices.Contains(changed, path) {
+		t.Errorf("expected %s squashed out of the working copy, still changed: %v", path, changed)
+	}
+	if changed := strings.Fields(jjRun(t, dir, "diff", "-r", "@-", "--name-only")); !slices.Contains(changed, path) {
+		t.Errorf("expected %s squashed into the parent, parent changes: %v", path, changed)
+	}
+}
+
+func TestFlowBookmarkSet(t *testing.T) {
+	dir := newFixtureRepo(t)
+	d := newDriver(t, dir)
+	selectWorkingCopy(t, d)
+	workingCopy := d.app.logPanel.SelectedChange().ChangeID
+
+	// Pick the bookmark, which selects where it points now: the parent
+	d.press("2", "enter")
+	if bm := d.app.bookmarksPanel.SelectedBookmark(); bm == nil || bm.Name != "feature" {
+		t.Fatalf("expected the feature bookmark selected; screen:\n%s", d.app.View())
+	}
+	d.press("enter")
+	if !d.app.bookmarkSetMode {
+		t.Fatalf("expected bookmark set mode; screen:\n%s", d.app.View())
+	}
+	if change := d.app.logPanel.SelectedChange(); change == nil || change.IsWorkingCopy {
+		t.Fatalf("expected the bookmark's revision selected; screen:\n%s", d.app.View())
+	}
+
+	// Move it forward to the working copy
+	d.press("up")
+	selectWorkingCopy(t, d)
+	d.press("enter")
+	d.settle()
+	if d.app.bookmarkSetMode {
+		t.Fatalf("expected bookmark set mode to end; screen:\n%s", d.app.View())
+	}
+
+	target := jjRun(t, dir, "log", "-r", "feature", "--no-graph", "-T", "change_id")
+	if !strings.HasPrefix(target, workingCopy) {
+		t.Errorf("expected feature on the working copy %s, got %s", workingCopy, target)
+	}
+}
@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/jj"
+)
+
+// startGitSync waits for the sync direction after G. Only colocated repos
+// share refs and HEAD with a git checkout, so elsewhere it just says so.
+func (a *App) startGitSync() tea.Cmd {
+	if a.git == nil || !a.git.Colocated {
+		return a.showToast("not a colocated git repo")
+	}
+	a.gitSyncPending = true
+	return nil
+}
+
+// handleGitSyncKey runs the sync chosen after G: i imports git's refs and
+// HEAD into jj, e exports jj's to git. Any other key cancels.
+func (a *App) handleGitSyncKey(msg tea.KeyMsg) tea.Cmd {
+	a.gitSyncPending = false
+
+	var sync func(context.Context, string) error
+	switch msg.String() {
+	case "i":
+		sync = jj.GitImport
+	case "e":
+		sync = jj.GitExport
+	default:
+		return nil
+	}
+	return a.runMutation(func(ctx context.Context) error {
+		return sync(ctx, a.repoPath)
+	}, a.refreshLogPanels)
+}
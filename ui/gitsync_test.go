@@ -0,0 +1,33 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/config"
+	"github.com/gerunddev/jjazy/jj"
+)
+
+// TestGitSyncPending verifies G only waits for a direction in colocated repos
+func TestGitSyncPending(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'G'}})
+	if a.gitSyncPending {
+		t.Fatal("Expected G to do nothing outside a colocated repo")
+	}
+	if a.toast != "not a colocated git repo" {
+		t.Errorf("Expected a toast explaining why, got %q", a.toast)
+	}
+
+	a.git = &jj.GitColocation{Colocated: true}
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'G'}})
+	if !a.gitSyncPending {
+		t.Fatal("Expected G to wait for import or export")
+	}
+	a.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if a.gitSyncPending || a.busy {
+		t.Error("Expected esc to cancel the sync without running it")
+	}
+}
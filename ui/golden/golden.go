@@ -0,0 +1,100 @@
+// Package golden compares rendered views against golden files in a test's
+// testdata directory, so layout and ANSI regressions show up as diffs.
+//
+// Run the tests with -update to rewrite the golden files after an
+// intended change, and review them like any other diff.
+package golden
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/gerunddev/jjazy/config"
+	"github.com/muesli/termenv"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files with the current output")
+
+// Freeze renders the theme the same on every machine for the rest of the
+// test: test mode, true color, a dark background
+func Freeze(t *testing.T) {
+	t.Helper()
+	t.Setenv(config.TestModeEnv, "1")
+
+	profile, dark := lipgloss.ColorProfile(), lipgloss.HasDarkBackground()
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	lipgloss.SetHasDarkBackground(true)
+	t.Cleanup(func() {
+		lipgloss.SetColorProfile(profile)
+		lipgloss.SetHasDarkBackground(dark)
+	})
+}
+
+// Assert checks view fits in width x height cells, then compares it with
+// testdata/<name>.golden
+func Assert(t *testing.T, name, view string, width, height int) {
+	t.Helper()
+	lines := strings.Split(view, "\n")
+	if len(lines) > height {
+		t.Errorf("%s: %d lines overflow the height of %d", name, len(lines), height)
+	}
+	for i, line := range lines {
+		if w := ansi.StringWidth(line); w > width {
+			t.Errorf("%s: line %d is %d cells, overflowing the width of %d: %q", name, i+1, w, width, ansi.Strip(line))
+		}
+	}
+
+	path := filepath.Join("testdata", name+".golden")
+	got := Escape(view)
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%s: %v (run the test with -update to create it)", name, err)
+	}
+	if diff := firstDiff(string(want), got); diff != "" {
+		t.Errorf("%s doesn't match %s (run with -update if the change is intended)\n%s", name, path, diff)
+	}
+}
+
+// Escape shows escape characters as \x1b so golden files stay readable
+// text, one rendered line per line
+func Escape(view string) string {
+	return strings.ReplaceAll(view, "\x1b", `\x1b`) + "\n"
+}
+
+// firstDiff describes the first line where got differs from want, or
+// returns "" when they match
+func firstDiff(want, got string) string {
+	if want == got {
+		return ""
+	}
+	wantLines, gotLines := strings.Split(want, "\n"), strings.Split(got, "\n")
+	for i := range max(len(wantLines), len(gotLines)) {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			return fmt.Sprintf("line %d:\nwant: %s\n got: %s", i+1, w, g)
+		}
+	}
+	return ""
+}
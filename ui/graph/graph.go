@@ -11,6 +11,9 @@ const (
 	SymbolWorkingCopy = "@"
 	SymbolCommit      = "○"
 	SymbolRoot        = "◆"
+	SymbolImmutable   = "◆"
+	SymbolHidden      = "◌"
+	SymbolLock        = "🔒"
 	SymbolVertical    = "│"
 	SymbolMergeLeft   = "├"
 	SymbolMergeRight  = "┤"
@@ -27,6 +30,9 @@ type RevisionInfo struct {
 	Parents       []string
 	IsWorkingCopy bool
 	IsRoot        bool
+	Divergent     bool
+	Hidden        bool
+	Immutable     bool
 }
 
 // GraphLine represents one line of the graph output.
@@ -199,6 +205,10 @@ func Simple(rev RevisionInfo, isLast bool, workingStyle, commitStyle, rootStyle,
 		graphChar = workingStyle.Render(SymbolWorkingCopy)
 	} else if rev.IsRoot {
 		graphChar = rootStyle.Render(SymbolRoot)
+	} else if rev.Hidden {
+		graphChar = commitStyle.Render(SymbolHidden)
+	} else if rev.Immutable {
+		graphChar = rootStyle.Render(SymbolImmutable)
 	} else {
 		graphChar = commitStyle.Render(SymbolCommit)
 	}
@@ -211,3 +221,20 @@ func Simple(rev RevisionInfo, isLast bool, workingStyle, commitStyle, rootStyle,
 
 	return graphChar, connector
 }
+
+// Markers renders tags for the divergent, hidden and immutable flags,
+// space separated, or "" when none are set. The root is immutable by
+// definition, so it isn't tagged.
+func Markers(rev RevisionInfo, divergentStyle, hiddenStyle, immutableStyle lipgloss.Style) string {
+	var tags []string
+	if rev.Divergent {
+		tags = append(tags, divergentStyle.Render("divergent"))
+	}
+	if rev.Hidden {
+		tags = append(tags, hiddenStyle.Render("hidden"))
+	}
+	if rev.Immutable && !rev.IsRoot {
+		tags = append(tags, immutableStyle.Render(SymbolLock))
+	}
+	return strings.Join(tags, " ")
+}
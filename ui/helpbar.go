@@ -11,9 +11,20 @@ import (
 type HelpBarContext struct {
 	Experience      Experience
 	FocusedPanel    int
-	Entered         bool // True if current panel is in "entered" mode
-	IsWorkingCopy   bool // True when viewing @ change
-	BookmarkSetMode bool // True when in bookmark set flow
+	Entered         bool       // True if current panel is in "entered" mode
+	IsWorkingCopy   bool       // True when viewing @ change
+	BookmarkSetMode bool       // True when in bookmark set flow
+	VisualMode      bool       // True when selecting a range in the log
+	RebaseMode      bool       // True when picking a rebase destination
+	SquashIntoMode  bool       // True when picking a squash destination
+	PickMode        bool       // True when running as a revision picker (--pick)
+	AtOperation     bool       // True when browsing the log at a past operation
+	FileHistory     bool       // True when the files sidebar shows a file's history
+	UntrackedFile   bool       // True when the selected working copy file is untracked
+	CopyPending     bool       // True after Y, while choosing what to copy
+	GitSyncPending  bool       // True after G, while choosing import or export
+	EnterOpens      bool       // True when Enter opens the Change view and → edits in the log
+	PanelHints      []HelpHint // Hints of the focused registered panel, if any
 }
 
 // HelpHint represents a single hint (key + description)
@@ -27,29 +38,93 @@ func (h HelpHint) Format() string {
 	return theme.HelpDescStyle.Render(h.Key + " " + h.Desc)
 }
 
+// editKey returns the log key that runs jj edit
+func (ctx HelpBarContext) editKey() string {
+	if ctx.EnterOpens {
+		return "→"
+	}
+	return "↵"
+}
+
+// viewKey returns the log key that opens the Change view
+func (ctx HelpBarContext) viewKey() string {
+	if ctx.EnterOpens {
+		return "↵"
+	}
+	return "→"
+}
+
 // getActionHints returns context-specific action hints (left section)
 func getActionHints(ctx HelpBarContext) []HelpHint {
+	if ctx.CopyPending {
+		return []HelpHint{
+			{Key: "c", Desc: "change ID"},
+			{Key: "i", Desc: "commit ID"},
+			{Key: "d", Desc: "diff"},
+		}
+	}
+	if ctx.GitSyncPending {
+		return []HelpHint{
+			{Key: "i", Desc: "import from git"},
+			{Key: "e", Desc: "export to git"},
+		}
+	}
+	if ctx.PanelHints != nil {
+		return ctx.PanelHints
+	}
+	if ctx.AtOperation && ctx.Experience != ExperienceOperations {
+		// Read-only: the only action is leaving
+		return []HelpHint{{Key: "@", Desc: "present"}}
+	}
 	switch ctx.Experience {
 	case ExperienceLog:
 		switch ctx.FocusedPanel {
 		case 0: // Log panel
+			if ctx.PickMode {
+				return []HelpHint{
+					{Key: "↵", Desc: "pick"},
+				}
+			}
 			if ctx.BookmarkSetMode {
 				return []HelpHint{
 					{Key: "↵", Desc: "set"},
 				}
 			}
+			if ctx.RebaseMode {
+				return []HelpHint{
+					{Key: "↵", Desc: "rebase"},
+					{Key: "r", Desc: "type destination"},
+				}
+			}
+			if ctx.SquashIntoMode {
+				return []HelpHint{
+					{Key: "↵", Desc: "squash here"},
+				}
+			}
+			if ctx.VisualMode {
+				return []HelpHint{
+					{Key: "a", Desc: "abandon"},
+					{Key: "r", Desc: "rebase"},
+					{Key: "s", Desc: "squash"},
+					{Key: "p", Desc: "parallelize"},
+					{Key: "x", Desc: "export"},
+				}
+			}
 			return []HelpHint{
-				{Key: "↵", Desc: "edit"},
+				{Key: ctx.editKey(), Desc: "edit"},
 				{Key: "n", Desc: "new"},
 				{Key: "d", Desc: "describe"},
 				{Key: "a", Desc: "abandon"},
 				{Key: "s", Desc: "squash"},
+				{Key: "r", Desc: "rebase"},
 			}
 		case 1: // Workspace panel
 			if ctx.Entered {
 				return []HelpHint{
 					{Key: "↵", Desc: "switch"},
 					{Key: "d", Desc: "forget"},
+					{Key: "E", Desc: "open"},
+					{Key: "i", Desc: "details"},
 				}
 			}
 			return []HelpHint{{Key: "a", Desc: "add"}}
@@ -58,41 +133,86 @@ func getActionHints(ctx HelpBarContext) []HelpHint {
 				return []HelpHint{
 					{Key: "↵", Desc: "set"},
 					{Key: "e", Desc: "edit"},
+					{Key: "c", Desc: "clean up"},
 				}
 			}
-			return nil
+			return []HelpHint{{Key: "c", Desc: "clean up"}}
 		}
 	case ExperienceChange:
 		switch ctx.FocusedPanel {
 		case 1: // Files panel
+			if ctx.PickMode {
+				return []HelpHint{
+					{Key: "space", Desc: "mark"},
+					{Key: "↵", Desc: "pick"},
+				}
+			}
+			if ctx.FileHistory {
+				return []HelpHint{
+					{Key: "↵", Desc: "show diff"},
+				}
+			}
+			if ctx.UntrackedFile {
+				return []HelpHint{
+					{Key: "t", Desc: "track"},
+					{Key: "i", Desc: "ignore"},
+				}
+			}
 			if ctx.IsWorkingCopy {
 				return []HelpHint{
 					{Key: "del", Desc: "discard"},  // PM feedback: "discard" clearer than "restore"
 					{Key: "s", Desc: "squash"},
+					{Key: "H", Desc: "history"},
 				}
 			}
-			return nil
+			return []HelpHint{
+				{Key: "H", Desc: "history"},
+			}
+		case 0: // Diff panel
+			return []HelpHint{{Key: "w", Desc: "wrap"}}
 		default:
 			return nil
 		}
+	case ExperienceOperations:
+		if ctx.FocusedPanel == 1 {
+			return []HelpHint{{Key: "↵", Desc: "details"}}
+		}
+		return []HelpHint{{Key: "↵", Desc: "browse"}}
 	}
 	return nil
 }
 
 // getNavigationHints returns context-specific navigation hints (center section)
 func getNavigationHints(ctx HelpBarContext) []HelpHint {
+	if ctx.CopyPending || ctx.GitSyncPending {
+		return []HelpHint{{Key: "esc", Desc: "cancel"}}
+	}
 	switch ctx.Experience {
 	case ExperienceLog:
 		switch ctx.FocusedPanel {
 		case 0: // Log panel
-			if ctx.BookmarkSetMode {
+			if ctx.BookmarkSetMode || ctx.RebaseMode || ctx.SquashIntoMode {
 				return []HelpHint{
 					{Key: "←", Desc: "cancel"},
 					{Key: "↑↓", Desc: "select"},
 				}
 			}
+			if ctx.VisualMode {
+				return []HelpHint{
+					{Key: "V", Desc: "exit"},
+					{Key: "↑↓", Desc: "extend"},
+				}
+			}
+			if ctx.AtOperation {
+				return []HelpHint{
+					{Key: ctx.viewKey(), Desc: "view"},
+					{Key: "z", Desc: "preview"},
+				}
+			}
 			return []HelpHint{
-				{Key: "→", Desc: "view"},
+				{Key: ctx.viewKey(), Desc: "view"},
+				{Key: "V", Desc: "range"},
+				{Key: "z", Desc: "preview"},
 			}
 		case 1: // Workspace panel
 			if ctx.Entered {
@@ -123,12 +243,33 @@ func getNavigationHints(ctx HelpBarContext) []HelpHint {
 				{Key: "↑↓", Desc: "scroll"},
 			}
 		case 1: // Files panel
+			if ctx.FileHistory {
+				return []HelpHint{
+					{Key: "←", Desc: "files"},
+					{Key: "→", Desc: "diff"},
+					{Key: "↑↓", Desc: "select"},
+				}
+			}
 			return []HelpHint{
 				{Key: "←", Desc: "exit"},
 				{Key: "→", Desc: "diff"},
 				{Key: "↑↓", Desc: "select"},
 			}
 		}
+	case ExperienceOperations:
+		switch ctx.FocusedPanel {
+		case 0: // Timeline panel
+			return []HelpHint{
+				{Key: "←→", Desc: "scrub"},
+				{Key: "esc", Desc: "back"},
+			}
+		case 1: // Operations panel
+			return []HelpHint{
+				{Key: "←", Desc: "exit"},
+				{Key: "→", Desc: "timeline"},
+				{Key: "↑↓", Desc: "select"},
+			}
+		}
 	}
 	return nil
 }
@@ -49,8 +49,8 @@ func TestGetActionHintsExperienceChangeFilesPanel(t *testing.T) {
 				IsWorkingCopy: true,
 			},
 			expectHints:   true,
-			expectedCount: 2,
-			expectedKeys:  []string{"del", "s"},
+			expectedCount: 3,
+			expectedKeys:  []string{"del", "s", "H"},
 		},
 		{
 			name: "Non-working copy with files panel focused",
@@ -61,7 +61,29 @@ func TestGetActionHintsExperienceChangeFilesPanel(t *testing.T) {
 				IsWorkingCopy: false,
 			},
 			expectHints:   false,
-			expectedCount: 0,
+			expectedCount: 1, // history
+		},
+		{
+			name: "File history with files panel focused",
+			ctx: HelpBarContext{
+				Experience:    ExperienceChange,
+				FocusedPanel:  1,
+				IsWorkingCopy: true,
+				FileHistory:   true,
+			},
+			expectHints:   false,
+			expectedCount: 1, // show diff
+		},
+		{
+			name: "Untracked working copy file with files panel focused",
+			ctx: HelpBarContext{
+				Experience:    ExperienceChange,
+				FocusedPanel:  1,
+				IsWorkingCopy: true,
+				UntrackedFile: true,
+			},
+			expectHints:   false,
+			expectedCount: 2, // track, ignore
 		},
 		{
 			name: "Working copy with diff panel focused",
@@ -72,7 +94,7 @@ func TestGetActionHintsExperienceChangeFilesPanel(t *testing.T) {
 				IsWorkingCopy: true,
 			},
 			expectHints:   false,
-			expectedCount: 0,
+			expectedCount: 1, // wrap
 		},
 	}
 
@@ -122,7 +144,7 @@ func TestGetActionHintsExperienceLog(t *testing.T) {
 				Entered:       false,
 				IsWorkingCopy: false,
 			},
-			expectedCount: 5, // edit, new, describe, abandon, squash
+			expectedCount: 6, // edit, new, describe, abandon, squash, rebase
 		},
 		{
 			name: "Log panel in bookmark set mode",
@@ -135,6 +157,51 @@ func TestGetActionHintsExperienceLog(t *testing.T) {
 			},
 			expectedCount: 1, // set
 		},
+		{
+			name: "Log panel in visual mode",
+			ctx: HelpBarContext{
+				Experience:   ExperienceLog,
+				FocusedPanel: 0,
+				VisualMode:   true,
+			},
+			expectedCount: 5, // abandon, rebase, squash, parallelize, export
+		},
+		{
+			name: "Log panel in rebase mode",
+			ctx: HelpBarContext{
+				Experience:   ExperienceLog,
+				FocusedPanel: 0,
+				RebaseMode:   true,
+			},
+			expectedCount: 2, // rebase, type destination
+		},
+		{
+			name: "Log panel in squash into mode",
+			ctx: HelpBarContext{
+				Experience:     ExperienceLog,
+				FocusedPanel:   0,
+				SquashIntoMode: true,
+			},
+			expectedCount: 1, // squash here
+		},
+		{
+			name: "Log panel at a past operation",
+			ctx: HelpBarContext{
+				Experience:   ExperienceLog,
+				FocusedPanel: 0,
+				AtOperation:  true,
+			},
+			expectedCount: 1, // present
+		},
+		{
+			name: "Log panel in pick mode",
+			ctx: HelpBarContext{
+				Experience:   ExperienceLog,
+				FocusedPanel: 0,
+				PickMode:     true,
+			},
+			expectedCount: 1, // pick
+		},
 		{
 			name: "Workspace panel not entered",
 			ctx: HelpBarContext{
@@ -153,7 +220,7 @@ func TestGetActionHintsExperienceLog(t *testing.T) {
 				Entered:       true,
 				IsWorkingCopy: false,
 			},
-			expectedCount: 2, // switch, forget
+			expectedCount: 4, // switch, forget, open, details
 		},
 		{
 			name: "Bookmarks panel not entered",
@@ -163,7 +230,7 @@ func TestGetActionHintsExperienceLog(t *testing.T) {
 				Entered:       false,
 				IsWorkingCopy: false,
 			},
-			expectedCount: 0,
+			expectedCount: 1, // clean up
 		},
 		{
 			name: "Bookmarks panel entered",
@@ -173,7 +240,7 @@ func TestGetActionHintsExperienceLog(t *testing.T) {
 				Entered:       true,
 				IsWorkingCopy: false,
 			},
-			expectedCount: 2, // set, edit
+			expectedCount: 3, // set, edit, clean up
 		},
 	}
 
@@ -187,6 +254,17 @@ func TestGetActionHintsExperienceLog(t *testing.T) {
 	}
 }
 
+// TestGetActionHintsExperienceOperations verifies Enter browses from the
+// timeline and shows details from the operations list
+func TestGetActionHintsExperienceOperations(t *testing.T) {
+	for panel, want := range map[int]string{0: "browse", 1: "details"} {
+		hints := getActionHints(HelpBarContext{Experience: ExperienceOperations, FocusedPanel: panel})
+		if len(hints) != 1 || hints[0].Desc != want {
+			t.Errorf("panel %d: hints = %v, want one %q hint", panel, hints, want)
+		}
+	}
+}
+
 // TestGetNavigationHints tests navigation hints for different experiences
 func TestGetNavigationHints(t *testing.T) {
 	tests := []struct {
@@ -202,7 +280,7 @@ func TestGetNavigationHints(t *testing.T) {
 				Entered:       false,
 				IsWorkingCopy: false,
 			},
-			expectedCount: 1,
+			expectedCount: 3, // view, range, preview
 		},
 		{
 			name: "Log panel in bookmark set mode",
@@ -255,6 +333,31 @@ func TestGetNavigationHints(t *testing.T) {
 			},
 			expectedCount: 3,
 		},
+		{
+			name: "Log panel at a past operation",
+			ctx: HelpBarContext{
+				Experience:   ExperienceLog,
+				FocusedPanel: 0,
+				AtOperation:  true,
+			},
+			expectedCount: 2, // view, preview
+		},
+		{
+			name: "Timeline in Operations experience",
+			ctx: HelpBarContext{
+				Experience:   ExperienceOperations,
+				FocusedPanel: 0,
+			},
+			expectedCount: 2, // scrub, back
+		},
+		{
+			name: "Operations list in Operations experience",
+			ctx: HelpBarContext{
+				Experience:   ExperienceOperations,
+				FocusedPanel: 1,
+			},
+			expectedCount: 3, // exit, timeline, select
+		},
 	}
 
 	for _, tt := range tests {
@@ -267,6 +370,26 @@ func TestGetNavigationHints(t *testing.T) {
 	}
 }
 
+// TestEnterOpensSwapsLogHints verifies the log hints follow the Enter/→ scheme
+func TestEnterOpensSwapsLogHints(t *testing.T) {
+	for _, tt := range []struct {
+		enterOpens bool
+		editKey    string
+		viewKey    string
+	}{
+		{enterOpens: false, editKey: "↵", viewKey: "→"},
+		{enterOpens: true, editKey: "→", viewKey: "↵"},
+	} {
+		ctx := HelpBarContext{Experience: ExperienceLog, FocusedPanel: 0, EnterOpens: tt.enterOpens}
+		if edit := getActionHints(ctx)[0]; edit.Key != tt.editKey || edit.Desc != "edit" {
+			t.Errorf("EnterOpens=%v: expected %s edit, got %s %s", tt.enterOpens, tt.editKey, edit.Key, edit.Desc)
+		}
+		if view := getNavigationHints(ctx)[0]; view.Key != tt.viewKey || view.Desc != "view" {
+			t.Errorf("EnterOpens=%v: expected %s view, got %s %s", tt.enterOpens, tt.viewKey, view.Key, view.Desc)
+		}
+	}
+}
+
 // TestGetAlwaysHints tests the always-visible hints
 func TestGetAlwaysHints(t *testing.T) {
 	hints := getAlwaysHints()
@@ -434,10 +557,40 @@ func TestWorkingCopyDetection(t *testing.T) {
 				IsWorkingCopy: tt.isWorkingCopy,
 			}
 			hints := getActionHints(ctx)
-			hasHints := len(hints) > 0
+			hasHints := false
+			for _, hint := range hints {
+				if hint.Key == "del" || hint.Key == "s" {
+					hasHints = true
+				}
+			}
 			if hasHints != tt.expectHints {
 				t.Errorf("Expected hints=%v, got hints=%v (count=%d)", tt.expectHints, hasHints, len(hints))
 			}
 		})
 	}
 }
+
+// TestHelpSectionsCoverContexts verifies every documented context has hints
+// and the keymap groups come first
+func TestHelpSectionsCoverContexts(t *testing.T) {
+	keys := DefaultKeyMap()
+	sections := helpSections(keys)
+
+	groups := keys.HelpGroups()
+	if len(sections) != len(groups)+len(helpContexts) {
+		t.Fatalf("Expected %d sections, got %d", len(groups)+len(helpContexts), len(sections))
+	}
+	for i, group := range groups {
+		if sections[i].Title != group.Title {
+			t.Errorf("Section %d: expected title %q, got %q", i, group.Title, sections[i].Title)
+		}
+		if len(sections[i].Entries) != len(group.Bindings) {
+			t.Errorf("Section %q: expected %d entries, got %d", group.Title, len(group.Bindings), len(sections[i].Entries))
+		}
+	}
+	for _, section := range sections[len(groups):] {
+		if len(section.Entries) == 0 {
+			t.Errorf("Context section %q has no hints", section.Title)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"context"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/floating"
+)
+
+// helpContext names a UI state whose help bar hints appear on the help screen
+type helpContext struct {
+	Title string
+	Ctx   HelpBarContext
+}
+
+// helpContexts lists the states documented on the help screen, in the order
+// a user meets them
+var helpContexts = []helpContext{
+	{"Log panel", HelpBarContext{Experience: ExperienceLog, FocusedPanel: 0}},
+	{"Log range selection", HelpBarContext{Experience: ExperienceLog, FocusedPanel: 0, VisualMode: true}},
+	{"Rebase destination", HelpBarContext{Experience: ExperienceLog, FocusedPanel: 0, RebaseMode: true}},
+	{"Squash destination", HelpBarContext{Experience: ExperienceLog, FocusedPanel: 0, SquashIntoMode: true}},
+	{"Bookmark set", HelpBarContext{Experience: ExperienceLog, FocusedPanel: 0, BookmarkSetMode: true}},
+	{"Workspace panel", HelpBarContext{Experience: ExperienceLog, FocusedPanel: 1}},
+	{"Workspace list", HelpBarContext{Experience: ExperienceLog, FocusedPanel: 1, Entered: true}},
+	{"Bookmarks list", HelpBarContext{Experience: ExperienceLog, FocusedPanel: 2, Entered: true}},
+	{"Files (working copy)", HelpBarContext{Experience: ExperienceChange, FocusedPanel: 1, IsWorkingCopy: true}},
+	{"Untracked file", HelpBarContext{Experience: ExperienceChange, FocusedPanel: 1, IsWorkingCopy: true, UntrackedFile: true}},
+	{"File history", HelpBarContext{Experience: ExperienceChange, FocusedPanel: 1, FileHistory: true}},
+	{"Diff", HelpBarContext{Experience: ExperienceChange, FocusedPanel: 0}},
+	{"Copy to clipboard", HelpBarContext{Experience: ExperienceLog, FocusedPanel: 0, CopyPending: true}},
+	{"Operation timeline", HelpBarContext{Experience: ExperienceOperations, FocusedPanel: 0}},
+	{"Log at a past operation", HelpBarContext{Experience: ExperienceLog, FocusedPanel: 0, AtOperation: true}},
+}
+
+// helpSections builds the help screen from the keymap and the contextual
+// help bar hints, so it always matches the real bindings
+func helpSections(keys KeyMap) []floating.HelpSection {
+	var sections []floating.HelpSection
+
+	for _, group := range keys.HelpGroups() {
+		section := floating.HelpSection{Title: group.Title}
+		for _, binding := range group.Bindings {
+			h := binding.Help()
+			section.Entries = append(section.Entries, floating.HelpEntry{Key: h.Key, Desc: h.Desc})
+		}
+		sections = append(sections, section)
+	}
+
+	for _, hc := range helpContexts {
+		section := floating.HelpSection{Title: hc.Title}
+		hints := append(getActionHints(hc.Ctx), getNavigationHints(hc.Ctx)...)
+		for _, hint := range hints {
+			section.Entries = append(section.Entries, floating.HelpEntry{Key: hint.Key, Desc: hint.Desc})
+		}
+		sections = append(sections, section)
+	}
+
+	return sections
+}
+
+// aliasHelpSections lists the jj config aliases, so they are as discoverable
+// here as with `jj help`
+func aliasHelpSections(ctx context.Context, repoPath string) []floating.HelpSection {
+	var sections []floating.HelpSection
+
+	if aliases, err := jj.RevsetAliases(ctx, repoPath); err == nil && len(aliases) > 0 {
+		sections = append(sections, aliasSection("Revset aliases (tab completes)", aliases))
+	}
+	if aliases, err := jj.CommandAliases(ctx, repoPath); err == nil && len(aliases) > 0 {
+		sections = append(sections, aliasSection("jj command aliases", aliases))
+	}
+
+	return sections
+}
+
+func aliasSection(title string, aliases []jj.Alias) floating.HelpSection {
+	section := floating.HelpSection{Title: title}
+	for _, alias := range aliases {
+		section.Entries = append(section.Entries, floating.HelpEntry{Key: alias.Name, Desc: alias.Definition})
+	}
+	return section
+}
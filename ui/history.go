@@ -0,0 +1,131 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/ui/floating"
+)
+
+// maxActionHistory is how many actions the action log keeps
+const maxActionHistory = 100
+
+// actionEntry is a mutating action run from the keyboard
+type actionEntry struct {
+	Name       string     // What ran, e.g. "squash file"
+	Subject    string     // Change ID or file path it ran against
+	Key        tea.KeyMsg // Key that ran it, replayed by .
+	Experience Experience // Where the key was pressed
+	Panel      int
+	At         time.Time
+	Done       bool  // The mutation finished
+	Err        error // Set if it failed
+}
+
+// String formats the entry as a row: time, action, subject and outcome
+func (e actionEntry) String() string {
+	status := "…"
+	switch {
+	case e.Err != nil:
+		status = "✗ " + e.Err.Error()
+	case e.Done:
+		status = "✓"
+	}
+	return fmt.Sprintf("%s  %s %s  %s", e.At.Format("15:04:05"), e.Name, e.Subject, status)
+}
+
+// actionHistory records mutating actions for . and the action log
+type actionHistory struct {
+	entries []actionEntry // Oldest first
+}
+
+// Record adds an action whose mutation just started
+func (h *actionHistory) Record(e actionEntry) {
+	h.entries = append(h.entries, e)
+	if len(h.entries) > maxActionHistory {
+		h.entries = h.entries[len(h.entries)-maxActionHistory:]
+	}
+}
+
+// Finish marks the most recent action as done
+func (h *actionHistory) Finish(err error) {
+	if last := h.Last(); last != nil {
+		last.Done = true
+		last.Err = err
+	}
+}
+
+// Last returns the most recent action, or nil if none ran yet
+func (h *actionHistory) Last() *actionEntry {
+	if len(h.entries) == 0 {
+		return nil
+	}
+	return &h.entries[len(h.entries)-1]
+}
+
+// recordAction notes the action a key is about to run. It joins the
+// history when its mutation starts, which for a squash may be after a
+// message prompt; if that is cancelled, nothing is recorded.
+func (a *App) recordAction(name, subject string, msg tea.KeyMsg) {
+	a.pendingAction = &actionEntry{
+		Name:       name,
+		Subject:    subject,
+		Key:        msg,
+		Experience: a.currentExperience,
+		Panel:      a.focusedPanel,
+		At:         time.Now(),
+	}
+}
+
+// repeatLastAction replays the key of the last action against the current
+// selection. It only works where that key was pressed, since the same key
+// means something else in other panels.
+func (a *App) repeatLastAction() tea.Cmd {
+	last := a.history.Last()
+	if last == nil {
+		return a.showToast("nothing to repeat")
+	}
+	if last.Experience != a.currentExperience || last.Panel != a.focusedPanel {
+		return a.showToast("can't repeat " + last.Name + " here")
+	}
+	_, cmd := a.Update(last.Key)
+	return cmd
+}
+
+// openActionLog lists the recorded actions, newest first
+func (a *App) openActionLog() {
+	var rows []string
+	for i := len(a.history.entries) - 1; i >= 0; i-- {
+		rows = append(rows, a.history.entries[i].String())
+	}
+	a.actionLogOverlay = floating.NewActionLogOverlay(rows)
+	a.actionLogOverlay.SetSize(a.width, a.height-1)
+	a.showActionLog = true
+}
+
+// handleActionLogKey scrolls the action log or closes it. . closes it and
+// repeats the newest action.
+func (a *App) handleActionLogKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "escape", "ctrl+g", "ctrl+c", "q", "L":
+		a.closeActionLog()
+		return nil
+	case ".":
+		a.closeActionLog()
+		return a.repeatLastAction()
+	default:
+		_, cmd := a.actionLogOverlay.Update(msg)
+		return cmd
+	}
+}
+
+// closeActionLog hides the action log
+func (a *App) closeActionLog() {
+	a.showActionLog = false
+	a.actionLogOverlay = nil
+}
+
+func (a *App) overlayActionLog(background string) string {
+	return a.composite(background, a.actionLogOverlay.View())
+}
@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/config"
+	"github.com/gerunddev/jjazy/ui/messages"
+)
+
+// TestActionHistoryRecordsRunningMutations verifies an action joins the
+// history when its mutation starts and records how it finished
+func TestActionHistoryRecordsRunningMutations(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	key := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}}
+	a.recordAction("abandon", "abcdefgh", key)
+	if a.history.Last() != nil {
+		t.Fatal("Expected nothing in the history before the mutation runs")
+	}
+
+	a.runMutation(func(ctx context.Context) error { return nil }, nil)
+	last := a.history.Last()
+	if last == nil || last.Name != "abandon" || last.Subject != "abcdefgh" || last.Done {
+		t.Fatalf("Expected a running abandon entry, got %+v", last)
+	}
+
+	a.Update(messages.MutationDoneMsg{Err: errors.New("boom")})
+	if last := a.history.Last(); !last.Done || last.Err == nil {
+		t.Errorf("Expected the entry to finish with the error, got %+v", last)
+	}
+
+	// Unrecorded mutations leave the history alone
+	a.runMutation(func(ctx context.Context) error { return nil }, nil)
+	a.Update(messages.MutationDoneMsg{})
+	if len(a.history.entries) != 1 || a.history.Last().Err == nil {
+		t.Errorf("Expected an unrecorded mutation not to touch the history, got %+v", a.history.entries)
+	}
+}
+
+// TestActionHistoryCap verifies old actions are dropped
+func TestActionHistoryCap(t *testing.T) {
+	var h actionHistory
+	for i := 0; i < maxActionHistory+5; i++ {
+		h.Record(actionEntry{Name: "new after"})
+	}
+	if len(h.entries) != maxActionHistory {
+		t.Errorf("Expected %d entries, got %d", maxActionHistory, len(h.entries))
+	}
+}
+
+// TestRepeatLastAction verifies . only replays where the action ran
+func TestRepeatLastAction(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	dot := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'.'}}
+	a.Update(dot)
+	if a.toast != "nothing to repeat" {
+		t.Errorf("Expected a toast with no history, got %q", a.toast)
+	}
+
+	a.history.Record(actionEntry{Name: "squash file", Experience: ExperienceChange, Panel: 1,
+		Key: tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}}})
+	a.Update(dot)
+	if a.toast != "can't repeat squash file here" || a.busy {
+		t.Errorf("Expected . to refuse outside the files panel, got toast %q", a.toast)
+	}
+}
+
+// TestActionLogOverlay verifies L opens the log and esc closes it
+func TestActionLogOverlay(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	a.history.Record(actionEntry{Name: "abandon", Subject: "abcdefgh", Done: true})
+
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'L'}})
+	if !a.showActionLog {
+		t.Fatal("Expected L to open the action log")
+	}
+	a.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if a.showActionLog {
+		t.Error("Expected esc to close the action log")
+	}
+}
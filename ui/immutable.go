@@ -0,0 +1,33 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// rewritesSelection reports whether a log key rewrites the selected
+// change or range (new changes and edits are checked elsewhere)
+func (a *App) rewritesSelection(msg tea.KeyMsg) bool {
+	return key.Matches(msg, a.keys.Describe, a.keys.Abandon, a.keys.SquashChange,
+		a.keys.SquashInto, a.keys.Rebase, a.keys.Parallelize, a.keys.EditAuthor, a.keys.Sign)
+}
+
+// blockImmutable explains and returns true when a rewriting key is pressed
+// on an immutable change, or on a range containing one
+func (a *App) blockImmutable(msg tea.KeyMsg) bool {
+	if !a.rewritesSelection(msg) {
+		return false
+	}
+	for _, change := range a.logPanel.SelectedRange() {
+		if change.Immutable {
+			a.showInfoDialog("Immutable Revision", fmt.Sprintf(
+				"%s is immutable, so jj won't rewrite it. Revisions in trunk(), "+
+					"tags and the immutable_heads() revset are protected; "+
+					"create a new change on top of it instead.", change.ChangeID))
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/floating"
+)
+
+// jumpTargets lists the working copy, trunk and root, then every bookmark
+func (a *App) jumpTargets() []floating.JumpTarget {
+	targets := []floating.JumpTarget{
+		{Label: "@ working copy", Revset: "@"},
+		{Label: "trunk()", Revset: "trunk()"},
+		{Label: "root()", Revset: "root()"},
+	}
+	for _, name := range a.bookmarksPanel.BookmarkNames() {
+		targets = append(targets, floating.JumpTarget{Label: name, Revset: strconv.Quote(name)})
+	}
+	return targets
+}
+
+// openJump shows the jump list over the log
+func (a *App) openJump() {
+	a.jumpOverlay = floating.NewJumpOverlay(a.jumpTargets())
+	a.jumpOverlay.SetSize(a.width, a.height-1)
+	a.showJump = true
+}
+
+// handleJumpKey handles a key in the jump list. Enter selects the picked
+// target's revision in the log.
+func (a *App) handleJumpKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "escape", "ctrl+g", "ctrl+c":
+		a.closeJump()
+		return nil
+	case "enter":
+		target := a.jumpOverlay.Selected()
+		if target == nil {
+			return nil
+		}
+		a.closeJump()
+		return a.jumpTo(*target)
+	default:
+		_, cmd := a.jumpOverlay.Update(msg)
+		return cmd
+	}
+}
+
+// jumpTo selects the revision a target resolves to and focuses the log
+func (a *App) jumpTo(target floating.JumpTarget) tea.Cmd {
+	changeID, err := jj.ChangeIDOf(a.readCtx(), a.repoPath, target.Revset)
+	if err != nil {
+		a.showErrorDialog(err)
+		return nil
+	}
+	if !a.logPanel.SelectByChangeID(changeID) {
+		return a.showToast(target.Label + " is not in the log")
+	}
+	a.bookmarksPanel.SetEntered(false)
+	a.setFocus(0)
+	return nil
+}
+
+// closeJump hides the jump list
+func (a *App) closeJump() {
+	a.showJump = false
+	a.jumpOverlay = nil
+}
+
+func (a *App) overlayJump(background string) string {
+	return a.composite(background, a.jumpOverlay.View())
+}
@@ -35,8 +35,94 @@ type KeyMap struct {
 	// Log view change actions
 	NewChange  key.Binding
 	Describe   key.Binding
+	Commit       key.Binding
 	Abandon    key.Binding
 	SquashChange key.Binding
+	SquashInto   key.Binding
+	CreateTag    key.Binding
+	EditAuthor   key.Binding
+	Sign         key.Binding
+	Backout      key.Binding
+
+	// Log visual (range) mode
+	VisualMode    key.Binding
+	Rebase        key.Binding
+	Parallelize   key.Binding
+	ExportPatches key.Binding
+
+	// Log navigation
+	Jump       key.Binding
+	GoToChange key.Binding
+	LogFilter  key.Binding
+	Details    key.Binding
+
+	// Log warnings
+	Divergent key.Binding
+
+	// Stacked changes between trunk and @
+	Stack            key.Binding
+	UpdateFromTrunk  key.Binding
+	AdvanceBookmarks key.Binding
+
+	// Workspace panel
+	OpenWorkspace key.Binding
+	WorkspaceInfo key.Binding
+
+	// Bookmarks panel
+	CleanupBookmarks key.Binding
+
+	// Log experience layout
+	TogglePreview key.Binding
+	ReverseLog    key.Binding
+
+	// Operations experience
+	Operations key.Binding
+	Present    key.Binding
+
+	// Change experience
+	ToggleMessage key.Binding
+	FileHistory   key.Binding
+	SortFiles     key.Binding
+	TrackFile     key.Binding
+	IgnoreFile    key.Binding
+	LoadFullDiff  key.Binding
+	WrapDiff      key.Binding
+	IgnoreSpace   key.Binding
+	LessContext   key.Binding
+	MoreContext   key.Binding
+
+	// Clipboard
+	Copy key.Binding
+
+	// Action history
+	Repeat    key.Binding
+	ActionLog key.Binding
+
+	// Notification log
+	Notifications key.Binding
+
+	// Context menu of the selected item's actions
+	ContextMenu key.Binding
+
+	// Debugging: bridge latency table and the operation log
+	Metrics  key.Binding
+	DebugLog key.Binding
+
+	// Colocated git
+	GitSync key.Binding
+
+	// Working copy
+	Snapshot         key.Binding
+	NewOnWorkingCopy key.Binding
+
+	// Release notes
+	Changelog key.Binding
+
+	// Panel resizing
+	GrowSidebar     key.Binding
+	ShrinkSidebar   key.Binding
+	GrowWorkspace   key.Binding
+	ShrinkWorkspace key.Binding
 }
 
 // DefaultKeyMap returns the default keybindings
@@ -59,15 +145,15 @@ func DefaultKeyMap() KeyMap {
 		// Panel navigation
 		Panel0: key.NewBinding(
 			key.WithKeys("0"),
-			key.WithHelp("0", "log"),
+			key.WithHelp("0", "log/diff"),
 		),
 		Panel1: key.NewBinding(
 			key.WithKeys("1"),
-			key.WithHelp("1", "workspace"),
+			key.WithHelp("1", "workspace/files"),
 		),
 		Panel2: key.NewBinding(
 			key.WithKeys("2"),
-			key.WithHelp("2", "files"),
+			key.WithHelp("2", "bookmarks"),
 		),
 		Panel3: key.NewBinding(
 			key.WithKeys("3"),
@@ -111,7 +197,7 @@ func DefaultKeyMap() KeyMap {
 		// Actions
 		Enter: key.NewBinding(
 			key.WithKeys("enter"),
-			key.WithHelp("enter", "select"),
+			key.WithHelp("enter", "edit/select"),
 		),
 		Space: key.NewBinding(
 			key.WithKeys(" "),
@@ -135,6 +221,10 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("d"),
 			key.WithHelp("d", "describe"),
 		),
+		Commit: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "commit @"),
+		),
 		Abandon: key.NewBinding(
 			key.WithKeys("a"),
 			key.WithHelp("a", "abandon"),
@@ -143,6 +233,236 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("s"),
 			key.WithHelp("s", "squash"),
 		),
+		SquashInto: key.NewBinding(
+			key.WithKeys("S"),
+			key.WithHelp("S", "squash into…"),
+		),
+		CreateTag: key.NewBinding(
+			key.WithKeys("T"),
+			key.WithHelp("T", "create tag"),
+		),
+		EditAuthor: key.NewBinding(
+			key.WithKeys("A"),
+			key.WithHelp("A", "edit author"),
+		),
+		Sign: key.NewBinding(
+			key.WithKeys("K"),
+			key.WithHelp("K", "sign"),
+		),
+		Backout: key.NewBinding(
+			key.WithKeys("B"),
+			key.WithHelp("B", "back out"),
+		),
+
+		// Log visual (range) mode
+		VisualMode: key.NewBinding(
+			key.WithKeys("V"),
+			key.WithHelp("V", "range select"),
+		),
+		Rebase: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "rebase"),
+		),
+		Parallelize: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "parallelize range"),
+		),
+		ExportPatches: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "export patches"),
+		),
+
+		// Log navigation
+		Jump: key.NewBinding(
+			key.WithKeys("'"),
+			key.WithHelp("'", "jump to…"),
+		),
+		GoToChange: key.NewBinding(
+			key.WithKeys("g"),
+			key.WithHelp("g", "go to change ID…"),
+		),
+		LogFilter: key.NewBinding(
+			key.WithKeys("F"),
+			key.WithHelp("F", "filter log…"),
+		),
+		Details: key.NewBinding(
+			key.WithKeys("i"),
+			key.WithHelp("i", "revision details"),
+		),
+
+		// Log warnings
+		Divergent: key.NewBinding(
+			key.WithKeys("!"),
+			key.WithHelp("!", "divergent changes"),
+		),
+
+		// Stacked changes between trunk and @
+		Stack: key.NewBinding(
+			key.WithKeys("P"),
+			key.WithHelp("P", "stack view"),
+		),
+		UpdateFromTrunk: key.NewBinding(
+			key.WithKeys("U"),
+			key.WithHelp("U", "update from trunk"),
+		),
+		AdvanceBookmarks: key.NewBinding(
+			key.WithKeys("M"),
+			key.WithHelp("M", "auto-advance bookmarks"),
+		),
+
+		// Workspace panel
+		OpenWorkspace: key.NewBinding(
+			key.WithKeys("E"),
+			key.WithHelp("E", "open workspace"),
+		),
+		WorkspaceInfo: key.NewBinding(
+			key.WithKeys("i"),
+			key.WithHelp("i", "workspace details"),
+		),
+
+		// Bookmarks panel
+		CleanupBookmarks: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "clean up bookmarks"),
+		),
+
+		// Log experience layout
+		TogglePreview: key.NewBinding(
+			key.WithKeys("z"),
+			key.WithHelp("z", "preview"),
+		),
+		ReverseLog: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "reverse log order"),
+		),
+
+		// Operations experience
+		Operations: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "operation timeline"),
+		),
+		Present: key.NewBinding(
+			key.WithKeys("@"),
+			key.WithHelp("@", "back to present"),
+		),
+
+		// Change experience
+		ToggleMessage: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "collapse message"),
+		),
+		FileHistory: key.NewBinding(
+			key.WithKeys("H"),
+			key.WithHelp("H", "file history"),
+		),
+		SortFiles: key.NewBinding(
+			key.WithKeys("O"),
+			key.WithHelp("O", "sort files"),
+		),
+		TrackFile: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "track file"),
+		),
+		IgnoreFile: key.NewBinding(
+			key.WithKeys("i"),
+			key.WithHelp("i", "ignore file"),
+		),
+		LoadFullDiff: key.NewBinding(
+			key.WithKeys("L"),
+			key.WithHelp("L", "load large diff fully"),
+		),
+		WrapDiff: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", "wrap long diff lines"),
+		),
+		IgnoreSpace: key.NewBinding(
+			key.WithKeys("b"),
+			key.WithHelp("b", "ignore whitespace"),
+		),
+		LessContext: key.NewBinding(
+			key.WithKeys("["),
+			key.WithHelp("[", "less context"),
+		),
+		MoreContext: key.NewBinding(
+			key.WithKeys("]"),
+			key.WithHelp("]", "more context"),
+		),
+
+		// Clipboard
+		Copy: key.NewBinding(
+			key.WithKeys("Y"),
+			key.WithHelp("Y", "copy ID or diff…"),
+		),
+
+		// Action history
+		Repeat: key.NewBinding(
+			key.WithKeys("."),
+			key.WithHelp(".", "repeat last action"),
+		),
+		ActionLog: key.NewBinding(
+			key.WithKeys("L"),
+			key.WithHelp("L", "action log"),
+		),
+		Notifications: key.NewBinding(
+			key.WithKeys("N"),
+			key.WithHelp("N", "notifications"),
+		),
+
+		// Context menu
+		ContextMenu: key.NewBinding(
+			key.WithKeys("m"),
+			key.WithHelp("m", "actions menu"),
+		),
+		Metrics: key.NewBinding(
+			// Bubble Tea reports alt before ctrl
+			key.WithKeys("alt+ctrl+d"),
+			key.WithHelp("ctrl+alt+d", "bridge metrics"),
+		),
+		DebugLog: key.NewBinding(
+			key.WithKeys("alt+ctrl+l"),
+			key.WithHelp("ctrl+alt+l", "debug log"),
+		),
+
+		// Colocated git
+		GitSync: key.NewBinding(
+			key.WithKeys("G"),
+			key.WithHelp("G", "git import/export…"),
+		),
+
+		// Working copy
+		Snapshot: key.NewBinding(
+			key.WithKeys("ctrl+s"),
+			key.WithHelp("C-s", "snapshot working copy"),
+		),
+		NewOnWorkingCopy: key.NewBinding(
+			// Terminals that tell ctrl+enter from enter mostly send it as ctrl+j
+			key.WithKeys("ctrl+enter", "ctrl+j"),
+			key.WithHelp("C-enter", "new change on @"),
+		),
+
+		// Release notes
+		Changelog: key.NewBinding(
+			key.WithKeys("W"),
+			key.WithHelp("W", "what's new"),
+		),
+
+		// Panel resizing
+		GrowSidebar: key.NewBinding(
+			key.WithKeys(">"),
+			key.WithHelp(">", "wider sidebar"),
+		),
+		ShrinkSidebar: key.NewBinding(
+			key.WithKeys("<"),
+			key.WithHelp("<", "narrower sidebar"),
+		),
+		GrowWorkspace: key.NewBinding(
+			key.WithKeys("+", "="),
+			key.WithHelp("+", "taller workspace"),
+		),
+		ShrinkWorkspace: key.NewBinding(
+			key.WithKeys("-"),
+			key.WithHelp("-", "shorter workspace"),
+		),
 	}
 }
 
@@ -154,12 +474,34 @@ func (k KeyMap) ShortHelp() []key.Binding {
 	}
 }
 
+// KeyGroup is a titled set of bindings shown together on the help screen
+type KeyGroup struct {
+	Title    string
+	Bindings []key.Binding
+}
+
+// HelpGroups returns the bindings shown on the help screen, grouped by purpose
+func (k KeyMap) HelpGroups() []KeyGroup {
+	return []KeyGroup{
+		{Title: "Panels", Bindings: []key.Binding{k.Panel0, k.Panel1, k.Panel2, k.NextPanel, k.PrevPanel}},
+		{Title: "Navigation", Bindings: []key.Binding{k.Up, k.Down, k.PageUp, k.PageDown, k.Home, k.End}},
+		{Title: "Log", Bindings: []key.Binding{k.Enter, k.NewChange, k.Describe, k.Commit, k.Abandon, k.SquashChange, k.SquashInto, k.CreateTag, k.EditAuthor, k.Sign, k.Backout, k.Rebase, k.Jump, k.GoToChange, k.LogFilter, k.Details, k.Divergent, k.Stack, k.UpdateFromTrunk, k.AdvanceBookmarks}},
+		{Title: "Log range", Bindings: []key.Binding{k.VisualMode, k.Parallelize, k.Sign, k.ExportPatches}},
+		{Title: "Workspaces", Bindings: []key.Binding{k.OpenWorkspace, k.WorkspaceInfo}},
+		{Title: "Bookmarks", Bindings: []key.Binding{k.CleanupBookmarks}},
+		{Title: "Change", Bindings: []key.Binding{k.ToggleMessage, k.FileHistory, k.SortFiles, k.TrackFile, k.IgnoreFile, k.WrapDiff, k.IgnoreSpace, k.LessContext, k.MoreContext, k.LoadFullDiff}},
+		{Title: "Operations", Bindings: []key.Binding{k.Operations, k.Enter, k.Present}},
+		{Title: "Layout", Bindings: []key.Binding{k.TogglePreview, k.ReverseLog, k.GrowSidebar, k.ShrinkSidebar, k.GrowWorkspace, k.ShrinkWorkspace}},
+		{Title: "General", Bindings: []key.Binding{k.ContextMenu, k.Copy, k.Repeat, k.ActionLog, k.Notifications, k.Metrics, k.DebugLog, k.GitSync, k.Snapshot, k.NewOnWorkingCopy, k.Changelog, k.Escape, k.Help, k.Quit}},
+	}
+}
+
 // FullHelp returns all keybindings for the help screen
 func (k KeyMap) FullHelp() [][]key.Binding {
-	return [][]key.Binding{
-		{k.Panel0, k.Panel1, k.Panel2, k.Panel3},
-		{k.Up, k.Down, k.PageUp, k.PageDown},
-		{k.Enter, k.Space, k.Edit, k.Delete},
-		{k.Escape, k.Help, k.Quit},
+	groups := k.HelpGroups()
+	rows := make([][]key.Binding, len(groups))
+	for i, group := range groups {
+		rows[i] = group.Bindings
 	}
+	return rows
 }
@@ -0,0 +1,99 @@
+package ui
+
+const (
+	sidebarResizeStep    = 2  // Columns per < / > press
+	minMainWidth         = 20 // Narrowest the main panel may get
+	minWorkspaceHeight   = 3
+	minBookmarksHeight   = 3
+	defaultWorkspaceRows = 3
+
+	// collapsedWorkspaceRows is the workspace panel's height while there is
+	// a single workspace: just its header line
+	collapsedWorkspaceRows = 1
+)
+
+// sidebarWidthFor returns the sidebar width for the available width, using the
+// saved ratio when set and the size-based default otherwise
+func sidebarWidthFor(termWidth, availableWidth int, ratio float64) int {
+	if ratio <= 0 {
+		if termWidth < 100 {
+			return SidebarMinWidth
+		} else if termWidth > 200 {
+			return SidebarMaxWidth
+		}
+		return SidebarWidth
+	}
+	return clamp(int(ratio*float64(availableWidth)+0.5), SidebarMinWidth, availableWidth-minMainWidth)
+}
+
+// workspaceHeightFor returns the workspace panel height in the Log experience
+// sidebar, leaving the rest to bookmarks
+func workspaceHeightFor(contentHeight int, ratio float64) int {
+	if ratio <= 0 {
+		return defaultWorkspaceRows
+	}
+	return clamp(int(ratio*float64(contentHeight)+0.5), minWorkspaceHeight, contentHeight-minBookmarksHeight)
+}
+
+// clamp bounds v to [lo, hi]; lo wins if the range is empty
+func clamp(v, lo, hi int) int {
+	if v > hi {
+		v = hi
+	}
+	if v < lo {
+		v = lo
+	}
+	return v
+}
+
+// resizeSidebar grows (positive delta) or shrinks the sidebar and saves the ratio
+func (a *App) resizeSidebar(delta int) {
+	availableWidth := a.width - 2
+	if availableWidth <= 0 {
+		return
+	}
+	width := sidebarWidthFor(a.width, availableWidth, a.layout.SidebarRatio) + delta
+	width = clamp(width, SidebarMinWidth, availableWidth-minMainWidth)
+	a.layout.SidebarRatio = float64(width) / float64(availableWidth)
+	a.saveLayout()
+}
+
+// workspaceShown reports whether the Log sidebar shows the workspace panel
+func (a *App) workspaceShown() bool {
+	return !a.cfg.HideWorkspacePanel
+}
+
+// workspaceCollapsed reports whether the workspace panel is shrunk to its
+// header line, as it is while the repository has a single workspace
+func (a *App) workspaceCollapsed() bool {
+	return a.workspaceShown() && a.workspacePanel.Count() <= 1
+}
+
+// syncWorkspaceCollapse redoes the layout when a workspace was added or
+// forgotten since the last one, expanding or collapsing the panel
+func (a *App) syncWorkspaceCollapse() {
+	if a.ready && a.workspaceCollapsed() != a.workspaceCollapsedLayout {
+		a.updateLayout()
+	}
+}
+
+// resizeWorkspace moves the workspace/bookmarks split by delta rows and saves the ratio
+func (a *App) resizeWorkspace(delta int) {
+	if !a.workspaceShown() || a.workspaceCollapsed() {
+		return
+	}
+	contentHeight := a.height - 4 - statusBarHeight - a.pluginRows(ExperienceLog)
+	if contentHeight <= 0 {
+		return
+	}
+	height := workspaceHeightFor(contentHeight, a.layout.WorkspaceRatio) + delta
+	height = clamp(height, minWorkspaceHeight, contentHeight-minBookmarksHeight)
+	a.layout.WorkspaceRatio = float64(height) / float64(contentHeight)
+	a.saveLayout()
+}
+
+// saveLayout applies and persists the current layout ratios
+func (a *App) saveLayout() {
+	a.updateLayout()
+	_ = a.layout.Save() // Layout still applies for this session if saving fails
+}
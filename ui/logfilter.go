@@ -0,0 +1,86 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/floating"
+)
+
+// logFilters lists the preset log filters: everything, my changes, one
+// per author in the log, then typing a revset. Revsets are empty for no
+// filter.
+func (a *App) logFilters() []floating.JumpTarget {
+	filters := []floating.JumpTarget{
+		{Label: "all changes"},
+		{Label: "mine()", Revset: "mine()"},
+	}
+	authors, _ := jj.LogAuthors(a.readCtx(), a.repoPath, a.cfg.LogRevset)
+	for _, author := range authors {
+		filters = append(filters, floating.JumpTarget{
+			Label:  "author: " + author.String(),
+			Revset: jj.AuthorRevset(author.Email),
+		})
+	}
+	return append(filters, floating.JumpTarget{Label: customLogFilter})
+}
+
+// openLogFilter shows the log filter presets
+func (a *App) openLogFilter() {
+	a.logFilterOverlay = floating.NewJumpOverlay(a.logFilters())
+	a.logFilterOverlay.SetTitle("Filter Log", "filter")
+	a.logFilterOverlay.SetSize(a.width, a.height-1)
+	a.showLogFilter = true
+}
+
+// handleLogFilterKey handles a key in the log filter list. Enter narrows
+// the log to the picked filter, or asks for a revset to narrow it to.
+func (a *App) handleLogFilterKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "escape", "ctrl+g", "ctrl+c":
+		a.closeLogFilter()
+		return nil
+	case "enter":
+		filter := a.logFilterOverlay.Selected()
+		if filter == nil {
+			return nil
+		}
+		a.closeLogFilter()
+		if filter.Label == customLogFilter {
+			return a.openRevsetInput("Filter Log", "Enter revset to show...", "", "filter_revset")
+		}
+		a.filterLog(*filter)
+		return nil
+	default:
+		_, cmd := a.logFilterOverlay.Update(msg)
+		return cmd
+	}
+}
+
+// filterLog shows the changes in the configured log revset that also
+// match filter, or the whole log for an empty filter
+func (a *App) filterLog(filter floating.JumpTarget) {
+	if filter.Revset == "" {
+		a.logPanel.SetRevset(a.cfg.LogRevset, "")
+		return
+	}
+	base := a.cfg.LogRevset
+	if base == "" {
+		base, _ = jj.ConfigGet(a.ctx, a.repoPath, "revsets.log")
+	}
+	revset := filter.Revset
+	if base != "" {
+		revset = "(" + base + ") & (" + filter.Revset + ")"
+	}
+	a.logPanel.SetRevset(revset, filter.Label)
+	a.setFocus(0)
+}
+
+// closeLogFilter hides the log filter list
+func (a *App) closeLogFilter() {
+	a.showLogFilter = false
+	a.logFilterOverlay = nil
+}
+
+func (a *App) overlayLogFilter(background string) string {
+	return a.composite(background, a.logFilterOverlay.View())
+}
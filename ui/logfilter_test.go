@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/config"
+)
+
+// TestLogFilter verifies F lists the presets and picking one narrows the
+// log, shown in its title, until "all changes" is picked
+func TestLogFilter(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'F'}})
+	if !a.showLogFilter {
+		t.Fatal("Expected F to open the log filters")
+	}
+	view := a.View()
+	if !strings.Contains(view, "Filter Log") || !strings.Contains(view, "mine()") {
+		t.Error("Expected the filter list with the mine() preset")
+	}
+
+	a.Update(tea.KeyMsg{Type: tea.KeyDown})
+	a.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if a.showLogFilter {
+		t.Error("Expected Enter to close the filter list")
+	}
+	if !strings.Contains(a.logPanel.View(), "0 Log [mine()]") {
+		t.Error("Expected the log title to show the filter")
+	}
+
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'F'}})
+	a.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if strings.Contains(a.logPanel.View(), "[mine()]") {
+		t.Error("Expected all changes to clear the filter")
+	}
+}
+
+// TestLogFilterRevset verifies the last preset asks for a revset, which
+// completes and is validated like other revset inputs
+func TestLogFilterRevset(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'F'}})
+	for _, r := range "revset" {
+		a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	a.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !a.showTextInput || a.textInputAction != "filter_revset" {
+		t.Fatalf("Expected a revset input, got action %q", a.textInputAction)
+	}
+	if !isRevsetInput(a.textInputAction) {
+		t.Error("Expected the filter input to be validated as a revset")
+	}
+}
@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/floating"
+)
+
+// rangeRevset returns the revset oldest::newest spanning the visual range,
+// or the selected change outside it, and how many changes it holds. The
+// ends must be ancestor and descendant, or the range would be empty.
+func (a *App) rangeRevset() (string, int, error) {
+	oldest, newest := a.logPanel.RangeEnds()
+	if oldest == nil {
+		return "", 0, fmt.Errorf("no change selected")
+	}
+	revset := jj.RangeRevset(oldest.ChangeID, newest.ChangeID)
+	count, err := jj.CheckRevset(a.ctx, a.repoPath, revset)
+	if err != nil {
+		return "", 0, err
+	}
+	if count == 0 {
+		return "", 0, fmt.Errorf("%s is not an ancestor of %s, so no range joins them", oldest.ChangeID, newest.ChangeID)
+	}
+	return revset, count, nil
+}
+
+// squashRange squashes the visual range into its oldest change, first
+// asking when the confirm setting says to
+func (a *App) squashRange() tea.Cmd {
+	revset, count, err := a.rangeRevset()
+	if err != nil {
+		a.showErrorDialog(err)
+		return nil
+	}
+	if count < 2 {
+		return a.showToast("select at least two changes to squash")
+	}
+	oldest, _ := a.logPanel.RangeEnds()
+	a.squashRangeSource = "(" + revset + ") ~ " + oldest.ChangeID
+	a.squashRangeDest = oldest.ChangeID
+
+	return a.askFirst(squashPolicy, "("+revset+")", nil, func(ask bool, descendants int) tea.Cmd {
+		if !ask {
+			return a.describeSquashRange()
+		}
+		message := fmt.Sprintf("Squash %d changes into %s?", count, oldest.ChangeID)
+		if descendants > 0 {
+			message += fmt.Sprintf(" Their %d descendants are rebased.", descendants)
+		}
+		a.showConfirmDialog("Squash Range", message, "squash_range")
+		return nil
+	})
+}
+
+// describeSquashRange asks for the combined description of the range
+// picked by squashRange, when more than one change is described, then
+// squashes it
+func (a *App) describeSquashRange() tea.Cmd {
+	revset, _, err := a.rangeRevset()
+	if err != nil {
+		a.showErrorDialog(err)
+		return nil
+	}
+	descriptions, err := jj.Descriptions(a.ctx, a.repoPath, revset)
+	if err != nil {
+		a.showErrorDialog(err)
+		return nil
+	}
+	if len(descriptions) < 2 {
+		return a.runSquashRange("")
+	}
+
+	a.textInputOverlay = floating.NewTextAreaOverlay("Squash Message", "Enter description...", strings.Join(descriptions, "\n\n")+"\n")
+	a.textInputOverlay.SetSize(a.width, a.height-1)
+	a.showTextInput = true
+	a.textInputAction = "squash_range_message"
+	return nil
+}
+
+// runSquashRange squashes the range picked by squashRange, setting the
+// combined description when message is not empty
+func (a *App) runSquashRange(message string) tea.Cmd {
+	source, dest := a.squashRangeSource, a.squashRangeDest
+	return a.runMutation(func(ctx context.Context) error {
+		if message == "" {
+			return jj.SquashInto(ctx, a.repoPath, source, dest)
+		}
+		return jj.SquashWithMessage(ctx, a.repoPath, source, dest, message)
+	}, func() {
+		a.logPanel.SetVisualMode(false)
+		a.refreshLogPanels()
+	})
+}
@@ -0,0 +1,232 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/ui/floating"
+)
+
+// keyMsg returns the key press for a key name as bindings spell it, and
+// false for names a menu can't press
+func keyMsg(name string) (tea.KeyMsg, bool) {
+	switch name {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}, true
+	case "delete":
+		return tea.KeyMsg{Type: tea.KeyDelete}, true
+	}
+	if len([]rune(name)) != 1 {
+		return tea.KeyMsg{}, false
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(name)}, true
+}
+
+// menuItem makes the menu entry that presses binding's first key
+func menuItem(label string, binding key.Binding) floating.MenuItem {
+	msg, _ := keyMsg(binding.Keys()[0])
+	return floating.MenuItem{Label: label, Key: msg}
+}
+
+// menuKey makes the menu entry that presses a key no binding names
+func menuKey(label, name string) floating.MenuItem {
+	msg, _ := keyMsg(name)
+	return floating.MenuItem{Label: label, Key: msg}
+}
+
+// contextMenu returns the actions for the focused panel's selected item
+// and a title naming it. Actions that would be refused, like rewriting an
+// immutable change, are left out.
+func (a *App) contextMenu() (string, []floating.MenuItem) {
+	if plugin := a.focusedPlugin(); plugin != nil {
+		var items []floating.MenuItem
+		for _, hint := range plugin.hints {
+			if msg, ok := keyMsg(hint.Key); ok {
+				items = append(items, floating.MenuItem{Label: hint.Desc, Key: msg})
+			}
+		}
+		return plugin.panel.Title(), items
+	}
+
+	if entry := a.panelAt(a.focusedPanel); entry != nil && entry.menu != nil {
+		return entry.menu()
+	}
+	return "", nil
+}
+
+// changeMenu lists the actions on the selected change, or on the range in
+// visual mode
+func (a *App) changeMenu() (string, []floating.MenuItem) {
+	change := a.logPanel.SelectedChange()
+	if change == nil {
+		return "", nil
+	}
+
+	var title string
+	var items []floating.MenuItem
+	if a.logPanel.InVisualMode() {
+		title = "Range"
+		items = []floating.MenuItem{
+			menuItem("abandon range", a.keys.Abandon),
+			menuItem("rebase range", a.keys.Rebase),
+			menuItem("squash into one", a.keys.SquashChange),
+			menuItem("parallelize", a.keys.Parallelize),
+		}
+		if a.signingBackend != "" {
+			items = append(items, menuItem("sign range", a.keys.Sign))
+		}
+		items = append(items,
+			menuItem("export patches", a.keys.ExportPatches),
+			menuItem("end range select", a.keys.VisualMode),
+		)
+	} else {
+		title = "Change " + change.ChangeID
+		items = []floating.MenuItem{
+			menuItem("open change", a.keys.Enter),
+			menuItem("details", a.keys.Details),
+			menuItem("new change after", a.keys.NewChange),
+			menuItem("describe", a.keys.Describe),
+			menuItem("commit @", a.keys.Commit),
+			menuItem("edit author", a.keys.EditAuthor),
+			menuItem("abandon", a.keys.Abandon),
+			menuItem("squash into parent", a.keys.SquashChange),
+			menuItem("squash into…", a.keys.SquashInto),
+			menuItem("back out", a.keys.Backout),
+			menuItem("rebase…", a.keys.Rebase),
+		}
+		if a.signingBackend != "" {
+			items = append(items, menuItem("sign", a.keys.Sign))
+		}
+		items = append(items,
+			menuItem("create tag", a.keys.CreateTag),
+			menuItem("range select", a.keys.VisualMode),
+		)
+	}
+
+	for _, selected := range a.logPanel.SelectedRange() {
+		if selected.Immutable {
+			var allowed []floating.MenuItem
+			for _, item := range items {
+				if !a.rewritesSelection(item.Key) {
+					allowed = append(allowed, item)
+				}
+			}
+			items = allowed
+			break
+		}
+	}
+	return title, items
+}
+
+// workspaceMenu lists the actions on the selected workspace, or adding one
+// when no workspace is entered
+func (a *App) workspaceMenu() (string, []floating.MenuItem) {
+	if !a.workspacePanel.IsEntered() {
+		return "Workspaces", []floating.MenuItem{menuKey("add workspace", "a")}
+	}
+	ws := a.workspacePanel.SelectedWorkspace()
+	if ws == nil {
+		return "", nil
+	}
+	items := []floating.MenuItem{
+		menuItem("open", a.keys.OpenWorkspace),
+		menuItem("details", a.keys.WorkspaceInfo),
+	}
+	if !ws.IsCurrent {
+		items = append(items, menuKey("forget", "d"))
+	}
+	return "Workspace " + ws.Name, items
+}
+
+// bookmarkMenu lists the actions on the selected bookmark
+func (a *App) bookmarkMenu() (string, []floating.MenuItem) {
+	items := []floating.MenuItem{menuItem("clean up bookmarks", a.keys.CleanupBookmarks)}
+	if !a.bookmarksPanel.IsEntered() {
+		return "Bookmarks", items
+	}
+	bm := a.bookmarksPanel.SelectedBookmark()
+	if bm == nil {
+		return "Bookmarks", items
+	}
+	return "Bookmark " + bm.Name, append([]floating.MenuItem{menuKey("edit at bookmark", "e")}, items...)
+}
+
+// fileMenu lists the actions on the selected file, with none while the
+// sidebar shows a file's history
+func (a *App) fileMenu() (string, []floating.MenuItem) {
+	file := a.filesPanel.SelectedFile()
+	if file == nil || a.fileHistoryPath != "" {
+		return "", nil
+	}
+	items := []floating.MenuItem{menuItem("file history", a.keys.FileHistory)}
+	switch {
+	case a.untrackedFileSelected():
+		items = []floating.MenuItem{
+			menuItem("track", a.keys.TrackFile),
+			menuItem("ignore", a.keys.IgnoreFile),
+		}
+	case a.selectedChangeIsWorking:
+		items = append(items,
+			menuKey("restore", "delete"),
+			menuKey("squash into parent", "s"),
+		)
+	}
+	items = append(items, menuItem("sort files", a.keys.SortFiles))
+	return file.Path, items
+}
+
+// canOpenMenu reports whether a right-click may show the context menu: not
+// while another window is open or a key would be refused anyway
+func (a *App) canOpenMenu() bool {
+	return !a.busy && a.atOp == "" && !a.copyPending && !a.gitSyncPending &&
+		!a.showTextInput && !a.showConfirm && !a.showCleanup && !a.showDivergent &&
+		!a.showOpDiff && !a.showAuthor && !a.showActionLog && !a.showMetrics &&
+		!a.showDebugLog && !a.showNotifications && !a.showJump && !a.showLogFilter &&
+		!a.showMenu && !a.showStack && !a.showUpdate && !a.showDetails && !a.showChangelog && !a.showInfo
+}
+
+// openContextMenu shows the actions for the selected item
+func (a *App) openContextMenu() tea.Cmd {
+	if a.pickMode != PickNone || a.bookmarkSetMode || a.rebaseMode || a.squashIntoMode {
+		return nil
+	}
+	title, items := a.contextMenu()
+	if len(items) == 0 {
+		return a.showToast("no actions here")
+	}
+	a.menuOverlay = floating.NewMenuOverlay(title, items)
+	a.menuOverlay.SetSize(a.width, a.height-1)
+	a.showMenu = true
+	return nil
+}
+
+// handleMenuKey moves through the menu, or closes it and runs the picked
+// action by pressing its key, as if typed
+func (a *App) handleMenuKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "escape", "ctrl+g", "ctrl+c", "q", "m":
+		a.closeMenu()
+		return nil
+	}
+
+	item := a.menuOverlay.ItemFor(msg.String())
+	if msg.String() == "enter" {
+		item = a.menuOverlay.Selected()
+	}
+	if item == nil {
+		_, cmd := a.menuOverlay.Update(msg)
+		return cmd
+	}
+	a.closeMenu()
+	_, cmd := a.Update(item.Key)
+	return cmd
+}
+
+// closeMenu hides the context menu
+func (a *App) closeMenu() {
+	a.showMenu = false
+	a.menuOverlay = nil
+}
+
+func (a *App) overlayMenu(background string) string {
+	return a.composite(background, a.menuOverlay.View())
+}
@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/config"
+	"github.com/gerunddev/jjazy/ui/panels"
+)
+
+// newMenuApp returns an app with a registered panel that acts on r
+func newMenuApp() (*App, *actionPanel) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	ci := &actionPanel{fakePanel: fakePanel{BasePanel: panels.NewBasePanel("CI", "")}}
+	a.RegisterPanel(ci, PanelSlot{Experience: ExperienceLog, Rows: 6}, HelpHint{Key: "r", Desc: "rerun"})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	return a, ci
+}
+
+// TestContextMenuRunsAction verifies m lists the focused panel's actions
+// and Enter runs the picked one as if its key was pressed
+func TestContextMenuRunsAction(t *testing.T) {
+	a, ci := newMenuApp()
+	a.setFocus(3)
+
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+	if !a.showMenu {
+		t.Fatal("Expected m to open the context menu")
+	}
+	if item := a.menuOverlay.Selected(); item == nil || item.Label != "rerun" {
+		t.Fatalf("Expected the panel's rerun action, got %+v", item)
+	}
+
+	a.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if a.showMenu {
+		t.Error("Expected the menu to close once an action runs")
+	}
+	if ci.actions != 1 {
+		t.Errorf("Expected the action to run once, got %d", ci.actions)
+	}
+}
+
+// TestContextMenuEmpty verifies m explains when nothing is selected
+func TestContextMenuEmpty(t *testing.T) {
+	a, _ := newMenuApp()
+
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+	if a.showMenu {
+		t.Error("Expected no menu without a selected change")
+	}
+	if a.toast != "no actions here" {
+		t.Errorf("Expected a toast explaining why, got %q", a.toast)
+	}
+}
+
+// TestContextMenuRightClick verifies a right-click focuses the panel under
+// the pointer and opens its menu
+func TestContextMenuRightClick(t *testing.T) {
+	a, _ := newMenuApp()
+
+	var bound *PanelBound
+	for i := range a.panelBounds {
+		if a.panelBounds[i].PanelIndex == 3 {
+			bound = &a.panelBounds[i]
+		}
+	}
+	if bound == nil {
+		t.Fatal("Expected the registered panel to have mouse bounds")
+	}
+
+	a.Update(tea.MouseMsg{X: bound.X1 + 1, Y: bound.Y1 + 1, Button: tea.MouseButtonRight, Action: tea.MouseActionPress})
+	if a.focusedPanel != 3 || !a.showMenu {
+		t.Errorf("Expected the panel focused with its menu open, got panel %d, menu %v", a.focusedPanel, a.showMenu)
+	}
+}
@@ -1,5 +1,13 @@
 package messages
 
+import (
+	"time"
+
+	"github.com/gerunddev/jjazy/app"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/release"
+)
+
 // FileSelectedMsg is sent when a file is selected in FilesPanel
 type FileSelectedMsg struct {
 	Path string
@@ -15,3 +23,157 @@ type DiffContentMsg struct {
 	Content string
 	Title   string
 }
+
+// MutationDoneMsg is sent when a mutating jj command finishes running
+type MutationDoneMsg struct {
+	Err error
+}
+
+// RevsetEvalMsg carries the result of evaluating a revset typed into an input
+type RevsetEvalMsg struct {
+	Revset string
+	Count  int
+	Err    error
+}
+
+// DescendantsCountedMsg carries a descendant count taken before asking to
+// confirm an action, for the ask it was started by
+type DescendantsCountedMsg struct {
+	Seq   int
+	Count int
+	Err   error
+}
+
+// RevsetEvalDueMsg evaluates the revset typed into an input once typing
+// pauses, unless a later keystroke rescheduled it
+type RevsetEvalDueMsg struct {
+	Seq    int
+	Revset string
+}
+
+// PreviewDueMsg loads the preview pane once the log selection settles,
+// unless a later move rescheduled it
+type PreviewDueMsg struct {
+	Seq      int
+	ChangeID string
+}
+
+// PreviewLoadedMsg carries the diff fetched for the preview pane
+type PreviewLoadedMsg struct {
+	Seq      int
+	ChangeID string
+	Diff     string
+	Stats    []jj.FileStat
+	Err      error
+}
+
+// DescriptionSuggestionMsg carries the describe hook's suggestion for a change
+type DescriptionSuggestionMsg struct {
+	ChangeID string
+	Text     string
+	Err      error
+}
+
+// CopiedMsg is sent when text has been written to the clipboard
+type CopiedMsg struct {
+	What string // What was copied, e.g. "change ID abc123"
+	Err  error
+}
+
+// ToastExpiredMsg clears the toast it was scheduled for, unless a newer one replaced it
+type ToastExpiredMsg struct {
+	Seq int
+}
+
+// SlowCallMsg is sent when a bridge call takes longer than the slow threshold
+type SlowCallMsg struct {
+	Op       string
+	Duration time.Duration
+}
+
+// SlowCallExpiredMsg clears the slow call warning it was scheduled for,
+// unless a newer one replaced it
+type SlowCallExpiredMsg struct {
+	Seq int
+}
+
+// RepoSummaryMsg carries a refreshed status bar summary
+type RepoSummaryMsg struct {
+	Workspace string // Name of the current workspace
+	Summary   *jj.RepoSummary
+	Git       *jj.GitColocation // Nil if the check failed
+	Err       error
+}
+
+// TrunkResolvedMsg carries the trunk bookmark detected at startup
+type TrunkResolvedMsg struct {
+	Bookmark string
+}
+
+// UpdateAvailableMsg is sent when the startup check finds a newer release
+type UpdateAvailableMsg struct {
+	Update *release.Update
+}
+
+// OperationSelectedMsg is sent when the timeline or operations list moves to an operation
+type OperationSelectedMsg struct {
+	OpID string
+}
+
+// OperationLogMsg carries the log as it was at an operation, for the timeline preview
+type OperationLogMsg struct {
+	OpID   string
+	Output *jj.LogOutput
+	Err    error
+}
+
+// WorkspaceOpenedMsg is sent when the workspace opener has run
+type WorkspaceOpenedMsg struct {
+	Name string // Name of the workspace that was opened
+	Err  error
+}
+
+// WorkspaceDetailsMsg carries a workspace's disk usage for its details popup
+type WorkspaceDetailsMsg struct {
+	Workspace jj.Workspace
+	Usage     *app.WorkspaceUsage
+	Err       error
+}
+
+// PullRequestsMsg carries the pull requests opened after a push, or the
+// pages to open them at
+type PullRequestsMsg struct {
+	PullRequests []app.PullRequest
+	Err          error
+}
+
+// NotificationExpiredMsg removes the notification it was scheduled for
+type NotificationExpiredMsg struct {
+	Seq int
+}
+
+// RefreshScope selects what a RefreshRequestMsg reloads
+type RefreshScope int
+
+const (
+	RefreshLog        RefreshScope = 1 << iota // The log panel
+	RefreshBookmarks                           // The bookmarks panel
+	RefreshWorkspaces                          // The workspaces panel
+	RefreshFiles                               // The open change's files and diff
+	RefreshOperations                          // The operation timeline and list
+
+	// RefreshAll reloads everything
+	RefreshAll = RefreshLog | RefreshBookmarks | RefreshWorkspaces | RefreshFiles | RefreshOperations
+)
+
+// Has reports whether s includes any of scope
+func (s RefreshScope) Has(scope RefreshScope) bool {
+	return s&scope != 0
+}
+
+// RefreshRequestMsg asks every panel to reload what Scope covers, after
+// the repo changed. Requests made while handling one message are merged
+// into a single RefreshRequestMsg.
+type RefreshRequestMsg struct {
+	Scope RefreshScope
+}
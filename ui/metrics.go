@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/config"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/floating"
+	"github.com/gerunddev/jjazy/ui/messages"
+)
+
+// slowCallDuration is how long a slow call warning stays in the status bar
+const slowCallDuration = 5 * time.Second
+
+// waitForSlowCall waits in the background for the next bridge call that
+// takes longer than the slow threshold. Test mode never waits, so nothing
+// timing-dependent reaches the screen.
+func (a *App) waitForSlowCall() tea.Cmd {
+	if config.TestMode() {
+		return nil
+	}
+	return func() tea.Msg {
+		call := jj.WaitForSlowCall()
+		return messages.SlowCallMsg{Op: call.Op, Duration: call.Duration}
+	}
+}
+
+// warnSlowCall shows a slow call in the status bar until it expires, and
+// goes back to waiting for the next one
+func (a *App) warnSlowCall(msg messages.SlowCallMsg) tea.Cmd {
+	a.slowCall = fmt.Sprintf("%s took %.2fs", msg.Op, msg.Duration.Seconds())
+	a.slowCallSeq++
+	seq := a.slowCallSeq
+	expire := tea.Tick(slowCallDuration, func(time.Time) tea.Msg {
+		return messages.SlowCallExpiredMsg{Seq: seq}
+	})
+	return tea.Batch(expire, a.waitForSlowCall())
+}
+
+// openMetrics shows the bridge call latencies so far
+func (a *App) openMetrics() {
+	a.metricsOverlay = floating.NewMetricsOverlay(jj.BridgeLatency(), jj.LatencyBuckets())
+	a.metricsOverlay.SetSize(a.width, a.height-1)
+	a.showMetrics = true
+}
+
+// handleMetricsKey scrolls the metrics table or closes it
+func (a *App) handleMetricsKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "escape", "ctrl+g", "ctrl+c", "q", "alt+ctrl+d":
+		a.closeMetrics()
+		return nil
+	default:
+		_, cmd := a.metricsOverlay.Update(msg)
+		return cmd
+	}
+}
+
+// closeMetrics hides the metrics table
+func (a *App) closeMetrics() {
+	a.showMetrics = false
+	a.metricsOverlay = nil
+}
+
+func (a *App) overlayMetrics(background string) string {
+	return a.composite(background, a.metricsOverlay.View())
+}
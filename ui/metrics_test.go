@@ -0,0 +1,72 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/config"
+	"github.com/gerunddev/jjazy/ui/messages"
+)
+
+// TestMetricsOverlayKey verifies ctrl+alt+d opens the bridge metrics and esc closes them
+func TestMetricsOverlayKey(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	a.Update(tea.KeyMsg{Type: tea.KeyCtrlD, Alt: true})
+	if !a.showMetrics {
+		t.Fatal("Expected ctrl+alt+d to open the bridge metrics")
+	}
+	if !strings.Contains(a.View(), "Bridge Metrics") {
+		t.Error("Expected the metrics table to be drawn")
+	}
+
+	a.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if a.showMetrics {
+		t.Error("Expected esc to close the bridge metrics")
+	}
+}
+
+// TestSlowCallWarning verifies a slow call shows in the status bar until
+// its expiry, and a stale expiry leaves a newer warning alone
+func TestSlowCallWarning(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	a.Update(messages.SlowCallMsg{Op: "get_log", Duration: 1200 * time.Millisecond})
+	if !strings.Contains(a.renderStatusBar(), "get_log took 1.20s") {
+		t.Fatalf("Expected the warning in the status bar, got %q", a.renderStatusBar())
+	}
+
+	a.Update(messages.SlowCallMsg{Op: "get_diff", Duration: time.Second})
+	a.Update(messages.SlowCallExpiredMsg{Seq: a.slowCallSeq - 1})
+	if !strings.Contains(a.renderStatusBar(), "get_diff took 1.00s") {
+		t.Error("Expected a stale expiry to keep the newer warning")
+	}
+
+	a.Update(messages.SlowCallExpiredMsg{Seq: a.slowCallSeq})
+	if a.slowCall != "" {
+		t.Error("Expected the warning to clear when it expires")
+	}
+}
+
+// TestDebugLogOverlayKey verifies ctrl+alt+l opens the debug log and esc closes it
+func TestDebugLogOverlayKey(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	a.Update(tea.KeyMsg{Type: tea.KeyCtrlL, Alt: true})
+	if !a.showDebugLog {
+		t.Fatal("Expected ctrl+alt+l to open the debug log")
+	}
+	if !strings.Contains(a.View(), "Debug Log") {
+		t.Error("Expected the debug log to be drawn")
+	}
+
+	a.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if a.showDebugLog {
+		t.Error("Expected esc to close the debug log")
+	}
+}
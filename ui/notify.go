@@ -0,0 +1,131 @@
+package ui
+
+import (
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/gerunddev/jjazy/ui/floating"
+	"github.com/gerunddev/jjazy/ui/messages"
+	"github.com/gerunddev/jjazy/ui/theme"
+)
+
+// Notification limits: how long one stays on screen, how many stack up
+// at once, how many the notification log keeps, and how wide one gets
+const (
+	notificationDuration  = 4 * time.Second
+	maxShownNotifications = 3
+	maxNotificationLog    = 100
+	maxNotificationWidth  = 50
+)
+
+// shownNotification is a notification on screen until its expiry
+type shownNotification struct {
+	floating.Notification
+	seq int // Matches the NotificationExpiredMsg that removes it
+}
+
+// notify shows a confirmation in the corner without interrupting, removed
+// after notificationDuration
+func (a *App) notify(text string) tea.Cmd {
+	return a.pushNotification(floating.Notification{At: time.Now(), Text: text})
+}
+
+// notifyError shows an error in the corner, for failures that don't stop
+// what the user is doing, like a background refresh
+func (a *App) notifyError(what string, err error) tea.Cmd {
+	return a.pushNotification(floating.Notification{At: time.Now(), Text: what + ": " + err.Error(), Err: true})
+}
+
+// pushNotification logs n and stacks it on screen. Repeating one already
+// shown restarts its time instead of stacking a copy.
+func (a *App) pushNotification(n floating.Notification) tea.Cmd {
+	a.notificationLog = append(a.notificationLog, n)
+	if len(a.notificationLog) > maxNotificationLog {
+		a.notificationLog = a.notificationLog[len(a.notificationLog)-maxNotificationLog:]
+	}
+
+	a.notificationSeq++
+	seq := a.notificationSeq
+	a.notifications = slices.DeleteFunc(a.notifications, func(shown shownNotification) bool {
+		return shown.Text == n.Text && shown.Err == n.Err
+	})
+	a.notifications = append(a.notifications, shownNotification{Notification: n, seq: seq})
+	if len(a.notifications) > maxShownNotifications {
+		a.notifications = a.notifications[len(a.notifications)-maxShownNotifications:]
+	}
+	return tea.Tick(notificationDuration, func(time.Time) tea.Msg {
+		return messages.NotificationExpiredMsg{Seq: seq}
+	})
+}
+
+// expireNotification removes the notification scheduled to expire as seq
+func (a *App) expireNotification(seq int) {
+	a.notifications = slices.DeleteFunc(a.notifications, func(shown shownNotification) bool {
+		return shown.seq == seq
+	})
+}
+
+// overlayNotifications stacks the shown notifications at the right, the
+// newest lowest, ending above row bottom
+func (a *App) overlayNotifications(background string, bottom int) string {
+	width := min(maxNotificationWidth, a.width/2)
+	for i := len(a.notifications) - 1; i >= 0; i-- {
+		box := renderNotification(a.notifications[i].Notification, width)
+		bottom -= lipgloss.Height(box)
+		if bottom < 1 {
+			break // No room left under the top border
+		}
+		background = floating.Place(background, box, a.width-lipgloss.Width(box)-2, bottom)
+	}
+	return background
+}
+
+// renderNotification draws a notification as a small box, its time dimmed,
+// green for a confirmation and red for an error, at most width wide
+func renderNotification(n floating.Notification, width int) string {
+	color, mark := theme.ColorGreen, "✓ "
+	if n.Err {
+		color, mark = theme.ColorRed, "✗ "
+	}
+	stamp := n.At.Format("15:04:05") + " "
+	text := ansi.Truncate(mark+n.Text, max(width-4-len(stamp), 1), "…")
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(color).
+		Padding(0, 1).
+		Render(theme.DimmedStyle.Render(stamp) + lipgloss.NewStyle().Foreground(color).Render(text))
+}
+
+// openNotifications lists this session's notifications, newest first
+func (a *App) openNotifications() {
+	notifications := slices.Clone(a.notificationLog)
+	slices.Reverse(notifications)
+	a.notificationsOverlay = floating.NewNotificationsOverlay(notifications)
+	a.notificationsOverlay.SetSize(a.width, a.height-1)
+	a.showNotifications = true
+}
+
+// handleNotificationsKey scrolls the notification log or closes it
+func (a *App) handleNotificationsKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "escape", "ctrl+g", "ctrl+c", "q", "N":
+		a.closeNotifications()
+		return nil
+	default:
+		_, cmd := a.notificationsOverlay.Update(msg)
+		return cmd
+	}
+}
+
+// closeNotifications hides the notification log
+func (a *App) closeNotifications() {
+	a.showNotifications = false
+	a.notificationsOverlay = nil
+}
+
+func (a *App) overlayNotificationLog(background string) string {
+	return a.composite(background, a.notificationsOverlay.View())
+}
@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/gerunddev/jjazy/config"
+	"github.com/gerunddev/jjazy/ui/messages"
+)
+
+// TestNotificationStack verifies notifications stack up to the limit,
+// expire one by one, and a repeat restarts instead of stacking
+func TestNotificationStack(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	for _, text := range []string{"one", "two", "three", "four"} {
+		a.notify(text)
+	}
+	if len(a.notifications) != maxShownNotifications || a.notifications[0].Text != "two" {
+		t.Fatalf("Expected the newest %d notifications shown, got %v", maxShownNotifications, a.notifications)
+	}
+
+	a.notify("three")
+	if len(a.notifications) != 3 || a.notifications[2].Text != "three" {
+		t.Errorf("Expected a repeat to move to the newest, got %v", a.notifications)
+	}
+
+	a.Update(messages.NotificationExpiredMsg{Seq: a.notifications[0].seq})
+	if len(a.notifications) != 2 || a.notifications[0].Text != "four" {
+		t.Errorf("Expected only the expired notification removed, got %v", a.notifications)
+	}
+	if len(a.notificationLog) != 5 {
+		t.Errorf("Expected every notification logged, got %d", len(a.notificationLog))
+	}
+}
+
+// TestNotificationsOnScreen verifies notifications are drawn at the right
+// of the main frame, keeping the screen around them
+func TestNotificationsOnScreen(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	rows := strings.Count(a.View(), "\n")
+	a.notifyError("refresh failed", errors.New("boom"))
+
+	lines := strings.Split(ansi.Strip(a.View()), "\n")
+	if len(lines) != rows+1 {
+		t.Fatalf("Expected the screen to keep its %d rows, got %d", rows+1, len(lines))
+	}
+	for i, line := range lines {
+		if col := strings.Index(line, "✗ refresh failed: boom"); col >= 0 {
+			if !strings.HasPrefix(line, "│") || ansi.StringWidth(line) != 120 {
+				t.Errorf("Expected the notification inside the frame on row %d, got %q", i, line)
+			}
+			return
+		}
+	}
+	t.Errorf("Expected the notification on screen, got:\n%s", strings.Join(lines, "\n"))
+}
+
+// TestMutationNotifies verifies a finished action is confirmed with a
+// notification and a failed one still gets the error dialog
+func TestMutationNotifies(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	a.recordAction("abandon", "kmnopqrs", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	a.runMutation(func(context.Context) error { return nil }, nil)
+	a.Update(messages.MutationDoneMsg{})
+	if len(a.notifications) != 1 || a.notifications[0].Text != "abandon kmnopqrs" {
+		t.Fatalf("Expected a confirmation, got %v", a.notifications)
+	}
+
+	a.recordAction("abandon", "zzzzzzzz", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	a.runMutation(func(context.Context) error { return nil }, nil)
+	a.Update(messages.MutationDoneMsg{Err: errors.New("immutable")})
+	if len(a.notifications) != 1 || !a.showInfo {
+		t.Errorf("Expected the failure shown as a dialog only, got %v", a.notifications)
+	}
+}
+
+// TestRefreshErrorNotifies verifies a failed status refresh shows an error
+// notification and keeps the last summary
+func TestRefreshErrorNotifies(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	a.workspaceName = "default"
+
+	a.Update(messages.RepoSummaryMsg{Err: errors.New("jj not found")})
+	if len(a.notifications) != 1 || !a.notifications[0].Err || a.workspaceName != "default" {
+		t.Errorf("Expected an error notification and the old summary kept, got %v", a.notifications)
+	}
+}
+
+// TestNotificationLogKey verifies N lists expired notifications and closes
+func TestNotificationLogKey(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	a.notify("copied diff")
+	a.Update(messages.NotificationExpiredMsg{Seq: a.notificationSeq})
+
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'N'}})
+	if !a.showNotifications || !strings.Contains(a.View(), "copied diff") {
+		t.Fatal("Expected N to list the expired notification")
+	}
+	a.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if a.showNotifications {
+		t.Error("Expected esc to close the notification log")
+	}
+}
@@ -0,0 +1,46 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/floating"
+)
+
+// openOpDiff shows what the selected operation changed, which is what
+// undoing it would revert
+func (a *App) openOpDiff() {
+	op := a.operationsPanel.SelectedOperation()
+	if op == nil {
+		return
+	}
+	output, err := jj.OpDiff(a.ctx, a.repoPath, op.ID)
+	if err != nil {
+		a.showErrorDialog(err)
+		return
+	}
+	a.opDiffOverlay = floating.NewOpDiffOverlay(op.ID, output)
+	a.opDiffOverlay.SetSize(a.width, a.height-1)
+	a.showOpDiff = true
+}
+
+// handleOpDiffKey scrolls the operation details or closes them
+func (a *App) handleOpDiffKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "escape", "ctrl+g", "ctrl+c", "q", "enter":
+		a.closeOpDiff()
+		return nil
+	default:
+		_, cmd := a.opDiffOverlay.Update(msg)
+		return cmd
+	}
+}
+
+// closeOpDiff hides the operation details
+func (a *App) closeOpDiff() {
+	a.showOpDiff = false
+	a.opDiffOverlay = nil
+}
+
+func (a *App) overlayOpDiff(background string) string {
+	return a.composite(background, a.opDiffOverlay.View())
+}
@@ -1,10 +1,12 @@
 package panels
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/gerunddev/jjazy/app"
 	"github.com/gerunddev/jjazy/jj"
 	"github.com/gerunddev/jjazy/ui/borders"
@@ -12,13 +14,16 @@ import (
 	"github.com/gerunddev/jjazy/ui/theme"
 )
 
-// BookmarksPanel shows bookmarks (branches).
+// BookmarksPanel shows bookmarks (branches), then tags under a Tags header.
 // This is a "browsable" panel that requires Enter to show cursor.
 type BookmarksPanel struct {
 	BasePanel
 	repo      *jj.Repo
 	repoPath  string
+	trunk     string // Trunk bookmark name, rendered with the trunk style
 	bookmarks []fixtures.Bookmark
+	pulls     map[string]string // Pull request URLs by bookmark, shown by number
+	tags      []jj.Tag          // Listed after the bookmarks; the cursor moves over both
 	viewport  viewport.Model
 	ready     bool
 }
@@ -46,7 +51,29 @@ func NewBookmarksPanel(repo *jj.Repo, repoPath string) *BookmarksPanel {
 	return p
 }
 
+// SetTrunkBookmark sets the bookmark rendered as trunk
+func (p *BookmarksPanel) SetTrunkBookmark(name string) {
+	p.trunk = name
+	if p.ready {
+		p.viewport.SetContent(p.renderContent())
+	}
+}
+
+// SetPullRequests sets the pull requests opened for bookmarks, by name
+func (p *BookmarksPanel) SetPullRequests(urls map[string]string) {
+	p.pulls = urls
+	if p.ready {
+		p.viewport.SetContent(p.renderContent())
+	}
+}
+
 func (p *BookmarksPanel) loadBookmarks() {
+	tags, err := p.repo.Tags()
+	if err != nil {
+		tags = nil
+	}
+	p.tags = tags
+
 	// Get branches from jj-lib
 	branches, err := p.repo.Branches()
 	if err != nil {
@@ -69,6 +96,8 @@ func (p *BookmarksPanel) loadBookmarks() {
 			Name:      b.Name,
 			IsLocal:   b.IsLocal,
 			IsCurrent: b.Name == currentBookmark,
+			Ahead:     b.Ahead,
+			Behind:    b.Behind,
 		}
 	}
 }
@@ -91,8 +120,8 @@ func (p *BookmarksPanel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch msg.Button {
 		case tea.MouseButtonLeft:
 			if msg.Action == tea.MouseActionPress {
-				itemIndex := msg.Y - 1 + p.viewport.YOffset
-				if itemIndex >= 0 && itemIndex < len(p.bookmarks) {
+				itemIndex := p.itemAt(msg.Y - 1 + p.viewport.YOffset)
+				if itemIndex >= 0 && itemIndex < p.itemCount() {
 					p.cursor = itemIndex
 					p.ensureCursorVisible()
 				}
@@ -110,16 +139,16 @@ func (p *BookmarksPanel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		switch msg.String() {
 		case "up", "k":
-			p.CursorUp(len(p.bookmarks))
+			p.CursorUp(p.itemCount())
 			p.ensureCursorVisible()
 		case "down", "j":
-			p.CursorDown(len(p.bookmarks))
+			p.CursorDown(p.itemCount())
 			p.ensureCursorVisible()
 		case "g", "home":
 			p.CursorHome()
 			p.viewport.GotoTop()
 		case "G", "end":
-			p.CursorEnd(len(p.bookmarks))
+			p.CursorEnd(p.itemCount())
 			p.viewport.GotoBottom()
 		case "ctrl+u", "pgup":
 			p.viewport.HalfViewUp()
@@ -136,11 +165,36 @@ func (p *BookmarksPanel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (p *BookmarksPanel) ensureCursorVisible() {
-	if p.cursor < p.viewport.YOffset {
-		p.viewport.SetYOffset(p.cursor)
-	} else if p.cursor >= p.viewport.YOffset+p.viewport.Height {
-		p.viewport.SetYOffset(p.cursor - p.viewport.Height + 1)
+	line := p.lineOf(p.cursor)
+	if line < p.viewport.YOffset {
+		p.viewport.SetYOffset(line)
+	} else if line >= p.viewport.YOffset+p.viewport.Height {
+		p.viewport.SetYOffset(line - p.viewport.Height + 1)
+	}
+}
+
+// itemCount returns how many bookmarks and tags the cursor moves over
+func (p *BookmarksPanel) itemCount() int {
+	return len(p.bookmarks) + len(p.tags)
+}
+
+// lineOf returns the content line of an item; tags sit below the Tags header
+func (p *BookmarksPanel) lineOf(item int) int {
+	if item >= len(p.bookmarks) {
+		return item + 1
 	}
+	return item
+}
+
+// itemAt returns the item on a content line, or -1 for the Tags header
+func (p *BookmarksPanel) itemAt(line int) int {
+	switch {
+	case line < len(p.bookmarks):
+		return line
+	case line == len(p.bookmarks):
+		return -1
+	}
+	return line - 1
 }
 
 func (p *BookmarksPanel) View() string {
@@ -181,10 +235,18 @@ func (p *BookmarksPanel) renderContent() string {
 	contentWidth := p.ContentWidth()
 
 	for i, bm := range p.bookmarks {
+		// Bookmarks end with how far they are from their remote, then
+		// the number of their pull request
+		suffix := aheadBehind(bm)
+		if url, ok := p.pulls[bm.Name]; ok {
+			suffix += " " + pullRequestLabel(url)
+		}
+		suffixWidth := ansi.StringWidth(suffix)
+
 		// Truncate if needed
 		name := bm.Name
-		if len(name)+2 > contentWidth && contentWidth > 3 {
-			name = truncate(name, contentWidth-3)
+		if len(name)+suffixWidth+2 > contentWidth && contentWidth > 3+suffixWidth {
+			name = truncate(name, contentWidth-3-suffixWidth)
 		}
 
 		// Style the name based on current/selected state
@@ -196,19 +258,59 @@ func (p *BookmarksPanel) renderContent() string {
 		} else if bm.IsCurrent {
 			// Current bookmark is PURPLE
 			styledName = theme.CurrentBookmarkStyle.Render(name)
+		} else if bm.Name == p.trunk {
+			// Trunk bookmark is BLUE (matches the ◆ log decoration)
+			styledName = theme.TrunkBookmarkStyle.Render(name)
 		} else if bm.IsLocal {
 			styledName = theme.NormalItemStyle.Render(name)
 		} else {
 			styledName = theme.DimmedStyle.Render(name)
 		}
 
-		line := styledName
+		line := styledName + theme.DimmedStyle.Render(suffix)
 		lines = append(lines, line)
 	}
 
+	if len(p.tags) > 0 {
+		lines = append(lines, theme.DimmedStyle.Render("Tags"))
+	}
+	for i, tag := range p.tags {
+		name := tag.Name
+		if len(name)+2 > contentWidth && contentWidth > 3 {
+			name = truncate(name, contentWidth-3)
+		}
+		if len(p.bookmarks)+i == p.cursor && p.focused && p.entered {
+			lines = append(lines, theme.SelectedItemStyle.Render(name))
+		} else {
+			lines = append(lines, theme.TagStyle.Render(name))
+		}
+	}
+
 	return strings.Join(lines, "\n")
 }
 
+// aheadBehind badges a bookmark with the commits it is ahead of and behind
+// its tracked remote bookmark, like " ↑2 ↓1", or "" when they match
+func aheadBehind(bm fixtures.Bookmark) string {
+	var badge string
+	if bm.Ahead > 0 {
+		badge += fmt.Sprintf(" ↑%d", bm.Ahead)
+	}
+	if bm.Behind > 0 {
+		badge += fmt.Sprintf(" ↓%d", bm.Behind)
+	}
+	return badge
+}
+
+// pullRequestLabel shortens a pull request URL to its number, like "#12"
+func pullRequestLabel(url string) string {
+	number := url[strings.LastIndex(url, "/")+1:]
+	if number == "" || strings.Trim(number, "0123456789") != "" {
+		return "PR"
+	}
+	return "#" + number
+}
+
 // SelectedBookmark returns the currently selected bookmark
 func (p *BookmarksPanel) SelectedBookmark() *fixtures.Bookmark {
 	if p.cursor >= 0 && p.cursor < len(p.bookmarks) {
@@ -217,6 +319,25 @@ func (p *BookmarksPanel) SelectedBookmark() *fixtures.Bookmark {
 	return nil
 }
 
+// SelectedTag returns the currently selected tag, or nil when the cursor
+// is on a bookmark
+func (p *BookmarksPanel) SelectedTag() *jj.Tag {
+	i := p.cursor - len(p.bookmarks)
+	if i >= 0 && i < len(p.tags) {
+		return &p.tags[i]
+	}
+	return nil
+}
+
+// BookmarkNames returns the names of the listed bookmarks, in order
+func (p *BookmarksPanel) BookmarkNames() []string {
+	names := make([]string, len(p.bookmarks))
+	for i, b := range p.bookmarks {
+		names[i] = b.Name
+	}
+	return names
+}
+
 // Count returns the number of bookmarks
 func (p *BookmarksPanel) Count() int {
 	return len(p.bookmarks)
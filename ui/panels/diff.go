@@ -1,26 +1,69 @@
 package panels
 
 import (
+	"context"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/gerunddev/jjazy/jj"
 	"github.com/gerunddev/jjazy/ui/borders"
 	"github.com/gerunddev/jjazy/ui/theme"
 )
 
+// maxDiffLines caps how many diff lines are shown until LoadFully is
+// called; longer diffs end with a notice instead
+const maxDiffLines = 5000
+
+// horizontalScrollStep is how many columns ←/→ scroll long lines
+const horizontalScrollStep = 8
+
 // DiffViewer shows the diff/patch content with syntax highlighting
 type DiffViewer struct {
 	BasePanel
 	repo     *jj.Repo
 	repoPath string
+	atOp     string // Operation diffs are loaded at; empty for the present
+	options  jj.DiffOptions
+
+	// What the diff was loaded from, to reload it when options change.
+	// Empty when the content was set directly.
+	changeID string
+	filePath string
 	viewport viewport.Model
 	content  string
+	stats    []jj.FileStat // Diffstat shown above the diff; nil hides it
 	ready    bool
+
+	// Lines as laid out in the viewport. Diff lines are styled when they
+	// first scroll into view, so opening a huge diff stays fast.
+	lines       []string // Display lines, unstyled past the header
+	sources     []string // Diff line each display line came from, for its style
+	rendered    []string // Styled lines, valid where styled is set
+	styled      []bool
+	headerLines int  // Leading lines that are the styled commit message
+	truncated   bool // True when the diff was cut at maxDiffLines
+	full        bool // True after LoadFully: show the diff uncapped
+
+	// Long lines are cut off at the panel edge unless wrapped; xOffset
+	// scrolls them horizontally
+	wrap    bool
+	xOffset int
+	longest int // Width of the longest diff line
+
+	// Commit message shown above the diff (Change experience)
+	description      string
+	hasDescription   bool // False hides the header entirely (e.g. working copy diff)
+	messageCollapsed bool // True shows only the subject line
 }
 
+// tabSpaces is what a tab in the diff is shown as, the same as lipgloss
+const tabSpaces = "    "
+
 // NewDiffViewer creates a new diff viewer panel
 func NewDiffViewer(repo *jj.Repo) *DiffViewer {
 	d := &DiffViewer{
@@ -32,11 +75,21 @@ func NewDiffViewer(repo *jj.Repo) *DiffViewer {
 	return d
 }
 
+// SetTitle changes the panel title
+func (d *DiffViewer) SetTitle(title string) {
+	d.title = title
+}
+
 // SetRepoPath sets the repository path for CLI operations
 func (d *DiffViewer) SetRepoPath(path string) {
 	d.repoPath = path
 }
 
+// SetAtOperation loads later diffs as of a past operation ("" for the present)
+func (d *DiffViewer) SetAtOperation(opID string) {
+	d.atOp = opID
+}
+
 func (d *DiffViewer) loadDiff() {
 	// Get diff from jj-lib
 	diff, err := d.repo.Diff()
@@ -47,42 +100,189 @@ func (d *DiffViewer) loadDiff() {
 	d.content = diff
 }
 
+// SetDiffOptions sets how later diffs are computed
+func (d *DiffViewer) SetDiffOptions(opts jj.DiffOptions) {
+	d.options = opts
+}
+
+// DiffOptions returns how diffs are computed
+func (d *DiffViewer) DiffOptions() jj.DiffOptions {
+	return d.options
+}
+
+// ToggleIgnoreWhitespace switches whitespace-only changes off or on and
+// reloads the diff
+func (d *DiffViewer) ToggleIgnoreWhitespace() {
+	d.options.IgnoreWhitespace = !d.options.IgnoreWhitespace
+	d.reload()
+}
+
+// AdjustContext shows delta more (or fewer) lines of context around each
+// change, at least one, and reloads the diff
+func (d *DiffViewer) AdjustContext(delta int) {
+	lines := d.options.Context
+	if lines == 0 {
+		lines = jj.DefaultDiffContext
+	}
+	d.options.Context = max(lines+delta, 1)
+	d.reload()
+}
+
+// reload fetches the loaded diff again, e.g. with new options
+func (d *DiffViewer) reload() {
+	switch {
+	case d.changeID == "":
+		return
+	case d.filePath != "":
+		d.LoadFileInChange(d.changeID, d.filePath)
+	default:
+		d.LoadChange(d.changeID)
+	}
+}
+
 // LoadChange loads the diff for a specific change ID
 func (d *DiffViewer) LoadChange(changeID string) {
-	diff, err := jj.DiffForChange(d.repoPath, changeID)
+	diff, stats, err := d.ChangeLoader(changeID)()
+	d.ShowChange(changeID, diff, stats, err)
+}
+
+// ChangeLoader returns a function fetching a change's diff with the
+// viewer's current settings. It leaves the viewer alone, so it can run in a
+// tea.Cmd; ShowChange shows what it fetched.
+func (d *DiffViewer) ChangeLoader(changeID string) func() (string, []jj.FileStat, error) {
+	ctx := jj.AtOperation(context.Background(), d.atOp)
+	repoPath, options := d.repoPath, d.options
+	return func() (string, []jj.FileStat, error) {
+		stats, _ := jj.DiffStats(ctx, repoPath, changeID, "", options)
+		diff, err := jj.DiffForChange(ctx, repoPath, changeID, options)
+		return diff, stats, err
+	}
+}
+
+// ShowChange shows a change's diff fetched by ChangeLoader
+func (d *DiffViewer) ShowChange(changeID, diff string, stats []jj.FileStat, err error) {
+	d.changeID, d.filePath = changeID, ""
+	d.stats = stats
 	if err != nil {
 		d.content = "Error loading diff: " + err.Error()
 	} else {
 		d.content = diff
 	}
+	d.full = false
+	d.xOffset = 0
 
 	if d.ready {
-		d.viewport.SetContent(d.renderDiff())
+		d.layout()
 		d.viewport.GotoTop()
 	}
 }
 
 // LoadFileInChange loads the diff for a specific file within a change
 func (d *DiffViewer) LoadFileInChange(changeID, filePath string) {
-	diff, err := jj.DiffForChangeFile(d.repoPath, changeID, filePath)
+	d.changeID, d.filePath = changeID, filePath
+	ctx := jj.AtOperation(context.Background(), d.atOp)
+	d.stats, _ = jj.DiffStats(ctx, d.repoPath, changeID, filePath, d.options)
+	diff, err := jj.DiffForChangeFile(ctx, d.repoPath, changeID, filePath, d.options)
 	if err != nil {
 		d.content = "Error loading diff: " + err.Error()
 	} else {
 		d.content = diff
 	}
+	d.full = false
+	d.xOffset = 0
 
 	if d.ready {
-		d.viewport.SetContent(d.renderDiff())
+		d.layout()
 		d.viewport.GotoTop()
 	}
 }
 
+// SetDescription sets the commit message shown above the diff
+func (d *DiffViewer) SetDescription(description string) {
+	d.description = description
+	d.hasDescription = true
+	d.refreshContent()
+}
+
+// ClearDescription removes the commit message header
+func (d *DiffViewer) ClearDescription() {
+	d.description = ""
+	d.hasDescription = false
+	d.refreshContent()
+}
+
+// ToggleMessage collapses or expands the commit message header
+func (d *DiffViewer) ToggleMessage() {
+	d.messageCollapsed = !d.messageCollapsed
+	d.refreshContent()
+}
+
+// refreshContent re-renders the viewport keeping the scroll position
+func (d *DiffViewer) refreshContent() {
+	if d.ready {
+		d.layout()
+	}
+}
+
+// Truncated returns true when the diff is too long to be shown in full
+func (d *DiffViewer) Truncated() bool {
+	return d.truncated
+}
+
+// ToggleWrap switches between wrapping long lines and cutting them off
+func (d *DiffViewer) ToggleWrap() {
+	d.wrap = !d.wrap
+	d.xOffset = 0
+	d.refreshContent()
+}
+
+// Wrapped returns true when long lines are wrapped
+func (d *DiffViewer) Wrapped() bool {
+	return d.wrap
+}
+
+// ScrollLeft scrolls long lines back towards their start. It returns false
+// when they already show from the start.
+func (d *DiffViewer) ScrollLeft() bool {
+	if d.xOffset == 0 {
+		return false
+	}
+	d.xOffset = max(d.xOffset-horizontalScrollStep, 0)
+	d.restyle()
+	return true
+}
+
+// ScrollRight scrolls long lines to show more of their ends. It returns
+// false when lines are wrapped or the longest already fits.
+func (d *DiffViewer) ScrollRight() bool {
+	limit := max(d.longest-d.maxLineWidth(), 0)
+	if d.wrap || d.xOffset >= limit {
+		return false
+	}
+	d.xOffset = min(d.xOffset+horizontalScrollStep, limit)
+	d.restyle()
+	return true
+}
+
+// LoadFully shows the whole diff, past maxDiffLines
+func (d *DiffViewer) LoadFully() {
+	d.full = true
+	d.refreshContent()
+}
+
+// Content returns the diff as loaded, without the commit message header
+func (d *DiffViewer) Content() string {
+	return d.content
+}
+
 // SetContent updates the diff content
 func (d *DiffViewer) SetContent(content string) {
+	d.changeID, d.filePath = "", ""
+	d.stats = nil
 	d.content = content
-	if d.ready {
-		d.viewport.SetContent(d.renderDiff())
-	}
+	d.full = false
+	d.xOffset = 0
+	d.refreshContent()
 }
 
 func (d *DiffViewer) Init() tea.Cmd {
@@ -125,6 +325,21 @@ func (d *DiffViewer) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return d, cmd
 }
 
+// ScrollOffset returns the first diff line in view
+func (d *DiffViewer) ScrollOffset() int {
+	if !d.ready {
+		return 0
+	}
+	return d.viewport.YOffset
+}
+
+// SetScrollOffset scrolls the diff to offset
+func (d *DiffViewer) SetScrollOffset(offset int) {
+	if d.ready {
+		d.viewport.SetYOffset(offset)
+	}
+}
+
 func (d *DiffViewer) View() string {
 	if !d.ready {
 		return d.RenderFrame("Initializing...")
@@ -134,7 +349,7 @@ func (d *DiffViewer) View() string {
 		return d.RenderFrame(theme.DimmedStyle.Render("No changes"))
 	}
 
-	return d.RenderFrame(d.viewport.View())
+	return d.RenderFrame(d.visibleLines())
 }
 
 // SetSize overrides BasePanel.SetSize to also resize viewport
@@ -146,64 +361,228 @@ func (d *DiffViewer) SetSize(width, height int) {
 
 	if !d.ready {
 		d.viewport = viewport.New(contentWidth, contentHeight)
-		d.viewport.SetContent(d.renderDiff())
 		d.ready = true
 	} else {
 		d.viewport.Width = contentWidth
 		d.viewport.Height = contentHeight
-		d.viewport.SetContent(d.renderDiff())
 	}
+	d.layout()
 }
 
-// renderDiff applies syntax highlighting to the diff content
-func (d *DiffViewer) renderDiff() string {
+// renderMessage renders the commit message header, collapsed to the subject
+// line when messageCollapsed is set
+func (d *DiffViewer) renderMessage(maxWidth int) []string {
+	if !d.hasDescription {
+		return nil
+	}
+
 	var lines []string
-	// Use contentWidth - 1 to add a safety margin and prevent overflow
-	maxWidth := d.ContentWidth()
-	if maxWidth > 0 {
-		maxWidth = maxWidth - 1
-	}
-
-	for _, line := range strings.Split(d.content, "\n") {
-		var styled string
-
-		switch {
-		case strings.HasPrefix(line, "+++ ") || strings.HasPrefix(line, "--- "):
-			// File headers
-			styled = theme.DimmedStyle.Bold(true).MaxWidth(maxWidth).Render(line)
-		case strings.HasPrefix(line, "@@"):
-			// Hunk headers
-			styled = theme.DiffHunkHeader.MaxWidth(maxWidth).Render(line)
-		case strings.HasPrefix(line, "+"):
-			// Added lines
-			styled = theme.DiffAddLine.MaxWidth(maxWidth).Render(line)
-		case strings.HasPrefix(line, "-"):
-			// Removed lines
-			styled = theme.DiffRemoveLine.MaxWidth(maxWidth).Render(line)
-		case strings.HasPrefix(line, "diff --git"):
-			// Diff header
-			styled = theme.DimmedStyle.Bold(true).MaxWidth(maxWidth).Render(line)
-		case strings.HasPrefix(line, "index "):
-			// Index line
-			styled = theme.DimmedStyle.MaxWidth(maxWidth).Render(line)
-		default:
-			// Context lines
-			styled = theme.DiffContextLine.MaxWidth(maxWidth).Render(line)
+	msgLines := strings.Split(d.description, "\n")
+	subject := msgLines[0]
+	if subject == "" {
+		lines = append(lines, theme.DimmedStyle.MaxWidth(maxWidth).Render("(no description set)"))
+	} else if d.messageCollapsed {
+		marker := ""
+		if len(msgLines) > 1 {
+			marker = " …"
+		}
+		lines = append(lines, theme.DiffMessageSubjectStyle.MaxWidth(maxWidth).Render(subject+marker))
+	} else {
+		lines = append(lines, theme.DiffMessageSubjectStyle.MaxWidth(maxWidth).Render(subject))
+		for _, line := range msgLines[1:] {
+			lines = append(lines, theme.DiffMessageStyle.MaxWidth(maxWidth).Render(line))
+		}
+	}
+
+	lines = append(lines, theme.DimmedStyle.Render(strings.Repeat("─", max(maxWidth, 0))))
+	return lines
+}
+
+// renderStats renders the diffstat: lines changed per file with a bar of
+// +/- scaled to fit, then the totals
+func (d *DiffViewer) renderStats(maxWidth int) []string {
+	if len(d.stats) == 0 {
+		return nil
+	}
+
+	pathWidth, countWidth, most := 0, 0, 0
+	added, removed := 0, 0
+	for _, stat := range d.stats {
+		changed := stat.Added + stat.Removed
+		pathWidth = max(pathWidth, ansi.StringWidth(stat.Path))
+		countWidth = max(countWidth, len(strconv.Itoa(changed)))
+		most = max(most, changed)
+		added += stat.Added
+		removed += stat.Removed
+	}
+	pathWidth = min(pathWidth, maxWidth/2)
+	barWidth := max(min(most, maxWidth-pathWidth-countWidth-4), 0)
+
+	// scale shrinks a line count to the bar, keeping nonzero counts visible
+	scale := func(n int) int {
+		if most <= barWidth || n == 0 {
+			return n
 		}
+		return max(n*barWidth/most, 1)
+	}
 
-		lines = append(lines, styled)
+	var lines []string
+	for _, stat := range d.stats {
+		path := ansi.Truncate(stat.Path, pathWidth, "…")
+		path += strings.Repeat(" ", pathWidth-ansi.StringWidth(path))
+		line := theme.DiffContextLine.Render(path + " | ")
+		if stat.Binary {
+			line += theme.DimmedStyle.Render("Bin")
+		} else {
+			line += theme.DiffContextLine.Render(fmt.Sprintf("%*d ", countWidth, stat.Added+stat.Removed)) +
+				theme.DiffAddLine.Render(strings.Repeat("+", scale(stat.Added))) +
+				theme.DiffRemoveLine.Render(strings.Repeat("-", scale(stat.Removed)))
+		}
+		lines = append(lines, lipgloss.NewStyle().MaxWidth(maxWidth).Render(line))
 	}
 
-	return strings.Join(lines, "\n")
+	summary := fmt.Sprintf("%s changed, %s(+), %s(-)",
+		plural(len(d.stats), "file"), plural(added, "insertion"), plural(removed, "deletion"))
+	lines = append(lines, theme.DimmedStyle.MaxWidth(maxWidth).Render(summary))
+	lines = append(lines, theme.DimmedStyle.Render(strings.Repeat("─", max(maxWidth, 0))))
+	return lines
+}
+
+// plural formats a count with its noun, adding an s unless it is one
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// maxLineWidth returns the width lines are cut to, one short of the
+// content width as a safety margin against overflow
+func (d *DiffViewer) maxLineWidth() int {
+	return max(d.ContentWidth()-1, 0)
+}
+
+// layout lays out the commit message header and the diff lines in the
+// viewport, wrapping long lines if enabled. The viewport only tracks
+// scrolling; visibleLines styles and renders the lines in view.
+func (d *DiffViewer) layout() {
+	maxWidth := d.maxLineWidth()
+	header := append(d.renderMessage(maxWidth), d.renderStats(maxWidth)...)
+	diffLines := strings.Split(d.content, "\n")
+	total := len(diffLines)
+	d.truncated = !d.full && total > maxDiffLines
+	if d.truncated {
+		diffLines = diffLines[:maxDiffLines]
+	}
+
+	d.headerLines = len(header)
+	d.lines = header
+	d.sources = make([]string, len(header), len(header)+len(diffLines))
+	d.longest = 0
+	for _, line := range diffLines {
+		// Expand tabs as lipgloss renders them, so widths and wraps line up
+		line = strings.ReplaceAll(line, "\t", tabSpaces)
+		width := ansi.StringWidth(line)
+		d.longest = max(d.longest, width)
+		if d.wrap && maxWidth > 0 && width > maxWidth {
+			for _, part := range strings.Split(ansi.Hardwrap(line, maxWidth, true), "\n") {
+				d.lines = append(d.lines, part)
+				d.sources = append(d.sources, line)
+			}
+			continue
+		}
+		d.lines = append(d.lines, line)
+		d.sources = append(d.sources, line)
+	}
+	if d.truncated {
+		notice := fmt.Sprintf("diff too large (%d of %d lines shown), press L to load fully", maxDiffLines, total)
+		d.lines = append(d.lines, theme.ModifiedStyle.Render(notice))
+		d.sources = append(d.sources, "")
+	}
+	d.xOffset = min(d.xOffset, max(d.longest-maxWidth, 0))
+
+	d.restyle()
+	d.viewport.SetContent(strings.Join(d.lines, "\n"))
+}
+
+// restyle drops the styled lines so they're styled again as they're shown
+func (d *DiffViewer) restyle() {
+	d.rendered = make([]string, len(d.lines))
+	d.styled = make([]bool, len(d.lines))
+}
+
+// isDiffLine returns true for lines that come from the diff, as opposed to
+// the commit message header and the truncation notice
+func (d *DiffViewer) isDiffLine(i int) bool {
+	return i >= d.headerLines && (!d.truncated || i < len(d.lines)-1)
+}
+
+// visibleLines styles the lines in view that aren't styled yet and returns them
+func (d *DiffViewer) visibleLines() string {
+	start := min(d.viewport.YOffset, len(d.lines))
+	end := min(start+d.viewport.Height, len(d.lines))
+	maxWidth := d.maxLineWidth()
+	for i := start; i < end; i++ {
+		if d.styled[i] {
+			continue
+		}
+		line := d.lines[i]
+		if d.isDiffLine(i) {
+			if d.xOffset > 0 {
+				line = ansi.Cut(line, d.xOffset, d.xOffset+maxWidth)
+			}
+			line = diffLineStyle(d.sources[i]).MaxWidth(maxWidth).Render(line)
+		}
+		d.rendered[i] = line
+		d.styled[i] = true
+	}
+	return strings.Join(d.rendered[start:end], "\n")
+}
+
+// diffLineStyle returns the syntax highlighting for a diff line
+func diffLineStyle(line string) lipgloss.Style {
+	switch {
+	case strings.HasPrefix(line, "+++ ") || strings.HasPrefix(line, "--- "):
+		// File headers
+		return theme.DimmedStyle.Bold(true)
+	case strings.HasPrefix(line, "@@"):
+		// Hunk headers
+		return theme.DiffHunkHeader
+	case strings.HasPrefix(line, "+"):
+		// Added lines
+		return theme.DiffAddLine
+	case strings.HasPrefix(line, "-"):
+		// Removed lines
+		return theme.DiffRemoveLine
+	case strings.HasPrefix(line, "diff --git"):
+		// Diff header
+		return theme.DimmedStyle.Bold(true)
+	case strings.HasPrefix(line, "index "):
+		// Index line
+		return theme.DimmedStyle
+	default:
+		// Context lines
+		return theme.DiffContextLine
+	}
 }
 
 // RenderFrame overrides to use titled border for the main diff panel
 func (d *DiffViewer) RenderFrame(content string) string {
 	// Build title with scroll percentage if applicable
 	title := d.title
+	var flags []string
+	if d.options.IgnoreWhitespace {
+		flags = append(flags, "-w")
+	}
+	if d.options.Context > 0 {
+		flags = append(flags, fmt.Sprintf("-U%d", d.options.Context))
+	}
+	if len(flags) > 0 {
+		title += " [" + strings.Join(flags, " ") + "]"
+	}
 	if d.ready && d.viewport.TotalLineCount() > d.viewport.Height {
 		scrollPercent := int(d.viewport.ScrollPercent() * 100)
-		title = fmt.Sprintf("%s (%d%%)", d.title, scrollPercent)
+		title = fmt.Sprintf("%s (%d%%)", title, scrollPercent)
 	}
 
 	return borders.RenderTitledBorder(content, title, d.width, d.height, d.focused)
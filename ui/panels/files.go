@@ -1,6 +1,9 @@
 package panels
 
 import (
+	"cmp"
+	"context"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/viewport"
@@ -12,14 +15,40 @@ import (
 	"github.com/gerunddev/jjazy/ui/theme"
 )
 
+// FileOrder is how FilesPanel sorts a change's files
+type FileOrder string
+
+const (
+	FileOrderPath   FileOrder = "path"   // Alphabetical, as jj lists them
+	FileOrderStatus FileOrder = "status" // Added, then modified, then deleted
+	FileOrderSize   FileOrder = "size"   // Most lines changed first
+)
+
+// Next returns the order after o, cycling back to path order
+func (o FileOrder) Next() FileOrder {
+	switch o {
+	case FileOrderPath, "":
+		return FileOrderStatus
+	case FileOrderStatus:
+		return FileOrderSize
+	}
+	return FileOrderPath
+}
+
 // FilesPanel shows files changed in the current revision
 type FilesPanel struct {
 	BasePanel
-	repo     *jj.Repo
-	repoPath string
-	files    []fixtures.FileChange
-	viewport viewport.Model
-	ready    bool
+	repo        *jj.Repo
+	repoPath    string
+	atOp        string // Operation files are listed at; empty for the present
+	files       []fixtures.FileChange
+	tracked     int             // Files before the untracked section
+	workingCopy bool            // List untracked files after the changes (working copy)
+	changeID    string          // Change the files were loaded for
+	order       FileOrder       // Sort order of the tracked files
+	marked      map[string]bool // Paths marked for multi-select (file picker)
+	viewport    viewport.Model
+	ready       bool
 }
 
 // NewFilesPanel creates a new files panel
@@ -38,6 +67,61 @@ func (p *FilesPanel) SetRepoPath(path string) {
 	p.repoPath = path
 }
 
+// SetWorkingCopy lists untracked files in their own section after later
+// changes' files; only meaningful for the working copy
+func (p *FilesPanel) SetWorkingCopy(workingCopy bool) {
+	p.workingCopy = workingCopy
+}
+
+// SetOrder re-sorts the files, keeping the untracked section last
+func (p *FilesPanel) SetOrder(order FileOrder) {
+	p.order = order
+	if p.changeID == "" {
+		return
+	}
+	p.sortFiles()
+	p.cursor = 0
+	if p.ready {
+		p.viewport.SetContent(p.renderContent())
+		p.viewport.GotoTop()
+	}
+}
+
+// Order returns the current sort order
+func (p *FilesPanel) Order() FileOrder {
+	if p.order == "" {
+		return FileOrderPath
+	}
+	return p.order
+}
+
+// sortFiles sorts the tracked files by the current order. Ties, and files
+// whose size can't be read, fall back to path order.
+func (p *FilesPanel) sortFiles() {
+	tracked := p.files[:p.tracked]
+	switch p.Order() {
+	case FileOrderStatus:
+		rank := map[fixtures.FileStatus]int{fixtures.StatusAdded: 0, fixtures.StatusModified: 1, fixtures.StatusDeleted: 2}
+		slices.SortStableFunc(tracked, func(a, b fixtures.FileChange) int {
+			return cmp.Or(cmp.Compare(rank[a.Status], rank[b.Status]), strings.Compare(a.Path, b.Path))
+		})
+	case FileOrderSize:
+		sizes, _ := jj.DiffSizes(jj.AtOperation(context.Background(), p.atOp), p.repoPath, p.changeID)
+		slices.SortStableFunc(tracked, func(a, b fixtures.FileChange) int {
+			return cmp.Or(cmp.Compare(sizes[b.Path], sizes[a.Path]), strings.Compare(a.Path, b.Path))
+		})
+	default:
+		slices.SortStableFunc(tracked, func(a, b fixtures.FileChange) int {
+			return strings.Compare(a.Path, b.Path)
+		})
+	}
+}
+
+// SetAtOperation lists later changes' files as of a past operation ("" for the present)
+func (p *FilesPanel) SetAtOperation(opID string) {
+	p.atOp = opID
+}
+
 func (p *FilesPanel) loadFiles() {
 	// Get file changes from jj-lib
 	changes, err := p.repo.WorkingCopyChanges()
@@ -64,13 +148,15 @@ func (p *FilesPanel) loadFiles() {
 			Status: status,
 		}
 	}
+	p.tracked = len(p.files)
 }
 
 // LoadForChange loads files changed in a specific change ID
 func (p *FilesPanel) LoadForChange(changeID string) {
-	cliFiles, err := jj.FilesForChange(p.repoPath, changeID)
+	cliFiles, err := jj.FilesForChange(jj.AtOperation(context.Background(), p.atOp), p.repoPath, changeID)
 	if err != nil {
 		p.files = nil
+		p.tracked = 0
 		return
 	}
 
@@ -92,14 +178,65 @@ func (p *FilesPanel) LoadForChange(changeID string) {
 			Status: status,
 		}
 	}
+	p.tracked = len(p.files)
+	p.changeID = changeID
+	p.sortFiles()
+
+	// Untracked files only exist in the present working copy
+	if p.workingCopy && p.atOp == "" {
+		if untracked, err := jj.UntrackedFiles(context.Background(), p.repoPath); err == nil {
+			for _, path := range untracked {
+				p.files = append(p.files, fixtures.FileChange{Path: path, Status: fixtures.StatusUntracked})
+			}
+		}
+	}
 
 	p.cursor = 0
+	p.marked = nil
 	if p.ready {
 		p.viewport.SetContent(p.renderContent())
 		p.viewport.GotoTop()
 	}
 }
 
+// SetTitle changes the panel title
+func (p *FilesPanel) SetTitle(title string) {
+	p.title = title
+}
+
+// ToggleMark marks or unmarks the file under the cursor and moves down
+func (p *FilesPanel) ToggleMark() {
+	file := p.SelectedFile()
+	if file == nil {
+		return
+	}
+	if p.marked == nil {
+		p.marked = make(map[string]bool)
+	}
+	if p.marked[file.Path] {
+		delete(p.marked, file.Path)
+	} else {
+		p.marked[file.Path] = true
+	}
+
+	p.CursorDown(len(p.files))
+	p.ensureCursorVisible()
+	if p.ready {
+		p.viewport.SetContent(p.renderContent())
+	}
+}
+
+// MarkedPaths returns the marked files in list order
+func (p *FilesPanel) MarkedPaths() []string {
+	var paths []string
+	for _, file := range p.files {
+		if p.marked[file.Path] {
+			paths = append(paths, file.Path)
+		}
+	}
+	return paths
+}
+
 func (p *FilesPanel) Init() tea.Cmd {
 	return nil
 }
@@ -114,7 +251,7 @@ func (p *FilesPanel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case tea.MouseButtonLeft:
 			if msg.Action == tea.MouseActionPress {
 				// Convert Y to item index (subtract 1 for top border, add viewport offset)
-				itemIndex := msg.Y - 1 + p.viewport.YOffset
+				itemIndex := p.fileAt(msg.Y - 1 + p.viewport.YOffset)
 				if itemIndex >= 0 && itemIndex < len(p.files) {
 					p.cursor = itemIndex
 					p.ensureCursorVisible()
@@ -167,11 +304,36 @@ func (p *FilesPanel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return p, nil
 }
 
+// hasUntracked returns true when the untracked section is shown
+func (p *FilesPanel) hasUntracked() bool {
+	return p.tracked < len(p.files)
+}
+
+// lineOf returns the content line of file i, past the untracked header
+func (p *FilesPanel) lineOf(i int) int {
+	if p.hasUntracked() && i >= p.tracked {
+		return i + 1
+	}
+	return i
+}
+
+// fileAt returns the file index on a content line, or -1 for the header
+func (p *FilesPanel) fileAt(line int) int {
+	if p.hasUntracked() && line >= p.tracked {
+		if line == p.tracked {
+			return -1
+		}
+		return line - 1
+	}
+	return line
+}
+
 func (p *FilesPanel) ensureCursorVisible() {
-	if p.cursor < p.viewport.YOffset {
-		p.viewport.SetYOffset(p.cursor)
-	} else if p.cursor >= p.viewport.YOffset+p.viewport.Height {
-		p.viewport.SetYOffset(p.cursor - p.viewport.Height + 1)
+	line := p.lineOf(p.cursor)
+	if line < p.viewport.YOffset {
+		p.viewport.SetYOffset(line)
+	} else if line >= p.viewport.YOffset+p.viewport.Height {
+		p.viewport.SetYOffset(line - p.viewport.Height + 1)
 	}
 }
 
@@ -208,6 +370,10 @@ func (p *FilesPanel) renderContent() string {
 	contentWidth := p.ContentWidth()
 
 	for i, file := range p.files {
+		if i == p.tracked && p.hasUntracked() {
+			lines = append(lines, theme.DimmedStyle.Render("Untracked"))
+		}
+
 		// Style the status indicator based on file status
 		var statusStyle lipgloss.Style
 		switch file.Status {
@@ -221,6 +387,8 @@ func (p *FilesPanel) renderContent() string {
 			statusStyle = theme.RenamedStyle
 		case fixtures.StatusConflict:
 			statusStyle = theme.ConflictStyle
+		case fixtures.StatusUntracked:
+			statusStyle = theme.DimmedStyle
 		default:
 			statusStyle = theme.NormalItemStyle
 		}
@@ -241,7 +409,13 @@ func (p *FilesPanel) renderContent() string {
 			path = theme.NormalItemStyle.Render(path)
 		}
 
-		line := status + " " + path
+		// Marked files get a marker in the gap after the status
+		gap := " "
+		if p.marked[file.Path] {
+			gap = theme.SelectedItemStyle.Render("*")
+		}
+
+		line := status + gap + path
 		lines = append(lines, line)
 	}
 
@@ -0,0 +1,94 @@
+package panels
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/golden"
+)
+
+// goldenLog builds a log of n changes, two lines each as jj prints them,
+// with @ on the first, a bookmark on the second and the last immutable
+func goldenLog(n int) *jj.LogOutput {
+	out := &jj.LogOutput{}
+	var lines []string
+	for i := range n {
+		change := jj.ChangeInfo{
+			ChangeID:      fmt.Sprintf("change%02d", i),
+			CommitID:      fmt.Sprintf("commit%02d", i),
+			Description:   fmt.Sprintf("change number %d", i),
+			StartLine:     len(lines),
+			EndLine:       len(lines) + 2,
+			IsWorkingCopy: i == 0,
+			Immutable:     i == n-1,
+		}
+		node := "○"
+		if change.IsWorkingCopy {
+			node = "\x1b[1m\x1b[38;5;2m@\x1b[0m"
+		}
+		first := fmt.Sprintf("%s  \x1b[1m\x1b[38;5;5m%s\x1b[0m test@example.com 2026-01-02 15:04:05 %s", node, change.ChangeID, change.CommitID)
+		if i == 1 {
+			change.Bookmarks = []string{"main"}
+			first += " \x1b[38;5;5mmain\x1b[0m"
+		}
+		lines = append(lines, first, "│  "+change.Description)
+		out.LineToChange = append(out.LineToChange, change.ChangeID, "")
+		out.Changes = append(out.Changes, change)
+	}
+	out.RawANSI = strings.Join(lines, "\n")
+	return out
+}
+
+const goldenDiff = `diff --git a/main.go b/main.go
+index 1234567..89abcde 100644
+--- a/main.go
++++ b/main.go
+@@ -1,5 +1,6 @@
+ package main
+
+-import "fmt"
++import (
++	"fmt"
++)
+
+ func main() {
++	fmt.Println("a line long enough to run past the right edge of a narrow diff panel")
+ }`
+
+func TestGoldenLogPanel(t *testing.T) {
+	golden.Freeze(t)
+	l := NewLogPreviewPanel("0 Log")
+	l.SetOutput(goldenLog(3), nil)
+	l.SetFocused(true)
+	l.SetSize(60, 10)
+	golden.Assert(t, "log", l.View(), 60, 10)
+}
+
+func TestGoldenLogPanelMinimap(t *testing.T) {
+	golden.Freeze(t)
+	l := NewLogPreviewPanel("0 Log")
+	l.minimap = true
+	l.SetOutput(goldenLog(12), nil)
+	l.SetSize(40, 12)
+	golden.Assert(t, "log_minimap", l.View(), 40, 12)
+}
+
+func TestGoldenDiffViewer(t *testing.T) {
+	golden.Freeze(t)
+	d := NewDiffViewer(nil)
+	d.SetContent(goldenDiff)
+	d.SetFocused(true)
+	d.SetSize(50, 16)
+	golden.Assert(t, "diff", d.View(), 50, 16)
+}
+
+func TestGoldenDiffViewerWrapped(t *testing.T) {
+	golden.Freeze(t)
+	d := NewDiffViewer(nil)
+	d.SetContent(goldenDiff)
+	d.ToggleWrap()
+	d.SetSize(50, 18)
+	golden.Assert(t, "diff_wrapped", d.View(), 50, 18)
+}
@@ -1,13 +1,18 @@
 package panels
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/gerunddev/jjazy/jj"
 	"github.com/gerunddev/jjazy/ui/borders"
+	"github.com/gerunddev/jjazy/ui/graph"
+	"github.com/gerunddev/jjazy/ui/prefix"
+	"github.com/gerunddev/jjazy/ui/theme"
 )
 
 // ANSI escape codes for selection highlighting
@@ -17,33 +22,84 @@ const (
 	selectionBgEnd   = "\x1b[49m" // Reset background only
 )
 
+// minimapWidth is the column at the right of the log that shows where @,
+// bookmarks and the visible window sit within the whole log
+const minimapWidth = 1
+
 // LogPanel displays the jj log with CLI-style output and selection.
 type LogPanel struct {
 	BasePanel
 	repoPath      string
+	atOp          string          // Operation the log is loaded at; empty for the present
+	revset        string          // Revisions shown; empty for jj's default log revset
+	reversed      bool            // Oldest changes first
+	filterLabel   string          // Shown in the title while a filter narrows revset
+	signingRepo   *jj.Repo        // Set when rows get signed/unsigned badges
+	signed        map[string]bool // Commit IDs known to be signed, from signingRepo
 	viewport      viewport.Model
 	logOutput     *jj.LogOutput
 	selectedIndex int // Index into logOutput.Changes
+	visualAnchor  int // Anchor index of the visual range, -1 when not in visual mode
+	changeIDs     *prefix.IDSet
+	jumping       bool   // Typing a change ID prefix to jump to
+	jumpPrefix    string // What has been typed of it
+	jumpFrom      int    // Selection before the jump, restored if it's cancelled
+	minimap       bool   // Reserve the minimap column (the main log only)
 	ready         bool
 }
 
-// NewLogPanel creates a new log panel.
-func NewLogPanel(repoPath string) *LogPanel {
+// NewLogPanel creates a new log panel showing revset, or jj's default
+// log revset when it is empty. reversed lists the oldest changes first.
+func NewLogPanel(repoPath, revset string, reversed bool) *LogPanel {
 	l := &LogPanel{
-		BasePanel: NewBasePanel("0 Log", "log"),
-		repoPath:  repoPath,
+		BasePanel:    NewBasePanel("0 Log", "log"),
+		repoPath:     repoPath,
+		revset:       revset,
+		reversed:     reversed,
+		visualAnchor: -1,
+		minimap:      true,
 	}
 	l.loadLog()
 	return l
 }
 
+// NewLogPreviewPanel creates a log panel that shows output handed to
+// SetOutput instead of loading the log itself.
+func NewLogPreviewPanel(title string) *LogPanel {
+	return &LogPanel{
+		BasePanel:    NewBasePanel(title, "log"),
+		visualAnchor: -1,
+		logOutput:    &jj.LogOutput{},
+	}
+}
+
 // SetTitle changes the panel title
 func (l *LogPanel) SetTitle(title string) {
 	l.title = title
 }
 
 func (l *LogPanel) loadLog() {
-	output, err := jj.LogCLI(l.repoPath)
+	l.setOutput(jj.LogRevset(jj.AtOperation(context.Background(), l.atOp), l.repoPath, l.revset, l.reversed))
+}
+
+// SetAtOperation reloads the log as of a past operation ("" for the present)
+func (l *LogPanel) SetAtOperation(opID string) {
+	l.atOp = opID
+	l.selectedIndex = 0
+	l.visualAnchor = -1
+	l.Refresh()
+}
+
+// SetOutput replaces the shown log with output loaded elsewhere, or an
+// error line if loading failed.
+func (l *LogPanel) SetOutput(output *jj.LogOutput, err error) {
+	l.setOutput(output, err)
+	if l.ready {
+		l.viewport.SetContent(l.renderLog())
+	}
+}
+
+func (l *LogPanel) setOutput(output *jj.LogOutput, err error) {
 	if err != nil {
 		// Create empty output on error
 		l.logOutput = &jj.LogOutput{
@@ -54,11 +110,117 @@ func (l *LogPanel) loadLog() {
 		return
 	}
 	l.logOutput = output
+	l.loadSignatures()
+
+	ids := make([]string, len(output.Changes))
+	for i, change := range output.Changes {
+		ids[i] = change.ChangeID
+	}
+	l.changeIDs = prefix.NewIDSet(ids)
 
 	// Ensure selected index is valid
 	if l.selectedIndex >= len(l.logOutput.Changes) {
 		l.selectedIndex = 0
 	}
+	if l.visualAnchor >= len(l.logOutput.Changes) {
+		l.visualAnchor = -1
+	}
+}
+
+// ShowSignatures badges each row as signed or unsigned, asking repo which
+// commits are signed on every load. A nil repo turns the badges off.
+func (l *LogPanel) ShowSignatures(repo *jj.Repo) {
+	l.signingRepo = repo
+	l.loadSignatures()
+	if l.ready {
+		l.viewport.SetContent(l.renderLog())
+	}
+}
+
+// loadSignatures refreshes which of the shown commits are signed. If the
+// bridge can't say, rows go without badges rather than show a wrong one.
+func (l *LogPanel) loadSignatures() {
+	l.signed = nil
+	if l.signingRepo == nil || l.logOutput == nil {
+		return
+	}
+	ids := make([]string, 0, len(l.logOutput.Changes))
+	for _, change := range l.logOutput.Changes {
+		ids = append(ids, change.CommitID)
+	}
+	signatures, err := l.signingRepo.Signatures(ids)
+	if err != nil {
+		return
+	}
+	l.signed = make(map[string]bool, len(signatures))
+	for _, sig := range signatures {
+		l.signed[sig.ID] = sig.Signed
+	}
+}
+
+// SetVisualMode starts or stops visual range selection.
+// Starting anchors the range at the current selection.
+func (l *LogPanel) SetVisualMode(on bool) {
+	if on && l.SelectedChange() != nil {
+		l.visualAnchor = l.selectedIndex
+	} else {
+		l.visualAnchor = -1
+	}
+	if l.ready {
+		l.viewport.SetContent(l.renderLog())
+	}
+}
+
+// InVisualMode returns true while a visual range is being selected.
+func (l *LogPanel) InVisualMode() bool {
+	return l.visualAnchor >= 0
+}
+
+// SelectedRange returns the changes between the visual anchor and the
+// selection (inclusive, in log order). Outside visual mode it returns
+// just the selected change.
+func (l *LogPanel) SelectedRange() []jj.ChangeInfo {
+	if l.SelectedChange() == nil {
+		return nil
+	}
+	if !l.InVisualMode() {
+		return []jj.ChangeInfo{l.logOutput.Changes[l.selectedIndex]}
+	}
+	start, end := l.visualAnchor, l.selectedIndex
+	if start > end {
+		start, end = end, start
+	}
+	return l.logOutput.Changes[start : end+1]
+}
+
+// RangeEnds returns the oldest and newest changes of the visual range by
+// log position, so oldest::newest spans it. Outside visual mode both are
+// the selected change.
+func (l *LogPanel) RangeEnds() (oldest, newest *jj.ChangeInfo) {
+	if l.SelectedChange() == nil {
+		return nil, nil
+	}
+	top, bottom := l.selectedIndex, l.selectedIndex
+	if l.InVisualMode() {
+		top, bottom = min(l.visualAnchor, l.selectedIndex), max(l.visualAnchor, l.selectedIndex)
+	}
+	oldest, newest = &l.logOutput.Changes[bottom], &l.logOutput.Changes[top]
+	if l.reversed {
+		oldest, newest = newest, oldest
+	}
+	return oldest, newest
+}
+
+// inRange reports whether the change at index i is highlighted.
+func (l *LogPanel) inRange(i int) bool {
+	if !l.InVisualMode() {
+		return i == l.selectedIndex
+	}
+	start, end := l.visualAnchor, l.selectedIndex
+	if start > end {
+		start, end = end, start
+	}
+	return i >= start && i <= end
 }
 
 // Refresh reloads the log from the CLI.
@@ -69,6 +231,38 @@ func (l *LogPanel) Refresh() {
 	}
 }
 
+// SetRevset switches the log to revset, keeping the selected change if
+// it's still shown. A non-empty label is shown in the title as the filter.
+func (l *LogPanel) SetRevset(revset, label string) {
+	selected := l.SelectedChange()
+	l.revset = revset
+	l.filterLabel = label
+	l.selectedIndex = 0
+	l.visualAnchor = -1
+	l.Refresh()
+	if selected != nil {
+		l.SelectByChangeID(selected.ChangeID)
+	}
+}
+
+// ToggleReversed flips the log between newest and oldest first, keeping
+// the same change selected. It ends visual mode, since the range would
+// flip too.
+func (l *LogPanel) ToggleReversed() {
+	selected := l.SelectedChange()
+	l.reversed = !l.reversed
+	l.visualAnchor = -1
+	l.Refresh()
+	if selected != nil && l.SelectByChangeID(selected.ChangeID) {
+		l.centerSelected()
+	}
+}
+
+// Reversed returns true when the oldest changes are listed first
+func (l *LogPanel) Reversed() bool {
+	return l.reversed
+}
+
 // SelectedChange returns the currently selected change, or nil if none.
 func (l *LogPanel) SelectedChange() *jj.ChangeInfo {
 	if l.logOutput == nil || len(l.logOutput.Changes) == 0 {
@@ -88,10 +282,11 @@ func (l *LogPanel) GetChanges() []jj.ChangeInfo {
 	return l.logOutput.Changes
 }
 
-// SelectByChangeID selects the change with the given change ID
-func (l *LogPanel) SelectByChangeID(changeID string) {
+// SelectByChangeID selects the change with the given change ID. It
+// returns false if the change isn't in the log.
+func (l *LogPanel) SelectByChangeID(changeID string) bool {
 	if l.logOutput == nil {
-		return
+		return false
 	}
 	for i, change := range l.logOutput.Changes {
 		if change.ChangeID == changeID {
@@ -100,8 +295,95 @@ func (l *LogPanel) SelectByChangeID(changeID string) {
 			if l.ready {
 				l.viewport.SetContent(l.renderLog())
 			}
+			return true
+		}
+	}
+	return false
+}
+
+// SelectByCommitID selects the change whose commit ID matches, comparing
+// as prefixes since the log shows short IDs
+func (l *LogPanel) SelectByCommitID(commitID string) bool {
+	if l.logOutput == nil || commitID == "" {
+		return false
+	}
+	for i, change := range l.logOutput.Changes {
+		if change.CommitID != "" && (strings.HasPrefix(commitID, change.CommitID) || strings.HasPrefix(change.CommitID, commitID)) {
+			l.selectedIndex = i
+			l.ensureSelectedVisible()
+			if l.ready {
+				l.viewport.SetContent(l.renderLog())
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// StartPrefixJump starts typing a change ID prefix: each key narrows it,
+// selecting the first matching change, until the prefix is unique
+func (l *LogPanel) StartPrefixJump() {
+	l.jumping = true
+	l.jumpPrefix = ""
+	l.jumpFrom = l.selectedIndex
+}
+
+// PrefixJumping reports whether a change ID prefix is being typed
+func (l *LogPanel) PrefixJumping() bool {
+	return l.jumping
+}
+
+// TypePrefix handles a key while jumping. Characters no change ID
+// continues with are ignored; enter keeps the selection and esc goes back
+// to where the jump started.
+func (l *LogPanel) TypePrefix(msg tea.KeyMsg) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		l.jumping = false
+	case tea.KeyEsc, tea.KeyCtrlG, tea.KeyCtrlC:
+		l.jumping = false
+		l.selectIndex(l.jumpFrom)
+	case tea.KeyBackspace:
+		if l.jumpPrefix != "" {
+			l.jumpPrefix = l.jumpPrefix[:len(l.jumpPrefix)-1]
+			l.jumpToPrefix()
+		}
+	case tea.KeyRunes:
+		typed := l.jumpPrefix + strings.ToLower(string(msg.Runes))
+		if l.changeIDs == nil || len(l.changeIDs.Matching(typed)) == 0 {
 			return
 		}
+		l.jumpPrefix = typed
+		if id, ok := l.changeIDs.Resolve(typed); ok {
+			l.jumping = false
+			l.SelectByChangeID(id)
+			return
+		}
+		l.jumpToPrefix()
+	}
+}
+
+// jumpToPrefix selects the first change matching the typed prefix, or the
+// change the jump started from when nothing is typed
+func (l *LogPanel) jumpToPrefix() {
+	if l.jumpPrefix == "" {
+		l.selectIndex(l.jumpFrom)
+		return
+	}
+	if matches := l.changeIDs.Matching(l.jumpPrefix); len(matches) > 0 {
+		l.SelectByChangeID(matches[0])
+	}
+}
+
+// selectIndex selects the change at index i, if the log still has it
+func (l *LogPanel) selectIndex(i int) {
+	if l.logOutput == nil || i < 0 || i >= len(l.logOutput.Changes) {
+		return
+	}
+	l.selectedIndex = i
+	l.ensureSelectedVisible()
+	if l.ready {
+		l.viewport.SetContent(l.renderLog())
 	}
 }
 
@@ -114,8 +396,14 @@ func (l *LogPanel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.MouseMsg:
-		// Handle scroll wheel
 		switch msg.Button {
+		case tea.MouseButtonLeft:
+			if msg.Action == tea.MouseActionPress && l.onMinimap(msg.X) {
+				l.jumpToMinimapRow(msg.Y - 1)
+			} else if msg.Action == tea.MouseActionPress {
+				// Convert Y to log line (subtract 1 for top border, add viewport offset)
+				l.selectLine(msg.Y - 1 + l.viewport.YOffset)
+			}
 		case tea.MouseButtonWheelUp:
 			l.viewport.LineUp(3)
 		case tea.MouseButtonWheelDown:
@@ -159,6 +447,26 @@ func (l *LogPanel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return l, cmd
 }
 
+// selectLine selects the change that owns the given log line.
+func (l *LogPanel) selectLine(line int) {
+	if l.logOutput == nil || line < 0 || line >= len(l.logOutput.LineToChange) {
+		return
+	}
+	changeID := l.logOutput.LineToChange[line]
+	if changeID == "" {
+		return
+	}
+	for i, change := range l.logOutput.Changes {
+		if change.ChangeID == changeID {
+			l.selectedIndex = i
+			if l.ready {
+				l.viewport.SetContent(l.renderLog())
+			}
+			return
+		}
+	}
+}
+
 func (l *LogPanel) selectNext() {
 	if l.logOutput == nil || len(l.logOutput.Changes) == 0 {
 		return
@@ -200,6 +508,24 @@ func (l *LogPanel) ensureSelectedVisible() {
 	}
 }
 
+// ScrollOffset returns the first log line in view
+func (l *LogPanel) ScrollOffset() int {
+	if !l.ready {
+		return 0
+	}
+	return l.viewport.YOffset
+}
+
+// SetScrollOffset scrolls the log to offset, then as little as needed to
+// keep the selected change in view
+func (l *LogPanel) SetScrollOffset(offset int) {
+	if !l.ready {
+		return
+	}
+	l.viewport.SetYOffset(offset)
+	l.ensureSelectedVisible()
+}
+
 func (l *LogPanel) centerSelected() {
 	if l.logOutput == nil || len(l.logOutput.Changes) == 0 || !l.ready {
 		return
@@ -219,7 +545,94 @@ func (l *LogPanel) View() string {
 		return l.RenderFrame("Loading log...")
 	}
 
-	return l.RenderFrame(l.viewport.View())
+	if !l.minimap {
+		return l.RenderFrame(l.viewport.View())
+	}
+	lines := strings.Split(l.viewport.View(), "\n")
+	for i, cell := range l.renderMinimap() {
+		if i < len(lines) {
+			lines[i] += cell
+		}
+	}
+	return l.RenderFrame(strings.Join(lines, "\n"))
+}
+
+// minimapShown returns true when the log is longer than the panel, so the
+// minimap has something to show
+func (l *LogPanel) minimapShown() bool {
+	return l.minimap && l.ready && l.viewport.TotalLineCount() > l.viewport.Height
+}
+
+// minimapRows returns the range of log lines minimap row covers
+func (l *LogPanel) minimapRows(row int) (first, last int) {
+	total, height := l.viewport.TotalLineCount(), l.viewport.Height
+	return row * total / height, max((row+1)*total/height, row*total/height+1)
+}
+
+// renderMinimap renders one cell per row: @ for the working copy, ◆ for
+// bookmarks, a bar for the visible window and a thin line elsewhere
+func (l *LogPanel) renderMinimap() []string {
+	cells := make([]string, l.viewport.Height)
+	if !l.minimapShown() {
+		for i := range cells {
+			cells[i] = " "
+		}
+		return cells
+	}
+
+	workingCopy, bookmarked := -1, make(map[int]bool)
+	for _, change := range l.logOutput.Changes {
+		if change.IsWorkingCopy {
+			workingCopy = change.StartLine
+		}
+		if len(change.Bookmarks) > 0 {
+			bookmarked[change.StartLine] = true
+		}
+	}
+
+	viewTop := l.viewport.YOffset
+	viewBottom := viewTop + l.viewport.Height
+	for row := range cells {
+		first, last := l.minimapRows(row)
+		hasBookmark := false
+		for line := first; line < last; line++ {
+			hasBookmark = hasBookmark || bookmarked[line]
+		}
+		switch {
+		case workingCopy >= first && workingCopy < last:
+			cells[row] = theme.WorkingCopyStyle.Render("@")
+		case hasBookmark:
+			cells[row] = theme.CurrentBookmarkStyle.Render("◆")
+		case first < viewBottom && last > viewTop:
+			cells[row] = theme.NormalItemStyle.Render("┃")
+		default:
+			cells[row] = theme.DimmedStyle.Render("│")
+		}
+	}
+	return cells
+}
+
+// onMinimap returns true when panel column x is in the minimap
+func (l *LogPanel) onMinimap(x int) bool {
+	right := l.width - 2 // Last column inside the border
+	return l.minimapShown() && x > right-minimapWidth && x <= right
+}
+
+// jumpToMinimapRow centers the log on the lines minimap row covers and
+// selects the change there
+func (l *LogPanel) jumpToMinimapRow(row int) {
+	if row < 0 || row >= l.viewport.Height {
+		return
+	}
+	first, _ := l.minimapRows(row)
+	l.viewport.SetYOffset(max(first-l.viewport.Height/2, 0))
+	// Select the first change starting at or after the line
+	for line := first; line < len(l.logOutput.LineToChange); line++ {
+		if l.logOutput.LineToChange[line] != "" {
+			l.selectLine(line)
+			return
+		}
+	}
 }
 
 // SetSize resizes the panel and viewport.
@@ -228,6 +641,9 @@ func (l *LogPanel) SetSize(width, height int) {
 
 	contentWidth := l.ContentWidth()
 	contentHeight := l.ContentHeight()
+	if l.minimap {
+		contentWidth = max(contentWidth-minimapWidth, 0)
+	}
 
 	if !l.ready {
 		l.viewport = viewport.New(contentWidth, contentHeight)
@@ -247,20 +663,56 @@ func (l *LogPanel) renderLog() string {
 	}
 
 	lines := strings.Split(l.logOutput.RawANSI, "\n")
-	var selectedChangeID string
 
-	// Get the selected change ID
-	if len(l.logOutput.Changes) > 0 && l.selectedIndex < len(l.logOutput.Changes) {
-		selectedChangeID = l.logOutput.Changes[l.selectedIndex].ChangeID
+	// Collect the highlighted change IDs (the selection, or the visual range)
+	highlighted := make(map[string]bool)
+	for i, change := range l.logOutput.Changes {
+		if l.inRange(i) {
+			highlighted[change.ChangeID] = true
+		}
+	}
+
+	// Flag markers go at the end of each change's first line, and
+	// immutable changes are dimmed
+	markers := make(map[int]string)
+	immutable := make(map[string]bool)
+	for _, change := range l.logOutput.Changes {
+		immutable[change.ChangeID] = change.Immutable
+		marker := graph.Markers(graph.RevisionInfo{
+			Divergent: change.Divergent,
+			Hidden:    change.Hidden,
+			Immutable: change.Immutable,
+		}, theme.DivergentStyle, theme.HiddenStyle, theme.ImmutableStyle)
+		if n := len(change.CoAuthors); n == 1 {
+			marker = strings.TrimSpace(marker + " " + theme.AuthorStyle.Render("co-author "+change.CoAuthors[0]))
+		} else if n > 1 {
+			marker = strings.TrimSpace(marker + " " + theme.AuthorStyle.Render(fmt.Sprintf("%d co-authors", n)))
+		}
+		// The root commit can't be signed, so it goes without a badge
+		if signed, ok := l.signed[change.CommitID]; ok && strings.Trim(change.CommitID, "0") != "" {
+			badge := theme.UnsignedStyle.Render("unsigned")
+			if signed {
+				badge = theme.SignedStyle.Render("signed")
+			}
+			marker = strings.TrimSpace(marker + " " + badge)
+		}
+		if marker != "" {
+			markers[change.StartLine] = marker
+		}
 	}
 
 	// Apply selection highlighting
 	var result []string
 	for i, line := range lines {
-		// Check if this line belongs to the selected change
-		if i < len(l.logOutput.LineToChange) && l.logOutput.LineToChange[i] == selectedChangeID && selectedChangeID != "" {
-			// Add background highlight, preserving existing ANSI codes
-			line = selectionBgStart + line + selectionBgEnd
+		if i < len(l.logOutput.LineToChange) && immutable[l.logOutput.LineToChange[i]] {
+			line = theme.DimmedStyle.Render(ansi.Strip(line))
+		}
+		if marker, ok := markers[i]; ok {
+			line += " " + marker
+		}
+		// Check if this line belongs to a highlighted change
+		if i < len(l.logOutput.LineToChange) && highlighted[l.logOutput.LineToChange[i]] {
+			line = highlightLine(line)
 		}
 		result = append(result, line)
 	}
@@ -268,12 +720,68 @@ func (l *LogPanel) renderLog() string {
 	return strings.Join(result, "\n")
 }
 
+// highlightLine puts the selection background behind line. jj's colored
+// output resets attributes mid-line, which clears a background set once at
+// the start, so it's set again after every SGR sequence that clears it.
+func highlightLine(line string) string {
+	var b strings.Builder
+	b.WriteString(selectionBgStart)
+	for {
+		start := strings.Index(line, "\x1b[")
+		if start < 0 {
+			b.WriteString(line)
+			break
+		}
+		// The sequence ends at its final byte, in the range @ to ~
+		end := strings.IndexFunc(line[start+2:], func(r rune) bool { return r >= '@' && r <= '~' })
+		if end < 0 {
+			b.WriteString(line)
+			break
+		}
+		end += start + 2
+		b.WriteString(line[:end+1])
+		if line[end] == 'm' && clearsBackground(line[start+2:end]) {
+			b.WriteString(selectionBgStart)
+		}
+		line = line[end+1:]
+	}
+	b.WriteString(selectionBgEnd)
+	return b.String()
+}
+
+// clearsBackground reports whether an SGR sequence's parameters reset the
+// background: a full reset (0, or no parameters) or the default
+// background (49). Color arguments of 38, 48 and 58 are skipped, so the 0
+// in 38;5;0 doesn't count.
+func clearsBackground(params string) bool {
+	args := strings.Split(params, ";")
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "", "0", "00", "49":
+			return true
+		case "38", "48", "58":
+			if i+1 < len(args) && args[i+1] == "5" {
+				i += 2
+			} else if i+1 < len(args) && args[i+1] == "2" {
+				i += 4
+			}
+		}
+	}
+	return false
+}
+
 // RenderFrame renders the panel with titled border.
 func (l *LogPanel) RenderFrame(content string) string {
 	title := l.title
+	if l.filterLabel != "" {
+		title += " [" + l.filterLabel + "]"
+	}
+	if l.jumping {
+		title += " go to " + l.jumpPrefix + "_"
+	}
 	if l.ready && l.viewport.TotalLineCount() > l.viewport.Height {
 		scrollPercent := int(l.viewport.ScrollPercent() * 100)
-		title = fmt.Sprintf("%s (%d%%)", l.title, scrollPercent)
+		title = fmt.Sprintf("%s (%d%%)", title, scrollPercent)
 	}
 
 	return borders.RenderTitledBorder(content, title, l.width, l.height, l.focused)
@@ -0,0 +1,108 @@
+package panels
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestHighlightLineAfterResets verifies the background is set again after
+// each reset in jj's output, and not after other SGR sequences
+func TestHighlightLineAfterResets(t *testing.T) {
+	line := "\x1b[1m\x1b[38;5;2m@\x1b[0m  \x1b[38;5;5mkmno\x1b[39m main\x1b[m end"
+	got := highlightLine(line)
+	want := selectionBgStart +
+		"\x1b[1m\x1b[38;5;2m@\x1b[0m" + selectionBgStart +
+		"  \x1b[38;5;5mkmno\x1b[39m main\x1b[m" + selectionBgStart +
+		" end" + selectionBgEnd
+	if got != want {
+		t.Errorf("highlightLine(%q)\n got %q\nwant %q", line, got, want)
+	}
+}
+
+// TestHighlightLineColorArguments verifies a 0 inside a color isn't taken
+// for a reset
+func TestHighlightLineColorArguments(t *testing.T) {
+	for _, seq := range []string{"\x1b[38;5;0m", "\x1b[38;2;0;0;0m", "\x1b[1;38;5;0;4m"} {
+		if got := highlightLine(seq + "x"); strings.Count(got, selectionBgStart) != 1 {
+			t.Errorf("Expected no re-injected background after %q, got %q", seq, got)
+		}
+	}
+	if got := highlightLine("\x1b[1;0mx"); strings.Count(got, selectionBgStart) != 2 {
+		t.Errorf("Expected a reset among other parameters to count, got %q", got)
+	}
+}
+
+// TestHighlightLinePlain verifies a line without escapes is just wrapped
+func TestHighlightLinePlain(t *testing.T) {
+	if got, want := highlightLine("plain"), selectionBgStart+"plain"+selectionBgEnd; got != want {
+		t.Errorf("highlightLine(plain) = %q, want %q", got, want)
+	}
+}
+
+// typeKeys sends each rune of s to the log's prefix jump
+func typeKeys(l *LogPanel, s string) {
+	for _, r := range s {
+		l.TypePrefix(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+// TestPrefixJump verifies typing a change ID prefix selects the first
+// match, ends the jump once the prefix is unique, and ignores characters
+// no change ID continues with
+func TestPrefixJump(t *testing.T) {
+	l := NewLogPreviewPanel("0 Log")
+	l.SetSize(80, 30)
+	l.SetOutput(goldenLog(12), nil)
+
+	l.StartPrefixJump()
+	typeKeys(l, "CHANGE1x")
+	if !l.PrefixJumping() || l.SelectedChange().ChangeID != "change10" {
+		t.Fatalf("Expected change10 selected while jumping, got %s (jumping %v)", l.SelectedChange().ChangeID, l.PrefixJumping())
+	}
+	typeKeys(l, "1")
+	if l.PrefixJumping() || l.SelectedChange().ChangeID != "change11" {
+		t.Errorf("Expected the unique prefix to select change11 and end the jump, got %s (jumping %v)", l.SelectedChange().ChangeID, l.PrefixJumping())
+	}
+}
+
+// TestPrefixJumpCancel verifies esc goes back to the change selected
+// before the jump
+func TestPrefixJumpCancel(t *testing.T) {
+	l := NewLogPreviewPanel("0 Log")
+	l.SetSize(80, 30)
+	l.SetOutput(goldenLog(12), nil)
+	l.SelectByChangeID("change03")
+
+	l.StartPrefixJump()
+	typeKeys(l, "change1")
+	l.TypePrefix(tea.KeyMsg{Type: tea.KeyEsc})
+	if l.PrefixJumping() || l.SelectedChange().ChangeID != "change03" {
+		t.Errorf("Expected esc to restore change03, got %s (jumping %v)", l.SelectedChange().ChangeID, l.PrefixJumping())
+	}
+}
+
+// TestRangeEnds verifies the visual range's ends are ordered oldest first,
+// whichever way the range was drawn and the log runs
+func TestRangeEnds(t *testing.T) {
+	l := NewLogPreviewPanel("0 Log")
+	l.SetSize(80, 30)
+	l.SetOutput(goldenLog(12), nil)
+
+	l.SelectByChangeID("change02")
+	if oldest, newest := l.RangeEnds(); oldest.ChangeID != "change02" || newest.ChangeID != "change02" {
+		t.Errorf("Expected the selection at both ends outside visual mode, got %s and %s", oldest.ChangeID, newest.ChangeID)
+	}
+
+	l.SetVisualMode(true)
+	l.SelectByChangeID("change05")
+	if oldest, newest := l.RangeEnds(); oldest.ChangeID != "change05" || newest.ChangeID != "change02" {
+		t.Errorf("Expected change05::change02, got %s::%s", oldest.ChangeID, newest.ChangeID)
+	}
+
+	l.reversed = true
+	if oldest, newest := l.RangeEnds(); oldest.ChangeID != "change02" || newest.ChangeID != "change05" {
+		t.Errorf("Expected the ends swapped in a reversed log, got %s::%s", oldest.ChangeID, newest.ChangeID)
+	}
+}
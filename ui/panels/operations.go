@@ -7,6 +7,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/gerunddev/jjazy/jj"
 	"github.com/gerunddev/jjazy/ui/fixtures"
+	"github.com/gerunddev/jjazy/ui/messages"
 	"github.com/gerunddev/jjazy/ui/theme"
 )
 
@@ -22,7 +23,7 @@ type OperationsPanel struct {
 // NewOperationsPanel creates a new operations panel
 func NewOperationsPanel(repo *jj.Repo) *OperationsPanel {
 	p := &OperationsPanel{
-		BasePanel: NewBasePanel("4 Operations", "undo"),
+		BasePanel: NewBasePanel("1 Operations", "undo"),
 		repo:      repo,
 	}
 	p.loadOperations()
@@ -44,7 +45,7 @@ func (p *OperationsPanel) loadOperations() {
 		p.operations[i] = fixtures.Operation{
 			ID:          op.ID,
 			Description: op.Description,
-			Timestamp:   op.Timestamp, // Unix timestamp (TODO: format as relative time)
+			Timestamp:   formatOpTime(op.Timestamp),
 			IsCurrent:   op.IsCurrent,
 		}
 	}
@@ -55,6 +56,8 @@ func (p *OperationsPanel) Init() tea.Cmd {
 }
 
 func (p *OperationsPanel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	prevCursor := p.cursor
+
 	switch msg := msg.(type) {
 	case tea.MouseMsg:
 		switch msg.Button {
@@ -100,9 +103,43 @@ func (p *OperationsPanel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		p.viewport.SetContent(p.renderContent())
 	}
 
+	// Emit selection message if cursor changed
+	if p.cursor != prevCursor {
+		if op := p.SelectedOperation(); op != nil {
+			return p, func() tea.Msg {
+				return messages.OperationSelectedMsg{OpID: op.ID}
+			}
+		}
+	}
+
 	return p, nil
 }
 
+// Refresh reloads the operation list
+func (p *OperationsPanel) Refresh() {
+	p.loadOperations()
+	if p.cursor >= len(p.operations) {
+		p.cursor = 0
+	}
+	if p.ready {
+		p.viewport.SetContent(p.renderContent())
+	}
+}
+
+// SelectOperation moves the cursor to the operation with the given ID
+func (p *OperationsPanel) SelectOperation(opID string) {
+	for i, op := range p.operations {
+		if op.ID == opID {
+			p.cursor = i
+			if p.ready {
+				p.ensureCursorVisible()
+				p.viewport.SetContent(p.renderContent())
+			}
+			return
+		}
+	}
+}
+
 func (p *OperationsPanel) ensureCursorVisible() {
 	if p.cursor < p.viewport.YOffset {
 		p.viewport.SetYOffset(p.cursor)
@@ -17,6 +17,21 @@ type Panel interface {
 	SetSize(width, height int)
 }
 
+// ActionHandler is implemented by panels with keys that act on the repo or
+// the app, beyond the navigation Update does. The focused panel is offered
+// each key the app's global keys didn't take; handled reports whether it
+// used the key, otherwise the key goes on to Update.
+type ActionHandler interface {
+	HandleAction(msg tea.KeyMsg) (cmd tea.Cmd, handled bool)
+}
+
+// ActionHandlerFunc adapts a function to ActionHandler
+type ActionHandlerFunc func(msg tea.KeyMsg) (tea.Cmd, bool)
+
+func (f ActionHandlerFunc) HandleAction(msg tea.KeyMsg) (tea.Cmd, bool) {
+	return f(msg)
+}
+
 // BasePanel provides common functionality for all panels
 type BasePanel struct {
 	title     string
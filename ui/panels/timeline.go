@@ -0,0 +1,184 @@
+package panels
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/messages"
+	"github.com/gerunddev/jjazy/ui/theme"
+)
+
+// TimelineHeight is the rows a timeline needs: the strip and a caption, plus borders
+const TimelineHeight = 4
+
+// TimelinePanel is a horizontal strip of operations, oldest on the left,
+// that left/right scrubs through
+type TimelinePanel struct {
+	BasePanel
+	repo       *jj.Repo
+	operations []jj.Operation // Oldest first
+	offset     int            // First operation shown in the strip
+}
+
+// NewTimelinePanel creates a timeline positioned at the current operation
+func NewTimelinePanel(repo *jj.Repo) *TimelinePanel {
+	t := &TimelinePanel{
+		BasePanel: NewBasePanel("0 Timeline", "scrub"),
+		repo:      repo,
+	}
+	t.Refresh()
+	return t
+}
+
+// Refresh reloads operations and returns to the present
+func (t *TimelinePanel) Refresh() {
+	ops, err := t.repo.Operations()
+	if err != nil {
+		ops = nil
+	}
+
+	// The bridge lists newest first; the timeline reads left to right
+	t.operations = make([]jj.Operation, len(ops))
+	for i, op := range ops {
+		t.operations[len(ops)-1-i] = op
+	}
+	t.CursorEnd(len(t.operations))
+	t.ensureCursorVisible()
+}
+
+func (t *TimelinePanel) Init() tea.Cmd {
+	return nil
+}
+
+func (t *TimelinePanel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok || !t.focused {
+		return t, nil
+	}
+
+	prevCursor := t.cursor
+	switch keyMsg.String() {
+	case "left", "h", "ctrl+b":
+		t.CursorUp(len(t.operations))
+	case "right", "l", "ctrl+f":
+		t.CursorDown(len(t.operations))
+	case "home", "alt+<":
+		t.CursorHome()
+	case "end", "alt+>":
+		t.CursorEnd(len(t.operations))
+	}
+	t.ensureCursorVisible()
+
+	if t.cursor != prevCursor {
+		if op := t.SelectedOperation(); op != nil {
+			return t, func() tea.Msg {
+				return messages.OperationSelectedMsg{OpID: op.ID}
+			}
+		}
+	}
+	return t, nil
+}
+
+// SelectOperation moves the scrubber to the operation with the given ID
+func (t *TimelinePanel) SelectOperation(opID string) {
+	for i, op := range t.operations {
+		if op.ID == opID {
+			t.cursor = i
+			t.ensureCursorVisible()
+			return
+		}
+	}
+}
+
+// SelectedOperation returns the operation under the scrubber, or nil if none
+func (t *TimelinePanel) SelectedOperation() *jj.Operation {
+	if t.cursor >= 0 && t.cursor < len(t.operations) {
+		return &t.operations[t.cursor]
+	}
+	return nil
+}
+
+// slots returns how many operations fit in the strip; each takes two
+// columns, and one column each side is kept for overflow arrows
+func (t *TimelinePanel) slots() int {
+	return max((t.ContentWidth()-2)/2, 1)
+}
+
+func (t *TimelinePanel) ensureCursorVisible() {
+	slots := t.slots()
+	if t.cursor < t.offset {
+		t.offset = t.cursor
+	} else if t.cursor >= t.offset+slots {
+		t.offset = t.cursor - slots + 1
+	}
+	// Keep the strip full when it can hold more than is past the offset
+	t.offset = max(min(t.offset, len(t.operations)-slots), 0)
+}
+
+func (t *TimelinePanel) View() string {
+	return t.RenderFrame(t.renderContent())
+}
+
+// SetSize resizes the panel, keeping the scrubber in view
+func (t *TimelinePanel) SetSize(width, height int) {
+	t.BasePanel.SetSize(width, height)
+	t.ensureCursorVisible()
+}
+
+func (t *TimelinePanel) renderContent() string {
+	op := t.SelectedOperation()
+	if op == nil {
+		return theme.DimmedStyle.Render("No operations")
+	}
+
+	end := min(t.offset+t.slots(), len(t.operations))
+
+	var strip strings.Builder
+	if t.offset > 0 {
+		strip.WriteString(theme.DimmedStyle.Render("‹"))
+	} else {
+		strip.WriteString(" ")
+	}
+	for i := t.offset; i < end; i++ {
+		switch {
+		case i == t.cursor:
+			strip.WriteString(theme.SelectedItemStyle.Render("◆"))
+		case t.operations[i].IsCurrent:
+			strip.WriteString(theme.WorkingCopyStyle.Render("●"))
+		default:
+			strip.WriteString(theme.DimmedStyle.Render("○"))
+		}
+		if i < end-1 {
+			strip.WriteString(theme.DimmedStyle.Render("─"))
+		}
+	}
+	if end < len(t.operations) {
+		strip.WriteString(theme.DimmedStyle.Render(" ›"))
+	}
+
+	// Caption: when and what the selected operation was
+	timestamp := formatOpTime(op.Timestamp)
+	maxDescLen := t.ContentWidth() - len(timestamp) - 1
+	desc := op.Description
+	if len(desc) > maxDescLen && maxDescLen > 0 {
+		desc = truncate(desc, maxDescLen)
+	}
+	caption := theme.TimestampStyle.Render(timestamp) + " " + theme.NormalItemStyle.Render(desc)
+
+	return strip.String() + "\n" + caption
+}
+
+// formatOpTime renders the bridge's millisecond timestamp as local time
+func formatOpTime(timestamp string) string {
+	millis, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return timestamp
+	}
+	return time.UnixMilli(millis).Format("2006-01-02 15:04")
+}
+
+// Ensure TimelinePanel implements Panel
+var _ Panel = (*TimelinePanel)(nil)
@@ -16,6 +16,7 @@ type WorkspacePanel struct {
 	BasePanel
 	repo       *jj.Repo
 	workspaces []jj.Workspace
+	parent     string // First line of the description of @'s parents, shown in the header
 	viewport   viewport.Model
 	ready      bool
 }
@@ -43,12 +44,62 @@ func NewWorkspacePanel(repo *jj.Repo) *WorkspacePanel {
 }
 
 func (p *WorkspacePanel) loadWorkspaces() {
+	p.parent = ""
 	workspaces, err := p.repo.Workspaces()
 	if err != nil {
 		p.workspaces = nil
 		return
 	}
 	p.workspaces = workspaces
+
+	for _, ws := range workspaces {
+		if ws.IsCurrent {
+			if revisions, err := p.repo.Log(); err == nil {
+				p.parent = parentDescription(revisions, ws.CommitID)
+			}
+		}
+	}
+}
+
+// parentDescription returns the first line of the description of the
+// parents of the working copy at commitID, joined with " + " for a merge.
+// Parents outside the log are left out.
+func parentDescription(revisions []jj.Revision, commitID string) string {
+	byID := make(map[string]*jj.Revision, len(revisions))
+	var workingCopy *jj.Revision
+	for i := range revisions {
+		rev := &revisions[i]
+		byID[rev.ID] = rev
+		// Log and workspace commit IDs may be shortened to different lengths
+		if rev.IsWorkingCopy && rev.ID != "" && (strings.HasPrefix(commitID, rev.ID) || strings.HasPrefix(rev.ID, commitID)) {
+			workingCopy = rev
+		}
+	}
+	if workingCopy == nil {
+		return ""
+	}
+
+	var lines []string
+	for _, id := range workingCopy.Parents {
+		parent := byID[id]
+		if parent == nil {
+			continue
+		}
+		line, _, _ := strings.Cut(parent.Description, "\n")
+		if line == "" {
+			line = "(no description)"
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, " + ")
+}
+
+// header returns the panel title followed by what @ is on top of
+func (p *WorkspacePanel) header(title string) string {
+	if p.parent == "" {
+		return title
+	}
+	return title + " · @- " + p.parent
 }
 
 // Refresh reloads workspace data and re-renders.
@@ -122,6 +173,14 @@ func (p *WorkspacePanel) ensureCursorVisible() {
 }
 
 func (p *WorkspacePanel) View() string {
+	if p.height == 1 {
+		// Collapsed to a header line naming the only workspace
+		title := p.title
+		if len(p.workspaces) == 1 {
+			title += ": " + p.workspaces[0].Name
+		}
+		return borders.RenderTitleLine(p.header(title), p.width, p.focused)
+	}
 	if !p.ready {
 		return p.RenderFrame("Loading...")
 	}
@@ -133,7 +192,7 @@ func (p *WorkspacePanel) RenderFrame(content string) string {
 	// Focus mode: yellow border (focused && !entered)
 	// Cursor mode: white border (entered)
 	showFocusBorder := p.focused && !p.entered
-	return borders.RenderTitledBorder(content, p.title, p.width, p.height, showFocusBorder)
+	return borders.RenderTitledBorder(content, p.header(p.title), p.width, p.height, showFocusBorder)
 }
 
 // SetSize initializes or resizes the viewport
@@ -141,7 +200,7 @@ func (p *WorkspacePanel) SetSize(width, height int) {
 	p.BasePanel.SetSize(width, height)
 
 	contentWidth := p.ContentWidth()
-	contentHeight := p.ContentHeight()
+	contentHeight := max(p.ContentHeight(), 0) // Collapsed to one row
 
 	if !p.ready {
 		p.viewport = viewport.New(contentWidth, contentHeight)
@@ -0,0 +1,28 @@
+package panels
+
+import (
+	"testing"
+
+	"github.com/gerunddev/jjazy/jj"
+)
+
+// TestParentDescription verifies the working copy's parents are described
+// by their first line, whatever the commit ID lengths
+func TestParentDescription(t *testing.T) {
+	revisions := []jj.Revision{
+		{ID: "aaaaaaaaaaaa", IsWorkingCopy: true, Parents: []string{"bbbbbbbbbbbb", "cccccccccccc"}},
+		{ID: "bbbbbbbbbbbb", Description: "Fix login\n\nDetails"},
+		{ID: "cccccccccccc"},
+		{ID: "dddddddddddd", IsWorkingCopy: true, Parents: []string{"eeeeeeeeeeee"}},
+	}
+
+	if got := parentDescription(revisions, "aaaaaaaaaaaa0123456789"); got != "Fix login + (no description)" {
+		t.Errorf("parentDescription() = %q, want both parents", got)
+	}
+	if got := parentDescription(revisions, "dddddddddddd"); got != "" {
+		t.Errorf("parentDescription() = %q, want nothing for a parent outside the log", got)
+	}
+	if got := parentDescription(revisions, "ffffffffffff"); got != "" {
+		t.Errorf("parentDescription() = %q, want nothing for an unknown working copy", got)
+	}
+}
@@ -0,0 +1,106 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// PickKind selects what the app picks when run as a picker for scripts
+type PickKind int
+
+const (
+	PickNone     PickKind = iota // Normal interactive use
+	PickRevision                 // --pick: choose a change in the log
+	PickFiles                    // --pick-file: choose files in a revision
+)
+
+// SetPickMode turns the app into a revision picker: Enter on a log row
+// records its change ID and quits, and mutating actions are disabled
+func (a *App) SetPickMode() {
+	a.pickMode = PickRevision
+	a.logPanel.SetTitle("0 Pick")
+}
+
+// SetPickFileMode turns the app into a file picker for a revision: space
+// marks files, Enter records the marked files (or the one under the cursor)
+// and quits
+func (a *App) SetPickFileMode(revision string) {
+	a.pickMode = PickFiles
+	a.enterChangeExperience(revision, false)
+	a.filesPanel.SetTitle("1 Pick Files")
+	a.setFocus(1)
+}
+
+// Picked returns what was chosen in pick mode, or nil if nothing was
+func (a *App) Picked() []string {
+	return a.picked
+}
+
+// pickSelected records the current selection and quits
+func (a *App) pickSelected() tea.Cmd {
+	switch a.pickMode {
+	case PickRevision:
+		if change := a.logPanel.SelectedChange(); change != nil {
+			a.picked = []string{change.ChangeID}
+		}
+	case PickFiles:
+		a.picked = a.filesPanel.MarkedPaths()
+		if len(a.picked) == 0 {
+			if file := a.filesPanel.SelectedFile(); file != nil {
+				a.picked = []string{file.Path}
+			}
+		}
+	}
+	if len(a.picked) == 0 {
+		return nil
+	}
+	return tea.Quit
+}
+
+// pickPanelFocused reports whether the panel that picks has focus
+func (a *App) pickPanelFocused() bool {
+	switch a.pickMode {
+	case PickRevision:
+		return a.focused(a.logPanel)
+	case PickFiles:
+		return a.focused(a.filesSidebar())
+	}
+	return false
+}
+
+// handlePickKey handles a key in pick mode. Navigation falls through to the
+// normal handlers; anything that could change the repo is swallowed.
+func (a *App) handlePickKey(msg tea.KeyMsg) (cmd tea.Cmd, handled bool) {
+	if a.showHelp {
+		return nil, false
+	}
+
+	switch {
+	case key.Matches(msg, a.keys.Enter):
+		if a.pickPanelFocused() {
+			return a.pickSelected(), true
+		}
+		return nil, true
+
+	case key.Matches(msg, a.keys.Space) && a.pickMode == PickFiles:
+		if a.pickPanelFocused() {
+			a.filesPanel.ToggleMark()
+		}
+		return nil, true
+
+	case a.pickMode == PickFiles && (key.Matches(msg, a.keys.Escape) || msg.Type == tea.KeyLeft):
+		// The file picker has no log to go back to
+		if a.focused(a.diffPanel) {
+			a.setFocus(1)
+		}
+		return nil, true
+
+	case a.allowedWhileBusy(msg),
+		key.Matches(msg, a.keys.Escape),
+		key.Matches(msg, a.keys.TogglePreview, a.keys.GoToChange),
+		key.Matches(msg, a.keys.Panel0, a.keys.Panel1, a.keys.Panel2):
+		return nil, false
+	}
+
+	return nil, true
+}
@@ -0,0 +1,118 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/ui/panels"
+)
+
+// PanelSlot declares where a registered panel is laid out
+type PanelSlot struct {
+	Experience Experience // Experience whose sidebar shows the panel
+	Rows       int        // Height including borders
+}
+
+// registeredPanel is a custom sidebar panel added with RegisterPanel
+type registeredPanel struct {
+	panel panels.Panel
+	slot  PanelSlot
+	hints []HelpHint
+}
+
+// minPluginRows is the smallest height a registered panel gets (borders + 1 row)
+const minPluginRows = 3
+
+// RegisterPanel adds a custom panel to the bottom of an experience's sidebar.
+// It joins tab focus cycling after the built-in panels, receives keys while
+// focused (first through HandleAction if it's a panels.ActionHandler) and
+// every non-key message, and its hints replace the help bar actions while
+// it is focused. A messages.RefreshRequestMsg tells it the repo changed.
+// Register panels before the program starts.
+func (a *App) RegisterPanel(p panels.Panel, slot PanelSlot, hints ...HelpHint) {
+	if slot.Rows < minPluginRows {
+		slot.Rows = minPluginRows
+	}
+	a.plugins = append(a.plugins, &registeredPanel{panel: p, slot: slot, hints: hints})
+	if a.ready {
+		a.updateLayout()
+	}
+}
+
+// builtinPanelCount returns how many built-in panels an experience has
+func builtinPanelCount(exp Experience) int {
+	return len(builtinPanels[exp])
+}
+
+// pluginsFor returns the registered panels shown in an experience, top to bottom
+func (a *App) pluginsFor(exp Experience) []*registeredPanel {
+	var shown []*registeredPanel
+	for _, p := range a.plugins {
+		if p.slot.Experience == exp {
+			shown = append(shown, p)
+		}
+	}
+	return shown
+}
+
+// pluginRows returns the sidebar rows taken by an experience's registered panels
+func (a *App) pluginRows(exp Experience) int {
+	rows := 0
+	for _, p := range a.pluginsFor(exp) {
+		rows += p.slot.Rows
+	}
+	return rows
+}
+
+// focusedPlugin returns the registered panel holding focus, or nil when a
+// built-in panel is focused
+func (a *App) focusedPlugin() *registeredPanel {
+	shown := a.pluginsFor(a.currentExperience)
+	i := a.focusedPanel - builtinPanelCount(a.currentExperience)
+	if i < 0 || i >= len(shown) {
+		return nil
+	}
+	return shown[i]
+}
+
+// layoutPlugins sizes the current experience's registered panels, stacked
+// below the built-in sidebar from row y, and adds their mouse bounds
+func (a *App) layoutPlugins(sidebarWidth, y int) {
+	index := builtinPanelCount(a.currentExperience)
+	for _, p := range a.pluginsFor(a.currentExperience) {
+		p.panel.SetSize(sidebarWidth, p.slot.Rows)
+		a.panelBounds = append(a.panelBounds, PanelBound{
+			X1: 0, Y1: y, X2: sidebarWidth - 1, Y2: y + p.slot.Rows - 1, PanelIndex: index,
+		})
+		y += p.slot.Rows
+		index++
+	}
+}
+
+// pluginViews renders the current experience's registered panels
+func (a *App) pluginViews() []string {
+	var views []string
+	for _, p := range a.pluginsFor(a.currentExperience) {
+		views = append(views, p.panel.View())
+	}
+	return views
+}
+
+// initPlugins runs every registered panel's Init
+func (a *App) initPlugins() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, p := range a.plugins {
+		cmds = append(cmds, p.panel.Init())
+	}
+	return tea.Batch(cmds...)
+}
+
+// updatePlugins forwards a non-key message to every registered panel so they
+// can handle their own async results
+func (a *App) updatePlugins(msg tea.Msg) []tea.Cmd {
+	var cmds []tea.Cmd
+	for _, p := range a.plugins {
+		if _, cmd := p.panel.Update(msg); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	}
+	return cmds
+}
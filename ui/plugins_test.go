@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/config"
+	"github.com/gerunddev/jjazy/ui/panels"
+)
+
+// fakePanel is a minimal registered panel that records the keys it receives
+type fakePanel struct {
+	panels.BasePanel
+	keys []string
+}
+
+func (f *fakePanel) Init() tea.Cmd { return nil }
+
+func (f *fakePanel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		f.keys = append(f.keys, key.String())
+	}
+	return f, nil
+}
+
+func (f *fakePanel) View() string { return f.RenderFrame("ci") }
+
+// TestRegisterPanel verifies registered panels join layout, focus cycling,
+// key routing and the help bar
+func TestRegisterPanel(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	ci := &fakePanel{BasePanel: panels.NewBasePanel("CI", "")}
+	a.RegisterPanel(ci, PanelSlot{Experience: ExperienceLog, Rows: 6}, HelpHint{Key: "r", Desc: "rerun"})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	if got := a.maxPanelsForExperience(); got != 4 {
+		t.Fatalf("maxPanelsForExperience() = %d, want 4", got)
+	}
+	if ci.Height() != 6 {
+		t.Errorf("registered panel height = %d, want 6", ci.Height())
+	}
+
+	// Tab from the log cycles through workspace and bookmarks to the panel
+	for i := 0; i < 3; i++ {
+		a.Update(tea.KeyMsg{Type: tea.KeyTab})
+	}
+	if !ci.IsFocused() || a.focusedPlugin() == nil {
+		t.Fatal("Expected registered panel to be focused after three tabs")
+	}
+
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if len(ci.keys) != 1 || ci.keys[0] != "x" {
+		t.Errorf("Expected focused panel to receive the key, got %v", ci.keys)
+	}
+
+	hints := getActionHints(HelpBarContext{Experience: ExperienceLog, FocusedPanel: 3, PanelHints: a.focusedPlugin().hints})
+	if len(hints) != 1 || hints[0].Key != "r" {
+		t.Errorf("Expected the panel's hints in the help bar, got %v", hints)
+	}
+
+	// Wraps back to the log
+	a.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if ci.IsFocused() || a.focusedPanel != 0 {
+		t.Errorf("Expected focus to wrap to the log, got panel %d", a.focusedPanel)
+	}
+}
+
+// actionPanel is a registered panel that acts on "r" itself
+type actionPanel struct {
+	fakePanel
+	actions int
+}
+
+func (p *actionPanel) HandleAction(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if msg.String() != "r" {
+		return nil, false
+	}
+	p.actions++
+	return nil, true
+}
+
+// TestRegisteredPanelActions verifies a focused panel's ActionHandler gets
+// keys first and passes on the ones it doesn't use
+func TestRegisteredPanelActions(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	ci := &actionPanel{fakePanel: fakePanel{BasePanel: panels.NewBasePanel("CI", "")}}
+	a.RegisterPanel(ci, PanelSlot{Experience: ExperienceLog, Rows: 6})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	a.setFocus(3)
+
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if ci.actions != 1 {
+		t.Errorf("Expected the panel to act on r once, got %d", ci.actions)
+	}
+	if len(ci.keys) != 1 || ci.keys[0] != "x" {
+		t.Errorf("Expected only the unhandled key in Update, got %v", ci.keys)
+	}
+}
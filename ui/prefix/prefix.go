@@ -1,6 +1,8 @@
 package prefix
 
 import (
+	"strings"
+
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -65,16 +67,40 @@ func FormatWithPrefix(id string, prefixLen int, prefixStyle, restStyle lipgloss.
 
 // IDSet holds IDs and their computed unique prefix lengths for efficient lookup.
 type IDSet struct {
+	ids      []string
 	prefixes map[string]int
 }
 
 // NewIDSet creates a new IDSet from a slice of IDs.
 func NewIDSet(ids []string) *IDSet {
 	return &IDSet{
+		ids:      ids,
 		prefixes: ComputeUniquePrefixes(ids),
 	}
 }
 
+// Matching returns the IDs that start with p, in the order they were given.
+func (s *IDSet) Matching(p string) []string {
+	var matches []string
+	for _, id := range s.ids {
+		if id != "" && strings.HasPrefix(id, p) {
+			matches = append(matches, id)
+		}
+	}
+	return matches
+}
+
+// Resolve returns the ID that p identifies: one starting with p, typed to
+// at least its unique prefix length.
+func (s *IDSet) Resolve(p string) (string, bool) {
+	for _, id := range s.ids {
+		if id != "" && strings.HasPrefix(id, p) && len(p) >= s.PrefixLen(id) {
+			return id, true
+		}
+	}
+	return "", false
+}
+
 // PrefixLen returns the unique prefix length for the given ID.
 // Returns MinPrefixLen if the ID is not in the set.
 func (s *IDSet) PrefixLen(id string) int {
@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/app"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/messages"
+)
+
+// pushRemote is the remote whose URL decides the forge for pull requests
+const pushRemote = "origin"
+
+// offerPullRequests asks to open pull requests for bookmarks just pushed,
+// listed from the bottom of the stack, when origin is on a forge that has
+// them. A failed push shows its error instead.
+func (a *App) offerPullRequests(names []string) {
+	if a.showInfo || len(names) == 0 {
+		return
+	}
+	remoteURL, err := jj.GitRemoteURL(a.ctx, a.repoPath, pushRemote)
+	if err != nil {
+		return
+	}
+	if urls, ok := app.ForgeURLs(remoteURL, names[0]); !ok || urls.PullRequest == "" {
+		return
+	}
+
+	a.pullRequestBookmarks = names
+	message := fmt.Sprintf("Open a pull request for %s?", names[0])
+	if len(names) > 1 {
+		message = fmt.Sprintf("Open pull requests for %s? Each one after the first targets the bookmark below it.", strings.Join(names, ", "))
+	}
+	a.showConfirmDialog("Open Pull Requests", message, "open_pull_requests")
+	a.confirmOverlay.SetLabels("Open", "Not now")
+}
+
+// openPullRequests opens the offered pull requests in the background,
+// stacked so each targets the bookmark below it
+func (a *App) openPullRequests() tea.Cmd {
+	ctx, repoPath, names := a.ctx, a.repoPath, a.pullRequestBookmarks
+	a.pullRequestBookmarks = nil
+	return func() tea.Msg {
+		remoteURL, err := jj.GitRemoteURL(ctx, repoPath, pushRemote)
+		if err != nil {
+			return messages.PullRequestsMsg{Err: err}
+		}
+		var prs []app.PullRequest
+		for i, name := range names {
+			var base string
+			if i > 0 {
+				base = names[i-1]
+			}
+			pr, ok, err := app.OpenPullRequest(ctx, repoPath, remoteURL, name, base)
+			if err != nil {
+				return messages.PullRequestsMsg{PullRequests: prs, Err: err}
+			}
+			if ok {
+				prs = append(prs, pr)
+			}
+		}
+		return messages.PullRequestsMsg{PullRequests: prs}
+	}
+}
+
+// showPullRequests records the opened pull requests for the bookmarks
+// panel and shows their links
+func (a *App) showPullRequests(msg messages.PullRequestsMsg) {
+	root := a.sessionKey()
+	var lines []string
+	for _, pr := range msg.PullRequests {
+		if pr.Opened {
+			a.layout.SetPullRequest(root, pr.Bookmark, pr.URL)
+			lines = append(lines, pr.Bookmark+": "+pr.URL)
+		} else {
+			lines = append(lines, pr.Bookmark+", open at: "+pr.URL)
+		}
+	}
+	if len(msg.PullRequests) > 0 {
+		_ = a.layout.Save() // The links still show for this session if saving fails
+		a.bookmarksPanel.SetPullRequests(a.layout.PullRequests[root])
+	}
+
+	if msg.Err != nil {
+		a.showErrorDialog(msg.Err)
+		return
+	}
+	if len(lines) == 0 {
+		return
+	}
+	a.showInfoDialog("Pull Requests", strings.Join(lines, "\n"))
+}
@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/app"
+	"github.com/gerunddev/jjazy/config"
+	"github.com/gerunddev/jjazy/ui/messages"
+)
+
+// TestPullRequestsRecorded verifies opened pull requests are remembered
+// for the repository and their links shown
+func TestPullRequestsRecorded(t *testing.T) {
+	t.Setenv("JJAZY_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	a.Update(messages.PullRequestsMsg{PullRequests: []app.PullRequest{
+		{Bookmark: "auth-1", URL: "https://github.com/o/r/pull/12", Opened: true},
+		{Bookmark: "auth-2", URL: "https://github.com/o/r/compare/auth-2?expand=1"},
+	}})
+	recorded := a.layout.PullRequests[a.sessionKey()]
+	if len(recorded) != 1 || recorded["auth-1"] != "https://github.com/o/r/pull/12" {
+		t.Errorf("Expected only the opened pull request recorded, got %v", recorded)
+	}
+	if !a.showInfo {
+		t.Error("Expected the links to be shown")
+	}
+
+	loaded, err := config.LoadState()
+	if err != nil || loaded.PullRequests[a.sessionKey()]["auth-1"] == "" {
+		t.Errorf("Expected the pull request saved to the state file, got %v (%v)", loaded.PullRequests, err)
+	}
+}
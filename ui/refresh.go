@@ -0,0 +1,57 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/ui/messages"
+)
+
+// refreshLogScope covers the Log experience panels a mutation can change
+const refreshLogScope = messages.RefreshLog | messages.RefreshBookmarks | messages.RefreshWorkspaces
+
+// requestRefresh asks for the panels in scope to reload once the current
+// message is handled. Requests add up and go out as one RefreshRequestMsg.
+func (a *App) requestRefresh(scope messages.RefreshScope) {
+	a.pendingRefresh |= scope
+}
+
+// refreshLogPanels reloads the Log experience panels after a mutation
+func (a *App) refreshLogPanels() {
+	a.requestRefresh(refreshLogScope)
+}
+
+// flushRefresh adds the pending refresh request, if any, to cmd
+func (a *App) flushRefresh(cmd tea.Cmd) tea.Cmd {
+	if a.pendingRefresh == 0 {
+		return cmd
+	}
+	scope := a.pendingRefresh
+	a.pendingRefresh = 0
+	return tea.Batch(cmd, func() tea.Msg {
+		return messages.RefreshRequestMsg{Scope: scope}
+	})
+}
+
+// applyRefresh reloads the built-in panels in scope. Files and operations
+// only reload while their experience is open; the others load on entry.
+// The repo is first brought up to jj's current operation, since the jj
+// commands behind most mutations leave the bridge's handle behind.
+func (a *App) applyRefresh(scope messages.RefreshScope) {
+	_ = a.repo.Reload() // A repo that can't reload fails the panels' own reads
+	if scope.Has(messages.RefreshLog) {
+		a.logPanel.Refresh()
+	}
+	if scope.Has(messages.RefreshWorkspaces) {
+		a.workspacePanel.Refresh()
+	}
+	if scope.Has(messages.RefreshBookmarks) {
+		a.bookmarksPanel.Refresh()
+	}
+	if scope.Has(messages.RefreshFiles) && a.currentExperience == ExperienceChange {
+		a.filesPanel.LoadForChange(a.selectedChangeID)
+		a.showSelectedFile()
+	}
+	if scope.Has(messages.RefreshOperations) && a.currentExperience == ExperienceOperations {
+		a.timelinePanel.Refresh()
+		a.operationsPanel.Refresh()
+	}
+}
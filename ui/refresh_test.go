@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/config"
+	"github.com/gerunddev/jjazy/ui/messages"
+	"github.com/gerunddev/jjazy/ui/panels"
+)
+
+// refreshPanel is a registered panel that records the refreshes it's asked for
+type refreshPanel struct {
+	panels.BasePanel
+	scopes []messages.RefreshScope
+}
+
+func (r *refreshPanel) Init() tea.Cmd { return nil }
+
+func (r *refreshPanel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if refresh, ok := msg.(messages.RefreshRequestMsg); ok {
+		r.scopes = append(r.scopes, refresh.Scope)
+	}
+	return r, nil
+}
+
+func (r *refreshPanel) View() string { return r.RenderFrame("") }
+
+// TestRefreshRequestsMerge verifies requests made while handling one
+// message go out as a single RefreshRequestMsg covering all of them
+func TestRefreshRequestsMerge(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.refreshLogPanels()
+	a.requestRefresh(messages.RefreshFiles)
+
+	cmd := a.flushRefresh(nil)
+	if cmd == nil {
+		t.Fatal("Expected a refresh command")
+	}
+	msg, ok := cmd().(messages.RefreshRequestMsg)
+	if !ok {
+		t.Fatalf("Expected a RefreshRequestMsg, got %T", cmd())
+	}
+	want := messages.RefreshLog | messages.RefreshBookmarks | messages.RefreshWorkspaces | messages.RefreshFiles
+	if msg.Scope != want {
+		t.Errorf("Scope = %b, want %b", msg.Scope, want)
+	}
+	if msg.Scope.Has(messages.RefreshOperations) {
+		t.Error("Expected operations left out of the scope")
+	}
+
+	if a.flushRefresh(nil) != nil {
+		t.Error("Expected no refresh once the request was sent")
+	}
+}
+
+// TestRefreshReachesPlugins verifies registered panels get refresh requests
+func TestRefreshReachesPlugins(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	p := &refreshPanel{BasePanel: panels.NewBasePanel("CI", "")}
+	a.RegisterPanel(p, PanelSlot{Experience: ExperienceLog, Rows: 6})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	a.Update(messages.RefreshRequestMsg{Scope: messages.RefreshBookmarks})
+	if len(p.scopes) != 1 || p.scopes[0] != messages.RefreshBookmarks {
+		t.Errorf("Expected the panel to get one bookmarks refresh, got %v", p.scopes)
+	}
+}
@@ -0,0 +1,96 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/ui/floating"
+	"github.com/gerunddev/jjazy/ui/panels"
+)
+
+// panelSpec describes a built-in panel: where the App keeps it, the keys it
+// handles and the context menu for its selection
+type panelSpec struct {
+	panel   func(*App) panels.Panel
+	actions func(*App, tea.KeyMsg) (tea.Cmd, bool)               // Nil when the panel only navigates
+	menu    func(*App) (title string, items []floating.MenuItem) // Nil when the panel has no menu
+}
+
+// builtinPanels lists each experience's built-in panels in focus order: the
+// main panel, then the sidebar top to bottom
+var builtinPanels map[Experience][]panelSpec
+
+// The table refers to handlers that look panels up in it, so it's filled
+// in at init rather than in its declaration
+func init() {
+	builtinPanels = map[Experience][]panelSpec{
+		ExperienceLog: {
+			{func(a *App) panels.Panel { return a.logPanel }, (*App).logActions, (*App).changeMenu},
+			{func(a *App) panels.Panel { return a.workspacePanel }, (*App).workspaceActions, (*App).workspaceMenu},
+			{func(a *App) panels.Panel { return a.bookmarksPanel }, (*App).bookmarkActions, (*App).bookmarkMenu},
+		},
+		ExperienceChange: {
+			{func(a *App) panels.Panel { return a.diffPanel }, (*App).diffActions, nil},
+			{(*App).filesSidebar, (*App).filesActions, (*App).fileMenu},
+		},
+		ExperienceOperations: {
+			{func(a *App) panels.Panel { return a.timelinePanel }, (*App).timelineActions, nil},
+			{func(a *App) panels.Panel { return a.operationsPanel }, (*App).operationsActions, nil},
+		},
+	}
+}
+
+// panelEntry is one panel of an experience, with the handler for the keys
+// it acts on beyond navigation
+type panelEntry struct {
+	panel   panels.Panel
+	actions panels.ActionHandler                 // Nil when the panel only navigates
+	menu    func() (string, []floating.MenuItem) // Nil when the panel has no menu
+}
+
+// panelsFor returns an experience's panels in focus order: the built-in
+// panels, then registered panels. A panel's index is the one focusedPanel
+// and PanelBound use.
+func (a *App) panelsFor(exp Experience) []panelEntry {
+	var entries []panelEntry
+	for _, spec := range builtinPanels[exp] {
+		entry := panelEntry{panel: spec.panel(a)}
+		if spec.actions != nil {
+			actions := spec.actions
+			entry.actions = panels.ActionHandlerFunc(func(msg tea.KeyMsg) (tea.Cmd, bool) {
+				return actions(a, msg)
+			})
+		}
+		if spec.menu != nil {
+			menu := spec.menu
+			entry.menu = func() (string, []floating.MenuItem) { return menu(a) }
+		}
+		entries = append(entries, entry)
+	}
+	for _, p := range a.pluginsFor(exp) {
+		actions, _ := p.panel.(panels.ActionHandler)
+		entries = append(entries, panelEntry{panel: p.panel, actions: actions})
+	}
+	return entries
+}
+
+// panelAt returns the current experience's panel at index, or nil
+func (a *App) panelAt(index int) *panelEntry {
+	entries := a.panelsFor(a.currentExperience)
+	if index < 0 || index >= len(entries) {
+		return nil
+	}
+	return &entries[index]
+}
+
+// focused reports whether p is the focused panel of the current experience
+func (a *App) focused(p panels.Panel) bool {
+	entry := a.panelAt(a.focusedPanel)
+	return entry != nil && entry.panel == p
+}
+
+// panelActions offers a key to the focused panel's action handler
+func (a *App) panelActions(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if entry := a.panelAt(a.focusedPanel); entry != nil && entry.actions != nil {
+		return entry.actions.HandleAction(msg)
+	}
+	return nil, false
+}
@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/config"
+)
+
+// TestBuiltinPanelKeys verifies the sidebar's navigation keys reach the
+// focused panel's handler: Esc leaves cursor mode and → returns to the log
+func TestBuiltinPanelKeys(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	a.setFocus(2) // Bookmarks
+	a.bookmarksPanel.SetEntered(true)
+
+	a.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if a.bookmarksPanel.IsEntered() || a.focusedPanel != 2 {
+		t.Errorf("Esc: entered=%v focus=%d, want false 2", a.bookmarksPanel.IsEntered(), a.focusedPanel)
+	}
+
+	a.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if a.focusedPanel != 0 || !a.focused(a.logPanel) {
+		t.Errorf("→: focus=%d, want the log panel", a.focusedPanel)
+	}
+}
+
+// TestBuiltinPanelCount verifies the focus order comes from the registry
+func TestBuiltinPanelCount(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	for exp, want := range map[Experience]int{ExperienceLog: 3, ExperienceChange: 2, ExperienceOperations: 2} {
+		if got := len(a.panelsFor(exp)); got != want || builtinPanelCount(exp) != want {
+			t.Errorf("experience %v: %d panels, want %d", exp, got, want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"context"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/floating"
+)
+
+// customLogFilter is the log filter list entry that asks for a revset
+const customLogFilter = "revset…"
+
+// isRevsetInput reports whether a text input action takes a revset, which
+// is validated as it's typed and completes on tab
+func isRevsetInput(action string) bool {
+	switch action {
+	case "rebase_revset", "rebase_destination", "filter_revset":
+		return true
+	}
+	return false
+}
+
+// openRevsetInput shows a text input for a revset, loading the alias and
+// bookmark names it completes and checking the initial value
+func (a *App) openRevsetInput(title, placeholder, value, action string) tea.Cmd {
+	a.textInputOverlay = floating.NewTextInputOverlay(title, placeholder, value)
+	a.textInputOverlay.SetSize(a.width, a.height-1)
+	a.showTextInput = true
+	a.textInputAction = action
+
+	aliases, _ := jj.RevsetAliases(a.ctx, a.repoPath)
+	var bookmarks []string
+	if branches, err := a.repo.Branches(); err == nil {
+		for _, branch := range branches {
+			if !slices.Contains(bookmarks, branch.Name) {
+				bookmarks = append(bookmarks, branch.Name)
+			}
+		}
+	}
+	a.revsetCompletions = revsetCompletions(aliases, bookmarks)
+	a.textInputOverlay.AddHint("tab complete")
+
+	if value == "" {
+		return nil
+	}
+	return a.evalRevset(value)
+}
+
+// rebaseOntoRevset rebases the revset being rebased onto a typed
+// destination, instead of one picked in the log
+func (a *App) rebaseOntoRevset(dest string) tea.Cmd {
+	source := a.rebaseSource
+	a.exitRebaseMode()
+
+	return a.runMutation(func(ctx context.Context) error {
+		return jj.Rebase(ctx, a.repoPath, source, dest)
+	}, a.refreshLogPanels)
+}
@@ -0,0 +1,33 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/config"
+	"github.com/gerunddev/jjazy/ui/messages"
+)
+
+// TestRevsetEvalWaitsForTypingToPause verifies only the last keystroke's
+// scheduled evaluation runs jj
+func TestRevsetEvalWaitsForTypingToPause(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	a.openRevsetInput("Rebase Revset", "Enter revset to rebase...", "", "rebase_revset")
+
+	for _, r := range "@-" {
+		if _, cmd := a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}); cmd == nil {
+			t.Fatalf("Expected typing %q to schedule an evaluation", r)
+		}
+	}
+	if a.cancelEval != nil {
+		t.Fatal("Expected nothing evaluated while typing")
+	}
+
+	if _, cmd := a.Update(messages.RevsetEvalDueMsg{Seq: a.evalSeq - 1, Revset: "@"}); cmd != nil || a.cancelEval != nil {
+		t.Error("Expected the evaluation scheduled by an earlier keystroke to be dropped")
+	}
+	if _, cmd := a.Update(messages.RevsetEvalDueMsg{Seq: a.evalSeq, Revset: "@-"}); cmd == nil || a.cancelEval == nil {
+		t.Error("Expected the last keystroke's evaluation to run")
+	}
+}
@@ -0,0 +1,104 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/app"
+	"github.com/gerunddev/jjazy/config"
+)
+
+// Session experience names, as stored in config.Session
+const (
+	sessionChange     = "change"
+	sessionOperations = "operations"
+)
+
+// sessionKey identifies the repository in the state file: the current
+// workspace's root, so starting from a subdirectory finds the same session
+func (a *App) sessionKey() string {
+	return app.WorkspaceRoot(a.repo, a.repoPath)
+}
+
+// saveSession remembers the experience, selection, focus and scroll
+// positions for this repository. Pickers are one-off, so they don't.
+func (a *App) saveSession() {
+	if a.pickMode != PickNone || !a.ready {
+		return
+	}
+
+	session := &config.Session{
+		FocusedPanel: a.focusedPanel,
+		LogOffset:    a.logPanel.ScrollOffset(),
+	}
+	if change := a.logPanel.SelectedChange(); change != nil {
+		session.ChangeID = change.ChangeID
+	}
+	switch a.currentExperience {
+	case ExperienceChange:
+		session.Experience = sessionChange
+		session.ChangeID = a.selectedChangeID
+		session.DiffOffset = a.diffPanel.ScrollOffset()
+	case ExperienceOperations:
+		session.Experience = sessionOperations
+	}
+
+	if a.layout.Sessions == nil {
+		a.layout.Sessions = make(map[string]*config.Session)
+	}
+	a.layout.Sessions[a.sessionKey()] = session
+	_ = a.layout.Save() // Nothing to tell the user on the way out
+}
+
+// restoreSession returns to where this repository was left. Anything that
+// no longer applies, like an abandoned change, falls back to the log. A
+// configured default_experience wins over the remembered one.
+func (a *App) restoreSession() tea.Cmd {
+	if a.pickMode != PickNone {
+		return nil
+	}
+	session := a.layout.Sessions[a.sessionKey()]
+	if session == nil {
+		return a.openDefaultExperience()
+	}
+
+	selected := session.ChangeID != "" && a.logPanel.SelectByChangeID(session.ChangeID)
+	a.logPanel.SetScrollOffset(session.LogOffset)
+	if a.cfg.DefaultExperience != "" {
+		return a.openDefaultExperience()
+	}
+
+	var cmd tea.Cmd
+	switch session.Experience {
+	case sessionChange:
+		change := a.logPanel.SelectedChange()
+		if !selected || change == nil {
+			return nil
+		}
+		a.enterChangeExperience(change.ChangeID, change.IsWorkingCopy)
+		a.diffPanel.SetScrollOffset(session.DiffOffset)
+	case sessionOperations:
+		cmd = a.enterOperationsExperience()
+	}
+
+	if session.FocusedPanel > 0 && session.FocusedPanel < a.maxPanelsForExperience() {
+		a.setFocus(session.FocusedPanel)
+	}
+	return cmd
+}
+
+// openDefaultExperience opens the experience named by default_experience:
+// "change" opens the working copy, "operations" the operation log. Anything
+// else stays in the log.
+func (a *App) openDefaultExperience() tea.Cmd {
+	switch a.cfg.DefaultExperience {
+	case sessionChange:
+		for _, change := range a.logPanel.GetChanges() {
+			if change.IsWorkingCopy {
+				a.enterChangeExperience(change.ChangeID, true)
+				return nil
+			}
+		}
+	case sessionOperations:
+		return a.enterOperationsExperience()
+	}
+	return nil
+}
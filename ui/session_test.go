@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/config"
+)
+
+// TestSessionRestore verifies quitting remembers the experience and focus
+// and the next start in the same repository returns to them
+func TestSessionRestore(t *testing.T) {
+	t.Setenv("JJAZY_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+
+	layout := &config.State{}
+	a := NewApp(nil, "/nonexistent/path", config.Default(), layout)
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	a.enterOperationsExperience()
+	a.setFocus(1)
+	a.quit()
+
+	session := layout.Sessions["/nonexistent/path"]
+	if session == nil || session.Experience != sessionOperations || session.FocusedPanel != 1 {
+		t.Fatalf("Expected the operations experience to be saved, got %+v", session)
+	}
+
+	loaded, err := config.LoadState()
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	b := NewApp(nil, "/nonexistent/path", config.Default(), loaded)
+	b.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	if b.currentExperience != ExperienceOperations || b.focusedPanel != 1 {
+		t.Errorf("Expected to restore the operations experience with panel 1 focused, got %v/%d",
+			b.currentExperience, b.focusedPanel)
+	}
+
+	// Another repository starts fresh
+	c := NewApp(nil, "/other/path", config.Default(), loaded)
+	c.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	if c.currentExperience != ExperienceLog || c.focusedPanel != 0 {
+		t.Errorf("Expected a fresh start in another repository, got %v/%d", c.currentExperience, c.focusedPanel)
+	}
+}
+
+// TestSessionSkipsPickers verifies picker runs don't overwrite the session
+func TestSessionSkipsPickers(t *testing.T) {
+	t.Setenv("JJAZY_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+
+	layout := &config.State{}
+	a := NewApp(nil, "/nonexistent/path", config.Default(), layout)
+	a.SetPickMode()
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	a.quit()
+	if len(layout.Sessions) != 0 {
+		t.Errorf("Expected no session saved by a picker, got %+v", layout.Sessions)
+	}
+}
+
+// TestDefaultExperienceConfig verifies default_experience picks the first
+// view even when a session remembers another
+func TestDefaultExperienceConfig(t *testing.T) {
+	t.Setenv("JJAZY_CONFIG", filepath.Join(t.TempDir(), "config.json"))
+
+	cfg := config.Default()
+	cfg.DefaultExperience = "operations"
+	a := NewApp(nil, "/nonexistent/path", cfg, &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	if a.currentExperience != ExperienceOperations {
+		t.Errorf("Expected to open in the operations experience, got %v", a.currentExperience)
+	}
+
+	layout := &config.State{Sessions: map[string]*config.Session{
+		"/nonexistent/path": {Experience: sessionOperations},
+	}}
+	cfg.DefaultExperience = "log"
+	b := NewApp(nil, "/nonexistent/path", cfg, layout)
+	b.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	if b.currentExperience != ExperienceLog {
+		t.Errorf("Expected default_experience to override the session, got %v", b.currentExperience)
+	}
+}
@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/app"
+	"github.com/gerunddev/jjazy/jj"
+)
+
+// notSigning toasts why K did nothing when jj has no signing backend
+func (a *App) notSigning() tea.Cmd {
+	return a.showToast("signing is not configured (signing.backend)")
+}
+
+// signChange signs the change, replacing any existing signature
+func (a *App) signChange(target app.Target) tea.Cmd {
+	if a.signingBackend == "" {
+		return a.notSigning()
+	}
+	return a.runOnTarget(target, func(ctx context.Context, changeID string) error {
+		return jj.Sign(ctx, a.repoPath, changeID)
+	}, a.refreshLogPanels)
+}
+
+// signRange signs every change in the visual range
+func (a *App) signRange(revset string) tea.Cmd {
+	if a.signingBackend == "" {
+		return a.notSigning()
+	}
+	return a.runMutation(func(ctx context.Context) error {
+		return jj.Sign(ctx, a.repoPath, revset)
+	}, func() {
+		a.logPanel.SetVisualMode(false)
+		a.refreshLogPanels()
+	})
+}
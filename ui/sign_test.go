@@ -0,0 +1,32 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/config"
+	"github.com/gerunddev/jjazy/jj"
+)
+
+// TestSignNeedsBackend verifies K explains itself when signing isn't set up
+// and signs the selected change when it is
+func TestSignNeedsBackend(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	a.logPanel.SetOutput(&jj.LogOutput{
+		LineToChange: []string{"work2222"},
+		Changes:      []jj.ChangeInfo{{ChangeID: "work2222", CommitID: "abcd1234", StartLine: 0, EndLine: 1}},
+	}, nil)
+
+	sign := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'K'}}
+	a.Update(sign)
+	if a.busy || a.toast != "signing is not configured (signing.backend)" {
+		t.Errorf("Expected a toast and no mutation, got busy=%v toast=%q", a.busy, a.toast)
+	}
+
+	a.signingBackend = "ssh"
+	a.Update(sign)
+	if !a.busy {
+		t.Errorf("Expected K to start signing the change")
+	}
+}
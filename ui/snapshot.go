@@ -0,0 +1,22 @@
+package ui
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/messages"
+)
+
+// snapshotWorkingCopy records files edited outside jj into the working
+// copy, then reloads whatever is on screen so the edits show up
+func (a *App) snapshotWorkingCopy() tea.Cmd {
+	return a.runMutation(func(ctx context.Context) error {
+		return jj.Snapshot(ctx, a.repoPath)
+	}, a.refreshAfterSnapshot)
+}
+
+// refreshAfterSnapshot reloads every panel, since edits can show up anywhere
+func (a *App) refreshAfterSnapshot() {
+	a.requestRefresh(messages.RefreshAll)
+}
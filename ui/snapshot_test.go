@@ -0,0 +1,26 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/config"
+)
+
+// TestSnapshotKey verifies ctrl+s runs a snapshot, except at a past operation
+func TestSnapshotKey(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	a.setAtOperation("abc123")
+	a.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if a.busy {
+		t.Fatal("Expected ctrl+s to be ignored at a past operation")
+	}
+
+	a.setAtOperation("")
+	a.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if !a.busy {
+		t.Error("Expected ctrl+s to start a snapshot")
+	}
+}
@@ -0,0 +1,134 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/floating"
+)
+
+// openStack shows the changes between trunk and @ with their push state
+func (a *App) openStack() {
+	entries, err := jj.Stack(a.ctx, a.repoPath, a.trunkRevset())
+	if err != nil {
+		a.showErrorDialog(err)
+		return
+	}
+	if len(entries) == 0 {
+		a.showInfoDialog("Stack", fmt.Sprintf("@ is on %s, so there is no stack above it.", a.trunkName()))
+		return
+	}
+	a.stackEntries = entries
+	a.stackOverlay = floating.NewStackOverlay(entries)
+	a.stackOverlay.SetSize(a.width, a.height-1)
+	a.showStack = true
+}
+
+// handleStackKey handles a key in the stack view. Enter selects the entry
+// in the log; p, r and n act on the whole stack.
+func (a *App) handleStackKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "escape", "ctrl+g", "ctrl+c", "q":
+		a.closeStack()
+		return nil
+	case "enter":
+		if entry := a.stackOverlay.Selected(); entry != nil {
+			a.logPanel.SelectByChangeID(entry.ChangeID)
+		}
+		a.closeStack()
+		return nil
+	case "p":
+		names := stackBookmarks(a.stackEntries)
+		if len(names) == 0 {
+			return a.showToast("no bookmarks in the stack")
+		}
+		a.closeStack()
+		a.showConfirmDialog("Push Stack",
+			fmt.Sprintf("Push %d bookmarks to the remote? %s", len(names), strings.Join(names, ", ")),
+			"push_stack")
+		return nil
+	case "r":
+		trunk := a.trunkRevset()
+		a.closeStack()
+		a.cooldown.Arm(msg.String(), time.Now())
+		return a.runMutation(func(ctx context.Context) error {
+			return jj.RebaseBranch(ctx, a.repoPath, "@", trunk)
+		}, a.refreshLogPanels)
+	case "n":
+		names := stackBookmarks(a.stackEntries)
+		if len(names) == 0 {
+			return a.showToast("no bookmarks in the stack")
+		}
+		a.closeStack()
+		a.textInputOverlay = floating.NewTextInputOverlay(
+			"Renumber Bookmarks",
+			"Enter the name to number, e.g. auth-...",
+			stackBase(names[0]),
+		)
+		a.textInputOverlay.SetSize(a.width, a.height-1)
+		a.showTextInput = true
+		a.textInputAction = "renumber_stack"
+		return nil
+	default:
+		_, cmd := a.stackOverlay.Update(msg)
+		return cmd
+	}
+}
+
+// pushStack pushes every bookmark in the stack, creating new ones on the
+// remote, then offers pull requests for them
+func (a *App) pushStack() tea.Cmd {
+	names := stackBookmarks(a.stackEntries)
+	return a.runMutation(func(ctx context.Context) error {
+		return jj.GitPushBookmarks(ctx, a.repoPath, names, true)
+	}, func() {
+		a.refreshLogPanels()
+		a.offerPullRequests(names)
+	})
+}
+
+// renumberStack renames the stack's bookmarks to base1, base2, … from the
+// bottom up
+func (a *App) renumberStack(base string) tea.Cmd {
+	renames := jj.StackRenames(a.stackEntries, base)
+	if base == "" || len(renames) == 0 {
+		return nil
+	}
+	return a.runMutation(func(ctx context.Context) error {
+		return jj.RenameBookmarks(ctx, a.repoPath, renames)
+	}, a.refreshLogPanels)
+}
+
+// stackBookmarks returns the bookmarks in the stack from the bottom up
+func stackBookmarks(entries []jj.StackEntry) []string {
+	var names []string
+	for _, entry := range slices.Backward(entries) {
+		names = append(names, entry.Bookmarks...)
+	}
+	return names
+}
+
+// stackBase guesses the name a stack's bookmarks are numbered after from
+// its lowest bookmark: "auth-3" gives "auth-", "auth" gives "auth-"
+func stackBase(name string) string {
+	base := strings.TrimRight(name, "0123456789")
+	if base == name {
+		return name + "-"
+	}
+	return base
+}
+
+// closeStack hides the stack view
+func (a *App) closeStack() {
+	a.showStack = false
+	a.stackOverlay = nil
+}
+
+func (a *App) overlayStack(background string) string {
+	return a.composite(background, a.stackOverlay.View())
+}
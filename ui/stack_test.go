@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/config"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/floating"
+)
+
+// TestStackBase verifies the numbered name is guessed from a bookmark
+func TestStackBase(t *testing.T) {
+	for name, want := range map[string]string{"auth-3": "auth-", "auth": "auth-", "part12": "part"} {
+		if got := stackBase(name); got != want {
+			t.Errorf("stackBase(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+// TestStackPushAsks verifies p in the stack view asks before pushing
+// every bookmark, listed from the bottom of the stack
+func TestStackPushAsks(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	a.stackEntries = []jj.StackEntry{
+		{ChangeID: "top", Bookmarks: []string{"auth-2"}},
+		{ChangeID: "wip"},
+		{ChangeID: "low", Bookmarks: []string{"auth-1"}},
+	}
+	a.stackOverlay = floating.NewStackOverlay(a.stackEntries)
+	a.stackOverlay.SetSize(a.width, a.height-1)
+	a.showStack = true
+
+	if got := stackBookmarks(a.stackEntries); !slices.Equal(got, []string{"auth-1", "auth-2"}) {
+		t.Errorf("stackBookmarks() = %v, want [auth-1 auth-2]", got)
+	}
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	if a.showStack || !a.showConfirm || a.confirmAction != "push_stack" {
+		t.Errorf("Expected p to ask to push the stack, got stack %v confirm %v action %q", a.showStack, a.showConfirm, a.confirmAction)
+	}
+}
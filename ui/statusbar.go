@@ -0,0 +1,169 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gerunddev/jjazy/config"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/messages"
+	"github.com/gerunddev/jjazy/ui/theme"
+)
+
+// statusBarHeight is the rows the status bar takes above the help bar
+const statusBarHeight = 1
+
+// StatusBarContext captures what the status bar shows
+type StatusBarContext struct {
+	Workspace string            // Current workspace name
+	Summary   *jj.RepoSummary   // Nil until the first refresh finishes
+	Trunk     string            // Trunk bookmark the ahead/behind counts are against, "" for trunk()
+	Git       *jj.GitColocation // Nil until the first refresh finishes
+	Busy      bool              // A mutation or refresh is running
+	Spinner   string            // Current spinner frame, shown while busy
+	Update    string            // Newer release found at startup, if any
+	SlowCall  string            // Recent bridge call that took too long, e.g. "get_log took 1.20s"
+	Advancing bool              // Bookmarks on @- move forward with new changes
+}
+
+// RenderStatusBar renders the repo summary on the left, and any update
+// notice and the background activity indicator on the right
+func RenderStatusBar(ctx StatusBarContext, width int) string {
+	var parts []string
+	if ctx.Workspace != "" {
+		parts = append(parts, theme.DimmedStyle.Render("workspace ")+theme.NormalItemStyle.Render(ctx.Workspace))
+	}
+	if s := ctx.Summary; s != nil {
+		if len(s.Bookmarks) > 0 {
+			bookmarks := theme.CurrentBookmarkStyle.Render(strings.Join(s.Bookmarks, " "))
+			if ctx.Advancing {
+				bookmarks += theme.DimmedStyle.Render(" (auto-advance)")
+			}
+			parts = append(parts, bookmarks)
+		}
+		if s.Ahead > 0 || s.Behind > 0 {
+			trunk := ctx.Trunk
+			if trunk == "" {
+				trunk = "trunk()"
+			}
+			parts = append(parts, theme.DimmedStyle.Render(trunk+" ")+theme.NormalItemStyle.Render(fmt.Sprintf("↑%d ↓%d", s.Ahead, s.Behind)))
+		}
+		if s.Conflicts > 0 {
+			parts = append(parts, theme.ConflictStyle.Render(plural(s.Conflicts, "conflict")))
+		}
+		if len(s.Divergent) > 0 {
+			warning := "⚠ " + plural(len(s.Divergent), "divergent change") + ": " + strings.Join(s.Divergent, " ")
+			parts = append(parts, lipgloss.NewStyle().Foreground(theme.ColorYellow).Render(warning)+
+				theme.DimmedStyle.Render(" (! to resolve)"))
+		}
+	}
+	if g := ctx.Git; g != nil && g.Colocated {
+		head := g.Head
+		if head == "" {
+			head = "(unborn)"
+		}
+		parts = append(parts, theme.DimmedStyle.Render("git HEAD ")+theme.NormalItemStyle.Render(head))
+		if warning := gitWarning(g); warning != "" {
+			parts = append(parts, lipgloss.NewStyle().Foreground(theme.ColorYellow).Render("⚠ "+warning)+
+				theme.DimmedStyle.Render(" (G to sync)"))
+		}
+	}
+	left := " " + strings.Join(parts, theme.DimmedStyle.Render("  │  "))
+
+	right := ""
+	if ctx.SlowCall != "" {
+		right += lipgloss.NewStyle().Foreground(theme.ColorYellow).Render("⚠ "+ctx.SlowCall) + theme.DimmedStyle.Render(" (ctrl+alt+d metrics) ")
+	}
+	if ctx.Update != "" {
+		right += lipgloss.NewStyle().Foreground(theme.ColorYellow).Render("jjazy "+ctx.Update+" available") + theme.DimmedStyle.Render(" (W what's new) ")
+	}
+	if ctx.Busy {
+		right += ctx.Spinner + " "
+	}
+
+	gap := max(width-lipgloss.Width(left)-lipgloss.Width(right), 1)
+	return lipgloss.NewStyle().MaxWidth(width).Render(left + strings.Repeat(" ", gap) + right)
+}
+
+// gitWarning describes how git disagrees with the working copy, or ""
+func gitWarning(g *jj.GitColocation) string {
+	switch {
+	case g.HeadMoved():
+		return "git HEAD is not @-"
+	case g.Diverged():
+		return "changes staged in git's index"
+	}
+	return ""
+}
+
+// plural formats a count with its noun, adding an s unless it is one
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+func (a *App) renderStatusBar() string {
+	return RenderStatusBar(StatusBarContext{
+		Workspace: a.workspaceName,
+		Summary:   a.summary,
+		Trunk:     a.trunkBookmark,
+		Git:       a.git,
+		Busy:      a.backgroundActive(),
+		Spinner:   a.spinner.View(),
+		Update:    a.updateVersion(),
+		SlowCall:  a.slowCall,
+		Advancing: a.advanceBookmarks,
+	}, a.width)
+}
+
+// backgroundActive reports whether any jj work is running off the UI loop
+func (a *App) backgroundActive() bool {
+	return a.busy || a.refreshingSummary
+}
+
+// refreshSummary reloads the status bar summary in the background
+func (a *App) refreshSummary() tea.Cmd {
+	a.refreshingSummary = true
+	repo, repoPath, ctx, trunk := a.repo, a.repoPath, a.ctx, a.trunkRevset()
+	load := func() tea.Msg {
+		msg := messages.RepoSummaryMsg{}
+		if workspaces, err := repo.Workspaces(); err == nil {
+			for _, ws := range workspaces {
+				if ws.IsCurrent {
+					msg.Workspace = ws.Name
+				}
+			}
+		}
+		msg.Summary, msg.Err = jj.Summary(ctx, repoPath, trunk)
+		msg.Git, _ = jj.Colocation(ctx, repoPath)
+		return msg
+	}
+	return tea.Batch(load, a.startSpinner())
+}
+
+// startSpinner starts the activity spinner unless it is already turning.
+// It stops itself on the first tick after background work finishes. In
+// test mode it never turns, so busy frames are reproducible.
+func (a *App) startSpinner() tea.Cmd {
+	if a.spinning || config.TestMode() {
+		return nil
+	}
+	a.spinning = true
+	return a.spinner.Tick
+}
+
+// updateSpinner advances the spinner, or stops it when nothing is running
+func (a *App) updateSpinner(msg spinner.TickMsg) tea.Cmd {
+	if !a.backgroundActive() {
+		a.spinning = false
+		return nil
+	}
+	var cmd tea.Cmd
+	a.spinner, cmd = a.spinner.Update(msg)
+	return cmd
+}
@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gerunddev/jjazy/jj"
+)
+
+// TestRenderStatusBar verifies the summary parts and the busy indicator
+func TestRenderStatusBar(t *testing.T) {
+	ctx := StatusBarContext{
+		Workspace: "default",
+		Summary: &jj.RepoSummary{
+			Bookmarks: []string{"main"},
+			Conflicts: 2,
+			Divergent: []string{"xyz11111"},
+		},
+	}
+
+	bar := RenderStatusBar(ctx, 120)
+	for _, want := range []string{"default", "main", "2 conflicts", "1 divergent change: xyz11111"} {
+		if !strings.Contains(bar, want) {
+			t.Errorf("Expected status bar to contain %q, got %q", want, bar)
+		}
+	}
+	if w := lipgloss.Width(bar); w != 120 {
+		t.Errorf("Expected status bar to fill the width, got %d", w)
+	}
+
+	ctx.Advancing = true
+	if bar := RenderStatusBar(ctx, 120); !strings.Contains(bar, "main (auto-advance)") {
+		t.Errorf("Expected auto-advance next to the bookmark, got %q", bar)
+	}
+
+	ctx.Busy = true
+	ctx.Spinner = "⠋"
+	if bar := RenderStatusBar(ctx, 120); !strings.Contains(bar, "⠋") {
+		t.Errorf("Expected the spinner while busy, got %q", bar)
+	}
+}
+
+// TestRenderStatusBarTrunkCounts verifies how far @ is from trunk shows
+// against the trunk bookmark, or trunk() before it is detected
+func TestRenderStatusBarTrunkCounts(t *testing.T) {
+	ctx := StatusBarContext{Summary: &jj.RepoSummary{Ahead: 3, Behind: 1}}
+	if bar := RenderStatusBar(ctx, 80); !strings.Contains(bar, "trunk() ↑3 ↓1") {
+		t.Errorf("Expected counts against trunk(), got %q", bar)
+	}
+	ctx.Trunk = "main"
+	if bar := RenderStatusBar(ctx, 80); !strings.Contains(bar, "main ↑3 ↓1") {
+		t.Errorf("Expected counts against main, got %q", bar)
+	}
+	ctx.Summary = &jj.RepoSummary{}
+	if bar := RenderStatusBar(ctx, 80); strings.Contains(bar, "↑") {
+		t.Errorf("Expected no counts when @ is on trunk, got %q", bar)
+	}
+}
+
+// TestRenderStatusBarBeforeSummary verifies nothing but the workspace shows until the first refresh
+func TestRenderStatusBarBeforeSummary(t *testing.T) {
+	bar := RenderStatusBar(StatusBarContext{Workspace: "default"}, 80)
+	if strings.Contains(bar, "conflict") || strings.Contains(bar, "divergent") {
+		t.Errorf("Expected no summary before the first refresh, got %q", bar)
+	}
+}
+
+// TestRenderStatusBarUpdate verifies the update notice names the new version
+func TestRenderStatusBarUpdate(t *testing.T) {
+	bar := RenderStatusBar(StatusBarContext{Workspace: "default", Update: "v0.4.0"}, 100)
+	if !strings.Contains(bar, "jjazy v0.4.0 available") {
+		t.Errorf("Expected the update notice, got %q", bar)
+	}
+	if w := lipgloss.Width(bar); w != 100 {
+		t.Errorf("Expected status bar to fill the width, got %d", w)
+	}
+}
+
+// TestRenderStatusBarGit verifies colocated repos show git HEAD and warn when it diverges
+func TestRenderStatusBarGit(t *testing.T) {
+	ctx := StatusBarContext{Git: &jj.GitColocation{Colocated: true, Head: "11111111", Parent: "11111111"}}
+	bar := RenderStatusBar(ctx, 120)
+	if !strings.Contains(bar, "git HEAD 11111111") || strings.Contains(bar, "G to sync") {
+		t.Errorf("Expected git HEAD without a warning, got %q", bar)
+	}
+
+	ctx.Git.Head = "22222222"
+	if bar := RenderStatusBar(ctx, 120); !strings.Contains(bar, "git HEAD is not @-") {
+		t.Errorf("Expected a moved HEAD warning, got %q", bar)
+	}
+
+	ctx.Git.Head = "11111111"
+	ctx.Git.Staged = true
+	if bar := RenderStatusBar(ctx, 120); !strings.Contains(bar, "changes staged in git's index") {
+		t.Errorf("Expected a staged changes warning, got %q", bar)
+	}
+
+	if bar := RenderStatusBar(StatusBarContext{Git: &jj.GitColocation{}}, 120); strings.Contains(bar, "git HEAD") {
+		t.Errorf("Expected nothing for a non-colocated repo, got %q", bar)
+	}
+}
+
+// TestRenderStatusBarSlowCall verifies a slow bridge call is warned about
+// along with the key for the metrics table
+func TestRenderStatusBarSlowCall(t *testing.T) {
+	bar := RenderStatusBar(StatusBarContext{Workspace: "default", SlowCall: "get_log took 1.20s"}, 120)
+	if !strings.Contains(bar, "get_log took 1.20s") || !strings.Contains(bar, "ctrl+alt+d") {
+		t.Errorf("Expected the slow call warning, got %q", bar)
+	}
+	if w := lipgloss.Width(bar); w != 120 {
+		t.Errorf("Expected status bar to fill the width, got %d", w)
+	}
+}
@@ -75,6 +75,10 @@ var (
 	DiffRemoveLine  = lipgloss.NewStyle().Foreground(ColorRed)
 	DiffContextLine = lipgloss.NewStyle().Foreground(ColorDimWhite)
 	DiffHunkHeader  = lipgloss.NewStyle().Foreground(ColorBlue).Bold(true)
+
+	// Commit message header above a change's diff
+	DiffMessageStyle        = lipgloss.NewStyle().Foreground(ColorYellow)
+	DiffMessageSubjectStyle = lipgloss.NewStyle().Foreground(ColorYellow).Bold(true)
 )
 
 // Log/revision styles
@@ -85,6 +89,15 @@ var (
 	TimestampStyle       = lipgloss.NewStyle().Foreground(ColorBlue)
 	WorkingCopyStyle     = lipgloss.NewStyle().Foreground(ColorGreen).Bold(true)
 	CurrentBookmarkStyle = lipgloss.NewStyle().Foreground(ColorMagenta).Bold(true)
+	TrunkBookmarkStyle   = lipgloss.NewStyle().Foreground(ColorBlue)
+	TagStyle             = lipgloss.NewStyle().Foreground(ColorYellow)
+
+	// Revision flag markers
+	DivergentStyle = lipgloss.NewStyle().Foreground(ColorYellow).Bold(true)
+	HiddenStyle    = lipgloss.NewStyle().Foreground(ColorDimWhite).Italic(true)
+	ImmutableStyle = lipgloss.NewStyle().Foreground(ColorDimWhite)
+	SignedStyle    = lipgloss.NewStyle().Foreground(ColorGreen)
+	UnsignedStyle  = lipgloss.NewStyle().Foreground(ColorDimWhite)
 
 	// Unique prefix highlighting styles (for jj-style ID display)
 	// Prefix: colored and bold, Rest: grey/dimmed
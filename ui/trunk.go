@@ -0,0 +1,42 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/app"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/messages"
+)
+
+// resolveTrunk detects the trunk bookmark from trunk() in the background
+// when none is configured. Until it answers, trunk-relative actions use
+// trunk() itself.
+func (a *App) resolveTrunk() tea.Cmd {
+	if a.cfg.TrunkBookmark != "" {
+		return nil
+	}
+	ctx, repoPath := a.ctx, a.repoPath
+	return func() tea.Msg {
+		return messages.TrunkResolvedMsg{Bookmark: app.ResolveTrunkBookmark(ctx, repoPath, "")}
+	}
+}
+
+// setTrunkBookmark switches the trunk decorations and counts to name
+func (a *App) setTrunkBookmark(name string) tea.Cmd {
+	a.trunkBookmark = name
+	a.bookmarksPanel.SetTrunkBookmark(name)
+	return a.refreshSummary()
+}
+
+// trunkName names the trunk in messages: the bookmark, or trunk() until
+// one is known
+func (a *App) trunkName() string {
+	if a.trunkBookmark == "" {
+		return "trunk()"
+	}
+	return a.trunkBookmark
+}
+
+// trunkRevset is the revset trunk-relative actions work against
+func (a *App) trunkRevset() string {
+	return jj.TrunkRevset(a.trunkBookmark)
+}
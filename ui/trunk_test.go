@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/gerunddev/jjazy/config"
+	"github.com/gerunddev/jjazy/ui/messages"
+)
+
+// TestTrunkResolvedInBackground verifies the trunk bookmark isn't looked
+// up while the app is built, and trunk-relative revsets follow it once
+// it arrives
+func TestTrunkResolvedInBackground(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	if a.trunkBookmark != "" || a.trunkRevset() != "trunk()" {
+		t.Fatalf("Expected trunk() until detection answers, got %q (%s)", a.trunkBookmark, a.trunkRevset())
+	}
+	if a.resolveTrunk() == nil {
+		t.Error("Expected a background lookup when no trunk is configured")
+	}
+
+	a.Update(messages.TrunkResolvedMsg{Bookmark: "develop"})
+	if a.trunkBookmark != "develop" || a.trunkRevset() != `coalesce(bookmarks(exact:"develop"), trunk())` {
+		t.Errorf("Expected develop as trunk, got %q (%s)", a.trunkBookmark, a.trunkRevset())
+	}
+}
+
+// TestConfiguredTrunkSkipsDetection verifies a configured trunk is used
+// straight away
+func TestConfiguredTrunkSkipsDetection(t *testing.T) {
+	cfg := config.Default()
+	cfg.TrunkBookmark = "release"
+	a := NewApp(nil, "/nonexistent/path", cfg, &config.State{})
+	if a.trunkBookmark != "release" {
+		t.Errorf("Expected the configured trunk, got %q", a.trunkBookmark)
+	}
+	if a.resolveTrunk() != nil {
+		t.Error("Expected no lookup when the trunk is configured")
+	}
+}
@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"context"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/fixtures"
+	"github.com/gerunddev/jjazy/ui/messages"
+)
+
+// untrackedFileSelected returns true when the files panel cursor is on a
+// file in its untracked section
+func (a *App) untrackedFileSelected() bool {
+	file := a.filesPanel.SelectedFile()
+	return file != nil && file.Status == fixtures.StatusUntracked
+}
+
+// showSelectedFile shows the diff of the selected file, or of the whole
+// change when there are no files. Untracked files have no diff, so they
+// get a note on what can be done with them instead.
+func (a *App) showSelectedFile() {
+	file := a.filesPanel.SelectedFile()
+	switch {
+	case file == nil:
+		a.diffPanel.LoadChange(a.selectedChangeID)
+	case file.Status == fixtures.StatusUntracked:
+		a.diffPanel.SetContent(file.Path + " is not tracked by jj.\n\n" +
+			"t starts tracking it; i adds it to .gitignore.")
+	default:
+		a.diffPanel.LoadFileInChange(a.selectedChangeID, file.Path)
+	}
+}
+
+// handleUntrackedFileKey tracks or ignores the selected untracked file,
+// then relists the working copy's files
+func (a *App) handleUntrackedFileKey(msg tea.KeyMsg) tea.Cmd {
+	path := a.filesPanel.SelectedFile().Path
+
+	fn := func(ctx context.Context) error {
+		return jj.TrackFile(ctx, a.repoPath, path)
+	}
+	if key.Matches(msg, a.keys.IgnoreFile) {
+		fn = func(ctx context.Context) error {
+			return jj.IgnoreFile(a.repoPath, path)
+		}
+	}
+
+	a.cooldown.Arm(msg.String(), time.Now())
+	return a.runMutation(fn, func() {
+		a.requestRefresh(messages.RefreshFiles)
+	})
+}
@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/floating"
+)
+
+// updateFromTrunk fetches and rebases the stack onto trunk, then reports
+// which changes the rebase left conflicted
+func (a *App) updateFromTrunk(msg tea.KeyMsg) tea.Cmd {
+	a.cooldown.Arm(msg.String(), time.Now())
+	trunk, name := a.trunkRevset(), a.trunkName()
+	var result *jj.UpdateResult
+	return a.runMutation(func(ctx context.Context) error {
+		var err error
+		result, err = jj.UpdateFromTrunk(ctx, a.repoPath, trunk)
+		return err
+	}, func() {
+		a.refreshLogPanels()
+		if result != nil {
+			a.updateOverlay = floating.NewUpdateOverlay(result, name)
+			a.updateOverlay.SetSize(a.width, a.height-1)
+			a.showUpdate = true
+		}
+	})
+}
+
+// handleUpdateKey handles a key in the update report. Enter selects the
+// conflicted change in the log.
+func (a *App) handleUpdateKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "escape", "ctrl+g", "ctrl+c", "q":
+		a.closeUpdate()
+		return nil
+	case "enter":
+		if change := a.updateOverlay.Selected(); change != nil {
+			a.logPanel.SelectByChangeID(change.ChangeID)
+		}
+		a.closeUpdate()
+		return nil
+	default:
+		_, cmd := a.updateOverlay.Update(msg)
+		return cmd
+	}
+}
+
+// closeUpdate hides the update report
+func (a *App) closeUpdate() {
+	a.showUpdate = false
+	a.updateOverlay = nil
+}
+
+func (a *App) overlayUpdate(background string) string {
+	return a.composite(background, a.updateOverlay.View())
+}
@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/config"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/floating"
+	"github.com/gerunddev/jjazy/ui/messages"
+)
+
+// TestUpdateFromTrunkFails verifies U runs the update and a failed fetch
+// shows its error rather than a report
+func TestUpdateFromTrunkFails(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'U'}})
+	if !a.busy {
+		t.Fatal("Expected U to start updating from trunk")
+	}
+	a.Update(messages.MutationDoneMsg{Err: errors.New("fetch failed")})
+	if a.showUpdate || !a.showInfo {
+		t.Errorf("Expected the error instead of a report, got report %v info %v", a.showUpdate, a.showInfo)
+	}
+}
+
+// TestUpdateReportCloses verifies enter on a conflicted change closes the
+// report
+func TestUpdateReportCloses(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	a.updateOverlay = floating.NewUpdateOverlay(&jj.UpdateResult{Changes: 1, Conflicts: []jj.ConflictedChange{
+		{ChangeID: "kmnopqrs", Description: "Add login", New: true},
+	}}, "main")
+	a.updateOverlay.SetSize(a.width, a.height-1)
+	a.showUpdate = true
+
+	if a.canOpenMenu() {
+		t.Error("Expected no context menu over the report")
+	}
+	a.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if a.showUpdate || a.updateOverlay != nil {
+		t.Error("Expected enter to close the report")
+	}
+}
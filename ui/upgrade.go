@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/theme"
+)
+
+// UpgradeScreen is shown instead of the app when the repo was written by a
+// newer jj than the bridge supports. Any key exits.
+type UpgradeScreen struct {
+	err    *jj.UnsupportedRepoError
+	width  int
+	height int
+}
+
+// NewUpgradeScreen creates the screen for an unsupported repo format
+func NewUpgradeScreen(err *jj.UnsupportedRepoError) *UpgradeScreen {
+	return &UpgradeScreen{err: err}
+}
+
+func (u *UpgradeScreen) Init() tea.Cmd {
+	return nil
+}
+
+func (u *UpgradeScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		u.width = msg.Width
+		u.height = msg.Height
+	case tea.KeyMsg:
+		return u, tea.Quit
+	}
+	return u, nil
+}
+
+func (u *UpgradeScreen) View() string {
+	title := theme.FloatingTitleStyle.Render(" Please upgrade jjazy ")
+	body := fmt.Sprintf(
+		"This repository was written by a newer version of jj.\n\n"+
+			"Its %s uses the %q format, but this build of jjazy\n"+
+			"links jj-lib %s, which does not understand it.\n\n"+
+			"jjazy has not opened the repository, so nothing was changed.\n"+
+			"Upgrade jjazy to a release built against your jj version.",
+		u.err.Component, u.err.Type, jj.SupportedJJLib)
+	hint := theme.DimmedStyle.Render(u.err.Path + "\n\npress any key to exit")
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.ColorRed).
+		Padding(1, 2).
+		Render(lipgloss.JoinVertical(lipgloss.Left, title, "", body, "", hint))
+
+	if u.width == 0 || u.height == 0 {
+		return box
+	}
+	return lipgloss.Place(u.width, u.height, lipgloss.Center, lipgloss.Center, box)
+}
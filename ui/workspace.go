@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/app"
+	"github.com/gerunddev/jjazy/jj"
+	"github.com/gerunddev/jjazy/ui/messages"
+)
+
+// newOnWorkingCopy starts fresh work in a new change on top of @, from any
+// panel
+func (a *App) newOnWorkingCopy(msg tea.KeyMsg) tea.Cmd {
+	a.recordAction("new", "@", msg)
+	return a.runMutation(func(ctx context.Context) error {
+		return a.advancing(ctx, func() error {
+			return a.repo.NewChange(ctx, "@")
+		})
+	}, a.refreshLogPanels)
+}
+
+// openWorkspace opens a workspace's root with the configured opener, or
+// hands the terminal to $EDITOR when none is configured
+func (a *App) openWorkspace(ws *jj.Workspace) tea.Cmd {
+	cmd, foreground, err := app.WorkspaceOpener(a.cfg.WorkspaceOpener, ws.RootPath)
+	if err != nil {
+		a.showInfoDialog("Open Workspace", err.Error())
+		return nil
+	}
+	name := ws.Name
+	if foreground {
+		return tea.ExecProcess(cmd, func(err error) tea.Msg {
+			return messages.WorkspaceOpenedMsg{Name: name, Err: err}
+		})
+	}
+	return func() tea.Msg {
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				err = fmt.Errorf("workspace opener failed: %s", msg)
+			}
+			return messages.WorkspaceOpenedMsg{Name: name, Err: err}
+		}
+		return messages.WorkspaceOpenedMsg{Name: name}
+	}
+}
+
+// showWorkspaceDetails measures the workspace's files in the background,
+// then shows its root path, working copy and disk usage
+func (a *App) showWorkspaceDetails(ws jj.Workspace) tea.Cmd {
+	ctx := a.ctx
+	return func() tea.Msg {
+		usage, err := app.MeasureWorkspace(ctx, ws.RootPath)
+		return messages.WorkspaceDetailsMsg{Workspace: ws, Usage: usage, Err: err}
+	}
+}
+
+// workspaceDetails renders the details popup's message
+func workspaceDetails(ws jj.Workspace, usage *app.WorkspaceUsage) string {
+	lines := []string{
+		"Path: " + ws.RootPath,
+		"Working copy: " + ws.CommitID,
+		fmt.Sprintf("Disk usage: %s in %d files", app.FormatBytes(usage.Bytes), usage.Files),
+	}
+	if !usage.Modified.IsZero() {
+		lines = append(lines, "Last modified: "+usage.Modified.Format("2006-01-02 15:04"))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,21 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gerunddev/jjazy/config"
+)
+
+// TestNewOnWorkingCopy verifies ctrl+enter, sent by terminals as ctrl+j,
+// starts a new change on @ from any panel
+func TestNewOnWorkingCopy(t *testing.T) {
+	a := NewApp(nil, "/nonexistent/path", config.Default(), &config.State{})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	a.focusedPanel = 2 // Bookmarks
+
+	a.Update(tea.KeyMsg{Type: tea.KeyCtrlJ})
+	if !a.busy {
+		t.Error("Expected ctrl+enter to run jj new @")
+	}
+}